@@ -0,0 +1,192 @@
+// import-addresses carrega a base local de municípios do IBGE e de CEPs a partir de arquivos CSV.
+//
+// Uso:
+//
+//	go run ./cmd/import-addresses -municipalities municipios.csv -postal-codes ceps.csv
+//
+// Os arquivos devem ter cabeçalho e as colunas, nesta ordem:
+//
+//	municipalities: ibge_code,name,uf
+//	postal-codes:   postal_code,street,district,ibge_code
+//
+// Reimportar um arquivo atualiza os registros existentes. Os municípios devem ser importados antes
+// dos CEPs que os referenciam.
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	dlog "log"
+	"os"
+	"strconv"
+	"strings"
+
+	"go-sales/internal/config"
+	"go-sales/internal/database"
+	"go-sales/internal/logger"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+
+	"github.com/rs/zerolog/log"
+)
+
+// batchSize é quantas linhas são lidas antes de cada gravação no banco.
+const batchSize = 5000
+
+func main() {
+	municipalitiesPath := flag.String("municipalities", "", "CSV de municípios do IBGE (ibge_code,name,uf)")
+	postalCodesPath := flag.String("postal-codes", "", "CSV de CEPs (postal_code,street,district,ibge_code)")
+	delimiter := flag.String("delimiter", ",", "separador de colunas dos arquivos CSV")
+	flag.Parse()
+
+	if *municipalitiesPath == "" && *postalCodesPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if len([]rune(*delimiter)) != 1 {
+		dlog.Fatalf("Fatal Error: delimiter must be a single character")
+	}
+	comma := []rune(*delimiter)[0]
+
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		dlog.Fatalf("Fatal Error: %v", err)
+	}
+	logger.Init(cfg)
+
+	if err := database.Connect(cfg); err != nil {
+		log.Fatal().Err(err).Msg("Error connecting to database")
+	}
+	repo := database.NewPostalCodeRepository(database.DB)
+
+	if *municipalitiesPath != "" {
+		count, err := importFile(*municipalitiesPath, comma, 3, func(rows [][]string) error {
+			municipalities := make([]model.IBGEMunicipality, 0, len(rows))
+			for _, row := range rows {
+				municipality, err := parseMunicipality(row)
+				if err != nil {
+					return err
+				}
+				municipalities = append(municipalities, municipality)
+			}
+			return repo.SaveMunicipalities(municipalities)
+		})
+		if err != nil {
+			log.Fatal().Err(err).Str("file", *municipalitiesPath).Msg("failed to import municipalities")
+		}
+		log.Info().Int("rows", count).Msg("municipalities imported")
+	}
+
+	if *postalCodesPath != "" {
+		count, err := importFile(*postalCodesPath, comma, 4, func(rows [][]string) error {
+			postalCodes := make([]model.PostalCode, 0, len(rows))
+			for _, row := range rows {
+				postalCode, err := parsePostalCode(row)
+				if err != nil {
+					return err
+				}
+				postalCodes = append(postalCodes, postalCode)
+			}
+			return repo.SavePostalCodes(postalCodes)
+		})
+		if err != nil {
+			log.Fatal().Err(err).Str("file", *postalCodesPath).Msg("failed to import postal codes")
+		}
+		log.Info().Int("rows", count).Msg("postal codes imported")
+	}
+}
+
+// importFile lê o CSV em lotes, ignorando o cabeçalho, e entrega cada lote para save.
+// Devolve o total de linhas importadas.
+func importFile(path string, comma rune, columns int, save func(rows [][]string) error) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comma = comma
+	reader.FieldsPerRecord = columns
+	reader.ReuseRecord = false
+
+	if _, err := reader.Read(); err != nil {
+		return 0, fmt.Errorf("reading header: %w", err)
+	}
+
+	total := 0
+	batch := make([][]string, 0, batchSize)
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return total, err
+		}
+		batch = append(batch, row)
+		if len(batch) == batchSize {
+			if err := save(batch); err != nil {
+				return total, err
+			}
+			total += len(batch)
+			batch = batch[:0]
+		}
+	}
+	if err := save(batch); err != nil {
+		return total, err
+	}
+	return total + len(batch), nil
+}
+
+func parseMunicipality(row []string) (model.IBGEMunicipality, error) {
+	code, err := parseIBGECode(row[0])
+	if err != nil {
+		return model.IBGEMunicipality{}, err
+	}
+	uf := strings.ToUpper(strings.TrimSpace(row[2]))
+	if !util.IsValidUF(uf) {
+		return model.IBGEMunicipality{}, fmt.Errorf("invalid uf %q for municipality %d", row[2], code)
+	}
+	return model.IBGEMunicipality{
+		IBGECode: code,
+		Name:     strings.TrimSpace(row[1]),
+		UF:       uf,
+	}, nil
+}
+
+func parsePostalCode(row []string) (model.PostalCode, error) {
+	postalCode := util.OnlyDigits(row[0])
+	if len(postalCode) != 8 {
+		return model.PostalCode{}, fmt.Errorf("invalid postal code %q", row[0])
+	}
+	code, err := parseIBGECode(row[3])
+	if err != nil {
+		return model.PostalCode{}, err
+	}
+	return model.PostalCode{
+		PostalCode:   postalCode,
+		Street:       optional(row[1]),
+		District:     optional(row[2]),
+		CityIBGECode: code,
+	}, nil
+}
+
+func parseIBGECode(value string) (int32, error) {
+	code, err := strconv.ParseInt(strings.TrimSpace(value), 10, 32)
+	if err != nil || code <= 0 {
+		return 0, fmt.Errorf("invalid ibge code %q", value)
+	}
+	return int32(code), nil
+}
+
+func optional(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
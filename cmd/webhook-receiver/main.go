@@ -0,0 +1,76 @@
+// webhook-receiver é um receptor local de webhooks para testes: confere a assinatura de cada entrega,
+// imprime o evento e responde com o status configurado.
+//
+// Uso:
+//
+//	go run ./cmd/webhook-receiver -addr :9000 -secret whsec_...
+//
+// Cadastre http://localhost:9000/ como URL do webhook e use como -secret o segredo devolvido na
+// criação. Com -fail N, as N primeiras entregas de cada Webhook-Id recebem 500, para observar as novas
+// tentativas no log de entregas; -status muda o status das demais respostas.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	dlog "log"
+	"net/http"
+	"sync"
+	"time"
+
+	"go-sales/internal/webhook"
+)
+
+func main() {
+	addr := flag.String("addr", ":9000", "endereço em que o receptor escuta")
+	secret := flag.String("secret", "", "segredo de assinatura do webhook (whsec_...); vazio não confere a assinatura")
+	fail := flag.Int("fail", 0, "quantas entregas de cada Webhook-Id respondem 500 antes de aceitar")
+	status := flag.Int("status", http.StatusNoContent, "status das respostas que não falham")
+	flag.Parse()
+
+	var mu sync.Mutex
+	received := make(map[string]int)
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		deliveryID := r.Header.Get(webhook.IDHeader)
+
+		if *secret != "" {
+			errVerify := webhook.Verify(*secret, r.Header.Get(webhook.TimestampHeader), r.Header.Get(webhook.SignatureHeader), body, webhook.DefaultTolerance, time.Now())
+			if errVerify != nil {
+				dlog.Printf("rejected delivery %s: %v", deliveryID, errVerify)
+				http.Error(w, errVerify.Error(), http.StatusUnauthorized)
+				return
+			}
+		}
+
+		mu.Lock()
+		received[deliveryID]++
+		attempt := received[deliveryID]
+		mu.Unlock()
+
+		var pretty bytes.Buffer
+		if json.Indent(&pretty, body, "", "  ") != nil {
+			pretty.Reset()
+			pretty.Write(body)
+		}
+		dlog.Printf("delivery %s attempt %d event %s\n%s", deliveryID, attempt, r.Header.Get(webhook.EventHeader), pretty.String())
+
+		if attempt <= *fail {
+			http.Error(w, "simulated failure", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(*status)
+	})
+
+	dlog.Printf("webhook receiver listening on %s", *addr)
+	if err := http.ListenAndServe(*addr, nil); err != nil {
+		dlog.Fatalf("Fatal Error: %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+// Package auth define o Principal, a identidade autenticada de uma requisição, e o contrato dos
+// autenticadores de tokens Bearer (chaves de API de contas de serviço e, com o login, tokens de usuário).
+package auth
+
+import (
+	"errors"
+
+	"go-sales/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PrincipalType diz que tipo de identidade fez a requisição.
+type PrincipalType string
+
+const (
+	PrincipalUser           PrincipalType = "user"
+	PrincipalServiceAccount PrincipalType = "service_account"
+)
+
+// PermissionManageServiceAccounts permite criar, alterar e apagar contas de serviço e emitir, rotacionar
+// e revogar as suas chaves de API.
+const PermissionManageServiceAccounts = "service_accounts.manage"
+
+// PermissionUnlockUsers permite desbloquear as contas da empresa bloqueadas por senhas erradas.
+const PermissionUnlockUsers = "users.unlock"
+
+// PermissionApproveCommissions permite aprovar os extratos de comissão dos vendedores da empresa.
+const PermissionApproveCommissions = "commissions.approve"
+
+// principalContextKey é a chave do Principal no contexto do Gin.
+const principalContextKey = "principal"
+
+// ErrInvalidToken é retornado pelos autenticadores quando o token não é reconhecido, expirou ou foi revogado.
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Principal é quem fez a requisição: um usuário ou uma conta de serviço, sempre de uma empresa.
+// Usuários e contas de serviço recebem roles da mesma tabela, então as checagens de permissão
+// não precisam saber qual dos dois autenticou.
+type Principal struct {
+	Type            PrincipalType
+	ID              int64
+	CompanyGlobalID int64
+	Name            string
+	RoleIDs         []int64
+	Permissions     map[string]bool
+	Admin           bool
+	// APIKeyID é a chave usada pela conta de serviço; zero para usuários.
+	APIKeyID int64
+}
+
+// NewPrincipal monta o Principal a partir das roles, juntando as permissões de todas elas.
+// As roles precisam vir com as permissões carregadas.
+func NewPrincipal(principalType PrincipalType, id, companyGlobalID int64, name string, roles []*model.Role) *Principal {
+	p := &Principal{
+		Type:            principalType,
+		ID:              id,
+		CompanyGlobalID: companyGlobalID,
+		Name:            name,
+		RoleIDs:         make([]int64, 0, len(roles)),
+		Permissions:     make(map[string]bool),
+	}
+	for _, role := range roles {
+		p.RoleIDs = append(p.RoleIDs, role.ID)
+		p.Admin = p.Admin || role.IsAdmin
+		for _, permission := range role.Permissions {
+			p.Permissions[permission.Name] = true
+		}
+	}
+	return p
+}
+
+// HasPermission indica se alguma das roles concede a permissão. Roles de administrador concedem todas.
+func (p *Principal) HasPermission(name string) bool {
+	return p.Admin || p.Permissions[name]
+}
+
+// Authenticator valida um tipo de token Bearer. Accepts decide, só pelo formato, se o token é deste
+// autenticador; Authenticate devolve o Principal ou ErrInvalidToken.
+type Authenticator interface {
+	Accepts(token string) bool
+	Authenticate(token string) (*Principal, error)
+}
+
+// SetPrincipal guarda o Principal autenticado no contexto da requisição.
+func SetPrincipal(c *gin.Context, p *Principal) {
+	c.Set(principalContextKey, p)
+}
+
+// FromContext devolve o Principal da requisição, se ela foi autenticada.
+func FromContext(c *gin.Context) (*Principal, bool) {
+	value, ok := c.Get(principalContextKey)
+	if !ok {
+		return nil, false
+	}
+	p, ok := value.(*Principal)
+	return p, ok
+}
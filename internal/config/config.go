@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -14,6 +15,56 @@ type Config struct {
 	AppAPIPort   string `mapstructure:"APP_API_PORT"`
 
 	AppDefaultAPIPageSize int `mapstructure:"APP_DEFAULT_API_PAGE_SIZE"`
+	// AppCursorSecret assina os cursores da paginação keyset (?cursor=).
+	AppCursorSecret string `mapstructure:"APP_CURSOR_SECRET"`
+	// AppIdempotencyTTL é por quanto tempo a resposta de um POST com Idempotency-Key é reaproveitada (ex: "24h").
+	AppIdempotencyTTL time.Duration `mapstructure:"APP_IDEMPOTENCY_TTL"`
+	// AppIdempotencyMaxBody é o maior corpo, em bytes, de um POST com Idempotency-Key, que é lido
+	// inteiro para o hash antes do handler. Zero usa o padrão (10 MiB, o maior upload da API).
+	AppIdempotencyMaxBody int64 `mapstructure:"APP_IDEMPOTENCY_MAX_BODY"`
+	// AppSnowflakeIDAsNumber volta a serializar os IDs como number JSON, para clientes que ainda não
+	// migraram para os IDs em string. Chave temporária da migração.
+	AppSnowflakeIDAsNumber bool `mapstructure:"APP_SNOWFLAKE_ID_AS_NUMBER"`
+	// AppJobWorkers é quantos jobs da fila em segundo plano este processo executa ao mesmo tempo.
+	AppJobWorkers int `mapstructure:"APP_JOB_WORKERS"`
+	// AppJobPollInterval é a espera entre consultas à fila de jobs quando ela está vazia (ex: "1s").
+	AppJobPollInterval time.Duration `mapstructure:"APP_JOB_POLL_INTERVAL"`
+	// AppOutboxPollInterval é a espera entre consultas ao outbox de eventos quando não há eventos prontos (ex: "1s").
+	AppOutboxPollInterval time.Duration `mapstructure:"APP_OUTBOX_POLL_INTERVAL"`
+	// AppShutdownTimeout é quanto o desligamento espera as requisições e os jobs em andamento (ex: "30s").
+	AppShutdownTimeout time.Duration `mapstructure:"APP_SHUTDOWN_TIMEOUT"`
+
+	// AppRateLimitStore é onde ficam os buckets do rate limit: "memory" (padrão, por réplica) ou
+	// "postgres" (limite dividido entre todas as réplicas).
+	AppRateLimitStore string `mapstructure:"APP_RATE_LIMIT_STORE"`
+	// AppRateLimitRequests e AppRateLimitPeriod são o limite padrão da API por IP, tenant e usuário
+	// (ex: 300 a cada "1m"). Zero desliga o rate limit.
+	AppRateLimitRequests int           `mapstructure:"APP_RATE_LIMIT_REQUESTS"`
+	AppRateLimitPeriod   time.Duration `mapstructure:"APP_RATE_LIMIT_PERIOD"`
+	// AppLoginMaxAttempts é quantas senhas erradas seguidas bloqueiam a conta.
+	AppLoginMaxAttempts int `mapstructure:"APP_LOGIN_MAX_ATTEMPTS"`
+	// AppLoginLockout é o primeiro bloqueio (ex: "1m"); cada nova falha depois dele dobra o tempo,
+	// até AppLoginMaxLockout (ex: "24h").
+	AppLoginLockout    time.Duration `mapstructure:"APP_LOGIN_LOCKOUT"`
+	AppLoginMaxLockout time.Duration `mapstructure:"APP_LOGIN_MAX_LOCKOUT"`
+	// AppWebhookAllowPrivateNetworks libera webhooks para localhost e redes privadas, para testar com
+	// um receptor local. Nunca deve ser true em produção: abre a API a SSRF.
+	AppWebhookAllowPrivateNetworks bool `mapstructure:"APP_WEBHOOK_ALLOW_PRIVATE_NETWORKS"`
+	// AppPixProvider escolhe o PSP das cobranças PIX. Por enquanto só existe "fake" (pix.FakeProvider,
+	// para desenvolvimento e testes), que nunca sobe em produção; vazio desliga as rotas de PIX.
+	AppPixProvider string `mapstructure:"APP_PIX_PROVIDER"`
+	// AppPixWebhookSecret assina (HMAC-SHA256) as notificações recebidas em POST /pix/webhook.
+	// Obrigatório quando há PSP: sem ele nenhuma notificação é aceita.
+	AppPixWebhookSecret string `mapstructure:"APP_PIX_WEBHOOK_SECRET"`
+	// AppStatementMatchDays é quantos dias antes ou depois do vencimento um crédito do extrato ainda
+	// pontua na conciliação automática com a parcela. Zero usa o padrão (5).
+	AppStatementMatchDays int `mapstructure:"APP_STATEMENT_MATCH_DAYS"`
+
+	// SnowflakeNodeID fixa o node ID do Snowflake desta instância; vazio toma qualquer node ID livre.
+	// Em ambos os casos o node ID é reservado por lease em master.snowflake_nodes.
+	SnowflakeNodeID string `mapstructure:"SNOWFLAKE_NODE_ID"`
+	// AppSnowflakeLeaseTTL é a validade do lease do node ID sem renovação (ex: "1m").
+	AppSnowflakeLeaseTTL time.Duration `mapstructure:"APP_SNOWFLAKE_LEASE_TTL"`
 
 	DBSchema string `mapstructure:"DEFAULT_SCHEMA"`
 	DBHost   string `mapstructure:"DB_HOST"`
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"go-sales/internal/dto"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+// BankAccountRepositoryInterface define os métodos para interagir com as contas bancárias.
+type BankAccountRepositoryInterface interface {
+	Create(account *model.BankAccount) error
+	FindByID(id int64, opts dto.ReadOptions) (*model.BankAccount, error)
+	FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]model.BankAccount, dto.PageInfo, error)
+	Patch(id int64, version int64, columns map[string]any) error
+	Delete(id int64, version int64) error
+}
+
+// bankAccountFilterSpec define os filtros e ordenações aceitos em GET /bank-accounts.
+var bankAccountFilterSpec = FilterSpec{
+	Fields: map[string]FilterField{
+		"id":            {Column: "id", Type: FieldInt, Operators: IDOperators},
+		"bankCode":      {Column: "bank_code", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"agency":        {Column: "agency", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"accountNumber": {Column: "account_number", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"description":   {Column: "description", Type: FieldString, Operators: TextOperators, Sortable: true, Nullable: true},
+		"createdAt":     {Column: "created_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+		"updatedAt":     {Column: "updated_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+	},
+	DefaultSort: "createdAt",
+}
+
+// bankAccountProjectionSpec define os campos de ?fields= nas leituras de contas bancárias.
+var bankAccountProjectionSpec = ProjectionSpec{
+	Columns: map[string]string{
+		"id":                 "id",
+		"companyGlobalId":    "company_global_id",
+		"bankCode":           "bank_code",
+		"agency":             "agency",
+		"agencyDigit":        "agency_digit",
+		"accountNumber":      "account_number",
+		"accountDigit":       "account_digit",
+		"description":        "description",
+		"wallet":             "wallet",
+		"walletVariation":    "wallet_variation",
+		"agreement":          "agreement",
+		"nextOurNumber":      "next_our_number",
+		"remittanceSequence": "remittance_sequence",
+		"createdAt":          "created_at",
+		"version":            "version",
+		"updatedAt":          "updated_at",
+	},
+}
+
+// bankAccountRepository é a implementação concreta que usa o GORM.
+type bankAccountRepository struct {
+	db *gorm.DB
+}
+
+// NewBankAccountRepository cria uma nova instância do repositório de contas bancárias.
+func NewBankAccountRepository(db *gorm.DB) BankAccountRepositoryInterface {
+	return &bankAccountRepository{db: db}
+}
+
+func (r *bankAccountRepository) Create(account *model.BankAccount) error {
+	account.ID = util.NewSnowflake()
+	return r.db.Create(account).Error
+}
+
+func (r *bankAccountRepository) FindByID(id int64, opts dto.ReadOptions) (*model.BankAccount, error) {
+	var account model.BankAccount
+	query, err := bankAccountProjectionSpec.Project(r.db, opts, "version")
+	if err != nil {
+		return nil, err
+	}
+	if err := query.Where("id = ?", id).First(&account).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *bankAccountRepository) FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]model.BankAccount, dto.PageInfo, error) {
+	query := r.db.Model(&model.BankAccount{}).Where("company_global_id = ?", companyGlobalID)
+	return findPage[model.BankAccount](query, bankAccountFilterSpec, bankAccountProjectionSpec, filters, page, opts)
+}
+
+// Patch atualiza apenas as colunas informadas (PATCH), desde que a versão no banco ainda seja version.
+func (r *bankAccountRepository) Patch(id int64, version int64, columns map[string]any) error {
+	columns["version"] = nextVersion()
+	result := whereVersion(r.db.Model(&model.BankAccount{}).Where("id = ?", id), version).Updates(columns)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return missingOrConflict(r.db, &model.BankAccount{}, id)
+	}
+	return nil
+}
+
+// Delete apaga (soft delete) a conta. Os boletos e arquivos dela são mantidos.
+func (r *bankAccountRepository) Delete(id int64, version int64) error {
+	result := whereVersion(r.db.Where("id = ?", id), version).Delete(&model.BankAccount{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return missingOrConflict(r.db, &model.BankAccount{}, id)
+	}
+	return nil
+}
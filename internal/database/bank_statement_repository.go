@@ -0,0 +1,249 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go-sales/internal/dto"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrStatementLineStatusChanged é retornado por Suggest, Confirm e Reject quando o lançamento já não
+// está num dos estados esperados.
+var ErrStatementLineStatusChanged = errors.New("statement line status changed")
+
+// StatementSuggestion é a parcela sugerida para um lançamento pela conciliação automática.
+type StatementSuggestion struct {
+	LineID        int64
+	InstallmentID int64
+	Confidence    int
+	Reasons       []string
+}
+
+// BankStatementRepositoryInterface define os métodos para interagir com os extratos importados e seus
+// lançamentos.
+type BankStatementRepositoryInterface interface {
+	// CreateImport grava o extrato e os lançamentos ainda não importados na conta (pelo external_id).
+	// Devolve os lançamentos gravados; import.DuplicateCount conta os ignorados.
+	CreateImport(statementImport *model.BankStatementImport, lines []model.BankStatementLine) ([]model.BankStatementLine, error)
+	// FindCandidates lista as parcelas em aberto da empresa com vencimento entre from e to que ainda
+	// não estão sugeridas ou conciliadas em outro lançamento.
+	FindCandidates(companyGlobalID int64, from, to time.Time) ([]model.Installment, error)
+	// SaveSuggestions grava as sugestões nos lançamentos unmatched e atualiza o total do extrato.
+	// Devolve as que foram gravadas: uma parcela sugerida por outra importação simultânea é ignorada.
+	SaveSuggestions(importID int64, suggestions []StatementSuggestion) ([]StatementSuggestion, error)
+
+	FindImport(id int64) (*model.BankStatementImport, error)
+	FindLines(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, bankAccountID int64) ([]model.BankStatementLine, dto.PageInfo, error)
+	FindLine(id int64) (*model.BankStatementLine, error)
+
+	// Confirm concilia o lançamento com a parcela, desde que ele esteja num dos estados from. Com
+	// settle, a parcela é baixada com payment na mesma transação; settled é false quando ela já não
+	// estava em aberto (paga por outro meio).
+	Confirm(id int64, installmentID int64, payment model.InstallmentPayment, settle bool, from []string, events ...Recorder[model.Installment]) (settled bool, err error)
+	// Reject descarta a parcela sugerida para o lançamento.
+	Reject(id int64) error
+}
+
+// statementLineFilterSpec define os filtros e ordenações aceitos em GET /bank-accounts/:id/statement-lines.
+var statementLineFilterSpec = FilterSpec{
+	Fields: map[string]FilterField{
+		"id":             {Column: "id", Type: FieldInt, Operators: IDOperators},
+		"importId":       {Column: "import_id", Type: FieldInt, Operators: IDOperators},
+		"postedAt":       {Column: "posted_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+		"description":    {Column: "description", Type: FieldString, Operators: TextOperators, Sortable: true, Nullable: true},
+		"documentNumber": {Column: "document_number", Type: FieldString, Operators: ExactTextOperators, Sortable: true, Nullable: true},
+		"status":         {Column: "status", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"installmentId":  {Column: "installment_id", Type: FieldInt, Operators: IDOperators, Nullable: true},
+		"confidence":     {Column: "confidence", Type: FieldInt, Operators: IDOperators, Sortable: true, Nullable: true},
+		"createdAt":      {Column: "created_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+		"updatedAt":      {Column: "updated_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+	},
+	DefaultSort: "postedAt",
+}
+
+// statementLineProjectionSpec define os campos de ?fields= nas leituras de lançamentos.
+var statementLineProjectionSpec = ProjectionSpec{
+	Columns: map[string]string{
+		"id":              "id",
+		"companyGlobalId": "company_global_id",
+		"bankAccountId":   "bank_account_id",
+		"importId":        "import_id",
+		"externalId":      "external_id",
+		"postedAt":        "posted_at",
+		"amount":          "amount",
+		"description":     "description",
+		"documentNumber":  "document_number",
+		"status":          "status",
+		"installmentId":   "installment_id",
+		"confidence":      "confidence",
+		"matchReasons":    "match_reasons",
+		"reconciledAt":    "reconciled_at",
+		"createdAt":       "created_at",
+		"updatedAt":       "updated_at",
+	},
+}
+
+// matchedLineStatuses são os estados em que o lançamento ocupa a parcela vinculada.
+var matchedLineStatuses = []string{model.StatementLineSuggested, model.StatementLineConfirmed}
+
+// bankStatementRepository é a implementação concreta que usa o GORM.
+type bankStatementRepository struct {
+	db *gorm.DB
+}
+
+// NewBankStatementRepository cria uma nova instância do repositório de extratos bancários.
+func NewBankStatementRepository(db *gorm.DB) BankStatementRepositoryInterface {
+	return &bankStatementRepository{db: db}
+}
+
+func (r *bankStatementRepository) CreateImport(statementImport *model.BankStatementImport, lines []model.BankStatementLine) ([]model.BankStatementLine, error) {
+	var inserted []model.BankStatementLine
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		statementImport.ID = util.NewSnowflake()
+		if err := tx.Create(statementImport).Error; err != nil {
+			return err
+		}
+		for i := range lines {
+			line := lines[i]
+			line.ID = util.NewSnowflake()
+			line.ImportID = statementImport.ID
+			line.CompanyGlobalID = statementImport.CompanyGlobalID
+			line.BankAccountID = statementImport.BankAccountID
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&line)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				statementImport.DuplicateCount++
+				continue
+			}
+			inserted = append(inserted, line)
+		}
+		statementImport.LineCount = len(inserted)
+		return tx.Model(statementImport).Updates(map[string]any{
+			"line_count":      statementImport.LineCount,
+			"duplicate_count": statementImport.DuplicateCount,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return inserted, nil
+}
+
+func (r *bankStatementRepository) FindCandidates(companyGlobalID int64, from, to time.Time) ([]model.Installment, error) {
+	var installments []model.Installment
+	err := r.db.Where("company_global_id = ? AND status = ? AND due_date BETWEEN ? AND ?", companyGlobalID, model.InstallmentOpen, from, to).
+		Where("NOT EXISTS (?)", r.db.Model(&model.BankStatementLine{}).Select("1").
+			Where("bank_statement_lines.installment_id = installments.id AND bank_statement_lines.status IN ?", matchedLineStatuses)).
+		Order("due_date, id").
+		Find(&installments).Error
+	return installments, err
+}
+
+func (r *bankStatementRepository) SaveSuggestions(importID int64, suggestions []StatementSuggestion) ([]StatementSuggestion, error) {
+	var saved []StatementSuggestion
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, suggestion := range suggestions {
+			// Updates com map não passa pelo serializer da coluna.
+			reasons, err := json.Marshal(suggestion.Reasons)
+			if err != nil {
+				return err
+			}
+			result := tx.Model(&model.BankStatementLine{}).
+				Where("id = ? AND status = ?", suggestion.LineID, model.StatementLineUnmatched).
+				Where("NOT EXISTS (?)", tx.Session(&gorm.Session{NewDB: true}).Model(&model.BankStatementLine{}).Select("1").
+					Where("installment_id = ? AND status IN ?", suggestion.InstallmentID, matchedLineStatuses)).
+				Updates(map[string]any{
+					"status":         model.StatementLineSuggested,
+					"installment_id": suggestion.InstallmentID,
+					"confidence":     suggestion.Confidence,
+					"match_reasons":  reasons,
+					"updated_at":     time.Now(),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				saved = append(saved, suggestion)
+			}
+		}
+		return tx.Model(&model.BankStatementImport{}).Where("id = ?", importID).Update("suggested_count", len(saved)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+func (r *bankStatementRepository) FindImport(id int64) (*model.BankStatementImport, error) {
+	var statementImport model.BankStatementImport
+	if err := r.db.Where("id = ?", id).First(&statementImport).Error; err != nil {
+		return nil, err
+	}
+	return &statementImport, nil
+}
+
+func (r *bankStatementRepository) FindLines(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, bankAccountID int64) ([]model.BankStatementLine, dto.PageInfo, error) {
+	query := r.db.Model(&model.BankStatementLine{}).Where("bank_account_id = ?", bankAccountID)
+	return findPage[model.BankStatementLine](query, statementLineFilterSpec, statementLineProjectionSpec, filters, page, opts)
+}
+
+func (r *bankStatementRepository) FindLine(id int64) (*model.BankStatementLine, error) {
+	var line model.BankStatementLine
+	if err := r.db.Where("id = ?", id).First(&line).Error; err != nil {
+		return nil, err
+	}
+	return &line, nil
+}
+
+func (r *bankStatementRepository) Confirm(id int64, installmentID int64, payment model.InstallmentPayment, settle bool, from []string, events ...Recorder[model.Installment]) (bool, error) {
+	settled := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.BankStatementLine{}).Where("id = ? AND status IN ?", id, from).Updates(map[string]any{
+			"status":         model.StatementLineConfirmed,
+			"installment_id": installmentID,
+			"reconciled_at":  payment.PaidAt,
+			"updated_at":     time.Now(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStatementLineStatusChanged
+		}
+		if !settle {
+			return nil
+		}
+		err := settleInstallment(tx, installmentID, payment, events)
+		if errors.Is(err, ErrInstallmentNotOpen) {
+			return nil
+		}
+		settled = err == nil
+		return err
+	})
+	return settled, err
+}
+
+func (r *bankStatementRepository) Reject(id int64) error {
+	result := r.db.Model(&model.BankStatementLine{}).Where("id = ? AND status = ?", id, model.StatementLineSuggested).Updates(map[string]any{
+		"status":         model.StatementLineRejected,
+		"installment_id": nil,
+		"confidence":     nil,
+		"match_reasons":  nil,
+		"updated_at":     time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStatementLineStatusChanged
+	}
+	return nil
+}
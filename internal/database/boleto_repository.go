@@ -0,0 +1,232 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"go-sales/internal/model"
+	"go-sales/pkg/decimal"
+	"go-sales/pkg/util"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNoPendingBoletos é retornado por CreateRemittance quando a conta não tem boletos pendentes.
+var ErrNoPendingBoletos = errors.New("no pending boletos")
+
+// ErrBoletoStatusChanged é retornado por SetStatus e MarkPaid quando o boleto já não está num dos
+// estados esperados.
+var ErrBoletoStatusChanged = errors.New("boleto status changed")
+
+// BoletoRepositoryInterface define os métodos para interagir com os boletos e com os arquivos de
+// remessa e de retorno.
+type BoletoRepositoryInterface interface {
+	// Create reserva o próximo nosso número da conta e grava o boleto montado por build com ele.
+	Create(boleto *model.Boleto, build func(ourNumber int64) error) error
+	FindByID(id int64) (*model.Boleto, error)
+	FindByInstallment(installmentID int64) ([]model.Boleto, error)
+	FindByOurNumber(bankAccountID int64, ourNumber int64) (*model.Boleto, error)
+	// CountActive conta os boletos pendentes, enviados ou registrados da parcela.
+	CountActive(installmentID int64) (int64, error)
+	// SetStatus muda o estado do boleto, desde que ele esteja num dos estados from.
+	SetStatus(id int64, status string, from ...string) error
+	// MarkPaid registra o pagamento no boleto e, com settle, baixa a parcela na mesma transação.
+	// settled é false quando a parcela já não estava em aberto.
+	MarkPaid(id int64, payment model.InstallmentPayment, creditedAt *time.Time, settle bool, events ...Recorder[model.Installment]) (settled bool, err error)
+
+	// CreateRemittance numera a próxima remessa da conta e grava o arquivo montado por build com os
+	// boletos pendentes, que passam a sent.
+	CreateRemittance(bankAccountID int64, build func(sequence int, boletos []model.Boleto) (*model.BoletoRemittance, error)) (*model.BoletoRemittance, error)
+	FindRemittance(id int64) (*model.BoletoRemittance, error)
+
+	CreateReturn(ret *model.BoletoReturn) error
+	FindReturn(id int64) (*model.BoletoReturn, error)
+}
+
+// activeBoletoStatuses são os estados em que o boleto ainda pode ser pago.
+var activeBoletoStatuses = []string{model.BoletoPending, model.BoletoSent, model.BoletoRegistered}
+
+// boletoRepository é a implementação concreta que usa o GORM.
+type boletoRepository struct {
+	db *gorm.DB
+}
+
+// NewBoletoRepository cria uma nova instância do repositório de boletos.
+func NewBoletoRepository(db *gorm.DB) BoletoRepositoryInterface {
+	return &boletoRepository{db: db}
+}
+
+func (r *boletoRepository) Create(boleto *model.Boleto, build func(ourNumber int64) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var account model.BankAccount
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "next_our_number").
+			Where("id = ?", boleto.BankAccountID).Take(&account).Error
+		if err != nil {
+			return err
+		}
+		boleto.OurNumber = account.NextOurNumber
+		if err := build(boleto.OurNumber); err != nil {
+			return err
+		}
+		boleto.ID = util.NewSnowflake()
+		if err := tx.Create(boleto).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.BankAccount{}).Where("id = ?", account.ID).
+			Update("next_our_number", gorm.Expr("next_our_number + 1")).Error
+	})
+}
+
+func (r *boletoRepository) FindByID(id int64) (*model.Boleto, error) {
+	var boleto model.Boleto
+	if err := r.db.Where("id = ?", id).First(&boleto).Error; err != nil {
+		return nil, err
+	}
+	return &boleto, nil
+}
+
+// FindByInstallment lista os boletos da parcela, dos mais recentes para os mais antigos.
+func (r *boletoRepository) FindByInstallment(installmentID int64) ([]model.Boleto, error) {
+	var boletos []model.Boleto
+	err := r.db.Where("installment_id = ?", installmentID).Order("created_at DESC, id DESC").Find(&boletos).Error
+	return boletos, err
+}
+
+func (r *boletoRepository) FindByOurNumber(bankAccountID int64, ourNumber int64) (*model.Boleto, error) {
+	var boleto model.Boleto
+	if err := r.db.Where("bank_account_id = ? AND our_number = ?", bankAccountID, ourNumber).First(&boleto).Error; err != nil {
+		return nil, err
+	}
+	return &boleto, nil
+}
+
+func (r *boletoRepository) CountActive(installmentID int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Boleto{}).Where("installment_id = ? AND status IN ?", installmentID, activeBoletoStatuses).Count(&count).Error
+	return count, err
+}
+
+func (r *boletoRepository) SetStatus(id int64, status string, from ...string) error {
+	result := r.db.Model(&model.Boleto{}).Where("id = ? AND status IN ?", id, from).Update("status", status)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrBoletoStatusChanged
+	}
+	return nil
+}
+
+func (r *boletoRepository) MarkPaid(id int64, payment model.InstallmentPayment, creditedAt *time.Time, settle bool, events ...Recorder[model.Installment]) (bool, error) {
+	settled := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var boleto model.Boleto
+		if err := tx.Select("id", "installment_id").Where("id = ?", id).First(&boleto).Error; err != nil {
+			return err
+		}
+		// Um boleto baixado ou rejeitado ainda pode ser pago (liquidação após baixa).
+		result := tx.Model(&model.Boleto{}).Where("id = ? AND status <> ?", id, model.BoletoPaid).Updates(map[string]any{
+			"status":      model.BoletoPaid,
+			"paid_amount": payment.Amount,
+			"paid_at":     payment.PaidAt,
+			"credited_at": creditedAt,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrBoletoStatusChanged
+		}
+		if !settle {
+			return nil
+		}
+		err := settleInstallment(tx, boleto.InstallmentID, payment, events)
+		if errors.Is(err, ErrInstallmentNotOpen) {
+			return nil
+		}
+		settled = err == nil
+		return err
+	})
+	return settled, err
+}
+
+func (r *boletoRepository) CreateRemittance(bankAccountID int64, build func(sequence int, boletos []model.Boleto) (*model.BoletoRemittance, error)) (*model.BoletoRemittance, error) {
+	var remittance *model.BoletoRemittance
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var account model.BankAccount
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "remittance_sequence").
+			Where("id = ?", bankAccountID).Take(&account).Error
+		if err != nil {
+			return err
+		}
+		var boletos []model.Boleto
+		err = tx.Where("bank_account_id = ? AND status = ?", bankAccountID, model.BoletoPending).
+			Order("our_number").Find(&boletos).Error
+		if err != nil {
+			return err
+		}
+		if len(boletos) == 0 {
+			return ErrNoPendingBoletos
+		}
+
+		sequence := account.RemittanceSequence + 1
+		if remittance, err = build(sequence, boletos); err != nil {
+			return err
+		}
+		remittance.ID = util.NewSnowflake()
+		remittance.BankAccountID = bankAccountID
+		remittance.Sequence = sequence
+		remittance.TitleCount = len(boletos)
+		remittance.TotalAmount = decimal.Zero
+		ids := make([]int64, len(boletos))
+		for i, boleto := range boletos {
+			ids[i] = boleto.ID
+			remittance.TotalAmount = remittance.TotalAmount.Add(boleto.Amount)
+		}
+		if err := tx.Create(remittance).Error; err != nil {
+			return err
+		}
+		err = tx.Model(&model.Boleto{}).Where("id IN ?", ids).Updates(map[string]any{
+			"status":        model.BoletoSent,
+			"remittance_id": remittance.ID,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&model.BankAccount{}).Where("id = ?", bankAccountID).Update("remittance_sequence", sequence).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return remittance, nil
+}
+
+func (r *boletoRepository) FindRemittance(id int64) (*model.BoletoRemittance, error) {
+	var remittance model.BoletoRemittance
+	if err := r.db.Where("id = ?", id).First(&remittance).Error; err != nil {
+		return nil, err
+	}
+	return &remittance, nil
+}
+
+// CreateReturn grava o arquivo de retorno processado com o resultado de cada linha.
+func (r *boletoRepository) CreateReturn(ret *model.BoletoReturn) error {
+	ret.ID = util.NewSnowflake()
+	for i := range ret.Lines {
+		ret.Lines[i].ID = util.NewSnowflake()
+		ret.Lines[i].ReturnID = ret.ID
+	}
+	return r.db.Create(ret).Error
+}
+
+func (r *boletoRepository) FindReturn(id int64) (*model.BoletoReturn, error) {
+	var ret model.BoletoReturn
+	err := r.db.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("line_number")
+	}).Where("id = ?", id).First(&ret).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
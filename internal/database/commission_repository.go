@@ -0,0 +1,438 @@
+package database
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"go-sales/internal/dto"
+	"go-sales/internal/model"
+	"go-sales/pkg/decimal"
+	"go-sales/pkg/util"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrCommissionPeriodClosed é retornado por CreateEntry e ApproveStatement quando o extrato do
+	// vendedor no mês já foi aprovado.
+	ErrCommissionPeriodClosed = errors.New("commission period closed")
+	// ErrCommissionOverReversed é retornado por CreateEntry quando os estornos passariam da base da venda.
+	ErrCommissionOverReversed = errors.New("commission reversals exceed the sale")
+	// ErrCommissionAssignmentOverlap é retornado por CreateAssignment quando o usuário ou a role já tem
+	// um plano vigente em parte do período.
+	ErrCommissionAssignmentOverlap = errors.New("commission assignment overlaps another one")
+)
+
+// CommissionRepositoryInterface define os métodos para interagir com os planos de comissão, as suas
+// atribuições, os lançamentos e os extratos aprovados.
+type CommissionRepositoryInterface interface {
+	CreatePlan(plan *model.CommissionPlan) error
+	FindPlanByID(id int64, opts dto.ReadOptions) (*model.CommissionPlan, error)
+	FindPlans(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]model.CommissionPlan, dto.PageInfo, error)
+	PatchPlan(id int64, version int64, columns map[string]any) error
+	DeletePlan(id int64, version int64) error
+
+	// CreateAssignment grava a atribuição se o usuário (ou a role) não tiver outra vigente no período.
+	CreateAssignment(assignment *model.CommissionPlanAssignment) error
+	FindAssignment(id int64) (*model.CommissionPlanAssignment, error)
+	FindAssignments(planID int64) ([]model.CommissionPlanAssignment, error)
+	DeleteAssignment(id int64) error
+	// FindPlanFor devolve o plano vigente para o usuário na data: o atribuído a ele ou, sem esse, o
+	// atribuído a uma das suas roles (o de início de vigência mais recente).
+	FindPlanFor(userID int64, date time.Time) (*model.CommissionPlan, error)
+
+	// MonthVolume soma a base dos lançamentos do vendedor no mês (vendas menos estornos).
+	MonthVolume(userID int64, period time.Time) (decimal.Decimal, error)
+	// CreateEntry grava o lançamento, desde que o extrato do vendedor no mês não esteja aprovado e, nos
+	// estornos, que a soma dos estornos não passe da base da venda.
+	CreateEntry(entry *model.CommissionEntry) error
+	FindEntry(id int64) (*model.CommissionEntry, error)
+	FindEntries(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]model.CommissionEntry, dto.PageInfo, error)
+	Stream(filters map[string][]string, opts dto.ReadOptions, companyGlobalID int64, fn func(*model.CommissionEntry) error) error
+	// FindSale devolve o lançamento de venda do documento na empresa.
+	FindSale(companyGlobalID int64, documentNumber string) (*model.CommissionEntry, error)
+	// EventRecorded indica se o evento de faturamento já gerou um lançamento.
+	EventRecorded(eventID int64) (bool, error)
+	// ReversedTotals soma a base e a comissão já estornadas de uma venda (valores negativos).
+	ReversedTotals(saleID int64) (base decimal.Decimal, amount decimal.Decimal, err error)
+
+	// FindStatement devolve o extrato aprovado do vendedor no mês (gorm.ErrRecordNotFound se aberto).
+	FindStatement(userID int64, period time.Time) (*model.CommissionStatement, error)
+	// FindPeriodEntries lista os lançamentos do vendedor no mês, na ordem em que foram gravados.
+	FindPeriodEntries(userID int64, period time.Time) ([]model.CommissionEntry, error)
+	// FindStatements resume o mês de cada vendedor da empresa com lançamentos ou extrato aprovado. Os
+	// extratos abertos vêm com ID zero e os totais dos lançamentos.
+	FindStatements(companyGlobalID int64, period time.Time) ([]model.CommissionStatement, error)
+	// ApproveStatement grava o extrato com os totais dos lançamentos do mês e os vincula a ele.
+	ApproveStatement(statement *model.CommissionStatement) error
+}
+
+// commissionPlanFilterSpec define os filtros e ordenações aceitos em GET /commission-plans.
+var commissionPlanFilterSpec = FilterSpec{
+	Fields: map[string]FilterField{
+		"id":        {Column: "id", Type: FieldInt, Operators: IDOperators},
+		"name":      {Column: "name", Type: FieldString, Operators: TextOperators, Sortable: true},
+		"kind":      {Column: "kind", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"createdAt": {Column: "created_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+		"updatedAt": {Column: "updated_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+	},
+	DefaultSort: "name",
+}
+
+// commissionPlanProjectionSpec define os campos de ?fields= nas leituras de planos de comissão.
+var commissionPlanProjectionSpec = ProjectionSpec{
+	Columns: map[string]string{
+		"id":              "id",
+		"companyGlobalId": "company_global_id",
+		"name":            "name",
+		"kind":            "kind",
+		"defaultRate":     "default_rate",
+		"rules":           "rules",
+		"version":         "version",
+		"createdAt":       "created_at",
+		"updatedAt":       "updated_at",
+	},
+}
+
+// commissionEntryFilterSpec define os filtros e ordenações aceitos em GET /commission-entries. period
+// é comparado no formato da API (2006-01).
+var commissionEntryFilterSpec = FilterSpec{
+	Fields: map[string]FilterField{
+		"id":              {Column: "id", Type: FieldInt, Operators: IDOperators},
+		"userId":          {Column: "user_id", Type: FieldInt, Operators: IDOperators},
+		"planId":          {Column: "plan_id", Type: FieldInt, Operators: IDOperators, Nullable: true},
+		"kind":            {Column: "kind", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"documentNumber":  {Column: "document_number", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"documentDate":    {Column: "document_date", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+		"period":          {Column: "to_char(period, 'YYYY-MM')", Type: FieldString, Operators: []FilterOperator{OpEq, OpIn}},
+		"reason":          {Column: "reason", Type: FieldString, Operators: ExactTextOperators, Nullable: true},
+		"reversedEntryId": {Column: "reversed_entry_id", Type: FieldInt, Operators: IDOperators, Nullable: true},
+		"statementId":     {Column: "statement_id", Type: FieldInt, Operators: IDOperators, Nullable: true},
+		"createdAt":       {Column: "created_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+	},
+	DefaultSort: "documentDate",
+}
+
+// commissionEntryProjectionSpec define os campos de ?fields= nas leituras de lançamentos de comissão.
+var commissionEntryProjectionSpec = ProjectionSpec{
+	Columns: map[string]string{
+		"id":              "id",
+		"companyGlobalId": "company_global_id",
+		"userId":          "user_id",
+		"planId":          "plan_id",
+		"kind":            "kind",
+		"documentNumber":  "document_number",
+		"documentDate":    "document_date",
+		"period":          "period",
+		"baseAmount":      "base_amount",
+		"amount":          "amount",
+		"lines":           "lines",
+		"reversedEntryId": "reversed_entry_id",
+		"reason":          "reason",
+		"statementId":     "statement_id",
+		"createdAt":       "created_at",
+	},
+}
+
+// commissionRepository é a implementação concreta que usa o GORM.
+type commissionRepository struct {
+	db *gorm.DB
+}
+
+// NewCommissionRepository cria uma nova instância do repositório de comissões.
+func NewCommissionRepository(db *gorm.DB) CommissionRepositoryInterface {
+	return &commissionRepository{db: db}
+}
+
+func (r *commissionRepository) CreatePlan(plan *model.CommissionPlan) error {
+	plan.ID = util.NewSnowflake()
+	return r.db.Create(plan).Error
+}
+
+func (r *commissionRepository) FindPlanByID(id int64, opts dto.ReadOptions) (*model.CommissionPlan, error) {
+	var plan model.CommissionPlan
+	query, err := commissionPlanProjectionSpec.Project(r.db, opts, "version")
+	if err != nil {
+		return nil, err
+	}
+	if err := query.Where("id = ?", id).First(&plan).Error; err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *commissionRepository) FindPlans(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]model.CommissionPlan, dto.PageInfo, error) {
+	query := r.db.Model(&model.CommissionPlan{}).Where("company_global_id = ?", companyGlobalID)
+	return findPage[model.CommissionPlan](query, commissionPlanFilterSpec, commissionPlanProjectionSpec, filters, page, opts)
+}
+
+// PatchPlan atualiza apenas as colunas informadas (PATCH), desde que a versão no banco ainda seja version.
+func (r *commissionRepository) PatchPlan(id int64, version int64, columns map[string]any) error {
+	columns["version"] = nextVersion()
+	result := whereVersion(r.db.Model(&model.CommissionPlan{}).Where("id = ?", id), version).Updates(columns)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return missingOrConflict(r.db, &model.CommissionPlan{}, id)
+	}
+	return nil
+}
+
+// DeletePlan apaga (soft delete) o plano. As atribuições ficam, mas deixam de valer; os lançamentos
+// já calculados mantêm a referência ao plano.
+func (r *commissionRepository) DeletePlan(id int64, version int64) error {
+	result := whereVersion(r.db.Where("id = ?", id), version).Delete(&model.CommissionPlan{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return missingOrConflict(r.db, &model.CommissionPlan{}, id)
+	}
+	return nil
+}
+
+func (r *commissionRepository) CreateAssignment(assignment *model.CommissionPlanAssignment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Trava o usuário ou a role para que duas atribuições simultâneas não passem juntas pela checagem.
+		var target any = &model.User{}
+		column, targetID := "user_id", assignment.UserID
+		if assignment.RoleID != nil {
+			target, column, targetID = &model.Role{}, "role_id", assignment.RoleID
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", *targetID).Take(target).Error; err != nil {
+			return err
+		}
+
+		// Dois períodos [from, to] se sobrepõem se cada um começa antes do fim do outro (fim nulo é aberto).
+		overlap := tx.Model(&model.CommissionPlanAssignment{}).
+			Joins("JOIN commission_plans ON commission_plans.id = commission_plan_assignments.plan_id AND commission_plans.deleted_at IS NULL").
+			Where("commission_plan_assignments."+column+" = ?", *targetID).
+			Where("(commission_plan_assignments.valid_to IS NULL OR commission_plan_assignments.valid_to >= ?)", assignment.ValidFrom)
+		if assignment.ValidTo != nil {
+			overlap = overlap.Where("commission_plan_assignments.valid_from <= ?", *assignment.ValidTo)
+		}
+		var count int64
+		if err := overlap.Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrCommissionAssignmentOverlap
+		}
+
+		assignment.ID = util.NewSnowflake()
+		return tx.Create(assignment).Error
+	})
+}
+
+func (r *commissionRepository) FindAssignment(id int64) (*model.CommissionPlanAssignment, error) {
+	var assignment model.CommissionPlanAssignment
+	if err := r.db.Where("id = ?", id).First(&assignment).Error; err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+func (r *commissionRepository) FindAssignments(planID int64) ([]model.CommissionPlanAssignment, error) {
+	var assignments []model.CommissionPlanAssignment
+	err := r.db.Where("plan_id = ?", planID).Order("valid_from DESC, id").Find(&assignments).Error
+	return assignments, err
+}
+
+func (r *commissionRepository) DeleteAssignment(id int64) error {
+	result := r.db.Where("id = ?", id).Delete(&model.CommissionPlanAssignment{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *commissionRepository) FindPlanFor(userID int64, date time.Time) (*model.CommissionPlan, error) {
+	var plan model.CommissionPlan
+	err := r.db.Select("commission_plans.*").
+		Joins("JOIN commission_plan_assignments a ON a.plan_id = commission_plans.id").
+		Where("(a.user_id = ? OR a.role_id IN (?))", userID,
+			r.db.Table("user_roles").Select("role_id").Where("user_id = ?", userID)).
+		Where("a.valid_from <= ? AND (a.valid_to IS NULL OR a.valid_to >= ?)", date, date).
+		Order("a.user_id IS NULL, a.valid_from DESC, a.id").
+		First(&plan).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *commissionRepository) MonthVolume(userID int64, period time.Time) (decimal.Decimal, error) {
+	var totals struct {
+		Volume decimal.Decimal
+	}
+	err := r.db.Model(&model.CommissionEntry{}).
+		Select("COALESCE(SUM(base_amount), 0) AS volume").
+		Where("user_id = ? AND period = ?", userID, period).
+		Scan(&totals).Error
+	return totals.Volume, err
+}
+
+func (r *commissionRepository) CreateEntry(entry *model.CommissionEntry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// A trava no vendedor serializa os lançamentos e a aprovação do extrato dele.
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", entry.UserID).Take(&model.User{}).Error; err != nil {
+			return err
+		}
+		closed, err := statementExists(tx, entry.UserID, entry.Period)
+		if err != nil {
+			return err
+		}
+		if closed {
+			return ErrCommissionPeriodClosed
+		}
+
+		if entry.ReversedEntryID != nil {
+			var sale model.CommissionEntry
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", *entry.ReversedEntryID).Take(&sale).Error; err != nil {
+				return err
+			}
+			reversedBase, _, err := (&commissionRepository{db: tx}).ReversedTotals(sale.ID)
+			if err != nil {
+				return err
+			}
+			// Estornos são negativos: a base da venda mais os estornos não pode ficar abaixo de zero.
+			if sale.BaseAmount.Add(reversedBase).Add(entry.BaseAmount).Sign() < 0 {
+				return ErrCommissionOverReversed
+			}
+		}
+
+		entry.ID = util.NewSnowflake()
+		return tx.Create(entry).Error
+	})
+}
+
+func statementExists(tx *gorm.DB, userID int64, period time.Time) (bool, error) {
+	var count int64
+	err := tx.Model(&model.CommissionStatement{}).Where("user_id = ? AND period = ?", userID, period).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *commissionRepository) FindEntry(id int64) (*model.CommissionEntry, error) {
+	var entry model.CommissionEntry
+	if err := r.db.Where("id = ?", id).First(&entry).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *commissionRepository) FindSale(companyGlobalID int64, documentNumber string) (*model.CommissionEntry, error) {
+	var entry model.CommissionEntry
+	err := r.db.Where("company_global_id = ? AND document_number = ? AND kind = ?", companyGlobalID, documentNumber, model.CommissionSale).
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *commissionRepository) EventRecorded(eventID int64) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.CommissionEntry{}).Where("event_id = ?", eventID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *commissionRepository) FindEntries(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]model.CommissionEntry, dto.PageInfo, error) {
+	query := r.db.Model(&model.CommissionEntry{}).Where("company_global_id = ?", companyGlobalID)
+	return findPage[model.CommissionEntry](query, commissionEntryFilterSpec, commissionEntryProjectionSpec, filters, page, opts)
+}
+
+// Stream percorre todos os lançamentos que atendem aos filtros, sem paginação, para a exportação.
+func (r *commissionRepository) Stream(filters map[string][]string, opts dto.ReadOptions, companyGlobalID int64, fn func(*model.CommissionEntry) error) error {
+	query := r.db.Model(&model.CommissionEntry{}).Where("company_global_id = ?", companyGlobalID)
+	return streamAll(query, commissionEntryFilterSpec, commissionEntryProjectionSpec, filters, opts, fn)
+}
+
+func (r *commissionRepository) ReversedTotals(saleID int64) (decimal.Decimal, decimal.Decimal, error) {
+	var totals struct {
+		Base   decimal.Decimal
+		Amount decimal.Decimal
+	}
+	err := r.db.Model(&model.CommissionEntry{}).
+		Select("COALESCE(SUM(base_amount), 0) AS base, COALESCE(SUM(amount), 0) AS amount").
+		Where("reversed_entry_id = ?", saleID).
+		Scan(&totals).Error
+	return totals.Base, totals.Amount, err
+}
+
+func (r *commissionRepository) FindStatement(userID int64, period time.Time) (*model.CommissionStatement, error) {
+	var statement model.CommissionStatement
+	if err := r.db.Where("user_id = ? AND period = ?", userID, period).First(&statement).Error; err != nil {
+		return nil, err
+	}
+	return &statement, nil
+}
+
+func (r *commissionRepository) FindPeriodEntries(userID int64, period time.Time) ([]model.CommissionEntry, error) {
+	var entries []model.CommissionEntry
+	err := r.db.Where("user_id = ? AND period = ?", userID, period).Order("created_at, id").Find(&entries).Error
+	return entries, err
+}
+
+func (r *commissionRepository) FindStatements(companyGlobalID int64, period time.Time) ([]model.CommissionStatement, error) {
+	var approved []model.CommissionStatement
+	if err := r.db.Where("company_global_id = ? AND period = ?", companyGlobalID, period).Find(&approved).Error; err != nil {
+		return nil, err
+	}
+	var open []model.CommissionStatement
+	err := r.db.Model(&model.CommissionEntry{}).
+		Select("company_global_id, user_id, period, COUNT(*) AS entry_count, SUM(base_amount) AS base_amount, SUM(amount) AS total_amount").
+		Where("company_global_id = ? AND period = ? AND statement_id IS NULL", companyGlobalID, period).
+		Group("company_global_id, user_id, period").
+		Scan(&open).Error
+	if err != nil {
+		return nil, err
+	}
+
+	statements := append(approved, open...)
+	sort.Slice(statements, func(i, j int) bool { return statements[i].UserID < statements[j].UserID })
+	return statements, nil
+}
+
+func (r *commissionRepository) ApproveStatement(statement *model.CommissionStatement) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", statement.UserID).Take(&model.User{}).Error; err != nil {
+			return err
+		}
+		closed, err := statementExists(tx, statement.UserID, statement.Period)
+		if err != nil {
+			return err
+		}
+		if closed {
+			return ErrCommissionPeriodClosed
+		}
+
+		var totals struct {
+			Count  int
+			Base   decimal.Decimal
+			Amount decimal.Decimal
+		}
+		err = tx.Model(&model.CommissionEntry{}).
+			Select("COUNT(*) AS count, COALESCE(SUM(base_amount), 0) AS base, COALESCE(SUM(amount), 0) AS amount").
+			Where("user_id = ? AND period = ?", statement.UserID, statement.Period).
+			Scan(&totals).Error
+		if err != nil {
+			return err
+		}
+		statement.ID = util.NewSnowflake()
+		statement.EntryCount = totals.Count
+		statement.BaseAmount = totals.Base
+		statement.TotalAmount = totals.Amount
+		if err := tx.Create(statement).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.CommissionEntry{}).
+			Where("user_id = ? AND period = ?", statement.UserID, statement.Period).
+			Update("statement_id", statement.ID).Error
+	})
+}
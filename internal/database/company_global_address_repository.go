@@ -0,0 +1,97 @@
+package database
+
+import (
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+// CompanyGlobalAddressRepositoryInterface define os métodos de /company-globals/:id/addresses.
+// Toda escrita também incrementa a versão da company, já que os endereços fazem parte dela.
+type CompanyGlobalAddressRepositoryInterface interface {
+	FindAll(companyID int64) ([]*model.CompanyGlobalAddress, error)
+	FindByID(companyID int64, id int64) (*model.CompanyGlobalAddress, error)
+	Create(address *model.CompanyGlobalAddress) error
+	Update(address *model.CompanyGlobalAddress, version int64) error
+	Delete(companyID int64, id int64, version int64) error
+}
+
+// companyGlobalAddressRepository é a implementação concreta que usa o GORM.
+type companyGlobalAddressRepository struct {
+	db *gorm.DB
+}
+
+// NewCompanyGlobalAddressRepository cria uma nova instância do repositório de endereços da company.
+func NewCompanyGlobalAddressRepository(db *gorm.DB) CompanyGlobalAddressRepositoryInterface {
+	return &companyGlobalAddressRepository{db: db}
+}
+
+func (r *companyGlobalAddressRepository) FindAll(companyID int64) ([]*model.CompanyGlobalAddress, error) {
+	var addresses []*model.CompanyGlobalAddress
+	if err := r.db.Where("company_id = ?", companyID).Order("CASE WHEN type = 'MAIN' THEN 0 ELSE 1 END, type, id").Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+func (r *companyGlobalAddressRepository) FindByID(companyID int64, id int64) (*model.CompanyGlobalAddress, error) {
+	var address model.CompanyGlobalAddress
+	if err := r.db.Where("id = ? AND company_id = ?", id, companyID).First(&address).Error; err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+func (r *companyGlobalAddressRepository) Create(address *model.CompanyGlobalAddress) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, &model.CompanyGlobal{}, address.CompanyID); err != nil {
+			return err
+		}
+		address.ID = util.NewSnowflake()
+		address.Version = 1
+		return tx.Create(address).Error
+	})
+}
+
+// Update grava o endereço somente se a versão no banco ainda for version.
+func (r *companyGlobalAddressRepository) Update(address *model.CompanyGlobalAddress, version int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&model.CompanyGlobalAddress{}).Where("id = ? AND company_id = ?", address.ID, address.CompanyID)
+		result := whereVersion(query, version).Updates(map[string]any{
+			"type":              address.Type,
+			"street":            address.Street,
+			"street_number":     address.StreetNumber,
+			"street_complement": address.StreetComplement,
+			"city":              address.City,
+			"state":             address.State,
+			"postal_code":       address.PostalCode,
+			"country":           address.Country,
+			"city_ibge_code":    address.CityIBGECode,
+			"version":           nextVersion(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return missingOrConflict(tx.Where("company_id = ?", address.CompanyID), &model.CompanyGlobalAddress{}, address.ID)
+		}
+		address.Version = version + 1
+		return bumpVersion(tx, &model.CompanyGlobal{}, address.CompanyID)
+	})
+}
+
+// Delete remove o endereço (hard delete) somente se a versão no banco ainda for version.
+func (r *companyGlobalAddressRepository) Delete(companyID int64, id int64, version int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Unscoped().Where("id = ? AND company_id = ?", id, companyID)
+		result := whereVersion(query, version).Delete(&model.CompanyGlobalAddress{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return missingOrConflict(tx.Where("company_id = ?", companyID), &model.CompanyGlobalAddress{}, id)
+		}
+		return bumpVersion(tx, &model.CompanyGlobal{}, companyID)
+	})
+}
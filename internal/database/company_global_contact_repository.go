@@ -0,0 +1,101 @@
+package database
+
+import (
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+// CompanyGlobalContactRepositoryInterface define os métodos de /company-globals/:id/contacts.
+// Toda escrita também incrementa a versão da company, já que os contatos fazem parte dela.
+type CompanyGlobalContactRepositoryInterface interface {
+	FindAll(companyID int64) ([]*model.CompanyGlobalContact, error)
+	FindByID(companyID int64, id int64) (*model.CompanyGlobalContact, error)
+	Count(companyID int64) (int64, error)
+	Create(contact *model.CompanyGlobalContact) error
+	Update(contact *model.CompanyGlobalContact, version int64) error
+	Delete(companyID int64, id int64, version int64) error
+}
+
+// companyGlobalContactRepository é a implementação concreta que usa o GORM.
+type companyGlobalContactRepository struct {
+	db *gorm.DB
+}
+
+// NewCompanyGlobalContactRepository cria uma nova instância do repositório de contatos da company.
+func NewCompanyGlobalContactRepository(db *gorm.DB) CompanyGlobalContactRepositoryInterface {
+	return &companyGlobalContactRepository{db: db}
+}
+
+func (r *companyGlobalContactRepository) FindAll(companyID int64) ([]*model.CompanyGlobalContact, error) {
+	var contacts []*model.CompanyGlobalContact
+	if err := r.db.Where("company_id = ?", companyID).Order("name, id").Find(&contacts).Error; err != nil {
+		return nil, err
+	}
+	return contacts, nil
+}
+
+func (r *companyGlobalContactRepository) FindByID(companyID int64, id int64) (*model.CompanyGlobalContact, error) {
+	var contact model.CompanyGlobalContact
+	if err := r.db.Where("id = ? AND company_id = ?", id, companyID).First(&contact).Error; err != nil {
+		return nil, err
+	}
+	return &contact, nil
+}
+
+func (r *companyGlobalContactRepository) Count(companyID int64) (int64, error) {
+	var count int64
+	if err := r.db.Model(&model.CompanyGlobalContact{}).Where("company_id = ?", companyID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *companyGlobalContactRepository) Create(contact *model.CompanyGlobalContact) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, &model.CompanyGlobal{}, contact.CompanyID); err != nil {
+			return err
+		}
+		contact.ID = util.NewSnowflake()
+		contact.Version = 1
+		return tx.Create(contact).Error
+	})
+}
+
+// Update grava o contato somente se a versão no banco ainda for version.
+func (r *companyGlobalContactRepository) Update(contact *model.CompanyGlobalContact, version int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&model.CompanyGlobalContact{}).Where("id = ? AND company_id = ?", contact.ID, contact.CompanyID)
+		result := whereVersion(query, version).Updates(map[string]any{
+			"name":    contact.Name,
+			"email":   contact.Email,
+			"phone":   contact.Phone,
+			"cgc":     contact.CGC,
+			"version": nextVersion(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return missingOrConflict(tx.Where("company_id = ?", contact.CompanyID), &model.CompanyGlobalContact{}, contact.ID)
+		}
+		contact.Version = version + 1
+		return bumpVersion(tx, &model.CompanyGlobal{}, contact.CompanyID)
+	})
+}
+
+// Delete remove o contato (hard delete) somente se a versão no banco ainda for version.
+func (r *companyGlobalContactRepository) Delete(companyID int64, id int64, version int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Unscoped().Where("id = ? AND company_id = ?", id, companyID)
+		result := whereVersion(query, version).Delete(&model.CompanyGlobalContact{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return missingOrConflict(tx.Where("company_id = ?", companyID), &model.CompanyGlobalContact{}, id)
+		}
+		return bumpVersion(tx, &model.CompanyGlobal{}, companyID)
+	})
+}
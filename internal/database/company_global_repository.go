@@ -1,24 +1,70 @@
 package database
 
 import (
+	"errors"
+
+	"go-sales/internal/dto"
 	"go-sales/internal/model"
 	"go-sales/pkg/util"
 
 	"gorm.io/gorm"
 )
 
+// companyGlobalFilterSpec define os filtros e ordenações aceitos em GET /company-globals.
+var companyGlobalFilterSpec = FilterSpec{
+	Fields: map[string]FilterField{
+		"id":         {Column: "id", Type: FieldInt, Operators: IDOperators},
+		"name":       {Column: "name", Type: FieldString, Operators: TextOperators, Sortable: true},
+		"socialName": {Column: "social_name", Type: FieldString, Operators: TextOperators, Sortable: true},
+		"cgc":        {Column: "cgc", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"email":      {Column: "email", Type: FieldString, Operators: ExactTextOperators, Sortable: true, Nullable: true},
+		"enabled":    {Column: "enabled", Type: FieldBool, Operators: BoolOperators, Sortable: true},
+		"taxRegime":  {Column: "tax_regime", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"createdAt":  {Column: "created_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+		"updatedAt":  {Column: "updated_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+	},
+	DefaultSort: "name",
+}
+
+// companyGlobalProjectionSpec define os campos de ?fields= e as associações de ?expand= nas leituras de company-globals.
+var companyGlobalProjectionSpec = ProjectionSpec{
+	Columns: map[string]string{
+		"id":          "id",
+		"name":        "name",
+		"socialName":  "social_name",
+		"description": "description",
+		"cgc":         "cgc",
+		"enabled":     "enabled",
+		"email":       "email",
+		"taxRegime":   "tax_regime",
+		"pixKey":      "pix_key",
+		"createdAt":   "created_at",
+		"version":     "version",
+		"updatedAt":   "updated_at",
+		"deletedAt":   "deleted_at",
+	},
+	Expansions: map[string]Expansion{
+		// address (o principal) e addresses vêm da mesma associação.
+		"address":   {Association: "Addresses"},
+		"addresses": {Association: "Addresses"},
+		"contacts":  {Association: "Contacts"},
+	},
+}
+
 type CompanyGlobalRepository struct {
 	db *gorm.DB
 }
 
 type CompanyGlobalRepositoryInterface interface {
-	Create(company *model.CompanyGlobal) error
-	FindByID(id int64, useUnscoped bool) (*model.CompanyGlobal, error)
+	Create(company *model.CompanyGlobal, events ...Recorder[model.CompanyGlobal]) error
+	FindByID(id int64, useUnscoped bool, opts dto.ReadOptions) (*model.CompanyGlobal, error)
 	FindByCGC(cgc string, useUnscoped bool) (*model.CompanyGlobal, error)
-	Update(company *model.CompanyGlobal) error
-	Delete(id int64) error
-	FindAll(filters map[string][]string, page, pageSize int, useUnscoped bool) ([]model.CompanyGlobal, int64, error)
-	Restore(id int64) error
+	Update(company *model.CompanyGlobal, version int64, events ...Recorder[model.CompanyGlobal]) error
+	Patch(id int64, version int64, columns map[string]any, address *model.CompanyGlobalAddress, contacts []*model.CompanyGlobalContact, events ...Recorder[model.CompanyGlobal]) error
+	Delete(id int64, version int64, events ...Recorder[model.CompanyGlobal]) error
+	FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, useUnscoped bool) ([]model.CompanyGlobal, dto.PageInfo, error)
+	Stream(filters map[string][]string, opts dto.ReadOptions, fn func(*model.CompanyGlobal) error) error
+	Restore(id int64, events ...Recorder[model.CompanyGlobal]) error
 	Exists(id int64, useUnscoped bool) (bool, error)
 }
 
@@ -40,16 +86,19 @@ func (r *CompanyGlobalRepository) Exists(id int64, useUnscoped bool) (bool, erro
 	return count > 0, nil
 }
 
-func (r *CompanyGlobalRepository) Create(company *model.CompanyGlobal) error {
+func (r *CompanyGlobalRepository) Create(company *model.CompanyGlobal, events ...Recorder[model.CompanyGlobal]) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		// Garantir que os CompanyID dos filhos estejam definidos para que o GORM
 		// possa persistir as associações ao criar a company (evita inserts duplicados).
 
 		company.ID = util.NewSnowflake()
 
-		if company.Address != nil {
-			company.Address.ID = util.NewSnowflake()
-			company.Address.CompanyID = company.ID
+		for _, address := range company.Addresses {
+			if address == nil {
+				continue
+			}
+			address.ID = util.NewSnowflake()
+			address.CompanyID = company.ID
 		}
 
 		for _, contact := range company.Contacts {
@@ -65,17 +114,28 @@ func (r *CompanyGlobalRepository) Create(company *model.CompanyGlobal) error {
 			return err
 		}
 
-		return nil
+		return recordEvents(tx, events, reloadCompanyGlobal(tx, company.ID))
 	})
 }
 
-func (r *CompanyGlobalRepository) FindByID(id int64, useUnscoped bool) (*model.CompanyGlobal, error) {
+// reloadCompanyGlobal relê a company completa dentro de tx, para os eventos levarem o estado gravado.
+func reloadCompanyGlobal(tx *gorm.DB, id int64) func() (*model.CompanyGlobal, error) {
+	return func() (*model.CompanyGlobal, error) {
+		return (&CompanyGlobalRepository{db: tx}).FindByID(id, false, dto.FullRead)
+	}
+}
+
+func (r *CompanyGlobalRepository) FindByID(id int64, useUnscoped bool, opts dto.ReadOptions) (*model.CompanyGlobal, error) {
 	var company model.CompanyGlobal
 	dbQuery := r.db
 	if useUnscoped {
 		dbQuery = dbQuery.Unscoped()
 	}
-	if err := dbQuery.Preload("Address").Preload("Contacts").Where("id = ?", id).First(&company).Error; err != nil {
+	dbQuery, err := companyGlobalProjectionSpec.Project(dbQuery, opts, "version")
+	if err != nil {
+		return nil, err
+	}
+	if err := dbQuery.Where("id = ?", id).First(&company).Error; err != nil {
 		return nil, err
 	}
 	return &company, nil
@@ -87,147 +147,207 @@ func (r *CompanyGlobalRepository) FindByCGC(cgc string, useUnscoped bool) (*mode
 	if useUnscoped {
 		dbQuery = dbQuery.Unscoped()
 	}
-	if err := dbQuery.Preload("Address").Preload("Contacts").Where("cgc = ?", cgc).First(&company).Error; err != nil {
+	if err := dbQuery.Preload("Addresses").Preload("Contacts").Where("cgc = ?", cgc).First(&company).Error; err != nil {
 		return nil, err
 	}
 	return &company, nil
 }
 
-func (r *CompanyGlobalRepository) Update(company *model.CompanyGlobal) error {
+// Update grava a company somente se a versão no banco ainda for version. O endereço principal e os
+// contatos são atualizados no lugar, mantendo os ids (ver saveMainAddress e syncContacts).
+func (r *CompanyGlobalRepository) Update(company *model.CompanyGlobal, version int64, events ...Recorder[model.CompanyGlobal]) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		// Carrega company e associações atuais
 		var existing model.CompanyGlobal
-		if err := tx.Preload("Address").Preload("Contacts").Where("id = ?", company.ID).First(&existing).Error; err != nil {
+		if err := tx.Where("id = ?", company.ID).First(&existing).Error; err != nil {
 			return err
 		}
 
-		if company.Address != nil {
-			company.Address.ID = util.NewSnowflake()
-			company.Address.CompanyID = company.ID
+		// Atualiza os campos da tabela principal, incrementando a versão
+		company.Version = version + 1
+		result := whereVersion(tx.Model(&existing).Select(
+			"name", "social_name", "description", "cgc", "email", "enabled", "tax_regime", "pix_key", "version",
+		), version).Updates(company)
+		if result.Error != nil {
+			return result.Error
 		}
-
-		for _, contact := range company.Contacts {
-			if contact == nil {
-				continue
-			}
-			contact.ID = util.NewSnowflake()
-			contact.CompanyID = company.ID
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
 		}
 
-		// Atualiza os campos da tabela principal
-		if err := tx.Model(&existing).Select(
-			"name", "social_name", "description", "cgc", "email", "enabled",
-		).Updates(company).Error; err != nil {
+		if err := saveMainAddress(tx, company.ID, company.MainAddress()); err != nil {
 			return err
 		}
-
-		// Delete all addresses (hard delete) to avoid GORM setting FK to NULL
-		if err := tx.Unscoped().Where("company_id = ?", company.ID).Delete(&model.CompanyGlobalAddress{}).Error; err != nil {
+		if err := syncContacts(tx, company.ID, company.Contacts); err != nil {
 			return err
 		}
-		// Recreate address if veio no payload
-		if company.Address != nil {
-			if err := tx.Create(company.Address).Error; err != nil {
-				return err
-			}
+		return recordEvents(tx, events, reloadCompanyGlobal(tx, company.ID))
+	})
+}
+
+// Patch atualiza apenas as colunas informadas (PATCH), desde que a versão no banco ainda seja version.
+// address, quando informado, é o endereço principal já mesclado pelo serviço; contacts, quando não
+// for nil, substitui a lista atual, mantendo os ids dos contatos que vierem com id.
+func (r *CompanyGlobalRepository) Patch(id int64, version int64, columns map[string]any, address *model.CompanyGlobalAddress, contacts []*model.CompanyGlobalContact, events ...Recorder[model.CompanyGlobal]) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// A versão é incrementada mesmo quando só o endereço ou os contatos mudam.
+		columns["version"] = nextVersion()
+		result := whereVersion(tx.Model(&model.CompanyGlobal{}).Where("id = ?", id), version).Updates(columns)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return missingOrConflict(tx, &model.CompanyGlobal{}, id)
 		}
 
-		// Delete all contacts (hard delete) and recreate from payload
-		if err := tx.Unscoped().Where("company_id = ?", company.ID).Delete(&model.CompanyGlobalContact{}).Error; err != nil {
+		if err := saveMainAddress(tx, id, address); err != nil {
 			return err
 		}
-		for _, contact := range company.Contacts {
-			if contact == nil {
-				continue
-			}
-			if err := tx.Create(contact).Error; err != nil {
+		if contacts != nil {
+			if err := syncContacts(tx, id, contacts); err != nil {
 				return err
 			}
 		}
-
-		return nil
+		return recordEvents(tx, events, reloadCompanyGlobal(tx, id))
 	})
 }
 
-// ...existing code...
+// saveMainAddress grava address por cima do endereço principal atual, mantendo o id e incrementando
+// a versão. Se a empresa ainda não tiver endereço principal, ele é criado.
+func saveMainAddress(tx *gorm.DB, companyID int64, address *model.CompanyGlobalAddress) error {
+	if address == nil {
+		return nil
+	}
+	address.CompanyID = companyID
+	address.Type = model.AddressTypeMain
+
+	var current model.CompanyGlobalAddress
+	err := tx.Where("company_id = ? AND type = ?", companyID, model.AddressTypeMain).First(&current).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		address.ID = util.NewSnowflake()
+		address.Version = 1
+		return tx.Create(address).Error
+	}
+	if err != nil {
+		return err
+	}
 
-func (r *CompanyGlobalRepository) Delete(id int64) error {
-	// Executa a operação de delete e armazena o resultado.
-	result := r.db.Where("id = ?", id).Delete(&model.CompanyGlobal{})
+	address.ID = current.ID
+	address.Version = current.Version + 1
+	return tx.Select("*").Save(address).Error
+}
 
-	// Primeiro, verifica se houve um erro real na execução da query.
-	if result.Error != nil {
-		return result.Error
+// syncContacts compara contacts com os contatos atuais pelo id: os que têm id são atualizados no lugar,
+// os sem id são criados e os que não vieram são removidos. Os ids já foram validados pelo serviço.
+func syncContacts(tx *gorm.DB, companyID int64, contacts []*model.CompanyGlobalContact) error {
+	keep := make([]int64, 0, len(contacts))
+	for _, contact := range contacts {
+		if contact != nil && contact.ID != 0 {
+			keep = append(keep, contact.ID)
+		}
 	}
 
-	// Se não houve erro, verifica se alguma linha foi realmente afetada.
-	// Se RowsAffected for 0, significa que o registro não foi encontrado.
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+	// Remove primeiro, liberando email, telefone e CGC (únicos por empresa) para os contatos novos.
+	// Hard delete para evitar que o GORM apenas anule a FK.
+	remove := tx.Unscoped().Where("company_id = ?", companyID)
+	if len(keep) > 0 {
+		remove = remove.Where("id NOT IN ?", keep)
+	}
+	if err := remove.Delete(&model.CompanyGlobalContact{}).Error; err != nil {
+		return err
 	}
 
+	for _, contact := range contacts {
+		if contact == nil {
+			continue
+		}
+		contact.CompanyID = companyID
+		if contact.ID == 0 {
+			contact.ID = util.NewSnowflake()
+			contact.Version = 1
+			if err := tx.Create(contact).Error; err != nil {
+				return err
+			}
+			continue
+		}
+
+		result := tx.Model(&model.CompanyGlobalContact{}).
+			Where("id = ? AND company_id = ?", contact.ID, companyID).
+			Updates(map[string]any{
+				"name":    contact.Name,
+				"email":   contact.Email,
+				"phone":   contact.Phone,
+				"cgc":     contact.CGC,
+				"version": nextVersion(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+	}
 	return nil
 }
-func (r *CompanyGlobalRepository) FindAll(filters map[string][]string, page, pageSize int, useUnscoped bool) ([]model.CompanyGlobal, int64, error) {
-	var companies []model.CompanyGlobal
-	var totalItems int64
-	query := r.db.Model(&model.CompanyGlobal{})
 
-	if useUnscoped {
-		query = query.Unscoped()
-	}
+// ...existing code...
 
-	// Lista de colunas permitidas para filtragem para evitar injeção de SQL.
-	allowedFilters := map[string]bool{
-		"name":        true,
-		"cgc":         true,
-		"enabled":     true,
-		"email":       true,
-		"social_name": true,
-	}
-
-	for key, value := range filters {
-		// Verifica se o filtro é permitido e se tem um valor.
-		if allowed, ok := allowedFilters[key]; ok && allowed && len(value) > 0 {
-			// Para campos de texto, usamos 'LIKE' para buscas parciais.
-			// Para outros, usamos '=' para buscas exatas.
-			if key == "name" {
-				query = query.Where("name ILIKE ?", "%"+value[0]+"%") // ILIKE é case-insensitive (PostgreSQL)
-			} else if key == "social_name" {
-				query = query.Where("social_name ILIKE ?", "%"+value[0]+"%")
-			} else {
-				query = query.Where(key+" = ?", value[0])
-			}
+// Delete apaga a company. Os eventos de events são montados com a company lida antes da exclusão.
+func (r *CompanyGlobalRepository) Delete(id int64, version int64, events ...Recorder[model.CompanyGlobal]) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Os eventos são gravados antes: se o delete falhar, a transação desfaz os dois.
+		if err := recordEvents(tx, events, lockedLoad(tx, id, reloadCompanyGlobal(tx, id))); err != nil {
+			return err
 		}
-	}
 
-	// 1. Faz a contagem do total de itens que correspondem ao filtro, ANTES de aplicar limit/offset.
-	if err := query.Count(&totalItems).Error; err != nil {
-		return nil, 0, err
-	}
+		// Executa a operação de delete e armazena o resultado.
+		result := whereVersion(tx.Where("id = ?", id), version).Delete(&model.CompanyGlobal{})
 
-	// 2. Calcula o offset para a paginação.
-	offset := (page - 1) * pageSize
+		// Primeiro, verifica se houve um erro real na execução da query.
+		if result.Error != nil {
+			return result.Error
+		}
 
-	// 3. Aplica a paginação (limit e offset) e busca os itens da página.
-	if err := query.Preload("Address").Preload("Contacts").Offset(offset).Limit(pageSize).Find(&companies).Error; err != nil {
-		return nil, 0, err
-	}
+		// Se não houve erro, verifica se alguma linha foi realmente afetada.
+		// Se RowsAffected for 0, o registro não existe ou a versão informada está desatualizada.
+		if result.RowsAffected == 0 {
+			return missingOrConflict(tx, &model.CompanyGlobal{}, id)
+		}
 
-	return companies, totalItems, nil
+		return nil
+	})
 }
 
-func (r *CompanyGlobalRepository) Restore(id int64) error {
-	result := r.db.Model(&model.CompanyGlobal{}).
-		Unscoped().
-		Where("id = ?", id).
-		Update("deleted_at", nil)
+func (r *CompanyGlobalRepository) FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, useUnscoped bool) ([]model.CompanyGlobal, dto.PageInfo, error) {
+	query := r.db.Model(&model.CompanyGlobal{})
 
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+	if useUnscoped {
+		query = query.Unscoped()
 	}
-	return nil
+
+	// Os filtros aceitos e as colunas correspondentes vêm da spec, evitando injeção de SQL.
+	// As associações só são carregadas quando pedidas em ?expand=.
+	return findPage[model.CompanyGlobal](query, companyGlobalFilterSpec, companyGlobalProjectionSpec, filters, page, opts)
+}
+
+// Stream percorre todas as companies que atendem aos filtros, sem paginação, para a exportação.
+func (r *CompanyGlobalRepository) Stream(filters map[string][]string, opts dto.ReadOptions, fn func(*model.CompanyGlobal) error) error {
+	query := r.db.Model(&model.CompanyGlobal{})
+	return streamAll(query, companyGlobalFilterSpec, companyGlobalProjectionSpec, filters, opts, fn)
+}
+
+func (r *CompanyGlobalRepository) Restore(id int64, events ...Recorder[model.CompanyGlobal]) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.CompanyGlobal{}).
+			Unscoped().
+			Where("id = ?", id).
+			Updates(map[string]any{"deleted_at": nil, "version": nextVersion()})
+
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return recordEvents(tx, events, reloadCompanyGlobal(tx, id))
+	})
 }
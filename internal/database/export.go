@@ -0,0 +1,47 @@
+package database
+
+import (
+	"go-sales/internal/dto"
+
+	"gorm.io/gorm"
+)
+
+// streamAll percorre todas as linhas que atendem aos filtros, na ordem de ?sort=, lendo uma linha
+// por vez do cursor do banco (Rows) em vez de carregar a lista em memória. ?fields= limita as
+// colunas lidas. ?expand= não é aceito, pois o Preload das associações exigiria a lista inteira.
+// A leitura para no primeiro erro devolvido por fn.
+func streamAll[T any](query *gorm.DB, spec FilterSpec, projection ProjectionSpec, filters map[string][]string, opts dto.ReadOptions, fn func(*T) error) error {
+	if len(opts.Expand) > 0 || opts.ExpandAll {
+		return &FilterError{Param: "expand", Reason: "expand is not supported when exporting"}
+	}
+
+	query, err := spec.Apply(query, filters)
+	if err != nil {
+		return err
+	}
+	keys, err := spec.parseSort(filters)
+	if err != nil {
+		return err
+	}
+	query, err = projection.Project(query, opts)
+	if err != nil {
+		return err
+	}
+
+	rows, err := applyOrder(query, keys, false).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item T
+		if err := query.ScanRows(rows, &item); err != nil {
+			return err
+		}
+		if err := fn(&item); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
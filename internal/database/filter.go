@@ -0,0 +1,349 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FilterOperator é um operador aceito na query string: ?campo[operador]=valor.
+type FilterOperator string
+
+const (
+	OpEq       FilterOperator = "eq"
+	OpNe       FilterOperator = "ne"
+	OpIn       FilterOperator = "in"
+	OpGt       FilterOperator = "gt"
+	OpLt       FilterOperator = "lt"
+	OpBetween  FilterOperator = "between"
+	OpContains FilterOperator = "contains"
+	OpIsNull   FilterOperator = "isnull"
+)
+
+// FieldType define como os valores recebidos como texto são convertidos antes de ir para o banco.
+type FieldType int
+
+const (
+	FieldString FieldType = iota
+	FieldInt
+	FieldBool
+	FieldTime
+)
+
+// Conjuntos de operadores mais comuns. O primeiro operador é o padrão quando
+// o cliente não informa nenhum (?name=joao equivale a ?name[contains]=joao).
+var (
+	TextOperators      = []FilterOperator{OpContains, OpEq, OpNe, OpIn, OpIsNull}
+	ExactTextOperators = []FilterOperator{OpEq, OpContains, OpNe, OpIn, OpIsNull}
+	IDOperators        = []FilterOperator{OpEq, OpNe, OpIn}
+	BoolOperators      = []FilterOperator{OpEq}
+	TimeOperators      = []FilterOperator{OpBetween, OpGt, OpLt, OpIsNull}
+)
+
+// FilterField descreve um campo da API que pode ser filtrado e/ou ordenado.
+// Campos Nullable podem ser ordenados no modo offset, mas não no modo cursor,
+// pois comparações com NULL quebrariam a paginação por keyset.
+type FilterField struct {
+	Column    string
+	Type      FieldType
+	Operators []FilterOperator
+	Sortable  bool
+	Nullable  bool
+}
+
+// FilterSpec é a especificação declarativa de filtros e ordenação de uma entidade.
+// As chaves de Fields são os nomes usados na API (camelCase, iguais aos dos DTOs).
+type FilterSpec struct {
+	Fields map[string]FilterField
+	// DefaultSort é aplicado quando o cliente não envia ?sort= (ex: "id" ou "-createdAt").
+	DefaultSort string
+}
+
+// reservedParams são parâmetros da query string que não são filtros.
+var reservedParams = map[string]bool{
+	"page":     true,
+	"pageSize": true,
+	"sort":     true,
+	"cursor":   true,
+	"limit":    true,
+	"count":    true,
+	"fields":   true,
+	"expand":   true,
+}
+
+// FilterError indica um filtro ou ordenação inválidos enviados pelo cliente.
+type FilterError struct {
+	Param  string
+	Reason string
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("invalid filter %q: %s", e.Param, e.Reason)
+}
+
+var filterKeyPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_]*)(?:\[([a-z]+)\])?$`)
+
+// Apply aplica na query os filtros presentes em params.
+// Filtros desconhecidos, operadores não permitidos e valores inválidos retornam *FilterError.
+func (s FilterSpec) Apply(query *gorm.DB, params map[string][]string) (*gorm.DB, error) {
+	for key, values := range params {
+		if reservedParams[key] {
+			continue
+		}
+		match := filterKeyPattern.FindStringSubmatch(key)
+		if match == nil {
+			return nil, &FilterError{Param: key, Reason: "unknown filter"}
+		}
+		field, ok := s.Fields[match[1]]
+		if !ok {
+			return nil, &FilterError{Param: key, Reason: "unknown filter"}
+		}
+
+		op := field.Operators[0]
+		if match[2] != "" {
+			op = FilterOperator(match[2])
+			if !field.allows(op) {
+				return nil, &FilterError{Param: key, Reason: "operator not allowed"}
+			}
+		}
+
+		condition, args, err := field.condition(op, values)
+		if err != nil {
+			return nil, &FilterError{Param: key, Reason: err.Error()}
+		}
+		if condition != "" {
+			query = query.Where(condition, args...)
+		}
+	}
+	return query, nil
+}
+
+// sortKey é um item já validado de ?sort=.
+type sortKey struct {
+	Name  string
+	Field FilterField
+	Desc  bool
+}
+
+// idField descreve a coluna id, usada como desempate em toda ordenação.
+var idField = FilterField{Column: "id", Type: FieldInt, Operators: IDOperators, Sortable: true}
+
+// parseSort valida ?sort=-createdAt,name contra a spec. Apenas campos marcados como Sortable
+// são aceitos, e o id é sempre acrescentado como desempate para manter a paginação estável.
+func (s FilterSpec) parseSort(params map[string][]string) ([]sortKey, error) {
+	sortParam := s.DefaultSort
+	if values := params["sort"]; len(values) > 0 && values[0] != "" {
+		sortParam = values[0]
+	}
+
+	keys := make([]sortKey, 0, 2)
+	hasID := false
+	for _, item := range strings.Split(sortParam, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		desc := strings.HasPrefix(item, "-")
+		name := strings.TrimPrefix(item, "-")
+
+		field, ok := s.Fields[name]
+		if name == "id" {
+			field, ok = idField, true
+			hasID = true
+		}
+		if !ok || !field.Sortable {
+			return nil, &FilterError{Param: "sort", Reason: fmt.Sprintf("cannot sort by %q", name)}
+		}
+		keys = append(keys, sortKey{Name: name, Field: field, Desc: desc})
+	}
+	if !hasID {
+		keys = append(keys, sortKey{Name: "id", Field: idField})
+	}
+	return keys, nil
+}
+
+// Sort aplica a ordenação de ?sort=-createdAt,name.
+func (s FilterSpec) Sort(query *gorm.DB, params map[string][]string) (*gorm.DB, error) {
+	keys, err := s.parseSort(params)
+	if err != nil {
+		return nil, err
+	}
+	return applyOrder(query, keys, false), nil
+}
+
+// applyOrder aplica a ordenação das chaves; reverse inverte todas as direções
+// (usado para buscar a página anterior no modo keyset).
+func applyOrder(query *gorm.DB, keys []sortKey, reverse bool) *gorm.DB {
+	for _, key := range keys {
+		query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: key.Field.Column}, Desc: key.Desc != reverse})
+	}
+	return query
+}
+
+func (f FilterField) allows(op FilterOperator) bool {
+	for _, allowed := range f.Operators {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
+
+// condition monta a cláusula SQL para um operador. Os nomes das colunas vêm sempre da
+// FilterSpec (nunca do cliente) e os valores são sempre passados como parâmetros.
+func (f FilterField) condition(op FilterOperator, rawValues []string) (string, []any, error) {
+	values := splitValues(op, rawValues)
+	if len(values) == 0 {
+		return "", nil, fmt.Errorf("missing value")
+	}
+
+	// Múltiplos valores sem operador explícito viram uma lista: ?status=a&status=b.
+	if len(values) > 1 {
+		switch op {
+		case OpEq:
+			op = OpIn
+		case OpNe, OpContains, OpIn, OpBetween:
+		default:
+			return "", nil, fmt.Errorf("operator %s accepts a single value", op)
+		}
+	}
+
+	switch op {
+	case OpIsNull:
+		isNull, err := strconv.ParseBool(values[0])
+		if err != nil {
+			return "", nil, fmt.Errorf("isnull expects true or false")
+		}
+		if isNull {
+			return f.Column + " IS NULL", nil, nil
+		}
+		return f.Column + " IS NOT NULL", nil, nil
+
+	case OpContains:
+		if f.Type != FieldString {
+			return "", nil, fmt.Errorf("contains is only valid for text fields")
+		}
+		clauses := make([]string, len(values))
+		args := make([]any, len(values))
+		for i, v := range values {
+			clauses[i] = f.Column + " ILIKE ?" // ILIKE é case-insensitive (PostgreSQL)
+			args[i] = "%" + escapeLike(v) + "%"
+		}
+		return "(" + strings.Join(clauses, " OR ") + ")", args, nil
+
+	case OpBetween:
+		if len(values) != 2 {
+			return "", nil, fmt.Errorf("between expects exactly two values")
+		}
+		args, err := f.convertAll(values)
+		if err != nil {
+			return "", nil, err
+		}
+		// Uma data sem hora no fim do intervalo inclui o dia inteiro: vira "antes do dia seguinte".
+		if f.Type == FieldTime && isDateOnly(values[1]) {
+			args[1] = args[1].(time.Time).AddDate(0, 0, 1)
+			return "(" + f.Column + " >= ? AND " + f.Column + " < ?)", args, nil
+		}
+		return f.Column + " BETWEEN ? AND ?", args, nil
+	}
+
+	args, err := f.convertAll(values)
+	if err != nil {
+		return "", nil, err
+	}
+	switch op {
+	case OpEq:
+		return f.Column + " = ?", args, nil
+	case OpGt:
+		return f.Column + " > ?", args, nil
+	case OpLt:
+		return f.Column + " < ?", args, nil
+	case OpIn:
+		return f.Column + " IN ?", []any{args}, nil
+	case OpNe:
+		if len(args) == 1 {
+			return f.Column + " <> ?", args, nil
+		}
+		return f.Column + " NOT IN ?", []any{args}, nil
+	}
+	return "", nil, fmt.Errorf("unsupported operator %s", op)
+}
+
+func (f FilterField) convertAll(values []string) ([]any, error) {
+	out := make([]any, len(values))
+	for i, v := range values {
+		converted, err := f.convert(v)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = converted
+	}
+	return out, nil
+}
+
+func (f FilterField) convert(value string) (any, error) {
+	switch f.Type {
+	case FieldInt:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a valid integer", value)
+		}
+		return v, nil
+	case FieldBool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a valid boolean", value)
+		}
+		return v, nil
+	case FieldTime:
+		return parseFilterTime(value)
+	default:
+		return value, nil
+	}
+}
+
+// parseFilterTime aceita RFC 3339 (2024-05-01T10:00:00Z) ou apenas a data (2024-05-01).
+func parseFilterTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a valid date (use RFC 3339 or YYYY-MM-DD)", value)
+}
+
+// isDateOnly diz se o valor é só a data (YYYY-MM-DD), sem hora.
+func isDateOnly(value string) bool {
+	_, err := time.Parse(time.DateOnly, value)
+	return err == nil
+}
+
+// splitValues junta as ocorrências repetidas do parâmetro e, para in/between/ne,
+// também separa valores por vírgula (?id[in]=1,2,3).
+func splitValues(op FilterOperator, rawValues []string) []string {
+	values := make([]string, 0, len(rawValues))
+	for _, raw := range rawValues {
+		if op == OpIn || op == OpBetween || op == OpNe {
+			for _, part := range strings.Split(raw, ",") {
+				if part = strings.TrimSpace(part); part != "" {
+					values = append(values, part)
+				}
+			}
+			continue
+		}
+		if raw != "" {
+			values = append(values, raw)
+		}
+	}
+	return values
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
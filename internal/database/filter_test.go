@@ -0,0 +1,46 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBetweenCondition(t *testing.T) {
+	field := FilterField{Column: "created_at", Type: FieldTime, Operators: []FilterOperator{OpBetween}}
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		values   []string
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "date-only upper bound includes the whole day",
+			values:   []string{"2025-01-01,2025-01-31"},
+			wantSQL:  "(created_at >= ? AND created_at < ?)",
+			wantArgs: []any{day(1), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "RFC 3339 upper bound is kept as sent",
+			values:   []string{"2025-01-01,2025-01-31T12:00:00Z"},
+			wantSQL:  "created_at BETWEEN ? AND ?",
+			wantArgs: []any{day(1), time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := field.condition(OpBetween, tt.values)
+			if err != nil {
+				t.Fatalf("condition: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Fatalf("sql = %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Fatalf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"go-sales/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrIdempotencyLockLost é retornado por Extend e Complete quando a reserva venceu e outra requisição
+// tomou a chave.
+var ErrIdempotencyLockLost = errors.New("idempotency key lock lost")
+
+// IdempotencyRepositoryInterface define os métodos para guardar e reaproveitar respostas de POSTs
+// enviados com o header Idempotency-Key.
+type IdempotencyRepositoryInterface interface {
+	Reserve(record *model.IdempotencyKey, now time.Time) (bool, error)
+	Find(tenantID int64, scope string, key string) (*model.IdempotencyKey, error)
+	Extend(record *model.IdempotencyKey, lockedUntil time.Time) error
+	Complete(record *model.IdempotencyKey) error
+	Release(record *model.IdempotencyKey) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// idempotencyRepository é a implementação concreta que usa o GORM.
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyRepository cria uma nova instância do repositório de chaves de idempotência.
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepositoryInterface {
+	return &idempotencyRepository{db: db}
+}
+
+// Reserve grava a chave como processing. Devolve false quando outra requisição já detém a chave;
+// registros expirados ou com a reserva vencida (processo que caiu no meio) são reaproveitados.
+// A disputa é resolvida pelo próprio INSERT ... ON CONFLICT, então só uma requisição vence, e o
+// LockToken do record passa a identificar a reserva dela.
+func (r *idempotencyRepository) Reserve(record *model.IdempotencyKey, now time.Time) (bool, error) {
+	record.Status = model.IdempotencyStatusProcessing
+	result := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "tenant_id"}, {Name: "scope"}, {Name: "idempotency_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"request_hash", "status", "response_status", "response_headers", "response_body",
+			"lock_token", "locked_until", "expires_at", "created_at", "updated_at",
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Or(
+				clause.Expr{SQL: "idempotency_keys.expires_at < ?", Vars: []any{now}},
+				clause.And(
+					clause.Eq{Column: "idempotency_keys.status", Value: model.IdempotencyStatusProcessing},
+					clause.Expr{SQL: "idempotency_keys.locked_until < ?", Vars: []any{now}},
+				),
+			),
+		}},
+	}).Create(record)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *idempotencyRepository) Find(tenantID int64, scope string, key string) (*model.IdempotencyKey, error) {
+	var record model.IdempotencyKey
+	if err := r.db.Where("tenant_id = ? AND scope = ? AND idempotency_key = ?", tenantID, scope, key).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// held filtra a chave do record enquanto ela ainda está reservada por ele.
+func (r *idempotencyRepository) held(record *model.IdempotencyKey) *gorm.DB {
+	return r.db.Model(&model.IdempotencyKey{}).
+		Where("tenant_id = ? AND scope = ? AND idempotency_key = ? AND lock_token = ? AND status = ?",
+			record.TenantID, record.Scope, record.Key, record.LockToken, model.IdempotencyStatusProcessing)
+}
+
+// Extend renova o prazo da reserva enquanto a requisição executa. Devolve ErrIdempotencyLockLost se a
+// reserva já não é do record.
+func (r *idempotencyRepository) Extend(record *model.IdempotencyKey, lockedUntil time.Time) error {
+	result := r.held(record).Update("locked_until", lockedUntil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrIdempotencyLockLost
+	}
+	record.LockedUntil = lockedUntil
+	return nil
+}
+
+// Complete grava a resposta da requisição que detém a chave. Devolve ErrIdempotencyLockLost, sem
+// gravar nada, se outra requisição tomou a chave depois que a reserva venceu.
+func (r *idempotencyRepository) Complete(record *model.IdempotencyKey) error {
+	result := r.held(record).Updates(map[string]any{
+		"status":           model.IdempotencyStatusCompleted,
+		"response_status":  record.ResponseStatus,
+		"response_headers": record.ResponseHeaders,
+		"response_body":    record.ResponseBody,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrIdempotencyLockLost
+	}
+	return nil
+}
+
+// Release apaga a chave, liberando uma nova tentativa (usado quando a resposta não deve ser
+// reaproveitada). A chave já tomada por outra requisição fica como está.
+func (r *idempotencyRepository) Release(record *model.IdempotencyKey) error {
+	return r.db.Where("tenant_id = ? AND scope = ? AND idempotency_key = ? AND lock_token = ?",
+		record.TenantID, record.Scope, record.Key, record.LockToken).Delete(&model.IdempotencyKey{}).Error
+}
+
+// DeleteExpired remove as chaves cujo TTL já passou.
+func (r *idempotencyRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", now).Delete(&model.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
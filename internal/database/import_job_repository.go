@@ -0,0 +1,76 @@
+package database
+
+import (
+	"time"
+
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+// ImportJobRepositoryInterface define os métodos para acompanhar as importações em massa.
+type ImportJobRepositoryInterface interface {
+	Create(job *model.ImportJob) error
+	Update(job *model.ImportJob) error
+	FindByID(id int64) (*model.ImportJob, error)
+	SaveRows(id int64, rows []byte) error
+	FindRows(id int64) ([]byte, error)
+	ClearRows(id int64) error
+	ClearStaleRows(before time.Time) (int64, error)
+}
+
+// importJobRepository é a implementação concreta que usa o GORM.
+type importJobRepository struct {
+	db *gorm.DB
+}
+
+// NewImportJobRepository cria uma nova instância do repositório de importações.
+func NewImportJobRepository(db *gorm.DB) ImportJobRepositoryInterface {
+	return &importJobRepository{db: db}
+}
+
+func (r *importJobRepository) Create(job *model.ImportJob) error {
+	job.ID = util.NewSnowflake()
+	return r.db.Create(job).Error
+}
+
+// Update grava o progresso da importação.
+func (r *importJobRepository) Update(job *model.ImportJob) error {
+	return r.db.Select("*").Omit("created_at").Save(job).Error
+}
+
+func (r *importJobRepository) FindByID(id int64) (*model.ImportJob, error) {
+	var job model.ImportJob
+	if err := r.db.Where("id = ?", id).First(&job).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// SaveRows grava as linhas da planilha (JSON) que o job da importação vai processar. Elas ficam fora
+// do model para que Update não as leia nem as regrave a cada lote.
+func (r *importJobRepository) SaveRows(id int64, rows []byte) error {
+	return r.db.Model(&model.ImportJob{}).Where("id = ?", id).Update("rows", rows).Error
+}
+
+// FindRows devolve as linhas gravadas por SaveRows, ou nil se já foram apagadas.
+func (r *importJobRepository) FindRows(id int64) ([]byte, error) {
+	var rows []byte
+	err := r.db.Model(&model.ImportJob{}).Select("rows").Where("id = ?", id).Row().Scan(&rows)
+	return rows, err
+}
+
+// ClearRows apaga as linhas da planilha quando a importação termina; elas podem ter senhas em texto puro.
+func (r *importJobRepository) ClearRows(id int64) error {
+	return r.db.Model(&model.ImportJob{}).Where("id = ?", id).Update("rows", nil).Error
+}
+
+// ClearStaleRows apaga as linhas das importações criadas antes de before que não chegaram a
+// terminar (o processo caiu durante a execução).
+func (r *importJobRepository) ClearStaleRows(before time.Time) (int64, error) {
+	result := r.db.Model(&model.ImportJob{}).
+		Where("rows IS NOT NULL AND created_at < ?", before).
+		Update("rows", nil)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,160 @@
+package database
+
+import (
+	"errors"
+
+	"go-sales/internal/dto"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+// ErrInstallmentNotOpen é retornado por Settle quando a parcela já foi paga ou cancelada.
+var ErrInstallmentNotOpen = errors.New("installment is not open")
+
+// InstallmentRepositoryInterface define os métodos para interagir com as parcelas a receber.
+type InstallmentRepositoryInterface interface {
+	Create(installment *model.Installment) error
+	FindByID(id int64, opts dto.ReadOptions) (*model.Installment, error)
+	FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]model.Installment, dto.PageInfo, error)
+	Patch(id int64, version int64, columns map[string]any) error
+	Delete(id int64, version int64) error
+	// Settle baixa a parcela em aberto com o pagamento informado.
+	Settle(id int64, payment model.InstallmentPayment, events ...Recorder[model.Installment]) error
+}
+
+// installmentFilterSpec define os filtros e ordenações aceitos em GET /installments.
+var installmentFilterSpec = FilterSpec{
+	Fields: map[string]FilterField{
+		"id":                {Column: "id", Type: FieldInt, Operators: IDOperators},
+		"documentNumber":    {Column: "document_number", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"installmentNumber": {Column: "installment_number", Type: FieldInt, Operators: IDOperators, Sortable: true},
+		"customerName":      {Column: "customer_name", Type: FieldString, Operators: TextOperators, Sortable: true},
+		"customerCgc":       {Column: "customer_cgc", Type: FieldString, Operators: ExactTextOperators, Sortable: true, Nullable: true},
+		"dueDate":           {Column: "due_date", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+		"status":            {Column: "status", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"paymentMethod":     {Column: "payment_method", Type: FieldString, Operators: ExactTextOperators, Sortable: true, Nullable: true},
+		"paidAt":            {Column: "paid_at", Type: FieldTime, Operators: TimeOperators, Sortable: true, Nullable: true},
+		"createdAt":         {Column: "created_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+		"updatedAt":         {Column: "updated_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+	},
+	DefaultSort: "dueDate",
+}
+
+// installmentProjectionSpec define os campos de ?fields= nas leituras de parcelas.
+var installmentProjectionSpec = ProjectionSpec{
+	Columns: map[string]string{
+		"id":                "id",
+		"companyGlobalId":   "company_global_id",
+		"documentNumber":    "document_number",
+		"installmentNumber": "installment_number",
+		"customerName":      "customer_name",
+		"customerCgc":       "customer_cgc",
+		"description":       "description",
+		"amount":            "amount",
+		"dueDate":           "due_date",
+		"status":            "status",
+		"paidAmount":        "paid_amount",
+		"paidAt":            "paid_at",
+		"paymentMethod":     "payment_method",
+		"paymentReference":  "payment_reference",
+		"createdAt":         "created_at",
+		"version":           "version",
+		"updatedAt":         "updated_at",
+	},
+}
+
+// installmentRepository é a implementação concreta que usa o GORM.
+type installmentRepository struct {
+	db *gorm.DB
+}
+
+// NewInstallmentRepository cria uma nova instância do repositório de parcelas.
+func NewInstallmentRepository(db *gorm.DB) InstallmentRepositoryInterface {
+	return &installmentRepository{db: db}
+}
+
+func (r *installmentRepository) Create(installment *model.Installment) error {
+	installment.ID = util.NewSnowflake()
+	return r.db.Create(installment).Error
+}
+
+func (r *installmentRepository) FindByID(id int64, opts dto.ReadOptions) (*model.Installment, error) {
+	var installment model.Installment
+	query, err := installmentProjectionSpec.Project(r.db, opts, "version")
+	if err != nil {
+		return nil, err
+	}
+	if err := query.Where("id = ?", id).First(&installment).Error; err != nil {
+		return nil, err
+	}
+	return &installment, nil
+}
+
+func (r *installmentRepository) FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]model.Installment, dto.PageInfo, error) {
+	query := r.db.Model(&model.Installment{}).Where("company_global_id = ?", companyGlobalID)
+	return findPage[model.Installment](query, installmentFilterSpec, installmentProjectionSpec, filters, page, opts)
+}
+
+// Patch atualiza apenas as colunas informadas (PATCH), desde que a versão no banco ainda seja version
+// e a parcela continue em aberto.
+func (r *installmentRepository) Patch(id int64, version int64, columns map[string]any) error {
+	columns["version"] = nextVersion()
+	query := r.db.Model(&model.Installment{}).Where("id = ? AND status = ?", id, model.InstallmentOpen)
+	result := whereVersion(query, version).Updates(columns)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return missingOrConflict(r.db, &model.Installment{}, id)
+	}
+	return nil
+}
+
+// Delete apaga (soft delete) a parcela, desde que ela continue em aberto.
+func (r *installmentRepository) Delete(id int64, version int64) error {
+	result := whereVersion(r.db.Where("id = ? AND status = ?", id, model.InstallmentOpen), version).Delete(&model.Installment{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return missingOrConflict(r.db, &model.Installment{}, id)
+	}
+	return nil
+}
+
+func (r *installmentRepository) Settle(id int64, payment model.InstallmentPayment, events ...Recorder[model.Installment]) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return settleInstallment(tx, id, payment, events)
+	})
+}
+
+// settleInstallment baixa a parcela dentro de tx. Os meios de pagamento (PIX, boleto, extrato) a usam
+// para baixar a parcela na mesma transação em que registram o pagamento.
+func settleInstallment(tx *gorm.DB, id int64, payment model.InstallmentPayment, events []Recorder[model.Installment]) error {
+	result := tx.Model(&model.Installment{}).Where("id = ? AND status = ?", id, model.InstallmentOpen).Updates(map[string]any{
+		"status":            model.InstallmentPaid,
+		"paid_amount":       payment.Amount,
+		"paid_at":           payment.PaidAt,
+		"payment_method":    payment.Method,
+		"payment_reference": payment.Reference,
+		"version":           nextVersion(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if err := missingOrConflict(tx, &model.Installment{}, id); !errors.Is(err, ErrVersionConflict) {
+			return err
+		}
+		return ErrInstallmentNotOpen
+	}
+	return recordEvents(tx, events, func() (*model.Installment, error) {
+		var installment model.Installment
+		if err := tx.Where("id = ?", id).First(&installment).Error; err != nil {
+			return nil, err
+		}
+		return &installment, nil
+	})
+}
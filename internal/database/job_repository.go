@@ -0,0 +1,186 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrJobLockLost é retornado por Complete, Retry e Dead quando o job já não está reservado pelo
+// worker: a reserva venceu, RecoverStale devolveu o job à fila e outro worker pode tê-lo pego.
+var ErrJobLockLost = errors.New("job lock lost")
+
+// JobRepositoryInterface define os métodos da fila de jobs em segundo plano.
+type JobRepositoryInterface interface {
+	Enqueue(job *model.Job) (bool, error)
+	Claim(workerID string, now time.Time) (*model.Job, error)
+	Heartbeat(id int64, workerID string, now time.Time) error
+	Complete(id int64, workerID string, now time.Time) error
+	Retry(id int64, workerID string, runAt time.Time, lastError string) error
+	Dead(id int64, workerID string, now time.Time, lastError string) error
+	RecoverStale(lockedBefore time.Time) (int64, error)
+	DeleteFinished(before time.Time) (int64, error)
+	FindByID(id int64) (*model.Job, error)
+}
+
+// jobRepository é a implementação concreta que usa o GORM.
+type jobRepository struct {
+	db *gorm.DB
+}
+
+// NewJobRepository cria uma nova instância do repositório da fila de jobs.
+func NewJobRepository(db *gorm.DB) JobRepositoryInterface {
+	return &jobRepository{db: db}
+}
+
+// Enqueue grava o job como pending. Com UniqueKey preenchida, um job com a mesma chave já
+// enfileirado faz o INSERT ser ignorado e Enqueue devolve false.
+func (r *jobRepository) Enqueue(job *model.Job) (bool, error) {
+	job.ID = util.NewSnowflake()
+	job.Status = model.JobStatusPending
+	job.Attempts = 0
+	if job.RunAt.IsZero() {
+		job.RunAt = time.Now()
+	}
+	if len(job.Payload) == 0 {
+		job.Payload = []byte("{}")
+	}
+
+	query := r.db
+	if job.UniqueKey != nil {
+		query = query.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "unique_key"}}, DoNothing: true})
+	}
+	result := query.Create(job)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Claim reserva o próximo job pronto para rodar (run_at <= now). O SKIP LOCKED faz cada worker
+// pular as linhas que outro worker está reservando, então um job nunca é entregue a dois workers.
+// Devolve nil quando não há job pronto.
+func (r *jobRepository) Claim(workerID string, now time.Time) (*model.Job, error) {
+	var claimed *model.Job
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var job model.Job
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND run_at <= ?", model.JobStatusPending, now).
+			Order("run_at").
+			Order("id").
+			Limit(1).
+			Find(&job)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		job.Status = model.JobStatusRunning
+		job.Attempts++
+		job.LockedBy = &workerID
+		job.LockedAt = &now
+		if err := tx.Model(&model.Job{}).Where("id = ?", job.ID).Updates(map[string]any{
+			"status":    job.Status,
+			"attempts":  job.Attempts,
+			"locked_by": workerID,
+			"locked_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		claimed = &job
+		return nil
+	})
+	return claimed, err
+}
+
+// Heartbeat renova a reserva de um job em execução, para que RecoverStale não o devolva à fila.
+func (r *jobRepository) Heartbeat(id int64, workerID string, now time.Time) error {
+	return r.db.Model(&model.Job{}).
+		Where("id = ? AND status = ? AND locked_by = ?", id, model.JobStatusRunning, workerID).
+		Update("locked_at", now).Error
+}
+
+// Complete marca o job como concluído e apaga o payload, que não é mais necessário.
+func (r *jobRepository) Complete(id int64, workerID string, now time.Time) error {
+	return r.finish(id, workerID, map[string]any{
+		"status":      model.JobStatusCompleted,
+		"payload":     nil,
+		"locked_by":   nil,
+		"locked_at":   nil,
+		"last_error":  nil,
+		"finished_at": now,
+	})
+}
+
+// Retry devolve o job para a fila, para rodar de novo em runAt.
+func (r *jobRepository) Retry(id int64, workerID string, runAt time.Time, lastError string) error {
+	return r.finish(id, workerID, map[string]any{
+		"status":     model.JobStatusPending,
+		"run_at":     runAt,
+		"locked_by":  nil,
+		"locked_at":  nil,
+		"last_error": lastError,
+	})
+}
+
+// Dead move o job para a dead-letter (status dead), de onde ele não é mais executado. O payload é
+// apagado e só o último erro fica para análise.
+func (r *jobRepository) Dead(id int64, workerID string, now time.Time, lastError string) error {
+	return r.finish(id, workerID, map[string]any{
+		"status":      model.JobStatusDead,
+		"payload":     nil,
+		"locked_by":   nil,
+		"locked_at":   nil,
+		"last_error":  lastError,
+		"finished_at": now,
+	})
+}
+
+// finish grava o resultado do job só se ele ainda está running e reservado por workerID; um worker
+// que perdeu a reserva não sobrescreve o resultado de quem pegou o job depois.
+func (r *jobRepository) finish(id int64, workerID string, columns map[string]any) error {
+	result := r.db.Model(&model.Job{}).
+		Where("id = ? AND status = ? AND locked_by = ?", id, model.JobStatusRunning, workerID).
+		Updates(columns)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrJobLockLost
+	}
+	return nil
+}
+
+// RecoverStale devolve à fila os jobs running cuja reserva não foi renovada desde lockedBefore
+// (o processo caiu no meio da execução). Os que já esgotaram as tentativas vão para dead.
+func (r *jobRepository) RecoverStale(lockedBefore time.Time) (int64, error) {
+	result := r.db.Model(&model.Job{}).
+		Where("status = ? AND locked_at < ?", model.JobStatusRunning, lockedBefore).
+		Updates(map[string]any{
+			"status":      gorm.Expr("CASE WHEN attempts >= max_attempts THEN ? ELSE ? END", model.JobStatusDead, model.JobStatusPending),
+			"finished_at": gorm.Expr("CASE WHEN attempts >= max_attempts THEN NOW() END"),
+			"payload":     gorm.Expr("CASE WHEN attempts >= max_attempts THEN NULL ELSE payload END"),
+			"locked_by":   nil,
+			"locked_at":   nil,
+			"last_error":  "worker stopped before finishing the job",
+		})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteFinished apaga os jobs completed e dead que terminaram antes de before.
+func (r *jobRepository) DeleteFinished(before time.Time) (int64, error) {
+	result := r.db.Where("status IN ? AND finished_at < ?", []string{model.JobStatusCompleted, model.JobStatusDead}, before).Delete(&model.Job{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *jobRepository) FindByID(id int64) (*model.Job, error) {
+	var job model.Job
+	if err := r.db.Where("id = ?", id).First(&job).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
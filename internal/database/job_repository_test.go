@@ -0,0 +1,40 @@
+package database
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Um worker só grava o resultado do job enquanto ainda o detém: depois que RecoverStale devolveu o job
+// à fila e outro worker o pegou, Complete, Retry e Dead do worker antigo não alteram nada.
+func TestJobResultRequiresTheLock(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	finishers := map[string]func(JobRepositoryInterface) error{
+		"Complete": func(r JobRepositoryInterface) error { return r.Complete(42, "worker-a", now) },
+		"Retry":    func(r JobRepositoryInterface) error { return r.Retry(42, "worker-a", now.Add(time.Minute), "boom") },
+		"Dead":     func(r JobRepositoryInterface) error { return r.Dead(42, "worker-a", now, "boom") },
+	}
+	for name, finish := range finishers {
+		t.Run(name, func(t *testing.T) {
+			db, connector := newRecordingDB(t)
+			repo := NewJobRepository(db)
+
+			connector.rowsAffected = 1
+			if err := finish(repo); err != nil {
+				t.Fatalf("%s while holding the lock: %v", name, err)
+			}
+			exec := connector.lastExec()
+			if !strings.Contains(exec.query, "locked_by = $") || !slices.Contains(exec.args, any("worker-a")) {
+				t.Fatalf("%s does not filter on the worker: %s %v", name, exec.query, exec.args)
+			}
+
+			connector.rowsAffected = 0
+			if err := finish(repo); !errors.Is(err, ErrJobLockLost) {
+				t.Fatalf("%s after losing the lock = %v, want ErrJobLockLost", name, err)
+			}
+		})
+	}
+}
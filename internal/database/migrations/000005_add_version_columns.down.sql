@@ -0,0 +1,4 @@
+ALTER TABLE master.permissions DROP COLUMN IF EXISTS version;
+ALTER TABLE master.roles DROP COLUMN IF EXISTS version;
+ALTER TABLE master.users DROP COLUMN IF EXISTS version;
+ALTER TABLE master.company_globals DROP COLUMN IF EXISTS version;
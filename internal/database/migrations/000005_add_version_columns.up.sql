@@ -0,0 +1,4 @@
+ALTER TABLE master.company_globals ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE master.users ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE master.roles ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE master.permissions ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
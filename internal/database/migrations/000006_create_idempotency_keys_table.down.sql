@@ -0,0 +1 @@
+DROP TABLE IF EXISTS master.idempotency_keys;
\ No newline at end of file
@@ -0,0 +1,22 @@
+-- Respostas de POSTs enviados com o header Idempotency-Key, reaproveitadas nas retentativas.
+CREATE TABLE IF NOT EXISTS master.idempotency_keys (
+    -- Empresa do header X-Company-Global-Id (0 quando a requisição não informa o tenant).
+    tenant_id BIGINT NOT NULL DEFAULT 0,
+    idempotency_key VARCHAR(255) NOT NULL,
+    -- SHA-256 do método, caminho e corpo da primeira requisição.
+    request_hash CHAR(64) NOT NULL,
+    -- processing enquanto a primeira requisição executa; completed quando a resposta foi gravada.
+    status VARCHAR(20) NOT NULL,
+    response_status INTEGER,
+    response_headers JSONB,
+    response_body BYTEA,
+    -- Prazo da reserva: depois dele, uma requisição em processing é considerada abandonada.
+    locked_until TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT pk_idempotency_keys PRIMARY KEY (tenant_id, idempotency_key)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON master.idempotency_keys (expires_at);
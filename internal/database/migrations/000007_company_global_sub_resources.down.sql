@@ -0,0 +1,11 @@
+ALTER TABLE master.company_global_contacts DROP COLUMN IF EXISTS version;
+
+DROP INDEX IF EXISTS master.idx_company_global_addresses_company_id;
+DROP INDEX IF EXISTS master.uk_company_global_addresses_main;
+
+-- Mantém apenas o endereço principal de cada empresa antes de restaurar a restrição antiga.
+DELETE FROM master.company_global_addresses WHERE type <> 'MAIN';
+ALTER TABLE master.company_global_addresses
+    ADD CONSTRAINT uk_company_global_postal_code UNIQUE (company_id, postal_code);
+ALTER TABLE master.company_global_addresses DROP COLUMN IF EXISTS version;
+ALTER TABLE master.company_global_addresses DROP COLUMN IF EXISTS type;
@@ -0,0 +1,12 @@
+-- Endereços tipados: uma empresa pode ter vários endereços, mas só um principal (MAIN).
+ALTER TABLE master.company_global_addresses ADD COLUMN IF NOT EXISTS type VARCHAR(20) NOT NULL DEFAULT 'MAIN';
+ALTER TABLE master.company_global_addresses ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE master.company_global_addresses DROP CONSTRAINT IF EXISTS uk_company_global_postal_code;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uk_company_global_addresses_main
+    ON master.company_global_addresses (company_id) WHERE type = 'MAIN';
+CREATE INDEX IF NOT EXISTS idx_company_global_addresses_company_id
+    ON master.company_global_addresses (company_id);
+
+-- Contatos passam a ser atualizados no lugar (IDs estáveis), com versão própria para o ETag.
+ALTER TABLE master.company_global_contacts ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
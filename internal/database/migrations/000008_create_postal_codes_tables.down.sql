@@ -0,0 +1,3 @@
+ALTER TABLE master.company_global_addresses DROP COLUMN IF EXISTS city_ibge_code;
+DROP TABLE IF EXISTS master.postal_codes;
+DROP TABLE IF EXISTS master.ibge_municipalities;
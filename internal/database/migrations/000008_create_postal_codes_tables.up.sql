@@ -0,0 +1,19 @@
+-- Base local de municípios do IBGE e de CEPs, carregada a partir de CSV por cmd/import-addresses.
+CREATE TABLE IF NOT EXISTS master.ibge_municipalities (
+    ibge_code INTEGER PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    uf CHAR(2) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ibge_municipalities_uf ON master.ibge_municipalities (uf);
+
+CREATE TABLE IF NOT EXISTS master.postal_codes (
+    postal_code CHAR(8) PRIMARY KEY,
+    street VARCHAR(255),
+    district VARCHAR(100),
+    city_ibge_code INTEGER NOT NULL,
+    CONSTRAINT fk_postal_codes_municipality FOREIGN KEY (city_ibge_code) REFERENCES master.ibge_municipalities (ibge_code)
+);
+
+-- Código IBGE da cidade, exigido nas notas fiscais. Nulo para endereços fora do Brasil.
+ALTER TABLE master.company_global_addresses ADD COLUMN IF NOT EXISTS city_ibge_code INTEGER;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS master.import_jobs;
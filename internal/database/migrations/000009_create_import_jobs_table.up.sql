@@ -0,0 +1,21 @@
+-- Importações em massa (POST /imports/{entity}), executadas em segundo plano.
+CREATE TABLE IF NOT EXISTS master.import_jobs (
+    id BIGINT PRIMARY KEY,
+    company_global_id BIGINT NOT NULL DEFAULT 0,
+    entity VARCHAR(30) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL,
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    processed_rows INTEGER NOT NULL DEFAULT 0,
+    succeeded_rows INTEGER NOT NULL DEFAULT 0,
+    failed_rows INTEGER NOT NULL DEFAULT 0,
+    errors JSONB NOT NULL DEFAULT '[]',
+    error_message TEXT,
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_jobs_created_at ON master.import_jobs (created_at);
@@ -0,0 +1,22 @@
+-- Fila de jobs em segundo plano. Os workers disputam as linhas com SELECT ... FOR UPDATE SKIP LOCKED.
+CREATE TABLE IF NOT EXISTS master.jobs (
+    id BIGINT PRIMARY KEY,
+    type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 5,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- unique_key evita que o mesmo job agendado (cron) seja enfileirado por mais de uma instância.
+    unique_key VARCHAR(255),
+    locked_by VARCHAR(100),
+    locked_at TIMESTAMPTZ,
+    last_error TEXT,
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uk_jobs_unique_key UNIQUE (unique_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_pending_run_at ON master.jobs (run_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_jobs_running_locked_at ON master.jobs (locked_at) WHERE status = 'running';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS master.snowflake_nodes;
@@ -0,0 +1,9 @@
+-- Leases dos node IDs do Snowflake (0 a 1023). Cada instância da API detém um node ID enquanto
+-- renovar o lease; um lease vencido pode ser tomado por outra instância.
+CREATE TABLE IF NOT EXISTS master.snowflake_nodes (
+    node_id SMALLINT PRIMARY KEY CHECK (node_id BETWEEN 0 AND 1023),
+    holder VARCHAR(255) NOT NULL,
+    leased_until TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,4 @@
+ALTER TABLE master.users DROP COLUMN IF EXISTS locked_until;
+ALTER TABLE master.users DROP COLUMN IF EXISTS last_failed_login_at;
+ALTER TABLE master.users DROP COLUMN IF EXISTS failed_login_attempts;
+DROP TABLE IF EXISTS master.rate_limit_buckets;
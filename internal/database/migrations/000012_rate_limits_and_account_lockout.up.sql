@@ -0,0 +1,17 @@
+-- Buckets do rate limit (token bucket) compartilhados entre as réplicas da API.
+-- tokens é o saldo do bucket em refilled_at; a recarga é calculada a cada requisição.
+CREATE TABLE IF NOT EXISTS master.rate_limit_buckets (
+    bucket_key VARCHAR(512) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    refilled_at TIMESTAMPTZ NOT NULL,
+    -- Depois de expires_at o bucket está cheio de novo e pode ser apagado.
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_expires_at ON master.rate_limit_buckets (expires_at);
+
+-- Bloqueio da conta após tentativas de senha erradas.
+ALTER TABLE master.users ADD COLUMN IF NOT EXISTS failed_login_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE master.users ADD COLUMN IF NOT EXISTS last_failed_login_at TIMESTAMPTZ;
+ALTER TABLE master.users ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;
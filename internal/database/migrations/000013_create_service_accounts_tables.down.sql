@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS master.api_keys;
+DROP TABLE IF EXISTS master.service_account_roles;
+DROP TABLE IF EXISTS master.service_accounts;
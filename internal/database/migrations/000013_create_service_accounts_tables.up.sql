@@ -0,0 +1,56 @@
+-- Contas de serviço: identidades de integrações (ERP, e-commerce) que chamam a API sem um usuário.
+CREATE TABLE IF NOT EXISTS master.service_accounts (
+    id BIGINT NOT NULL,
+    company_global_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT pk_service_accounts PRIMARY KEY (id),
+    CONSTRAINT fk_service_accounts_company_global_id
+        FOREIGN KEY (company_global_id)
+        REFERENCES master.company_globals(id)
+        ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uk_service_accounts_company_global_id_name
+    ON master.service_accounts (company_global_id, name) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS master.service_account_roles (
+    service_account_id BIGINT NOT NULL,
+    role_id BIGINT NOT NULL,
+    CONSTRAINT pk_service_account_roles PRIMARY KEY (service_account_id, role_id),
+    CONSTRAINT fk_service_account_roles_service_account
+        FOREIGN KEY (service_account_id)
+        REFERENCES master.service_accounts(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_service_account_roles_role
+        FOREIGN KEY (role_id)
+        REFERENCES master.roles(id)
+        ON DELETE CASCADE
+);
+
+-- Chaves de API das contas de serviço. Só o hash SHA-256 da chave é guardado; o prefixo identifica
+-- a chave nas buscas e nas listagens.
+CREATE TABLE IF NOT EXISTS master.api_keys (
+    id BIGINT NOT NULL,
+    service_account_id BIGINT NOT NULL,
+    prefix VARCHAR(32) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_api_keys PRIMARY KEY (id),
+    CONSTRAINT uk_api_keys_prefix UNIQUE (prefix),
+    CONSTRAINT fk_api_keys_service_account
+        FOREIGN KEY (service_account_id)
+        REFERENCES master.service_accounts(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_service_account_id ON master.api_keys (service_account_id);
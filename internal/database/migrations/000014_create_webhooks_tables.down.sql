@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS master.webhook_delivery_attempts;
+DROP TABLE IF EXISTS master.webhook_deliveries;
+DROP TABLE IF EXISTS master.webhook_endpoints;
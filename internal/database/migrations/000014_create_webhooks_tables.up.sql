@@ -0,0 +1,70 @@
+-- Endpoints de webhook cadastrados por empresa. event_types é a lista de eventos assinados ("*" assina todos).
+-- secret assina as entregas (HMAC-SHA256) e precisa ser guardado em claro para isso.
+CREATE TABLE IF NOT EXISTS master.webhook_endpoints (
+    id BIGINT NOT NULL,
+    company_global_id BIGINT NOT NULL,
+    url VARCHAR(2048) NOT NULL,
+    description TEXT,
+    event_types JSONB NOT NULL DEFAULT '[]',
+    secret VARCHAR(128) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT pk_webhook_endpoints PRIMARY KEY (id),
+    CONSTRAINT fk_webhook_endpoints_company_global_id
+        FOREIGN KEY (company_global_id)
+        REFERENCES master.company_globals(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_company_global_id
+    ON master.webhook_endpoints (company_global_id) WHERE deleted_at IS NULL;
+
+-- Entregas: um evento para um endpoint. Cada entrega é um job da fila (webhooks.deliver).
+CREATE TABLE IF NOT EXISTS master.webhook_deliveries (
+    id BIGINT NOT NULL,
+    endpoint_id BIGINT NOT NULL,
+    company_global_id BIGINT NOT NULL,
+    event_id BIGINT NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_status_code INTEGER,
+    last_error TEXT,
+    delivered_at TIMESTAMPTZ,
+    -- Entrega original quando esta é um replay manual.
+    replay_of BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_webhook_deliveries PRIMARY KEY (id),
+    CONSTRAINT fk_webhook_deliveries_endpoint
+        FOREIGN KEY (endpoint_id)
+        REFERENCES master.webhook_endpoints(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint_id_created_at
+    ON master.webhook_deliveries (endpoint_id, created_at DESC);
+
+-- Cada tentativa de entrega, com a resposta do receptor (truncada).
+CREATE TABLE IF NOT EXISTS master.webhook_delivery_attempts (
+    id BIGINT NOT NULL,
+    delivery_id BIGINT NOT NULL,
+    attempt INTEGER NOT NULL,
+    status_code INTEGER,
+    error TEXT,
+    response_body TEXT,
+    duration_ms BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_webhook_delivery_attempts PRIMARY KEY (id),
+    CONSTRAINT fk_webhook_delivery_attempts_delivery
+        FOREIGN KEY (delivery_id)
+        REFERENCES master.webhook_deliveries(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery_id
+    ON master.webhook_delivery_attempts (delivery_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS master.uq_webhook_deliveries_endpoint_event;
+DROP TABLE IF EXISTS master.outbox_events;
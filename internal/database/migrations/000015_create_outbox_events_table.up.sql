@@ -0,0 +1,37 @@
+-- Outbox dos eventos de domínio. Cada evento é gravado na mesma transação da escrita que o gerou e
+-- despachado depois do commit para os assinantes em processo.
+-- position (BIGSERIAL) ordena os eventos: como as escritas num mesmo registro se serializam pelo lock
+-- da linha, a ordem de position num agregado é a ordem de commit, o que não vale para IDs snowflake
+-- gerados em instâncias com relógios diferentes.
+CREATE TABLE IF NOT EXISTS master.outbox_events (
+    id BIGINT NOT NULL,
+    position BIGSERIAL NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    aggregate_type VARCHAR(100) NOT NULL,
+    aggregate_id BIGINT NOT NULL,
+    company_global_id BIGINT NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_by VARCHAR(100),
+    locked_until TIMESTAMPTZ,
+    last_error TEXT,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMPTZ,
+    CONSTRAINT pk_outbox_events PRIMARY KEY (id),
+    CONSTRAINT uq_outbox_events_position UNIQUE (position)
+);
+
+-- Busca do primeiro evento pendente de cada agregado.
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending_aggregate
+    ON master.outbox_events (aggregate_type, aggregate_id, position) WHERE status = 'pending';
+
+-- Limpeza dos eventos já processados.
+CREATE INDEX IF NOT EXISTS idx_outbox_events_processed_at
+    ON master.outbox_events (processed_at) WHERE processed_at IS NOT NULL;
+
+-- Um evento reentregue pelo outbox não gera uma segunda entrega de webhook para o mesmo endpoint.
+-- Replays manuais (replay_of preenchido) repetem o evento de propósito e ficam de fora.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_webhook_deliveries_endpoint_event
+    ON master.webhook_deliveries (endpoint_id, event_id) WHERE replay_of IS NULL;
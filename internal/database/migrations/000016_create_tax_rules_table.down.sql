@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS master.tax_rules;
+ALTER TABLE master.company_globals DROP COLUMN IF EXISTS tax_regime;
@@ -0,0 +1,44 @@
+-- Regime tributário da empresa emitente, usado na escolha das regras de impostos.
+ALTER TABLE master.company_globals ADD COLUMN IF NOT EXISTS tax_regime VARCHAR(20) NOT NULL DEFAULT 'normal';
+
+-- Regras de impostos mantidas por cada empresa. Os critérios nulos (origin_uf ... issuer_regime) casam
+-- com qualquer valor; entre as regras que casam com um item vale a mais específica.
+-- Alíquotas, reduções de base e MVA são percentuais (18.0000 é 18%).
+CREATE TABLE IF NOT EXISTS master.tax_rules (
+    id BIGINT NOT NULL,
+    company_global_id BIGINT NOT NULL,
+    description TEXT,
+    origin_uf CHAR(2),
+    destination_uf CHAR(2),
+    ncm VARCHAR(8),
+    cfop CHAR(4),
+    customer_regime VARCHAR(20),
+    issuer_regime VARCHAR(20),
+    icms_code VARCHAR(3) NOT NULL,
+    icms_rate NUMERIC(7,4) NOT NULL DEFAULT 0,
+    icms_base_reduction NUMERIC(7,4) NOT NULL DEFAULT 0,
+    simples_credit_rate NUMERIC(7,4) NOT NULL DEFAULT 0,
+    icms_st_mva NUMERIC(9,4) NOT NULL DEFAULT 0,
+    icms_st_rate NUMERIC(7,4) NOT NULL DEFAULT 0,
+    icms_st_base_reduction NUMERIC(7,4) NOT NULL DEFAULT 0,
+    ipi_code CHAR(2),
+    ipi_rate NUMERIC(7,4) NOT NULL DEFAULT 0,
+    pis_code CHAR(2) NOT NULL,
+    pis_rate NUMERIC(7,4) NOT NULL DEFAULT 0,
+    cofins_code CHAR(2) NOT NULL,
+    cofins_rate NUMERIC(7,4) NOT NULL DEFAULT 0,
+    exclude_icms_from_pis_cofins BOOLEAN NOT NULL DEFAULT FALSE,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT pk_tax_rules PRIMARY KEY (id),
+    CONSTRAINT fk_tax_rules_company_global_id
+        FOREIGN KEY (company_global_id)
+        REFERENCES master.company_globals(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_tax_rules_company_global_id
+    ON master.tax_rules (company_global_id) WHERE deleted_at IS NULL;
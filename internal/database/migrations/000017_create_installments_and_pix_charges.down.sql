@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS master.pix_charges;
+DROP TABLE IF EXISTS master.installments;
+ALTER TABLE master.company_globals DROP COLUMN IF EXISTS pix_key;
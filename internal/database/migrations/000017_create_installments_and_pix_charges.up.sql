@@ -0,0 +1,68 @@
+-- Chave PIX de recebimento da empresa, no formato do DICT.
+ALTER TABLE master.company_globals ADD COLUMN IF NOT EXISTS pix_key VARCHAR(77);
+
+-- Parcelas a receber de cada empresa. document_number identifica o documento de origem (pedido, nota)
+-- e installment_number a parcela dentro dele. Uma parcela paga guarda como e quando foi paga.
+CREATE TABLE IF NOT EXISTS master.installments (
+    id BIGINT NOT NULL,
+    company_global_id BIGINT NOT NULL,
+    document_number VARCHAR(60) NOT NULL,
+    installment_number INTEGER NOT NULL DEFAULT 1,
+    customer_name VARCHAR(255) NOT NULL,
+    customer_cgc VARCHAR(14),
+    description TEXT,
+    amount NUMERIC(15,2) NOT NULL,
+    due_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    paid_amount NUMERIC(15,2),
+    paid_at TIMESTAMPTZ,
+    payment_method VARCHAR(20),
+    -- Identificador do pagamento no meio usado (ex: o endToEndId do PIX).
+    payment_reference VARCHAR(100),
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT pk_installments PRIMARY KEY (id),
+    CONSTRAINT fk_installments_company_global_id
+        FOREIGN KEY (company_global_id)
+        REFERENCES master.company_globals(id)
+        ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_installments_document
+    ON master.installments (company_global_id, document_number, installment_number) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_installments_company_global_id_status_due_date
+    ON master.installments (company_global_id, status, due_date) WHERE deleted_at IS NULL;
+
+-- Cobranças PIX geradas para as parcelas. txid é único entre todas as empresas: é por ele que o
+-- webhook do PSP encontra a cobrança. payload é o BR Code ("copia e cola").
+CREATE TABLE IF NOT EXISTS master.pix_charges (
+    id BIGINT NOT NULL,
+    company_global_id BIGINT NOT NULL,
+    installment_id BIGINT NOT NULL,
+    txid VARCHAR(35) NOT NULL,
+    type VARCHAR(10) NOT NULL,
+    pix_key VARCHAR(77) NOT NULL,
+    amount NUMERIC(15,2) NOT NULL,
+    payload TEXT NOT NULL,
+    -- URL do payload no PSP, só nas cobranças dinâmicas.
+    location VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    end_to_end_id VARCHAR(32),
+    paid_amount NUMERIC(15,2),
+    paid_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_pix_charges PRIMARY KEY (id),
+    CONSTRAINT uq_pix_charges_txid UNIQUE (txid),
+    CONSTRAINT fk_pix_charges_installment
+        FOREIGN KEY (installment_id)
+        REFERENCES master.installments(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_pix_charges_installment_id
+    ON master.pix_charges (installment_id, created_at DESC);
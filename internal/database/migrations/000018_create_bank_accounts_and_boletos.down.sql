@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS master.boleto_return_lines;
+DROP TABLE IF EXISTS master.boleto_returns;
+DROP TABLE IF EXISTS master.boletos;
+DROP TABLE IF EXISTS master.boleto_remittances;
+DROP TABLE IF EXISTS master.bank_accounts;
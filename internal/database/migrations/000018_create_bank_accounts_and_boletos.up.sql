@@ -0,0 +1,130 @@
+-- Contas bancárias das empresas. wallet, wallet_variation e agreement são os dados de cobrança
+-- exigidos pelo banco para emitir boletos; next_our_number e remittance_sequence numeram os boletos e
+-- os arquivos de remessa da conta.
+CREATE TABLE IF NOT EXISTS master.bank_accounts (
+    id BIGINT NOT NULL,
+    company_global_id BIGINT NOT NULL,
+    bank_code VARCHAR(3) NOT NULL,
+    agency VARCHAR(5) NOT NULL,
+    agency_digit VARCHAR(1),
+    account_number VARCHAR(12) NOT NULL,
+    account_digit VARCHAR(1),
+    description VARCHAR(255),
+    wallet VARCHAR(3),
+    wallet_variation VARCHAR(3),
+    agreement VARCHAR(20),
+    next_our_number BIGINT NOT NULL DEFAULT 1,
+    remittance_sequence INTEGER NOT NULL DEFAULT 0,
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT pk_bank_accounts PRIMARY KEY (id),
+    CONSTRAINT fk_bank_accounts_company_global_id
+        FOREIGN KEY (company_global_id)
+        REFERENCES master.company_globals(id)
+        ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_bank_accounts_account
+    ON master.bank_accounts (company_global_id, bank_code, agency, account_number) WHERE deleted_at IS NULL;
+
+-- Arquivos de remessa gerados. O conteúdo fica guardado para ser baixado de novo.
+CREATE TABLE IF NOT EXISTS master.boleto_remittances (
+    id BIGINT NOT NULL,
+    company_global_id BIGINT NOT NULL,
+    bank_account_id BIGINT NOT NULL,
+    layout VARCHAR(10) NOT NULL,
+    sequence INTEGER NOT NULL,
+    file_name VARCHAR(100) NOT NULL,
+    content TEXT NOT NULL,
+    title_count INTEGER NOT NULL,
+    total_amount NUMERIC(15,2) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_boleto_remittances PRIMARY KEY (id),
+    CONSTRAINT uq_boleto_remittances_sequence UNIQUE (bank_account_id, sequence),
+    CONSTRAINT fk_boleto_remittances_bank_account
+        FOREIGN KEY (bank_account_id)
+        REFERENCES master.bank_accounts(id)
+        ON DELETE CASCADE
+);
+
+-- Boletos das parcelas. O nosso número é único na conta: é por ele que o retorno encontra o boleto.
+CREATE TABLE IF NOT EXISTS master.boletos (
+    id BIGINT NOT NULL,
+    company_global_id BIGINT NOT NULL,
+    bank_account_id BIGINT NOT NULL,
+    installment_id BIGINT NOT NULL,
+    our_number BIGINT NOT NULL,
+    amount NUMERIC(15,2) NOT NULL,
+    due_date DATE NOT NULL,
+    barcode VARCHAR(44) NOT NULL,
+    digitable_line VARCHAR(60) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    remittance_id BIGINT,
+    paid_amount NUMERIC(15,2),
+    paid_at TIMESTAMPTZ,
+    credited_at DATE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_boletos PRIMARY KEY (id),
+    CONSTRAINT uq_boletos_our_number UNIQUE (bank_account_id, our_number),
+    CONSTRAINT fk_boletos_bank_account
+        FOREIGN KEY (bank_account_id)
+        REFERENCES master.bank_accounts(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_boletos_installment
+        FOREIGN KEY (installment_id)
+        REFERENCES master.installments(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_boletos_remittance
+        FOREIGN KEY (remittance_id)
+        REFERENCES master.boleto_remittances(id)
+        ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_boletos_installment_id
+    ON master.boletos (installment_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_boletos_bank_account_id_pending
+    ON master.boletos (bank_account_id, created_at) WHERE status = 'pending';
+
+-- Arquivos de retorno processados e o resultado de cada movimento, que forma o relatório de
+-- conciliação (linhas sem boleto, com valor divergente ou ilegíveis).
+CREATE TABLE IF NOT EXISTS master.boleto_returns (
+    id BIGINT NOT NULL,
+    company_global_id BIGINT NOT NULL,
+    bank_account_id BIGINT NOT NULL,
+    layout VARCHAR(10) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_boleto_returns PRIMARY KEY (id),
+    CONSTRAINT fk_boleto_returns_bank_account
+        FOREIGN KEY (bank_account_id)
+        REFERENCES master.bank_accounts(id)
+        ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS master.boleto_return_lines (
+    id BIGINT NOT NULL,
+    return_id BIGINT NOT NULL,
+    line_number INTEGER NOT NULL,
+    our_number BIGINT,
+    document_number VARCHAR(25),
+    boleto_id BIGINT,
+    installment_id BIGINT,
+    occurrence VARCHAR(2) NOT NULL,
+    amount NUMERIC(15,2),
+    paid_amount NUMERIC(15,2),
+    credit_date DATE,
+    result VARCHAR(30) NOT NULL,
+    message VARCHAR(255),
+    CONSTRAINT pk_boleto_return_lines PRIMARY KEY (id),
+    CONSTRAINT fk_boleto_return_lines_return
+        FOREIGN KEY (return_id)
+        REFERENCES master.boleto_returns(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_boleto_return_lines_return_id
+    ON master.boleto_return_lines (return_id, line_number);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS master.bank_statement_lines;
+DROP TABLE IF EXISTS master.bank_statement_imports;
@@ -0,0 +1,61 @@
+-- Extratos bancários importados (OFX ou CSV). line_count conta os lançamentos novos;
+-- duplicate_count os que já tinham sido importados em outro extrato da mesma conta.
+CREATE TABLE IF NOT EXISTS master.bank_statement_imports (
+    id BIGINT NOT NULL,
+    company_global_id BIGINT NOT NULL,
+    bank_account_id BIGINT NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    line_count INTEGER NOT NULL,
+    duplicate_count INTEGER NOT NULL,
+    suggested_count INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_bank_statement_imports PRIMARY KEY (id),
+    CONSTRAINT fk_bank_statement_imports_bank_account
+        FOREIGN KEY (bank_account_id)
+        REFERENCES master.bank_accounts(id)
+        ON DELETE CASCADE
+);
+
+-- Lançamentos dos extratos. external_id (FITID do OFX ou hash do lançamento) é único na conta, para
+-- que reimportar um extrato ou importar períodos sobrepostos não duplique lançamentos.
+-- installment_id é a parcela sugerida (suggested) ou conciliada (confirmed).
+CREATE TABLE IF NOT EXISTS master.bank_statement_lines (
+    id BIGINT NOT NULL,
+    company_global_id BIGINT NOT NULL,
+    bank_account_id BIGINT NOT NULL,
+    import_id BIGINT NOT NULL,
+    external_id VARCHAR(255) NOT NULL,
+    posted_at DATE NOT NULL,
+    amount NUMERIC(15,2) NOT NULL,
+    description VARCHAR(255),
+    document_number VARCHAR(60),
+    status VARCHAR(20) NOT NULL,
+    installment_id BIGINT,
+    confidence INTEGER,
+    match_reasons JSONB,
+    reconciled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_bank_statement_lines PRIMARY KEY (id),
+    CONSTRAINT uq_bank_statement_lines_external_id UNIQUE (bank_account_id, external_id),
+    CONSTRAINT fk_bank_statement_lines_bank_account
+        FOREIGN KEY (bank_account_id)
+        REFERENCES master.bank_accounts(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_bank_statement_lines_import
+        FOREIGN KEY (import_id)
+        REFERENCES master.bank_statement_imports(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_bank_statement_lines_installment
+        FOREIGN KEY (installment_id)
+        REFERENCES master.installments(id)
+        ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_bank_statement_lines_account_posted
+    ON master.bank_statement_lines (bank_account_id, posted_at);
+
+-- Uma parcela só pode estar sugerida ou conciliada em um lançamento por vez.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_bank_statement_lines_installment
+    ON master.bank_statement_lines (installment_id) WHERE status IN ('suggested', 'confirmed');
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS master.commission_entries;
+DROP TABLE IF EXISTS master.commission_statements;
+DROP TABLE IF EXISTS master.commission_plan_assignments;
+DROP TABLE IF EXISTS master.commission_plans;
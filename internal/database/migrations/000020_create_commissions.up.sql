@@ -0,0 +1,118 @@
+-- Planos de comissão dos vendedores. kind é o critério do plano (category, discount ou volume) e rules
+-- guarda as faixas: [{"category"|"maxDiscount"|"minVolume", "rate"}]. Percentuais como 5.0000 para 5%.
+CREATE TABLE IF NOT EXISTS master.commission_plans (
+    id BIGINT NOT NULL,
+    company_global_id BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    default_rate NUMERIC(7,4) NOT NULL DEFAULT 0,
+    rules JSONB NOT NULL DEFAULT '[]',
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT pk_commission_plans PRIMARY KEY (id),
+    CONSTRAINT fk_commission_plans_company_global_id
+        FOREIGN KEY (company_global_id)
+        REFERENCES master.company_globals(id)
+        ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_commission_plans_name
+    ON master.commission_plans (company_global_id, name) WHERE deleted_at IS NULL;
+
+-- Vigência de um plano para um usuário ou para todos os usuários de uma role. A atribuição ao usuário
+-- prevalece sobre a da role.
+CREATE TABLE IF NOT EXISTS master.commission_plan_assignments (
+    id BIGINT NOT NULL,
+    company_global_id BIGINT NOT NULL,
+    plan_id BIGINT NOT NULL,
+    user_id BIGINT,
+    role_id BIGINT,
+    valid_from DATE NOT NULL,
+    valid_to DATE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_commission_plan_assignments PRIMARY KEY (id),
+    CONSTRAINT ck_commission_plan_assignments_target CHECK ((user_id IS NULL) <> (role_id IS NULL)),
+    CONSTRAINT ck_commission_plan_assignments_validity CHECK (valid_to IS NULL OR valid_to >= valid_from),
+    CONSTRAINT fk_commission_plan_assignments_plan
+        FOREIGN KEY (plan_id)
+        REFERENCES master.commission_plans(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_commission_plan_assignments_user
+        FOREIGN KEY (user_id)
+        REFERENCES master.users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_commission_plan_assignments_role
+        FOREIGN KEY (role_id)
+        REFERENCES master.roles(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_commission_plan_assignments_user_id
+    ON master.commission_plan_assignments (user_id) WHERE user_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_commission_plan_assignments_role_id
+    ON master.commission_plan_assignments (role_id) WHERE role_id IS NOT NULL;
+
+-- Extratos de comissão aprovados, um por vendedor e mês (period é o primeiro dia do mês). Depois da
+-- aprovação o mês fica fechado para novos lançamentos do vendedor.
+CREATE TABLE IF NOT EXISTS master.commission_statements (
+    id BIGINT NOT NULL,
+    company_global_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    period DATE NOT NULL,
+    entry_count INTEGER NOT NULL,
+    base_amount NUMERIC(15,2) NOT NULL,
+    total_amount NUMERIC(15,2) NOT NULL,
+    approved_at TIMESTAMPTZ NOT NULL,
+    approved_by VARCHAR(60),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_commission_statements PRIMARY KEY (id),
+    CONSTRAINT uq_commission_statements_period UNIQUE (user_id, period),
+    CONSTRAINT fk_commission_statements_user
+        FOREIGN KEY (user_id)
+        REFERENCES master.users(id)
+        ON DELETE CASCADE
+);
+
+-- Lançamentos de comissão: sale na venda faturada e reversal (valores negativos) no cancelamento ou na
+-- devolução. Uma venda (document_number) gera um único lançamento sale por empresa.
+CREATE TABLE IF NOT EXISTS master.commission_entries (
+    id BIGINT NOT NULL,
+    company_global_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    plan_id BIGINT,
+    kind VARCHAR(10) NOT NULL,
+    document_number VARCHAR(60) NOT NULL,
+    document_date DATE NOT NULL,
+    period DATE NOT NULL,
+    base_amount NUMERIC(15,2) NOT NULL,
+    amount NUMERIC(15,2) NOT NULL,
+    lines JSONB,
+    reversed_entry_id BIGINT,
+    reason VARCHAR(20),
+    statement_id BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_commission_entries PRIMARY KEY (id),
+    CONSTRAINT fk_commission_entries_user
+        FOREIGN KEY (user_id)
+        REFERENCES master.users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_commission_entries_plan
+        FOREIGN KEY (plan_id)
+        REFERENCES master.commission_plans(id)
+        ON DELETE SET NULL,
+    CONSTRAINT fk_commission_entries_reversed_entry
+        FOREIGN KEY (reversed_entry_id)
+        REFERENCES master.commission_entries(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_commission_entries_statement
+        FOREIGN KEY (statement_id)
+        REFERENCES master.commission_statements(id)
+        ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_commission_entries_sale_document
+    ON master.commission_entries (company_global_id, document_number) WHERE kind = 'sale';
+CREATE INDEX IF NOT EXISTS idx_commission_entries_user_period
+    ON master.commission_entries (user_id, period);
@@ -0,0 +1,4 @@
+UPDATE master.jobs SET payload = '{}' WHERE payload IS NULL;
+ALTER TABLE master.jobs ALTER COLUMN payload SET NOT NULL;
+
+ALTER TABLE master.import_jobs DROP COLUMN IF EXISTS rows;
@@ -0,0 +1,7 @@
+-- As linhas da planilha (que podem ter senhas em texto puro) saem do payload do job e ficam na
+-- importação só até ela terminar.
+ALTER TABLE master.import_jobs ADD COLUMN IF NOT EXISTS rows JSONB;
+
+-- Jobs terminados não guardam mais o payload.
+ALTER TABLE master.jobs ALTER COLUMN payload DROP NOT NULL;
+UPDATE master.jobs SET payload = NULL WHERE status IN ('completed', 'dead');
@@ -0,0 +1,6 @@
+ALTER TABLE master.idempotency_keys DROP COLUMN IF EXISTS lock_token;
+
+DELETE FROM master.idempotency_keys WHERE scope <> '';
+ALTER TABLE master.idempotency_keys DROP CONSTRAINT IF EXISTS pk_idempotency_keys;
+ALTER TABLE master.idempotency_keys DROP COLUMN IF EXISTS scope;
+ALTER TABLE master.idempotency_keys ADD CONSTRAINT pk_idempotency_keys PRIMARY KEY (tenant_id, idempotency_key);
@@ -0,0 +1,7 @@
+-- Requisições anônimas não escolhem mais o tenant da chave: ficam no escopo do IP do cliente.
+ALTER TABLE master.idempotency_keys ADD COLUMN IF NOT EXISTS scope VARCHAR(60) NOT NULL DEFAULT '';
+ALTER TABLE master.idempotency_keys DROP CONSTRAINT IF EXISTS pk_idempotency_keys;
+ALTER TABLE master.idempotency_keys ADD CONSTRAINT pk_idempotency_keys PRIMARY KEY (tenant_id, scope, idempotency_key);
+
+-- Token da reserva: só quem detém a chave renova o prazo, grava a resposta ou a libera.
+ALTER TABLE master.idempotency_keys ADD COLUMN IF NOT EXISTS lock_token VARCHAR(36) NOT NULL DEFAULT '';
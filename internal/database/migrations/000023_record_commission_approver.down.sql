@@ -0,0 +1,3 @@
+ALTER TABLE master.commission_statements ADD COLUMN IF NOT EXISTS approved_by VARCHAR(60);
+ALTER TABLE master.commission_statements DROP COLUMN IF EXISTS approved_by_id;
+ALTER TABLE master.commission_statements DROP COLUMN IF EXISTS approved_by_type;
@@ -0,0 +1,7 @@
+-- Quem aprovou o extrato passa a ser identificado pelo tipo e ID do principal, não pelo nome, que muda
+-- e não é único. Aprovações antigas ficam com o tipo legacy e ID 0.
+ALTER TABLE master.commission_statements ADD COLUMN IF NOT EXISTS approved_by_type VARCHAR(20) NOT NULL DEFAULT 'legacy';
+ALTER TABLE master.commission_statements ADD COLUMN IF NOT EXISTS approved_by_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE master.commission_statements ALTER COLUMN approved_by_type DROP DEFAULT;
+ALTER TABLE master.commission_statements ALTER COLUMN approved_by_id DROP DEFAULT;
+ALTER TABLE master.commission_statements DROP COLUMN IF EXISTS approved_by;
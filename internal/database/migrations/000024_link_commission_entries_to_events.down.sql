@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS master.uq_commission_entries_event;
+ALTER TABLE master.commission_entries DROP COLUMN IF EXISTS event_id;
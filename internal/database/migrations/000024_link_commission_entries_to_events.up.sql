@@ -0,0 +1,6 @@
+-- Lançamentos gerados pelos eventos de faturamento guardam o ID do evento no outbox: um evento entregue
+-- de novo não gera outro lançamento.
+ALTER TABLE master.commission_entries ADD COLUMN IF NOT EXISTS event_id BIGINT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_commission_entries_event
+    ON master.commission_entries (event_id) WHERE event_id IS NOT NULL;
@@ -0,0 +1,174 @@
+package database
+
+import (
+	"encoding/json"
+	"time"
+
+	"go-sales/internal/event"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Recorder monta o evento de domínio de uma escrita a partir do estado do registro lido na mesma
+// transação, depois da escrita (ou antes dela, numa exclusão). Os métodos de escrita dos repositórios
+// recebem recorders para gravar os eventos no outbox de forma atômica com a escrita.
+type Recorder[T any] func(state *T) event.Event
+
+// recordEvents grava no outbox, dentro de tx, os eventos montados pelos recorders. load só é chamado
+// quando há recorders, para não custar uma leitura a mais nas escritas sem eventos.
+func recordEvents[T any](tx *gorm.DB, recorders []Recorder[T], load func() (*T, error)) error {
+	if len(recorders) == 0 {
+		return nil
+	}
+	state, err := load()
+	if err != nil {
+		return err
+	}
+	for _, record := range recorders {
+		outboxEvent, err := newOutboxEvent(record(state))
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(outboxEvent).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockedLoad trava a linha id de T antes de chamar load. As exclusões gravam os eventos antes de apagar,
+// e com a linha travada o evento só ganha position depois dos eventos das escritas concorrentes no mesmo
+// registro, mantendo a ordem do agregado igual à ordem de commit.
+func lockedLoad[T any](tx *gorm.DB, id any, load func() (*T, error)) func() (*T, error) {
+	return func() (*T, error) {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", id).Take(new(T)).Error
+		if err != nil {
+			return nil, err
+		}
+		return load()
+	}
+}
+
+// loaded devolve um load para recordEvents com um estado já conhecido.
+func loaded[T any](state *T) func() (*T, error) {
+	return func() (*T, error) { return state, nil }
+}
+
+func newOutboxEvent(e event.Event) (*model.OutboxEvent, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	aggregateType, aggregateID := e.Aggregate()
+	now := time.Now()
+	return &model.OutboxEvent{
+		ID:              util.NewSnowflake(),
+		EventType:       e.EventType(),
+		AggregateType:   aggregateType,
+		AggregateID:     aggregateID,
+		CompanyGlobalID: e.Tenant(),
+		Payload:         payload,
+		Status:          model.OutboxStatusPending,
+		NextAttemptAt:   now,
+		OccurredAt:      now,
+	}, nil
+}
+
+// OutboxRepositoryInterface define os métodos usados pelo despachante do outbox.
+type OutboxRepositoryInterface interface {
+	Claim(workerID string, now time.Time, limit int, lockFor time.Duration) ([]model.OutboxEvent, error)
+	MarkProcessed(id int64, now time.Time) error
+	Retry(id int64, nextAttemptAt time.Time, lastError string) error
+	Dead(id int64, now time.Time, lastError string) error
+	DeleteProcessed(before time.Time) (int64, error)
+}
+
+// outboxRepository é a implementação concreta que usa o GORM.
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository cria uma nova instância do repositório do outbox.
+func NewOutboxRepository(db *gorm.DB) OutboxRepositoryInterface {
+	return &outboxRepository{db: db}
+}
+
+// Claim reserva até limit eventos prontos para despacho por lockFor. Só o primeiro evento pendente de
+// cada agregado é elegível: enquanto ele não for processado (ou não for para dead), os seguintes do
+// mesmo agregado esperam, o que mantém a ordem por agregado mesmo com várias instâncias. O SKIP LOCKED
+// impede que duas instâncias reservem o mesmo evento.
+func (r *outboxRepository) Claim(workerID string, now time.Time, limit int, lockFor time.Duration) ([]model.OutboxEvent, error) {
+	var events []model.OutboxEvent
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.OutboxStatusPending, now).
+			Where("(locked_until IS NULL OR locked_until < ?)", now).
+			Where(`NOT EXISTS (
+				SELECT 1 FROM outbox_events previous
+				WHERE previous.aggregate_type = outbox_events.aggregate_type
+				  AND previous.aggregate_id = outbox_events.aggregate_id
+				  AND previous.status = ?
+				  AND previous.position < outbox_events.position)`, model.OutboxStatusPending).
+			Order("position").
+			Limit(limit).
+			Find(&events).Error
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		ids := make([]int64, len(events))
+		lockedUntil := now.Add(lockFor)
+		for i := range events {
+			ids[i] = events[i].ID
+			events[i].Attempts++
+			events[i].LockedBy = &workerID
+			events[i].LockedUntil = &lockedUntil
+		}
+		return tx.Model(&model.OutboxEvent{}).Where("id IN ?", ids).Updates(map[string]any{
+			"attempts":     gorm.Expr("attempts + 1"),
+			"locked_by":    workerID,
+			"locked_until": lockedUntil,
+		}).Error
+	})
+	return events, err
+}
+
+func (r *outboxRepository) MarkProcessed(id int64, now time.Time) error {
+	return r.db.Model(&model.OutboxEvent{}).Where("id = ?", id).Updates(map[string]any{
+		"status":       model.OutboxStatusProcessed,
+		"locked_by":    nil,
+		"locked_until": nil,
+		"last_error":   nil,
+		"processed_at": now,
+	}).Error
+}
+
+// Retry devolve o evento para a fila, para ser despachado de novo em nextAttemptAt.
+func (r *outboxRepository) Retry(id int64, nextAttemptAt time.Time, lastError string) error {
+	return r.db.Model(&model.OutboxEvent{}).Where("id = ?", id).Updates(map[string]any{
+		"next_attempt_at": nextAttemptAt,
+		"locked_by":       nil,
+		"locked_until":    nil,
+		"last_error":      lastError,
+	}).Error
+}
+
+// Dead tira o evento da fila depois de esgotadas as tentativas.
+func (r *outboxRepository) Dead(id int64, now time.Time, lastError string) error {
+	return r.db.Model(&model.OutboxEvent{}).Where("id = ?", id).Updates(map[string]any{
+		"status":       model.OutboxStatusDead,
+		"locked_by":    nil,
+		"locked_until": nil,
+		"last_error":   lastError,
+		"processed_at": now,
+	}).Error
+}
+
+// DeleteProcessed apaga os eventos processados antes de before. Os eventos dead são mantidos para análise.
+func (r *outboxRepository) DeleteProcessed(before time.Time) (int64, error) {
+	result := r.db.Where("status = ? AND processed_at < ?", model.OutboxStatusProcessed, before).Delete(&model.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,267 @@
+package database
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-sales/internal/dto"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var cursorSecret []byte
+
+// SetCursorSecret define a chave HMAC usada para assinar os cursores de paginação.
+// Deve ser chamada na inicialização, antes de atender requisições.
+func SetCursorSecret(secret string) {
+	cursorSecret = []byte(secret)
+}
+
+const (
+	cursorNext = "n"
+	cursorPrev = "p"
+)
+
+// cursorPayload é o conteúdo (opaco para o cliente) de um cursor de paginação.
+// Values guarda os valores das colunas de ordenação da linha de referência, na mesma ordem de Sort.
+type cursorPayload struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+	Dir    string   `json:"d"`
+}
+
+var errInvalidCursor = &FilterError{Param: "cursor", Reason: "invalid cursor"}
+
+// findPage executa a listagem paginada comum a todos os repositórios: aplica os filtros da spec,
+// calcula o total quando pedido, aplica ?fields=/?expand= e busca a página no modo offset ou keyset (cursor).
+func findPage[T any](query *gorm.DB, spec FilterSpec, projection ProjectionSpec, filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions) ([]T, dto.PageInfo, error) {
+	info := dto.PageInfo{PageSize: page.PageSize}
+
+	query, err := spec.Apply(query, filters)
+	if err != nil {
+		return nil, info, err
+	}
+	keys, err := spec.parseSort(filters)
+	if err != nil {
+		return nil, info, err
+	}
+
+	// 1. Faz a contagem do total de itens que correspondem ao filtro, ANTES de aplicar limit/offset.
+	if page.WithCount {
+		var totalItems int64
+		if err := query.Count(&totalItems).Error; err != nil {
+			return nil, info, err
+		}
+		totalPages := 0
+		if page.PageSize > 0 {
+			totalPages = int(math.Ceil(float64(totalItems) / float64(page.PageSize)))
+		}
+		info.TotalItems = &totalItems
+		info.TotalPages = &totalPages
+	}
+
+	// A projeção entra depois da contagem; as colunas de ordenação são sempre lidas para montar o cursor.
+	sortColumns := make([]string, len(keys))
+	for i, key := range keys {
+		sortColumns[i] = key.Field.Column
+	}
+	query, err = projection.Project(query, opts, sortColumns...)
+	if err != nil {
+		return nil, info, err
+	}
+
+	if page.UseCursor {
+		items, next, prev, err := findKeyset[T](query, keys, page)
+		info.NextCursor = next
+		info.PrevCursor = prev
+		return items, info, err
+	}
+
+	// 2. Modo offset: calcula o offset e aplica limit/offset.
+	info.Page = page.Page
+	offset := (page.Page - 1) * page.PageSize
+	var items []T
+	if err := applyOrder(query, keys, false).Offset(offset).Limit(page.PageSize).Find(&items).Error; err != nil {
+		return nil, info, err
+	}
+	return items, info, nil
+}
+
+// findKeyset busca uma página a partir do cursor, usando as colunas de ordenação (com o id
+// como desempate) em vez de OFFSET. Busca limit+1 linhas para saber se existe outra página.
+func findKeyset[T any](query *gorm.DB, keys []sortKey, page dto.PageRequest) ([]T, string, string, error) {
+	for _, key := range keys {
+		if key.Field.Nullable {
+			return nil, "", "", &FilterError{Param: "sort", Reason: fmt.Sprintf("cannot use nullable field %q with cursor pagination", key.Name)}
+		}
+	}
+	signature := sortSignature(keys)
+
+	dir := cursorNext
+	if page.Cursor != "" {
+		payload, err := decodeCursor(page.Cursor)
+		if err != nil || payload.Sort != signature || len(payload.Values) != len(keys) {
+			return nil, "", "", errInvalidCursor
+		}
+		dir = payload.Dir
+		condition, args, err := keysetCondition(keys, payload.Values, dir == cursorPrev)
+		if err != nil {
+			return nil, "", "", errInvalidCursor
+		}
+		query = query.Where(condition, args...)
+	}
+
+	var items []T
+	if err := applyOrder(query, keys, dir == cursorPrev).Limit(page.PageSize + 1).Find(&items).Error; err != nil {
+		return nil, "", "", err
+	}
+	hasMore := len(items) > page.PageSize
+	if hasMore {
+		items = items[:page.PageSize]
+	}
+	if dir == cursorPrev {
+		slices.Reverse(items)
+	}
+	if len(items) == 0 {
+		return items, "", "", nil
+	}
+
+	nextCursor, prevCursor := "", ""
+	var err error
+	// Avançando: existe próxima se sobrou linha; existe anterior se viemos de um cursor.
+	// Voltando: o inverso, e sempre existe a página de onde viemos.
+	if (dir == cursorNext && hasMore) || dir == cursorPrev {
+		if nextCursor, err = rowCursor(query, keys, &items[len(items)-1], signature, cursorNext); err != nil {
+			return nil, "", "", err
+		}
+	}
+	if (dir == cursorPrev && hasMore) || (dir == cursorNext && page.Cursor != "") {
+		if prevCursor, err = rowCursor(query, keys, &items[0], signature, cursorPrev); err != nil {
+			return nil, "", "", err
+		}
+	}
+	return items, nextCursor, prevCursor, nil
+}
+
+// keysetCondition monta "(k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...", respeitando a direção
+// de cada chave. backward inverte as comparações para buscar a página anterior.
+func keysetCondition(keys []sortKey, rawValues []string, backward bool) (string, []any, error) {
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		v, err := key.Field.convert(rawValues[i])
+		if err != nil {
+			return "", nil, err
+		}
+		values[i] = v
+	}
+
+	ors := make([]string, 0, len(keys))
+	args := make([]any, 0, len(keys)*(len(keys)+1)/2)
+	for i, key := range keys {
+		ands := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, keys[j].Field.Column+" = ?")
+			args = append(args, values[j])
+		}
+		op := "<"
+		if key.Desc == backward {
+			op = ">"
+		}
+		ands = append(ands, key.Field.Column+" "+op+" ?")
+		args = append(args, values[i])
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	return "(" + strings.Join(ors, " OR ") + ")", args, nil
+}
+
+// rowCursor gera o cursor que aponta para a linha informada.
+func rowCursor[T any](db *gorm.DB, keys []sortKey, row *T, signature, dir string) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(row); err != nil {
+		return "", err
+	}
+	rv := reflect.ValueOf(row)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		field := stmt.Schema.LookUpField(key.Field.Column)
+		if field == nil {
+			return "", fmt.Errorf("column %s not found in %s", key.Field.Column, stmt.Schema.Name)
+		}
+		v, _ := field.ValueOf(db.Statement.Context, rv)
+		values[i] = formatCursorValue(v)
+	}
+	return encodeCursor(cursorPayload{Sort: signature, Values: values, Dir: dir})
+}
+
+func formatCursorValue(v any) string {
+	switch value := v.(type) {
+	case time.Time:
+		return value.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func sortSignature(keys []sortKey) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		if key.Desc {
+			parts[i] = "-" + key.Name
+		} else {
+			parts[i] = key.Name
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// encodeCursor serializa o payload e anexa a assinatura HMAC-SHA256: base64(payload).base64(mac).
+func encodeCursor(payload cursorPayload) (string, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw) + "." + base64.RawURLEncoding.EncodeToString(signCursor(raw)), nil
+}
+
+func decodeCursor(token string) (cursorPayload, error) {
+	var payload cursorPayload
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return payload, errors.New("malformed cursor")
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return payload, err
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return payload, err
+	}
+	if !hmac.Equal(mac, signCursor(raw)) {
+		return payload, errors.New("cursor signature mismatch")
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return payload, err
+	}
+	if payload.Dir != cursorNext && payload.Dir != cursorPrev {
+		return payload, errors.New("invalid cursor direction")
+	}
+	return payload, nil
+}
+
+func signCursor(raw []byte) []byte {
+	mac := hmac.New(sha256.New, cursorSecret)
+	mac.Write(raw)
+	return mac.Sum(nil)
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"go-sales/internal/dto"
 	"go-sales/internal/model"
 	"go-sales/pkg/util"
 
@@ -9,16 +10,44 @@ import (
 
 // PermissionRepositoryInterface define os métodos para interagir com os dados de permission.
 type PermissionRepositoryInterface interface {
-	FindByID(id int64) (*model.Permission, error)
+	FindByID(id int64, opts dto.ReadOptions) (*model.Permission, error)
 	FindByName(name string, companyGlobalID int64) (*model.Permission, error)
 	ExistsByName(name string, companyGlobalID int64) (bool, error)
 	Create(permission *model.Permission) error
-	Update(permission *model.Permission) error
-	Delete(id int64) error
-	FindAll(filters map[string][]string, page, pageSize int, companyGlobalID int64) ([]*model.Permission, int64, error)
+	Update(permission *model.Permission, version int64) error
+	Patch(id int64, version int64, columns map[string]any) error
+	Delete(id int64, version int64) error
+	FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]*model.Permission, dto.PageInfo, error)
+	Stream(filters map[string][]string, opts dto.ReadOptions, companyGlobalID int64, fn func(*model.Permission) error) error
 	FindByIDs(ids []int64, companyGlobalID *int64) ([]*model.Permission, error)
 }
 
+// permissionFilterSpec define os filtros e ordenações aceitos em GET /permissions.
+var permissionFilterSpec = FilterSpec{
+	Fields: map[string]FilterField{
+		"id":              {Column: "id", Type: FieldInt, Operators: IDOperators},
+		"name":            {Column: "name", Type: FieldString, Operators: TextOperators, Sortable: true},
+		"description":     {Column: "description", Type: FieldString, Operators: TextOperators},
+		"companyGlobalId": {Column: "company_global_id", Type: FieldInt, Operators: IDOperators},
+		"createdAt":       {Column: "created_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+		"updatedAt":       {Column: "updated_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+	},
+	DefaultSort: "name",
+}
+
+// permissionProjectionSpec define os campos aceitos em ?fields= nas leituras de permissions.
+var permissionProjectionSpec = ProjectionSpec{
+	Columns: map[string]string{
+		"id":              "id",
+		"companyGlobalID": "company_global_id",
+		"name":            "name",
+		"description":     "description",
+		"createdAt":       "created_at",
+		"version":         "version",
+		"updatedAt":       "updated_at",
+	},
+}
+
 // permissionRepository é a implementação concreta que usa o GORM.
 type permissionRepository struct {
 	db *gorm.DB
@@ -29,9 +58,13 @@ func NewPermissionRepository(db *gorm.DB) PermissionRepositoryInterface {
 	return &permissionRepository{db: db}
 }
 
-func (r *permissionRepository) FindByID(id int64) (*model.Permission, error) {
+func (r *permissionRepository) FindByID(id int64, opts dto.ReadOptions) (*model.Permission, error) {
 	var permission model.Permission
-	if err := r.db.Where("id = ?", id).First(&permission).Error; err != nil {
+	query, err := permissionProjectionSpec.Project(r.db, opts, "version")
+	if err != nil {
+		return nil, err
+	}
+	if err := query.Where("id = ?", id).First(&permission).Error; err != nil {
 		return nil, err
 	}
 	return &permission, nil
@@ -58,55 +91,52 @@ func (r *permissionRepository) Create(permission *model.Permission) error {
 	return r.db.Create(permission).Error
 }
 
-func (r *permissionRepository) Update(permission *model.Permission) error {
-	return r.db.Save(permission).Error
-}
-
-func (r *permissionRepository) Delete(id int64) error {
-	result := r.db.Unscoped().Where("id = ?", id).Delete(&model.Permission{})
+// Update grava a permissão inteira somente se a versão no banco ainda for version, incrementando-a.
+func (r *permissionRepository) Update(permission *model.Permission, version int64) error {
+	permission.Version = version + 1
+	result := whereVersion(r.db.Select("*"), version).Save(permission)
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+		return missingOrConflict(r.db, &model.Permission{}, permission.ID)
 	}
 	return nil
 }
 
-func (r *permissionRepository) FindAll(filters map[string][]string, page, pageSize int, companyGlobalID int64) ([]*model.Permission, int64, error) {
-	var permissions []*model.Permission
-	var totalItems int64
-	query := r.db.Model(&model.Permission{}).Where("company_global_id = ?", companyGlobalID)
-
-	allowedFilters := map[string]bool{
-		"name":            true,
-		"companyGlobalId": true,
+// Patch atualiza apenas as colunas informadas (PATCH), desde que a versão no banco ainda seja version.
+func (r *permissionRepository) Patch(id int64, version int64, columns map[string]any) error {
+	columns["version"] = nextVersion()
+	result := whereVersion(r.db.Model(&model.Permission{}).Where("id = ?", id), version).Updates(columns)
+	if result.Error != nil {
+		return result.Error
 	}
-
-	for key, value := range filters {
-		if allowed, ok := allowedFilters[key]; ok && allowed && len(value) > 0 {
-			if key == "name" {
-				query = query.Where("name ILIKE ?", "%"+value[0]+"%")
-			} else if key == "companyGlobalId" {
-				// companyGlobalId é a chave estrangeira, então usamos o nome da coluna correta.
-				query = query.Where("company_global_id = ?", value[0])
-
-			} else {
-				query = query.Where(key+" = ?", value[0])
-			}
-		}
+	if result.RowsAffected == 0 {
+		return missingOrConflict(r.db, &model.Permission{}, id)
 	}
+	return nil
+}
 
-	if err := query.Count(&totalItems).Error; err != nil {
-		return nil, 0, err
+func (r *permissionRepository) Delete(id int64, version int64) error {
+	result := whereVersion(r.db.Unscoped().Where("id = ?", id), version).Delete(&model.Permission{})
+	if result.Error != nil {
+		return result.Error
 	}
-
-	offset := (page - 1) * pageSize
-	if err := query.Offset(offset).Limit(pageSize).Find(&permissions).Error; err != nil {
-		return nil, 0, err
+	if result.RowsAffected == 0 {
+		return missingOrConflict(r.db, &model.Permission{}, id)
 	}
+	return nil
+}
+
+func (r *permissionRepository) FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]*model.Permission, dto.PageInfo, error) {
+	query := r.db.Model(&model.Permission{}).Where("company_global_id = ?", companyGlobalID)
+	return findPage[*model.Permission](query, permissionFilterSpec, permissionProjectionSpec, filters, page, opts)
+}
 
-	return permissions, totalItems, nil
+// Stream percorre todas as permissões que atendem aos filtros, sem paginação, para a exportação.
+func (r *permissionRepository) Stream(filters map[string][]string, opts dto.ReadOptions, companyGlobalID int64, fn func(*model.Permission) error) error {
+	query := r.db.Model(&model.Permission{}).Where("company_global_id = ?", companyGlobalID)
+	return streamAll(query, permissionFilterSpec, permissionProjectionSpec, filters, opts, fn)
 }
 
 func (r *permissionRepository) FindByIDs(ids []int64, companyGlobalID *int64) ([]*model.Permission, error) {
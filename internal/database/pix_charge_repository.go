@@ -0,0 +1,94 @@
+package database
+
+import (
+	"errors"
+
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+// ErrPixChargeNotActive é retornado por MarkPaid quando a cobrança já foi paga.
+var ErrPixChargeNotActive = errors.New("pix charge is not active")
+
+// PixChargeRepositoryInterface define os métodos para interagir com as cobranças PIX.
+type PixChargeRepositoryInterface interface {
+	Create(charge *model.PixCharge) error
+	FindByID(id int64) (*model.PixCharge, error)
+	FindByTxID(txID string) (*model.PixCharge, error)
+	FindByInstallment(installmentID int64) ([]model.PixCharge, error)
+	// MarkPaid registra o PIX recebido na cobrança e, com settle, baixa a parcela na mesma transação.
+	// settled é false quando a parcela já não estava em aberto: o pagamento fica registrado só na cobrança.
+	MarkPaid(id int64, payment model.InstallmentPayment, settle bool, events ...Recorder[model.Installment]) (settled bool, err error)
+}
+
+// pixChargeRepository é a implementação concreta que usa o GORM.
+type pixChargeRepository struct {
+	db *gorm.DB
+}
+
+// NewPixChargeRepository cria uma nova instância do repositório de cobranças PIX.
+func NewPixChargeRepository(db *gorm.DB) PixChargeRepositoryInterface {
+	return &pixChargeRepository{db: db}
+}
+
+func (r *pixChargeRepository) Create(charge *model.PixCharge) error {
+	charge.ID = util.NewSnowflake()
+	return r.db.Create(charge).Error
+}
+
+func (r *pixChargeRepository) FindByID(id int64) (*model.PixCharge, error) {
+	var charge model.PixCharge
+	if err := r.db.Where("id = ?", id).First(&charge).Error; err != nil {
+		return nil, err
+	}
+	return &charge, nil
+}
+
+func (r *pixChargeRepository) FindByTxID(txID string) (*model.PixCharge, error) {
+	var charge model.PixCharge
+	if err := r.db.Where("txid = ?", txID).First(&charge).Error; err != nil {
+		return nil, err
+	}
+	return &charge, nil
+}
+
+// FindByInstallment lista as cobranças da parcela, das mais recentes para as mais antigas.
+func (r *pixChargeRepository) FindByInstallment(installmentID int64) ([]model.PixCharge, error) {
+	var charges []model.PixCharge
+	err := r.db.Where("installment_id = ?", installmentID).Order("created_at DESC, id DESC").Find(&charges).Error
+	return charges, err
+}
+
+func (r *pixChargeRepository) MarkPaid(id int64, payment model.InstallmentPayment, settle bool, events ...Recorder[model.Installment]) (bool, error) {
+	settled := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var charge model.PixCharge
+		if err := tx.Select("id", "installment_id").Where("id = ?", id).First(&charge).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&model.PixCharge{}).Where("id = ? AND status = ?", id, model.PixChargeActive).Updates(map[string]any{
+			"status":        model.PixChargePaid,
+			"end_to_end_id": payment.Reference,
+			"paid_amount":   payment.Amount,
+			"paid_at":       payment.PaidAt,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrPixChargeNotActive
+		}
+		if !settle {
+			return nil
+		}
+		err := settleInstallment(tx, charge.InstallmentID, payment, events)
+		if errors.Is(err, ErrInstallmentNotOpen) {
+			return nil
+		}
+		settled = err == nil
+		return err
+	})
+	return settled, err
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"go-sales/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// importBatchSize é o tamanho dos lotes gravados na importação da base de CEPs.
+const importBatchSize = 1000
+
+// PostalCodeRepositoryInterface define os métodos de leitura e importação da base local de CEPs e
+// municípios do IBGE.
+type PostalCodeRepositoryInterface interface {
+	FindByPostalCode(postalCode string) (*model.PostalCode, error)
+	FindMunicipalitiesByUF(uf string) ([]model.IBGEMunicipality, error)
+	SaveMunicipalities(municipalities []model.IBGEMunicipality) error
+	SavePostalCodes(postalCodes []model.PostalCode) error
+}
+
+// postalCodeRepository é a implementação concreta que usa o GORM.
+type postalCodeRepository struct {
+	db *gorm.DB
+}
+
+// NewPostalCodeRepository cria uma nova instância do repositório da base de CEPs.
+func NewPostalCodeRepository(db *gorm.DB) PostalCodeRepositoryInterface {
+	return &postalCodeRepository{db: db}
+}
+
+func (r *postalCodeRepository) FindByPostalCode(postalCode string) (*model.PostalCode, error) {
+	var record model.PostalCode
+	if err := r.db.Preload("Municipality").Where("postal_code = ?", postalCode).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *postalCodeRepository) FindMunicipalitiesByUF(uf string) ([]model.IBGEMunicipality, error) {
+	var municipalities []model.IBGEMunicipality
+	if err := r.db.Where("uf = ?", uf).Order("name").Find(&municipalities).Error; err != nil {
+		return nil, err
+	}
+	return municipalities, nil
+}
+
+// SaveMunicipalities insere ou atualiza (pelo código IBGE) os municípios informados.
+func (r *postalCodeRepository) SaveMunicipalities(municipalities []model.IBGEMunicipality) error {
+	if len(municipalities) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "ibge_code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "uf"}),
+	}).CreateInBatches(municipalities, importBatchSize).Error
+}
+
+// SavePostalCodes insere ou atualiza (pelo CEP) os CEPs informados.
+func (r *postalCodeRepository) SavePostalCodes(postalCodes []model.PostalCode) error {
+	if len(postalCodes) == 0 {
+		return nil
+	}
+	return r.db.Omit("Municipality").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "postal_code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"street", "district", "city_ibge_code"}),
+	}).CreateInBatches(postalCodes, importBatchSize).Error
+}
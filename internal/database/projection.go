@@ -0,0 +1,110 @@
+package database
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"go-sales/internal/dto"
+
+	"gorm.io/gorm"
+)
+
+// ProjectionSpec descreve os campos que podem ser pedidos em ?fields= e as associações de ?expand=.
+// As chaves são os nomes usados na API (iguais às tags json dos DTOs).
+type ProjectionSpec struct {
+	// Columns mapeia o campo da API para a coluna no banco. Campos fora daqui não podem ser selecionados.
+	Columns map[string]string
+	// Expansions lista as associações que podem ser carregadas.
+	Expansions map[string]Expansion
+}
+
+// Expansion é uma associação que pode ser carregada com ?expand=.
+type Expansion struct {
+	// Association é o nome do campo no model, usado no Preload (ex: "Roles").
+	Association string
+	// Keys são as colunas locais que o GORM precisa para montar a associação (ex: a FK de um belongs-to).
+	Keys []string
+	// Spec descreve a entidade associada, permitindo expansões aninhadas (ex: roles.permissions).
+	Spec *ProjectionSpec
+}
+
+// Project aplica ?fields= e ?expand= na query: seleciona apenas as colunas pedidas (sempre com o id)
+// e faz o Preload somente das associações pedidas. extra são colunas que precisam ser lidas mesmo
+// sem terem sido pedidas, como as de ordenação usadas no cursor.
+func (s ProjectionSpec) Project(query *gorm.DB, opts dto.ReadOptions, extra ...string) (*gorm.DB, error) {
+	preloads, keys, err := s.preloads(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.Fields) > 0 {
+		columns := []string{"id"}
+		for _, name := range opts.Fields {
+			column, ok := s.Columns[name]
+			if !ok {
+				return nil, &FilterError{Param: "fields", Reason: fmt.Sprintf("unknown field %q", name)}
+			}
+			columns = appendUnique(columns, column)
+		}
+		for _, column := range append(keys, extra...) {
+			columns = appendUnique(columns, column)
+		}
+		query = query.Select(columns)
+	}
+
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+	return query, nil
+}
+
+// preloads valida ?expand= e devolve os caminhos para o Preload do GORM (ex: "Roles.Permissions"),
+// junto com as colunas locais exigidas pelas associações carregadas.
+func (s ProjectionSpec) preloads(opts dto.ReadOptions) ([]string, []string, error) {
+	var preloads, keys []string
+	if opts.ExpandAll {
+		for _, name := range sortedKeys(s.Expansions) {
+			expansion := s.Expansions[name]
+			preloads = appendUnique(preloads, expansion.Association)
+			keys = append(keys, expansion.Keys...)
+		}
+	}
+
+	for _, path := range opts.Expand {
+		spec := &s
+		associations := make([]string, 0, 2)
+		for i, name := range strings.Split(path, ".") {
+			if spec == nil {
+				return nil, nil, &FilterError{Param: "expand", Reason: fmt.Sprintf("cannot expand %q", path)}
+			}
+			expansion, ok := spec.Expansions[name]
+			if !ok {
+				return nil, nil, &FilterError{Param: "expand", Reason: fmt.Sprintf("cannot expand %q", path)}
+			}
+			if i == 0 {
+				keys = append(keys, expansion.Keys...)
+			}
+			associations = append(associations, expansion.Association)
+			spec = expansion.Spec
+		}
+		preloads = appendUnique(preloads, strings.Join(associations, "."))
+	}
+	return preloads, keys, nil
+}
+
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"time"
+
+	"go-sales/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// RateLimitRepositoryInterface define os métodos do token bucket do rate limit guardado no Postgres.
+type RateLimitRepositoryInterface interface {
+	Take(key string, capacity, ratePerSecond float64, ttl time.Duration) (*model.RateLimitBucket, error)
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// rateLimitRepository é a implementação concreta que usa o GORM.
+type rateLimitRepository struct {
+	db *gorm.DB
+}
+
+// NewRateLimitRepository cria uma nova instância do repositório de buckets do rate limit.
+func NewRateLimitRepository(db *gorm.DB) RateLimitRepositoryInterface {
+	return &rateLimitRepository{db: db}
+}
+
+// refillExpr é o saldo do bucket recarregado até agora, limitado à capacidade.
+// O relógio é o do banco, para que todas as réplicas usem a mesma referência.
+const refillExpr = `LEAST(CAST(@capacity AS DOUBLE PRECISION),
+	b.tokens + GREATEST(EXTRACT(EPOCH FROM NOW() - b.refilled_at), 0) * CAST(@rate AS DOUBLE PRECISION))`
+
+// Take recarrega o bucket e consome um token, se houver, em um único INSERT ... ON CONFLICT.
+// O bucket devolvido traz o saldo restante e se a requisição foi permitida.
+// Um bucket novo nasce cheio e já com o primeiro token consumido.
+func (r *rateLimitRepository) Take(key string, capacity, ratePerSecond float64, ttl time.Duration) (*model.RateLimitBucket, error) {
+	var bucket model.RateLimitBucket
+	err := r.db.Raw(`
+		INSERT INTO rate_limit_buckets AS b (bucket_key, tokens, allowed, refilled_at, expires_at)
+		VALUES (@key, CAST(@capacity AS DOUBLE PRECISION) - 1, TRUE, NOW(), NOW() + CAST(@ttl AS DOUBLE PRECISION) * INTERVAL '1 second')
+		ON CONFLICT (bucket_key) DO UPDATE SET
+			tokens = CASE WHEN `+refillExpr+` >= 1 THEN `+refillExpr+` - 1 ELSE `+refillExpr+` END,
+			allowed = `+refillExpr+` >= 1,
+			refilled_at = NOW(),
+			expires_at = NOW() + CAST(@ttl AS DOUBLE PRECISION) * INTERVAL '1 second'
+		RETURNING bucket_key, tokens, allowed, refilled_at, expires_at`,
+		map[string]any{
+			"key":      key,
+			"capacity": capacity,
+			"rate":     ratePerSecond,
+			"ttl":      ttl.Seconds(),
+		}).Scan(&bucket).Error
+	if err != nil {
+		return nil, err
+	}
+	return &bucket, nil
+}
+
+// DeleteExpired apaga os buckets que já voltaram a ficar cheios.
+func (r *rateLimitRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", now).Delete(&model.RateLimitBucket{})
+	return result.RowsAffected, result.Error
+}
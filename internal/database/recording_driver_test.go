@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordedExec é um comando que o repositório mandou ao banco.
+type recordedExec struct {
+	query string
+	args  []any
+}
+
+// recordingConnector é um driver database/sql sem banco: guarda cada Exec e responde com rowsAffected.
+// Serve para conferir o SQL que os repositórios geram sem um PostgreSQL de pé.
+type recordingConnector struct {
+	mu           sync.Mutex
+	execs        []recordedExec
+	rowsAffected int64
+}
+
+// newRecordingDB abre um *gorm.DB com o dialeto do PostgreSQL sobre um recordingConnector.
+func newRecordingDB(t *testing.T) (*gorm.DB, *recordingConnector) {
+	t.Helper()
+	connector := &recordingConnector{}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(connector)}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("opening recording database: %v", err)
+	}
+	return db, connector
+}
+
+func (c *recordingConnector) lastExec() recordedExec {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.execs) == 0 {
+		return recordedExec{}
+	}
+	return c.execs[len(c.execs)-1]
+}
+
+func (c *recordingConnector) Connect(context.Context) (driver.Conn, error) {
+	return recordingConn{c}, nil
+}
+func (c *recordingConnector) Driver() driver.Driver { return recordingDriver{c} }
+
+type recordingDriver struct{ connector *recordingConnector }
+
+func (d recordingDriver) Open(string) (driver.Conn, error) { return recordingConn(d), nil }
+
+type recordingConn struct{ connector *recordingConnector }
+
+func (c recordingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("recording driver: prepared statements are not supported")
+}
+func (c recordingConn) Close() error              { return nil }
+func (c recordingConn) Begin() (driver.Tx, error) { return recordingTx{}, nil }
+
+// CheckNamedValue aceita qualquer argumento, como o pgx faz com os tipos que conhece.
+func (c recordingConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (c recordingConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	values := make([]any, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	c.connector.mu.Lock()
+	defer c.connector.mu.Unlock()
+	c.connector.execs = append(c.connector.execs, recordedExec{query: query, args: values})
+	return driver.RowsAffected(c.connector.rowsAffected), nil
+}
+
+type recordingTx struct{}
+
+func (recordingTx) Commit() error   { return nil }
+func (recordingTx) Rollback() error { return nil }
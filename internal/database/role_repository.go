@@ -1,6 +1,7 @@
 package database
 
 import (
+	"go-sales/internal/dto"
 	"go-sales/internal/model"
 	"go-sales/pkg/util"
 
@@ -10,16 +11,53 @@ import (
 // RoleRepositoryInterface define os métodos para interagir com os dados de role.
 type RoleRepositoryInterface interface {
 	FindAllByIDs(roleIDs []int64) ([]*model.Role, error)
-	FindByID(id int64) (*model.Role, error)
+	FindByID(id int64, opts dto.ReadOptions) (*model.Role, error)
 	FindByName(name string, companyGlobalID int64) (*model.Role, error)
 	ExistsByName(name string, companyGlobalID int64) (bool, error)
-	Create(role *model.Role) error
-	Update(role *model.Role) error
-	Delete(id int64) error
-	FindAll(filters map[string][]string, page, pageSize int, companyGlobalID int64) ([]model.Role, int64, error)
+	Create(role *model.Role, events ...Recorder[model.Role]) error
+	Update(role *model.Role, version int64, events ...Recorder[model.Role]) error
+	Patch(id int64, version int64, columns map[string]any, permissions []*model.Permission, events ...Recorder[model.Role]) error
+	Delete(id int64, version int64, events ...Recorder[model.Role]) error
+	FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]model.Role, dto.PageInfo, error)
+	Stream(filters map[string][]string, opts dto.ReadOptions, companyGlobalID int64, fn func(*model.Role) error) error
 	AssociatePermissions(role *model.Role, permissions []*model.Permission) error
 }
 
+// roleFilterSpec define os filtros e ordenações aceitos em GET /roles.
+var roleFilterSpec = FilterSpec{
+	Fields: map[string]FilterField{
+		"id":              {Column: "id", Type: FieldInt, Operators: IDOperators},
+		"name":            {Column: "name", Type: FieldString, Operators: TextOperators, Sortable: true},
+		"description":     {Column: "description", Type: FieldString, Operators: TextOperators},
+		"companyGlobalId": {Column: "company_global_id", Type: FieldInt, Operators: IDOperators},
+		"canEdit":         {Column: "can_edit", Type: FieldBool, Operators: BoolOperators},
+		"canDelete":       {Column: "can_delete", Type: FieldBool, Operators: BoolOperators},
+		"isAdmin":         {Column: "is_admin", Type: FieldBool, Operators: BoolOperators, Sortable: true},
+		"createdAt":       {Column: "created_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+		"updatedAt":       {Column: "updated_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+	},
+	DefaultSort: "name",
+}
+
+// roleProjectionSpec define os campos de ?fields= e as associações de ?expand= nas leituras de roles.
+var roleProjectionSpec = ProjectionSpec{
+	Columns: map[string]string{
+		"id":              "id",
+		"name":            "name",
+		"description":     "description",
+		"companyGlobalId": "company_global_id",
+		"canEdit":         "can_edit",
+		"canDelete":       "can_delete",
+		"isAdmin":         "is_admin",
+		"createdAt":       "created_at",
+		"version":         "version",
+		"updatedAt":       "updated_at",
+	},
+	Expansions: map[string]Expansion{
+		"permissions": {Association: "Permissions", Spec: &permissionProjectionSpec},
+	},
+}
+
 // roleRepository é a implementação concreta que usa o GORM.
 type roleRepository struct {
 	db *gorm.DB
@@ -30,9 +68,13 @@ func NewRoleRepository(db *gorm.DB) RoleRepositoryInterface {
 	return &roleRepository{db: db}
 }
 
-func (r *roleRepository) FindByID(id int64) (*model.Role, error) {
+func (r *roleRepository) FindByID(id int64, opts dto.ReadOptions) (*model.Role, error) {
 	var role model.Role
-	if err := r.db.Preload("Permissions").Where("id = ?", id).First(&role).Error; err != nil {
+	query, err := roleProjectionSpec.Project(r.db, opts, "version")
+	if err != nil {
+		return nil, err
+	}
+	if err := query.Where("id = ?", id).First(&role).Error; err != nil {
 		return nil, err
 	}
 	return &role, nil
@@ -54,60 +96,87 @@ func (r *roleRepository) FindByName(name string, companyGlobalID int64) (*model.
 	return &role, nil
 }
 
-func (r *roleRepository) Create(role *model.Role) error {
+func (r *roleRepository) Create(role *model.Role, events ...Recorder[model.Role]) error {
 	role.ID = util.NewSnowflake()
-	return r.db.Create(role).Error
-}
-
-func (r *roleRepository) Update(role *model.Role) error {
-	return r.db.Save(role).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(role).Error; err != nil {
+			return err
+		}
+		return recordEvents(tx, events, loaded(role))
+	})
 }
 
-func (r *roleRepository) Delete(id int64) error {
-	result := r.db.Where("id = ?", id).Delete(&model.Role{})
-	if result.Error != nil {
-		return result.Error
+// reloadRole relê a role com as permissões dentro de tx, para os eventos levarem o estado gravado.
+func reloadRole(tx *gorm.DB, id int64) func() (*model.Role, error) {
+	return func() (*model.Role, error) {
+		return (&roleRepository{db: tx}).FindByID(id, dto.FullRead)
 	}
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
-	}
-	return nil
 }
 
-func (r *roleRepository) FindAll(filters map[string][]string, page, pageSize int, companyGlobalID int64) ([]model.Role, int64, error) {
-	var roles []model.Role
-	var totalItems int64
-	query := r.db.Preload("Permissions").Model(&model.Role{}).Where("company_global_id = ?", companyGlobalID)
-
-	allowedFilters := map[string]bool{
-		"name":            true,
-		"companyGlobalId": true,
-	}
-
-	for key, value := range filters {
-		if allowed, ok := allowedFilters[key]; ok && allowed && len(value) > 0 {
-			if key == "name" {
-				query = query.Where("name ILIKE ?", "%"+value[0]+"%")
-			} else if key == "companyGlobalId" {
-				// companyGlobalId é a chave estrangeira, então usamos o nome da coluna correta.
-				query = query.Where("company_global_id = ?", value[0])
+// Update grava a role inteira somente se a versão no banco ainda for version, incrementando-a.
+func (r *roleRepository) Update(role *model.Role, version int64, events ...Recorder[model.Role]) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		role.Version = version + 1
+		result := whereVersion(tx.Select("*"), version).Save(role)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return missingOrConflict(tx, &model.Role{}, role.ID)
+		}
+		return recordEvents(tx, events, reloadRole(tx, role.ID))
+	})
+}
 
-			} else {
-				query = query.Where(key+" = ?", value[0])
+// Patch atualiza apenas as colunas informadas (PATCH), desde que a versão no banco ainda seja version.
+// Se permissions não for nil, substitui as permissões da role.
+func (r *roleRepository) Patch(id int64, version int64, columns map[string]any, permissions []*model.Permission, events ...Recorder[model.Role]) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// A versão é incrementada mesmo quando só as permissões mudam.
+		columns["version"] = nextVersion()
+		result := whereVersion(tx.Model(&model.Role{}).Where("id = ?", id), version).Updates(columns)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return missingOrConflict(tx, &model.Role{}, id)
+		}
+		if permissions != nil {
+			if err := tx.Model(&model.Role{ID: id}).Association("Permissions").Replace(permissions); err != nil {
+				return err
 			}
 		}
-	}
+		return recordEvents(tx, events, reloadRole(tx, id))
+	})
+}
 
-	if err := query.Count(&totalItems).Error; err != nil {
-		return nil, 0, err
-	}
+// Delete apaga a role. Os eventos de events são montados com a role lida antes da exclusão.
+func (r *roleRepository) Delete(id int64, version int64, events ...Recorder[model.Role]) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Os eventos são gravados antes: se o delete falhar, a transação desfaz os dois.
+		if err := recordEvents(tx, events, lockedLoad(tx, id, reloadRole(tx, id))); err != nil {
+			return err
+		}
+		result := whereVersion(tx.Where("id = ?", id), version).Delete(&model.Role{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return missingOrConflict(tx, &model.Role{}, id)
+		}
+		return nil
+	})
+}
 
-	offset := (page - 1) * pageSize
-	if err := query.Preload("Permissions").Offset(offset).Limit(pageSize).Find(&roles).Error; err != nil {
-		return nil, 0, err
-	}
+func (r *roleRepository) FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]model.Role, dto.PageInfo, error) {
+	query := r.db.Model(&model.Role{}).Where("company_global_id = ?", companyGlobalID)
+	return findPage[model.Role](query, roleFilterSpec, roleProjectionSpec, filters, page, opts)
+}
 
-	return roles, totalItems, nil
+// Stream percorre todas as roles que atendem aos filtros, sem paginação, para a exportação.
+func (r *roleRepository) Stream(filters map[string][]string, opts dto.ReadOptions, companyGlobalID int64, fn func(*model.Role) error) error {
+	query := r.db.Model(&model.Role{}).Where("company_global_id = ?", companyGlobalID)
+	return streamAll(query, roleFilterSpec, roleProjectionSpec, filters, opts, fn)
 }
 
 // Busca todas as roles pelos IDs informados
@@ -0,0 +1,206 @@
+package database
+
+import (
+	"time"
+
+	"go-sales/internal/dto"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ServiceAccountRepositoryInterface define os métodos para interagir com as contas de serviço e
+// suas chaves de API.
+type ServiceAccountRepositoryInterface interface {
+	Create(account *model.ServiceAccount) error
+	FindByID(id int64, opts dto.ReadOptions, companyGlobalID int64) (*model.ServiceAccount, error)
+	FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]model.ServiceAccount, dto.PageInfo, error)
+	ExistsByName(name string, companyGlobalID int64) (bool, error)
+	Patch(id int64, version int64, companyGlobalID int64, columns map[string]any, roles []*model.Role) error
+	Delete(id int64, version int64, companyGlobalID int64, now time.Time) error
+
+	CreateKey(key *model.APIKey) error
+	RotateKey(old *model.APIKey, key *model.APIKey, oldExpiresAt time.Time) error
+	FindKeys(serviceAccountID int64) ([]model.APIKey, error)
+	FindKey(serviceAccountID, keyID int64) (*model.APIKey, error)
+	FindKeyByPrefix(prefix string) (*model.APIKey, error)
+	RevokeKey(serviceAccountID, keyID int64, now time.Time) error
+	TouchKey(id int64, now time.Time, interval time.Duration) error
+}
+
+// serviceAccountFilterSpec define os filtros e ordenações aceitos em GET /service-accounts.
+var serviceAccountFilterSpec = FilterSpec{
+	Fields: map[string]FilterField{
+		"id":        {Column: "id", Type: FieldInt, Operators: IDOperators},
+		"name":      {Column: "name", Type: FieldString, Operators: TextOperators, Sortable: true},
+		"enabled":   {Column: "enabled", Type: FieldBool, Operators: BoolOperators, Sortable: true},
+		"createdAt": {Column: "created_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+		"updatedAt": {Column: "updated_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+	},
+	DefaultSort: "name",
+}
+
+// serviceAccountProjectionSpec define os campos de ?fields= e as associações de ?expand= nas leituras
+// de contas de serviço.
+var serviceAccountProjectionSpec = ProjectionSpec{
+	Columns: map[string]string{
+		"id":              "id",
+		"name":            "name",
+		"description":     "description",
+		"enabled":         "enabled",
+		"companyGlobalId": "company_global_id",
+		"createdAt":       "created_at",
+		"version":         "version",
+		"updatedAt":       "updated_at",
+	},
+	Expansions: map[string]Expansion{
+		"roles": {Association: "Roles", Spec: &roleProjectionSpec},
+	},
+}
+
+// serviceAccountRepository é a implementação concreta que usa o GORM.
+type serviceAccountRepository struct {
+	db *gorm.DB
+}
+
+// NewServiceAccountRepository cria uma nova instância do repositório de contas de serviço.
+func NewServiceAccountRepository(db *gorm.DB) ServiceAccountRepositoryInterface {
+	return &serviceAccountRepository{db: db}
+}
+
+// Create salva a conta de serviço com as suas roles.
+func (r *serviceAccountRepository) Create(account *model.ServiceAccount) error {
+	account.ID = util.NewSnowflake()
+	return r.db.Create(account).Error
+}
+
+// FindByID busca a conta de serviço da empresa companyGlobalID; contas de outras empresas não são encontradas.
+func (r *serviceAccountRepository) FindByID(id int64, opts dto.ReadOptions, companyGlobalID int64) (*model.ServiceAccount, error) {
+	var account model.ServiceAccount
+	query, err := serviceAccountProjectionSpec.Project(r.db, opts, "version")
+	if err != nil {
+		return nil, err
+	}
+	if err := query.Where("id = ? AND company_global_id = ?", id, companyGlobalID).First(&account).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *serviceAccountRepository) FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]model.ServiceAccount, dto.PageInfo, error) {
+	query := r.db.Model(&model.ServiceAccount{}).Where("company_global_id = ?", companyGlobalID)
+	return findPage[model.ServiceAccount](query, serviceAccountFilterSpec, serviceAccountProjectionSpec, filters, page, opts)
+}
+
+func (r *serviceAccountRepository) ExistsByName(name string, companyGlobalID int64) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.ServiceAccount{}).
+		Where("name = ? AND company_global_id = ?", name, companyGlobalID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Patch atualiza apenas as colunas informadas (PATCH), desde que a versão no banco ainda seja version
+// e a conta seja da empresa companyGlobalID. Se roles não for nil, substitui as roles da conta.
+func (r *serviceAccountRepository) Patch(id int64, version int64, companyGlobalID int64, columns map[string]any, roles []*model.Role) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		columns["version"] = nextVersion()
+		result := whereVersion(tx.Model(&model.ServiceAccount{}).Where("id = ? AND company_global_id = ?", id, companyGlobalID), version).Updates(columns)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return missingOrConflict(tx.Where("company_global_id = ?", companyGlobalID), &model.ServiceAccount{}, id)
+		}
+		if roles != nil {
+			if err := tx.Model(&model.ServiceAccount{ID: id}).Association("Roles").Replace(roles); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Delete apaga (soft delete) a conta e revoga todas as suas chaves na mesma transação.
+func (r *serviceAccountRepository) Delete(id int64, version int64, companyGlobalID int64, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := whereVersion(tx.Where("id = ? AND company_global_id = ?", id, companyGlobalID), version).Delete(&model.ServiceAccount{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return missingOrConflict(tx.Where("company_global_id = ?", companyGlobalID), &model.ServiceAccount{}, id)
+		}
+		return tx.Model(&model.APIKey{}).
+			Where("service_account_id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", now).Error
+	})
+}
+
+// CreateKey salva uma nova chave de API.
+func (r *serviceAccountRepository) CreateKey(key *model.APIKey) error {
+	key.ID = util.NewSnowflake()
+	return r.db.Omit(clause.Associations).Create(key).Error
+}
+
+// RotateKey cria a chave nova e encurta a validade da antiga para oldExpiresAt, na mesma transação.
+// A antiga nunca passa a valer por mais tempo do que já valia.
+func (r *serviceAccountRepository) RotateKey(old *model.APIKey, key *model.APIKey, oldExpiresAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		key.ID = util.NewSnowflake()
+		if err := tx.Omit(clause.Associations).Create(key).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.APIKey{}).Where("id = ?", old.ID).
+			Update("expires_at", gorm.Expr("LEAST(COALESCE(expires_at, ?), ?)", oldExpiresAt, oldExpiresAt)).Error
+	})
+}
+
+// FindKeys lista as chaves da conta, das mais novas para as mais antigas.
+func (r *serviceAccountRepository) FindKeys(serviceAccountID int64) ([]model.APIKey, error) {
+	var keys []model.APIKey
+	err := r.db.Where("service_account_id = ?", serviceAccountID).Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+func (r *serviceAccountRepository) FindKey(serviceAccountID, keyID int64) (*model.APIKey, error) {
+	var key model.APIKey
+	if err := r.db.Where("id = ? AND service_account_id = ?", keyID, serviceAccountID).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindKeyByPrefix busca a chave pelo prefixo para a autenticação, já com a conta de serviço, as roles e
+// as permissões. Contas apagadas não são carregadas e ficam com ServiceAccount.ID zerado.
+func (r *serviceAccountRepository) FindKeyByPrefix(prefix string) (*model.APIKey, error) {
+	var key model.APIKey
+	if err := r.db.Preload("ServiceAccount.Roles.Permissions").Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// RevokeKey revoga a chave. Revogar uma chave já revogada não muda a data original.
+func (r *serviceAccountRepository) RevokeKey(serviceAccountID, keyID int64, now time.Time) error {
+	result := r.db.Model(&model.APIKey{}).
+		Where("id = ? AND service_account_id = ?", keyID, serviceAccountID).
+		Update("revoked_at", gorm.Expr("COALESCE(revoked_at, ?)", now))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// TouchKey registra o uso da chave. Para não gravar a cada requisição, last_used_at só é atualizado
+// quando o último registro tem mais de interval.
+func (r *serviceAccountRepository) TouchKey(id int64, now time.Time, interval time.Duration) error {
+	return r.db.Model(&model.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, now.Add(-interval)).
+		UpdateColumn("last_used_at", now).Error
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"time"
+
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+// SnowflakeNodeRepositoryInterface define os métodos dos leases de node ID do Snowflake.
+type SnowflakeNodeRepositoryInterface interface {
+	Acquire(holder string, ttl time.Duration, nodeID *int64) (*model.SnowflakeNode, error)
+	Renew(nodeID int64, holder string, ttl time.Duration) (*model.SnowflakeNode, error)
+	Release(nodeID int64, holder string) error
+}
+
+// snowflakeNodeRepository é a implementação concreta que usa o GORM.
+type snowflakeNodeRepository struct {
+	db *gorm.DB
+}
+
+// NewSnowflakeNodeRepository cria uma nova instância do repositório de leases de node ID.
+func NewSnowflakeNodeRepository(db *gorm.DB) SnowflakeNodeRepositoryInterface {
+	return &snowflakeNodeRepository{db: db}
+}
+
+// acquireNodeSQL toma um node ID livre: um que nunca foi usado ou cujo lease venceu. Se duas
+// instâncias escolherem o mesmo node ID, o ON CONFLICT ... WHERE deixa só uma vencer; a outra não
+// recebe linha e deve tentar de novo. Os horários vêm do relógio do banco, comum a todas as instâncias.
+const acquireNodeSQL = `
+INSERT INTO snowflake_nodes (node_id, holder, leased_until, created_at, updated_at)
+SELECT candidate.node_id, @holder, NOW() + @ttl * INTERVAL '1 second', NOW(), NOW()
+FROM generate_series(CAST(@first AS INTEGER), CAST(@last AS INTEGER)) AS candidate(node_id)
+WHERE NOT EXISTS (
+    SELECT 1 FROM snowflake_nodes s WHERE s.node_id = candidate.node_id AND s.leased_until > NOW()
+)
+ORDER BY random()
+LIMIT 1
+ON CONFLICT (node_id) DO UPDATE
+SET holder = EXCLUDED.holder, leased_until = EXCLUDED.leased_until, updated_at = NOW()
+WHERE snowflake_nodes.leased_until <= NOW()
+RETURNING node_id, holder, leased_until, created_at, updated_at`
+
+// Acquire toma o lease de um node ID livre por ttl. Com nodeID informado, tenta apenas esse node ID.
+// Devolve gorm.ErrRecordNotFound quando nenhum node ID está livre.
+func (r *snowflakeNodeRepository) Acquire(holder string, ttl time.Duration, nodeID *int64) (*model.SnowflakeNode, error) {
+	first, last := int64(0), int64(util.MaxSnowflakeNodeID)
+	if nodeID != nil {
+		first, last = *nodeID, *nodeID
+	}
+
+	var nodes []model.SnowflakeNode
+	err := r.db.Raw(acquireNodeSQL, map[string]any{
+		"holder": holder,
+		"ttl":    ttl.Seconds(),
+		"first":  first,
+		"last":   last,
+	}).Scan(&nodes).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &nodes[0], nil
+}
+
+// Renew estende o lease por mais ttl. Devolve gorm.ErrRecordNotFound quando o lease passou para
+// outra instância (ou foi apagado), caso em que o node ID não pode mais ser usado.
+func (r *snowflakeNodeRepository) Renew(nodeID int64, holder string, ttl time.Duration) (*model.SnowflakeNode, error) {
+	var nodes []model.SnowflakeNode
+	err := r.db.Raw(`
+UPDATE snowflake_nodes
+SET leased_until = NOW() + ? * INTERVAL '1 second', updated_at = NOW()
+WHERE node_id = ? AND holder = ?
+RETURNING node_id, holder, leased_until, created_at, updated_at`, ttl.Seconds(), nodeID, holder).Scan(&nodes).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &nodes[0], nil
+}
+
+// Release libera o node ID no desligamento, para que outra instância possa tomá-lo sem esperar o lease vencer.
+func (r *snowflakeNodeRepository) Release(nodeID int64, holder string) error {
+	return r.db.Where("node_id = ? AND holder = ?", nodeID, holder).Delete(&model.SnowflakeNode{}).Error
+}
@@ -0,0 +1,134 @@
+package database
+
+import (
+	"go-sales/internal/dto"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+// TaxRuleRepositoryInterface define os métodos para interagir com as regras de impostos.
+type TaxRuleRepositoryInterface interface {
+	Create(rule *model.TaxRule) error
+	FindByID(id int64, opts dto.ReadOptions) (*model.TaxRule, error)
+	FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]model.TaxRule, dto.PageInfo, error)
+	Patch(id int64, version int64, columns map[string]any) error
+	Delete(id int64, version int64) error
+	FindEnabledByCompany(companyGlobalID int64) ([]model.TaxRule, error)
+}
+
+// taxRuleFilterSpec define os filtros e ordenações aceitos em GET /tax-rules.
+var taxRuleFilterSpec = FilterSpec{
+	Fields: map[string]FilterField{
+		"id":             {Column: "id", Type: FieldInt, Operators: IDOperators},
+		"originUf":       {Column: "origin_uf", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"destinationUf":  {Column: "destination_uf", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"ncm":            {Column: "ncm", Type: FieldString, Operators: TextOperators, Sortable: true},
+		"cfop":           {Column: "cfop", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"customerRegime": {Column: "customer_regime", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"issuerRegime":   {Column: "issuer_regime", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"icmsCode":       {Column: "icms_code", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"enabled":        {Column: "enabled", Type: FieldBool, Operators: BoolOperators, Sortable: true},
+		"createdAt":      {Column: "created_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+		"updatedAt":      {Column: "updated_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+	},
+	DefaultSort: "createdAt",
+}
+
+// taxRuleProjectionSpec define os campos de ?fields= nas leituras de regras de impostos.
+var taxRuleProjectionSpec = ProjectionSpec{
+	Columns: map[string]string{
+		"id":                       "id",
+		"companyGlobalId":          "company_global_id",
+		"description":              "description",
+		"originUf":                 "origin_uf",
+		"destinationUf":            "destination_uf",
+		"ncm":                      "ncm",
+		"cfop":                     "cfop",
+		"customerRegime":           "customer_regime",
+		"issuerRegime":             "issuer_regime",
+		"icmsCode":                 "icms_code",
+		"icmsRate":                 "icms_rate",
+		"icmsBaseReduction":        "icms_base_reduction",
+		"simplesCreditRate":        "simples_credit_rate",
+		"icmsStMva":                "icms_st_mva",
+		"icmsStRate":               "icms_st_rate",
+		"icmsStBaseReduction":      "icms_st_base_reduction",
+		"ipiCode":                  "ipi_code",
+		"ipiRate":                  "ipi_rate",
+		"pisCode":                  "pis_code",
+		"pisRate":                  "pis_rate",
+		"cofinsCode":               "cofins_code",
+		"cofinsRate":               "cofins_rate",
+		"excludeIcmsFromPisCofins": "exclude_icms_from_pis_cofins",
+		"enabled":                  "enabled",
+		"createdAt":                "created_at",
+		"version":                  "version",
+		"updatedAt":                "updated_at",
+	},
+}
+
+// taxRuleRepository é a implementação concreta que usa o GORM.
+type taxRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewTaxRuleRepository cria uma nova instância do repositório de regras de impostos.
+func NewTaxRuleRepository(db *gorm.DB) TaxRuleRepositoryInterface {
+	return &taxRuleRepository{db: db}
+}
+
+func (r *taxRuleRepository) Create(rule *model.TaxRule) error {
+	rule.ID = util.NewSnowflake()
+	return r.db.Create(rule).Error
+}
+
+func (r *taxRuleRepository) FindByID(id int64, opts dto.ReadOptions) (*model.TaxRule, error) {
+	var rule model.TaxRule
+	query, err := taxRuleProjectionSpec.Project(r.db, opts, "version")
+	if err != nil {
+		return nil, err
+	}
+	if err := query.Where("id = ?", id).First(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *taxRuleRepository) FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]model.TaxRule, dto.PageInfo, error) {
+	query := r.db.Model(&model.TaxRule{}).Where("company_global_id = ?", companyGlobalID)
+	return findPage[model.TaxRule](query, taxRuleFilterSpec, taxRuleProjectionSpec, filters, page, opts)
+}
+
+// Patch atualiza apenas as colunas informadas (PATCH), desde que a versão no banco ainda seja version.
+func (r *taxRuleRepository) Patch(id int64, version int64, columns map[string]any) error {
+	columns["version"] = nextVersion()
+	result := whereVersion(r.db.Model(&model.TaxRule{}).Where("id = ?", id), version).Updates(columns)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return missingOrConflict(r.db, &model.TaxRule{}, id)
+	}
+	return nil
+}
+
+// Delete apaga (soft delete) a regra.
+func (r *taxRuleRepository) Delete(id int64, version int64) error {
+	result := whereVersion(r.db.Where("id = ?", id), version).Delete(&model.TaxRule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return missingOrConflict(r.db, &model.TaxRule{}, id)
+	}
+	return nil
+}
+
+// FindEnabledByCompany lista as regras habilitadas da empresa, que o cálculo de impostos escolhe por item.
+func (r *taxRuleRepository) FindEnabledByCompany(companyGlobalID int64) ([]model.TaxRule, error) {
+	var rules []model.TaxRule
+	err := r.db.Where("company_global_id = ? AND enabled", companyGlobalID).Order("id").Find(&rules).Error
+	return rules, err
+}
@@ -1,6 +1,10 @@
 package database
 
 import (
+	"strconv"
+	"time"
+
+	"go-sales/internal/dto"
 	"go-sales/internal/model"
 	"go-sales/pkg/util"
 
@@ -10,13 +14,68 @@ import (
 // UserRepositoryInterface define os métodos que nosso serviço pode usar para interagir com os dados do usuário.
 type UserRepositoryInterface interface {
 	FindByEmail(email string) (*model.User, error)
-	FindByID(id string) (*model.User, error)
-	Create(user *model.User) error
-	Update(user *model.User) error
-	Delete(id string) error
+	FindByID(id string, opts dto.ReadOptions) (*model.User, error)
+	Create(user *model.User, events ...Recorder[model.User]) error
+	Update(user *model.User, version int64, events ...Recorder[model.User]) error
+	Patch(id int64, version int64, columns map[string]any, roles []*model.Role, events ...Recorder[model.User]) error
+	Delete(id string, version int64, events ...Recorder[model.User]) error
 	AssociateRoles(user *model.User, roles []*model.Role) error
-	FindAll(filters map[string][]string, page, pageSize int) ([]model.User, int64, error)
+	FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]model.User, dto.PageInfo, error)
+	Stream(filters map[string][]string, opts dto.ReadOptions, companyGlobalID int64, fn func(*model.User) error) error
 	EmailExists(email string, company_global_id int64, useUnscoped bool) (bool, error)
+	FindByEmailAndCompany(email string, companyGlobalID int64) (*model.User, error)
+	RecordLoginFailure(id int64, now time.Time, maxAttempts int, baseLockout, maxLockout time.Duration) (*model.User, error)
+	ResetLoginFailures(id int64) error
+}
+
+// userFilterSpec define os filtros e ordenações aceitos em GET /users.
+var userFilterSpec = FilterSpec{
+	Fields: map[string]FilterField{
+		"id":              {Column: "id", Type: FieldInt, Operators: IDOperators},
+		"name":            {Column: "full_name", Type: FieldString, Operators: TextOperators, Sortable: true},
+		"email":           {Column: "email_address", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"phone":           {Column: "phone_number", Type: FieldString, Operators: ExactTextOperators},
+		"enabled":         {Column: "enabled", Type: FieldBool, Operators: BoolOperators, Sortable: true},
+		"actived":         {Column: "actived", Type: FieldBool, Operators: BoolOperators},
+		"emailVerified":   {Column: "email_verified", Type: FieldBool, Operators: BoolOperators},
+		"companyGlobalId": {Column: "company_global_id", Type: FieldInt, Operators: IDOperators},
+		"createdAt":       {Column: "created_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+		"updatedAt":       {Column: "updated_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+	},
+	DefaultSort: "name",
+}
+
+// userProjectionSpec define os campos de ?fields= e as associações de ?expand= nas leituras de users.
+// Campos sensíveis (senha, chaves de ativação e reset) nunca são expostos e por isso não aparecem aqui.
+var userProjectionSpec = ProjectionSpec{
+	Columns: map[string]string{
+		"id":              "id",
+		"name":            "full_name",
+		"email":           "email_address",
+		"emailRecovery":   "email_recovery",
+		"emailVerified":   "email_verified",
+		"emailVerifiedAt": "email_verified_at",
+		"phone":           "phone_number",
+		"phoneVerified":   "phone_verified",
+		"phoneVerifiedAt": "phone_verified_at",
+		"enabled":         "enabled",
+		"actived":         "actived",
+		"activatedAt":     "activated_at",
+		"resetRequested":  "reset_requested",
+		"resetAt":         "reset_at",
+		"companyGlobalId": "company_global_id",
+		"createdAt":       "created_at",
+		"version":         "version",
+		"updatedAt":       "updated_at",
+		"deletedAt":       "deleted_at",
+
+		"failedLoginAttempts": "failed_login_attempts",
+		"lockedUntil":         "locked_until",
+	},
+	Expansions: map[string]Expansion{
+		"companyGlobal": {Association: "CompanyGlobal", Keys: []string{"company_global_id"}, Spec: &companyGlobalProjectionSpec},
+		"roles":         {Association: "Roles", Spec: &roleProjectionSpec},
+	},
 }
 
 // userRepository é a implementação concreta que usa o GORM.
@@ -51,41 +110,100 @@ func (r *userRepository) EmailExists(email string, company_global_id int64, useU
 	return count > 0, nil
 }
 
-// Create salva um novo usuário no banco de dados.
-func (r *userRepository) Create(user *model.User) error {
+// Create salva um novo usuário no banco de dados, junto com os eventos de events.
+func (r *userRepository) Create(user *model.User, events ...Recorder[model.User]) error {
 	user.ID = util.NewSnowflake()
-	return r.db.Create(user).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return recordEvents(tx, events, loaded(user))
+	})
+}
+
+// reloadUser relê o usuário completo dentro de tx, para os eventos levarem o estado gravado.
+func reloadUser(tx *gorm.DB, id int64) func() (*model.User, error) {
+	return func() (*model.User, error) {
+		return (&userRepository{db: tx}).FindByID(strconv.FormatInt(id, 10), dto.FullRead)
+	}
+}
+
+// Update grava o usuário inteiro somente se a versão no banco ainda for version, incrementando-a.
+// Sem a condição de versão o Save seria last-writer-wins entre edições concorrentes.
+func (r *userRepository) Update(user *model.User, version int64, events ...Recorder[model.User]) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		user.Version = version + 1
+		result := whereVersion(tx.Select("*"), version).Save(user)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return missingOrConflict(tx, &model.User{}, user.ID)
+		}
+		return recordEvents(tx, events, reloadUser(tx, user.ID))
+	})
 }
 
-// Update user
-func (r *userRepository) Update(user *model.User) error {
-	return r.db.Save(user).Error
+// Patch atualiza apenas as colunas informadas (PATCH), desde que a versão no banco ainda seja version.
+// Se roles não for nil, substitui as roles do usuário.
+func (r *userRepository) Patch(id int64, version int64, columns map[string]any, roles []*model.Role, events ...Recorder[model.User]) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// A versão é incrementada mesmo quando só as roles mudam.
+		columns["version"] = nextVersion()
+		result := whereVersion(tx.Model(&model.User{}).Where("id = ?", id), version).Updates(columns)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return missingOrConflict(tx, &model.User{}, id)
+		}
+		if roles != nil {
+			if err := tx.Model(&model.User{ID: id}).Association("Roles").Replace(roles); err != nil {
+				return err
+			}
+		}
+		return recordEvents(tx, events, reloadUser(tx, id))
+	})
 }
 
-func (r *userRepository) FindByID(id string) (*model.User, error) {
+func (r *userRepository) FindByID(id string, opts dto.ReadOptions) (*model.User, error) {
 	var user model.User
-	if err := r.db.Preload("CompanyGlobal").Preload("Roles").Where("id = ?", id).First(&user).Error; err != nil {
+	query, err := userProjectionSpec.Project(r.db, opts, "version")
+	if err != nil {
+		return nil, err
+	}
+	if err := query.Where("id = ?", id).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) Delete(id string) error {
-	// Executa a operação de delete e armazena o resultado.
-	result := r.db.Where("id = ?", id).Delete(&model.User{})
+// Delete apaga o usuário. Os eventos de events são montados com o usuário lido antes da exclusão.
+func (r *userRepository) Delete(id string, version int64, events ...Recorder[model.User]) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Os eventos são gravados antes: se o delete falhar, a transação desfaz os dois.
+		if err := recordEvents(tx, events, lockedLoad(tx, id, func() (*model.User, error) {
+			return (&userRepository{db: tx}).FindByID(id, dto.FullRead)
+		})); err != nil {
+			return err
+		}
 
-	// Primeiro, verifica se houve um erro real na execução da query.
-	if result.Error != nil {
-		return result.Error
-	}
+		// Executa a operação de delete e armazena o resultado.
+		result := whereVersion(tx.Where("id = ?", id), version).Delete(&model.User{})
 
-	// Se não houve erro, verifica se alguma linha foi realmente afetada.
-	// Se RowsAffected for 0, significa que o registro não foi encontrado.
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
-	}
+		// Primeiro, verifica se houve um erro real na execução da query.
+		if result.Error != nil {
+			return result.Error
+		}
 
-	return nil
+		// Se não houve erro, verifica se alguma linha foi realmente afetada.
+		// Se RowsAffected for 0, o registro não existe ou a versão informada está desatualizada.
+		if result.RowsAffected == 0 {
+			return missingOrConflict(tx, &model.User{}, id)
+		}
+
+		return nil
+	})
 }
 
 func (r *userRepository) AssociateRoles(user *model.User, roles []*model.Role) error {
@@ -94,47 +212,73 @@ func (r *userRepository) AssociateRoles(user *model.User, roles []*model.Role) e
 	return r.db.Model(user).Association("Roles").Append(roles)
 }
 
-func (r *userRepository) FindAll(filters map[string][]string, page, pageSize int) ([]model.User, int64, error) {
-	var users []model.User
-	var totalItems int64
-	query := r.db.Model(&model.User{}).Preload("CompanyGlobal").Preload("Roles")
+func (r *userRepository) FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]model.User, dto.PageInfo, error) {
+	query := r.db.Model(&model.User{}).Where("company_global_id = ?", companyGlobalID)
 
-	allowedFilters := map[string]bool{
-		"name":            true,
-		"email":           true,
-		"enabled":         true,
-		"id":              true,
-		"companyGlobalId": true,
-	}
+	// Os filtros aceitos e as colunas correspondentes vêm da spec, evitando injeção de SQL.
+	// As associações só são carregadas quando pedidas em ?expand=.
+	return findPage[model.User](query, userFilterSpec, userProjectionSpec, filters, page, opts)
+}
 
-	for key, value := range filters {
-		// Verifica se o filtro é permitido e se tem um valor.
-		if allowed, ok := allowedFilters[key]; ok && allowed && len(value) > 0 {
-			// Para campos de texto, usamos 'LIKE' para buscas parciais.
-			// Para outros, usamos '=' para buscas exatas.
-			if key == "name" {
-				query = query.Where("name ILIKE ?", "%"+value[0]+"%") // ILIKE é case-insensitive (PostgreSQL)
-			} else if key == "companyGlobalId" {
-				// companyGlobalId é a chave estrangeira, então usamos o nome da coluna correta.
-				query = query.Where("company_global_id = ?", value[0])
-
-			} else {
-				query = query.Where(key+" = ?", value[0])
-			}
-		}
-	}
+// Stream percorre todos os usuários que atendem aos filtros, sem paginação, para a exportação.
+func (r *userRepository) Stream(filters map[string][]string, opts dto.ReadOptions, companyGlobalID int64, fn func(*model.User) error) error {
+	query := r.db.Model(&model.User{}).Where("company_global_id = ?", companyGlobalID)
+	return streamAll(query, userFilterSpec, userProjectionSpec, filters, opts, fn)
+}
 
-	// 1. Faz a contagem do total de itens que correspondem ao filtro, ANTES de aplicar limit/offset.
-	if err := query.Count(&totalItems).Error; err != nil {
-		return nil, 0, err
+// FindByEmailAndCompany busca o usuário de uma empresa pelo email, para conferir a senha.
+func (r *userRepository) FindByEmailAndCompany(email string, companyGlobalID int64) (*model.User, error) {
+	var user model.User
+	if err := r.db.Where("email_address = ? AND company_global_id = ?", email, companyGlobalID).First(&user).Error; err != nil {
+		return nil, err
 	}
+	return &user, nil
+}
 
-	// 2. Calcula o offset para a paginação.
-	offset := (page - 1) * pageSize
-
-	if err := query.Offset(offset).Limit(pageSize).Find(&users).Error; err != nil {
-		return nil, 0, err
+// RecordLoginFailure conta mais uma senha errada e, a partir de maxAttempts falhas seguidas, bloqueia
+// a conta por baseLockout * 2^(falhas - maxAttempts), limitado a maxLockout. Tudo acontece em um
+// único UPDATE, então tentativas simultâneas não se perdem. A versão não muda: os contadores não são
+// editados pela API e um ataque em andamento não deve fazer as edições do administrador falharem com 412.
+func (r *userRepository) RecordLoginFailure(id int64, now time.Time, maxAttempts int, baseLockout, maxLockout time.Duration) (*model.User, error) {
+	var user model.User
+	result := r.db.Raw(`
+		UPDATE users SET
+			failed_login_attempts = failed_login_attempts + 1,
+			last_failed_login_at = @now,
+			locked_until = CASE WHEN failed_login_attempts + 1 >= CAST(@max AS INTEGER)
+				THEN CAST(@now AS TIMESTAMPTZ) + LEAST(
+					CAST(@base AS DOUBLE PRECISION) * POWER(2, failed_login_attempts + 1 - CAST(@max AS INTEGER)),
+					CAST(@cap AS DOUBLE PRECISION)) * INTERVAL '1 second'
+				ELSE locked_until END
+		WHERE id = @id AND deleted_at IS NULL
+		RETURNING id, failed_login_attempts, last_failed_login_at, locked_until`,
+		map[string]any{
+			"id":   id,
+			"now":  now,
+			"max":  maxAttempts,
+			"base": baseLockout.Seconds(),
+			"cap":  maxLockout.Seconds(),
+		}).Scan(&user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
 	}
+	return &user, nil
+}
 
-	return users, totalItems, nil
+// ResetLoginFailures zera as falhas e desbloqueia a conta (senha correta ou desbloqueio manual).
+func (r *userRepository) ResetLoginFailures(id int64) error {
+	result := r.db.Model(&model.User{}).Where("id = ?", id).Updates(map[string]any{
+		"failed_login_attempts": 0,
+		"locked_until":          nil,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
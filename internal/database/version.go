@@ -0,0 +1,53 @@
+package database
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrVersionConflict é retornado quando a versão esperada (If-Match) não é mais a versão atual do registro,
+// ou seja, outra requisição alterou o registro depois que ele foi lido.
+var ErrVersionConflict = errors.New("version conflict")
+
+// AnyVersion indica que a escrita não depende de uma versão específica (If-Match: *).
+const AnyVersion int64 = 0
+
+// whereVersion restringe um UPDATE/DELETE à versão esperada. Com AnyVersion a query não é alterada.
+func whereVersion(query *gorm.DB, version int64) *gorm.DB {
+	if version == AnyVersion {
+		return query
+	}
+	return query.Where("version = ?", version)
+}
+
+// nextVersion incrementa a coluna version no próprio UPDATE, mantendo o incremento atômico.
+func nextVersion() any {
+	return gorm.Expr("version + 1")
+}
+
+// missingOrConflict explica um UPDATE/DELETE que não afetou nenhuma linha: se o registro ainda existe,
+// a versão mudou (ErrVersionConflict); senão, o registro não foi encontrado.
+func missingOrConflict(query *gorm.DB, model any, id any) error {
+	var count int64
+	if err := query.Model(model).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return ErrVersionConflict
+}
+
+// bumpVersion incrementa a versão de um registro pai quando um filho muda (ex: o contato de uma
+// company), para que o ETag do pai deixe de valer. Devolve gorm.ErrRecordNotFound se o pai não existir.
+func bumpVersion(tx *gorm.DB, model any, id any) error {
+	result := tx.Model(model).Where("id = ?", id).Update("version", nextVersion())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package database
+
+import (
+	"encoding/json"
+	"time"
+
+	"go-sales/internal/dto"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookRepositoryInterface define os métodos para interagir com os endpoints de webhook, as
+// entregas e as tentativas de entrega.
+type WebhookRepositoryInterface interface {
+	Create(endpoint *model.WebhookEndpoint) error
+	FindByID(id int64, opts dto.ReadOptions) (*model.WebhookEndpoint, error)
+	FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]model.WebhookEndpoint, dto.PageInfo, error)
+	Patch(id int64, version int64, columns map[string]any) error
+	Delete(id int64, version int64) error
+	FindSubscribed(companyGlobalID int64, eventType string) ([]model.WebhookEndpoint, error)
+
+	CreateDelivery(delivery *model.WebhookDelivery) (bool, error)
+	FindDelivery(endpointID, deliveryID int64) (*model.WebhookDelivery, error)
+	FindDeliveries(filters map[string][]string, page dto.PageRequest, endpointID int64) ([]model.WebhookDelivery, dto.PageInfo, error)
+	FindDeliveryToSend(id int64) (*model.WebhookDelivery, error)
+	RecordAttempt(attempt *model.WebhookDeliveryAttempt, status string, deliveredAt *time.Time) error
+}
+
+// webhookFilterSpec define os filtros e ordenações aceitos em GET /webhooks.
+var webhookFilterSpec = FilterSpec{
+	Fields: map[string]FilterField{
+		"id":        {Column: "id", Type: FieldInt, Operators: IDOperators},
+		"url":       {Column: "url", Type: FieldString, Operators: TextOperators, Sortable: true},
+		"enabled":   {Column: "enabled", Type: FieldBool, Operators: BoolOperators, Sortable: true},
+		"createdAt": {Column: "created_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+		"updatedAt": {Column: "updated_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+	},
+	DefaultSort: "createdAt",
+}
+
+// webhookProjectionSpec define os campos de ?fields= nas leituras de endpoints. O segredo não é
+// selecionável: só aparece na resposta da criação.
+var webhookProjectionSpec = ProjectionSpec{
+	Columns: map[string]string{
+		"id":              "id",
+		"url":             "url",
+		"description":     "description",
+		"eventTypes":      "event_types",
+		"enabled":         "enabled",
+		"companyGlobalId": "company_global_id",
+		"createdAt":       "created_at",
+		"version":         "version",
+		"updatedAt":       "updated_at",
+	},
+}
+
+// webhookDeliveryFilterSpec define os filtros e ordenações aceitos em GET /webhooks/:id/deliveries.
+var webhookDeliveryFilterSpec = FilterSpec{
+	Fields: map[string]FilterField{
+		"id":        {Column: "id", Type: FieldInt, Operators: IDOperators},
+		"eventId":   {Column: "event_id", Type: FieldInt, Operators: IDOperators},
+		"eventType": {Column: "event_type", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"status":    {Column: "status", Type: FieldString, Operators: ExactTextOperators, Sortable: true},
+		"createdAt": {Column: "created_at", Type: FieldTime, Operators: TimeOperators, Sortable: true},
+	},
+	DefaultSort: "-createdAt",
+}
+
+// webhookDeliveryProjectionSpec lista as colunas das entregas; o payload fica de fora da listagem.
+var webhookDeliveryProjectionSpec = ProjectionSpec{
+	Columns: map[string]string{
+		"id":             "id",
+		"endpointId":     "endpoint_id",
+		"eventId":        "event_id",
+		"eventType":      "event_type",
+		"status":         "status",
+		"attempts":       "attempts",
+		"lastStatusCode": "last_status_code",
+		"lastError":      "last_error",
+		"deliveredAt":    "delivered_at",
+		"replayOf":       "replay_of",
+		"createdAt":      "created_at",
+		"updatedAt":      "updated_at",
+	},
+}
+
+// webhookRepository é a implementação concreta que usa o GORM.
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository cria uma nova instância do repositório de webhooks.
+func NewWebhookRepository(db *gorm.DB) WebhookRepositoryInterface {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) Create(endpoint *model.WebhookEndpoint) error {
+	endpoint.ID = util.NewSnowflake()
+	return r.db.Create(endpoint).Error
+}
+
+func (r *webhookRepository) FindByID(id int64, opts dto.ReadOptions) (*model.WebhookEndpoint, error) {
+	var endpoint model.WebhookEndpoint
+	query, err := webhookProjectionSpec.Project(r.db, opts, "version")
+	if err != nil {
+		return nil, err
+	}
+	if err := query.Where("id = ?", id).First(&endpoint).Error; err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+func (r *webhookRepository) FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) ([]model.WebhookEndpoint, dto.PageInfo, error) {
+	query := r.db.Model(&model.WebhookEndpoint{}).Where("company_global_id = ?", companyGlobalID)
+	return findPage[model.WebhookEndpoint](query, webhookFilterSpec, webhookProjectionSpec, filters, page, opts)
+}
+
+// Patch atualiza apenas as colunas informadas (PATCH), desde que a versão no banco ainda seja version.
+func (r *webhookRepository) Patch(id int64, version int64, columns map[string]any) error {
+	columns["version"] = nextVersion()
+	result := whereVersion(r.db.Model(&model.WebhookEndpoint{}).Where("id = ?", id), version).Updates(columns)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return missingOrConflict(r.db, &model.WebhookEndpoint{}, id)
+	}
+	return nil
+}
+
+// Delete apaga (soft delete) o endpoint. As entregas pendentes dele deixam de ser enviadas.
+func (r *webhookRepository) Delete(id int64, version int64) error {
+	result := whereVersion(r.db.Where("id = ?", id), version).Delete(&model.WebhookEndpoint{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return missingOrConflict(r.db, &model.WebhookEndpoint{}, id)
+	}
+	return nil
+}
+
+// FindSubscribed lista os endpoints habilitados da empresa que assinam eventType, diretamente ou com "*".
+func (r *webhookRepository) FindSubscribed(companyGlobalID int64, eventType string) ([]model.WebhookEndpoint, error) {
+	eventJSON, err := json.Marshal([]string{eventType})
+	if err != nil {
+		return nil, err
+	}
+	var endpoints []model.WebhookEndpoint
+	err = r.db.
+		Where("company_global_id = ? AND enabled", companyGlobalID).
+		Where("(event_types @> CAST(? AS JSONB) OR event_types @> '[\"*\"]')", string(eventJSON)).
+		Find(&endpoints).Error
+	return endpoints, err
+}
+
+// CreateDelivery grava a entrega e informa se ela foi criada. Uma segunda entrega do mesmo evento para
+// o mesmo endpoint é ignorada, exceto os replays (ver uq_webhook_deliveries_endpoint_event).
+func (r *webhookRepository) CreateDelivery(delivery *model.WebhookDelivery) (bool, error) {
+	delivery.ID = util.NewSnowflake()
+	result := r.db.Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "endpoint_id"}, {Name: "event_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "replay_of IS NULL"}}},
+		DoNothing:   true,
+	}).Create(delivery)
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindDelivery busca a entrega do endpoint com o payload e o histórico de tentativas.
+func (r *webhookRepository) FindDelivery(endpointID, deliveryID int64) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	err := r.db.
+		Preload("AttemptLog", func(db *gorm.DB) *gorm.DB { return db.Order("attempt") }).
+		Where("id = ? AND endpoint_id = ?", deliveryID, endpointID).
+		First(&delivery).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *webhookRepository) FindDeliveries(filters map[string][]string, page dto.PageRequest, endpointID int64) ([]model.WebhookDelivery, dto.PageInfo, error) {
+	query := r.db.Model(&model.WebhookDelivery{}).Where("endpoint_id = ?", endpointID)
+	return findPage[model.WebhookDelivery](query, webhookDeliveryFilterSpec, webhookDeliveryProjectionSpec, filters, page, dto.FullRead)
+}
+
+// FindDeliveryToSend busca a entrega com o endpoint para o envio. Um endpoint apagado não é
+// carregado e fica com Endpoint.ID zerado.
+func (r *webhookRepository) FindDeliveryToSend(id int64) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	if err := r.db.Preload("Endpoint").Where("id = ?", id).First(&delivery).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// RecordAttempt grava a tentativa e atualiza o resumo da entrega (tentativas, última resposta e
+// status) na mesma transação.
+func (r *webhookRepository) RecordAttempt(attempt *model.WebhookDeliveryAttempt, status string, deliveredAt *time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		attempt.ID = util.NewSnowflake()
+		if err := tx.Create(attempt).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.WebhookDelivery{}).Where("id = ?", attempt.DeliveryID).Updates(map[string]any{
+			"attempts":         attempt.Attempt,
+			"last_status_code": attempt.StatusCode,
+			"last_error":       attempt.Error,
+			"status":           status,
+			"delivered_at":     deliveredAt,
+			"updated_at":       time.Now(),
+		}).Error
+	})
+}
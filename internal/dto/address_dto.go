@@ -0,0 +1,12 @@
+package dto
+
+// AddressLookupDTO é a resposta de GET /addresses/lookup?postalCode=.
+type AddressLookupDTO struct {
+	PostalCode   string  `json:"postalCode"`
+	Street       *string `json:"street,omitempty"`
+	District     *string `json:"district,omitempty"`
+	City         string  `json:"city"`
+	State        string  `json:"state"`
+	CityIBGECode int32   `json:"cityIbgeCode"`
+	Country      string  `json:"country"`
+}
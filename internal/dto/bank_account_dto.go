@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"go-sales/pkg/util"
+
+	"time"
+)
+
+// CreateBankAccountDTO é o corpo do POST /bank-accounts. wallet, walletVariation e agreement são os
+// dados de cobrança do banco, exigidos só para emitir boletos.
+type CreateBankAccountDTO struct {
+	CompanyGlobalID util.SnowflakeID `json:"companyGlobalId" binding:"required,snowflake"`
+	BankCode        string           `json:"bankCode" binding:"required,len=3,number"`
+	Agency          string           `json:"agency" binding:"required,max=5,number"`
+	AgencyDigit     *string          `json:"agencyDigit,omitempty" binding:"omitempty,len=1,alphanum"`
+	AccountNumber   string           `json:"accountNumber" binding:"required,max=12,number"`
+	AccountDigit    *string          `json:"accountDigit,omitempty" binding:"omitempty,len=1,alphanum"`
+	Description     *string          `json:"description,omitempty" binding:"omitempty,max=255"`
+	Wallet          *string          `json:"wallet,omitempty" binding:"omitempty,max=3,number"`
+	WalletVariation *string          `json:"walletVariation,omitempty" binding:"omitempty,max=3,number"`
+	Agreement       *string          `json:"agreement,omitempty" binding:"omitempty,max=20,number"`
+	NextOurNumber   *int64           `json:"nextOurNumber,omitempty" binding:"omitempty,min=1"`
+}
+
+// UpdateBankAccountDTO é o corpo do PATCH /bank-accounts/:id (JSON Merge Patch). Banco, agência e
+// número da conta não mudam.
+type UpdateBankAccountDTO struct {
+	AgencyDigit     *string `json:"agencyDigit,omitempty" binding:"omitempty,len=1,alphanum"`
+	AccountDigit    *string `json:"accountDigit,omitempty" binding:"omitempty,len=1,alphanum"`
+	Description     *string `json:"description,omitempty" binding:"omitempty,max=255"`
+	Wallet          *string `json:"wallet,omitempty" binding:"omitempty,max=3,number"`
+	WalletVariation *string `json:"walletVariation,omitempty" binding:"omitempty,max=3,number"`
+	Agreement       *string `json:"agreement,omitempty" binding:"omitempty,max=20,number"`
+	NextOurNumber   *int64  `json:"nextOurNumber,omitempty" binding:"notnull,omitempty,min=1"`
+
+	MergePatch `json:"-"`
+}
+
+func (d *UpdateBankAccountDTO) UnmarshalJSON(data []byte) error {
+	type alias UpdateBankAccountDTO
+	return decodeMergePatch(data, (*alias)(d), &d.MergePatch)
+}
+
+type BankAccountDTO struct {
+	ID                 util.SnowflakeID `json:"id"`
+	CompanyGlobalID    util.SnowflakeID `json:"companyGlobalId"`
+	BankCode           string           `json:"bankCode"`
+	Agency             string           `json:"agency"`
+	AgencyDigit        *string          `json:"agencyDigit,omitempty"`
+	AccountNumber      string           `json:"accountNumber"`
+	AccountDigit       *string          `json:"accountDigit,omitempty"`
+	Description        *string          `json:"description,omitempty"`
+	Wallet             *string          `json:"wallet,omitempty"`
+	WalletVariation    *string          `json:"walletVariation,omitempty"`
+	Agreement          *string          `json:"agreement,omitempty"`
+	NextOurNumber      int64            `json:"nextOurNumber"`
+	RemittanceSequence int              `json:"remittanceSequence"`
+	Version            int64            `json:"version"`
+	CreatedAt          time.Time        `json:"createdAt"`
+	UpdatedAt          time.Time        `json:"updatedAt"`
+}
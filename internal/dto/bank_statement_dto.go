@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"go-sales/pkg/decimal"
+	"go-sales/pkg/util"
+
+	"time"
+)
+
+// BankStatementLineDTO é um lançamento do extrato: unmatched, suggested (com a parcela sugerida, a
+// confiança de 0 a 100 e os critérios que a compõem), confirmed, rejected ou ignored (débitos).
+type BankStatementLineDTO struct {
+	ID              util.SnowflakeID  `json:"id"`
+	CompanyGlobalID util.SnowflakeID  `json:"companyGlobalId"`
+	BankAccountID   util.SnowflakeID  `json:"bankAccountId"`
+	ImportID        util.SnowflakeID  `json:"importId"`
+	ExternalID      string            `json:"externalId"`
+	PostedAt        string            `json:"postedAt"`
+	Amount          decimal.Decimal   `json:"amount"`
+	Description     *string           `json:"description,omitempty"`
+	DocumentNumber  *string           `json:"documentNumber,omitempty"`
+	Status          string            `json:"status"`
+	InstallmentID   *util.SnowflakeID `json:"installmentId,omitempty"`
+	Confidence      *int              `json:"confidence,omitempty"`
+	MatchReasons    []string          `json:"matchReasons,omitempty"`
+	ReconciledAt    *time.Time        `json:"reconciledAt,omitempty"`
+	CreatedAt       time.Time         `json:"createdAt"`
+	UpdatedAt       time.Time         `json:"updatedAt"`
+}
+
+// BankStatementImportDTO é o resultado da importação de um extrato, com os lançamentos novos.
+type BankStatementImportDTO struct {
+	ID              util.SnowflakeID       `json:"id"`
+	CompanyGlobalID util.SnowflakeID       `json:"companyGlobalId"`
+	BankAccountID   util.SnowflakeID       `json:"bankAccountId"`
+	Format          string                 `json:"format"`
+	FileName        string                 `json:"fileName"`
+	LineCount       int                    `json:"lineCount"`
+	DuplicateCount  int                    `json:"duplicateCount"`
+	SuggestedCount  int                    `json:"suggestedCount"`
+	Lines           []BankStatementLineDTO `json:"lines"`
+	CreatedAt       time.Time              `json:"createdAt"`
+}
+
+// LinkStatementLineDTO é o corpo do POST /bank-statement-lines/:id/link.
+type LinkStatementLineDTO struct {
+	InstallmentID util.SnowflakeID `json:"installmentId" binding:"required,snowflake"`
+}
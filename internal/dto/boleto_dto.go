@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"go-sales/pkg/decimal"
+	"go-sales/pkg/util"
+
+	"time"
+)
+
+// CreateBoletoDTO é o corpo do POST /installments/:id/boletos.
+type CreateBoletoDTO struct {
+	BankAccountID util.SnowflakeID `json:"bankAccountId" binding:"required,snowflake"`
+}
+
+type BoletoDTO struct {
+	ID              util.SnowflakeID `json:"id"`
+	CompanyGlobalID util.SnowflakeID `json:"companyGlobalId"`
+	BankAccountID   util.SnowflakeID `json:"bankAccountId"`
+	InstallmentID   util.SnowflakeID `json:"installmentId"`
+	OurNumber       int64            `json:"ourNumber"`
+	Amount          decimal.Decimal  `json:"amount"`
+	DueDate         string           `json:"dueDate"`
+	// Barcode são os 44 dígitos do código de barras (padrão Interleaved 2 of 5).
+	Barcode       string            `json:"barcode"`
+	DigitableLine string            `json:"digitableLine"`
+	Status        string            `json:"status"`
+	RemittanceID  *util.SnowflakeID `json:"remittanceId,omitempty"`
+	PaidAmount    *decimal.Decimal  `json:"paidAmount,omitempty"`
+	PaidAt        *time.Time        `json:"paidAt,omitempty"`
+	CreditedAt    *string           `json:"creditedAt,omitempty"`
+	CreatedAt     time.Time         `json:"createdAt"`
+	UpdatedAt     time.Time         `json:"updatedAt"`
+}
+
+// CreateBoletoRemittanceDTO é o corpo do POST /bank-accounts/:id/remittances.
+type CreateBoletoRemittanceDTO struct {
+	Layout string `json:"layout" binding:"required,oneof=cnab240 cnab400"`
+}
+
+// BoletoRemittanceDTO descreve um arquivo de remessa; o arquivo é baixado em
+// GET /boleto-remittances/:id/file.
+type BoletoRemittanceDTO struct {
+	ID              util.SnowflakeID `json:"id"`
+	CompanyGlobalID util.SnowflakeID `json:"companyGlobalId"`
+	BankAccountID   util.SnowflakeID `json:"bankAccountId"`
+	Layout          string           `json:"layout"`
+	Sequence        int              `json:"sequence"`
+	FileName        string           `json:"fileName"`
+	TitleCount      int              `json:"titleCount"`
+	TotalAmount     decimal.Decimal  `json:"totalAmount"`
+	CreatedAt       time.Time        `json:"createdAt"`
+}
+
+// BoletoReturnLineDTO é o resultado de um movimento do retorno: settled, registered, rejected,
+// written_off, already_processed, ignored, unmatched, amount_mismatch, installment_not_open ou invalid.
+type BoletoReturnLineDTO struct {
+	LineNumber     int               `json:"lineNumber"`
+	OurNumber      *int64            `json:"ourNumber,omitempty"`
+	DocumentNumber *string           `json:"documentNumber,omitempty"`
+	BoletoID       *util.SnowflakeID `json:"boletoId,omitempty"`
+	InstallmentID  *util.SnowflakeID `json:"installmentId,omitempty"`
+	Occurrence     string            `json:"occurrence"`
+	Amount         *decimal.Decimal  `json:"amount,omitempty"`
+	PaidAmount     *decimal.Decimal  `json:"paidAmount,omitempty"`
+	CreditDate     *string           `json:"creditDate,omitempty"`
+	Result         string            `json:"result"`
+	Message        *string           `json:"message,omitempty"`
+}
+
+// BoletoReturnDTO é o relatório de um arquivo de retorno: a contagem por resultado, todas as linhas e,
+// em unmatched, as linhas que precisam de conciliação manual.
+type BoletoReturnDTO struct {
+	ID              util.SnowflakeID      `json:"id"`
+	CompanyGlobalID util.SnowflakeID      `json:"companyGlobalId"`
+	BankAccountID   util.SnowflakeID      `json:"bankAccountId"`
+	Layout          string                `json:"layout"`
+	FileName        string                `json:"fileName"`
+	Summary         map[string]int        `json:"summary"`
+	Lines           []BoletoReturnLineDTO `json:"lines"`
+	Unmatched       []BoletoReturnLineDTO `json:"unmatched"`
+	CreatedAt       time.Time             `json:"createdAt"`
+}
@@ -0,0 +1,152 @@
+package dto
+
+import (
+	"go-sales/pkg/decimal"
+	"go-sales/pkg/util"
+
+	"time"
+)
+
+// CreateCommissionPlanDTO é o corpo do POST /commission-plans. kind é o critério das faixas em rules;
+// os percentuais são informados como 5 para 5% (number ou string).
+type CreateCommissionPlanDTO struct {
+	CompanyGlobalID util.SnowflakeID    `json:"companyGlobalId" binding:"required,snowflake"`
+	Name            string              `json:"name" binding:"required,max=100"`
+	Kind            string              `json:"kind" binding:"required,oneof=category discount volume"`
+	DefaultRate     decimal.Decimal     `json:"defaultRate"`
+	Rules           []CommissionRuleDTO `json:"rules" binding:"max=100,dive"`
+}
+
+// UpdateCommissionPlanDTO é o corpo do PATCH /commission-plans/:id (JSON Merge Patch). rules, por ser
+// um array, substitui a lista inteira.
+type UpdateCommissionPlanDTO struct {
+	Name        *string             `json:"name,omitempty" binding:"notnull,omitempty,max=100"`
+	Kind        *string             `json:"kind,omitempty" binding:"notnull,omitempty,oneof=category discount volume"`
+	DefaultRate *decimal.Decimal    `json:"defaultRate,omitempty" binding:"notnull"`
+	Rules       []CommissionRuleDTO `json:"rules,omitempty" binding:"notnull,omitnil,max=100,dive"`
+
+	MergePatch `json:"-"`
+}
+
+func (d *UpdateCommissionPlanDTO) UnmarshalJSON(data []byte) error {
+	type alias UpdateCommissionPlanDTO
+	return decodeMergePatch(data, (*alias)(d), &d.MergePatch)
+}
+
+// CommissionRuleDTO é uma faixa do plano, com o critério do tipo do plano: category, maxDiscount (em %)
+// ou minVolume.
+type CommissionRuleDTO struct {
+	Category    *string          `json:"category,omitempty" binding:"omitempty,max=100"`
+	MaxDiscount *decimal.Decimal `json:"maxDiscount,omitempty"`
+	MinVolume   *decimal.Decimal `json:"minVolume,omitempty"`
+	Rate        decimal.Decimal  `json:"rate"`
+}
+
+type CommissionPlanDTO struct {
+	ID              util.SnowflakeID    `json:"id"`
+	CompanyGlobalID util.SnowflakeID    `json:"companyGlobalId"`
+	Name            string              `json:"name"`
+	Kind            string              `json:"kind"`
+	DefaultRate     decimal.Decimal     `json:"defaultRate"`
+	Rules           []CommissionRuleDTO `json:"rules"`
+	Version         int64               `json:"version"`
+	CreatedAt       time.Time           `json:"createdAt"`
+	UpdatedAt       time.Time           `json:"updatedAt"`
+}
+
+// CreateCommissionAssignmentDTO é o corpo do POST /commission-plans/:id/assignments: o plano vale para
+// userId ou para os usuários de roleId (um dos dois) de validFrom até validTo (sem fim se omitida).
+type CreateCommissionAssignmentDTO struct {
+	UserID    *util.SnowflakeID `json:"userId,omitempty" binding:"omitempty,snowflake"`
+	RoleID    *util.SnowflakeID `json:"roleId,omitempty" binding:"omitempty,snowflake"`
+	ValidFrom string            `json:"validFrom" binding:"required,datetime=2006-01-02"`
+	ValidTo   *string           `json:"validTo,omitempty" binding:"omitempty,datetime=2006-01-02"`
+}
+
+type CommissionAssignmentDTO struct {
+	ID              util.SnowflakeID  `json:"id"`
+	CompanyGlobalID util.SnowflakeID  `json:"companyGlobalId"`
+	PlanID          util.SnowflakeID  `json:"planId"`
+	UserID          *util.SnowflakeID `json:"userId,omitempty"`
+	RoleID          *util.SnowflakeID `json:"roleId,omitempty"`
+	ValidFrom       string            `json:"validFrom"`
+	ValidTo         *string           `json:"validTo,omitempty"`
+	CreatedAt       time.Time         `json:"createdAt"`
+}
+
+// CreateCommissionEntryDTO é o corpo do POST /commission-entries: uma venda faturada pelo vendedor
+// userId. documentNumber identifica a venda (pedido ou nota) e só gera uma comissão por empresa.
+type CreateCommissionEntryDTO struct {
+	CompanyGlobalID util.SnowflakeID    `json:"companyGlobalId" binding:"required,snowflake"`
+	UserID          util.SnowflakeID    `json:"userId" binding:"required,snowflake"`
+	DocumentNumber  string              `json:"documentNumber" binding:"required,max=60"`
+	DocumentDate    string              `json:"documentDate" binding:"required,datetime=2006-01-02"`
+	Items           []CommissionItemDTO `json:"items" binding:"required,min=1,max=990,dive"`
+}
+
+// CommissionItemDTO é um item da venda. discount é o desconto do item (não unitário).
+type CommissionItemDTO struct {
+	Category  *string         `json:"category,omitempty" binding:"omitempty,max=100"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	UnitPrice decimal.Decimal `json:"unitPrice"`
+	Discount  decimal.Decimal `json:"discount"`
+}
+
+// ReverseCommissionEntryDTO é o corpo do POST /commission-entries/:id/reverse. reason é cancelled
+// (estorna o saldo da comissão) ou returned (estorna a parte de baseAmount; sem ele, o saldo). date é
+// a data do cancelamento ou da devolução; omitida, é hoje.
+type ReverseCommissionEntryDTO struct {
+	Reason     string           `json:"reason" binding:"required,oneof=cancelled returned"`
+	Date       *string          `json:"date,omitempty" binding:"omitempty,datetime=2006-01-02"`
+	BaseAmount *decimal.Decimal `json:"baseAmount,omitempty"`
+}
+
+// CommissionEntryDTO é um lançamento de comissão: sale, com o cálculo de cada item em lines, ou
+// reversal, com valores negativos. period (2006-01) é o mês do extrato em que ele entra.
+type CommissionEntryDTO struct {
+	ID              util.SnowflakeID    `json:"id"`
+	CompanyGlobalID util.SnowflakeID    `json:"companyGlobalId"`
+	UserID          util.SnowflakeID    `json:"userId"`
+	PlanID          *util.SnowflakeID   `json:"planId,omitempty"`
+	Kind            string              `json:"kind"`
+	DocumentNumber  string              `json:"documentNumber"`
+	DocumentDate    string              `json:"documentDate"`
+	Period          string              `json:"period"`
+	BaseAmount      decimal.Decimal     `json:"baseAmount"`
+	Amount          decimal.Decimal     `json:"amount"`
+	Lines           []CommissionLineDTO `json:"lines,omitempty"`
+	ReversedEntryID *util.SnowflakeID   `json:"reversedEntryId,omitempty"`
+	Reason          *string             `json:"reason,omitempty"`
+	StatementID     *util.SnowflakeID   `json:"statementId,omitempty"`
+	CreatedAt       time.Time           `json:"createdAt"`
+}
+
+// CommissionLineDTO é a comissão de um item: o valor, o desconto (e o seu percentual), a base, o
+// percentual aplicado e a comissão.
+type CommissionLineDTO struct {
+	Item         int             `json:"item"`
+	Category     string          `json:"category,omitempty"`
+	Value        decimal.Decimal `json:"value"`
+	Discount     decimal.Decimal `json:"discount"`
+	DiscountRate decimal.Decimal `json:"discountRate"`
+	Base         decimal.Decimal `json:"base"`
+	Rate         decimal.Decimal `json:"rate"`
+	Amount       decimal.Decimal `json:"amount"`
+}
+
+// CommissionStatementDTO é o extrato de um vendedor num mês: open enquanto não aprovado (com os totais
+// dos lançamentos até agora) ou approved. entries só vem no extrato de um vendedor.
+type CommissionStatementDTO struct {
+	ID              *util.SnowflakeID    `json:"id,omitempty"`
+	CompanyGlobalID util.SnowflakeID     `json:"companyGlobalId"`
+	UserID          util.SnowflakeID     `json:"userId"`
+	Period          string               `json:"period"`
+	Status          string               `json:"status"`
+	EntryCount      int                  `json:"entryCount"`
+	BaseAmount      decimal.Decimal      `json:"baseAmount"`
+	TotalAmount     decimal.Decimal      `json:"totalAmount"`
+	ApprovedAt      *time.Time           `json:"approvedAt,omitempty"`
+	ApprovedByType  *string              `json:"approvedByType,omitempty"`
+	ApprovedByID    *util.SnowflakeID    `json:"approvedById,omitempty"`
+	Entries         []CommissionEntryDTO `json:"entries,omitempty"`
+}
@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"go-sales/pkg/util"
+
 	"time"
 )
 
@@ -8,11 +10,18 @@ type CreateCompanyGlobalDTO struct {
 	Name        string  `json:"name" binding:"required,max=255"`
 	SocialName  string  `json:"socialName" binding:"required,max=255"`
 	Description *string `json:"description,omitempty" binding:"omitempty,max=4000"`
-	CGC         string  `json:"cgc" binding:"required,max=14"`
+	CGC         string  `json:"cgc" binding:"required,max=14,cgc"`
 	Enabled     bool    `json:"enabled"`
 	Email       *string `json:"email" binding:"required,max=150"`
+	// TaxRegime é o regime tributário ("normal" ou "simples_nacional"). Vazio é "normal".
+	TaxRegime string `json:"taxRegime,omitempty" binding:"omitempty,oneof=normal simples_nacional"`
+	// PixKey é a chave PIX de recebimento: CPF, CNPJ, e-mail, telefone (+55...) ou chave aleatória.
+	PixKey *string `json:"pixKey,omitempty" binding:"omitempty,max=77"`
 
-	Address  *CreateCompanyGlobalAddressDTO   `json:"address,omitempty" binding:"required"`
+	// Address é o endereço principal (MAIN). Os demais endereços são mantidos em /company-globals/:id/addresses.
+	Address *CreateCompanyGlobalAddressDTO `json:"address,omitempty" binding:"required"`
+	// Contacts, no PUT, é comparado pelo id com os contatos atuais: os com id são atualizados, os sem id
+	// são criados e os ausentes são removidos.
 	Contacts []*CreateCompanyGlobalContactDTO `json:"contacts,omitempty" binding:"required,min=1,dive"`
 }
 
@@ -26,45 +35,112 @@ type CreateCompanyGlobalAddressDTO struct {
 	Country          string  `json:"country" binding:"required,max=100"`
 }
 
+// SaveCompanyGlobalAddressDTO é o corpo de POST/PUT /company-globals/:id/addresses.
+type SaveCompanyGlobalAddressDTO struct {
+	Type             string  `json:"type" binding:"required,oneof=MAIN BILLING DELIVERY OTHER"`
+	Street           string  `json:"street" binding:"required,max=255"`
+	StreetNumber     *string `json:"streetNumber,omitempty" binding:"omitempty,max=50"`
+	StreetComplement *string `json:"streetComplement,omitempty" binding:"omitempty,max=255"`
+	City             string  `json:"city" binding:"required,max=100"`
+	State            string  `json:"state" binding:"required,max=100"`
+	PostalCode       string  `json:"postalCode" binding:"required,max=20"`
+	Country          string  `json:"country" binding:"required,max=100"`
+}
+
+// CreateCompanyGlobalContactDTO é um contato no payload da company e o corpo de POST/PUT
+// /company-globals/:id/contacts. ID só é considerado no PUT/PATCH da company, para manter o contato.
 type CreateCompanyGlobalContactDTO struct {
-	Name  string  `json:"name" binding:"required,max=255"`
-	Email *string `json:"email" binding:"required,max=150"`
-	Phone *string `json:"phone" binding:"required,max=20"`
-	CGC   *string `json:"cgc" binding:"required,max=40"`
+	ID    util.SnowflakeID `json:"id,omitempty" binding:"omitempty,snowflake"`
+	Name  string           `json:"name" binding:"required,max=255"`
+	Email *string          `json:"email" binding:"required,max=150"`
+	Phone *string          `json:"phone" binding:"required,max=20"`
+	CGC   *string          `json:"cgc" binding:"required,max=40"`
+}
+
+// UpdateCompanyGlobalDTO é o corpo do PATCH /company-globals/:id (JSON Merge Patch).
+// address é mesclado no endereço principal; contacts, por ser um array, substitui a lista inteira,
+// mas os contatos com id são atualizados no lugar, mantendo o id.
+type UpdateCompanyGlobalDTO struct {
+	Name        *string `json:"name,omitempty" binding:"notnull,omitempty,max=255"`
+	SocialName  *string `json:"socialName,omitempty" binding:"notnull,omitempty,max=255"`
+	Description *string `json:"description,omitempty" binding:"omitempty,max=4000"`
+	CGC         *string `json:"cgc,omitempty" binding:"notnull,omitempty,max=14,cgc"`
+	Enabled     *bool   `json:"enabled,omitempty" binding:"notnull"`
+	Email       *string `json:"email,omitempty" binding:"notnull,omitempty,max=150"`
+	TaxRegime   *string `json:"taxRegime,omitempty" binding:"notnull,omitempty,oneof=normal simples_nacional"`
+	PixKey      *string `json:"pixKey,omitempty" binding:"omitempty,max=77"`
+
+	Address  *UpdateCompanyGlobalAddressDTO   `json:"address,omitempty" binding:"notnull"`
+	Contacts []*CreateCompanyGlobalContactDTO `json:"contacts,omitempty" binding:"notnull,omitnil,min=1,dive"`
+
+	MergePatch `json:"-"`
+}
+
+func (d *UpdateCompanyGlobalDTO) UnmarshalJSON(data []byte) error {
+	type alias UpdateCompanyGlobalDTO
+	return decodeMergePatch(data, (*alias)(d), &d.MergePatch)
+}
+
+// UpdateCompanyGlobalAddressDTO é a parte de endereço de UpdateCompanyGlobalDTO.
+type UpdateCompanyGlobalAddressDTO struct {
+	Street           *string `json:"street,omitempty" binding:"notnull,omitempty,max=255"`
+	StreetNumber     *string `json:"streetNumber,omitempty" binding:"omitempty,max=50"`
+	StreetComplement *string `json:"streetComplement,omitempty" binding:"omitempty,max=255"`
+	City             *string `json:"city,omitempty" binding:"notnull,omitempty,max=100"`
+	State            *string `json:"state,omitempty" binding:"notnull,omitempty,max=100"`
+	PostalCode       *string `json:"postalCode,omitempty" binding:"notnull,omitempty,max=20"`
+	Country          *string `json:"country,omitempty" binding:"notnull,omitempty,max=100"`
+
+	MergePatch `json:"-"`
+}
+
+func (d *UpdateCompanyGlobalAddressDTO) UnmarshalJSON(data []byte) error {
+	type alias UpdateCompanyGlobalAddressDTO
+	return decodeMergePatch(data, (*alias)(d), &d.MergePatch)
 }
 
 type CompanyGlobalAddressDTO struct {
-	ID               int64   `json:"id"`
-	Street           string  `json:"street"`
-	StreetNumber     *string `json:"streetNumber,omitempty"`
-	StreetComplement *string `json:"streetComplement,omitempty"`
-	City             string  `json:"city"`
-	State            string  `json:"state"`
-	PostalCode       string  `json:"postalCode"`
-	Country          string  `json:"country"`
+	ID               util.SnowflakeID `json:"id"`
+	Type             string           `json:"type"`
+	Street           string           `json:"street"`
+	StreetNumber     *string          `json:"streetNumber,omitempty"`
+	StreetComplement *string          `json:"streetComplement,omitempty"`
+	City             string           `json:"city"`
+	State            string           `json:"state"`
+	PostalCode       string           `json:"postalCode"`
+	Country          string           `json:"country"`
+	CityIBGECode     *int32           `json:"cityIbgeCode,omitempty"`
+	Version          int64            `json:"version"`
 }
 
 type CompanyGlobalContactDTO struct {
-	ID    int64   `json:"id"`
-	Name  string  `json:"name"`
-	Email *string `json:"email,omitempty"`
-	Phone *string `json:"phone,omitempty"`
-	CGC   *string `json:"cgc,omitempty"`
+	ID      util.SnowflakeID `json:"id"`
+	Name    string           `json:"name"`
+	Email   *string          `json:"email,omitempty"`
+	Phone   *string          `json:"phone,omitempty"`
+	CGC     *string          `json:"cgc,omitempty"`
+	Version int64            `json:"version"`
 }
 
 type CompanyGlobalDTO struct {
-	ID          int64                      `json:"id"`
-	Name        string                     `json:"name"`
-	SocialName  string                     `json:"socialName"`
-	Description *string                    `json:"description,omitempty"`
-	CGC         string                     `json:"cgc"`
-	Enabled     bool                       `json:"enabled"`
-	Email       *string                    `json:"email,omitempty"`
-	CreatedAt   time.Time                  `json:"createdAt"`
-	UpdatedAt   time.Time                  `json:"updatedAt"`
-	DeletedAt   *time.Time                 `json:"deletedAt,omitempty"`
-	Address     *CompanyGlobalAddressDTO   `json:"address,omitempty"`
-	Contacts    []*CompanyGlobalContactDTO `json:"contacts,omitempty"`
+	ID          util.SnowflakeID `json:"id"`
+	Name        string           `json:"name"`
+	SocialName  string           `json:"socialName"`
+	Description *string          `json:"description,omitempty"`
+	CGC         string           `json:"cgc"`
+	Enabled     bool             `json:"enabled"`
+	Email       *string          `json:"email,omitempty"`
+	TaxRegime   string           `json:"taxRegime"`
+	PixKey      *string          `json:"pixKey,omitempty"`
+	Version     int64            `json:"version"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	UpdatedAt   time.Time        `json:"updatedAt"`
+	DeletedAt   *time.Time       `json:"deletedAt,omitempty"`
+
+	// Address é o endereço principal (MAIN); Addresses traz todos os endereços, inclusive o principal.
+	Address   *CompanyGlobalAddressDTO   `json:"address,omitempty"`
+	Addresses []*CompanyGlobalAddressDTO `json:"addresses,omitempty"`
+	Contacts  []*CompanyGlobalContactDTO `json:"contacts,omitempty"`
 }
 
 func (dto *CreateCompanyGlobalDTO) ValidateContacts() bool {
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"go-sales/pkg/util"
+
+	"time"
+)
+
+// ImportJobDTO é o estado de uma importação, devolvido por POST /imports/{entity} e
+// GET /imports/jobs/:id.
+type ImportJobDTO struct {
+	ID              util.SnowflakeID    `json:"id"`
+	CompanyGlobalID util.SnowflakeID    `json:"companyGlobalId,omitempty"`
+	Entity          string              `json:"entity"`
+	FileName        string              `json:"fileName"`
+	DryRun          bool                `json:"dryRun"`
+	Status          string              `json:"status"`
+	TotalRows       int                 `json:"totalRows"`
+	ProcessedRows   int                 `json:"processedRows"`
+	SucceededRows   int                 `json:"succeededRows"`
+	FailedRows      int                 `json:"failedRows"`
+	Errors          []ImportRowErrorDTO `json:"errors"`
+	ErrorMessage    *string             `json:"errorMessage,omitempty"`
+	StartedAt       *time.Time          `json:"startedAt,omitempty"`
+	FinishedAt      *time.Time          `json:"finishedAt,omitempty"`
+	CreatedAt       time.Time           `json:"createdAt"`
+	UpdatedAt       time.Time           `json:"updatedAt"`
+}
+
+// ImportRowErrorDTO é um erro de uma linha da planilha. Row é o número da linha no arquivo
+// (a linha 1 é o cabeçalho); Code é a regra de validação ou o código do erro de negócio.
+type ImportRowErrorDTO struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"go-sales/pkg/decimal"
+	"go-sales/pkg/util"
+
+	"time"
+)
+
+// CreateInstallmentDTO é o corpo do POST /installments. dueDate é uma data no formato 2006-01-02.
+type CreateInstallmentDTO struct {
+	CompanyGlobalID   util.SnowflakeID `json:"companyGlobalId" binding:"required,snowflake"`
+	DocumentNumber    string           `json:"documentNumber" binding:"required,max=60"`
+	InstallmentNumber int              `json:"installmentNumber,omitempty" binding:"omitempty,min=1,max=999"`
+	CustomerName      string           `json:"customerName" binding:"required,max=255"`
+	CustomerCGC       *string          `json:"customerCgc,omitempty" binding:"omitempty,max=14,cgc"`
+	Description       *string          `json:"description,omitempty" binding:"omitempty,max=4000"`
+	Amount            decimal.Decimal  `json:"amount"`
+	DueDate           string           `json:"dueDate" binding:"required,datetime=2006-01-02"`
+}
+
+// UpdateInstallmentDTO é o corpo do PATCH /installments/:id (JSON Merge Patch). Só parcelas em
+// aberto podem ser alteradas.
+type UpdateInstallmentDTO struct {
+	CustomerName *string          `json:"customerName,omitempty" binding:"notnull,omitempty,max=255"`
+	CustomerCGC  *string          `json:"customerCgc,omitempty" binding:"omitempty,max=14,cgc"`
+	Description  *string          `json:"description,omitempty" binding:"omitempty,max=4000"`
+	Amount       *decimal.Decimal `json:"amount,omitempty" binding:"notnull"`
+	DueDate      *string          `json:"dueDate,omitempty" binding:"notnull,omitempty,datetime=2006-01-02"`
+
+	MergePatch `json:"-"`
+}
+
+func (d *UpdateInstallmentDTO) UnmarshalJSON(data []byte) error {
+	type alias UpdateInstallmentDTO
+	return decodeMergePatch(data, (*alias)(d), &d.MergePatch)
+}
+
+type InstallmentDTO struct {
+	ID                util.SnowflakeID `json:"id"`
+	CompanyGlobalID   util.SnowflakeID `json:"companyGlobalId"`
+	DocumentNumber    string           `json:"documentNumber"`
+	InstallmentNumber int              `json:"installmentNumber"`
+	CustomerName      string           `json:"customerName"`
+	CustomerCGC       *string          `json:"customerCgc,omitempty"`
+	Description       *string          `json:"description,omitempty"`
+	Amount            decimal.Decimal  `json:"amount"`
+	DueDate           string           `json:"dueDate"`
+	Status            string           `json:"status"`
+	PaidAmount        *decimal.Decimal `json:"paidAmount,omitempty"`
+	PaidAt            *time.Time       `json:"paidAt,omitempty"`
+	PaymentMethod     *string          `json:"paymentMethod,omitempty"`
+	PaymentReference  *string          `json:"paymentReference,omitempty"`
+	Version           int64            `json:"version"`
+	CreatedAt         time.Time        `json:"createdAt"`
+	UpdatedAt         time.Time        `json:"updatedAt"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"go-sales/pkg/util"
+
+	"time"
+)
+
+// JobDTO é o estado de um job da fila, devolvido por GET /jobs/:id. O payload não é exposto,
+// pois pode carregar dados sensíveis (ex: as linhas de uma importação).
+type JobDTO struct {
+	ID          util.SnowflakeID `json:"id"`
+	Type        string           `json:"type"`
+	Status      string           `json:"status"`
+	Attempts    int              `json:"attempts"`
+	MaxAttempts int              `json:"maxAttempts"`
+	RunAt       time.Time        `json:"runAt"`
+	LastError   *string          `json:"lastError,omitempty"`
+	FinishedAt  *time.Time       `json:"finishedAt,omitempty"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	UpdatedAt   time.Time        `json:"updatedAt"`
+}
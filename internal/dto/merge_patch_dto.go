@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MergePatch é embutida nos DTOs de PATCH e registra quais campos vieram no corpo,
+// seguindo a semântica do JSON Merge Patch (RFC 7396): campo ausente = não alterar,
+// campo com null = limpar, demais valores = substituir.
+type MergePatch struct {
+	present map[string]bool
+	null    map[string]bool
+}
+
+// Has indica se o campo (nome JSON) veio no corpo, inclusive como null.
+func (p MergePatch) Has(field string) bool {
+	return p.present[field]
+}
+
+// IsNull indica se o campo veio explicitamente como null.
+func (p MergePatch) IsNull(field string) bool {
+	return p.null[field]
+}
+
+// decodeMergePatch decodifica o corpo em target e guarda em patch os campos presentes.
+// target deve ser um alias do DTO (sem UnmarshalJSON) para evitar recursão.
+func decodeMergePatch(data []byte, target any, patch *MergePatch) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	patch.present = make(map[string]bool, len(raw))
+	patch.null = make(map[string]bool)
+	for field, value := range raw {
+		patch.present[field] = true
+		if bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+			patch.null[field] = true
+		}
+	}
+	return json.Unmarshal(data, target)
+}
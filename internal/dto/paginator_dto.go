@@ -1,11 +1,26 @@
 package dto
 
+// PageRequest descreve a paginação pedida pelo cliente.
+// No modo offset (padrão) usa Page e PageSize. O modo cursor (keyset) é ativado
+// quando o cliente envia ?cursor= ou ?limit=; nesse caso PageSize recebe o limit.
+type PageRequest struct {
+	Page      int
+	PageSize  int
+	Cursor    string
+	UseCursor bool
+	// WithCount indica se o total de itens deve ser calculado (?count=false desliga o COUNT(*)).
+	WithCount bool
+}
+
 // PageInfo contém os metadados da paginação.
+// TotalItems e TotalPages só são enviados quando o total foi calculado.
 type PageInfo struct {
-	Page       int   `json:"page"`
-	PageSize   int   `json:"pageSize"`
-	TotalItems int64 `json:"totalItems"`
-	TotalPages int   `json:"totalPages"`
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"pageSize"`
+	TotalItems *int64 `json:"totalItems,omitempty"`
+	TotalPages *int   `json:"totalPages,omitempty"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
 }
 
 // PaginatedResponse é uma estrutura de resposta genérica para qualquer lista paginada.
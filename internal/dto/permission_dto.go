@@ -1,20 +1,36 @@
 package dto
 
 import (
+	"go-sales/pkg/util"
+
 	"time"
 )
 
 type CreatePermissionDTO struct {
-	Name            string  `json:"name" binding:"required,max=255"`
-	CompanyGlobalID int64   `json:"companyGlobalID" binding:"required"`
-	Description     *string `json:"description" binding:"omitempty,max=4000"`
+	Name            string           `json:"name" binding:"required,max=255"`
+	CompanyGlobalID util.SnowflakeID `json:"companyGlobalID" binding:"required"`
+	Description     *string          `json:"description" binding:"omitempty,max=4000"`
+}
+
+// UpdatePermissionDTO é o corpo do PATCH /permissions/:id (JSON Merge Patch).
+type UpdatePermissionDTO struct {
+	Name        *string `json:"name,omitempty" binding:"notnull,omitempty,max=255"`
+	Description *string `json:"description,omitempty" binding:"omitempty,max=4000"`
+
+	MergePatch `json:"-"`
+}
+
+func (d *UpdatePermissionDTO) UnmarshalJSON(data []byte) error {
+	type alias UpdatePermissionDTO
+	return decodeMergePatch(data, (*alias)(d), &d.MergePatch)
 }
 
 type PermissionDTO struct {
-	ID              int64     `json:"id" binding:"required,snowflake"`
-	CompanyGlobalID int64     `json:"companyGlobalID" binding:"required,snowflake"`
-	Name            string    `json:"name" binding:"required,max=255"`
-	Description     *string   `json:"description,omitempty" binding:"omitempty,max=4000"`
-	CreatedAt       time.Time `json:"createdAt"`
-	UpdatedAt       time.Time `json:"updatedAt"`
+	ID              util.SnowflakeID `json:"id" binding:"required,snowflake"`
+	CompanyGlobalID util.SnowflakeID `json:"companyGlobalID" binding:"required,snowflake"`
+	Name            string           `json:"name" binding:"required,max=255"`
+	Description     *string          `json:"description,omitempty" binding:"omitempty,max=4000"`
+	Version         int64            `json:"version"`
+	CreatedAt       time.Time        `json:"createdAt"`
+	UpdatedAt       time.Time        `json:"updatedAt"`
 }
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"go-sales/pkg/decimal"
+	"go-sales/pkg/util"
+
+	"time"
+)
+
+// CreatePixChargeDTO é o corpo do POST /installments/:id/pix-charges. O QR Code estático leva a chave
+// e o txid no próprio payload; o dinâmico registra a cobrança no PSP e expira em expiresIn segundos.
+type CreatePixChargeDTO struct {
+	Type        string  `json:"type" binding:"required,oneof=static dynamic"`
+	Description *string `json:"description,omitempty" binding:"omitempty,max=140"`
+	ExpiresIn   *int    `json:"expiresIn,omitempty" binding:"omitempty,min=60,max=2592000"`
+}
+
+type PixChargeDTO struct {
+	ID              util.SnowflakeID `json:"id"`
+	CompanyGlobalID util.SnowflakeID `json:"companyGlobalId"`
+	InstallmentID   util.SnowflakeID `json:"installmentId"`
+	TxID            string           `json:"txid"`
+	Type            string           `json:"type"`
+	PixKey          string           `json:"pixKey"`
+	Amount          decimal.Decimal  `json:"amount"`
+	// Payload é o BR Code ("copia e cola").
+	Payload string `json:"payload"`
+	// QRCode é o BR Code como PNG, numa data URI. Não vem nas listagens.
+	QRCode     string           `json:"qrCode,omitempty"`
+	Location   *string          `json:"location,omitempty"`
+	Status     string           `json:"status"`
+	EndToEndID *string          `json:"endToEndId,omitempty"`
+	PaidAmount *decimal.Decimal `json:"paidAmount,omitempty"`
+	PaidAt     *time.Time       `json:"paidAt,omitempty"`
+	ExpiresAt  *time.Time       `json:"expiresAt,omitempty"`
+	CreatedAt  time.Time        `json:"createdAt"`
+}
+
+// PixNotificationResultDTO é o resultado de um PIX recebido no webhook: settled (parcela baixada),
+// already_processed, unknown_txid, amount_mismatch ou installment_not_open.
+type PixNotificationResultDTO struct {
+	EndToEndID    string            `json:"endToEndId"`
+	TxID          string            `json:"txid"`
+	Result        string            `json:"result"`
+	InstallmentID *util.SnowflakeID `json:"installmentId,omitempty"`
+}
+
+// PixWebhookResultDTO é a resposta do POST /pix/webhook.
+type PixWebhookResultDTO struct {
+	Results []PixNotificationResultDTO `json:"results"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+// ReadOptions descreve a forma da resposta pedida pelo cliente em uma leitura.
+// Fields vem de ?fields=id,name (vazio = todos os campos) e Expand de
+// ?expand=roles.permissions,companyGlobal (associações a carregar, com caminhos aninhados).
+type ReadOptions struct {
+	Fields []string
+	Expand []string
+	// ExpandAll carrega todas as associações diretas da entidade. É o padrão das leituras
+	// de um único registro quando o cliente não envia ?expand=.
+	ExpandAll bool
+}
+
+// FullRead carrega a entidade completa, com todas as associações diretas.
+// Usado pelos serviços em leituras internas, que não dependem da query string.
+var FullRead = ReadOptions{ExpandAll: true}
@@ -1,34 +1,55 @@
 package dto
 
 import (
+	"go-sales/pkg/util"
+
 	"time"
 )
 
 type PermissionUtilHelper struct {
-	ID int64 `json:"id" binding:"required,snowflake"`
+	ID util.SnowflakeID `json:"id" binding:"required,snowflake"`
 }
 
 type CreateRoleDTO struct {
 	Name            string                 `json:"name" binding:"required,max=255"`
 	Description     *string                `json:"description,omitempty" binding:"omitempty,max=4000"`
-	CompanyGlobalID int64                  `json:"companyGlobalId" binding:"required,snowflake"`
+	CompanyGlobalID util.SnowflakeID       `json:"companyGlobalId" binding:"required,snowflake"`
 	Permissions     []PermissionUtilHelper `json:"permissions" binding:"required,min=1,dive"`
 	CanEdit         bool                   `json:"canEdit"`
 	CanDelete       bool                   `json:"canDelete"`
 	IsAdmin         bool                   `json:"isAdmin"`
 }
 
+// UpdateRoleDTO é o corpo do PATCH /roles/:id (JSON Merge Patch).
+// permissions, por ser um array, substitui a lista inteira.
+type UpdateRoleDTO struct {
+	Name        *string                `json:"name,omitempty" binding:"notnull,omitempty,max=255"`
+	Description *string                `json:"description,omitempty" binding:"omitempty,max=4000"`
+	Permissions []PermissionUtilHelper `json:"permissions,omitempty" binding:"notnull,omitnil,min=1,dive"`
+	CanEdit     *bool                  `json:"canEdit,omitempty" binding:"notnull"`
+	CanDelete   *bool                  `json:"canDelete,omitempty" binding:"notnull"`
+	IsAdmin     *bool                  `json:"isAdmin,omitempty" binding:"notnull"`
+
+	MergePatch `json:"-"`
+}
+
+func (d *UpdateRoleDTO) UnmarshalJSON(data []byte) error {
+	type alias UpdateRoleDTO
+	return decodeMergePatch(data, (*alias)(d), &d.MergePatch)
+}
+
 type RoleDTO struct {
-	ID              int64           `json:"id" binding:"required,snowflake"`
-	Name            string          `json:"name" binding:"required,max=255"`
-	Description     *string         `json:"description,omitempty" binding:"omitempty,max=4000"`
-	Permissions     []PermissionDTO `json:"permissions" binding:"required,min=1,dive"`
-	CompanyGlobalID int64           `json:"companyGlobalId" binding:"required,snowflake"`
+	ID              util.SnowflakeID `json:"id" binding:"required,snowflake"`
+	Name            string           `json:"name" binding:"required,max=255"`
+	Description     *string          `json:"description,omitempty" binding:"omitempty,max=4000"`
+	Permissions     []PermissionDTO  `json:"permissions" binding:"required,min=1,dive"`
+	CompanyGlobalID util.SnowflakeID `json:"companyGlobalId" binding:"required,snowflake"`
 
 	CanEdit   bool `json:"canEdit"`
 	CanDelete bool `json:"canDelete"`
 	IsAdmin   bool `json:"isAdmin"`
 
+	Version   int64     `json:"version"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
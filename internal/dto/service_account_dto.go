@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"go-sales/pkg/util"
+
+	"time"
+)
+
+type CreateServiceAccountDTO struct {
+	Name            string             `json:"name" binding:"required,min=2,max=255"`
+	Description     *string            `json:"description,omitempty" binding:"omitempty,max=4000"`
+	CompanyGlobalID util.SnowflakeID   `json:"companyGlobalId" binding:"required,snowflake"`
+	RoleIDs         []util.SnowflakeID `json:"roleIds" binding:"required,min=1,dive,snowflake"`
+}
+
+// UpdateServiceAccountDTO é o corpo do PATCH /service-accounts/:id (JSON Merge Patch).
+// roleIds, por ser um array, substitui a lista inteira.
+type UpdateServiceAccountDTO struct {
+	Name        *string            `json:"name,omitempty" binding:"notnull,omitempty,min=2,max=255"`
+	Description *string            `json:"description,omitempty" binding:"omitempty,max=4000"`
+	Enabled     *bool              `json:"enabled,omitempty" binding:"notnull"`
+	RoleIDs     []util.SnowflakeID `json:"roleIds,omitempty" binding:"notnull,omitnil,min=1,dive,snowflake"`
+
+	MergePatch `json:"-"`
+}
+
+func (d *UpdateServiceAccountDTO) UnmarshalJSON(data []byte) error {
+	type alias UpdateServiceAccountDTO
+	return decodeMergePatch(data, (*alias)(d), &d.MergePatch)
+}
+
+type ServiceAccountDTO struct {
+	ID              util.SnowflakeID `json:"id"`
+	Name            string           `json:"name"`
+	Description     *string          `json:"description,omitempty"`
+	CompanyGlobalID util.SnowflakeID `json:"companyGlobalId"`
+	Enabled         bool             `json:"enabled"`
+	Roles           []RoleDTO        `json:"roles"`
+	Version         int64            `json:"version"`
+	CreatedAt       time.Time        `json:"createdAt"`
+	UpdatedAt       time.Time        `json:"updatedAt"`
+}
+
+// CreateAPIKeyDTO é o corpo do POST /service-accounts/:id/keys. Sem expiresAt a chave não expira.
+type CreateAPIKeyDTO struct {
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// RotateAPIKeyDTO é o corpo do POST /service-accounts/:id/keys/:keyId/rotate.
+// A chave antiga continua valendo por gracePeriodSeconds (padrão 0: deixa de valer na hora),
+// tempo para a integração trocar a chave sem parar.
+type RotateAPIKeyDTO struct {
+	ExpiresAt          *time.Time `json:"expiresAt,omitempty"`
+	GracePeriodSeconds int        `json:"gracePeriodSeconds" binding:"min=0,max=2592000"`
+}
+
+// APIKeyDTO descreve uma chave de API. A chave em si nunca é devolvida depois de criada; o prefixo
+// serve para reconhecê-la.
+type APIKeyDTO struct {
+	ID               util.SnowflakeID `json:"id"`
+	ServiceAccountID util.SnowflakeID `json:"serviceAccountId"`
+	Prefix           string           `json:"prefix"`
+	ExpiresAt        *time.Time       `json:"expiresAt,omitempty"`
+	LastUsedAt       *time.Time       `json:"lastUsedAt,omitempty"`
+	RevokedAt        *time.Time       `json:"revokedAt,omitempty"`
+	CreatedAt        time.Time        `json:"createdAt"`
+}
+
+// CreatedAPIKeyDTO é a resposta da criação e da rotação: a única vez em que a chave aparece.
+type CreatedAPIKeyDTO struct {
+	APIKeyDTO
+	Key string `json:"key"`
+}
@@ -0,0 +1,179 @@
+package dto
+
+import (
+	"go-sales/pkg/decimal"
+	"go-sales/pkg/util"
+
+	"time"
+)
+
+// CreateTaxRuleDTO é o corpo do POST /tax-rules. Os critérios omitidos casam com qualquer valor e os
+// percentuais são informados como 18 para 18% (number ou string).
+type CreateTaxRuleDTO struct {
+	CompanyGlobalID util.SnowflakeID `json:"companyGlobalId" binding:"required,snowflake"`
+	Description     *string          `json:"description,omitempty" binding:"omitempty,max=4000"`
+
+	OriginUF       *string `json:"originUf,omitempty" binding:"omitempty,len=2"`
+	DestinationUF  *string `json:"destinationUf,omitempty" binding:"omitempty,len=2"`
+	NCM            *string `json:"ncm,omitempty" binding:"omitempty,numeric,min=2,max=8"`
+	CFOP           *string `json:"cfop,omitempty" binding:"omitempty,numeric,len=4"`
+	CustomerRegime *string `json:"customerRegime,omitempty" binding:"omitempty,oneof=contributor simples_nacional non_contributor"`
+	IssuerRegime   *string `json:"issuerRegime,omitempty" binding:"omitempty,oneof=normal simples_nacional"`
+
+	ICMSCode                 string          `json:"icmsCode" binding:"required,numeric,min=2,max=3"`
+	ICMSRate                 decimal.Decimal `json:"icmsRate"`
+	ICMSBaseReduction        decimal.Decimal `json:"icmsBaseReduction"`
+	SimplesCreditRate        decimal.Decimal `json:"simplesCreditRate"`
+	ICMSSTMVA                decimal.Decimal `json:"icmsStMva"`
+	ICMSSTRate               decimal.Decimal `json:"icmsStRate"`
+	ICMSSTBaseReduction      decimal.Decimal `json:"icmsStBaseReduction"`
+	IPICode                  *string         `json:"ipiCode,omitempty" binding:"omitempty,numeric,len=2"`
+	IPIRate                  decimal.Decimal `json:"ipiRate"`
+	PISCode                  string          `json:"pisCode" binding:"required,numeric,len=2"`
+	PISRate                  decimal.Decimal `json:"pisRate"`
+	COFINSCode               string          `json:"cofinsCode" binding:"required,numeric,len=2"`
+	COFINSRate               decimal.Decimal `json:"cofinsRate"`
+	ExcludeICMSFromPISCOFINS bool            `json:"excludeIcmsFromPisCofins"`
+	Enabled                  *bool           `json:"enabled,omitempty"`
+}
+
+// UpdateTaxRuleDTO é o corpo do PATCH /tax-rules/:id (JSON Merge Patch). null num critério faz a
+// regra voltar a casar com qualquer valor.
+type UpdateTaxRuleDTO struct {
+	Description *string `json:"description,omitempty" binding:"omitempty,max=4000"`
+
+	OriginUF       *string `json:"originUf,omitempty" binding:"omitempty,len=2"`
+	DestinationUF  *string `json:"destinationUf,omitempty" binding:"omitempty,len=2"`
+	NCM            *string `json:"ncm,omitempty" binding:"omitempty,numeric,min=2,max=8"`
+	CFOP           *string `json:"cfop,omitempty" binding:"omitempty,numeric,len=4"`
+	CustomerRegime *string `json:"customerRegime,omitempty" binding:"omitempty,oneof=contributor simples_nacional non_contributor"`
+	IssuerRegime   *string `json:"issuerRegime,omitempty" binding:"omitempty,oneof=normal simples_nacional"`
+
+	ICMSCode                 *string          `json:"icmsCode,omitempty" binding:"notnull,omitempty,numeric,min=2,max=3"`
+	ICMSRate                 *decimal.Decimal `json:"icmsRate,omitempty" binding:"notnull"`
+	ICMSBaseReduction        *decimal.Decimal `json:"icmsBaseReduction,omitempty" binding:"notnull"`
+	SimplesCreditRate        *decimal.Decimal `json:"simplesCreditRate,omitempty" binding:"notnull"`
+	ICMSSTMVA                *decimal.Decimal `json:"icmsStMva,omitempty" binding:"notnull"`
+	ICMSSTRate               *decimal.Decimal `json:"icmsStRate,omitempty" binding:"notnull"`
+	ICMSSTBaseReduction      *decimal.Decimal `json:"icmsStBaseReduction,omitempty" binding:"notnull"`
+	IPICode                  *string          `json:"ipiCode,omitempty" binding:"omitempty,numeric,len=2"`
+	IPIRate                  *decimal.Decimal `json:"ipiRate,omitempty" binding:"notnull"`
+	PISCode                  *string          `json:"pisCode,omitempty" binding:"notnull,omitempty,numeric,len=2"`
+	PISRate                  *decimal.Decimal `json:"pisRate,omitempty" binding:"notnull"`
+	COFINSCode               *string          `json:"cofinsCode,omitempty" binding:"notnull,omitempty,numeric,len=2"`
+	COFINSRate               *decimal.Decimal `json:"cofinsRate,omitempty" binding:"notnull"`
+	ExcludeICMSFromPISCOFINS *bool            `json:"excludeIcmsFromPisCofins,omitempty" binding:"notnull"`
+	Enabled                  *bool            `json:"enabled,omitempty" binding:"notnull"`
+
+	MergePatch `json:"-"`
+}
+
+func (d *UpdateTaxRuleDTO) UnmarshalJSON(data []byte) error {
+	type alias UpdateTaxRuleDTO
+	return decodeMergePatch(data, (*alias)(d), &d.MergePatch)
+}
+
+type TaxRuleDTO struct {
+	ID              util.SnowflakeID `json:"id"`
+	CompanyGlobalID util.SnowflakeID `json:"companyGlobalId"`
+	Description     *string          `json:"description,omitempty"`
+
+	OriginUF       *string `json:"originUf,omitempty"`
+	DestinationUF  *string `json:"destinationUf,omitempty"`
+	NCM            *string `json:"ncm,omitempty"`
+	CFOP           *string `json:"cfop,omitempty"`
+	CustomerRegime *string `json:"customerRegime,omitempty"`
+	IssuerRegime   *string `json:"issuerRegime,omitempty"`
+
+	ICMSCode                 string          `json:"icmsCode"`
+	ICMSRate                 decimal.Decimal `json:"icmsRate"`
+	ICMSBaseReduction        decimal.Decimal `json:"icmsBaseReduction"`
+	SimplesCreditRate        decimal.Decimal `json:"simplesCreditRate"`
+	ICMSSTMVA                decimal.Decimal `json:"icmsStMva"`
+	ICMSSTRate               decimal.Decimal `json:"icmsStRate"`
+	ICMSSTBaseReduction      decimal.Decimal `json:"icmsStBaseReduction"`
+	IPICode                  *string         `json:"ipiCode,omitempty"`
+	IPIRate                  decimal.Decimal `json:"ipiRate"`
+	PISCode                  string          `json:"pisCode"`
+	PISRate                  decimal.Decimal `json:"pisRate"`
+	COFINSCode               string          `json:"cofinsCode"`
+	COFINSRate               decimal.Decimal `json:"cofinsRate"`
+	ExcludeICMSFromPISCOFINS bool            `json:"excludeIcmsFromPisCofins"`
+	Enabled                  bool            `json:"enabled"`
+
+	Version   int64     `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TaxPreviewDTO é o corpo do POST /taxes/preview: um carrinho a ser tributado pelas regras da
+// empresa. originUf omitida é a UF do endereço principal da empresa.
+type TaxPreviewDTO struct {
+	CompanyGlobalID util.SnowflakeID    `json:"companyGlobalId" binding:"required,snowflake"`
+	OriginUF        *string             `json:"originUf,omitempty" binding:"omitempty,len=2"`
+	DestinationUF   string              `json:"destinationUf" binding:"required,len=2"`
+	CustomerRegime  string              `json:"customerRegime" binding:"required,oneof=contributor simples_nacional non_contributor"`
+	Items           []TaxPreviewItemDTO `json:"items" binding:"required,min=1,max=990,dive"`
+}
+
+// TaxPreviewItemDTO é um item do carrinho. discount, freight, insurance e otherCosts são valores do
+// item (não unitários).
+type TaxPreviewItemDTO struct {
+	NCM        string          `json:"ncm" binding:"required,numeric,len=8"`
+	CFOP       string          `json:"cfop" binding:"required,numeric,len=4"`
+	Quantity   decimal.Decimal `json:"quantity"`
+	UnitPrice  decimal.Decimal `json:"unitPrice"`
+	Discount   decimal.Decimal `json:"discount"`
+	Freight    decimal.Decimal `json:"freight"`
+	Insurance  decimal.Decimal `json:"insurance"`
+	OtherCosts decimal.Decimal `json:"otherCosts"`
+}
+
+// TaxPreviewResultDTO é a resposta do POST /taxes/preview.
+type TaxPreviewResultDTO struct {
+	OriginUF       string              `json:"originUf"`
+	DestinationUF  string              `json:"destinationUf"`
+	IssuerRegime   string              `json:"issuerRegime"`
+	CustomerRegime string              `json:"customerRegime"`
+	Items          []TaxPreviewLineDTO `json:"items"`
+	Totals         TaxTotalsDTO        `json:"totals"`
+}
+
+// TaxPreviewLineDTO são os impostos de um item. icmsSt e simplesCredit só aparecem quando calculados.
+type TaxPreviewLineDTO struct {
+	Item          int              `json:"item"`
+	TaxRuleID     util.SnowflakeID `json:"taxRuleId"`
+	Value         decimal.Decimal  `json:"value"`
+	Base          decimal.Decimal  `json:"base"`
+	ICMS          TaxAmountDTO     `json:"icms"`
+	ICMSST        *TaxAmountDTO    `json:"icmsSt,omitempty"`
+	SimplesCredit *TaxAmountDTO    `json:"simplesCredit,omitempty"`
+	IPI           *TaxAmountDTO    `json:"ipi,omitempty"`
+	PIS           TaxAmountDTO     `json:"pis"`
+	COFINS        TaxAmountDTO     `json:"cofins"`
+	Total         decimal.Decimal  `json:"total"`
+}
+
+// TaxAmountDTO é um imposto calculado: o CST/CSOSN, a base, a alíquota (em %) e o valor.
+type TaxAmountDTO struct {
+	Code   string          `json:"code"`
+	Base   decimal.Decimal `json:"base"`
+	Rate   decimal.Decimal `json:"rate"`
+	Amount decimal.Decimal `json:"amount"`
+}
+
+type TaxTotalsDTO struct {
+	Products   decimal.Decimal `json:"products"`
+	Discount   decimal.Decimal `json:"discount"`
+	Freight    decimal.Decimal `json:"freight"`
+	Insurance  decimal.Decimal `json:"insurance"`
+	OtherCosts decimal.Decimal `json:"otherCosts"`
+	ICMSBase   decimal.Decimal `json:"icmsBase"`
+	ICMS       decimal.Decimal `json:"icms"`
+	ICMSSTBase decimal.Decimal `json:"icmsStBase"`
+	ICMSST     decimal.Decimal `json:"icmsSt"`
+	IPI        decimal.Decimal `json:"ipi"`
+	PIS        decimal.Decimal `json:"pis"`
+	COFINS     decimal.Decimal `json:"cofins"`
+	Total      decimal.Decimal `json:"total"`
+}
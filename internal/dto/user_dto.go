@@ -1,22 +1,24 @@
 package dto
 
 import (
+	"go-sales/pkg/util"
+
 	"time"
 )
 
 type CreateUserDTO struct {
-	Name            string `json:"name" binding:"required,min=2"`
-	Email           string `json:"email" binding:"required,email"`
-	Password        string `json:"password" binding:"required,min=8"`
-	CompanyGlobalID int64  `json:"companyGlobalId" binding:"required"`
+	Name            string           `json:"name" binding:"required,min=2"`
+	Email           string           `json:"email" binding:"required,email"`
+	Password        string           `json:"password" binding:"required,min=8"`
+	CompanyGlobalID util.SnowflakeID `json:"companyGlobalId" binding:"required"`
 
 	// Adicionamos o campo para receber os IDs das roles.
 	// "dive" diz ao validador para aplicar a regra "uuid" em cada elemento do array.
-	RoleIDs []int64 `json:"roleIds" binding:"required,dive"`
+	RoleIDs []util.SnowflakeID `json:"roleIds" binding:"required,dive"`
 }
 
 type UserDTO struct {
-	ID              int64            `json:"id"`
+	ID              util.SnowflakeID `json:"id"`
 	Name            string           `json:"name"`
 	Email           string           `json:"email"`
 	EmailRecovery   string           `json:"emailRecovery"`
@@ -33,16 +35,33 @@ type UserDTO struct {
 	ResetKey        *string          `json:"resetKey,omitempty"`
 	ResetRequested  *time.Time       `json:"resetRequested,omitempty"`
 	ResetAt         *time.Time       `json:"resetAt,omitempty"`
-	CompanyGlobalID int64            `json:"companyGlobalId"`
-	CompanyGlobal   CompanyGlobalDTO `json:"companyGlobal"`
-	Roles           []RoleDTO        `json:"roles"`
-	CreatedAt       time.Time        `json:"createdAt"`
-	UpdatedAt       time.Time        `json:"updatedAt"`
-	DeletedAt       *time.Time       `json:"deletedAt,omitempty"`
+	// FailedLoginAttempts e LockedUntil mostram o bloqueio por senhas erradas.
+	FailedLoginAttempts int              `json:"failedLoginAttempts"`
+	LockedUntil         *time.Time       `json:"lockedUntil,omitempty"`
+	CompanyGlobalID     util.SnowflakeID `json:"companyGlobalId"`
+	CompanyGlobal       CompanyGlobalDTO `json:"companyGlobal"`
+	Roles               []RoleDTO        `json:"roles"`
+	Version             int64            `json:"version"`
+	CreatedAt           time.Time        `json:"createdAt"`
+	UpdatedAt           time.Time        `json:"updatedAt"`
+	DeletedAt           *time.Time       `json:"deletedAt,omitempty"`
 }
 
+// UpdateUserDTO é o corpo do PATCH /users/:id (JSON Merge Patch).
+// Apenas os campos presentes são validados e gravados; "notnull" impede limpar campos obrigatórios.
 type UpdateUserDTO struct {
-	Name    *string `json:"name,omitempty" binding:"omitempty,max=255"`
-	Email   *string `json:"email,omitempty" binding:"omitempty,email,max=150"`
-	Enabled *bool   `json:"enabled,omitempty"`
+	Name          *string            `json:"name,omitempty" binding:"notnull,omitempty,min=2,max=255"`
+	Email         *string            `json:"email,omitempty" binding:"notnull,omitempty,email,max=150"`
+	EmailRecovery *string            `json:"emailRecovery,omitempty" binding:"omitempty,email,max=255"`
+	Phone         *string            `json:"phone,omitempty" binding:"omitempty,max=20"`
+	Password      *string            `json:"password,omitempty" binding:"notnull,omitempty,min=8"`
+	Enabled       *bool              `json:"enabled,omitempty" binding:"notnull"`
+	RoleIDs       []util.SnowflakeID `json:"roleIds,omitempty" binding:"notnull,omitnil,min=1,dive,snowflake"`
+
+	MergePatch `json:"-"`
+}
+
+func (d *UpdateUserDTO) UnmarshalJSON(data []byte) error {
+	type alias UpdateUserDTO
+	return decodeMergePatch(data, (*alias)(d), &d.MergePatch)
 }
@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"encoding/json"
+	"go-sales/pkg/util"
+
+	"time"
+)
+
+// CreateWebhookDTO é o corpo do POST /webhooks. eventTypes lista os eventos assinados; "*" assina todos.
+type CreateWebhookDTO struct {
+	URL             string           `json:"url" binding:"required,url,max=2048"`
+	Description     *string          `json:"description,omitempty" binding:"omitempty,max=4000"`
+	CompanyGlobalID util.SnowflakeID `json:"companyGlobalId" binding:"required,snowflake"`
+	EventTypes      []string         `json:"eventTypes" binding:"required,min=1,dive,required,max=100"`
+}
+
+// UpdateWebhookDTO é o corpo do PATCH /webhooks/:id (JSON Merge Patch).
+// eventTypes, por ser um array, substitui a lista inteira.
+type UpdateWebhookDTO struct {
+	URL         *string  `json:"url,omitempty" binding:"notnull,omitempty,url,max=2048"`
+	Description *string  `json:"description,omitempty" binding:"omitempty,max=4000"`
+	EventTypes  []string `json:"eventTypes,omitempty" binding:"notnull,omitnil,min=1,dive,required,max=100"`
+	Enabled     *bool    `json:"enabled,omitempty" binding:"notnull"`
+
+	MergePatch `json:"-"`
+}
+
+func (d *UpdateWebhookDTO) UnmarshalJSON(data []byte) error {
+	type alias UpdateWebhookDTO
+	return decodeMergePatch(data, (*alias)(d), &d.MergePatch)
+}
+
+type WebhookDTO struct {
+	ID              util.SnowflakeID `json:"id"`
+	URL             string           `json:"url"`
+	Description     *string          `json:"description,omitempty"`
+	EventTypes      []string         `json:"eventTypes"`
+	Enabled         bool             `json:"enabled"`
+	CompanyGlobalID util.SnowflakeID `json:"companyGlobalId"`
+	Version         int64            `json:"version"`
+	CreatedAt       time.Time        `json:"createdAt"`
+	UpdatedAt       time.Time        `json:"updatedAt"`
+}
+
+// CreatedWebhookDTO é a resposta da criação: a única vez em que o segredo de assinatura aparece.
+type CreatedWebhookDTO struct {
+	WebhookDTO
+	Secret string `json:"secret"`
+}
+
+// WebhookDeliveryDTO descreve uma entrega. O payload e o histórico de tentativas só vêm no detalhe
+// (GET /webhooks/:id/deliveries/:deliveryId).
+type WebhookDeliveryDTO struct {
+	ID             util.SnowflakeID            `json:"id"`
+	EndpointID     util.SnowflakeID            `json:"endpointId"`
+	EventID        util.SnowflakeID            `json:"eventId"`
+	EventType      string                      `json:"eventType"`
+	Status         string                      `json:"status"`
+	Attempts       int                         `json:"attempts"`
+	LastStatusCode *int                        `json:"lastStatusCode,omitempty"`
+	LastError      *string                     `json:"lastError,omitempty"`
+	DeliveredAt    *time.Time                  `json:"deliveredAt,omitempty"`
+	ReplayOf       *util.SnowflakeID           `json:"replayOf,omitempty"`
+	Payload        json.RawMessage             `json:"payload,omitempty"`
+	AttemptLog     []WebhookDeliveryAttemptDTO `json:"attemptLog,omitempty"`
+	CreatedAt      time.Time                   `json:"createdAt"`
+	UpdatedAt      time.Time                   `json:"updatedAt"`
+}
+
+// WebhookDeliveryAttemptDTO é uma tentativa de entrega. responseBody traz no máximo o começo da resposta.
+type WebhookDeliveryAttemptDTO struct {
+	Attempt      int       `json:"attempt"`
+	StatusCode   *int      `json:"statusCode,omitempty"`
+	Error        *string   `json:"error,omitempty"`
+	ResponseBody *string   `json:"responseBody,omitempty"`
+	DurationMs   int64     `json:"durationMs"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
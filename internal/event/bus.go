@@ -0,0 +1,72 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// AllTypes assina todos os tipos de evento em Bus.Subscribe.
+const AllTypes = "*"
+
+// Handler processa um evento entregue pelo barramento. A entrega é "pelo menos uma vez": um erro faz o
+// evento ser entregue de novo a todos os assinantes, então os handlers devem ser idempotentes
+// (Envelope.ID identifica o evento).
+type Handler func(ctx context.Context, env Envelope) error
+
+type subscriber struct {
+	name    string
+	handler Handler
+}
+
+// Bus entrega os eventos aos assinantes em processo. Os assinantes são registrados na inicialização,
+// antes de o despacho começar.
+type Bus struct {
+	subscribers map[string][]subscriber
+}
+
+// NewBus cria um barramento sem assinantes.
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[string][]subscriber)}
+}
+
+// Subscribe registra handler para eventType (ou AllTypes). name identifica o assinante nos erros e logs.
+func (b *Bus) Subscribe(eventType string, name string, handler Handler) {
+	b.subscribers[eventType] = append(b.subscribers[eventType], subscriber{name: name, handler: handler})
+}
+
+// Subscribe registra um handler que recebe o evento já no tipo concreto E.
+func Subscribe[E Event](b *Bus, name string, handler func(ctx context.Context, env Envelope, e E) error) {
+	var zero E
+	b.Subscribe(zero.EventType(), name, func(ctx context.Context, env Envelope) error {
+		e, ok := env.Event.(E)
+		if !ok {
+			return fmt.Errorf("event %s is %T, not %T", env.Type, env.Event, zero)
+		}
+		return handler(ctx, env, e)
+	})
+}
+
+// Dispatch entrega o evento a todos os assinantes do tipo dele e de AllTypes. Todos são chamados mesmo
+// que algum falhe; os erros são devolvidos juntos.
+func (b *Bus) Dispatch(ctx context.Context, env Envelope) error {
+	var errs []error
+	for _, subscribers := range [][]subscriber{b.subscribers[env.Type], b.subscribers[AllTypes]} {
+		for _, s := range subscribers {
+			if err := call(ctx, s.handler, env); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// call executa o handler convertendo um panic em erro.
+func call(ctx context.Context, handler Handler, env Envelope) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("subscriber panicked: %v", recovered)
+		}
+	}()
+	return handler(ctx, env)
+}
@@ -0,0 +1,67 @@
+// Package event define os eventos de domínio publicados pelos serviços e o barramento em processo que
+// os entrega aos assinantes. Os eventos são gravados no outbox na mesma transação da escrita que os
+// gerou (ver database.Recorder) e despachados depois do commit pelo outbox.Dispatcher.
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Event é um evento de domínio. O tipo concreto é serializado em JSON no outbox e reconstruído por
+// Decode antes de chegar aos assinantes.
+type Event interface {
+	// EventType é o nome do evento (ex: "user.created").
+	EventType() string
+	// Aggregate identifica o registro a que o evento se refere. Os eventos de um mesmo agregado são
+	// entregues na ordem em que foram gravados.
+	Aggregate() (aggregateType string, aggregateID int64)
+	// Tenant é a empresa dona do registro.
+	Tenant() int64
+}
+
+// Envelope é o evento entregue aos assinantes, com os metadados gravados no outbox.
+type Envelope struct {
+	// ID é o ID do evento no outbox. Se repete quando o evento é entregue de novo, então os assinantes
+	// podem usá-lo para descartar duplicatas.
+	ID              int64
+	Type            string
+	AggregateType   string
+	AggregateID     int64
+	CompanyGlobalID int64
+	OccurredAt      time.Time
+	// Payload é o evento serializado, como foi gravado.
+	Payload json.RawMessage
+	// Event é o evento decodificado no seu tipo concreto.
+	Event Event
+}
+
+// decoders reconstrói cada tipo de evento a partir do JSON gravado no outbox.
+var decoders = make(map[string]func(data []byte) (Event, error))
+
+// Types lista os tipos de evento conhecidos, na ordem em que foram registrados.
+var Types []string
+
+// register adiciona E ao catálogo de eventos.
+func register[E Event]() {
+	var zero E
+	eventType := zero.EventType()
+	decoders[eventType] = func(data []byte) (Event, error) {
+		var e E
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
+	}
+	Types = append(Types, eventType)
+}
+
+// Decode reconstrói o evento do tipo informado a partir do payload gravado.
+func Decode(eventType string, payload []byte) (Event, error) {
+	decode, ok := decoders[eventType]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %q", eventType)
+	}
+	return decode(payload)
+}
@@ -0,0 +1,191 @@
+package event
+
+import (
+	"go-sales/internal/dto"
+	"go-sales/pkg/decimal"
+	"go-sales/pkg/util"
+)
+
+// Tipos de agregado.
+const (
+	AggregateUser          = "user"
+	AggregateCompanyGlobal = "company_global"
+	AggregateRole          = "role"
+	AggregateInstallment   = "installment"
+	AggregateInvoice       = "invoice"
+)
+
+// Tipos de evento.
+const (
+	TypeUserCreated = "user.created"
+	TypeUserUpdated = "user.updated"
+	TypeUserDeleted = "user.deleted"
+
+	TypeCompanyGlobalCreated  = "company_global.created"
+	TypeCompanyGlobalUpdated  = "company_global.updated"
+	TypeCompanyGlobalDeleted  = "company_global.deleted"
+	TypeCompanyGlobalRestored = "company_global.restored"
+
+	TypeRoleCreated = "role.created"
+	TypeRoleUpdated = "role.updated"
+	TypeRoleDeleted = "role.deleted"
+
+	TypeInstallmentPaid = "installment.paid"
+
+	TypeInvoiceIssued    = "invoice.issued"
+	TypeInvoiceCancelled = "invoice.cancelled"
+	TypeInvoiceReturned  = "invoice.returned"
+)
+
+func init() {
+	register[UserCreated]()
+	register[UserUpdated]()
+	register[UserDeleted]()
+	register[CompanyGlobalCreated]()
+	register[CompanyGlobalUpdated]()
+	register[CompanyGlobalDeleted]()
+	register[CompanyGlobalRestored]()
+	register[RoleCreated]()
+	register[RoleUpdated]()
+	register[RoleDeleted]()
+	register[InstallmentPaid]()
+	register[InvoiceIssued]()
+	register[InvoiceCancelled]()
+	register[InvoiceReturned]()
+}
+
+// Deleted é o conteúdo dos eventos de exclusão: o ID do registro apagado e a empresa dona dele.
+type Deleted struct {
+	ID              util.SnowflakeID `json:"id"`
+	CompanyGlobalID util.SnowflakeID `json:"companyGlobalId"`
+}
+
+// UserCreated traz o usuário como a API o devolve, sem a senha.
+type UserCreated struct{ dto.UserDTO }
+
+func (UserCreated) EventType() string            { return TypeUserCreated }
+func (e UserCreated) Aggregate() (string, int64) { return AggregateUser, e.ID.Int64() }
+func (e UserCreated) Tenant() int64              { return e.CompanyGlobalID.Int64() }
+
+// UserUpdated traz o usuário depois da alteração.
+type UserUpdated struct{ dto.UserDTO }
+
+func (UserUpdated) EventType() string            { return TypeUserUpdated }
+func (e UserUpdated) Aggregate() (string, int64) { return AggregateUser, e.ID.Int64() }
+func (e UserUpdated) Tenant() int64              { return e.CompanyGlobalID.Int64() }
+
+type UserDeleted struct{ Deleted }
+
+func (UserDeleted) EventType() string            { return TypeUserDeleted }
+func (e UserDeleted) Aggregate() (string, int64) { return AggregateUser, e.ID.Int64() }
+func (e UserDeleted) Tenant() int64              { return e.CompanyGlobalID.Int64() }
+
+// CompanyGlobalCreated traz a empresa com os endereços e contatos.
+type CompanyGlobalCreated struct{ dto.CompanyGlobalDTO }
+
+func (CompanyGlobalCreated) EventType() string { return TypeCompanyGlobalCreated }
+func (e CompanyGlobalCreated) Aggregate() (string, int64) {
+	return AggregateCompanyGlobal, e.ID.Int64()
+}
+func (e CompanyGlobalCreated) Tenant() int64 { return e.ID.Int64() }
+
+type CompanyGlobalUpdated struct{ dto.CompanyGlobalDTO }
+
+func (CompanyGlobalUpdated) EventType() string { return TypeCompanyGlobalUpdated }
+func (e CompanyGlobalUpdated) Aggregate() (string, int64) {
+	return AggregateCompanyGlobal, e.ID.Int64()
+}
+func (e CompanyGlobalUpdated) Tenant() int64 { return e.ID.Int64() }
+
+type CompanyGlobalDeleted struct{ Deleted }
+
+func (CompanyGlobalDeleted) EventType() string { return TypeCompanyGlobalDeleted }
+func (e CompanyGlobalDeleted) Aggregate() (string, int64) {
+	return AggregateCompanyGlobal, e.ID.Int64()
+}
+func (e CompanyGlobalDeleted) Tenant() int64 { return e.ID.Int64() }
+
+// CompanyGlobalRestored traz a empresa depois de desfeita a exclusão.
+type CompanyGlobalRestored struct{ dto.CompanyGlobalDTO }
+
+func (CompanyGlobalRestored) EventType() string { return TypeCompanyGlobalRestored }
+func (e CompanyGlobalRestored) Aggregate() (string, int64) {
+	return AggregateCompanyGlobal, e.ID.Int64()
+}
+func (e CompanyGlobalRestored) Tenant() int64 { return e.ID.Int64() }
+
+// RoleCreated traz a role com as permissões.
+type RoleCreated struct{ dto.RoleDTO }
+
+func (RoleCreated) EventType() string            { return TypeRoleCreated }
+func (e RoleCreated) Aggregate() (string, int64) { return AggregateRole, e.ID.Int64() }
+func (e RoleCreated) Tenant() int64              { return e.CompanyGlobalID.Int64() }
+
+type RoleUpdated struct{ dto.RoleDTO }
+
+func (RoleUpdated) EventType() string            { return TypeRoleUpdated }
+func (e RoleUpdated) Aggregate() (string, int64) { return AggregateRole, e.ID.Int64() }
+func (e RoleUpdated) Tenant() int64              { return e.CompanyGlobalID.Int64() }
+
+type RoleDeleted struct{ Deleted }
+
+func (RoleDeleted) EventType() string            { return TypeRoleDeleted }
+func (e RoleDeleted) Aggregate() (string, int64) { return AggregateRole, e.ID.Int64() }
+func (e RoleDeleted) Tenant() int64              { return e.CompanyGlobalID.Int64() }
+
+// InstallmentPaid traz a parcela depois da baixa, com o meio e a referência do pagamento.
+type InstallmentPaid struct{ dto.InstallmentDTO }
+
+func (InstallmentPaid) EventType() string            { return TypeInstallmentPaid }
+func (e InstallmentPaid) Aggregate() (string, int64) { return AggregateInstallment, e.ID.Int64() }
+func (e InstallmentPaid) Tenant() int64              { return e.CompanyGlobalID.Int64() }
+
+// InvoiceItem é um item da nota, com o que o cálculo da comissão usa. Discount é o desconto do item
+// (não unitário).
+type InvoiceItem struct {
+	Category  *string         `json:"category,omitempty"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	UnitPrice decimal.Decimal `json:"unitPrice"`
+	Discount  decimal.Decimal `json:"discount"`
+}
+
+// InvoiceIssued é publicado pelo faturamento quando a nota de uma venda é emitida. SellerID é o
+// vendedor, um usuário da empresa; vendas sem vendedor não o trazem. IssueDate é 2006-01-02.
+type InvoiceIssued struct {
+	ID              util.SnowflakeID  `json:"id"`
+	CompanyGlobalID util.SnowflakeID  `json:"companyGlobalId"`
+	Number          string            `json:"number"`
+	IssueDate       string            `json:"issueDate"`
+	SellerID        *util.SnowflakeID `json:"sellerId,omitempty"`
+	Items           []InvoiceItem     `json:"items"`
+}
+
+func (InvoiceIssued) EventType() string            { return TypeInvoiceIssued }
+func (e InvoiceIssued) Aggregate() (string, int64) { return AggregateInvoice, e.ID.Int64() }
+func (e InvoiceIssued) Tenant() int64              { return e.CompanyGlobalID.Int64() }
+
+// InvoiceCancelled é publicado quando a nota é cancelada. Date é o dia do cancelamento (2006-01-02).
+type InvoiceCancelled struct {
+	ID              util.SnowflakeID `json:"id"`
+	CompanyGlobalID util.SnowflakeID `json:"companyGlobalId"`
+	Number          string           `json:"number"`
+	Date            string           `json:"date"`
+}
+
+func (InvoiceCancelled) EventType() string            { return TypeInvoiceCancelled }
+func (e InvoiceCancelled) Aggregate() (string, int64) { return AggregateInvoice, e.ID.Int64() }
+func (e InvoiceCancelled) Tenant() int64              { return e.CompanyGlobalID.Int64() }
+
+// InvoiceReturned é publicado a cada devolução de mercadorias da nota. BaseAmount é o valor devolvido,
+// já sem os descontos.
+type InvoiceReturned struct {
+	ID              util.SnowflakeID `json:"id"`
+	CompanyGlobalID util.SnowflakeID `json:"companyGlobalId"`
+	Number          string           `json:"number"`
+	Date            string           `json:"date"`
+	BaseAmount      decimal.Decimal  `json:"baseAmount"`
+}
+
+func (InvoiceReturned) EventType() string            { return TypeInvoiceReturned }
+func (e InvoiceReturned) Aggregate() (string, int64) { return AggregateInvoice, e.ID.Int64() }
+func (e InvoiceReturned) Tenant() int64              { return e.CompanyGlobalID.Int64() }
@@ -0,0 +1,117 @@
+package nfe
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-sales/pkg/util"
+)
+
+// ErrInvalidAccessKey é retornado por ParseAccessKey quando a chave não tem 44 dígitos ou o dígito
+// verificador não confere.
+var ErrInvalidAccessKey = errors.New("nfe: invalid access key")
+
+// AccessKey é a chave de acesso da NF-e: os 43 dígitos que identificam o documento mais o dígito
+// verificador (módulo 11).
+type AccessKey struct {
+	// UF é o código IBGE da UF do emitente (ex: 35 para SP).
+	UF int
+	// IssuedAt é a data de emissão; só o ano e o mês (AAMM) entram na chave.
+	IssuedAt time.Time
+	// IssuerDocument é o CNPJ (ou CPF) do emitente, só com os dígitos.
+	IssuerDocument string
+	Model          int
+	Series         int
+	Number         int
+	EmissionType   int
+	// Code é o código numérico aleatório (cNF) que impede que a chave seja deduzida pelo número.
+	Code int
+}
+
+// digits devolve os 43 dígitos da chave, sem o dígito verificador.
+func (k AccessKey) digits() string {
+	document := util.OnlyDigits(k.IssuerDocument)
+	if len(document) < 14 {
+		document = strings.Repeat("0", 14-len(document)) + document
+	}
+	return fmt.Sprintf("%02d%s%s%02d%03d%09d%d%08d",
+		k.UF, k.IssuedAt.Format("0601"), document,
+		k.Model, k.Series, k.Number, k.EmissionType, k.Code)
+}
+
+// CheckDigit é o dígito verificador (cDV) da chave.
+func (k AccessKey) CheckDigit() int {
+	return CheckDigit(k.digits())
+}
+
+// String devolve os 44 dígitos da chave.
+func (k AccessKey) String() string {
+	digits := k.digits()
+	return digits + strconv.Itoa(CheckDigit(digits))
+}
+
+// ID é o atributo Id do infNFe ("NFe" seguido da chave).
+func (k AccessKey) ID() string {
+	return "NFe" + k.String()
+}
+
+// CheckDigit calcula o dígito verificador módulo 11 da chave: os dígitos são multiplicados pelos
+// pesos 2 a 9, da direita para a esquerda e recomeçando em 2 depois do 9. Restos 0 e 1 dão dígito 0.
+func CheckDigit(digits string) int {
+	sum, weight := 0, 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+	rest := sum % 11
+	if rest < 2 {
+		return 0
+	}
+	return 11 - rest
+}
+
+// ParseAccessKey lê uma chave de 44 dígitos e confere o dígito verificador. IssuedAt fica no dia 1
+// do mês da chave, em UTC.
+func ParseAccessKey(key string) (AccessKey, error) {
+	if len(key) != 44 || util.OnlyDigits(key) != key {
+		return AccessKey{}, ErrInvalidAccessKey
+	}
+	if CheckDigit(key[:43]) != int(key[43]-'0') {
+		return AccessKey{}, ErrInvalidAccessKey
+	}
+	atoi := func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	return AccessKey{
+		UF:             atoi(key[0:2]),
+		IssuedAt:       time.Date(2000+atoi(key[2:4]), time.Month(atoi(key[4:6])), 1, 0, 0, 0, 0, time.UTC),
+		IssuerDocument: key[6:20],
+		Model:          atoi(key[20:22]),
+		Series:         atoi(key[22:25]),
+		Number:         atoi(key[25:34]),
+		EmissionType:   atoi(key[34:35]),
+		Code:           atoi(key[35:43]),
+	}, nil
+}
+
+// NewCode sorteia o código numérico (cNF) de 8 dígitos. A SEFAZ rejeita um cNF igual ao número da nota.
+func NewCode(number int) (int, error) {
+	for {
+		n, err := rand.Int(rand.Reader, big.NewInt(100_000_000))
+		if err != nil {
+			return 0, err
+		}
+		if code := int(n.Int64()); code != number {
+			return code, nil
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package nfe
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// publishedKey é a chave do exemplo de cálculo do dígito verificador do Manual de Orientação do
+// Contribuinte: soma ponderada 644, resto 6, DV 5. É do leiaute antigo, com cNF de 9 dígitos, que no
+// leiaute 4.00 se lê como tpEmis 0 seguido de um cNF de 8.
+const publishedKey = "52060433009911002506550120000007800267301615"
+
+func TestCheckDigitOfThePublishedKey(t *testing.T) {
+	if got := CheckDigit(publishedKey[:43]); got != 5 {
+		t.Fatalf("CheckDigit = %d, want 5", got)
+	}
+}
+
+func TestParseAccessKey(t *testing.T) {
+	key, err := ParseAccessKey(publishedKey)
+	if err != nil {
+		t.Fatalf("ParseAccessKey: %v", err)
+	}
+	want := AccessKey{
+		UF:             52,
+		IssuedAt:       time.Date(2006, time.April, 1, 0, 0, 0, 0, time.UTC),
+		IssuerDocument: "33009911002506",
+		Model:          55,
+		Series:         12,
+		Number:         780,
+		EmissionType:   0,
+		Code:           26730161,
+	}
+	if key != want {
+		t.Fatalf("ParseAccessKey = %+v, want %+v", key, want)
+	}
+	if key.String() != publishedKey || key.CheckDigit() != 5 {
+		t.Fatalf("String = %s, CheckDigit = %d, want the published key back", key.String(), key.CheckDigit())
+	}
+}
+
+func TestParseAccessKeyRejects(t *testing.T) {
+	for _, key := range []string{
+		"52060433009911002506550120000007800267301614", // DV errado
+		"52060433009911002506550120000007800267301625", // um dígito trocado
+		"5206043300991100250655012000000780026730161",  // 43 dígitos
+		"5206043300991100250655012000000780026730161X",
+	} {
+		if _, err := ParseAccessKey(key); !errors.Is(err, ErrInvalidAccessKey) {
+			t.Errorf("ParseAccessKey(%s) = %v, want ErrInvalidAccessKey", key, err)
+		}
+	}
+}
+
+// Restos 0 e 1 dão DV 0.
+func TestCheckDigitLowRests(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   int
+	}{
+		{"0000000000000000000000000000000000000000000", 0}, // resto 0
+		{"0000000000000000000000000000000000000000006", 0}, // 12 = resto 1
+		{"0000000000000000000000000000000000000000001", 9}, // 2 = resto 2
+	}
+	for _, tt := range tests {
+		if got := CheckDigit(tt.digits); got != tt.want {
+			t.Errorf("CheckDigit(%s) = %d, want %d", tt.digits, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,711 @@
+package nfe
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-sales/pkg/decimal"
+	"go-sales/pkg/util"
+)
+
+// Ambientes de emissão (tpAmb).
+const (
+	EnvironmentProduction   = 1
+	EnvironmentHomologation = 2
+)
+
+// Regimes tributários do emitente (CRT).
+const (
+	TaxRegimeSimples       = 1
+	TaxRegimeSimplesExcess = 2
+	TaxRegimeNormal        = 3
+	TaxRegimeSimplesMEI    = 4
+)
+
+// Indicadores da inscrição estadual do destinatário (indIEDest).
+const (
+	IEContributor    = 1
+	IEExempt         = 2
+	IENonContributor = 9
+)
+
+// Modalidades do frete (modFrete).
+const (
+	FreightByIssuer   = 0
+	FreightByCustomer = 1
+	FreightNone       = 9
+)
+
+const (
+	// ModelNFe é o modelo 55 (NF-e). O modelo 65 (NFC-e) não é gerado.
+	ModelNFe = 55
+	// EmissionNormal é a emissão normal (tpEmis 1), sem contingência.
+	EmissionNormal = 1
+
+	// homologationName substitui o nome do destinatário em homologação, como exige a SEFAZ.
+	homologationName = "NF-E EMITIDA EM AMBIENTE DE HOMOLOGACAO - SEM VALOR FISCAL"
+	// withoutGTIN é o cEAN dos produtos sem código de barras.
+	withoutGTIN = "SEM GTIN"
+	// brazilCode e brazilName são o código e o nome do Brasil na tabela de países do BACEN.
+	brazilCode = "1058"
+	brazilName = "BRASIL"
+)
+
+// Invoice reúne os dados de uma venda para a emissão da NF-e: o pedido, o cliente, o emitente e os
+// dados fiscais dos produtos.
+type Invoice struct {
+	Environment int
+	Series      int
+	Number      int
+	// Code é o cNF. Zero sorteia um código novo (ver NewCode).
+	Code     int
+	IssuedAt time.Time
+	// Operation é a natureza da operação (natOp, ex: "VENDA DE MERCADORIA").
+	Operation string
+	// FinalConsumer indica venda a consumidor final (indFinal).
+	FinalConsumer bool
+	// Presence é o indicador de presença do comprador (indPres: 1 presencial, 2 internet, 9 outros...).
+	Presence int
+	// Purpose é a finalidade (finNFe: 1 normal, 2 complementar, 3 ajuste, 4 devolução). Zero é 1.
+	Purpose     int
+	FreightMode int
+	// OrderReference vai em xPed de todos os itens (ex: o número do pedido do cliente).
+	OrderReference string
+	AdditionalInfo string
+	// Software identifica o aplicativo emissor (verProc).
+	Software string
+
+	Issuer   Issuer
+	Customer Customer
+	Items    []Item
+	Payments []Payment
+}
+
+// Issuer é o emitente.
+type Issuer struct {
+	CNPJ                  string
+	Name                  string
+	TradeName             string
+	StateRegistration     string
+	MunicipalRegistration string
+	CNAE                  string
+	TaxRegime             int
+	Address               Address
+}
+
+// Address é um endereço no Brasil. CityCode é o código IBGE do município.
+type Address struct {
+	Street     string
+	Number     string
+	Complement string
+	District   string
+	CityCode   int
+	City       string
+	UF         string
+	PostalCode string
+	Phone      string
+}
+
+// Customer é o destinatário. Document é o CPF ou o CNPJ, só com os dígitos.
+type Customer struct {
+	Document string
+	Name     string
+	// IEIndicator é o indIEDest; StateRegistration só é informada para contribuintes (1).
+	IEIndicator       int
+	StateRegistration string
+	Email             string
+	Address           *Address
+}
+
+// Item é um item do pedido com os dados fiscais do produto.
+type Item struct {
+	Code        string
+	GTIN        string
+	Description string
+	NCM         string
+	CEST        string
+	CFOP        string
+	Unit        string
+	Quantity    decimal.Decimal
+	UnitPrice   decimal.Decimal
+	Freight     decimal.Decimal
+	Insurance   decimal.Decimal
+	Discount    decimal.Decimal
+	OtherCosts  decimal.Decimal
+	Tax         ItemTax
+	// AdditionalInfo vai em infAdProd.
+	AdditionalInfo string
+}
+
+// ItemTax é a tributação do item. Os valores (base e imposto) são calculados por Build a partir das alíquotas.
+type ItemTax struct {
+	// Origin é a origem da mercadoria (orig: 0 nacional, 1 estrangeira de importação direta...).
+	Origin int
+	// ICMSCode é o CST (2 dígitos, regime normal) ou o CSOSN (3 dígitos, Simples Nacional).
+	ICMSCode string
+	ICMSRate decimal.Decimal
+	// ICMSBaseReduction é o percentual de redução da base do CST 20.
+	ICMSBaseReduction decimal.Decimal
+	// SimplesCreditRate é a alíquota de crédito do CSOSN 101.
+	SimplesCreditRate decimal.Decimal
+	// IPICode vazio omite o grupo IPI.
+	IPICode string
+	// IPIEnquadramento é o código de enquadramento legal do IPI (cEnq). Vazio é 999.
+	IPIEnquadramento string
+	IPIRate          decimal.Decimal
+	PISCode          string
+	PISRate          decimal.Decimal
+	COFINSCode       string
+	COFINSRate       decimal.Decimal
+}
+
+// Payment é uma forma de pagamento. Method é o tPag (01 dinheiro, 03 cartão de crédito, 15 boleto,
+// 17 PIX, 90 sem pagamento, 99 outros).
+type Payment struct {
+	Method      string
+	Description string
+	Amount      decimal.Decimal
+	// Deferred marca pagamento a prazo (indPag 1).
+	Deferred bool
+}
+
+// ValidationError lista os problemas encontrados nos dados da nota, antes da geração do XML.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "nfe: invalid invoice: " + strings.Join(e.Problems, "; ")
+}
+
+// validator acumula os problemas de validação.
+type validator []string
+
+func (v *validator) check(ok bool, format string, args ...any) {
+	if !ok {
+		*v = append(*v, fmt.Sprintf(format, args...))
+	}
+}
+
+func (v validator) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: v}
+}
+
+// Document é a NF-e montada, ainda sem assinatura.
+type Document struct {
+	NFe       *NFe
+	AccessKey AccessKey
+	// Total é o valor da nota (vNF).
+	Total decimal.Decimal
+}
+
+// Build valida a venda, calcula os impostos e totais e monta a NF-e. Os valores monetários são
+// arredondados para 2 casas em cada item, e os totais são a soma dos itens já arredondados, como a
+// SEFAZ confere.
+func Build(invoice Invoice) (*Document, error) {
+	var v validator
+	validateInvoice(&v, invoice)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	code := invoice.Code
+	if code == 0 {
+		var err error
+		if code, err = NewCode(invoice.Number); err != nil {
+			return nil, err
+		}
+	}
+	issuerAddress := invoice.Issuer.Address
+	key := AccessKey{
+		UF:             issuerAddress.CityCode / 100000,
+		IssuedAt:       invoice.IssuedAt,
+		IssuerDocument: invoice.Issuer.CNPJ,
+		Model:          ModelNFe,
+		Series:         invoice.Series,
+		Number:         invoice.Number,
+		EmissionType:   EmissionNormal,
+		Code:           code,
+	}
+
+	var totals totals
+	dets := make([]Det, len(invoice.Items))
+	for i, item := range invoice.Items {
+		det, err := buildDet(i+1, item, invoice.OrderReference, &totals)
+		if err != nil {
+			return nil, err
+		}
+		dets[i] = det
+	}
+	total := totals.invoiceTotal()
+
+	pag, err := buildPag(invoice.Payments, total)
+	if err != nil {
+		return nil, err
+	}
+
+	purpose := invoice.Purpose
+	if purpose == 0 {
+		purpose = 1
+	}
+	nfe := &NFe{InfNFe: InfNFe{
+		Versao: Version,
+		ID:     key.ID(),
+		Ide: Ide{
+			CUF:      strconv.Itoa(key.UF),
+			CNF:      fmt.Sprintf("%08d", code),
+			NatOp:    clean(invoice.Operation, 60),
+			Mod:      strconv.Itoa(ModelNFe),
+			Serie:    strconv.Itoa(invoice.Series),
+			NNF:      strconv.Itoa(invoice.Number),
+			DhEmi:    formatDateTime(invoice.IssuedAt),
+			TpNF:     "1",
+			IdDest:   destination(invoice),
+			CMunFG:   strconv.Itoa(issuerAddress.CityCode),
+			TpImp:    "1",
+			TpEmis:   strconv.Itoa(EmissionNormal),
+			CDV:      strconv.Itoa(key.CheckDigit()),
+			TpAmb:    strconv.Itoa(invoice.Environment),
+			FinNFe:   strconv.Itoa(purpose),
+			IndFinal: boolFlag(invoice.FinalConsumer),
+			IndPres:  strconv.Itoa(invoice.Presence),
+			ProcEmi:  "0",
+			VerProc:  clean(invoice.Software, 20),
+		},
+		Emit:   buildEmit(invoice.Issuer),
+		Dest:   buildDest(invoice.Customer, invoice.Environment),
+		Det:    dets,
+		Total:  Total{ICMSTot: totals.icmsTot(total)},
+		Transp: Transp{ModFrete: strconv.Itoa(invoice.FreightMode)},
+		Pag:    pag,
+	}}
+	// indIntermed é obrigatório nas vendas não presenciais; a venda é sempre feita no próprio site.
+	if invoice.Presence >= 2 && invoice.Presence <= 5 || invoice.Presence == 9 {
+		nfe.InfNFe.Ide.IndIntermed = "0"
+	}
+	if info := clean(invoice.AdditionalInfo, 5000); info != "" {
+		nfe.InfNFe.InfAdic = &InfAdic{InfCpl: info}
+	}
+	return &Document{NFe: nfe, AccessKey: key, Total: total}, nil
+}
+
+func validateInvoice(v *validator, invoice Invoice) {
+	v.check(invoice.Environment == EnvironmentProduction || invoice.Environment == EnvironmentHomologation, "environment must be 1 or 2")
+	v.check(invoice.Series >= 0 && invoice.Series <= 999, "series must be between 0 and 999")
+	v.check(invoice.Number >= 1 && invoice.Number <= 999_999_999, "number must be between 1 and 999999999")
+	v.check(!invoice.IssuedAt.IsZero(), "issue date is required")
+	v.check(clean(invoice.Operation, 60) != "", "operation (natOp) is required")
+	v.check(invoice.Presence >= 0 && invoice.Presence <= 9, "presence indicator is invalid")
+	v.check(invoice.Purpose >= 0 && invoice.Purpose <= 4, "purpose must be between 1 and 4")
+	v.check(invoice.FreightMode >= 0 && invoice.FreightMode <= 4 || invoice.FreightMode == FreightNone, "freight mode is invalid")
+
+	issuer := invoice.Issuer
+	v.check(len(util.OnlyDigits(issuer.CNPJ)) == 14, "issuer CNPJ must have 14 digits")
+	v.check(strings.TrimSpace(issuer.Name) != "", "issuer name is required")
+	v.check(strings.TrimSpace(issuer.StateRegistration) != "", "issuer state registration (IE) is required")
+	v.check(issuer.TaxRegime >= TaxRegimeSimples && issuer.TaxRegime <= TaxRegimeSimplesMEI, "issuer tax regime (CRT) must be between 1 and 4")
+	validateAddress(v, "issuer", issuer.Address)
+
+	customer := invoice.Customer
+	document := util.OnlyDigits(customer.Document)
+	v.check(len(document) == 11 || len(document) == 14, "customer document must be a CPF (11 digits) or a CNPJ (14 digits)")
+	v.check(strings.TrimSpace(customer.Name) != "", "customer name is required")
+	v.check(customer.IEIndicator == IEContributor || customer.IEIndicator == IEExempt || customer.IEIndicator == IENonContributor, "customer IE indicator must be 1, 2 or 9")
+	v.check(customer.IEIndicator != IEContributor || strings.TrimSpace(customer.StateRegistration) != "", "customer state registration (IE) is required for contributors")
+	if customer.Address != nil {
+		validateAddress(v, "customer", *customer.Address)
+	}
+
+	v.check(len(invoice.Items) > 0, "at least one item is required")
+	v.check(len(invoice.Items) <= 990, "an NF-e has at most 990 items")
+	for i, item := range invoice.Items {
+		validateItem(v, i+1, item, issuer.TaxRegime)
+	}
+	v.check(len(invoice.Payments) > 0, "at least one payment is required")
+	for i, payment := range invoice.Payments {
+		v.check(len(payment.Method) == 2 && util.OnlyDigits(payment.Method) == payment.Method, "payment %d: method (tPag) must have 2 digits", i+1)
+		v.check(payment.Amount.Sign() >= 0, "payment %d: amount cannot be negative", i+1)
+	}
+}
+
+func validateAddress(v *validator, who string, address Address) {
+	v.check(strings.TrimSpace(address.Street) != "", "%s address: street is required", who)
+	v.check(strings.TrimSpace(address.District) != "", "%s address: district is required", who)
+	v.check(address.CityCode >= 1000000 && address.CityCode <= 9999999, "%s address: IBGE city code is required", who)
+	v.check(util.IsValidUF(address.UF), "%s address: invalid UF", who)
+	v.check(len(util.OnlyDigits(address.PostalCode)) == 8, "%s address: postal code must have 8 digits", who)
+}
+
+func validateItem(v *validator, n int, item Item, taxRegime int) {
+	v.check(strings.TrimSpace(item.Code) != "", "item %d: code is required", n)
+	v.check(strings.TrimSpace(item.Description) != "", "item %d: description is required", n)
+	v.check(len(item.NCM) == 8 && util.OnlyDigits(item.NCM) == item.NCM, "item %d: NCM must have 8 digits", n)
+	v.check(item.CEST == "" || len(item.CEST) == 7 && util.OnlyDigits(item.CEST) == item.CEST, "item %d: CEST must have 7 digits", n)
+	v.check(len(item.CFOP) == 4 && util.OnlyDigits(item.CFOP) == item.CFOP && strings.ContainsAny(item.CFOP[:1], "567"), "item %d: CFOP must be an outgoing code with 4 digits (5xxx, 6xxx or 7xxx)", n)
+	v.check(item.GTIN == "" || validGTIN(item.GTIN), "item %d: invalid GTIN", n)
+	v.check(strings.TrimSpace(item.Unit) != "", "item %d: unit is required", n)
+	v.check(item.Quantity.Sign() > 0, "item %d: quantity must be positive", n)
+	v.check(item.UnitPrice.Sign() >= 0, "item %d: unit price cannot be negative", n)
+	for name, value := range map[string]decimal.Decimal{"freight": item.Freight, "insurance": item.Insurance, "discount": item.Discount, "other costs": item.OtherCosts} {
+		v.check(value.Sign() >= 0, "item %d: %s cannot be negative", n, name)
+	}
+
+	tax := item.Tax
+	v.check(tax.Origin >= 0 && tax.Origin <= 8, "item %d: origin must be between 0 and 8", n)
+	if taxRegime == TaxRegimeSimples || taxRegime == TaxRegimeSimplesMEI {
+		v.check(supportedCSOSN[tax.ICMSCode], "item %d: unsupported ICMS CSOSN %q", n, tax.ICMSCode)
+	} else {
+		v.check(supportedICMSCST[tax.ICMSCode], "item %d: unsupported ICMS CST %q", n, tax.ICMSCode)
+	}
+	v.check(tax.IPICode == "" || ipiTaxedCST[tax.IPICode] || ipiExemptCST[tax.IPICode], "item %d: unsupported IPI CST %q", n, tax.IPICode)
+	v.check(pisTaxedCST[tax.PISCode] || pisExemptCST[tax.PISCode] || pisOtherCST(tax.PISCode), "item %d: unsupported PIS CST %q", n, tax.PISCode)
+	v.check(pisTaxedCST[tax.COFINSCode] || pisExemptCST[tax.COFINSCode] || pisOtherCST(tax.COFINSCode), "item %d: unsupported COFINS CST %q", n, tax.COFINSCode)
+}
+
+// CSTs e CSOSNs aceitos por Build.
+var (
+	supportedICMSCST = map[string]bool{"00": true, "20": true, "40": true, "41": true, "50": true}
+	supportedCSOSN   = map[string]bool{"101": true, "102": true, "103": true, "300": true, "400": true}
+	ipiTaxedCST      = map[string]bool{"00": true, "49": true, "50": true, "99": true}
+	ipiExemptCST     = map[string]bool{"01": true, "02": true, "03": true, "04": true, "05": true, "51": true, "52": true, "53": true, "54": true, "55": true}
+	pisTaxedCST      = map[string]bool{"01": true, "02": true}
+	pisExemptCST     = map[string]bool{"04": true, "05": true, "06": true, "07": true, "08": true, "09": true}
+)
+
+// pisOtherCST aceita os CSTs 49 a 99 do PIS e da COFINS, informados no grupo Outr.
+func pisOtherCST(cst string) bool {
+	n, err := strconv.Atoi(cst)
+	return err == nil && len(cst) == 2 && n >= 49 && n <= 99
+}
+
+// validGTIN confere o dígito verificador de um GTIN-8, 12, 13 ou 14.
+func validGTIN(gtin string) bool {
+	if util.OnlyDigits(gtin) != gtin {
+		return false
+	}
+	switch len(gtin) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+	sum := 0
+	for i := len(gtin) - 2; i >= 0; i-- {
+		digit := int(gtin[i] - '0')
+		if (len(gtin)-2-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return (10-sum%10)%10 == int(gtin[len(gtin)-1]-'0')
+}
+
+// totals acumula os totais da nota a partir dos valores já arredondados de cada item.
+type totals struct {
+	products, freight, insurance, discount, other decimal.Decimal
+	icmsBase, icms, ipi, pis, cofins              decimal.Decimal
+}
+
+// invoiceTotal é o vNF: produtos - desconto + frete + seguro + outras despesas + IPI.
+func (t *totals) invoiceTotal() decimal.Decimal {
+	return decimal.Sum(t.products, t.freight, t.insurance, t.other, t.ipi).Sub(t.discount)
+}
+
+func (t *totals) icmsTot(total decimal.Decimal) ICMSTot {
+	zero := money(decimal.Zero)
+	return ICMSTot{
+		VBC:        money(t.icmsBase),
+		VICMS:      money(t.icms),
+		VICMSDeson: zero,
+		VFCP:       zero,
+		VBCST:      zero,
+		VST:        zero,
+		VFCPST:     zero,
+		VFCPSTRet:  zero,
+		VProd:      money(t.products),
+		VFrete:     money(t.freight),
+		VSeg:       money(t.insurance),
+		VDesc:      money(t.discount),
+		VII:        zero,
+		VIPI:       money(t.ipi),
+		VIPIDevol:  zero,
+		VPIS:       money(t.pis),
+		VCOFINS:    money(t.cofins),
+		VOutro:     money(t.other),
+		VNF:        money(total),
+	}
+}
+
+func buildDet(n int, item Item, orderReference string, t *totals) (Det, error) {
+	gtin := item.GTIN
+	if gtin == "" {
+		gtin = withoutGTIN
+	}
+	unit := clean(item.Unit, 6)
+	quantity := item.Quantity.Round(4)
+	unitPrice := item.UnitPrice.Round(10)
+	value := quantity.Mul(unitPrice).Round(2)
+	freight, insurance := item.Freight.Round(2), item.Insurance.Round(2)
+	discount, other := item.Discount.Round(2), item.OtherCosts.Round(2)
+	if discount.Cmp(value) > 0 {
+		return Det{}, &ValidationError{Problems: []string{fmt.Sprintf("item %d: discount is greater than the item value", n)}}
+	}
+
+	prod := Prod{
+		CProd:    clean(item.Code, 60),
+		CEAN:     gtin,
+		XProd:    clean(item.Description, 120),
+		NCM:      item.NCM,
+		CEST:     item.CEST,
+		CFOP:     item.CFOP,
+		UCom:     unit,
+		QCom:     quantity.StringFixed(4),
+		VUnCom:   unitPrice.String(),
+		VProd:    money(value),
+		CEANTrib: gtin,
+		UTrib:    unit,
+		QTrib:    quantity.StringFixed(4),
+		VUnTrib:  unitPrice.String(),
+		VFrete:   optionalMoney(freight),
+		VSeg:     optionalMoney(insurance),
+		VDesc:    optionalMoney(discount),
+		VOutro:   optionalMoney(other),
+		IndTot:   "1",
+	}
+	if orderReference != "" {
+		prod.XPed = clean(orderReference, 15)
+		prod.NItemPed = strconv.Itoa(n)
+	}
+
+	// A base dos impostos é o valor do item com as despesas acessórias e sem o desconto.
+	base := decimal.Sum(value, freight, insurance, other).Sub(discount)
+	imposto, taxes := buildImposto(item.Tax, base)
+
+	t.products = t.products.Add(value)
+	t.freight = t.freight.Add(freight)
+	t.insurance = t.insurance.Add(insurance)
+	t.discount = t.discount.Add(discount)
+	t.other = t.other.Add(other)
+	t.icmsBase = t.icmsBase.Add(taxes.icmsBase)
+	t.icms = t.icms.Add(taxes.icms)
+	t.ipi = t.ipi.Add(taxes.ipi)
+	t.pis = t.pis.Add(taxes.pis)
+	t.cofins = t.cofins.Add(taxes.cofins)
+
+	return Det{
+		NItem:     strconv.Itoa(n),
+		Prod:      prod,
+		Imposto:   imposto,
+		InfAdProd: clean(item.AdditionalInfo, 500),
+	}, nil
+}
+
+// itemTaxes são os valores calculados dos impostos de um item.
+type itemTaxes struct {
+	icmsBase, icms, ipi, pis, cofins decimal.Decimal
+}
+
+func buildImposto(tax ItemTax, base decimal.Decimal) (Imposto, itemTaxes) {
+	var taxes itemTaxes
+	var imposto Imposto
+	orig := strconv.Itoa(tax.Origin)
+
+	switch tax.ICMSCode {
+	case "00":
+		taxes.icmsBase = base
+		taxes.icms = base.Percent(tax.ICMSRate).Round(2)
+		imposto.ICMS.ICMS00 = &ICMS00{Orig: orig, CST: "00", ModBC: "3", VBC: money(base), PICMS: rate(tax.ICMSRate), VICMS: money(taxes.icms)}
+	case "20":
+		taxes.icmsBase = base.Sub(base.Percent(tax.ICMSBaseReduction)).Round(2)
+		taxes.icms = taxes.icmsBase.Percent(tax.ICMSRate).Round(2)
+		imposto.ICMS.ICMS20 = &ICMS20{Orig: orig, CST: "20", ModBC: "3", PRedBC: rate(tax.ICMSBaseReduction), VBC: money(taxes.icmsBase), PICMS: rate(tax.ICMSRate), VICMS: money(taxes.icms)}
+	case "40", "41", "50":
+		imposto.ICMS.ICMS40 = &ICMS40{Orig: orig, CST: tax.ICMSCode}
+	case "101":
+		credit := base.Percent(tax.SimplesCreditRate).Round(2)
+		imposto.ICMS.ICMSSN101 = &ICMSSN101{Orig: orig, CSOSN: "101", PCredSN: rate(tax.SimplesCreditRate), VCredICMSSN: money(credit)}
+	default:
+		imposto.ICMS.ICMSSN102 = &ICMSSN102{Orig: orig, CSOSN: tax.ICMSCode}
+	}
+
+	if tax.IPICode != "" {
+		enq := tax.IPIEnquadramento
+		if enq == "" {
+			enq = "999"
+		}
+		imposto.IPI = &IPI{CEnq: enq}
+		if ipiTaxedCST[tax.IPICode] {
+			taxes.ipi = base.Percent(tax.IPIRate).Round(2)
+			imposto.IPI.IPITrib = &IPITrib{CST: tax.IPICode, VBC: money(base), PIPI: rate(tax.IPIRate), VIPI: money(taxes.ipi)}
+		} else {
+			imposto.IPI.IPINT = &IPINT{CST: tax.IPICode}
+		}
+	}
+
+	switch {
+	case pisTaxedCST[tax.PISCode]:
+		taxes.pis = base.Percent(tax.PISRate).Round(2)
+		imposto.PIS.PISAliq = &PISAliq{CST: tax.PISCode, VBC: money(base), PPIS: rate(tax.PISRate), VPIS: money(taxes.pis)}
+	case pisExemptCST[tax.PISCode]:
+		imposto.PIS.PISNT = &PISNT{CST: tax.PISCode}
+	default:
+		taxes.pis = base.Percent(tax.PISRate).Round(2)
+		imposto.PIS.PISOutr = &PISOutr{CST: tax.PISCode, VBC: money(base), PPIS: rate(tax.PISRate), VPIS: money(taxes.pis)}
+	}
+
+	switch {
+	case pisTaxedCST[tax.COFINSCode]:
+		taxes.cofins = base.Percent(tax.COFINSRate).Round(2)
+		imposto.COFINS.COFINSAliq = &COFINSAliq{CST: tax.COFINSCode, VBC: money(base), PCOFINS: rate(tax.COFINSRate), VCOFINS: money(taxes.cofins)}
+	case pisExemptCST[tax.COFINSCode]:
+		imposto.COFINS.COFINSNT = &COFINSNT{CST: tax.COFINSCode}
+	default:
+		taxes.cofins = base.Percent(tax.COFINSRate).Round(2)
+		imposto.COFINS.COFINSOutr = &COFINSOutr{CST: tax.COFINSCode, VBC: money(base), PCOFINS: rate(tax.COFINSRate), VCOFINS: money(taxes.cofins)}
+	}
+	return imposto, taxes
+}
+
+// buildPag monta os pagamentos. A soma deve cobrir o total; o que passar dele é o troco (vTroco).
+func buildPag(payments []Payment, total decimal.Decimal) (Pag, error) {
+	var pag Pag
+	paid := decimal.Zero
+	for _, payment := range payments {
+		amount := payment.Amount.Round(2)
+		paid = paid.Add(amount)
+		detPag := DetPag{TPag: payment.Method, VPag: money(amount)}
+		if payment.Deferred {
+			detPag.IndPag = "1"
+		}
+		if payment.Method == "99" {
+			detPag.XPag = clean(payment.Description, 60)
+			if detPag.XPag == "" {
+				detPag.XPag = "OUTROS"
+			}
+		}
+		pag.DetPag = append(pag.DetPag, detPag)
+	}
+	withoutPayment := len(payments) == 1 && payments[0].Method == "90"
+	if !withoutPayment && paid.Cmp(total) < 0 {
+		return Pag{}, &ValidationError{Problems: []string{fmt.Sprintf("payments (%s) do not cover the invoice total (%s)", money(paid), money(total))}}
+	}
+	if change := paid.Sub(total); !withoutPayment && change.Sign() > 0 {
+		pag.VTroco = money(change)
+	}
+	return pag, nil
+}
+
+func buildEmit(issuer Issuer) Emit {
+	emit := Emit{
+		CNPJ:      util.OnlyDigits(issuer.CNPJ),
+		XNome:     clean(issuer.Name, 60),
+		XFant:     clean(issuer.TradeName, 60),
+		EnderEmit: buildAddress(issuer.Address),
+		IE:        util.OnlyDigits(issuer.StateRegistration),
+		CRT:       strconv.Itoa(issuer.TaxRegime),
+	}
+	// CNAE só pode ser informado junto com a inscrição municipal.
+	if im := clean(issuer.MunicipalRegistration, 15); im != "" {
+		emit.IM = im
+		emit.CNAE = util.OnlyDigits(issuer.CNAE)
+	}
+	return emit
+}
+
+func buildDest(customer Customer, environment int) *Dest {
+	dest := &Dest{
+		XNome:     clean(customer.Name, 60),
+		IndIEDest: strconv.Itoa(customer.IEIndicator),
+		Email:     clean(customer.Email, 60),
+	}
+	if environment == EnvironmentHomologation {
+		dest.XNome = homologationName
+	}
+	document := util.OnlyDigits(customer.Document)
+	if len(document) == 14 {
+		dest.CNPJ = document
+	} else {
+		dest.CPF = document
+	}
+	if customer.IEIndicator == IEContributor {
+		dest.IE = util.OnlyDigits(customer.StateRegistration)
+	}
+	if customer.Address != nil {
+		address := buildAddress(*customer.Address)
+		dest.EnderDest = &address
+	}
+	return dest
+}
+
+func buildAddress(address Address) Endereco {
+	number := clean(address.Number, 60)
+	if number == "" {
+		number = "S/N"
+	}
+	return Endereco{
+		XLgr:    clean(address.Street, 60),
+		Nro:     number,
+		XCpl:    clean(address.Complement, 60),
+		XBairro: clean(address.District, 60),
+		CMun:    strconv.Itoa(address.CityCode),
+		XMun:    clean(address.City, 60),
+		UF:      address.UF,
+		CEP:     util.OnlyDigits(address.PostalCode),
+		CPais:   brazilCode,
+		XPais:   brazilName,
+		Fone:    util.OnlyDigits(address.Phone),
+	}
+}
+
+// destination é o idDest: 1 operação interna, 2 interestadual. Sem endereço do destinatário a
+// operação é tratada como interna.
+func destination(invoice Invoice) string {
+	if invoice.Customer.Address != nil && invoice.Customer.Address.UF != invoice.Issuer.Address.UF {
+		return "2"
+	}
+	return "1"
+}
+
+// formatDateTime formata a data no padrão UTC da NF-e (AAAA-MM-DDThh:mm:ssTZD), sem frações de segundo.
+func formatDateTime(t time.Time) string {
+	return t.Truncate(time.Second).Format("2006-01-02T15:04:05-07:00")
+}
+
+func boolFlag(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// money formata valores com 2 casas (TDec_1302).
+func money(d decimal.Decimal) string {
+	return d.StringFixed(2)
+}
+
+// optionalMoney omite valores zerados dos campos opcionais.
+func optionalMoney(d decimal.Decimal) string {
+	if d.IsZero() {
+		return ""
+	}
+	return money(d)
+}
+
+// rate formata alíquotas e percentuais com 4 casas (TDec_0302a04).
+func rate(d decimal.Decimal) string {
+	return d.StringFixed(4)
+}
+
+// clean prepara um texto para o XML: sem espaços nas pontas, sem espaços repetidos nem quebras de
+// linha (rejeitados pelos schemas) e com no máximo max caracteres.
+func clean(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if runes := []rune(s); len(runes) > max {
+		s = strings.TrimSpace(string(runes[:max]))
+	}
+	return s
+}
@@ -0,0 +1,158 @@
+package nfe
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+)
+
+// errElementNotFound é retornado por canonicalize quando nenhum elemento satisfaz match.
+var errElementNotFound = errors.New("nfe: element to canonicalize not found")
+
+// canonicalize devolve a forma canônica (Canonical XML 1.0, sem comentários) do primeiro elemento
+// para o qual match devolve true, com os filhos. É o algoritmo exigido pela assinatura da NF-e.
+// Documentos com DTD ou instruções de processamento dentro do elemento não são suportados.
+func canonicalize(data []byte, match func(xml.StartElement) bool) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	// scopes guarda os namespaces declarados em cada nível; rendered, os já escritos na saída.
+	var scopes []map[string]string
+	var rendered []map[string]string
+	var out bytes.Buffer
+	depth := 0
+
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			declared := map[string]string{}
+			var attrs []xml.Attr
+			for _, attr := range t.Attr {
+				switch {
+				case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+					declared[""] = attr.Value
+				case attr.Name.Space == "xmlns":
+					declared[attr.Name.Local] = attr.Value
+				default:
+					attrs = append(attrs, attr)
+				}
+			}
+			scopes = append(scopes, declared)
+			if depth == 0 && !match(t) {
+				continue
+			}
+			depth++
+
+			// O primeiro elemento escreve todos os namespaces em escopo; os demais, só o que mudou.
+			visible := inScope(scopes)
+			parent := map[string]string{}
+			if len(rendered) > 0 {
+				parent = rendered[len(rendered)-1]
+			}
+			current := make(map[string]string, len(parent))
+			for prefix, uri := range parent {
+				current[prefix] = uri
+			}
+			var prefixes []string
+			for prefix, uri := range visible {
+				// xmlns="" só é escrito para desfazer um namespace padrão escrito num ancestral.
+				if written, ok := parent[prefix]; ok && written == uri || !ok && prefix == "" && uri == "" {
+					continue
+				}
+				prefixes = append(prefixes, prefix)
+				current[prefix] = uri
+			}
+			sort.Strings(prefixes)
+			rendered = append(rendered, current)
+
+			out.WriteString("<" + qualifiedName(t.Name))
+			for _, prefix := range prefixes {
+				if prefix == "" {
+					out.WriteString(` xmlns="`)
+				} else {
+					out.WriteString(" xmlns:" + prefix + `="`)
+				}
+				escapeAttr(&out, visible[prefix])
+				out.WriteString(`"`)
+			}
+			sort.SliceStable(attrs, func(i, j int) bool {
+				si, sj := visible[attrs[i].Name.Space], visible[attrs[j].Name.Space]
+				if attrs[i].Name.Space == "" {
+					si = ""
+				}
+				if attrs[j].Name.Space == "" {
+					sj = ""
+				}
+				if si != sj {
+					return si < sj
+				}
+				return attrs[i].Name.Local < attrs[j].Name.Local
+			})
+			for _, attr := range attrs {
+				out.WriteString(" " + qualifiedName(attr.Name) + `="`)
+				escapeAttr(&out, attr.Value)
+				out.WriteString(`"`)
+			}
+			out.WriteString(">")
+		case xml.EndElement:
+			scopes = scopes[:len(scopes)-1]
+			if depth == 0 {
+				continue
+			}
+			out.WriteString("</" + qualifiedName(t.Name) + ">")
+			rendered = rendered[:len(rendered)-1]
+			depth--
+			if depth == 0 {
+				return out.Bytes(), nil
+			}
+		case xml.CharData:
+			if depth > 0 {
+				escapeText(&out, string(t))
+			}
+		case xml.ProcInst, xml.Directive:
+			if depth > 0 {
+				return nil, errors.New("nfe: processing instructions and directives are not supported")
+			}
+		}
+	}
+	return nil, errElementNotFound
+}
+
+// inScope junta as declarações de namespace de todos os níveis; as mais internas prevalecem.
+func inScope(scopes []map[string]string) map[string]string {
+	visible := map[string]string{}
+	for _, scope := range scopes {
+		for prefix, uri := range scope {
+			visible[prefix] = uri
+		}
+	}
+	return visible
+}
+
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+)
+
+func escapeText(out *bytes.Buffer, s string) {
+	textEscaper.WriteString(out, s)
+}
+
+func escapeAttr(out *bytes.Buffer, s string) {
+	attrEscaper.WriteString(out, s)
+}
@@ -0,0 +1,57 @@
+package nfe
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Os arquivos .c14n de testdata são a saída de `xmllint --c14n` (libxml2 2.13, a mesma implementação
+// de C14N 1.0 usada pelo xmlsec1) para o .xml de mesmo nome. infNFe.c14n é a forma canônica do infNFe
+// de nfe_unsigned.xml como subconjunto do documento, com o namespace herdado do NFe.
+func TestCanonicalizeMatchesLibxml2(t *testing.T) {
+	root := func(xml.StartElement) bool { return true }
+	tests := []struct {
+		name   string
+		input  string
+		golden string
+		match  func(xml.StartElement) bool
+	}{
+		{"attribute and namespace order, empty elements", "attributes.xml", "attributes.c14n", root},
+		{"redundant and undeclared namespaces", "namespaces.xml", "namespaces.c14n", root},
+		{"text and attribute escaping", "escaping.xml", "escaping.c14n", root},
+		{"infNFe subset with the inherited namespace", "nfe_unsigned.xml", "infNFe.c14n", isSignedElement("NFe35250311222333000181550010000012341123456783")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := readTestdata(t, tt.input)
+			want := readTestdata(t, tt.golden)
+
+			got, err := canonicalize(input, tt.match)
+			if err != nil {
+				t.Fatalf("canonicalize: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("canonical form differs from libxml2:\n got: %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestCanonicalizeElementNotFound(t *testing.T) {
+	_, err := canonicalize([]byte(`<NFe><infNFe Id="NFe1"/></NFe>`), isSignedElement("NFe2"))
+	if err != errElementNotFound {
+		t.Fatalf("canonicalize = %v, want errElementNotFound", err)
+	}
+}
+
+func readTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("reading testdata: %v", err)
+	}
+	return data
+}
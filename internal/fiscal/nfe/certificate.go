@@ -0,0 +1,69 @@
+package nfe
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/pkcs12"
+)
+
+// ErrCertificateExpired é retornado por LoadCertificate quando o certificado está fora da validade.
+var ErrCertificateExpired = errors.New("nfe: certificate is expired or not yet valid")
+
+// Certificate é um certificado digital A1 (e-CNPJ) com a chave privada, usado para assinar a NF-e.
+type Certificate struct {
+	Certificate *x509.Certificate
+	PrivateKey  *rsa.PrivateKey
+}
+
+// LoadCertificate lê um certificado A1 de um arquivo PFX (PKCS#12). Só são aceitos arquivos com
+// chave RSA, cifrados com 3DES ou RC2 (o formato dos certificados emitidos pelas ACs brasileiras);
+// PFX exportados pelo OpenSSL 3 com AES precisam ser convertidos com -legacy.
+func LoadCertificate(path, password string) (*Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("nfe: reading certificate: %w", err)
+	}
+	return ParseCertificate(data, password, time.Now())
+}
+
+// ParseCertificate lê um PFX já carregado e confere se o certificado vale em now. O arquivo pode
+// trazer a cadeia da AC; o certificado usado é o que corresponde à chave privada.
+func ParseCertificate(pfx []byte, password string, now time.Time) (*Certificate, error) {
+	blocks, err := pkcs12.ToPEM(pfx, password)
+	if err != nil {
+		return nil, fmt.Errorf("nfe: decoding certificate: %w", err)
+	}
+	var key *rsa.PrivateKey
+	var certificates []*x509.Certificate
+	for _, block := range blocks {
+		switch block.Type {
+		case "PRIVATE KEY":
+			if key, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+				return nil, fmt.Errorf("nfe: certificate private key must be RSA: %w", err)
+			}
+		case "CERTIFICATE":
+			certificate, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("nfe: parsing certificate: %w", err)
+			}
+			certificates = append(certificates, certificate)
+		}
+	}
+	if key == nil {
+		return nil, errors.New("nfe: certificate file has no private key")
+	}
+	for _, certificate := range certificates {
+		if public, ok := certificate.PublicKey.(*rsa.PublicKey); ok && public.Equal(&key.PublicKey) {
+			if now.Before(certificate.NotBefore) || now.After(certificate.NotAfter) {
+				return nil, ErrCertificateExpired
+			}
+			return &Certificate{Certificate: certificate, PrivateKey: key}, nil
+		}
+	}
+	return nil, errors.New("nfe: certificate file has no certificate for its private key")
+}
@@ -0,0 +1,62 @@
+package nfe
+
+import (
+	"errors"
+
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+)
+
+// ErrCompanyWithoutAddress é retornado por IssuerFromCompany quando a empresa não tem endereço principal.
+var ErrCompanyWithoutAddress = errors.New("nfe: issuing company has no main address")
+
+// IssuerRegistration são os dados fiscais do emitente que não fazem parte do cadastro da empresa.
+type IssuerRegistration struct {
+	StateRegistration     string
+	MunicipalRegistration string
+	CNAE                  string
+	TaxRegime             int
+	// District é o bairro do endereço principal, obrigatório na NF-e.
+	District string
+	Phone    string
+}
+
+// IssuerFromCompany monta o emitente a partir da empresa emissora e do seu endereço principal (MAIN),
+// que deve ter o código IBGE do município preenchido pelo AddressProvider. Os Addresses da empresa
+// precisam estar carregados.
+func IssuerFromCompany(company *model.CompanyGlobal, registration IssuerRegistration) (Issuer, error) {
+	main := company.MainAddress()
+	if main == nil {
+		return Issuer{}, ErrCompanyWithoutAddress
+	}
+	address := Address{
+		Street:     main.Street,
+		Number:     deref(main.StreetNumber),
+		Complement: deref(main.StreetComplement),
+		District:   registration.District,
+		City:       main.City,
+		UF:         main.State,
+		PostalCode: util.OnlyDigits(main.PostalCode),
+		Phone:      registration.Phone,
+	}
+	if main.CityIBGECode != nil {
+		address.CityCode = int(*main.CityIBGECode)
+	}
+	return Issuer{
+		CNPJ:                  util.OnlyDigits(company.CGC),
+		Name:                  company.SocialName,
+		TradeName:             company.Name,
+		StateRegistration:     registration.StateRegistration,
+		MunicipalRegistration: registration.MunicipalRegistration,
+		CNAE:                  registration.CNAE,
+		TaxRegime:             registration.TaxRegime,
+		Address:               address,
+	}, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,45 @@
+package nfe
+
+import (
+	"context"
+	"fmt"
+)
+
+// Emitter faz a emissão completa: monta, assina, valida contra o schema e transmite a NF-e.
+type Emitter struct {
+	Certificate *Certificate
+	Schema      SchemaValidator
+	Transmitter Transmitter
+}
+
+// Issued é uma NF-e emitida: o XML assinado (que deve ser guardado) e o retorno da autorização.
+type Issued struct {
+	Document      *Document
+	XML           []byte
+	Authorization *Authorization
+}
+
+func NewEmitter(certificate *Certificate, schema SchemaValidator, transmitter Transmitter) *Emitter {
+	return &Emitter{Certificate: certificate, Schema: schema, Transmitter: transmitter}
+}
+
+// Issue emite a NF-e da venda. Uma rejeição da SEFAZ não é erro: ela vem em Authorization, com o XML
+// assinado, para que a nota possa ser corrigida e reenviada com outro número.
+func (e *Emitter) Issue(ctx context.Context, invoice Invoice) (*Issued, error) {
+	document, err := Build(invoice)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := Sign(document, e.Certificate)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.Schema.Validate(ctx, signed); err != nil {
+		return nil, err
+	}
+	authorization, err := e.Transmitter.Authorize(ctx, document.AccessKey, signed)
+	if err != nil {
+		return nil, fmt.Errorf("nfe: transmitting %s: %w", document.AccessKey, err)
+	}
+	return &Issued{Document: document, XML: signed, Authorization: authorization}, nil
+}
@@ -0,0 +1,311 @@
+package nfe
+
+import "encoding/xml"
+
+// Namespace é o namespace dos documentos da NF-e.
+const Namespace = "http://www.portalfiscal.inf.br/nfe"
+
+// Version é a versão do leiaute gerado (atributo versao do infNFe).
+const Version = "4.00"
+
+// As structs abaixo seguem o leiaute da NF-e 4.00 (nfe_v4.00.xsd). A ordem dos campos é a ordem dos
+// elementos no schema e não pode ser alterada. Valores numéricos já vêm formatados como texto, no
+// número de casas decimais exigido por cada campo (ver Build).
+
+// NFe é o documento raiz. A assinatura (Signature) é acrescentada depois, por Sign.
+type NFe struct {
+	XMLName   xml.Name   `xml:"http://www.portalfiscal.inf.br/nfe NFe"`
+	InfNFe    InfNFe     `xml:"infNFe"`
+	Signature *Signature `xml:"http://www.w3.org/2000/09/xmldsig# Signature,omitempty"`
+}
+
+type InfNFe struct {
+	Versao  string   `xml:"versao,attr"`
+	ID      string   `xml:"Id,attr"`
+	Ide     Ide      `xml:"ide"`
+	Emit    Emit     `xml:"emit"`
+	Dest    *Dest    `xml:"dest,omitempty"`
+	Det     []Det    `xml:"det"`
+	Total   Total    `xml:"total"`
+	Transp  Transp   `xml:"transp"`
+	Pag     Pag      `xml:"pag"`
+	InfAdic *InfAdic `xml:"infAdic,omitempty"`
+}
+
+// Ide identifica a nota.
+type Ide struct {
+	CUF         string `xml:"cUF"`
+	CNF         string `xml:"cNF"`
+	NatOp       string `xml:"natOp"`
+	Mod         string `xml:"mod"`
+	Serie       string `xml:"serie"`
+	NNF         string `xml:"nNF"`
+	DhEmi       string `xml:"dhEmi"`
+	DhSaiEnt    string `xml:"dhSaiEnt,omitempty"`
+	TpNF        string `xml:"tpNF"`
+	IdDest      string `xml:"idDest"`
+	CMunFG      string `xml:"cMunFG"`
+	TpImp       string `xml:"tpImp"`
+	TpEmis      string `xml:"tpEmis"`
+	CDV         string `xml:"cDV"`
+	TpAmb       string `xml:"tpAmb"`
+	FinNFe      string `xml:"finNFe"`
+	IndFinal    string `xml:"indFinal"`
+	IndPres     string `xml:"indPres"`
+	IndIntermed string `xml:"indIntermed,omitempty"`
+	ProcEmi     string `xml:"procEmi"`
+	VerProc     string `xml:"verProc"`
+}
+
+// Emit é o emitente.
+type Emit struct {
+	CNPJ      string   `xml:"CNPJ,omitempty"`
+	CPF       string   `xml:"CPF,omitempty"`
+	XNome     string   `xml:"xNome"`
+	XFant     string   `xml:"xFant,omitempty"`
+	EnderEmit Endereco `xml:"enderEmit"`
+	IE        string   `xml:"IE"`
+	IEST      string   `xml:"IEST,omitempty"`
+	IM        string   `xml:"IM,omitempty"`
+	CNAE      string   `xml:"CNAE,omitempty"`
+	CRT       string   `xml:"CRT"`
+}
+
+// Endereco é usado no emitente (TEnderEmi) e no destinatário (TEndereco).
+type Endereco struct {
+	XLgr    string `xml:"xLgr"`
+	Nro     string `xml:"nro"`
+	XCpl    string `xml:"xCpl,omitempty"`
+	XBairro string `xml:"xBairro"`
+	CMun    string `xml:"cMun"`
+	XMun    string `xml:"xMun"`
+	UF      string `xml:"UF"`
+	CEP     string `xml:"CEP,omitempty"`
+	CPais   string `xml:"cPais,omitempty"`
+	XPais   string `xml:"xPais,omitempty"`
+	Fone    string `xml:"fone,omitempty"`
+}
+
+// Dest é o destinatário.
+type Dest struct {
+	CNPJ      string    `xml:"CNPJ,omitempty"`
+	CPF       string    `xml:"CPF,omitempty"`
+	XNome     string    `xml:"xNome,omitempty"`
+	EnderDest *Endereco `xml:"enderDest,omitempty"`
+	IndIEDest string    `xml:"indIEDest"`
+	IE        string    `xml:"IE,omitempty"`
+	Email     string    `xml:"email,omitempty"`
+}
+
+// Det é um item da nota.
+type Det struct {
+	NItem     string  `xml:"nItem,attr"`
+	Prod      Prod    `xml:"prod"`
+	Imposto   Imposto `xml:"imposto"`
+	InfAdProd string  `xml:"infAdProd,omitempty"`
+}
+
+type Prod struct {
+	CProd    string `xml:"cProd"`
+	CEAN     string `xml:"cEAN"`
+	XProd    string `xml:"xProd"`
+	NCM      string `xml:"NCM"`
+	CEST     string `xml:"CEST,omitempty"`
+	EXTIPI   string `xml:"EXTIPI,omitempty"`
+	CFOP     string `xml:"CFOP"`
+	UCom     string `xml:"uCom"`
+	QCom     string `xml:"qCom"`
+	VUnCom   string `xml:"vUnCom"`
+	VProd    string `xml:"vProd"`
+	CEANTrib string `xml:"cEANTrib"`
+	UTrib    string `xml:"uTrib"`
+	QTrib    string `xml:"qTrib"`
+	VUnTrib  string `xml:"vUnTrib"`
+	VFrete   string `xml:"vFrete,omitempty"`
+	VSeg     string `xml:"vSeg,omitempty"`
+	VDesc    string `xml:"vDesc,omitempty"`
+	VOutro   string `xml:"vOutro,omitempty"`
+	IndTot   string `xml:"indTot"`
+	XPed     string `xml:"xPed,omitempty"`
+	NItemPed string `xml:"nItemPed,omitempty"`
+}
+
+type Imposto struct {
+	VTotTrib string `xml:"vTotTrib,omitempty"`
+	ICMS     ICMS   `xml:"ICMS"`
+	IPI      *IPI   `xml:"IPI,omitempty"`
+	PIS      PIS    `xml:"PIS"`
+	COFINS   COFINS `xml:"COFINS"`
+}
+
+// ICMS tem exatamente um dos grupos preenchido, conforme o CST (regime normal) ou o CSOSN (Simples Nacional).
+type ICMS struct {
+	ICMS00    *ICMS00    `xml:"ICMS00,omitempty"`
+	ICMS20    *ICMS20    `xml:"ICMS20,omitempty"`
+	ICMS40    *ICMS40    `xml:"ICMS40,omitempty"`
+	ICMSSN101 *ICMSSN101 `xml:"ICMSSN101,omitempty"`
+	ICMSSN102 *ICMSSN102 `xml:"ICMSSN102,omitempty"`
+}
+
+// ICMS00 é a tributação integral (CST 00).
+type ICMS00 struct {
+	Orig  string `xml:"orig"`
+	CST   string `xml:"CST"`
+	ModBC string `xml:"modBC"`
+	VBC   string `xml:"vBC"`
+	PICMS string `xml:"pICMS"`
+	VICMS string `xml:"vICMS"`
+}
+
+// ICMS20 é a tributação com redução de base de cálculo (CST 20).
+type ICMS20 struct {
+	Orig   string `xml:"orig"`
+	CST    string `xml:"CST"`
+	ModBC  string `xml:"modBC"`
+	PRedBC string `xml:"pRedBC"`
+	VBC    string `xml:"vBC"`
+	PICMS  string `xml:"pICMS"`
+	VICMS  string `xml:"vICMS"`
+}
+
+// ICMS40 é a isenção, não incidência ou suspensão (CST 40, 41 e 50).
+type ICMS40 struct {
+	Orig string `xml:"orig"`
+	CST  string `xml:"CST"`
+}
+
+// ICMSSN101 é o Simples Nacional com permissão de crédito (CSOSN 101).
+type ICMSSN101 struct {
+	Orig        string `xml:"orig"`
+	CSOSN       string `xml:"CSOSN"`
+	PCredSN     string `xml:"pCredSN"`
+	VCredICMSSN string `xml:"vCredICMSSN"`
+}
+
+// ICMSSN102 é o Simples Nacional sem permissão de crédito (CSOSN 102, 103, 300 e 400).
+type ICMSSN102 struct {
+	Orig  string `xml:"orig"`
+	CSOSN string `xml:"CSOSN"`
+}
+
+// IPI só é informado para produtos industrializados pelo emitente ou equiparados.
+type IPI struct {
+	CEnq    string   `xml:"cEnq"`
+	IPITrib *IPITrib `xml:"IPITrib,omitempty"`
+	IPINT   *IPINT   `xml:"IPINT,omitempty"`
+}
+
+// IPITrib é o IPI tributado por alíquota (CST 00, 49, 50 e 99).
+type IPITrib struct {
+	CST  string `xml:"CST"`
+	VBC  string `xml:"vBC"`
+	PIPI string `xml:"pIPI"`
+	VIPI string `xml:"vIPI"`
+}
+
+// IPINT é o IPI não tributado (CST 01 a 05 e 51 a 55).
+type IPINT struct {
+	CST string `xml:"CST"`
+}
+
+// PIS tem exatamente um dos grupos preenchido, conforme o CST.
+type PIS struct {
+	PISAliq *PISAliq `xml:"PISAliq,omitempty"`
+	PISNT   *PISNT   `xml:"PISNT,omitempty"`
+	PISOutr *PISOutr `xml:"PISOutr,omitempty"`
+}
+
+// PISAliq é o PIS por alíquota (CST 01 e 02).
+type PISAliq struct {
+	CST  string `xml:"CST"`
+	VBC  string `xml:"vBC"`
+	PPIS string `xml:"pPIS"`
+	VPIS string `xml:"vPIS"`
+}
+
+// PISNT é o PIS não tributado (CST 04 a 09).
+type PISNT struct {
+	CST string `xml:"CST"`
+}
+
+// PISOutr é o PIS das demais operações (CST 49 a 99), aqui sempre por alíquota.
+type PISOutr struct {
+	CST  string `xml:"CST"`
+	VBC  string `xml:"vBC"`
+	PPIS string `xml:"pPIS"`
+	VPIS string `xml:"vPIS"`
+}
+
+// COFINS espelha o PIS.
+type COFINS struct {
+	COFINSAliq *COFINSAliq `xml:"COFINSAliq,omitempty"`
+	COFINSNT   *COFINSNT   `xml:"COFINSNT,omitempty"`
+	COFINSOutr *COFINSOutr `xml:"COFINSOutr,omitempty"`
+}
+
+type COFINSAliq struct {
+	CST     string `xml:"CST"`
+	VBC     string `xml:"vBC"`
+	PCOFINS string `xml:"pCOFINS"`
+	VCOFINS string `xml:"vCOFINS"`
+}
+
+type COFINSNT struct {
+	CST string `xml:"CST"`
+}
+
+type COFINSOutr struct {
+	CST     string `xml:"CST"`
+	VBC     string `xml:"vBC"`
+	PCOFINS string `xml:"pCOFINS"`
+	VCOFINS string `xml:"vCOFINS"`
+}
+
+type Total struct {
+	ICMSTot ICMSTot `xml:"ICMSTot"`
+}
+
+// ICMSTot são os totais da nota.
+type ICMSTot struct {
+	VBC        string `xml:"vBC"`
+	VICMS      string `xml:"vICMS"`
+	VICMSDeson string `xml:"vICMSDeson"`
+	VFCP       string `xml:"vFCP"`
+	VBCST      string `xml:"vBCST"`
+	VST        string `xml:"vST"`
+	VFCPST     string `xml:"vFCPST"`
+	VFCPSTRet  string `xml:"vFCPSTRet"`
+	VProd      string `xml:"vProd"`
+	VFrete     string `xml:"vFrete"`
+	VSeg       string `xml:"vSeg"`
+	VDesc      string `xml:"vDesc"`
+	VII        string `xml:"vII"`
+	VIPI       string `xml:"vIPI"`
+	VIPIDevol  string `xml:"vIPIDevol"`
+	VPIS       string `xml:"vPIS"`
+	VCOFINS    string `xml:"vCOFINS"`
+	VOutro     string `xml:"vOutro"`
+	VNF        string `xml:"vNF"`
+	VTotTrib   string `xml:"vTotTrib,omitempty"`
+}
+
+type Transp struct {
+	ModFrete string `xml:"modFrete"`
+}
+
+type Pag struct {
+	DetPag []DetPag `xml:"detPag"`
+	VTroco string   `xml:"vTroco,omitempty"`
+}
+
+type DetPag struct {
+	IndPag string `xml:"indPag,omitempty"`
+	TPag   string `xml:"tPag"`
+	XPag   string `xml:"xPag,omitempty"`
+	VPag   string `xml:"vPag"`
+}
+
+type InfAdic struct {
+	InfAdFisco string `xml:"infAdFisco,omitempty"`
+	InfCpl     string `xml:"infCpl,omitempty"`
+}
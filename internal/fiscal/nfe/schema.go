@@ -0,0 +1,132 @@
+package nfe
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// embeddedSchemas são os schemas oficiais da NF-e versionados em schemas (pacote de liberação
+// PL_009), compilados no binário para que a validação não dependa do diretório de trabalho.
+//
+//go:embed schemas
+var embeddedSchemas embed.FS
+
+var (
+	embeddedSchemaOnce sync.Once
+	embeddedSchemaDir  string
+	embeddedSchemaErr  error
+)
+
+// schemaFile é o schema de entrada do pacote; ele importa os de tipos e o do XML-DSig do mesmo diretório.
+const schemaFile = "nfe_v4.00.xsd"
+
+// documentErrorCodes são os códigos de saída do xmllint para documentos inválidos.
+var documentErrorCodes = map[int]bool{1: true, 3: true, 4: true}
+
+// ErrSchemaNotFound é retornado quando o diretório não tem os schemas oficiais.
+var ErrSchemaNotFound = errors.New("nfe: XSD schemas not found")
+
+// SchemaValidator confere o XML assinado contra os schemas oficiais antes da transmissão.
+type SchemaValidator interface {
+	Validate(ctx context.Context, signed []byte) error
+}
+
+// SchemaError lista as violações do schema encontradas no XML.
+type SchemaError struct {
+	Problems []string
+}
+
+func (e *SchemaError) Error() string {
+	return "nfe: XML does not match the schema: " + strings.Join(e.Problems, "; ")
+}
+
+// XMLLintValidator valida com o xmllint (libxml2), que implementa XSD 1.0 por completo; não há
+// validador de XSD na biblioteca padrão do Go. A validação não acessa a rede (--nonet).
+type XMLLintValidator struct {
+	// Dir é o diretório dos schemas. Vazio usa os schemas embutidos no binário.
+	Dir string
+	// Command é o caminho do executável. Vazio é "xmllint", procurado no PATH.
+	Command string
+}
+
+func (v XMLLintValidator) Validate(ctx context.Context, signed []byte) error {
+	dir := v.Dir
+	if dir == "" {
+		var err error
+		if dir, err = extractEmbeddedSchemas(); err != nil {
+			return err
+		}
+	}
+	schema := filepath.Join(dir, schemaFile)
+	if _, err := os.Stat(schema); err != nil {
+		return fmt.Errorf("%w: %s", ErrSchemaNotFound, schema)
+	}
+	command := v.Command
+	if command == "" {
+		command = "xmllint"
+	}
+
+	cmd := exec.CommandContext(ctx, command, "--noout", "--nonet", "--schema", schema, "-")
+	cmd.Stdin = bytes.NewReader(signed)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err == nil {
+		return nil
+	}
+	// O xmllint sai com 3 quando o documento não segue o schema e com 1 ou 4 (conforme a versão da
+	// libxml2) quando não é bem formado; os demais códigos são falhas da própria validação (5 é um
+	// schema que não compila).
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || !documentErrorCodes[exitErr.ExitCode()] {
+		return fmt.Errorf("nfe: running %s: %w: %s", command, err, strings.TrimSpace(stderr.String()))
+	}
+	var problems []string
+	for _, line := range strings.Split(stderr.String(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasSuffix(line, "fails to validate") {
+			continue
+		}
+		// As linhas vêm como "-:1: element X: Schemas validity error : ..."; o prefixo é o stdin.
+		problems = append(problems, strings.TrimPrefix(line, "-:"))
+	}
+	if len(problems) == 0 {
+		problems = []string{strings.TrimSpace(stderr.String())}
+	}
+	return &SchemaError{Problems: problems}
+}
+
+// extractEmbeddedSchemas copia os schemas embutidos para um diretório temporário uma única vez por
+// processo: o xmllint lê o schema de entrada e os que ele importa do disco.
+func extractEmbeddedSchemas() (string, error) {
+	embeddedSchemaOnce.Do(func() {
+		if _, err := fs.Stat(embeddedSchemas, "schemas/"+schemaFile); err != nil {
+			embeddedSchemaErr = fmt.Errorf("%w: %s is not embedded", ErrSchemaNotFound, schemaFile)
+			return
+		}
+		dir, err := os.MkdirTemp("", "nfe-schemas-")
+		if err != nil {
+			embeddedSchemaErr = fmt.Errorf("nfe: extracting schemas: %w", err)
+			return
+		}
+		schemas, err := fs.Sub(embeddedSchemas, "schemas")
+		if err == nil {
+			err = os.CopyFS(dir, schemas)
+		}
+		if err != nil {
+			embeddedSchemaErr = fmt.Errorf("nfe: extracting schemas: %w", err)
+			return
+		}
+		embeddedSchemaDir = dir
+	})
+	return embeddedSchemaDir, embeddedSchemaErr
+}
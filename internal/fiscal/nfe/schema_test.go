@@ -0,0 +1,32 @@
+package nfe
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os/exec"
+	"testing"
+)
+
+// A nota assinada tem que passar nos schemas oficiais embutidos de schemas (ver schemas/README.md). Sem
+// eles o Emitter não emite nenhuma nota, então a falta dos arquivos falha o teste.
+func TestSignedInvoiceMatchesSchema(t *testing.T) {
+	if _, err := fs.Stat(embeddedSchemas, "schemas/"+schemaFile); err != nil {
+		t.Fatalf("PL_009 schemas are missing from internal/fiscal/nfe/schemas; copy them as schemas/README.md explains: %v", err)
+	}
+	if _, err := exec.LookPath("xmllint"); err != nil {
+		t.Skip("xmllint not installed")
+	}
+	signed := signTestInvoice(t, newTestCertificate(t))
+
+	if err := (XMLLintValidator{}).Validate(context.Background(), signed); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+}
+
+func TestValidateWithoutSchemas(t *testing.T) {
+	err := XMLLintValidator{Dir: t.TempDir()}.Validate(context.Background(), []byte("<NFe/>"))
+	if !errors.Is(err, ErrSchemaNotFound) {
+		t.Fatalf("Validate = %v, want ErrSchemaNotFound", err)
+	}
+}
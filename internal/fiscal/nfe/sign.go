@@ -0,0 +1,183 @@
+package nfe
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+)
+
+// Algoritmos da assinatura exigidos pelo Manual de Orientação do Contribuinte: C14N 1.0, RSA-SHA1
+// e a transformação enveloped-signature, com a referência apontando para o Id do infNFe.
+const (
+	algorithmC14N      = "http://www.w3.org/TR/2001/REC-xml-c14n-20010315"
+	algorithmRSASHA1   = "http://www.w3.org/2000/09/xmldsig#rsa-sha1"
+	algorithmSHA1      = "http://www.w3.org/2000/09/xmldsig#sha1"
+	algorithmEnveloped = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+
+	signedInfoElement = "SignedInfo"
+	signedElement     = "infNFe"
+)
+
+// ErrInvalidSignature é retornado por Verify quando o XML não tem assinatura ou ela não confere.
+var ErrInvalidSignature = errors.New("nfe: invalid signature")
+
+// Signature é o elemento Signature (XML-DSig) acrescentado ao fim do NFe.
+type Signature struct {
+	XMLName        xml.Name   `xml:"http://www.w3.org/2000/09/xmldsig# Signature"`
+	SignedInfo     SignedInfo `xml:"SignedInfo"`
+	SignatureValue string     `xml:"SignatureValue"`
+	KeyInfo        KeyInfo    `xml:"KeyInfo"`
+}
+
+type SignedInfo struct {
+	CanonicalizationMethod Algorithm `xml:"CanonicalizationMethod"`
+	SignatureMethod        Algorithm `xml:"SignatureMethod"`
+	Reference              Reference `xml:"Reference"`
+}
+
+type Algorithm struct {
+	Algorithm string `xml:"Algorithm,attr"`
+}
+
+type Reference struct {
+	URI          string      `xml:"URI,attr"`
+	Transforms   []Algorithm `xml:"Transforms>Transform"`
+	DigestMethod Algorithm   `xml:"DigestMethod"`
+	DigestValue  string      `xml:"DigestValue"`
+}
+
+type KeyInfo struct {
+	X509Certificate string `xml:"X509Data>X509Certificate"`
+}
+
+// Sign assina o infNFe do documento com o certificado e devolve o XML da NF-e assinada, já na forma
+// canônica (é o XML que deve ser validado, transmitido e guardado).
+func Sign(document *Document, certificate *Certificate) ([]byte, error) {
+	nfe := *document.NFe
+	nfe.Signature = nil
+	unsigned, err := xml.Marshal(&nfe)
+	if err != nil {
+		return nil, err
+	}
+	infNFe, err := canonicalize(unsigned, isSignedElement(nfe.InfNFe.ID))
+	if err != nil {
+		return nil, err
+	}
+	digest := sha1.Sum(infNFe)
+
+	signature := &Signature{
+		SignedInfo: SignedInfo{
+			CanonicalizationMethod: Algorithm{Algorithm: algorithmC14N},
+			SignatureMethod:        Algorithm{Algorithm: algorithmRSASHA1},
+			Reference: Reference{
+				URI:          "#" + nfe.InfNFe.ID,
+				Transforms:   []Algorithm{{Algorithm: algorithmEnveloped}, {Algorithm: algorithmC14N}},
+				DigestMethod: Algorithm{Algorithm: algorithmSHA1},
+				DigestValue:  base64.StdEncoding.EncodeToString(digest[:]),
+			},
+		},
+		KeyInfo: KeyInfo{X509Certificate: base64.StdEncoding.EncodeToString(certificate.Certificate.Raw)},
+	}
+	signedInfo, err := canonicalSignedInfo(signature)
+	if err != nil {
+		return nil, err
+	}
+	hashed := sha1.Sum(signedInfo)
+	value, err := rsa.SignPKCS1v15(nil, certificate.PrivateKey, crypto.SHA1, hashed[:])
+	if err != nil {
+		return nil, fmt.Errorf("nfe: signing: %w", err)
+	}
+	signature.SignatureValue = base64.StdEncoding.EncodeToString(value)
+
+	nfe.Signature = signature
+	signed, err := xml.Marshal(&nfe)
+	if err != nil {
+		return nil, err
+	}
+	canonical, err := canonicalize(signed, func(xml.StartElement) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), canonical...), nil
+}
+
+// Verify confere a assinatura de uma NF-e assinada por Sign (ou por outro emissor com os mesmos
+// algoritmos) e devolve o certificado do signatário. A cadeia do certificado não é validada.
+func Verify(signed []byte) (*x509.Certificate, error) {
+	var nfe NFe
+	if err := xml.Unmarshal(signed, &nfe); err != nil {
+		return nil, err
+	}
+	signature := nfe.Signature
+	if signature == nil || signature.SignedInfo.Reference.URI != "#"+nfe.InfNFe.ID {
+		return nil, ErrInvalidSignature
+	}
+	raw, err := base64.StdEncoding.DecodeString(signature.KeyInfo.X509Certificate)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	certificate, err := x509.ParseCertificate(raw)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	public, ok := certificate.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+
+	// A assinatura está fora do infNFe, então a transformação enveloped-signature não remove nada dele.
+	infNFe, err := canonicalize(signed, isSignedElement(nfe.InfNFe.ID))
+	if err != nil {
+		return nil, err
+	}
+	digest := sha1.Sum(infNFe)
+	if base64.StdEncoding.EncodeToString(digest[:]) != signature.SignedInfo.Reference.DigestValue {
+		return nil, ErrInvalidSignature
+	}
+	signedInfo, err := canonicalize(signed, func(start xml.StartElement) bool {
+		return start.Name.Local == signedInfoElement
+	})
+	if err != nil {
+		return nil, err
+	}
+	value, err := base64.StdEncoding.DecodeString(signature.SignatureValue)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	hashed := sha1.Sum(signedInfo)
+	if rsa.VerifyPKCS1v15(public, crypto.SHA1, hashed[:], value) != nil {
+		return nil, ErrInvalidSignature
+	}
+	return certificate, nil
+}
+
+// canonicalSignedInfo devolve a forma canônica do SignedInfo como ele fica dentro da Signature, que
+// declara o namespace do XML-DSig.
+func canonicalSignedInfo(signature *Signature) ([]byte, error) {
+	data, err := xml.Marshal(signature)
+	if err != nil {
+		return nil, err
+	}
+	return canonicalize(data, func(start xml.StartElement) bool {
+		return start.Name.Local == signedInfoElement
+	})
+}
+
+func isSignedElement(id string) func(xml.StartElement) bool {
+	return func(start xml.StartElement) bool {
+		if start.Name.Local != signedElement {
+			return false
+		}
+		for _, attr := range start.Attr {
+			if attr.Name.Local == "Id" && attr.Value == id {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,220 @@
+package nfe
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"math/big"
+	"os/exec"
+	"testing"
+	"time"
+
+	"go-sales/pkg/decimal"
+)
+
+// testInvoice é uma venda válida com dados fixos (inclusive o cNF), para que o XML seja sempre o mesmo.
+// nfe_unsigned.xml em testdata é o NFe montado a partir dela.
+func testInvoice() Invoice {
+	return Invoice{
+		Environment:    EnvironmentHomologation,
+		Series:         1,
+		Number:         1234,
+		Code:           12345678,
+		IssuedAt:       time.Date(2025, 3, 10, 14, 30, 0, 0, time.FixedZone("BRT", -3*60*60)),
+		Operation:      "VENDA DE MERCADORIA",
+		FinalConsumer:  true,
+		Presence:       2,
+		FreightMode:    FreightNone,
+		OrderReference: "PED-1234",
+		AdditionalInfo: "Pedido & entrega <expressa>",
+		Software:       "go-sales 1.0",
+		Issuer: Issuer{
+			CNPJ:              "11222333000181",
+			Name:              "Loja Exemplo Ltda",
+			TradeName:         "Loja Exemplo",
+			StateRegistration: "110042490114",
+			TaxRegime:         TaxRegimeNormal,
+			Address: Address{
+				Street: "Avenida Paulista", Number: "1000", District: "Bela Vista",
+				CityCode: 3550308, City: "São Paulo", UF: "SP", PostalCode: "01310100",
+			},
+		},
+		Customer: Customer{
+			Document:    "52998224725",
+			Name:        "João da Silva",
+			IEIndicator: IENonContributor,
+			Email:       "joao@example.com",
+			Address: &Address{
+				Street: "Rua das Flores", Number: "10", District: "Centro",
+				CityCode: 3304557, City: "Rio de Janeiro", UF: "RJ", PostalCode: "20010000",
+			},
+		},
+		Items: []Item{{
+			Code:        "SKU-1",
+			GTIN:        "7891000315507",
+			Description: "Camiseta \"básica\"",
+			NCM:         "61091000",
+			CFOP:        "6108",
+			Unit:        "UN",
+			Quantity:    decimal.MustParse("2"),
+			UnitPrice:   decimal.MustParse("49.90"),
+			Freight:     decimal.MustParse("10.00"),
+			Tax: ItemTax{
+				ICMSCode:   "00",
+				ICMSRate:   decimal.MustParse("12"),
+				IPICode:    "99",
+				IPIRate:    decimal.MustParse("5"),
+				PISCode:    "01",
+				PISRate:    decimal.MustParse("1.65"),
+				COFINSCode: "01",
+				COFINSRate: decimal.MustParse("7.6"),
+			},
+		}},
+		Payments: []Payment{{Method: "17", Amount: decimal.MustParse("120.00")}},
+	}
+}
+
+// newTestCertificate gera um certificado autoassinado com chave RSA de 2048 bits, como o e-CNPJ A1.
+func newTestCertificate(t *testing.T) *Certificate {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "LOJA EXEMPLO LTDA:11222333000181"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("creating certificate: %v", err)
+	}
+	certificate, err := x509.ParseCertificate(raw)
+	if err != nil {
+		t.Fatalf("parsing certificate: %v", err)
+	}
+	return &Certificate{Certificate: certificate, PrivateKey: key}
+}
+
+func signTestInvoice(t *testing.T, certificate *Certificate) []byte {
+	t.Helper()
+	document, err := Build(testInvoice())
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	signed, err := Sign(document, certificate)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	return signed
+}
+
+func TestBuildMatchesFixture(t *testing.T) {
+	document, err := Build(testInvoice())
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	got, err := xml.Marshal(document.NFe)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := readTestdata(t, "nfe_unsigned.xml"); !bytes.Equal(got, want) {
+		t.Fatalf("NFe differs from testdata/nfe_unsigned.xml:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	certificate := newTestCertificate(t)
+	signed := signTestInvoice(t, certificate)
+
+	signer, err := Verify(signed)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !signer.Equal(certificate.Certificate) {
+		t.Fatalf("Verify returned %s, want the signing certificate", signer.Subject)
+	}
+}
+
+// O DigestValue tem que ser o SHA-1 da forma canônica do infNFe calculada pela libxml2; é o que a
+// SEFAZ (e o xmlsec1) recalculam ao receber a nota.
+func TestSignDigestMatchesLibxml2(t *testing.T) {
+	signed := signTestInvoice(t, newTestCertificate(t))
+
+	var nfe NFe
+	if err := xml.Unmarshal(signed, &nfe); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	digest := sha1.Sum(readTestdata(t, "infNFe.c14n"))
+	want := base64.StdEncoding.EncodeToString(digest[:])
+	if got := nfe.Signature.SignedInfo.Reference.DigestValue; got != want {
+		t.Fatalf("DigestValue = %s, want %s", got, want)
+	}
+}
+
+// Sign devolve o XML já canônico; canonicalizá-lo de novo com o xmllint não pode mudar nada. O teste
+// só roda onde o xmllint está instalado (é a mesma dependência da validação do schema).
+func TestSignOutputIsCanonicalForXMLLint(t *testing.T) {
+	path, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Skip("xmllint not installed")
+	}
+	signed := signTestInvoice(t, newTestCertificate(t))
+
+	cmd := exec.Command(path, "--c14n", "-")
+	cmd.Stdin = bytes.NewReader(signed)
+	want, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("xmllint --c14n: %v", err)
+	}
+	if got := bytes.TrimPrefix(signed, []byte(xml.Header)); !bytes.Equal(got, want) {
+		t.Fatalf("signed XML differs from its xmllint canonical form:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	signed := signTestInvoice(t, newTestCertificate(t))
+	other := signTestInvoice(t, newTestCertificate(t))
+
+	tests := []struct {
+		name string
+		old  string
+		new  string
+	}{
+		{"changed total", "<vNF>115.29</vNF>", "<vNF>15.29</vNF>"},
+		{"changed customer", "<CPF>52998224725</CPF>", "<CPF>11144477735</CPF>"},
+		{"changed reference", `URI="#NFe`, `URI="#NFe0`},
+		{"certificate of another key", x509Certificate(signed), x509Certificate(other)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := bytes.Replace(signed, []byte(tt.old), []byte(tt.new), 1)
+			if bytes.Equal(tampered, signed) {
+				t.Fatalf("%q not found in the signed XML", tt.old)
+			}
+			if _, err := Verify(tampered); !errors.Is(err, ErrInvalidSignature) {
+				t.Fatalf("Verify = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+
+	if _, err := Verify(readTestdata(t, "nfe_unsigned.xml")); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("Verify of an unsigned NF-e = %v, want ErrInvalidSignature", err)
+	}
+}
+
+// x509Certificate devolve o conteúdo do X509Certificate de um XML assinado.
+func x509Certificate(signed []byte) string {
+	_, after, _ := bytes.Cut(signed, []byte("<X509Certificate>"))
+	value, _, _ := bytes.Cut(after, []byte("</X509Certificate>"))
+	return string(value)
+}
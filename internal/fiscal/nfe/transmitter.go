@@ -0,0 +1,93 @@
+package nfe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Códigos de status (cStat) usados pela SEFAZ e devolvidos pelo FakeTransmitter.
+const (
+	StatusAuthorized = 100
+	StatusDuplicate  = 204
+	StatusBadSigning = 297
+)
+
+// ErrAuthorizationNotFound é retornado por Status quando a chave não foi transmitida.
+var ErrAuthorizationNotFound = errors.New("nfe: authorization not found")
+
+// Authorization é o retorno da SEFAZ para uma NF-e (protNFe).
+type Authorization struct {
+	AccessKey string
+	// Status é o cStat; só StatusAuthorized autoriza o uso da nota.
+	Status int
+	Reason string
+	// Protocol é o número do protocolo de autorização (nProt), vazio nas rejeições.
+	Protocol   string
+	ReceivedAt time.Time
+}
+
+func (a *Authorization) Authorized() bool {
+	return a.Status == StatusAuthorized
+}
+
+// Transmitter envia a NF-e assinada para autorização. A transmissão para a SEFAZ (web services com
+// TLS mútuo) ainda não existe; FakeTransmitter atende o desenvolvimento e a homologação interna.
+type Transmitter interface {
+	Authorize(ctx context.Context, key AccessKey, signed []byte) (*Authorization, error)
+	Status(ctx context.Context, key AccessKey) (*Authorization, error)
+}
+
+// FakeTransmitter autoriza em memória toda NF-e com assinatura válida, como a SEFAZ faria, e rejeita
+// chaves repetidas. Não confere regras de negócio.
+type FakeTransmitter struct {
+	mu             sync.Mutex
+	authorizations map[string]*Authorization
+	protocol       int64
+}
+
+func NewFakeTransmitter() *FakeTransmitter {
+	return &FakeTransmitter{authorizations: map[string]*Authorization{}}
+}
+
+func (t *FakeTransmitter) Authorize(ctx context.Context, key AccessKey, signed []byte) (*Authorization, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if _, err := Verify(signed); err != nil {
+		return &Authorization{AccessKey: key.String(), Status: StatusBadSigning, Reason: "Rejeição: Falha na assinatura digital", ReceivedAt: now}, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.authorizations[key.String()]; ok {
+		return &Authorization{AccessKey: key.String(), Status: StatusDuplicate, Reason: "Rejeição: Duplicidade de NF-e", ReceivedAt: now}, nil
+	}
+	t.protocol++
+	// O protocolo tem 15 dígitos: o tipo de autorizador, a UF, o ano e um sequencial.
+	authorization := &Authorization{
+		AccessKey:  key.String(),
+		Status:     StatusAuthorized,
+		Reason:     "Autorizado o uso da NF-e",
+		Protocol:   fmt.Sprintf("1%02d%s%010d", key.UF, now.Format("06"), t.protocol),
+		ReceivedAt: now,
+	}
+	t.authorizations[key.String()] = authorization
+	return authorization, nil
+}
+
+func (t *FakeTransmitter) Status(ctx context.Context, key AccessKey) (*Authorization, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	authorization, ok := t.authorizations[key.String()]
+	if !ok {
+		return nil, ErrAuthorizationNotFound
+	}
+	return authorization, nil
+}
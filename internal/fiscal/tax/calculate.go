@@ -0,0 +1,216 @@
+package tax
+
+import (
+	"fmt"
+
+	"go-sales/pkg/decimal"
+)
+
+// Operation são os dados da venda que escolhem as regras: as UFs e os regimes das duas partes.
+type Operation struct {
+	OriginUF       string
+	DestinationUF  string
+	IssuerRegime   string
+	CustomerRegime string
+}
+
+// Line é um item do carrinho.
+type Line struct {
+	NCM        string
+	CFOP       string
+	Quantity   decimal.Decimal
+	UnitPrice  decimal.Decimal
+	Discount   decimal.Decimal
+	Freight    decimal.Decimal
+	Insurance  decimal.Decimal
+	OtherCosts decimal.Decimal
+}
+
+// Amount é um imposto calculado: o código de situação tributária, a base, a alíquota e o valor.
+type Amount struct {
+	Code   string
+	Base   decimal.Decimal
+	Rate   decimal.Decimal
+	Amount decimal.Decimal
+}
+
+// LineResult são os impostos de um item.
+type LineResult struct {
+	// RuleID é a regra aplicada.
+	RuleID int64
+	// Value é quantidade x preço unitário; Base é o valor do item com as despesas e sem o desconto.
+	Value decimal.Decimal
+	Base  decimal.Decimal
+	ICMS  Amount
+	// ICMSST tem o código do ICMS, a base do ICMS-ST, a alíquota interna do destino e o valor a reter.
+	ICMSST Amount
+	// SimplesCredit é o crédito de ICMS do Simples Nacional (CSOSN 101 e 201); não soma no total.
+	SimplesCredit Amount
+	IPI           Amount
+	PIS           Amount
+	COFINS        Amount
+	// Total é a base mais o IPI e o ICMS-ST, que são cobrados do cliente por fora do preço.
+	Total decimal.Decimal
+}
+
+// Totals soma os itens.
+type Totals struct {
+	Products   decimal.Decimal
+	Discount   decimal.Decimal
+	Freight    decimal.Decimal
+	Insurance  decimal.Decimal
+	OtherCosts decimal.Decimal
+	ICMSBase   decimal.Decimal
+	ICMS       decimal.Decimal
+	ICMSSTBase decimal.Decimal
+	ICMSST     decimal.Decimal
+	IPI        decimal.Decimal
+	PIS        decimal.Decimal
+	COFINS     decimal.Decimal
+	Total      decimal.Decimal
+}
+
+type Result struct {
+	Lines  []LineResult
+	Totals Totals
+}
+
+// LineError é um problema em um item (numerado a partir de 1), como a falta de regra que se aplique.
+type LineError struct {
+	Line    int
+	Problem string
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("tax: line %d: %s", e.Line, e.Problem)
+}
+
+// Calculate calcula os impostos de cada item com a regra mais específica que se aplica a ele. Cada
+// valor é arredondado para 2 casas (metade para cima) e os totais somam os valores arredondados, como
+// na NF-e.
+func Calculate(op Operation, lines []Line, rules []Rule) (*Result, error) {
+	result := &Result{Lines: make([]LineResult, len(lines))}
+	for i, line := range lines {
+		if line.Quantity.Sign() <= 0 || line.UnitPrice.Sign() < 0 {
+			return nil, &LineError{Line: i + 1, Problem: "quantity must be positive and unit price cannot be negative"}
+		}
+		rule := findRule(rules, op, line)
+		if rule == nil {
+			return nil, &LineError{Line: i + 1, Problem: "no tax rule matches this line"}
+		}
+		lineResult, err := calculateLine(op, line, rule)
+		if err != nil {
+			return nil, &LineError{Line: i + 1, Problem: err.Error()}
+		}
+		result.Lines[i] = *lineResult
+		result.Totals.add(line, lineResult)
+	}
+	return result, nil
+}
+
+func (t *Totals) add(line Line, r *LineResult) {
+	t.Products = t.Products.Add(r.Value)
+	t.Discount = t.Discount.Add(line.Discount.Round(2))
+	t.Freight = t.Freight.Add(line.Freight.Round(2))
+	t.Insurance = t.Insurance.Add(line.Insurance.Round(2))
+	t.OtherCosts = t.OtherCosts.Add(line.OtherCosts.Round(2))
+	t.ICMSBase = t.ICMSBase.Add(r.ICMS.Base)
+	t.ICMS = t.ICMS.Add(r.ICMS.Amount)
+	t.ICMSSTBase = t.ICMSSTBase.Add(r.ICMSST.Base)
+	t.ICMSST = t.ICMSST.Add(r.ICMSST.Amount)
+	t.IPI = t.IPI.Add(r.IPI.Amount)
+	t.PIS = t.PIS.Add(r.PIS.Amount)
+	t.COFINS = t.COFINS.Add(r.COFINS.Amount)
+	t.Total = t.Total.Add(r.Total)
+}
+
+func calculateLine(op Operation, line Line, rule *Rule) (*LineResult, error) {
+	value := line.Quantity.Mul(line.UnitPrice).Round(2)
+	discount := line.Discount.Round(2)
+	if discount.Sign() < 0 || discount.Cmp(value) > 0 {
+		return nil, fmt.Errorf("discount must be between 0 and the line value")
+	}
+	base := decimal.Sum(value, line.Freight.Round(2), line.Insurance.Round(2), line.OtherCosts.Round(2)).Sub(discount)
+	r := &LineResult{RuleID: rule.ID, Value: value, Base: base}
+
+	// IPI: calculado sobre o valor da operação.
+	r.IPI.Code = rule.IPICode
+	if ipiTaxedCST[rule.IPICode] {
+		r.IPI.Base = base
+		r.IPI.Rate = rule.IPIRate
+		r.IPI.Amount = base.Percent(rule.IPIRate).Round(2)
+	}
+
+	// Para o consumidor final não contribuinte o IPI integra a base do ICMS.
+	icmsBase := base
+	if op.CustomerRegime == CustomerNonContributor {
+		icmsBase = icmsBase.Add(r.IPI.Amount)
+	}
+	// ownICMS é o ICMS da operação, deduzido do ICMS-ST. No Simples ele não é destacado na nota.
+	var ownICMS decimal.Decimal
+	var withST bool
+	r.ICMS.Code = rule.ICMSCode
+	r.ICMSST.Code = rule.ICMSCode
+
+	if op.IssuerRegime == RegimeSimples {
+		if !icmsCSOSN[rule.ICMSCode] {
+			return nil, fmt.Errorf("rule %d: icmsCode %q is not a CSOSN, but the issuer is in the Simples Nacional", rule.ID, rule.ICMSCode)
+		}
+		if simplesCreditCSOSN[rule.ICMSCode] {
+			r.SimplesCredit = Amount{Code: rule.ICMSCode, Base: base, Rate: rule.SimplesCreditRate, Amount: base.Percent(rule.SimplesCreditRate).Round(2)}
+		}
+		withST = simplesSTCSOSN[rule.ICMSCode]
+		ownICMS = reduce(icmsBase, rule.ICMSBaseReduction).Percent(rule.ICMSRate).Round(2)
+	} else {
+		if !icmsCST[rule.ICMSCode] {
+			return nil, fmt.Errorf("rule %d: icmsCode %q is not a CST, but the issuer is in the normal regime", rule.ID, rule.ICMSCode)
+		}
+		switch rule.ICMSCode {
+		case "00", "10", "20", "70":
+			r.ICMS.Base = reduce(icmsBase, rule.ICMSBaseReduction)
+			r.ICMS.Rate = rule.ICMSRate
+			r.ICMS.Amount = r.ICMS.Base.Percent(rule.ICMSRate).Round(2)
+			ownICMS = r.ICMS.Amount
+		}
+		withST = icmsSTCST[rule.ICMSCode]
+	}
+
+	// ICMS-ST: a base é o valor com o IPI e a margem (MVA); o imposto retido é o ICMS dessa base pela
+	// alíquota interna do destino menos o ICMS próprio da operação.
+	if withST {
+		stBase := base.Add(r.IPI.Amount)
+		stBase = stBase.Add(stBase.Percent(rule.ICMSSTMVA))
+		r.ICMSST.Base = reduce(stBase, rule.ICMSSTBaseReduction)
+		r.ICMSST.Rate = rule.ICMSSTRate
+		r.ICMSST.Amount = r.ICMSST.Base.Percent(rule.ICMSSTRate).Round(2).Sub(ownICMS)
+		if r.ICMSST.Amount.Sign() < 0 {
+			r.ICMSST.Amount = decimal.Zero
+		}
+	}
+
+	// PIS e COFINS: sobre o valor da operação, sem o IPI e, se a regra mandar, sem o ICMS próprio.
+	pisBase := base
+	if rule.ExcludeICMSFromPISCOFINS {
+		pisBase = pisBase.Sub(r.ICMS.Amount)
+	}
+	r.PIS = contribution(rule.PISCode, pisBase, rule.PISRate)
+	r.COFINS = contribution(rule.COFINSCode, pisBase, rule.COFINSRate)
+
+	r.Total = decimal.Sum(base, r.IPI.Amount, r.ICMSST.Amount)
+	return r, nil
+}
+
+// reduce aplica a redução de base de cálculo (em %) e arredonda a base para 2 casas.
+func reduce(base, reduction decimal.Decimal) decimal.Decimal {
+	if reduction.IsZero() {
+		return base
+	}
+	return base.Sub(base.Percent(reduction)).Round(2)
+}
+
+func contribution(code string, base, rate decimal.Decimal) Amount {
+	if !pisTaxedCST(code) {
+		return Amount{Code: code}
+	}
+	return Amount{Code: code, Base: base, Rate: rate, Amount: base.Percent(rate).Round(2)}
+}
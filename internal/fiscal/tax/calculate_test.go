@@ -0,0 +1,121 @@
+package tax
+
+import (
+	"testing"
+
+	"go-sales/pkg/decimal"
+)
+
+// testRules são as regras de test/tax/post_tax_rule.http; os casos abaixo são os exemplos conferidos
+// de test/tax/post_tax_preview.http.
+var testRules = []Rule{
+	{
+		ID: 1, OriginUF: "SP", DestinationUF: "MG", IssuerRegime: RegimeNormal,
+		ICMSCode: "10", ICMSRate: decimal.MustParse("12"), ICMSSTMVA: decimal.MustParse("40"), ICMSSTRate: decimal.MustParse("18"),
+		IPICode: "50", IPIRate: decimal.MustParse("10"),
+		PISCode: "01", PISRate: decimal.MustParse("1.65"), COFINSCode: "01", COFINSRate: decimal.MustParse("7.6"),
+		ExcludeICMSFromPISCOFINS: true,
+	},
+	{
+		ID: 2, IssuerRegime: RegimeSimples,
+		ICMSCode: "101", SimplesCreditRate: decimal.MustParse("2.56"),
+		PISCode: "49", COFINSCode: "49",
+	},
+	{
+		ID: 3, OriginUF: "SP", DestinationUF: "SP", NCM: "8471", CustomerRegime: CustomerNonContributor, IssuerRegime: RegimeNormal,
+		ICMSCode: "00", ICMSRate: decimal.MustParse("18"),
+		IPICode: "50", IPIRate: decimal.MustParse("15"),
+		PISCode: "01", PISRate: decimal.MustParse("1.65"), COFINSCode: "01", COFINSRate: decimal.MustParse("7.6"),
+	},
+}
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		name string
+		op   Operation
+		line Line
+
+		wantRule       int64
+		wantICMS       string
+		wantICMSST     string
+		wantSimples    string
+		wantIPI        string
+		wantPIS        string
+		wantCOFINS     string
+		wantTotal      string
+		wantICMSBase   string
+		wantICMSSTBase string
+	}{
+		{
+			name: "ICMS-ST on an interstate sale to a contributor",
+			op:   Operation{OriginUF: "SP", DestinationUF: "MG", IssuerRegime: RegimeNormal, CustomerRegime: CustomerContributor},
+			line: Line{NCM: "22021000", CFOP: "6401", Quantity: decimal.MustParse("10"), UnitPrice: decimal.MustParse("100.00")},
+
+			wantRule: 1, wantICMS: "120.00", wantICMSST: "157.20", wantSimples: "0", wantIPI: "100.00",
+			wantPIS: "14.52", wantCOFINS: "66.88", wantTotal: "1257.20", wantICMSBase: "1000.00", wantICMSSTBase: "1540.00",
+		},
+		{
+			name: "IPI in the ICMS base for a final consumer",
+			op:   Operation{OriginUF: "SP", DestinationUF: "SP", IssuerRegime: RegimeNormal, CustomerRegime: CustomerNonContributor},
+			line: Line{
+				NCM: "84713012", CFOP: "5102", Quantity: decimal.MustParse("2"), UnitPrice: decimal.MustParse("1500"),
+				Discount: decimal.MustParse("100"), Freight: decimal.MustParse("50"),
+			},
+
+			wantRule: 3, wantICMS: "610.65", wantICMSST: "0", wantSimples: "0", wantIPI: "442.50",
+			wantPIS: "48.68", wantCOFINS: "224.20", wantTotal: "3392.50", wantICMSBase: "3392.50", wantICMSSTBase: "0",
+		},
+		{
+			name: "Simples Nacional ICMS credit",
+			op:   Operation{OriginUF: "SP", DestinationUF: "SP", IssuerRegime: RegimeSimples, CustomerRegime: CustomerContributor},
+			line: Line{NCM: "22021000", CFOP: "5102", Quantity: decimal.MustParse("10"), UnitPrice: decimal.MustParse("100")},
+
+			wantRule: 2, wantICMS: "0", wantICMSST: "0", wantSimples: "25.60", wantIPI: "0",
+			wantPIS: "0", wantCOFINS: "0", wantTotal: "1000.00", wantICMSBase: "0", wantICMSSTBase: "0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Calculate(tt.op, []Line{tt.line}, testRules)
+			if err != nil {
+				t.Fatalf("Calculate: %v", err)
+			}
+			line := result.Lines[0]
+			if line.RuleID != tt.wantRule {
+				t.Fatalf("rule = %d, want %d", line.RuleID, tt.wantRule)
+			}
+			amounts := []struct {
+				name string
+				got  decimal.Decimal
+				want string
+			}{
+				{"ICMS base", line.ICMS.Base, tt.wantICMSBase},
+				{"ICMS", line.ICMS.Amount, tt.wantICMS},
+				{"ICMS-ST base", line.ICMSST.Base, tt.wantICMSSTBase},
+				{"ICMS-ST", line.ICMSST.Amount, tt.wantICMSST},
+				{"Simples credit", line.SimplesCredit.Amount, tt.wantSimples},
+				{"IPI", line.IPI.Amount, tt.wantIPI},
+				{"PIS", line.PIS.Amount, tt.wantPIS},
+				{"COFINS", line.COFINS.Amount, tt.wantCOFINS},
+				{"line total", line.Total, tt.wantTotal},
+				{"invoice total", result.Totals.Total, tt.wantTotal},
+			}
+			for _, a := range amounts {
+				if !a.got.Equal(decimal.MustParse(a.want)) {
+					t.Errorf("%s = %s, want %s", a.name, a.got, a.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateWithoutRule(t *testing.T) {
+	op := Operation{OriginUF: "SP", DestinationUF: "RJ", IssuerRegime: RegimeNormal, CustomerRegime: CustomerContributor}
+	line := Line{NCM: "22021000", CFOP: "6102", Quantity: decimal.MustParse("1"), UnitPrice: decimal.MustParse("10")}
+
+	_, err := Calculate(op, []Line{line}, testRules)
+	lineErr, ok := err.(*LineError)
+	if !ok || lineErr.Line != 1 {
+		t.Fatalf("Calculate = %v, want a LineError for line 1", err)
+	}
+}
@@ -0,0 +1,177 @@
+// Package tax calcula os impostos de uma venda (ICMS, ICMS-ST, IPI, PIS e COFINS) a partir de regras
+// mantidas por cada empresa. O pacote não acessa o banco: as regras chegam prontas em Calculate.
+package tax
+
+import (
+	"strings"
+
+	"go-sales/pkg/decimal"
+	"go-sales/pkg/util"
+)
+
+// Regimes tributários do emitente (a empresa).
+const (
+	RegimeNormal  = "normal"
+	RegimeSimples = "simples_nacional"
+)
+
+// Regimes do destinatário perante o ICMS.
+const (
+	// CustomerContributor é o contribuinte do ICMS no regime normal.
+	CustomerContributor = "contributor"
+	// CustomerSimples é o contribuinte do ICMS optante pelo Simples Nacional.
+	CustomerSimples = "simples_nacional"
+	// CustomerNonContributor é o consumidor final não contribuinte; o IPI entra na base do ICMS.
+	CustomerNonContributor = "non_contributor"
+)
+
+// Rule diz como tributar os itens que casam com os seus critérios. Critérios vazios casam com
+// qualquer valor. Quando várias regras casam, vale a mais específica (ver Rule.moreSpecificThan).
+type Rule struct {
+	ID int64
+
+	OriginUF      string
+	DestinationUF string
+	// NCM é um prefixo do NCM do produto, de 2 a 8 dígitos (capítulo, posição, subposição...).
+	NCM            string
+	CFOP           string
+	CustomerRegime string
+	IssuerRegime   string
+
+	// ICMSCode é o CST (2 dígitos) para o emitente no regime normal ou o CSOSN (3 dígitos) no Simples.
+	ICMSCode string
+	// ICMSRate é a alíquota da operação (interna ou interestadual). No Simples só é usada para deduzir
+	// o ICMS próprio do ICMS-ST.
+	ICMSRate          decimal.Decimal
+	ICMSBaseReduction decimal.Decimal
+	// SimplesCreditRate é a alíquota do crédito de ICMS que o Simples permite destacar (CSOSN 101 e 201).
+	SimplesCreditRate decimal.Decimal
+	// ICMSSTMVA é a margem de valor agregado do ICMS-ST, já ajustada para operações interestaduais.
+	ICMSSTMVA decimal.Decimal
+	// ICMSSTRate é a alíquota interna da UF de destino usada no ICMS-ST.
+	ICMSSTRate          decimal.Decimal
+	ICMSSTBaseReduction decimal.Decimal
+
+	IPICode    string
+	IPIRate    decimal.Decimal
+	PISCode    string
+	PISRate    decimal.Decimal
+	COFINSCode string
+	COFINSRate decimal.Decimal
+	// ExcludeICMSFromPISCOFINS tira o ICMS próprio da base do PIS e da COFINS (Tema 69 do STF).
+	ExcludeICMSFromPISCOFINS bool
+}
+
+// matches diz se a regra se aplica ao item na operação.
+func (r *Rule) matches(op Operation, line Line) bool {
+	return matchField(r.OriginUF, op.OriginUF) &&
+		matchField(r.DestinationUF, op.DestinationUF) &&
+		strings.HasPrefix(util.OnlyDigits(line.NCM), r.NCM) &&
+		matchField(r.CFOP, line.CFOP) &&
+		matchField(r.CustomerRegime, op.CustomerRegime) &&
+		matchField(r.IssuerRegime, op.IssuerRegime)
+}
+
+func matchField(rule, value string) bool {
+	return rule == "" || rule == value
+}
+
+// moreSpecificThan ordena as regras que casam com o mesmo item: vence o prefixo de NCM mais longo;
+// depois, a regra que fixa o CFOP, a UF de destino, a UF de origem, o regime do destinatário e o do
+// emitente, nesta ordem. Empatadas, vale a de menor ID (a mais antiga).
+func (r *Rule) moreSpecificThan(other *Rule) bool {
+	if len(r.NCM) != len(other.NCM) {
+		return len(r.NCM) > len(other.NCM)
+	}
+	criteria := [][2]string{
+		{r.CFOP, other.CFOP},
+		{r.DestinationUF, other.DestinationUF},
+		{r.OriginUF, other.OriginUF},
+		{r.CustomerRegime, other.CustomerRegime},
+		{r.IssuerRegime, other.IssuerRegime},
+	}
+	for _, pair := range criteria {
+		if (pair[0] != "") != (pair[1] != "") {
+			return pair[0] != ""
+		}
+	}
+	return r.ID < other.ID
+}
+
+// findRule devolve a regra mais específica para o item, ou nil.
+func findRule(rules []Rule, op Operation, line Line) *Rule {
+	var best *Rule
+	for i := range rules {
+		rule := &rules[i]
+		if rule.matches(op, line) && (best == nil || rule.moreSpecificThan(best)) {
+			best = rule
+		}
+	}
+	return best
+}
+
+// CSTs e CSOSNs suportados.
+var (
+	icmsCST = map[string]bool{"00": true, "10": true, "20": true, "40": true, "41": true, "50": true, "60": true, "70": true}
+	// icmsSTCST são os CSTs com ICMS-ST calculado na operação.
+	icmsSTCST = map[string]bool{"10": true, "70": true}
+	icmsCSOSN = map[string]bool{"101": true, "102": true, "103": true, "201": true, "202": true, "203": true, "300": true, "400": true, "500": true}
+	// simplesSTCSOSN são os CSOSNs com ICMS-ST calculado na operação.
+	simplesSTCSOSN = map[string]bool{"201": true, "202": true, "203": true}
+	// simplesCreditCSOSN são os CSOSNs com crédito de ICMS destacado.
+	simplesCreditCSOSN = map[string]bool{"101": true, "201": true}
+	ipiTaxedCST        = map[string]bool{"00": true, "49": true, "50": true, "99": true}
+	ipiUntaxedCST      = map[string]bool{"01": true, "02": true, "03": true, "04": true, "05": true, "51": true, "52": true, "53": true, "54": true, "55": true}
+	pisUntaxedCST      = map[string]bool{"04": true, "05": true, "06": true, "07": true, "08": true, "09": true}
+)
+
+// pisTaxedCST aceita os CSTs de PIS e COFINS calculados por alíquota: 01, 02 e 49 a 99.
+func pisTaxedCST(cst string) bool {
+	if cst == "01" || cst == "02" {
+		return true
+	}
+	return len(cst) == 2 && util.OnlyDigits(cst) == cst && cst >= "49"
+}
+
+// Validate confere os códigos e percentuais da regra e devolve os problemas encontrados.
+func (r *Rule) Validate() []string {
+	var problems []string
+	check := func(ok bool, problem string) {
+		if !ok {
+			problems = append(problems, problem)
+		}
+	}
+	check(r.OriginUF == "" || util.IsValidUF(r.OriginUF), "originUf must be a valid UF")
+	check(r.DestinationUF == "" || util.IsValidUF(r.DestinationUF), "destinationUf must be a valid UF")
+	check(r.NCM == "" || len(r.NCM) >= 2 && len(r.NCM) <= 8 && util.OnlyDigits(r.NCM) == r.NCM, "ncm must be a prefix with 2 to 8 digits")
+	check(r.CFOP == "" || len(r.CFOP) == 4 && util.OnlyDigits(r.CFOP) == r.CFOP, "cfop must have 4 digits")
+	check(r.CustomerRegime == "" || r.CustomerRegime == CustomerContributor || r.CustomerRegime == CustomerSimples || r.CustomerRegime == CustomerNonContributor, "customerRegime is invalid")
+	check(r.IssuerRegime == "" || r.IssuerRegime == RegimeNormal || r.IssuerRegime == RegimeSimples, "issuerRegime is invalid")
+
+	switch r.IssuerRegime {
+	case RegimeNormal:
+		check(icmsCST[r.ICMSCode], "icmsCode must be a supported CST for the normal regime")
+	case RegimeSimples:
+		check(icmsCSOSN[r.ICMSCode], "icmsCode must be a supported CSOSN for the Simples Nacional")
+	default:
+		check(icmsCST[r.ICMSCode] || icmsCSOSN[r.ICMSCode], "icmsCode must be a supported CST or CSOSN")
+	}
+	check(r.IPICode == "" || ipiTaxedCST[r.IPICode] || ipiUntaxedCST[r.IPICode], "ipiCode is not a supported CST")
+	check(pisTaxedCST(r.PISCode) || pisUntaxedCST[r.PISCode], "pisCode is not a supported CST")
+	check(pisTaxedCST(r.COFINSCode) || pisUntaxedCST[r.COFINSCode], "cofinsCode is not a supported CST")
+
+	hundred := decimal.FromInt(100)
+	percentages := []struct {
+		name  string
+		value decimal.Decimal
+	}{
+		{"icmsRate", r.ICMSRate}, {"icmsBaseReduction", r.ICMSBaseReduction}, {"simplesCreditRate", r.SimplesCreditRate},
+		{"icmsStRate", r.ICMSSTRate}, {"icmsStBaseReduction", r.ICMSSTBaseReduction},
+		{"ipiRate", r.IPIRate}, {"pisRate", r.PISRate}, {"cofinsRate", r.COFINSRate},
+	}
+	for _, p := range percentages {
+		check(p.value.Sign() >= 0 && p.value.Cmp(hundred) <= 0, p.name+" must be between 0 and 100")
+	}
+	check(r.ICMSSTMVA.Sign() >= 0, "icmsStMva cannot be negative")
+	return problems
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"go-sales/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+type AddressHandler struct {
+	provider service.AddressProvider
+}
+
+func NewAddressHandler(provider service.AddressProvider) *AddressHandler {
+	return &AddressHandler{
+		provider: provider,
+	}
+}
+
+// Lookup devolve cidade, UF e código IBGE de um CEP (GET /addresses/lookup?postalCode=01310100).
+func (h *AddressHandler) Lookup(c *gin.Context) {
+	postalCode := c.Query("postalCode")
+	log.Info().Str("postal_code", postalCode).Msg("Looking up postal code")
+
+	address, err := h.provider.LookupPostalCode(postalCode)
+	if err != nil {
+		HandleError(err, "AddressHandler.Lookup error", c)
+		return
+	}
+	c.JSON(http.StatusOK, address)
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"go-sales/internal/config"
+	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
+	"go-sales/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// BankAccountHandler encapsula a dependência do serviço de contas bancárias.
+type BankAccountHandler struct {
+	service service.BankAccountServiceInterface
+	cfg     *config.Config
+}
+
+// NewBankAccountHandler cria uma nova instância do handler de contas bancárias.
+func NewBankAccountHandler(s service.BankAccountServiceInterface, cfg *config.Config) *BankAccountHandler {
+	return &BankAccountHandler{
+		service: s,
+		cfg:     cfg,
+	}
+}
+
+// Create cadastra uma conta bancária (POST /bank-accounts).
+func (h *BankAccountHandler) Create(c *gin.Context) {
+	log.Info().Msg("Creating a new bank account")
+
+	createDTO, utilError := GetValidatedDTO[*dto.CreateBankAccountDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "BankAccountHandler.Create - Error getting validated DTO", c)
+		return
+	}
+
+	account, err := h.service.Create(*createDTO)
+	if err != nil {
+		HandleError(err, "BankAccountHandler.Create error", c)
+		return
+	}
+	SetETag(c, account.Version)
+	c.JSON(http.StatusCreated, account)
+}
+
+// Patch aplica um JSON Merge Patch na conta bancária.
+func (h *BankAccountHandler) Patch(c *gin.Context) {
+	patchDTO, utilError := GetValidatedDTO[*dto.UpdateBankAccountDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "BankAccountHandler.Patch - Error getting validated DTO", c)
+		return
+	}
+
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "BankAccountHandler.Patch - Error parsing ID", c)
+		return
+	}
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "BankAccountHandler.Patch - Error reading If-Match", c)
+		return
+	}
+
+	account, err := h.service.Patch(*patchDTO, id, version)
+	if err != nil {
+		HandleError(err, "BankAccountHandler.Patch error", c)
+		return
+	}
+	SetETag(c, account.Version)
+	c.JSON(http.StatusOK, account)
+}
+
+func (h *BankAccountHandler) Delete(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "BankAccountHandler.Delete - Error parsing ID", c)
+		return
+	}
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "BankAccountHandler.Delete - Error reading If-Match", c)
+		return
+	}
+
+	if err := h.service.Delete(id, version); err != nil {
+		HandleError(err, "BankAccountHandler.Delete error", c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *BankAccountHandler) FindByID(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "BankAccountHandler.FindByID - Error parsing ID", c)
+		return
+	}
+
+	opts := GetReadOptions(c, true)
+	account, err := h.service.FindByID(id, opts)
+	if err != nil {
+		HandleError(err, "BankAccountHandler.FindByID error", c)
+		return
+	}
+	SetETag(c, account.Version)
+	if NotModified(c, account.Version) {
+		return
+	}
+	c.JSON(http.StatusOK, mapper.Project(account, opts))
+}
+
+func (h *BankAccountHandler) FindAll(c *gin.Context) {
+	page := GetPageRequest(c, h.cfg.AppDefaultAPIPageSize)
+	opts := GetReadOptions(c, false)
+
+	companyGlobalID, err := strconv.ParseInt(c.Query("companyGlobalId"), 10, 64)
+	if err != nil {
+		customError := service.NewError("invalid companyGlobalId format", http.StatusBadRequest, "invalid_company_global_id_format")
+		HandleError(customError, "BankAccountHandler.FindAll - Error parsing companyGlobalId", c)
+		return
+	}
+	if companyGlobalID == 0 {
+		customError := service.NewError("companyGlobalId is required", http.StatusBadRequest, "company_global_id_required")
+		HandleError(customError, "BankAccountHandler.FindAll - companyGlobalId is required", c)
+		return
+	}
+
+	result, errFindAll := h.service.FindAll(c.Request.URL.Query(), page, opts, companyGlobalID)
+	if errFindAll != nil {
+		HandleError(errFindAll, "BankAccountHandler.FindAll error", c)
+		return
+	}
+	c.JSON(http.StatusOK, mapper.ProjectPage(result, opts))
+}
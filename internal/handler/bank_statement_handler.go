@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"go-sales/internal/config"
+	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
+	"go-sales/internal/service"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxStatementFileSize limita o corpo de POST /bank-accounts/:id/statements (10 MB).
+const maxStatementFileSize = 10 << 20
+
+// BankStatementHandler encapsula a dependência do serviço de extratos bancários.
+type BankStatementHandler struct {
+	service service.BankStatementServiceInterface
+	cfg     *config.Config
+}
+
+// NewBankStatementHandler cria uma nova instância do handler de extratos bancários.
+func NewBankStatementHandler(s service.BankStatementServiceInterface, cfg *config.Config) *BankStatementHandler {
+	return &BankStatementHandler{
+		service: s,
+		cfg:     cfg,
+	}
+}
+
+// Import recebe o extrato no campo "file" de um multipart/form-data. ?format=ofx|csv força o formato;
+// sem ele, o formato é detectado pela extensão e pelo conteúdo do arquivo.
+func (h *BankStatementHandler) Import(c *gin.Context) {
+	bankAccountID, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "BankStatementHandler.Import - Error parsing ID", c)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxStatementFileSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		HandleError(service.ErrInvalidStatementFile, "BankStatementHandler.Import - missing file: "+err.Error(), c)
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		HandleError(service.ErrInvalidStatementFile, "BankStatementHandler.Import - error opening file: "+err.Error(), c)
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		HandleError(service.ErrInvalidStatementFile, "BankStatementHandler.Import - error reading file: "+err.Error(), c)
+		return
+	}
+
+	result, errImport := h.service.Import(bankAccountID, c.Query("format"), fileHeader.Filename, content)
+	if errImport != nil {
+		HandleError(errImport, "BankStatementHandler.Import error", c)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+// FindLines lista os lançamentos dos extratos da conta (GET /bank-accounts/:id/statement-lines).
+func (h *BankStatementHandler) FindLines(c *gin.Context) {
+	bankAccountID, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "BankStatementHandler.FindLines - Error parsing ID", c)
+		return
+	}
+	page := GetPageRequest(c, h.cfg.AppDefaultAPIPageSize)
+	opts := GetReadOptions(c, false)
+
+	result, err := h.service.FindLines(c.Request.URL.Query(), page, opts, bankAccountID)
+	if err != nil {
+		HandleError(err, "BankStatementHandler.FindLines error", c)
+		return
+	}
+	c.JSON(http.StatusOK, mapper.ProjectPage(result, opts))
+}
+
+func (h *BankStatementHandler) FindLine(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "BankStatementHandler.FindLine - Error parsing ID", c)
+		return
+	}
+
+	line, err := h.service.FindLine(id)
+	if err != nil {
+		HandleError(err, "BankStatementHandler.FindLine error", c)
+		return
+	}
+	c.JSON(http.StatusOK, line)
+}
+
+// Confirm aceita a parcela sugerida para o lançamento (POST /bank-statement-lines/:id/confirm).
+func (h *BankStatementHandler) Confirm(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "BankStatementHandler.Confirm - Error parsing ID", c)
+		return
+	}
+
+	line, err := h.service.Confirm(id)
+	if err != nil {
+		HandleError(err, "BankStatementHandler.Confirm error", c)
+		return
+	}
+	c.JSON(http.StatusOK, line)
+}
+
+// Reject descarta a parcela sugerida para o lançamento (POST /bank-statement-lines/:id/reject).
+func (h *BankStatementHandler) Reject(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "BankStatementHandler.Reject - Error parsing ID", c)
+		return
+	}
+
+	line, err := h.service.Reject(id)
+	if err != nil {
+		HandleError(err, "BankStatementHandler.Reject error", c)
+		return
+	}
+	c.JSON(http.StatusOK, line)
+}
+
+// Link concilia o lançamento com a parcela informada (POST /bank-statement-lines/:id/link).
+func (h *BankStatementHandler) Link(c *gin.Context) {
+	linkDTO, utilError := GetValidatedDTO[*dto.LinkStatementLineDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "BankStatementHandler.Link - Error getting validated DTO", c)
+		return
+	}
+
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "BankStatementHandler.Link - Error parsing ID", c)
+		return
+	}
+
+	line, err := h.service.Link(id, *linkDTO)
+	if err != nil {
+		HandleError(err, "BankStatementHandler.Link error", c)
+		return
+	}
+	c.JSON(http.StatusOK, line)
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"fmt"
+	"go-sales/internal/dto"
+	"go-sales/internal/service"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxReturnFileSize limita o corpo de POST /bank-accounts/:id/returns (10 MB).
+const maxReturnFileSize = 10 << 20
+
+// BoletoHandler encapsula a dependência do serviço de boletos.
+type BoletoHandler struct {
+	service service.BoletoServiceInterface
+}
+
+// NewBoletoHandler cria uma nova instância do handler de boletos.
+func NewBoletoHandler(s service.BoletoServiceInterface) *BoletoHandler {
+	return &BoletoHandler{service: s}
+}
+
+// Create emite um boleto para a parcela (POST /installments/:id/boletos), com o código de barras e a
+// linha digitável.
+func (h *BoletoHandler) Create(c *gin.Context) {
+	createDTO, utilError := GetValidatedDTO[*dto.CreateBoletoDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "BoletoHandler.Create - Error getting validated DTO", c)
+		return
+	}
+
+	installmentID, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "BoletoHandler.Create - Error parsing ID", c)
+		return
+	}
+
+	slip, err := h.service.Create(installmentID, *createDTO)
+	if err != nil {
+		HandleError(err, "BoletoHandler.Create error", c)
+		return
+	}
+	c.JSON(http.StatusCreated, slip)
+}
+
+// FindByInstallment lista os boletos da parcela (GET /installments/:id/boletos).
+func (h *BoletoHandler) FindByInstallment(c *gin.Context) {
+	installmentID, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "BoletoHandler.FindByInstallment - Error parsing ID", c)
+		return
+	}
+
+	slips, err := h.service.FindByInstallment(installmentID)
+	if err != nil {
+		HandleError(err, "BoletoHandler.FindByInstallment error", c)
+		return
+	}
+	c.JSON(http.StatusOK, slips)
+}
+
+func (h *BoletoHandler) FindByID(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "BoletoHandler.FindByID - Error parsing ID", c)
+		return
+	}
+
+	slip, err := h.service.FindByID(id)
+	if err != nil {
+		HandleError(err, "BoletoHandler.FindByID error", c)
+		return
+	}
+	c.JSON(http.StatusOK, slip)
+}
+
+// CreateRemittance gera o arquivo de remessa com os boletos pendentes da conta
+// (POST /bank-accounts/:id/remittances).
+func (h *BoletoHandler) CreateRemittance(c *gin.Context) {
+	createDTO, utilError := GetValidatedDTO[*dto.CreateBoletoRemittanceDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "BoletoHandler.CreateRemittance - Error getting validated DTO", c)
+		return
+	}
+
+	bankAccountID, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "BoletoHandler.CreateRemittance - Error parsing ID", c)
+		return
+	}
+
+	remittance, err := h.service.CreateRemittance(bankAccountID, *createDTO)
+	if err != nil {
+		HandleError(err, "BoletoHandler.CreateRemittance error", c)
+		return
+	}
+	c.JSON(http.StatusCreated, remittance)
+}
+
+func (h *BoletoHandler) FindRemittance(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "BoletoHandler.FindRemittance - Error parsing ID", c)
+		return
+	}
+
+	remittance, err := h.service.FindRemittance(id)
+	if err != nil {
+		HandleError(err, "BoletoHandler.FindRemittance error", c)
+		return
+	}
+	c.JSON(http.StatusOK, remittance)
+}
+
+// RemittanceFile baixa o arquivo de remessa (GET /boleto-remittances/:id/file).
+func (h *BoletoHandler) RemittanceFile(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "BoletoHandler.RemittanceFile - Error parsing ID", c)
+		return
+	}
+
+	name, content, err := h.service.RemittanceFile(id)
+	if err != nil {
+		HandleError(err, "BoletoHandler.RemittanceFile error", c)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	c.Data(http.StatusOK, "text/plain; charset=us-ascii", content)
+}
+
+// ProcessReturn recebe um arquivo de retorno CNAB 240 ou 400 no campo multipart "file"
+// (POST /bank-accounts/:id/returns), baixa as parcelas pagas e responde com o relatório de conciliação.
+func (h *BoletoHandler) ProcessReturn(c *gin.Context) {
+	bankAccountID, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "BoletoHandler.ProcessReturn - Error parsing ID", c)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxReturnFileSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		HandleError(service.ErrInvalidReturnFile, "BoletoHandler.ProcessReturn - missing file: "+err.Error(), c)
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		HandleError(service.ErrInvalidReturnFile, "BoletoHandler.ProcessReturn - error opening file: "+err.Error(), c)
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		HandleError(service.ErrInvalidReturnFile, "BoletoHandler.ProcessReturn - error reading file: "+err.Error(), c)
+		return
+	}
+
+	report, errReturn := h.service.ProcessReturn(bankAccountID, fileHeader.Filename, content)
+	if errReturn != nil {
+		HandleError(errReturn, "BoletoHandler.ProcessReturn error", c)
+		return
+	}
+	c.JSON(http.StatusCreated, report)
+}
+
+// FindReturn devolve o relatório de um arquivo de retorno processado (GET /boleto-returns/:id).
+func (h *BoletoHandler) FindReturn(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "BoletoHandler.FindReturn - Error parsing ID", c)
+		return
+	}
+
+	report, err := h.service.FindReturn(id)
+	if err != nil {
+		HandleError(err, "BoletoHandler.FindReturn error", c)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
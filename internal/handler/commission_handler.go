@@ -0,0 +1,342 @@
+package handler
+
+import (
+	"go-sales/internal/auth"
+	"go-sales/internal/config"
+	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
+	"go-sales/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// CommissionHandler encapsula a dependência do serviço de comissões.
+type CommissionHandler struct {
+	service service.CommissionServiceInterface
+	cfg     *config.Config
+}
+
+// NewCommissionHandler cria uma nova instância do handler de comissões.
+func NewCommissionHandler(s service.CommissionServiceInterface, cfg *config.Config) *CommissionHandler {
+	return &CommissionHandler{
+		service: s,
+		cfg:     cfg,
+	}
+}
+
+// CreatePlan cadastra um plano de comissão (POST /commission-plans).
+func (h *CommissionHandler) CreatePlan(c *gin.Context) {
+	log.Info().Msg("Creating a new commission plan")
+
+	createDTO, utilError := GetValidatedDTO[*dto.CreateCommissionPlanDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "CommissionHandler.CreatePlan - Error getting validated DTO", c)
+		return
+	}
+
+	plan, err := h.service.CreatePlan(*createDTO)
+	if err != nil {
+		HandleError(err, "CommissionHandler.CreatePlan error", c)
+		return
+	}
+	SetETag(c, plan.Version)
+	c.JSON(http.StatusCreated, plan)
+}
+
+// PatchPlan aplica um JSON Merge Patch no plano de comissão.
+func (h *CommissionHandler) PatchPlan(c *gin.Context) {
+	patchDTO, utilError := GetValidatedDTO[*dto.UpdateCommissionPlanDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "CommissionHandler.PatchPlan - Error getting validated DTO", c)
+		return
+	}
+
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "CommissionHandler.PatchPlan - Error parsing ID", c)
+		return
+	}
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "CommissionHandler.PatchPlan - Error reading If-Match", c)
+		return
+	}
+
+	plan, err := h.service.PatchPlan(*patchDTO, id, version)
+	if err != nil {
+		HandleError(err, "CommissionHandler.PatchPlan error", c)
+		return
+	}
+	SetETag(c, plan.Version)
+	c.JSON(http.StatusOK, plan)
+}
+
+func (h *CommissionHandler) DeletePlan(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "CommissionHandler.DeletePlan - Error parsing ID", c)
+		return
+	}
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "CommissionHandler.DeletePlan - Error reading If-Match", c)
+		return
+	}
+
+	if err := h.service.DeletePlan(id, version); err != nil {
+		HandleError(err, "CommissionHandler.DeletePlan error", c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *CommissionHandler) FindPlanByID(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "CommissionHandler.FindPlanByID - Error parsing ID", c)
+		return
+	}
+
+	opts := GetReadOptions(c, true)
+	plan, err := h.service.FindPlanByID(id, opts)
+	if err != nil {
+		HandleError(err, "CommissionHandler.FindPlanByID error", c)
+		return
+	}
+	SetETag(c, plan.Version)
+	if NotModified(c, plan.Version) {
+		return
+	}
+	c.JSON(http.StatusOK, mapper.Project(plan, opts))
+}
+
+func (h *CommissionHandler) FindPlans(c *gin.Context) {
+	page := GetPageRequest(c, h.cfg.AppDefaultAPIPageSize)
+	opts := GetReadOptions(c, false)
+
+	companyGlobalID, err := strconv.ParseInt(c.Query("companyGlobalId"), 10, 64)
+	if err != nil {
+		customError := service.NewError("invalid companyGlobalId format", http.StatusBadRequest, "invalid_company_global_id_format")
+		HandleError(customError, "CommissionHandler.FindPlans - Error parsing companyGlobalId", c)
+		return
+	}
+	if companyGlobalID == 0 {
+		customError := service.NewError("companyGlobalId is required", http.StatusBadRequest, "company_global_id_required")
+		HandleError(customError, "CommissionHandler.FindPlans - companyGlobalId is required", c)
+		return
+	}
+
+	result, errFindAll := h.service.FindPlans(c.Request.URL.Query(), page, opts, companyGlobalID)
+	if errFindAll != nil {
+		HandleError(errFindAll, "CommissionHandler.FindPlans error", c)
+		return
+	}
+	c.JSON(http.StatusOK, mapper.ProjectPage(result, opts))
+}
+
+// CreateAssignment atribui o plano a um usuário ou a uma role (POST /commission-plans/:id/assignments).
+func (h *CommissionHandler) CreateAssignment(c *gin.Context) {
+	createDTO, utilError := GetValidatedDTO[*dto.CreateCommissionAssignmentDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "CommissionHandler.CreateAssignment - Error getting validated DTO", c)
+		return
+	}
+
+	planID, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "CommissionHandler.CreateAssignment - Error parsing ID", c)
+		return
+	}
+
+	assignment, err := h.service.CreateAssignment(*createDTO, planID)
+	if err != nil {
+		HandleError(err, "CommissionHandler.CreateAssignment error", c)
+		return
+	}
+	c.JSON(http.StatusCreated, assignment)
+}
+
+func (h *CommissionHandler) FindAssignments(c *gin.Context) {
+	planID, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "CommissionHandler.FindAssignments - Error parsing ID", c)
+		return
+	}
+
+	assignments, err := h.service.FindAssignments(planID)
+	if err != nil {
+		HandleError(err, "CommissionHandler.FindAssignments error", c)
+		return
+	}
+	c.JSON(http.StatusOK, assignments)
+}
+
+func (h *CommissionHandler) DeleteAssignment(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "CommissionHandler.DeleteAssignment - Error parsing ID", c)
+		return
+	}
+
+	if err := h.service.DeleteAssignment(id); err != nil {
+		HandleError(err, "CommissionHandler.DeleteAssignment error", c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Record lança a comissão de uma venda faturada (POST /commission-entries).
+func (h *CommissionHandler) Record(c *gin.Context) {
+	createDTO, utilError := GetValidatedDTO[*dto.CreateCommissionEntryDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "CommissionHandler.Record - Error getting validated DTO", c)
+		return
+	}
+
+	entry, err := h.service.Record(*createDTO)
+	if err != nil {
+		HandleError(err, "CommissionHandler.Record error", c)
+		return
+	}
+	c.JSON(http.StatusCreated, entry)
+}
+
+// Reverse estorna a comissão de uma venda cancelada ou devolvida (POST /commission-entries/:id/reverse).
+func (h *CommissionHandler) Reverse(c *gin.Context) {
+	reverseDTO, utilError := GetValidatedDTO[*dto.ReverseCommissionEntryDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "CommissionHandler.Reverse - Error getting validated DTO", c)
+		return
+	}
+
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "CommissionHandler.Reverse - Error parsing ID", c)
+		return
+	}
+
+	entry, err := h.service.Reverse(*reverseDTO, id)
+	if err != nil {
+		HandleError(err, "CommissionHandler.Reverse error", c)
+		return
+	}
+	c.JSON(http.StatusCreated, entry)
+}
+
+func (h *CommissionHandler) FindEntry(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "CommissionHandler.FindEntry - Error parsing ID", c)
+		return
+	}
+
+	entry, err := h.service.FindEntry(id)
+	if err != nil {
+		HandleError(err, "CommissionHandler.FindEntry error", c)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+func (h *CommissionHandler) FindEntries(c *gin.Context) {
+	page := GetPageRequest(c, h.cfg.AppDefaultAPIPageSize)
+	opts := GetReadOptions(c, false)
+
+	companyGlobalID, err := strconv.ParseInt(c.Query("companyGlobalId"), 10, 64)
+	if err != nil {
+		customError := service.NewError("invalid companyGlobalId format", http.StatusBadRequest, "invalid_company_global_id_format")
+		HandleError(customError, "CommissionHandler.FindEntries - Error parsing companyGlobalId", c)
+		return
+	}
+	if companyGlobalID == 0 {
+		customError := service.NewError("companyGlobalId is required", http.StatusBadRequest, "company_global_id_required")
+		HandleError(customError, "CommissionHandler.FindEntries - companyGlobalId is required", c)
+		return
+	}
+
+	filters := c.Request.URL.Query()
+	// Accept: text/csv ou application/x-ndjson exporta todos os lançamentos dos filtros, sem paginação.
+	if format := GetExportFormat(c); format != "" {
+		StreamExport(c, format, "commission-entries", opts, "CommissionHandler.FindEntries export error", func(fn func(*dto.CommissionEntryDTO) error) service.ErrorUtil {
+			return h.service.Export(filters, opts, companyGlobalID, fn)
+		})
+		return
+	}
+
+	result, errFindAll := h.service.FindEntries(filters, page, opts, companyGlobalID)
+	if errFindAll != nil {
+		HandleError(errFindAll, "CommissionHandler.FindEntries error", c)
+		return
+	}
+	c.JSON(http.StatusOK, mapper.ProjectPage(result, opts))
+}
+
+// FindStatements resume o mês de cada vendedor da empresa (GET /commission-statements?period=2006-01).
+func (h *CommissionHandler) FindStatements(c *gin.Context) {
+	companyGlobalID, err := strconv.ParseInt(c.Query("companyGlobalId"), 10, 64)
+	if err != nil {
+		customError := service.NewError("invalid companyGlobalId format", http.StatusBadRequest, "invalid_company_global_id_format")
+		HandleError(customError, "CommissionHandler.FindStatements - Error parsing companyGlobalId", c)
+		return
+	}
+	if companyGlobalID == 0 {
+		customError := service.NewError("companyGlobalId is required", http.StatusBadRequest, "company_global_id_required")
+		HandleError(customError, "CommissionHandler.FindStatements - companyGlobalId is required", c)
+		return
+	}
+
+	// Accept: text/csv ou application/x-ndjson exporta os extratos do mês em vez do JSON.
+	if format := GetExportFormat(c); format != "" {
+		opts := GetReadOptions(c, false)
+		StreamExport(c, format, "commission-statements", opts, "CommissionHandler.FindStatements export error", func(fn func(*dto.CommissionStatementDTO) error) service.ErrorUtil {
+			return h.service.ExportStatements(companyGlobalID, c.Query("period"), fn)
+		})
+		return
+	}
+
+	statements, errFind := h.service.FindStatements(companyGlobalID, c.Query("period"))
+	if errFind != nil {
+		HandleError(errFind, "CommissionHandler.FindStatements error", c)
+		return
+	}
+	c.JSON(http.StatusOK, statements)
+}
+
+// FindStatement devolve o extrato do vendedor no mês (GET /users/:id/commission-statements/:period).
+func (h *CommissionHandler) FindStatement(c *gin.Context) {
+	userID, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "CommissionHandler.FindStatement - Error parsing ID", c)
+		return
+	}
+
+	statement, err := h.service.FindStatement(userID, c.Param("period"))
+	if err != nil {
+		HandleError(err, "CommissionHandler.FindStatement error", c)
+		return
+	}
+	c.JSON(http.StatusOK, statement)
+}
+
+// Approve aprova o extrato do vendedor no mês; quem aprovou é o autor da requisição, que a rota
+// exige com a permissão commissions.approve.
+func (h *CommissionHandler) Approve(c *gin.Context) {
+	userID, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "CommissionHandler.Approve - Error parsing ID", c)
+		return
+	}
+
+	principal, _ := auth.FromContext(c)
+	statement, err := h.service.Approve(userID, c.Param("period"), principal)
+	if err != nil {
+		HandleError(err, "CommissionHandler.Approve error", c)
+		return
+	}
+	c.JSON(http.StatusOK, statement)
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"go-sales/internal/dto"
+	"go-sales/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// CompanyGlobalAddressHandler atende /company-globals/:id/addresses. O ETag de cada endereço é a sua
+// própria versão; alterar um endereço também muda o ETag da company.
+type CompanyGlobalAddressHandler struct {
+	service service.CompanyGlobalAddressServiceInterface
+}
+
+func NewCompanyGlobalAddressHandler(service service.CompanyGlobalAddressServiceInterface) *CompanyGlobalAddressHandler {
+	return &CompanyGlobalAddressHandler{
+		service: service,
+	}
+}
+
+func (h *CompanyGlobalAddressHandler) FindAll(c *gin.Context) {
+	companyID, errID := GetIDParam(c, "id")
+	if errID != nil {
+		HandleError(errID, "CompanyGlobalAddressHandler.FindAll - Error parsing ID", c)
+		return
+	}
+	log.Info().Int64("company_global_id", companyID).Msg("Finding company global addresses")
+
+	addresses, err := h.service.FindAll(companyID)
+	if err != nil {
+		HandleError(err, "CompanyGlobalAddressHandler.FindAll error", c)
+		return
+	}
+	c.JSON(http.StatusOK, addresses)
+}
+
+func (h *CompanyGlobalAddressHandler) FindByID(c *gin.Context) {
+	companyID, addressID, errID := getAddressIDs(c)
+	if errID != nil {
+		HandleError(errID, "CompanyGlobalAddressHandler.FindByID - Error parsing ID", c)
+		return
+	}
+	log.Info().Int64("company_global_id", companyID).Int64("address_id", addressID).Msg("Finding company global address by ID")
+
+	address, err := h.service.FindByID(companyID, addressID)
+	if err != nil {
+		HandleError(err, "CompanyGlobalAddressHandler.FindByID error", c)
+		return
+	}
+
+	SetETag(c, address.Version)
+	if NotModified(c, address.Version) {
+		return
+	}
+	c.JSON(http.StatusOK, address)
+}
+
+func (h *CompanyGlobalAddressHandler) Create(c *gin.Context) {
+	companyID, errID := GetIDParam(c, "id")
+	if errID != nil {
+		HandleError(errID, "CompanyGlobalAddressHandler.Create - Error parsing ID", c)
+		return
+	}
+	log.Info().Int64("company_global_id", companyID).Msg("Creating company global address")
+
+	addressDTO, utilError := GetValidatedDTO[*dto.SaveCompanyGlobalAddressDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "CompanyGlobalAddressHandler.Create - Error getting validated DTO", c)
+		return
+	}
+
+	address, err := h.service.Create(companyID, *addressDTO)
+	if err != nil {
+		HandleError(err, "CompanyGlobalAddressHandler.Create error", c)
+		return
+	}
+
+	SetETag(c, address.Version)
+	c.JSON(http.StatusCreated, address)
+}
+
+func (h *CompanyGlobalAddressHandler) Update(c *gin.Context) {
+	companyID, addressID, errID := getAddressIDs(c)
+	if errID != nil {
+		HandleError(errID, "CompanyGlobalAddressHandler.Update - Error parsing ID", c)
+		return
+	}
+	log.Info().Int64("company_global_id", companyID).Int64("address_id", addressID).Msg("Updating company global address")
+
+	addressDTO, utilError := GetValidatedDTO[*dto.SaveCompanyGlobalAddressDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "CompanyGlobalAddressHandler.Update - Error getting validated DTO", c)
+		return
+	}
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "CompanyGlobalAddressHandler.Update - Error reading If-Match", c)
+		return
+	}
+
+	address, err := h.service.Update(companyID, addressID, version, *addressDTO)
+	if err != nil {
+		HandleError(err, "CompanyGlobalAddressHandler.Update error", c)
+		return
+	}
+
+	SetETag(c, address.Version)
+	c.JSON(http.StatusOK, address)
+}
+
+func (h *CompanyGlobalAddressHandler) Delete(c *gin.Context) {
+	companyID, addressID, errID := getAddressIDs(c)
+	if errID != nil {
+		HandleError(errID, "CompanyGlobalAddressHandler.Delete - Error parsing ID", c)
+		return
+	}
+	log.Info().Int64("company_global_id", companyID).Int64("address_id", addressID).Msg("Deleting company global address")
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "CompanyGlobalAddressHandler.Delete - Error reading If-Match", c)
+		return
+	}
+
+	if err := h.service.Delete(companyID, addressID, version); err != nil {
+		HandleError(err, "CompanyGlobalAddressHandler.Delete error", c)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func getAddressIDs(c *gin.Context) (int64, int64, service.ErrorUtil) {
+	companyID, err := GetIDParam(c, "id")
+	if err != nil {
+		return 0, 0, err
+	}
+	addressID, err := GetIDParam(c, "addressId")
+	if err != nil {
+		return 0, 0, err
+	}
+	return companyID, addressID, nil
+}
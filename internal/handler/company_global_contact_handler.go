@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"go-sales/internal/dto"
+	"go-sales/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// CompanyGlobalContactHandler atende /company-globals/:id/contacts. O ETag de cada contato é a sua
+// própria versão; alterar um contato também muda o ETag da company.
+type CompanyGlobalContactHandler struct {
+	service service.CompanyGlobalContactServiceInterface
+}
+
+func NewCompanyGlobalContactHandler(service service.CompanyGlobalContactServiceInterface) *CompanyGlobalContactHandler {
+	return &CompanyGlobalContactHandler{
+		service: service,
+	}
+}
+
+func (h *CompanyGlobalContactHandler) FindAll(c *gin.Context) {
+	companyID, errID := GetIDParam(c, "id")
+	if errID != nil {
+		HandleError(errID, "CompanyGlobalContactHandler.FindAll - Error parsing ID", c)
+		return
+	}
+	log.Info().Int64("company_global_id", companyID).Msg("Finding company global contacts")
+
+	contacts, err := h.service.FindAll(companyID)
+	if err != nil {
+		HandleError(err, "CompanyGlobalContactHandler.FindAll error", c)
+		return
+	}
+	c.JSON(http.StatusOK, contacts)
+}
+
+func (h *CompanyGlobalContactHandler) FindByID(c *gin.Context) {
+	companyID, contactID, errID := getContactIDs(c)
+	if errID != nil {
+		HandleError(errID, "CompanyGlobalContactHandler.FindByID - Error parsing ID", c)
+		return
+	}
+	log.Info().Int64("company_global_id", companyID).Int64("contact_id", contactID).Msg("Finding company global contact by ID")
+
+	contact, err := h.service.FindByID(companyID, contactID)
+	if err != nil {
+		HandleError(err, "CompanyGlobalContactHandler.FindByID error", c)
+		return
+	}
+
+	SetETag(c, contact.Version)
+	if NotModified(c, contact.Version) {
+		return
+	}
+	c.JSON(http.StatusOK, contact)
+}
+
+func (h *CompanyGlobalContactHandler) Create(c *gin.Context) {
+	companyID, errID := GetIDParam(c, "id")
+	if errID != nil {
+		HandleError(errID, "CompanyGlobalContactHandler.Create - Error parsing ID", c)
+		return
+	}
+	log.Info().Int64("company_global_id", companyID).Msg("Creating company global contact")
+
+	contactDTO, utilError := GetValidatedDTO[*dto.CreateCompanyGlobalContactDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "CompanyGlobalContactHandler.Create - Error getting validated DTO", c)
+		return
+	}
+
+	contact, err := h.service.Create(companyID, *contactDTO)
+	if err != nil {
+		HandleError(err, "CompanyGlobalContactHandler.Create error", c)
+		return
+	}
+
+	SetETag(c, contact.Version)
+	c.JSON(http.StatusCreated, contact)
+}
+
+func (h *CompanyGlobalContactHandler) Update(c *gin.Context) {
+	companyID, contactID, errID := getContactIDs(c)
+	if errID != nil {
+		HandleError(errID, "CompanyGlobalContactHandler.Update - Error parsing ID", c)
+		return
+	}
+	log.Info().Int64("company_global_id", companyID).Int64("contact_id", contactID).Msg("Updating company global contact")
+
+	contactDTO, utilError := GetValidatedDTO[*dto.CreateCompanyGlobalContactDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "CompanyGlobalContactHandler.Update - Error getting validated DTO", c)
+		return
+	}
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "CompanyGlobalContactHandler.Update - Error reading If-Match", c)
+		return
+	}
+
+	contact, err := h.service.Update(companyID, contactID, version, *contactDTO)
+	if err != nil {
+		HandleError(err, "CompanyGlobalContactHandler.Update error", c)
+		return
+	}
+
+	SetETag(c, contact.Version)
+	c.JSON(http.StatusOK, contact)
+}
+
+func (h *CompanyGlobalContactHandler) Delete(c *gin.Context) {
+	companyID, contactID, errID := getContactIDs(c)
+	if errID != nil {
+		HandleError(errID, "CompanyGlobalContactHandler.Delete - Error parsing ID", c)
+		return
+	}
+	log.Info().Int64("company_global_id", companyID).Int64("contact_id", contactID).Msg("Deleting company global contact")
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "CompanyGlobalContactHandler.Delete - Error reading If-Match", c)
+		return
+	}
+
+	if err := h.service.Delete(companyID, contactID, version); err != nil {
+		HandleError(err, "CompanyGlobalContactHandler.Delete error", c)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func getContactIDs(c *gin.Context) (int64, int64, service.ErrorUtil) {
+	companyID, err := GetIDParam(c, "id")
+	if err != nil {
+		return 0, 0, err
+	}
+	contactID, err := GetIDParam(c, "contactId")
+	if err != nil {
+		return 0, 0, err
+	}
+	return companyID, contactID, nil
+}
@@ -1,13 +1,12 @@
 package handler
 
 import (
-	"fmt"
 	"go-sales/internal/config"
 	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
 	"go-sales/internal/service"
 	"go-sales/pkg/util"
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
@@ -80,17 +79,57 @@ func (h *CompanyGlobalHandler) Update(c *gin.Context) {
 		return
 	}
 
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "CompanyGlobalHandler.Update - Error reading If-Match", c)
+		return
+	}
+
 	// 2. Chamar a Camada de Serviço
-	updatedCompany, errUpdate := h.service.Update(*updateCompanyDTO, id)
+	updatedCompany, errUpdate := h.service.Update(*updateCompanyDTO, id, version)
 	if errUpdate != nil {
 		HandleError(errUpdate, "CompanyGlobalHandler.Update error", c)
 		return
 	}
 
 	// 4. Retornar Resposta de Sucesso
+	SetETag(c, updatedCompany.Version)
 	c.JSON(http.StatusOK, updatedCompany)
 }
 
+// Patch aplica um JSON Merge Patch (RFC 7396) na empresa global.
+func (h *CompanyGlobalHandler) Patch(c *gin.Context) {
+	idStr := c.Param("id")
+	log.Info().Str("id", idStr).Msg("Patching company global")
+	patchCompanyDTO, utilError := GetValidatedDTO[*dto.UpdateCompanyGlobalDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "CompanyGlobalHandler.Patch - Error getting validated DTO", c)
+		return
+	}
+
+	id, err := util.ParseSnowflake(idStr)
+	if err != nil {
+		customError := service.NewError(err.Error(), http.StatusBadRequest, "invalid_id_format")
+		HandleError(customError, "CompanyGlobalHandler.Patch - Error parsing ID", c)
+		return
+	}
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "CompanyGlobalHandler.Patch - Error reading If-Match", c)
+		return
+	}
+
+	patchedCompany, errPatch := h.service.Patch(*patchCompanyDTO, id, version)
+	if errPatch != nil {
+		HandleError(errPatch, "CompanyGlobalHandler.Patch error", c)
+		return
+	}
+
+	SetETag(c, patchedCompany.Version)
+	c.JSON(http.StatusOK, patchedCompany)
+}
+
 // Delete é o método do handler para apagar uma empresa global.
 func (h *CompanyGlobalHandler) Delete(c *gin.Context) {
 	idStr := c.Param("id")
@@ -102,8 +141,14 @@ func (h *CompanyGlobalHandler) Delete(c *gin.Context) {
 		return
 	}
 
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "CompanyGlobalHandler.Delete - Error reading If-Match", c)
+		return
+	}
+
 	// 2. Chamar a Camada de Serviço
-	if err := h.service.Delete(id); err != nil {
+	if err := h.service.Delete(id, version); err != nil {
 		HandleError(err, "CompanyGlobalHandler.Delete error", c)
 		return
 	}
@@ -123,6 +168,10 @@ func (h *CompanyGlobalHandler) FindByCGC(c *gin.Context) {
 	}
 
 	// 4. Retornar Resposta de Sucesso
+	SetETag(c, company.Version)
+	if NotModified(c, company.Version) {
+		return
+	}
 	c.JSON(http.StatusOK, company)
 }
 
@@ -136,34 +185,40 @@ func (h *CompanyGlobalHandler) FindByID(c *gin.Context) {
 		return
 	}
 
-	company, errFind := h.service.FindByID(id)
+	opts := GetReadOptions(c, true)
+	company, errFind := h.service.FindByID(id, opts)
 	if errFind != nil {
 		HandleError(errFind, "CompanyGlobalHandler.FindByID error", c)
 		return
 	}
 
 	// 4. Retornar Resposta de Sucesso
-	c.JSON(http.StatusOK, company)
+	SetETag(c, company.Version)
+	if NotModified(c, company.Version) {
+		return
+	}
+	c.JSON(http.StatusOK, mapper.Project(company, opts))
 }
 
 func (h *CompanyGlobalHandler) FindAll(c *gin.Context) {
 	log.Info().Msg("Fetching all company globals")
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		page = 1
-	}
-
-	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", fmt.Sprintf("%d", h.cfg.AppDefaultAPIPageSize)))
-	if err != nil || pageSize < 1 {
-		pageSize = h.cfg.AppDefaultAPIPageSize
-	}
+	page := GetPageRequest(c, h.cfg.AppDefaultAPIPageSize)
+	opts := GetReadOptions(c, false)
 
 	// 2. Extrai os filtros.
 	filters := c.Request.URL.Query()
 
 	// 2. Chamar a Camada de Serviço, passando os filtros.
 	// 3. Chama o serviço.
-	paginatedResult, customErr := h.service.FindAll(filters, page, pageSize)
+	// Accept: text/csv ou application/x-ndjson exporta todas as linhas dos filtros, sem paginação.
+	if format := GetExportFormat(c); format != "" {
+		StreamExport(c, format, "company-globals", opts, "CompanyGlobalHandler.FindAll export error", func(fn func(*dto.CompanyGlobalDTO) error) service.ErrorUtil {
+			return h.service.Export(filters, opts, fn)
+		})
+		return
+	}
+
+	paginatedResult, customErr := h.service.FindAll(filters, page, opts)
 	if customErr != nil {
 
 		HandleError(customErr, "CompanyGlobalHandler.FindAll error", c)
@@ -172,5 +227,5 @@ func (h *CompanyGlobalHandler) FindAll(c *gin.Context) {
 	}
 
 	// 4. Retorna o resultado paginado.
-	c.JSON(http.StatusOK, paginatedResult)
+	c.JSON(http.StatusOK, mapper.ProjectPage(paginatedResult, opts))
 }
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"go-sales/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CredentialHandler struct {
+	service service.CredentialServiceInterface
+}
+
+func NewCredentialHandler(service service.CredentialServiceInterface) *CredentialHandler {
+	return &CredentialHandler{
+		service: service,
+	}
+}
+
+// Unlock desbloqueia uma conta bloqueada por senhas erradas (POST /users/:id/unlock). Usuários de
+// outra empresa não são encontrados.
+func (h *CredentialHandler) Unlock(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "CredentialHandler.Unlock - Error parsing ID", c)
+		return
+	}
+
+	if customErr := h.service.Unlock(id, GetPrincipalCompanyGlobalID(c)); customErr != nil {
+		HandleError(customErr, "CredentialHandler.Unlock - Error unlocking user", c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
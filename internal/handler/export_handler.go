@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
+	"go-sales/internal/service"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// MIMECSV e MIMENDJSON são os formatos de exportação aceitos no Accept das listagens.
+	MIMECSV    = "text/csv"
+	MIMENDJSON = "application/x-ndjson"
+
+	// exportFlushRows é a quantidade de linhas escritas entre cada envio parcial ao cliente.
+	exportFlushRows = 500
+)
+
+// GetExportFormat devolve o formato de exportação pedido no Accept (text/csv ou application/x-ndjson),
+// ou "" quando o cliente espera a listagem JSON paginada.
+func GetExportFormat(c *gin.Context) string {
+	switch format := c.NegotiateFormat(binding.MIMEJSON, MIMECSV, MIMENDJSON); format {
+	case MIMECSV, MIMENDJSON:
+		return format
+	default:
+		return ""
+	}
+}
+
+// StreamExport escreve a exportação de uma listagem no formato pedido, linha a linha, à medida que
+// export entrega os registros. Os parâmetros de paginação são ignorados: todas as linhas dos filtros
+// são exportadas. Um erro antes da primeira linha gera a resposta de erro normal; depois dela o status
+// já foi enviado, então a resposta é apenas interrompida.
+func StreamExport[T any](c *gin.Context, format string, name string, opts dto.ReadOptions, msg string, export func(func(*T) error) service.ErrorUtil) {
+	columns := mapper.ExportColumns[T](opts)
+	csvWriter := csv.NewWriter(c.Writer)
+	encoder := json.NewEncoder(c.Writer)
+	started := false
+	rows := 0
+
+	start := func() error {
+		started = true
+		extension := "csv"
+		if format == MIMENDJSON {
+			extension = "ndjson"
+		}
+		c.Header("Content-Type", format+"; charset=utf-8")
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+"."+extension))
+		c.Status(http.StatusOK)
+		if format == MIMECSV {
+			return csvWriter.Write(columns)
+		}
+		return nil
+	}
+
+	err := export(func(item *T) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		values := mapper.ExportValues(item, columns)
+		if format == MIMECSV {
+			record := make([]string, len(values))
+			for i, value := range values {
+				record[i] = formatCSVValue(value)
+			}
+			if err := csvWriter.Write(record); err != nil {
+				return err
+			}
+		} else {
+			line := make(map[string]any, len(columns))
+			for i, column := range columns {
+				line[column] = values[i]
+			}
+			if err := encoder.Encode(line); err != nil {
+				return err
+			}
+		}
+
+		rows++
+		if rows%exportFlushRows == 0 {
+			csvWriter.Flush()
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		if !started {
+			HandleError(err, msg, c)
+			return
+		}
+		log.Error().
+			Err(err).
+			Caller().
+			Int("rows", rows).
+			Msg(msg + " - export interrupted")
+		return
+	}
+
+	// Sem linhas, a exportação ainda devolve o cabeçalho do CSV (ou um NDJSON vazio).
+	if !started {
+		if err := start(); err != nil {
+			log.Error().Err(err).Caller().Msg(msg)
+			return
+		}
+	}
+	csvWriter.Flush()
+	c.Writer.Flush()
+}
+
+func formatCSVValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	default:
+		if reflect.ValueOf(v).Kind() == reflect.String {
+			return escapeCSVFormula(fmt.Sprint(v))
+		}
+		return fmt.Sprint(v)
+	}
+}
+
+// escapeCSVFormula prefixa com ' os textos que uma planilha interpretaria como fórmula (começando
+// com =, +, -, @, tab ou CR). Só textos passam por aqui: números negativos continuam números.
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
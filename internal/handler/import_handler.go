@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"go-sales/internal/service"
+	"go-sales/pkg/spreadsheet"
+	"go-sales/pkg/util"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// maxImportFileSize limita o corpo de POST /imports/{entity} (10 MB).
+const maxImportFileSize = 10 << 20
+
+type ImportHandler struct {
+	service service.ImportServiceInterface
+}
+
+func NewImportHandler(service service.ImportServiceInterface) *ImportHandler {
+	return &ImportHandler{
+		service: service,
+	}
+}
+
+// Create recebe uma planilha CSV ou XLSX no campo multipart "file" e inicia a importação em segundo
+// plano. Responde 202 com o job; o progresso e o relatório por linha ficam em GET /imports/jobs/{id}.
+// ?dryRun=true valida todas as linhas sem gravar nada; ?companyGlobalId= preenche a coluna ausente.
+func (h *ImportHandler) Create(c *gin.Context) {
+	entity := c.Param("entity")
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dryRun", "false"))
+	log.Info().Str("entity", entity).Bool("dry_run", dryRun).Msg("Starting import")
+
+	var companyGlobalID int64
+	if value := c.Query("companyGlobalId"); value != "" {
+		id, err := util.ParseSnowflake(value)
+		if err != nil {
+			customError := service.NewError("invalid companyGlobalId format", http.StatusBadRequest, "invalid_company_global_id_format")
+			HandleError(customError, "ImportHandler.Create - Error parsing companyGlobalId", c)
+			return
+		}
+		companyGlobalID = id
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		HandleError(service.ErrInvalidImportFile, "ImportHandler.Create - missing file: "+err.Error(), c)
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		HandleError(service.ErrInvalidImportFile, "ImportHandler.Create - error opening file: "+err.Error(), c)
+		return
+	}
+	defer file.Close()
+
+	var rows [][]string
+	switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+	case ".csv":
+		rows, err = spreadsheet.ReadCSV(file)
+	case ".xlsx":
+		rows, err = spreadsheet.ReadXLSX(file, fileHeader.Size)
+	default:
+		HandleError(service.ErrInvalidImportFile, "ImportHandler.Create - unsupported file extension", c)
+		return
+	}
+	if err != nil {
+		HandleError(service.ErrInvalidImportFile, "ImportHandler.Create - error reading spreadsheet: "+err.Error(), c)
+		return
+	}
+
+	job, customErr := h.service.Start(entity, fileHeader.Filename, rows, dryRun, companyGlobalID)
+	if customErr != nil {
+		HandleError(customErr, "ImportHandler.Create - Error starting import", c)
+		return
+	}
+	// FullPath é "<prefixo>/imports/:entity"; o job fica em "<prefixo>/imports/jobs/{id}".
+	c.Header("Location", strings.TrimSuffix(c.FullPath(), ":entity")+"jobs/"+job.ID.String())
+	c.JSON(http.StatusAccepted, job)
+}
+
+// FindByID devolve o andamento da importação e os erros das linhas já processadas.
+func (h *ImportHandler) FindByID(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "ImportHandler.FindByID - Error parsing ID", c)
+		return
+	}
+
+	job, customErr := h.service.FindByID(id)
+	if customErr != nil {
+		HandleError(customErr, "ImportHandler.FindByID - Error finding import job", c)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"go-sales/internal/config"
+	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
+	"go-sales/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// InstallmentHandler encapsula a dependência do serviço de parcelas.
+type InstallmentHandler struct {
+	service service.InstallmentServiceInterface
+	cfg     *config.Config
+}
+
+// NewInstallmentHandler cria uma nova instância do handler de parcelas.
+func NewInstallmentHandler(s service.InstallmentServiceInterface, cfg *config.Config) *InstallmentHandler {
+	return &InstallmentHandler{
+		service: s,
+		cfg:     cfg,
+	}
+}
+
+// Create cadastra uma parcela a receber (POST /installments).
+func (h *InstallmentHandler) Create(c *gin.Context) {
+	log.Info().Msg("Creating a new installment")
+
+	createDTO, utilError := GetValidatedDTO[*dto.CreateInstallmentDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "InstallmentHandler.Create - Error getting validated DTO", c)
+		return
+	}
+
+	installment, err := h.service.Create(*createDTO)
+	if err != nil {
+		HandleError(err, "InstallmentHandler.Create error", c)
+		return
+	}
+	SetETag(c, installment.Version)
+	c.JSON(http.StatusCreated, installment)
+}
+
+// Patch aplica um JSON Merge Patch na parcela.
+func (h *InstallmentHandler) Patch(c *gin.Context) {
+	patchDTO, utilError := GetValidatedDTO[*dto.UpdateInstallmentDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "InstallmentHandler.Patch - Error getting validated DTO", c)
+		return
+	}
+
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "InstallmentHandler.Patch - Error parsing ID", c)
+		return
+	}
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "InstallmentHandler.Patch - Error reading If-Match", c)
+		return
+	}
+
+	installment, err := h.service.Patch(*patchDTO, id, version)
+	if err != nil {
+		HandleError(err, "InstallmentHandler.Patch error", c)
+		return
+	}
+	SetETag(c, installment.Version)
+	c.JSON(http.StatusOK, installment)
+}
+
+func (h *InstallmentHandler) Delete(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "InstallmentHandler.Delete - Error parsing ID", c)
+		return
+	}
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "InstallmentHandler.Delete - Error reading If-Match", c)
+		return
+	}
+
+	if err := h.service.Delete(id, version); err != nil {
+		HandleError(err, "InstallmentHandler.Delete error", c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *InstallmentHandler) FindByID(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "InstallmentHandler.FindByID - Error parsing ID", c)
+		return
+	}
+
+	opts := GetReadOptions(c, true)
+	installment, err := h.service.FindByID(id, opts)
+	if err != nil {
+		HandleError(err, "InstallmentHandler.FindByID error", c)
+		return
+	}
+	SetETag(c, installment.Version)
+	if NotModified(c, installment.Version) {
+		return
+	}
+	c.JSON(http.StatusOK, mapper.Project(installment, opts))
+}
+
+func (h *InstallmentHandler) FindAll(c *gin.Context) {
+	page := GetPageRequest(c, h.cfg.AppDefaultAPIPageSize)
+	opts := GetReadOptions(c, false)
+
+	companyGlobalID, err := strconv.ParseInt(c.Query("companyGlobalId"), 10, 64)
+	if err != nil {
+		customError := service.NewError("invalid companyGlobalId format", http.StatusBadRequest, "invalid_company_global_id_format")
+		HandleError(customError, "InstallmentHandler.FindAll - Error parsing companyGlobalId", c)
+		return
+	}
+	if companyGlobalID == 0 {
+		customError := service.NewError("companyGlobalId is required", http.StatusBadRequest, "company_global_id_required")
+		HandleError(customError, "InstallmentHandler.FindAll - companyGlobalId is required", c)
+		return
+	}
+
+	result, errFindAll := h.service.FindAll(c.Request.URL.Query(), page, opts, companyGlobalID)
+	if errFindAll != nil {
+		HandleError(errFindAll, "InstallmentHandler.FindAll error", c)
+		return
+	}
+	c.JSON(http.StatusOK, mapper.ProjectPage(result, opts))
+}
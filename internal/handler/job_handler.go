@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"go-sales/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JobHandler struct {
+	service service.JobServiceInterface
+}
+
+func NewJobHandler(service service.JobServiceInterface) *JobHandler {
+	return &JobHandler{
+		service: service,
+	}
+}
+
+// FindByID devolve o estado de um job da fila (GET /jobs/:id).
+func (h *JobHandler) FindByID(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "JobHandler.FindByID - Error parsing ID", c)
+		return
+	}
+
+	job, customErr := h.service.FindByID(id)
+	if customErr != nil {
+		HandleError(customErr, "JobHandler.FindByID - Error finding job", c)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
@@ -1,9 +1,9 @@
 package handler
 
 import (
-	"fmt"
 	"go-sales/internal/config"
 	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
 	"go-sales/internal/service"
 	"go-sales/pkg/util"
 	"net/http"
@@ -64,15 +64,56 @@ func (h *PermissionHandler) Update(c *gin.Context) {
 		return
 	}
 
-	updatedPermission, errUpdate := h.service.Update(*updatePermissionDTO, id)
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "PermissionHandler.Update - Error reading If-Match", c)
+		return
+	}
+
+	updatedPermission, errUpdate := h.service.Update(*updatePermissionDTO, id, version)
 	if errUpdate != nil {
 		HandleError(errUpdate, "PermissionHandler.Update error", c)
 		return
 	}
 
+	SetETag(c, updatedPermission.Version)
 	c.JSON(http.StatusOK, updatedPermission)
 }
 
+// Patch aplica um JSON Merge Patch (RFC 7396) na permissão.
+func (h *PermissionHandler) Patch(c *gin.Context) {
+	idStr := c.Param("id")
+	log.Info().Str("permission_id", idStr).Msg("Patching permission")
+
+	patchPermissionDTO, utilError := GetValidatedDTO[*dto.UpdatePermissionDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "PermissionHandler.Patch - Error getting validated DTO", c)
+		return
+	}
+
+	id, err := util.ParseSnowflake(idStr)
+	if err != nil {
+		customError := service.NewError(err.Error(), http.StatusBadRequest, "invalid_id_format")
+		HandleError(customError, "PermissionHandler.Patch - Error parsing ID", c)
+		return
+	}
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "PermissionHandler.Patch - Error reading If-Match", c)
+		return
+	}
+
+	patchedPermission, errPatch := h.service.Patch(*patchPermissionDTO, id, version)
+	if errPatch != nil {
+		HandleError(errPatch, "PermissionHandler.Patch error", c)
+		return
+	}
+
+	SetETag(c, patchedPermission.Version)
+	c.JSON(http.StatusOK, patchedPermission)
+}
+
 // Delete remove uma permissão.
 func (h *PermissionHandler) Delete(c *gin.Context) {
 	idStr := c.Param("id")
@@ -85,7 +126,13 @@ func (h *PermissionHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.Delete(id); err != nil {
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "PermissionHandler.Delete - Error reading If-Match", c)
+		return
+	}
+
+	if err := h.service.Delete(id, version); err != nil {
 		HandleError(err, "PermissionHandler.Delete error", c)
 		return
 	}
@@ -95,15 +142,8 @@ func (h *PermissionHandler) Delete(c *gin.Context) {
 // FindAll retorna todas as permissões paginadas.
 func (h *PermissionHandler) FindAll(c *gin.Context) {
 	log.Debug().Msg("Fetching all permissions")
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-
-	if err != nil || page < 1 {
-		page = 1
-	}
-	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", fmt.Sprintf("%d", h.cfg.AppDefaultAPIPageSize)))
-	if err != nil || pageSize < 1 {
-		pageSize = h.cfg.AppDefaultAPIPageSize
-	}
+	page := GetPageRequest(c, h.cfg.AppDefaultAPIPageSize)
+	opts := GetReadOptions(c, false)
 	companyGlobalId, err := strconv.ParseInt(c.Query("companyGlobalId"), 10, 64)
 	if err != nil {
 		customError := service.NewError("invalid companyGlobalId format", http.StatusBadRequest, "invalid_company_global_id_format")
@@ -117,7 +157,15 @@ func (h *PermissionHandler) FindAll(c *gin.Context) {
 	}
 
 	filters := c.Request.URL.Query()
-	paginatedResult, customErr := h.service.FindAll(filters, page, pageSize, companyGlobalId)
+	// Accept: text/csv ou application/x-ndjson exporta todas as linhas dos filtros, sem paginação.
+	if format := GetExportFormat(c); format != "" {
+		StreamExport(c, format, "permissions", opts, "PermissionHandler.FindAll export error", func(fn func(*dto.PermissionDTO) error) service.ErrorUtil {
+			return h.service.Export(filters, opts, companyGlobalId, fn)
+		})
+		return
+	}
+
+	paginatedResult, customErr := h.service.FindAll(filters, page, opts, companyGlobalId)
 	if customErr != nil {
 
 		HandleError(customErr, "PermissionHandler.FindAll error", c)
@@ -125,7 +173,7 @@ func (h *PermissionHandler) FindAll(c *gin.Context) {
 
 	}
 
-	c.JSON(http.StatusOK, paginatedResult)
+	c.JSON(http.StatusOK, mapper.ProjectPage(paginatedResult, opts))
 }
 
 // FindByID retorna uma permissão pelo ID.
@@ -140,10 +188,15 @@ func (h *PermissionHandler) FindByID(c *gin.Context) {
 		return
 	}
 
-	permission, errFind := h.service.FindByID(id)
+	opts := GetReadOptions(c, true)
+	permission, errFind := h.service.FindByID(id, opts)
 	if errFind != nil {
 		HandleError(errFind, "PermissionHandler.FindByID error", c)
 		return
 	}
-	c.JSON(http.StatusOK, permission)
+	SetETag(c, permission.Version)
+	if NotModified(c, permission.Version) {
+		return
+	}
+	c.JSON(http.StatusOK, mapper.Project(permission, opts))
 }
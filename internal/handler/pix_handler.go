@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"go-sales/internal/dto"
+	"go-sales/internal/service"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// maxPixNotificationSize limita o corpo das notificações do webhook PIX.
+const maxPixNotificationSize = 1 << 20
+
+// PixHandler encapsula a dependência do serviço de PIX.
+type PixHandler struct {
+	service service.PixServiceInterface
+}
+
+// NewPixHandler cria uma nova instância do handler de PIX.
+func NewPixHandler(s service.PixServiceInterface) *PixHandler {
+	return &PixHandler{service: s}
+}
+
+// CreateCharge gera uma cobrança PIX para a parcela (POST /installments/:id/pix-charges), com o
+// "copia e cola" em payload e o QR Code em qrCode.
+func (h *PixHandler) CreateCharge(c *gin.Context) {
+	createDTO, utilError := GetValidatedDTO[*dto.CreatePixChargeDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "PixHandler.CreateCharge - Error getting validated DTO", c)
+		return
+	}
+
+	installmentID, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "PixHandler.CreateCharge - Error parsing ID", c)
+		return
+	}
+
+	charge, err := h.service.CreateCharge(c.Request.Context(), installmentID, *createDTO)
+	if err != nil {
+		HandleError(err, "PixHandler.CreateCharge error", c)
+		return
+	}
+	c.JSON(http.StatusCreated, charge)
+}
+
+// FindByInstallment lista as cobranças PIX da parcela (GET /installments/:id/pix-charges).
+func (h *PixHandler) FindByInstallment(c *gin.Context) {
+	installmentID, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "PixHandler.FindByInstallment - Error parsing ID", c)
+		return
+	}
+
+	charges, err := h.service.FindByInstallment(installmentID)
+	if err != nil {
+		HandleError(err, "PixHandler.FindByInstallment error", c)
+		return
+	}
+	c.JSON(http.StatusOK, charges)
+}
+
+func (h *PixHandler) FindByID(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "PixHandler.FindByID - Error parsing ID", c)
+		return
+	}
+
+	charge, err := h.service.FindByID(id)
+	if err != nil {
+		HandleError(err, "PixHandler.FindByID error", c)
+		return
+	}
+	c.JSON(http.StatusOK, charge)
+}
+
+// QRCode devolve o QR Code da cobrança como imagem PNG (GET /pix-charges/:id/qrcode.png).
+func (h *PixHandler) QRCode(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "PixHandler.QRCode - Error parsing ID", c)
+		return
+	}
+
+	png, err := h.service.QRCode(id)
+	if err != nil {
+		HandleError(err, "PixHandler.QRCode error", c)
+		return
+	}
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// Webhook recebe as notificações de PIX recebidos do PSP (POST /pix/webhook). O corpo é lido cru
+// porque a assinatura é calculada sobre ele.
+func (h *PixHandler) Webhook(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPixNotificationSize))
+	if err != nil {
+		HandleError(service.ErrInvalidPixNotification, "PixHandler.Webhook - error reading body: "+err.Error(), c)
+		return
+	}
+
+	result, errWebhook := h.service.HandleWebhook(c.Request.Header, body)
+	if errWebhook != nil {
+		HandleError(errWebhook, "PixHandler.Webhook error", c)
+		return
+	}
+	log.Info().Int("notifications", len(result.Results)).Msg("PIX webhook processed")
+	c.JSON(http.StatusOK, result)
+}
@@ -1,9 +1,9 @@
 package handler
 
 import (
-	"fmt"
 	"go-sales/internal/config"
 	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
 	"go-sales/internal/service"
 	"go-sales/pkg/util"
 	"net/http"
@@ -61,14 +61,54 @@ func (h *RoleHandler) Update(c *gin.Context) {
 		return
 	}
 
-	updatedRole, errUpdate := h.service.Update(*updateRoleDTO, id)
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "RoleHandler.Update - Error reading If-Match", c)
+		return
+	}
+
+	updatedRole, errUpdate := h.service.Update(*updateRoleDTO, id, version)
 	if errUpdate != nil {
 		HandleError(errUpdate, "RoleHandler.Update error", c)
 		return
 	}
+	SetETag(c, updatedRole.Version)
 	c.JSON(http.StatusOK, updatedRole)
 }
 
+// Patch aplica um JSON Merge Patch (RFC 7396) na role.
+func (h *RoleHandler) Patch(c *gin.Context) {
+	idStr := c.Param("id")
+	log.Info().Str("role_id", idStr).Msg("Patching role")
+
+	patchRoleDTO, utilError := GetValidatedDTO[*dto.UpdateRoleDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "RoleHandler.Patch - Error getting validated DTO", c)
+		return
+	}
+
+	id, err := util.ParseSnowflake(idStr)
+	if err != nil {
+		customError := service.NewError(err.Error(), http.StatusBadRequest, "invalid_id_format")
+		HandleError(customError, "RoleHandler.Patch - Error parsing ID", c)
+		return
+	}
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "RoleHandler.Patch - Error reading If-Match", c)
+		return
+	}
+
+	patchedRole, errPatch := h.service.Patch(*patchRoleDTO, id, version)
+	if errPatch != nil {
+		HandleError(errPatch, "RoleHandler.Patch error", c)
+		return
+	}
+	SetETag(c, patchedRole.Version)
+	c.JSON(http.StatusOK, patchedRole)
+}
+
 func (h *RoleHandler) Delete(c *gin.Context) {
 	idStr := c.Param("id")
 	log.Info().Str("role_id", idStr).Msg("Deleting role")
@@ -79,7 +119,14 @@ func (h *RoleHandler) Delete(c *gin.Context) {
 		HandleError(customError, "RoleHandler.Delete - Error parsing ID", c)
 		return
 	}
-	if err := h.service.Delete(id); err != nil {
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "RoleHandler.Delete - Error reading If-Match", c)
+		return
+	}
+
+	if err := h.service.Delete(id, version); err != nil {
 		HandleError(err, "RoleHandler.Delete error", c)
 		return
 	}
@@ -88,14 +135,8 @@ func (h *RoleHandler) Delete(c *gin.Context) {
 
 func (h *RoleHandler) FindAll(c *gin.Context) {
 	log.Info().Msg("Fetching all roles")
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		page = 1
-	}
-	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", fmt.Sprintf("%d", h.cfg.AppDefaultAPIPageSize)))
-	if err != nil || pageSize < 1 {
-		pageSize = h.cfg.AppDefaultAPIPageSize
-	}
+	page := GetPageRequest(c, h.cfg.AppDefaultAPIPageSize)
+	opts := GetReadOptions(c, false)
 
 	companyGlobalId, err := strconv.ParseInt(c.Query("companyGlobalId"), 10, 64)
 	if err != nil {
@@ -110,12 +151,20 @@ func (h *RoleHandler) FindAll(c *gin.Context) {
 	}
 
 	filters := c.Request.URL.Query()
-	paginatedResult, errFindAll := h.service.FindAll(filters, page, pageSize, companyGlobalId)
+	// Accept: text/csv ou application/x-ndjson exporta todas as linhas dos filtros, sem paginação.
+	if format := GetExportFormat(c); format != "" {
+		StreamExport(c, format, "roles", opts, "RoleHandler.FindAll export error", func(fn func(*dto.RoleDTO) error) service.ErrorUtil {
+			return h.service.Export(filters, opts, companyGlobalId, fn)
+		})
+		return
+	}
+
+	paginatedResult, errFindAll := h.service.FindAll(filters, page, opts, companyGlobalId)
 	if errFindAll != nil {
 		HandleError(errFindAll, "RoleHandler.FindAll error", c)
 		return
 	}
-	c.JSON(http.StatusOK, paginatedResult)
+	c.JSON(http.StatusOK, mapper.ProjectPage(paginatedResult, opts))
 }
 
 func (h *RoleHandler) FindByID(c *gin.Context) {
@@ -127,10 +176,15 @@ func (h *RoleHandler) FindByID(c *gin.Context) {
 		HandleError(customError, "RoleHandler.FindByID - Error parsing ID", c)
 		return
 	}
-	role, errFind := h.service.FindByID(id)
+	opts := GetReadOptions(c, true)
+	role, errFind := h.service.FindByID(id, opts)
 	if errFind != nil {
 		HandleError(errFind, "RoleHandler.FindByID error", c)
 		return
 	}
-	c.JSON(http.StatusOK, role)
+	SetETag(c, role.Version)
+	if NotModified(c, role.Version) {
+		return
+	}
+	c.JSON(http.StatusOK, mapper.Project(role, opts))
 }
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"go-sales/internal/config"
+	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
+	"go-sales/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// ServiceAccountHandler encapsula a dependência do serviço de contas de serviço.
+type ServiceAccountHandler struct {
+	service service.ServiceAccountServiceInterface
+	cfg     *config.Config
+}
+
+// NewServiceAccountHandler cria uma nova instância do handler de contas de serviço.
+func NewServiceAccountHandler(s service.ServiceAccountServiceInterface, cfg *config.Config) *ServiceAccountHandler {
+	return &ServiceAccountHandler{
+		service: s,
+		cfg:     cfg,
+	}
+}
+
+func (h *ServiceAccountHandler) Create(c *gin.Context) {
+	log.Info().Msg("Creating a new service account")
+
+	createDTO, utilError := GetValidatedDTO[*dto.CreateServiceAccountDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "ServiceAccountHandler.Create - Error getting validated DTO", c)
+		return
+	}
+
+	account, err := h.service.Create(*createDTO, GetPrincipalCompanyGlobalID(c))
+	if err != nil {
+		HandleError(err, "ServiceAccountHandler.Create error", c)
+		return
+	}
+	SetETag(c, account.Version)
+	c.JSON(http.StatusCreated, account)
+}
+
+// Patch aplica um JSON Merge Patch na conta de serviço.
+func (h *ServiceAccountHandler) Patch(c *gin.Context) {
+	patchDTO, utilError := GetValidatedDTO[*dto.UpdateServiceAccountDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "ServiceAccountHandler.Patch - Error getting validated DTO", c)
+		return
+	}
+
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "ServiceAccountHandler.Patch - Error parsing ID", c)
+		return
+	}
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "ServiceAccountHandler.Patch - Error reading If-Match", c)
+		return
+	}
+
+	account, err := h.service.Patch(*patchDTO, id, version, GetPrincipalCompanyGlobalID(c))
+	if err != nil {
+		HandleError(err, "ServiceAccountHandler.Patch error", c)
+		return
+	}
+	SetETag(c, account.Version)
+	c.JSON(http.StatusOK, account)
+}
+
+// Delete apaga a conta de serviço e revoga as suas chaves.
+func (h *ServiceAccountHandler) Delete(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "ServiceAccountHandler.Delete - Error parsing ID", c)
+		return
+	}
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "ServiceAccountHandler.Delete - Error reading If-Match", c)
+		return
+	}
+
+	if err := h.service.Delete(id, version, GetPrincipalCompanyGlobalID(c)); err != nil {
+		HandleError(err, "ServiceAccountHandler.Delete error", c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ServiceAccountHandler) FindByID(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "ServiceAccountHandler.FindByID - Error parsing ID", c)
+		return
+	}
+
+	opts := GetReadOptions(c, true)
+	account, err := h.service.FindByID(id, opts, GetPrincipalCompanyGlobalID(c))
+	if err != nil {
+		HandleError(err, "ServiceAccountHandler.FindByID error", c)
+		return
+	}
+	SetETag(c, account.Version)
+	if NotModified(c, account.Version) {
+		return
+	}
+	c.JSON(http.StatusOK, mapper.Project(account, opts))
+}
+
+func (h *ServiceAccountHandler) FindAll(c *gin.Context) {
+	page := GetPageRequest(c, h.cfg.AppDefaultAPIPageSize)
+	opts := GetReadOptions(c, false)
+
+	companyGlobalID, err := strconv.ParseInt(c.Query("companyGlobalId"), 10, 64)
+	if err != nil {
+		customError := service.NewError("invalid companyGlobalId format", http.StatusBadRequest, "invalid_company_global_id_format")
+		HandleError(customError, "ServiceAccountHandler.FindAll - Error parsing companyGlobalId", c)
+		return
+	}
+	if companyGlobalID == 0 {
+		customError := service.NewError("companyGlobalId is required", http.StatusBadRequest, "company_global_id_required")
+		HandleError(customError, "ServiceAccountHandler.FindAll - companyGlobalId is required", c)
+		return
+	}
+	if companyGlobalID != GetPrincipalCompanyGlobalID(c) {
+		HandleError(service.ErrServiceAccountTenantMismatch, "ServiceAccountHandler.FindAll - companyGlobalId of another tenant", c)
+		return
+	}
+
+	result, errFindAll := h.service.FindAll(c.Request.URL.Query(), page, opts, companyGlobalID)
+	if errFindAll != nil {
+		HandleError(errFindAll, "ServiceAccountHandler.FindAll error", c)
+		return
+	}
+	c.JSON(http.StatusOK, mapper.ProjectPage(result, opts))
+}
+
+// CreateKey emite uma chave de API para a conta (POST /service-accounts/:id/keys).
+// A chave só aparece nesta resposta.
+func (h *ServiceAccountHandler) CreateKey(c *gin.Context) {
+	keyDTO, utilError := GetValidatedDTO[*dto.CreateAPIKeyDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "ServiceAccountHandler.CreateKey - Error getting validated DTO", c)
+		return
+	}
+
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "ServiceAccountHandler.CreateKey - Error parsing ID", c)
+		return
+	}
+
+	key, err := h.service.CreateKey(id, *keyDTO, GetPrincipalCompanyGlobalID(c))
+	if err != nil {
+		HandleError(err, "ServiceAccountHandler.CreateKey error", c)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, key)
+}
+
+// RotateKey troca uma chave por outra (POST /service-accounts/:id/keys/:keyId/rotate).
+func (h *ServiceAccountHandler) RotateKey(c *gin.Context) {
+	rotateDTO, utilError := GetValidatedDTO[*dto.RotateAPIKeyDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "ServiceAccountHandler.RotateKey - Error getting validated DTO", c)
+		return
+	}
+
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "ServiceAccountHandler.RotateKey - Error parsing ID", c)
+		return
+	}
+	keyID, customErr := GetIDParam(c, "keyId")
+	if customErr != nil {
+		HandleError(customErr, "ServiceAccountHandler.RotateKey - Error parsing key ID", c)
+		return
+	}
+
+	key, err := h.service.RotateKey(id, keyID, *rotateDTO, GetPrincipalCompanyGlobalID(c))
+	if err != nil {
+		HandleError(err, "ServiceAccountHandler.RotateKey error", c)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, key)
+}
+
+// FindKeys lista as chaves da conta, sem o segredo (GET /service-accounts/:id/keys).
+func (h *ServiceAccountHandler) FindKeys(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "ServiceAccountHandler.FindKeys - Error parsing ID", c)
+		return
+	}
+
+	keys, err := h.service.FindKeys(id, GetPrincipalCompanyGlobalID(c))
+	if err != nil {
+		HandleError(err, "ServiceAccountHandler.FindKeys error", c)
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// RevokeKey revoga uma chave (DELETE /service-accounts/:id/keys/:keyId).
+func (h *ServiceAccountHandler) RevokeKey(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "ServiceAccountHandler.RevokeKey - Error parsing ID", c)
+		return
+	}
+	keyID, customErr := GetIDParam(c, "keyId")
+	if customErr != nil {
+		HandleError(customErr, "ServiceAccountHandler.RevokeKey - Error parsing key ID", c)
+		return
+	}
+
+	if err := h.service.RevokeKey(id, keyID, GetPrincipalCompanyGlobalID(c)); err != nil {
+		HandleError(err, "ServiceAccountHandler.RevokeKey error", c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"go-sales/internal/config"
+	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
+	"go-sales/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// TaxHandler encapsula a dependência do serviço de impostos.
+type TaxHandler struct {
+	service service.TaxServiceInterface
+	cfg     *config.Config
+}
+
+// NewTaxHandler cria uma nova instância do handler de impostos.
+func NewTaxHandler(s service.TaxServiceInterface, cfg *config.Config) *TaxHandler {
+	return &TaxHandler{
+		service: s,
+		cfg:     cfg,
+	}
+}
+
+// Create cadastra uma regra de impostos (POST /tax-rules).
+func (h *TaxHandler) Create(c *gin.Context) {
+	log.Info().Msg("Creating a new tax rule")
+
+	createDTO, utilError := GetValidatedDTO[*dto.CreateTaxRuleDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "TaxHandler.Create - Error getting validated DTO", c)
+		return
+	}
+
+	rule, err := h.service.Create(*createDTO)
+	if err != nil {
+		HandleError(err, "TaxHandler.Create error", c)
+		return
+	}
+	SetETag(c, rule.Version)
+	c.JSON(http.StatusCreated, rule)
+}
+
+// Patch aplica um JSON Merge Patch na regra de impostos.
+func (h *TaxHandler) Patch(c *gin.Context) {
+	patchDTO, utilError := GetValidatedDTO[*dto.UpdateTaxRuleDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "TaxHandler.Patch - Error getting validated DTO", c)
+		return
+	}
+
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "TaxHandler.Patch - Error parsing ID", c)
+		return
+	}
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "TaxHandler.Patch - Error reading If-Match", c)
+		return
+	}
+
+	rule, err := h.service.Patch(*patchDTO, id, version)
+	if err != nil {
+		HandleError(err, "TaxHandler.Patch error", c)
+		return
+	}
+	SetETag(c, rule.Version)
+	c.JSON(http.StatusOK, rule)
+}
+
+func (h *TaxHandler) Delete(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "TaxHandler.Delete - Error parsing ID", c)
+		return
+	}
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "TaxHandler.Delete - Error reading If-Match", c)
+		return
+	}
+
+	if err := h.service.Delete(id, version); err != nil {
+		HandleError(err, "TaxHandler.Delete error", c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TaxHandler) FindByID(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "TaxHandler.FindByID - Error parsing ID", c)
+		return
+	}
+
+	opts := GetReadOptions(c, true)
+	rule, err := h.service.FindByID(id, opts)
+	if err != nil {
+		HandleError(err, "TaxHandler.FindByID error", c)
+		return
+	}
+	SetETag(c, rule.Version)
+	if NotModified(c, rule.Version) {
+		return
+	}
+	c.JSON(http.StatusOK, mapper.Project(rule, opts))
+}
+
+func (h *TaxHandler) FindAll(c *gin.Context) {
+	page := GetPageRequest(c, h.cfg.AppDefaultAPIPageSize)
+	opts := GetReadOptions(c, false)
+
+	companyGlobalID, err := strconv.ParseInt(c.Query("companyGlobalId"), 10, 64)
+	if err != nil {
+		customError := service.NewError("invalid companyGlobalId format", http.StatusBadRequest, "invalid_company_global_id_format")
+		HandleError(customError, "TaxHandler.FindAll - Error parsing companyGlobalId", c)
+		return
+	}
+	if companyGlobalID == 0 {
+		customError := service.NewError("companyGlobalId is required", http.StatusBadRequest, "company_global_id_required")
+		HandleError(customError, "TaxHandler.FindAll - companyGlobalId is required", c)
+		return
+	}
+
+	result, errFindAll := h.service.FindAll(c.Request.URL.Query(), page, opts, companyGlobalID)
+	if errFindAll != nil {
+		HandleError(errFindAll, "TaxHandler.FindAll error", c)
+		return
+	}
+	c.JSON(http.StatusOK, mapper.ProjectPage(result, opts))
+}
+
+// Preview calcula os impostos de um carrinho (POST /taxes/preview) sem gravar nada.
+func (h *TaxHandler) Preview(c *gin.Context) {
+	previewDTO, utilError := GetValidatedDTO[*dto.TaxPreviewDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "TaxHandler.Preview - Error getting validated DTO", c)
+		return
+	}
+
+	result, err := h.service.Preview(*previewDTO)
+	if err != nil {
+		HandleError(err, "TaxHandler.Preview error", c)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -1,9 +1,9 @@
 package handler
 
 import (
-	"fmt"
 	"go-sales/internal/config"
 	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
 	"go-sales/internal/service"
 	"net/http"
 	"strconv"
@@ -50,44 +50,68 @@ func (h *UserHandler) Create(c *gin.Context) {
 
 func (h *UserHandler) Update(c *gin.Context) {
 	idStr := c.Param("id")
-	validatedDTO, exists := c.Get("validatedDTO")
-	if !exists {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Validated DTO not found"})
+	updateUserDTO, utilError := GetValidatedDTO[*dto.CreateUserDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "UserHandler.Update - Error getting validated DTO", c)
 		return
 	}
 
-	// Faz a asserção de tipo
-	updateUserDTO, ok := validatedDTO.(*dto.CreateUserDTO)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid DTO type"})
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "UserHandler.Update - Error reading If-Match", c)
 		return
 	}
 
 	// 2. Chamar a Camada de Serviço
-	updatedUser, err := h.service.Update(*updateUserDTO, idStr)
+	updatedUser, err := h.service.Update(*updateUserDTO, idStr, version)
 	if err != nil {
-		c.JSON(err.HTTPStatusCode(), gin.H{"error": err.Error(), "code": err.Code()})
+		HandleError(err, "UserHandler.Update error", c)
 		return
 	}
 
 	// 4. Retornar Resposta de Sucesso
+	SetETag(c, updatedUser.Version)
 	c.JSON(http.StatusOK, updatedUser)
 }
 
+// Patch aplica um JSON Merge Patch (RFC 7396) no usuário.
+func (h *UserHandler) Patch(c *gin.Context) {
+	idStr := c.Param("id")
+	patchUserDTO, utilError := GetValidatedDTO[*dto.UpdateUserDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "UserHandler.Patch - Error getting validated DTO", c)
+		return
+	}
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "UserHandler.Patch - Error reading If-Match", c)
+		return
+	}
+
+	patchedUser, err := h.service.Patch(*patchUserDTO, idStr, version)
+	if err != nil {
+		HandleError(err, "UserHandler.Patch error", c)
+		return
+	}
+
+	SetETag(c, patchedUser.Version)
+	c.JSON(http.StatusOK, patchedUser)
+}
+
 func (h *UserHandler) Delete(c *gin.Context) {
 	idStr := c.Param("id")
 
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "UserHandler.Delete - Error reading If-Match", c)
+		return
+	}
+
 	// 2. Chamar a Camada de Serviço
-	if err := h.service.Delete(idStr); err != nil {
+	if err := h.service.Delete(idStr, version); err != nil {
 		// 3. Tratar Erros da Camada de Serviço
-		// Verifica se o erro é o nosso erro de "não encontrado".
-		if err != nil {
-			c.JSON(err.HTTPStatusCode(), gin.H{"error": err.Error(), "code": err.Code()})
-			return
-		}
-
-		// Para qualquer outro erro, consideramos um erro interno do servidor.
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "An internal error occurred"})
+		HandleError(err, "UserHandler.Delete error", c)
 		return
 	}
 
@@ -97,15 +121,8 @@ func (h *UserHandler) Delete(c *gin.Context) {
 
 func (h *UserHandler) FindAll(c *gin.Context) {
 	log.Debug().Msg("Finding all users")
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		page = 1
-	}
-
-	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", fmt.Sprintf("%d", h.cfg.AppDefaultAPIPageSize)))
-	if err != nil || pageSize < 1 {
-		pageSize = h.cfg.AppDefaultAPIPageSize
-	}
+	page := GetPageRequest(c, h.cfg.AppDefaultAPIPageSize)
+	opts := GetReadOptions(c, false)
 	filters := c.Request.URL.Query()
 
 	companyGlobalId, err := strconv.ParseInt(c.Query("companyGlobalId"), 10, 64)
@@ -120,7 +137,15 @@ func (h *UserHandler) FindAll(c *gin.Context) {
 		return
 	}
 
-	paginatedResult, customErr := h.service.FindAll(filters, page, pageSize, companyGlobalId)
+	// Accept: text/csv ou application/x-ndjson exporta todas as linhas dos filtros, sem paginação.
+	if format := GetExportFormat(c); format != "" {
+		StreamExport(c, format, "users", opts, "UserHandler.FindAll export error", func(fn func(*dto.UserDTO) error) service.ErrorUtil {
+			return h.service.Export(filters, opts, companyGlobalId, fn)
+		})
+		return
+	}
+
+	paginatedResult, customErr := h.service.FindAll(filters, page, opts, companyGlobalId)
 	if customErr != nil {
 
 		HandleError(customErr, "UserHandler.FindAll error", c)
@@ -129,26 +154,25 @@ func (h *UserHandler) FindAll(c *gin.Context) {
 	}
 
 	// 4. Retorna o resultado paginado.
-	c.JSON(http.StatusOK, paginatedResult)
+	c.JSON(http.StatusOK, mapper.ProjectPage(paginatedResult, opts))
 }
 
 func (h *UserHandler) FindByID(c *gin.Context) {
 	idStr := c.Param("id")
 
 	// 2. Chamar a Camada de Serviço
-	user, err := h.service.FindByID(idStr)
+	opts := GetReadOptions(c, true)
+	user, err := h.service.FindByID(idStr, opts)
 	if err != nil {
 		// 3. Tratar Erros da Camada de Serviço
-		if err != nil {
-			c.JSON(err.HTTPStatusCode(), gin.H{"error": err.Error(), "code": err.Code()})
-			return
-		}
-
-		// Para qualquer outro erro, consideramos um erro interno do servidor.
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "An internal error occurred"})
+		HandleError(err, "UserHandler.FindByID error", c)
 		return
 	}
 
 	// 4. Retornar Resposta de Sucesso
-	c.JSON(http.StatusOK, user)
+	SetETag(c, user.Version)
+	if NotModified(c, user.Version) {
+		return
+	}
+	c.JSON(http.StatusOK, mapper.Project(user, opts))
 }
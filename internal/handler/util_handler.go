@@ -1,20 +1,31 @@
 package handler
 
 import (
+	"fmt"
+	"hash/fnv"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 
+	"go-sales/internal/auth"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/problem"
 	"go-sales/internal/service"
+	"go-sales/pkg/util"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
 )
 
+// HandleError loga o erro e responde no formato application/problem+json (RFC 9457).
 func HandleError(err service.ErrorUtil, msg string, c *gin.Context) {
 	log.Error().
 		Err(err).
 		Caller().
 		Msg(msg)
-	c.JSON(err.HTTPStatusCode(), gin.H{"error": err.Error(), "code": err.Code()})
+	problem.Write(c, problem.FromError(err))
 }
 
 func GetValidatedDTO[T any](c *gin.Context, key string) (T, service.ErrorUtil) {
@@ -33,3 +44,158 @@ func GetValidatedDTO[T any](c *gin.Context, key string) (T, service.ErrorUtil) {
 
 	return dto, nil
 }
+
+// MaxPageSize é o maior pageSize (ou limit) aceito; valores maiores são reduzidos a ele.
+const MaxPageSize = 100
+
+// GetPageRequest lê os parâmetros de paginação da query string.
+// Sem ?cursor= nem ?limit= usa o modo offset (?page=&pageSize=); com qualquer um deles usa o
+// modo keyset, onde ?limit= substitui pageSize. ?count=false dispensa o cálculo do total.
+// pageSize e limit são limitados a MaxPageSize.
+func GetPageRequest(c *gin.Context, defaultPageSize int) dto.PageRequest {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", strconv.Itoa(defaultPageSize)))
+	if err != nil || pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+
+	cursor, hasCursor := c.GetQuery("cursor")
+	limitStr, hasLimit := c.GetQuery("limit")
+	if hasLimit {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			pageSize = limit
+		}
+	}
+	pageSize = min(pageSize, MaxPageSize)
+
+	withCount, err := strconv.ParseBool(c.DefaultQuery("count", "true"))
+	if err != nil {
+		withCount = true
+	}
+
+	return dto.PageRequest{
+		Page:      page,
+		PageSize:  pageSize,
+		Cursor:    cursor,
+		UseCursor: hasCursor || hasLimit,
+		WithCount: withCount,
+	}
+}
+
+// GetReadOptions lê ?fields= e ?expand= (listas separadas por vírgula).
+// expandByDefault carrega todas as associações diretas quando o cliente não envia ?expand=,
+// mantendo a resposta completa nas leituras de um único registro.
+func GetReadOptions(c *gin.Context, expandByDefault bool) dto.ReadOptions {
+	expand, hasExpand := c.GetQuery("expand")
+	return dto.ReadOptions{
+		Fields:    splitQueryList(c.Query("fields")),
+		Expand:    splitQueryList(expand),
+		ExpandAll: expandByDefault && !hasExpand,
+	}
+}
+
+func splitQueryList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// GetIDParam lê um parâmetro de rota com um Snowflake ID (ex: o :contactId das sub-rotas).
+func GetIDParam(c *gin.Context, name string) (int64, service.ErrorUtil) {
+	id, err := util.ParseSnowflake(c.Param(name))
+	if err != nil {
+		return 0, service.NewError(err.Error(), http.StatusBadRequest, "invalid_id_format", name, "Snowflake ID")
+	}
+	return id, nil
+}
+
+// staleVersion é usada quando o If-Match não traz um ETag forte válido: nenhuma versão real é
+// menor que 1, então a escrita sempre termina em 412 (ou 404, se o registro não existir).
+const staleVersion int64 = -1
+
+// GetPrincipalCompanyGlobalID devolve a empresa do Principal da requisição, ou zero se ela for anônima.
+// As rotas que dependem dela passam por middleware.RequirePrincipal ou middleware.RequirePermission.
+func GetPrincipalCompanyGlobalID(c *gin.Context) int64 {
+	principal, ok := auth.FromContext(c)
+	if !ok {
+		return 0
+	}
+	return principal.CompanyGlobalID
+}
+
+// FormatETag monta o ETag forte de um registro a partir da sua versão (ex: "3").
+func FormatETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// requestETag monta o ETag da representação pedida: a versão e, quando a requisição usa ?fields=
+// ou ?expand=, um hash da projeção (ex: "3.5f1d2c7a"), para que representações diferentes do mesmo
+// registro nunca compartilhem o ETag.
+func requestETag(c *gin.Context, version int64) string {
+	fields := splitQueryList(c.Query("fields"))
+	expand, hasExpand := c.GetQuery("expand")
+	if len(fields) == 0 && !hasExpand {
+		return FormatETag(version)
+	}
+	expandList := splitQueryList(expand)
+	slices.Sort(fields)
+	slices.Sort(expandList)
+
+	hash := fnv.New32a()
+	hash.Write([]byte("fields=" + strings.Join(fields, ",") + ";expand=" + strings.Join(expandList, ",")))
+	return fmt.Sprintf(`"%d.%08x"`, version, hash.Sum32())
+}
+
+// SetETag devolve no cabeçalho ETag a versão atual do registro e a projeção pedida.
+func SetETag(c *gin.Context, version int64) {
+	c.Header("ETag", requestETag(c, version))
+}
+
+// GetIfMatchVersion lê o If-Match exigido em PUT, PATCH e DELETE e devolve a versão esperada.
+// "*" aceita qualquer versão (database.AnyVersion). Apenas um ETag forte é aceito; ETags fracos,
+// listas ou valores malformados nunca correspondem à versão atual. O ETag de uma leitura com
+// ?fields= ou ?expand= também vale: só a versão, antes do ponto, é comparada.
+func GetIfMatchVersion(c *gin.Context) (int64, service.ErrorUtil) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		return 0, service.ErrPreconditionRequired
+	}
+	if header == "*" {
+		return database.AnyVersion, nil
+	}
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' {
+		return staleVersion, nil
+	}
+	tag, _, _ := strings.Cut(header[1:len(header)-1], ".")
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version < 1 {
+		return staleVersion, nil
+	}
+	return version, nil
+}
+
+// NotModified responde 304 quando o If-None-Match contém o ETag atual da representação pedida
+// (versão e projeção) ou "*". A comparação é fraca (RFC 9110): W/"3" corresponde a "3".
+func NotModified(c *gin.Context, version int64) bool {
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+	etag := requestETag(c, version)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
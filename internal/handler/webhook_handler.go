@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"go-sales/internal/config"
+	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
+	"go-sales/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// WebhookHandler encapsula a dependência do serviço de webhooks.
+type WebhookHandler struct {
+	service service.WebhookServiceInterface
+	cfg     *config.Config
+}
+
+// NewWebhookHandler cria uma nova instância do handler de webhooks.
+func NewWebhookHandler(s service.WebhookServiceInterface, cfg *config.Config) *WebhookHandler {
+	return &WebhookHandler{
+		service: s,
+		cfg:     cfg,
+	}
+}
+
+// Create cadastra um endpoint (POST /webhooks). O segredo de assinatura só aparece nesta resposta.
+func (h *WebhookHandler) Create(c *gin.Context) {
+	log.Info().Msg("Creating a new webhook")
+
+	createDTO, utilError := GetValidatedDTO[*dto.CreateWebhookDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "WebhookHandler.Create - Error getting validated DTO", c)
+		return
+	}
+
+	endpoint, err := h.service.Create(*createDTO)
+	if err != nil {
+		HandleError(err, "WebhookHandler.Create error", c)
+		return
+	}
+	SetETag(c, endpoint.Version)
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, endpoint)
+}
+
+// Patch aplica um JSON Merge Patch no endpoint.
+func (h *WebhookHandler) Patch(c *gin.Context) {
+	patchDTO, utilError := GetValidatedDTO[*dto.UpdateWebhookDTO](c, "validatedDTO")
+	if utilError != nil {
+		HandleError(utilError, "WebhookHandler.Patch - Error getting validated DTO", c)
+		return
+	}
+
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "WebhookHandler.Patch - Error parsing ID", c)
+		return
+	}
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "WebhookHandler.Patch - Error reading If-Match", c)
+		return
+	}
+
+	endpoint, err := h.service.Patch(*patchDTO, id, version)
+	if err != nil {
+		HandleError(err, "WebhookHandler.Patch error", c)
+		return
+	}
+	SetETag(c, endpoint.Version)
+	c.JSON(http.StatusOK, endpoint)
+}
+
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "WebhookHandler.Delete - Error parsing ID", c)
+		return
+	}
+
+	version, errVersion := GetIfMatchVersion(c)
+	if errVersion != nil {
+		HandleError(errVersion, "WebhookHandler.Delete - Error reading If-Match", c)
+		return
+	}
+
+	if err := h.service.Delete(id, version); err != nil {
+		HandleError(err, "WebhookHandler.Delete error", c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) FindByID(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "WebhookHandler.FindByID - Error parsing ID", c)
+		return
+	}
+
+	opts := GetReadOptions(c, true)
+	endpoint, err := h.service.FindByID(id, opts)
+	if err != nil {
+		HandleError(err, "WebhookHandler.FindByID error", c)
+		return
+	}
+	SetETag(c, endpoint.Version)
+	if NotModified(c, endpoint.Version) {
+		return
+	}
+	c.JSON(http.StatusOK, mapper.Project(endpoint, opts))
+}
+
+func (h *WebhookHandler) FindAll(c *gin.Context) {
+	page := GetPageRequest(c, h.cfg.AppDefaultAPIPageSize)
+	opts := GetReadOptions(c, false)
+
+	companyGlobalID, err := strconv.ParseInt(c.Query("companyGlobalId"), 10, 64)
+	if err != nil {
+		customError := service.NewError("invalid companyGlobalId format", http.StatusBadRequest, "invalid_company_global_id_format")
+		HandleError(customError, "WebhookHandler.FindAll - Error parsing companyGlobalId", c)
+		return
+	}
+	if companyGlobalID == 0 {
+		customError := service.NewError("companyGlobalId is required", http.StatusBadRequest, "company_global_id_required")
+		HandleError(customError, "WebhookHandler.FindAll - companyGlobalId is required", c)
+		return
+	}
+
+	result, errFindAll := h.service.FindAll(c.Request.URL.Query(), page, opts, companyGlobalID)
+	if errFindAll != nil {
+		HandleError(errFindAll, "WebhookHandler.FindAll error", c)
+		return
+	}
+	c.JSON(http.StatusOK, mapper.ProjectPage(result, opts))
+}
+
+// FindDeliveries lista as entregas do endpoint (GET /webhooks/:id/deliveries).
+func (h *WebhookHandler) FindDeliveries(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "WebhookHandler.FindDeliveries - Error parsing ID", c)
+		return
+	}
+
+	page := GetPageRequest(c, h.cfg.AppDefaultAPIPageSize)
+	result, err := h.service.FindDeliveries(id, c.Request.URL.Query(), page)
+	if err != nil {
+		HandleError(err, "WebhookHandler.FindDeliveries error", c)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// FindDelivery devolve a entrega com o payload e as tentativas (GET /webhooks/:id/deliveries/:deliveryId).
+func (h *WebhookHandler) FindDelivery(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "WebhookHandler.FindDelivery - Error parsing ID", c)
+		return
+	}
+	deliveryID, customErr := GetIDParam(c, "deliveryId")
+	if customErr != nil {
+		HandleError(customErr, "WebhookHandler.FindDelivery - Error parsing delivery ID", c)
+		return
+	}
+
+	delivery, err := h.service.FindDelivery(id, deliveryID)
+	if err != nil {
+		HandleError(err, "WebhookHandler.FindDelivery error", c)
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
+
+// Replay reenvia uma entrega (POST /webhooks/:id/deliveries/:deliveryId/replay). O envio é assíncrono:
+// a resposta 202 traz a nova entrega, que pode ser acompanhada pelo Location.
+func (h *WebhookHandler) Replay(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "WebhookHandler.Replay - Error parsing ID", c)
+		return
+	}
+	deliveryID, customErr := GetIDParam(c, "deliveryId")
+	if customErr != nil {
+		HandleError(customErr, "WebhookHandler.Replay - Error parsing delivery ID", c)
+		return
+	}
+
+	delivery, err := h.service.Replay(id, deliveryID)
+	if err != nil {
+		HandleError(err, "WebhookHandler.Replay error", c)
+		return
+	}
+	h.accepted(c, delivery)
+}
+
+// Ping envia um evento webhook.ping para testar o endpoint (POST /webhooks/:id/ping).
+func (h *WebhookHandler) Ping(c *gin.Context) {
+	id, customErr := GetIDParam(c, "id")
+	if customErr != nil {
+		HandleError(customErr, "WebhookHandler.Ping - Error parsing ID", c)
+		return
+	}
+
+	delivery, err := h.service.Ping(id)
+	if err != nil {
+		HandleError(err, "WebhookHandler.Ping error", c)
+		return
+	}
+	h.accepted(c, delivery)
+}
+
+// accepted responde 202 com a entrega enfileirada e o Location do seu detalhe.
+func (h *WebhookHandler) accepted(c *gin.Context, delivery *dto.WebhookDeliveryDTO) {
+	prefix, _, _ := strings.Cut(c.FullPath(), "/webhooks/")
+	c.Header("Location", prefix+"/webhooks/"+delivery.EndpointID.String()+"/deliveries/"+delivery.ID.String())
+	c.JSON(http.StatusAccepted, delivery)
+}
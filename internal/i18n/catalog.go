@@ -0,0 +1,10 @@
+package i18n
+
+// catalogs agrupa as mensagens de cada locale, indexadas pelo código estável do erro
+// (AbstractError.Code()). Ao criar um novo erro em service/static_errors.go,
+// adicione a mensagem correspondente nos três catálogos.
+var catalogs = map[string]map[string]string{
+	LocaleEnUS: messagesEnUS,
+	LocalePtBR: messagesPtBR,
+	LocaleEs:   messagesEs,
+}
@@ -0,0 +1,100 @@
+package i18n
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+// Locales suportados pela API. O valor é usado no header Content-Language.
+const (
+	LocaleEnUS = "en-US"
+	LocalePtBR = "pt-BR"
+	LocaleEs   = "es"
+
+	// DefaultLocale é usado quando o cliente não envia Accept-Language ou pede um idioma não suportado.
+	DefaultLocale = LocaleEnUS
+)
+
+// contextKey é a chave usada para guardar o locale negociado no gin.Context.
+const contextKey = "locale"
+
+// A ordem precisa bater com supportedLocales: o matcher devolve o índice da tag escolhida.
+var (
+	supportedTags    = []language.Tag{language.AmericanEnglish, language.BrazilianPortuguese, language.Spanish}
+	supportedLocales = []string{LocaleEnUS, LocalePtBR, LocaleEs}
+	matcher          = language.NewMatcher(supportedTags)
+)
+
+// Negotiate escolhe o locale suportado mais adequado para o header Accept-Language.
+func Negotiate(acceptLanguage string) string {
+	if acceptLanguage == "" {
+		return DefaultLocale
+	}
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return DefaultLocale
+	}
+	_, idx, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return DefaultLocale
+	}
+	return supportedLocales[idx]
+}
+
+// FromContext devolve o locale da requisição, negociando-o uma única vez a partir do Accept-Language.
+func FromContext(c *gin.Context) string {
+	if locale := c.GetString(contextKey); locale != "" {
+		return locale
+	}
+	locale := Negotiate(c.GetHeader("Accept-Language"))
+	c.Set(contextKey, locale)
+	return locale
+}
+
+// Message devolve a mensagem do catálogo para o código informado.
+// Os placeholders {0}, {1}... são substituídos pelos args. Se o código não existir no catálogo,
+// ou se a mensagem exigir argumentos que não foram informados, devolve o fallback.
+func Message(locale, code, fallback string, args ...string) string {
+	msg, ok := lookup(locale, code)
+	if !ok {
+		return fallback
+	}
+	if msg, ok = fill(msg, args); !ok {
+		return fallback
+	}
+	return msg
+}
+
+// Translate devolve a mensagem do código no catálogo do próprio locale, sem recorrer ao catálogo
+// padrão. ok é falso se o locale não tiver a mensagem ou se faltarem argumentos para ela.
+func Translate(locale, code string, args ...string) (string, bool) {
+	msg, ok := catalogs[locale][code]
+	if !ok {
+		return "", false
+	}
+	return fill(msg, args)
+}
+
+// placeholderPattern encontra os placeholders {0}, {1}... que sobraram numa mensagem.
+var placeholderPattern = regexp.MustCompile(`\{[0-9]+\}`)
+
+func fill(msg string, args []string) (string, bool) {
+	for i, arg := range args {
+		msg = strings.ReplaceAll(msg, "{"+strconv.Itoa(i)+"}", arg)
+	}
+	return msg, !placeholderPattern.MatchString(msg)
+}
+
+func lookup(locale, code string) (string, bool) {
+	if catalog, ok := catalogs[locale]; ok {
+		if msg, ok := catalog[code]; ok {
+			return msg, true
+		}
+	}
+	msg, ok := catalogs[DefaultLocale][code]
+	return msg, ok
+}
@@ -0,0 +1,125 @@
+package i18n
+
+var messagesEnUS = map[string]string{
+	// Erros de domínio (service/static_errors.go)
+	"email_in_use":             "email already in use",
+	"cgc_in_use":               "cgc already in use",
+	"entity_not_found":         "entity not found",
+	"duplicate_key":            "a record with this key already exists",
+	"foreign_key_constraint":   "cannot delete this record because it is referenced by other records",
+	"foreign_key_violated":     "foreign key violated",
+	"invalid_data":             "invalid data",
+	"company_global_not_found": "company global not found",
+	"role_not_found":           "role not found",
+	"permission_name_in_use":   "permission name already in use",
+	"role_name_in_use":         "role name already in use",
+	"permission_not_found":     "permission not found",
+	"internal_server_error":    "internal server error",
+	"database_error":           "database error",
+	"company_global_contacts_field_validation_unique": "the properties cgc, phone and email must be unique in contacts",
+	"role_must_have_permissions":                      "role must have at least one permission",
+	"permissions_not_found":                           "one or more permissions not found or do not belong to the specified company global",
+	"address_incomplete":                              "address is incomplete: street, city, state, postalCode and country are required",
+	"version_mismatch":                                "the resource was modified by another request; reload it and retry with the current ETag",
+	"precondition_required":                           "the If-Match header is required for this request",
+	"company_global_contact_not_found":                "contact not found in this company global",
+	"company_global_address_not_found":                "address not found in this company global",
+	"company_global_contact_required":                 "a company global must have at least one contact",
+	"company_global_main_address_required":            "a company global must keep its MAIN address; update it instead",
+	"company_global_main_address_exists":              "the company global already has a MAIN address",
+	"invalid_postal_code":                             "postal code must have 8 digits",
+	"postal_code_not_found":                           "postal code not found",
+	"invalid_state":                                   "state must be a valid UF (e.g. SP)",
+	"city_not_found":                                  "city not found in the IBGE municipality list for the given state",
+	"unsupported_import_entity":                       "this entity cannot be imported; supported entities are users and permissions",
+	"invalid_import_file":                             "the file must be a CSV or XLSX spreadsheet with a header row and up to 10000 data rows",
+	"import_job_not_found":                            "import job not found",
+	"job_not_found":                                   "job not found",
+	"invalid_credentials":                             "invalid email or password",
+	"account_locked":                                  "account temporarily locked after too many failed password attempts",
+	"service_account_not_found":                       "service account not found",
+	"service_account_name_in_use":                     "service account name already in use",
+	"api_key_not_found":                               "API key not found",
+	"api_key_revoked":                                 "API key is revoked",
+	"invalid_api_key_expiry":                          "expiresAt must be in the future",
+	"webhook_not_found":                               "webhook not found",
+	"webhook_delivery_not_found":                      "webhook delivery not found",
+	"webhook_disabled":                                "webhook is disabled",
+	"invalid_webhook_url":                             "url must be an absolute http or https URL",
+	"webhook_url_not_allowed":                         "url must not point to localhost, a private network or a reserved address",
+	"unsupported_webhook_event":                       "unsupported webhook event type: {0}",
+	"tax_rule_not_found":                              "tax rule not found",
+	"invalid_tax_rule":                                "invalid tax rule: {0}",
+	"tax_origin_uf_required":                          "originUf is required because the company global has no MAIN address",
+	"tax_calculation_failed":                          "cannot calculate the taxes of item {0}: {1}",
+	"invalid_pix_key":                                 "invalid PIX key",
+	"installment_not_found":                           "installment not found",
+	"invalid_installment_amount":                      "amount must be greater than zero",
+	"installment_not_open":                            "installment is not open",
+	"pix_charge_not_found":                            "PIX charge not found",
+	"pix_key_not_configured":                          "the company has no PIX key",
+	"pix_merchant_city_required":                      "the company needs a MAIN address to receive PIX payments",
+	"pix_payload_failed":                              "cannot build the PIX payload: {0}",
+	"pix_provider_failed":                             "the PIX provider failed to create the charge",
+	"invalid_pix_notification":                        "invalid PIX notification",
+	"bank_account_not_found":                          "bank account not found",
+	"bank_account_company_mismatch":                   "the bank account belongs to another company global",
+	"unsupported_boleto_bank":                         "boletos are not supported for this bank",
+	"invalid_boleto_account":                          "the bank account cannot issue boletos: {0}",
+	"boleto_not_found":                                "boleto not found",
+	"boleto_already_issued":                           "the installment already has an active boleto",
+	"boleto_generation_failed":                        "cannot generate the boleto: {0}",
+	"no_pending_boletos":                              "the bank account has no pending boletos",
+	"boleto_remittance_not_found":                     "remittance file not found",
+	"invalid_return_file":                             "the file is not a CNAB 240 or 400 return file",
+	"return_bank_mismatch":                            "the return file is from another bank",
+	"boleto_return_not_found":                         "return file not found",
+	"invalid_statement_file":                          "the file is not an OFX or CSV bank statement with transactions",
+	"statement_line_not_found":                        "statement line not found",
+	"statement_line_not_suggested":                    "the statement line has no suggested installment",
+	"statement_line_not_linkable":                     "only unreconciled credit lines can be linked to an installment",
+	"installment_already_matched":                     "the installment is already matched to another statement line",
+	"installment_cancelled":                           "the installment is cancelled",
+	"user_not_found":                                  "user not found",
+	"commission_plan_not_found":                       "commission plan not found",
+	"invalid_commission_plan":                         "invalid commission plan: {0}",
+	"commission_plan_name_in_use":                     "commission plan name already in use",
+	"commission_assignment_not_found":                 "commission plan assignment not found",
+	"commission_assignment_target":                    "exactly one of userId or roleId is required",
+	"commission_assignment_validity":                  "validTo cannot be before validFrom",
+	"commission_assignment_overlap":                   "the user or role already has a commission plan in this period",
+	"commission_company_mismatch":                     "the user or role belongs to another company global",
+	"commission_plan_not_assigned":                    "no commission plan is assigned to the user on the document date",
+	"commission_entry_not_found":                      "commission entry not found",
+	"commission_document_exists":                      "the document already has a commission entry",
+	"commission_period_closed":                        "the commission statement of this period is already approved",
+	"commission_approver_required":                    "the commission statement approver is required",
+	"commission_period_not_ended":                     "the commission period has not ended yet",
+	"invalid_commission_period":                       "period must be a month in the format YYYY-MM",
+	"commission_entry_not_reversible":                 "only sale commission entries can be reversed",
+	"commission_already_reversed":                     "the sale commission is already fully reversed",
+	"commission_reversal_exceeds_sale":                "baseAmount must be positive and cannot exceed the sale base not yet reversed",
+	"commission_reversal_before_sale":                 "the reversal date cannot be before the sale date",
+	"commission_calculation_failed":                   "cannot calculate the commission of item {0}: {1}",
+
+	// Erros de requisição (handlers e middlewares)
+	"validation_error":                 "the request payload is invalid",
+	"invalid_filter":                   "invalid filter, sort, fields or expand parameter {0}: {1}",
+	"invalid_type":                     "expected {0} but got {1}",
+	"invalid_id_format":                "Invalid {0} format. Must be a valid {1}.",
+	"invalid_cgc_format":               "Invalid {0} format. Must be a valid CGC.",
+	"invalid_company_global_id_format": "invalid companyGlobalId format",
+	"company_global_id_required":       "companyGlobalId is required",
+	"validated_dto_not_found":          "Validated DTO not found",
+	"type_assertion_failed":            "Type assertion failed for validatedDTO",
+	"invalid_idempotency_key":          "Idempotency-Key must have at most 255 characters",
+	"idempotency_key_reused":           "Idempotency-Key was already used with a different request",
+	"payload_too_large":                "the request body exceeds {0} bytes",
+	"idempotency_key_in_progress":      "a request with this Idempotency-Key is still being processed",
+	"rate_limited":                     "too many requests, retry in {0} seconds",
+	"invalid_token":                    "missing, invalid or expired bearer token",
+	"permission_denied":                "the {0} permission is required",
+	"tenant_mismatch":                  "{0} does not match the tenant of the credentials",
+	"route_not_found":                  "no route matches {0} {1}",
+	"method_not_allowed":               "method {0} is not allowed on {1}",
+}
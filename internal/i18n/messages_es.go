@@ -0,0 +1,125 @@
+package i18n
+
+var messagesEs = map[string]string{
+	// Erros de domínio (service/static_errors.go)
+	"email_in_use":             "el correo electrónico ya está en uso",
+	"cgc_in_use":               "el CPF/CNPJ ya está en uso",
+	"entity_not_found":         "registro no encontrado",
+	"duplicate_key":            "ya existe un registro con esta clave",
+	"foreign_key_constraint":   "no se puede eliminar este registro porque otros registros lo referencian",
+	"foreign_key_violated":     "clave foránea violada",
+	"invalid_data":             "datos inválidos",
+	"company_global_not_found": "empresa no encontrada",
+	"role_not_found":           "rol no encontrado",
+	"permission_name_in_use":   "el nombre del permiso ya está en uso",
+	"role_name_in_use":         "el nombre del rol ya está en uso",
+	"permission_not_found":     "permiso no encontrado",
+	"internal_server_error":    "error interno del servidor",
+	"database_error":           "error de base de datos",
+	"company_global_contacts_field_validation_unique": "las propiedades cgc, teléfono y correo electrónico deben ser únicas entre los contactos",
+	"role_must_have_permissions":                      "el rol debe tener al menos un permiso",
+	"permissions_not_found":                           "uno o más permisos no existen o no pertenecen a la empresa indicada",
+	"address_incomplete":                              "dirección incompleta: street, city, state, postalCode y country son obligatorios",
+	"version_mismatch":                                "el recurso fue modificado por otra solicitud; vuelva a cargarlo e intente de nuevo con el ETag actual",
+	"precondition_required":                           "el encabezado If-Match es obligatorio en esta solicitud",
+	"company_global_contact_not_found":                "contacto no encontrado en esta empresa",
+	"company_global_address_not_found":                "dirección no encontrada en esta empresa",
+	"company_global_contact_required":                 "la empresa debe tener al menos un contacto",
+	"company_global_main_address_required":            "la empresa debe mantener su dirección principal (MAIN); modifíquela en lugar de eliminarla",
+	"company_global_main_address_exists":              "la empresa ya tiene una dirección principal (MAIN)",
+	"invalid_postal_code":                             "el código postal debe tener 8 dígitos",
+	"postal_code_not_found":                           "código postal no encontrado",
+	"invalid_state":                                   "el estado debe ser una UF válida (ej: SP)",
+	"city_not_found":                                  "ciudad no encontrada en la lista de municipios del IBGE para el estado informado",
+	"unsupported_import_entity":                       "esta entidad no se puede importar; las entidades aceptadas son users y permissions",
+	"invalid_import_file":                             "el archivo debe ser una hoja de cálculo CSV o XLSX con encabezado y hasta 10000 filas de datos",
+	"import_job_not_found":                            "importación no encontrada",
+	"job_not_found":                                   "job no encontrado",
+	"invalid_credentials":                             "correo electrónico o contraseña inválidos",
+	"account_locked":                                  "cuenta bloqueada temporalmente tras demasiados intentos de contraseña fallidos",
+	"service_account_not_found":                       "cuenta de servicio no encontrada",
+	"service_account_name_in_use":                     "el nombre de la cuenta de servicio ya está en uso",
+	"api_key_not_found":                               "clave de API no encontrada",
+	"api_key_revoked":                                 "la clave de API está revocada",
+	"invalid_api_key_expiry":                          "expiresAt debe estar en el futuro",
+	"webhook_not_found":                               "webhook no encontrado",
+	"webhook_delivery_not_found":                      "entrega de webhook no encontrada",
+	"webhook_disabled":                                "el webhook está deshabilitado",
+	"invalid_webhook_url":                             "url debe ser una URL http o https absoluta",
+	"webhook_url_not_allowed":                         "url no puede apuntar a localhost, a una red privada ni a una dirección reservada",
+	"unsupported_webhook_event":                       "tipo de evento de webhook no soportado: {0}",
+	"tax_rule_not_found":                              "regla de impuestos no encontrada",
+	"invalid_tax_rule":                                "regla de impuestos inválida: {0}",
+	"tax_origin_uf_required":                          "originUf es obligatorio porque la empresa no tiene dirección MAIN",
+	"tax_calculation_failed":                          "no se pueden calcular los impuestos del ítem {0}: {1}",
+	"invalid_pix_key":                                 "clave PIX inválida",
+	"installment_not_found":                           "cuota no encontrada",
+	"invalid_installment_amount":                      "el monto debe ser mayor que cero",
+	"installment_not_open":                            "la cuota no está abierta",
+	"pix_charge_not_found":                            "cobro PIX no encontrado",
+	"pix_key_not_configured":                          "la empresa no tiene clave PIX",
+	"pix_merchant_city_required":                      "la empresa necesita una dirección principal para recibir PIX",
+	"pix_payload_failed":                              "no se puede generar el payload PIX: {0}",
+	"pix_provider_failed":                             "el proveedor PIX no pudo crear el cobro",
+	"invalid_pix_notification":                        "notificación PIX inválida",
+	"bank_account_not_found":                          "cuenta bancaria no encontrada",
+	"bank_account_company_mismatch":                   "la cuenta bancaria pertenece a otra empresa",
+	"unsupported_boleto_bank":                         "el banco no tiene boletos implementados",
+	"invalid_boleto_account":                          "la cuenta bancaria no puede emitir boletos: {0}",
+	"boleto_not_found":                                "boleto no encontrado",
+	"boleto_already_issued":                           "la cuota ya tiene un boleto activo",
+	"boleto_generation_failed":                        "no se puede generar el boleto: {0}",
+	"no_pending_boletos":                              "la cuenta bancaria no tiene boletos pendientes",
+	"boleto_remittance_not_found":                     "archivo de remesa no encontrado",
+	"invalid_return_file":                             "el archivo no es un retorno CNAB 240 o 400",
+	"return_bank_mismatch":                            "el archivo de retorno es de otro banco",
+	"boleto_return_not_found":                         "archivo de retorno no encontrado",
+	"invalid_statement_file":                          "el archivo no es un extracto OFX o CSV con movimientos",
+	"statement_line_not_found":                        "movimiento del extracto no encontrado",
+	"statement_line_not_suggested":                    "el movimiento no tiene una cuota sugerida",
+	"statement_line_not_linkable":                     "solo los movimientos de crédito aún no conciliados pueden vincularse a una cuota",
+	"installment_already_matched":                     "la cuota ya está vinculada a otro movimiento del extracto",
+	"installment_cancelled":                           "la cuota está cancelada",
+	"user_not_found":                                  "usuario no encontrado",
+	"commission_plan_not_found":                       "plan de comisión no encontrado",
+	"invalid_commission_plan":                         "plan de comisión inválido: {0}",
+	"commission_plan_name_in_use":                     "el nombre del plan de comisión ya está en uso",
+	"commission_assignment_not_found":                 "asignación del plan de comisión no encontrada",
+	"commission_assignment_target":                    "informe exactamente uno entre userId y roleId",
+	"commission_assignment_validity":                  "validTo no puede ser anterior a validFrom",
+	"commission_assignment_overlap":                   "el usuario o el perfil ya tiene un plan de comisión en este período",
+	"commission_company_mismatch":                     "el usuario o el perfil pertenece a otra empresa",
+	"commission_plan_not_assigned":                    "el usuario no tiene un plan de comisión vigente en la fecha del documento",
+	"commission_entry_not_found":                      "asiento de comisión no encontrado",
+	"commission_document_exists":                      "el documento ya tiene un asiento de comisión",
+	"commission_period_closed":                        "el extracto de comisiones de este mes ya fue aprobado",
+	"commission_approver_required":                    "se requiere saber quién aprueba el extracto de comisiones",
+	"commission_period_not_ended":                     "el mes del extracto de comisiones aún no ha terminado",
+	"invalid_commission_period":                       "el período debe ser un mes en el formato AAAA-MM",
+	"commission_entry_not_reversible":                 "solo los asientos de venta pueden ser revertidos",
+	"commission_already_reversed":                     "la comisión de la venta ya fue revertida por completo",
+	"commission_reversal_exceeds_sale":                "baseAmount debe ser positivo y no puede superar la base de la venta aún no revertida",
+	"commission_reversal_before_sale":                 "la fecha de la reversión no puede ser anterior a la de la venta",
+	"commission_calculation_failed":                   "no se puede calcular la comisión del ítem {0}: {1}",
+
+	// Erros de requisição (handlers e middlewares)
+	"validation_error":                 "el payload de la solicitud no es válido",
+	"invalid_filter":                   "parámetro de filtro, ordenación, fields o expand inválido {0}: {1}",
+	"invalid_type":                     "se esperaba {0} pero se recibió {1}",
+	"invalid_id_format":                "Formato de {0} inválido. Debe ser un {1} válido.",
+	"invalid_cgc_format":               "Formato de {0} inválido. Debe ser un CPF/CNPJ válido.",
+	"invalid_company_global_id_format": "formato de companyGlobalId inválido",
+	"company_global_id_required":       "companyGlobalId es obligatorio",
+	"validated_dto_not_found":          "DTO validado no encontrado",
+	"type_assertion_failed":            "Falló la conversión de tipo del DTO validado",
+	"invalid_idempotency_key":          "Idempotency-Key debe tener como máximo 255 caracteres",
+	"idempotency_key_reused":           "Idempotency-Key ya fue usada con una solicitud diferente",
+	"payload_too_large":                "el cuerpo de la solicitud supera {0} bytes",
+	"idempotency_key_in_progress":      "una solicitud con esta Idempotency-Key todavía se está procesando",
+	"rate_limited":                     "demasiadas solicitudes, inténtelo de nuevo en {0} segundos",
+	"invalid_token":                    "token bearer ausente, inválido o expirado",
+	"permission_denied":                "se requiere el permiso {0}",
+	"tenant_mismatch":                  "{0} no corresponde al tenant de las credenciales",
+	"route_not_found":                  "ninguna ruta coincide con {0} {1}",
+	"method_not_allowed":               "el método {0} no está permitido en {1}",
+}
@@ -0,0 +1,125 @@
+package i18n
+
+var messagesPtBR = map[string]string{
+	// Erros de domínio (service/static_errors.go)
+	"email_in_use":             "e-mail já está em uso",
+	"cgc_in_use":               "CPF/CNPJ já está em uso",
+	"entity_not_found":         "registro não encontrado",
+	"duplicate_key":            "já existe um registro com esta chave",
+	"foreign_key_constraint":   "não é possível excluir este registro porque ele é referenciado por outros registros",
+	"foreign_key_violated":     "chave estrangeira violada",
+	"invalid_data":             "dados inválidos",
+	"company_global_not_found": "empresa não encontrada",
+	"role_not_found":           "perfil não encontrado",
+	"permission_name_in_use":   "nome de permissão já está em uso",
+	"role_name_in_use":         "nome de perfil já está em uso",
+	"permission_not_found":     "permissão não encontrada",
+	"internal_server_error":    "erro interno do servidor",
+	"database_error":           "erro de banco de dados",
+	"company_global_contacts_field_validation_unique": "as propriedades cgc, telefone e e-mail devem ser únicas entre os contatos",
+	"role_must_have_permissions":                      "o perfil deve ter pelo menos uma permissão",
+	"permissions_not_found":                           "uma ou mais permissões não foram encontradas ou não pertencem à empresa informada",
+	"address_incomplete":                              "endereço incompleto: street, city, state, postalCode e country são obrigatórios",
+	"version_mismatch":                                "o recurso foi alterado por outra requisição; recarregue-o e tente novamente com o ETag atual",
+	"precondition_required":                           "o cabeçalho If-Match é obrigatório nesta requisição",
+	"company_global_contact_not_found":                "contato não encontrado nesta empresa",
+	"company_global_address_not_found":                "endereço não encontrado nesta empresa",
+	"company_global_contact_required":                 "a empresa deve ter pelo menos um contato",
+	"company_global_main_address_required":            "a empresa deve manter o seu endereço principal (MAIN); altere-o em vez de removê-lo",
+	"company_global_main_address_exists":              "a empresa já possui um endereço principal (MAIN)",
+	"invalid_postal_code":                             "o CEP deve ter 8 dígitos",
+	"postal_code_not_found":                           "CEP não encontrado",
+	"invalid_state":                                   "o estado deve ser uma UF válida (ex: SP)",
+	"city_not_found":                                  "cidade não encontrada na lista de municípios do IBGE para a UF informada",
+	"unsupported_import_entity":                       "esta entidade não pode ser importada; as entidades aceitas são users e permissions",
+	"invalid_import_file":                             "o arquivo deve ser uma planilha CSV ou XLSX com cabeçalho e até 10000 linhas de dados",
+	"import_job_not_found":                            "importação não encontrada",
+	"job_not_found":                                   "job não encontrado",
+	"invalid_credentials":                             "email ou senha inválidos",
+	"account_locked":                                  "conta bloqueada temporariamente após muitas tentativas de senha erradas",
+	"service_account_not_found":                       "conta de serviço não encontrada",
+	"service_account_name_in_use":                     "nome da conta de serviço já está em uso",
+	"api_key_not_found":                               "chave de API não encontrada",
+	"api_key_revoked":                                 "a chave de API está revogada",
+	"invalid_api_key_expiry":                          "expiresAt deve estar no futuro",
+	"webhook_not_found":                               "webhook não encontrado",
+	"webhook_delivery_not_found":                      "entrega de webhook não encontrada",
+	"webhook_disabled":                                "webhook está desabilitado",
+	"invalid_webhook_url":                             "url deve ser uma URL http ou https absoluta",
+	"webhook_url_not_allowed":                         "url não pode apontar para localhost, rede privada ou endereço reservado",
+	"unsupported_webhook_event":                       "tipo de evento de webhook não suportado: {0}",
+	"tax_rule_not_found":                              "regra de impostos não encontrada",
+	"invalid_tax_rule":                                "regra de impostos inválida: {0}",
+	"tax_origin_uf_required":                          "originUf é obrigatório porque a empresa não tem endereço MAIN",
+	"tax_calculation_failed":                          "não foi possível calcular os impostos do item {0}: {1}",
+	"invalid_pix_key":                                 "chave PIX inválida",
+	"installment_not_found":                           "parcela não encontrada",
+	"invalid_installment_amount":                      "o valor deve ser maior que zero",
+	"installment_not_open":                            "a parcela não está em aberto",
+	"pix_charge_not_found":                            "cobrança PIX não encontrada",
+	"pix_key_not_configured":                          "a empresa não tem chave PIX",
+	"pix_merchant_city_required":                      "a empresa precisa de um endereço principal para receber PIX",
+	"pix_payload_failed":                              "não foi possível montar o payload PIX: {0}",
+	"pix_provider_failed":                             "o provedor PIX não conseguiu criar a cobrança",
+	"invalid_pix_notification":                        "notificação PIX inválida",
+	"bank_account_not_found":                          "conta bancária não encontrada",
+	"bank_account_company_mismatch":                   "a conta bancária é de outra empresa",
+	"unsupported_boleto_bank":                         "o banco não tem boletos implementados",
+	"invalid_boleto_account":                          "a conta bancária não pode emitir boletos: {0}",
+	"boleto_not_found":                                "boleto não encontrado",
+	"boleto_already_issued":                           "a parcela já tem um boleto ativo",
+	"boleto_generation_failed":                        "não foi possível gerar o boleto: {0}",
+	"no_pending_boletos":                              "a conta bancária não tem boletos pendentes",
+	"boleto_remittance_not_found":                     "arquivo de remessa não encontrado",
+	"invalid_return_file":                             "o arquivo não é um retorno CNAB 240 ou 400",
+	"return_bank_mismatch":                            "o arquivo de retorno é de outro banco",
+	"boleto_return_not_found":                         "arquivo de retorno não encontrado",
+	"invalid_statement_file":                          "o arquivo não é um extrato OFX ou CSV com lançamentos",
+	"statement_line_not_found":                        "lançamento do extrato não encontrado",
+	"statement_line_not_suggested":                    "o lançamento não tem parcela sugerida",
+	"statement_line_not_linkable":                     "só lançamentos de crédito ainda não conciliados podem ser vinculados a uma parcela",
+	"installment_already_matched":                     "a parcela já está vinculada a outro lançamento do extrato",
+	"installment_cancelled":                           "a parcela está cancelada",
+	"user_not_found":                                  "usuário não encontrado",
+	"commission_plan_not_found":                       "plano de comissão não encontrado",
+	"invalid_commission_plan":                         "plano de comissão inválido: {0}",
+	"commission_plan_name_in_use":                     "nome de plano de comissão já está em uso",
+	"commission_assignment_not_found":                 "atribuição do plano de comissão não encontrada",
+	"commission_assignment_target":                    "informe exatamente um entre userId e roleId",
+	"commission_assignment_validity":                  "validTo não pode ser anterior a validFrom",
+	"commission_assignment_overlap":                   "o usuário ou o perfil já tem um plano de comissão neste período",
+	"commission_company_mismatch":                     "o usuário ou o perfil é de outra empresa",
+	"commission_plan_not_assigned":                    "o usuário não tem plano de comissão vigente na data do documento",
+	"commission_entry_not_found":                      "lançamento de comissão não encontrado",
+	"commission_document_exists":                      "o documento já tem um lançamento de comissão",
+	"commission_period_closed":                        "o extrato de comissões deste mês já foi aprovado",
+	"commission_approver_required":                    "é preciso saber quem aprova o extrato de comissões",
+	"commission_period_not_ended":                     "o mês do extrato de comissões ainda não terminou",
+	"invalid_commission_period":                       "o período deve ser um mês no formato AAAA-MM",
+	"commission_entry_not_reversible":                 "só lançamentos de venda podem ser estornados",
+	"commission_already_reversed":                     "a comissão da venda já foi estornada por inteiro",
+	"commission_reversal_exceeds_sale":                "baseAmount deve ser positivo e não pode passar da base da venda ainda não estornada",
+	"commission_reversal_before_sale":                 "a data do estorno não pode ser anterior à da venda",
+	"commission_calculation_failed":                   "não foi possível calcular a comissão do item {0}: {1}",
+
+	// Erros de requisição (handlers e middlewares)
+	"validation_error":                 "o payload da requisição é inválido",
+	"invalid_filter":                   "parâmetro de filtro, ordenação, fields ou expand inválido {0}: {1}",
+	"invalid_type":                     "esperado {0}, mas recebido {1}",
+	"invalid_id_format":                "Formato de {0} inválido. Informe um {1} válido.",
+	"invalid_cgc_format":               "Formato de {0} inválido. Informe um CPF/CNPJ válido.",
+	"invalid_company_global_id_format": "formato de companyGlobalId inválido",
+	"company_global_id_required":       "companyGlobalId é obrigatório",
+	"validated_dto_not_found":          "DTO validado não encontrado",
+	"type_assertion_failed":            "Falha na conversão de tipo do DTO validado",
+	"invalid_idempotency_key":          "Idempotency-Key deve ter no máximo 255 caracteres",
+	"idempotency_key_reused":           "Idempotency-Key já foi usada com uma requisição diferente",
+	"payload_too_large":                "o corpo da requisição passa de {0} bytes",
+	"idempotency_key_in_progress":      "uma requisição com esta Idempotency-Key ainda está sendo processada",
+	"rate_limited":                     "muitas requisições, tente novamente em {0} segundos",
+	"invalid_token":                    "token bearer ausente, inválido ou expirado",
+	"permission_denied":                "a permissão {0} é necessária",
+	"tenant_mismatch":                  "{0} não corresponde ao tenant das credenciais",
+	"route_not_found":                  "nenhuma rota corresponde a {0} {1}",
+	"method_not_allowed":               "o método {0} não é permitido em {1}",
+}
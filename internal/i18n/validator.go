@@ -0,0 +1,92 @@
+package i18n
+
+import (
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	"github.com/go-playground/locales/pt_BR"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	es_translations "github.com/go-playground/validator/v10/translations/es"
+	pt_BR_translations "github.com/go-playground/validator/v10/translations/pt_BR"
+)
+
+var uni *ut.UniversalTranslator
+
+// translatorNames mapeia nossos locales para os nomes usados pelo go-playground/locales.
+var translatorNames = map[string]string{
+	LocaleEnUS: "en",
+	LocalePtBR: "pt_BR",
+	LocaleEs:   "es",
+}
+
+// customTagMessages traz as mensagens das tags de validação registradas pelo projeto
+// (ver internal/validator). Toda tag nova precisa de uma entrada aqui.
+var customTagMessages = map[string]map[string]string{
+	"snowflake": {
+		LocaleEnUS: "{0} must be a valid Snowflake ID",
+		LocalePtBR: "{0} deve ser um ID Snowflake válido",
+		LocaleEs:   "{0} debe ser un ID Snowflake válido",
+	},
+	"cgc": {
+		LocaleEnUS: "{0} must be a valid CPF/CNPJ",
+		LocalePtBR: "{0} deve ser um CPF/CNPJ válido",
+		LocaleEs:   "{0} debe ser un CPF/CNPJ válido",
+	},
+	"notnull": {
+		LocaleEnUS: "{0} cannot be null",
+		LocalePtBR: "{0} não pode ser nulo",
+		LocaleEs:   "{0} no puede ser nulo",
+	},
+}
+
+// RegisterValidatorTranslations registra no validador as traduções padrão do go-playground
+// para cada locale suportado, além das mensagens das tags customizadas.
+func RegisterValidatorTranslations(v *validator.Validate) error {
+	enLocale := en.New()
+	uni = ut.New(enLocale, enLocale, pt_BR.New(), es.New())
+
+	defaults := map[string]func(*validator.Validate, ut.Translator) error{
+		LocaleEnUS: en_translations.RegisterDefaultTranslations,
+		LocalePtBR: pt_BR_translations.RegisterDefaultTranslations,
+		LocaleEs:   es_translations.RegisterDefaultTranslations,
+	}
+
+	for locale, name := range translatorNames {
+		trans, _ := uni.GetTranslator(name)
+		if err := defaults[locale](v, trans); err != nil {
+			return err
+		}
+		for tag, messages := range customTagMessages {
+			if err := registerTag(v, trans, tag, messages[locale]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func registerTag(v *validator.Validate, trans ut.Translator, tag, message string) error {
+	return v.RegisterTranslation(tag, trans,
+		func(t ut.Translator) error {
+			return t.Add(tag, message, true)
+		},
+		func(t ut.Translator, fe validator.FieldError) string {
+			msg, err := t.T(tag, fe.Field())
+			if err != nil {
+				return fe.Error()
+			}
+			return msg
+		},
+	)
+}
+
+// Translator devolve o tradutor do validador para o locale. Retorna nil se as traduções
+// ainda não foram registradas; nesse caso FieldError.Translate cai na mensagem padrão.
+func Translator(locale string) ut.Translator {
+	if uni == nil {
+		return nil
+	}
+	trans, _ := uni.GetTranslator(translatorNames[locale])
+	return trans
+}
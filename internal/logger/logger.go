@@ -2,6 +2,7 @@ package logger
 
 import (
 	"go-sales/internal/config"
+	"go-sales/internal/problem"
 	"net/http"
 	"os"
 	"time"
@@ -61,6 +62,7 @@ func GinLogger() gin.HandlerFunc {
 		}
 
 		event.
+			Str("request_id", c.Writer.Header().Get(problem.RequestIDHeader)).
 			Str("method", method).
 			Str("path", path).
 			Int("status_code", statusCode).
@@ -83,9 +85,7 @@ func GinRecovery(stack bool) gin.HandlerFunc {
 					Msg("Panic recovered")
 
 				// Retorna uma resposta de erro genérica para o cliente
-				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-					"error": "An internal server error occurred",
-				})
+				problem.Write(c, problem.New(http.StatusInternalServerError, "internal_server_error", "An internal server error occurred"))
 			}
 		}()
 		c.Next()
@@ -0,0 +1,24 @@
+package mapper
+
+import (
+	"go-sales/internal/dto"
+	"go-sales/internal/model"
+)
+
+func MapToAddressLookupDTO(postalCode *model.PostalCode) *dto.AddressLookupDTO {
+	if postalCode == nil {
+		return nil
+	}
+	lookup := &dto.AddressLookupDTO{
+		PostalCode:   postalCode.PostalCode,
+		Street:       postalCode.Street,
+		District:     postalCode.District,
+		CityIBGECode: postalCode.CityIBGECode,
+		Country:      "BR",
+	}
+	if postalCode.Municipality != nil {
+		lookup.City = postalCode.Municipality.Name
+		lookup.State = postalCode.Municipality.UF
+	}
+	return lookup
+}
@@ -0,0 +1,65 @@
+package mapper
+
+import (
+	"go-sales/internal/dto"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+)
+
+func MapToBankAccountDTO(account *model.BankAccount) *dto.BankAccountDTO {
+	if account == nil {
+		return nil
+	}
+	return &dto.BankAccountDTO{
+		ID:                 util.SnowflakeID(account.ID),
+		CompanyGlobalID:    util.SnowflakeID(account.CompanyGlobalID),
+		BankCode:           account.BankCode,
+		Agency:             account.Agency,
+		AgencyDigit:        account.AgencyDigit,
+		AccountNumber:      account.AccountNumber,
+		AccountDigit:       account.AccountDigit,
+		Description:        account.Description,
+		Wallet:             account.Wallet,
+		WalletVariation:    account.WalletVariation,
+		Agreement:          account.Agreement,
+		NextOurNumber:      account.NextOurNumber,
+		RemittanceSequence: account.RemittanceSequence,
+		Version:            account.Version,
+		CreatedAt:          account.CreatedAt,
+		UpdatedAt:          account.UpdatedAt,
+	}
+}
+
+func MapCreateToBankAccount(accountDTO *dto.CreateBankAccountDTO) *model.BankAccount {
+	nextOurNumber := int64(1)
+	if accountDTO.NextOurNumber != nil {
+		nextOurNumber = *accountDTO.NextOurNumber
+	}
+	return &model.BankAccount{
+		CompanyGlobalID: accountDTO.CompanyGlobalID.Int64(),
+		BankCode:        accountDTO.BankCode,
+		Agency:          accountDTO.Agency,
+		AgencyDigit:     accountDTO.AgencyDigit,
+		AccountNumber:   accountDTO.AccountNumber,
+		AccountDigit:    accountDTO.AccountDigit,
+		Description:     accountDTO.Description,
+		Wallet:          accountDTO.Wallet,
+		WalletVariation: accountDTO.WalletVariation,
+		Agreement:       accountDTO.Agreement,
+		NextOurNumber:   nextOurNumber,
+	}
+}
+
+// MapUpdateBankAccountToColumns converte um PATCH nas colunas de bank_accounts a atualizar.
+func MapUpdateBankAccountToColumns(accountDTO *dto.UpdateBankAccountDTO) map[string]any {
+	columns := make(map[string]any)
+	patch := accountDTO.MergePatch
+	patchColumn(columns, patch, "agencyDigit", "agency_digit", accountDTO.AgencyDigit, nil)
+	patchColumn(columns, patch, "accountDigit", "account_digit", accountDTO.AccountDigit, nil)
+	patchColumn(columns, patch, "description", "description", accountDTO.Description, nil)
+	patchColumn(columns, patch, "wallet", "wallet", accountDTO.Wallet, nil)
+	patchColumn(columns, patch, "walletVariation", "wallet_variation", accountDTO.WalletVariation, nil)
+	patchColumn(columns, patch, "agreement", "agreement", accountDTO.Agreement, nil)
+	patchColumn(columns, patch, "nextOurNumber", "next_our_number", accountDTO.NextOurNumber, nil)
+	return columns
+}
@@ -0,0 +1,53 @@
+package mapper
+
+import (
+	"go-sales/internal/dto"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+)
+
+func MapToBankStatementLineDTO(line *model.BankStatementLine) *dto.BankStatementLineDTO {
+	if line == nil {
+		return nil
+	}
+	return &dto.BankStatementLineDTO{
+		ID:              util.SnowflakeID(line.ID),
+		CompanyGlobalID: util.SnowflakeID(line.CompanyGlobalID),
+		BankAccountID:   util.SnowflakeID(line.BankAccountID),
+		ImportID:        util.SnowflakeID(line.ImportID),
+		ExternalID:      line.ExternalID,
+		PostedAt:        line.PostedAt.Format(dateLayout),
+		Amount:          line.Amount,
+		Description:     line.Description,
+		DocumentNumber:  line.DocumentNumber,
+		Status:          line.Status,
+		InstallmentID:   snowflakePtr(line.InstallmentID),
+		Confidence:      line.Confidence,
+		MatchReasons:    line.MatchReasons,
+		ReconciledAt:    line.ReconciledAt,
+		CreatedAt:       line.CreatedAt,
+		UpdatedAt:       line.UpdatedAt,
+	}
+}
+
+func MapToBankStatementImportDTO(statementImport *model.BankStatementImport, lines []model.BankStatementLine) *dto.BankStatementImportDTO {
+	if statementImport == nil {
+		return nil
+	}
+	importDTO := &dto.BankStatementImportDTO{
+		ID:              util.SnowflakeID(statementImport.ID),
+		CompanyGlobalID: util.SnowflakeID(statementImport.CompanyGlobalID),
+		BankAccountID:   util.SnowflakeID(statementImport.BankAccountID),
+		Format:          statementImport.Format,
+		FileName:        statementImport.FileName,
+		LineCount:       statementImport.LineCount,
+		DuplicateCount:  statementImport.DuplicateCount,
+		SuggestedCount:  statementImport.SuggestedCount,
+		Lines:           make([]dto.BankStatementLineDTO, len(lines)),
+		CreatedAt:       statementImport.CreatedAt,
+	}
+	for i := range lines {
+		importDTO.Lines[i] = *MapToBankStatementLineDTO(&lines[i])
+	}
+	return importDTO
+}
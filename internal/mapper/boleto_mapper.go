@@ -0,0 +1,119 @@
+package mapper
+
+import (
+	"go-sales/internal/dto"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+	"time"
+)
+
+// formatDate formata uma data sem hora opcional.
+func formatDate(date *time.Time) *string {
+	if date == nil {
+		return nil
+	}
+	formatted := date.Format(dateLayout)
+	return &formatted
+}
+
+func snowflakePtr(id *int64) *util.SnowflakeID {
+	if id == nil {
+		return nil
+	}
+	snowflake := util.SnowflakeID(*id)
+	return &snowflake
+}
+
+func MapToBoletoDTO(boleto *model.Boleto) *dto.BoletoDTO {
+	if boleto == nil {
+		return nil
+	}
+	return &dto.BoletoDTO{
+		ID:              util.SnowflakeID(boleto.ID),
+		CompanyGlobalID: util.SnowflakeID(boleto.CompanyGlobalID),
+		BankAccountID:   util.SnowflakeID(boleto.BankAccountID),
+		InstallmentID:   util.SnowflakeID(boleto.InstallmentID),
+		OurNumber:       boleto.OurNumber,
+		Amount:          boleto.Amount,
+		DueDate:         boleto.DueDate.Format(dateLayout),
+		Barcode:         boleto.Barcode,
+		DigitableLine:   boleto.DigitableLine,
+		Status:          boleto.Status,
+		RemittanceID:    snowflakePtr(boleto.RemittanceID),
+		PaidAmount:      boleto.PaidAmount,
+		PaidAt:          boleto.PaidAt,
+		CreditedAt:      formatDate(boleto.CreditedAt),
+		CreatedAt:       boleto.CreatedAt,
+		UpdatedAt:       boleto.UpdatedAt,
+	}
+}
+
+func MapToBoletoDTOs(boletos []model.Boleto) []dto.BoletoDTO {
+	dtos := make([]dto.BoletoDTO, len(boletos))
+	for i := range boletos {
+		dtos[i] = *MapToBoletoDTO(&boletos[i])
+	}
+	return dtos
+}
+
+func MapToBoletoRemittanceDTO(remittance *model.BoletoRemittance) *dto.BoletoRemittanceDTO {
+	if remittance == nil {
+		return nil
+	}
+	return &dto.BoletoRemittanceDTO{
+		ID:              util.SnowflakeID(remittance.ID),
+		CompanyGlobalID: util.SnowflakeID(remittance.CompanyGlobalID),
+		BankAccountID:   util.SnowflakeID(remittance.BankAccountID),
+		Layout:          remittance.Layout,
+		Sequence:        remittance.Sequence,
+		FileName:        remittance.FileName,
+		TitleCount:      remittance.TitleCount,
+		TotalAmount:     remittance.TotalAmount,
+		CreatedAt:       remittance.CreatedAt,
+	}
+}
+
+func MapToBoletoReturnLineDTO(line *model.BoletoReturnLine) dto.BoletoReturnLineDTO {
+	return dto.BoletoReturnLineDTO{
+		LineNumber:     line.LineNumber,
+		OurNumber:      line.OurNumber,
+		DocumentNumber: line.DocumentNumber,
+		BoletoID:       snowflakePtr(line.BoletoID),
+		InstallmentID:  snowflakePtr(line.InstallmentID),
+		Occurrence:     line.Occurrence,
+		Amount:         line.Amount,
+		PaidAmount:     line.PaidAmount,
+		CreditDate:     formatDate(line.CreditDate),
+		Result:         line.Result,
+		Message:        line.Message,
+	}
+}
+
+// MapToBoletoReturnDTO monta o relatório do retorno; unmatched reúne as linhas sem baixa que precisam
+// de conciliação manual.
+func MapToBoletoReturnDTO(ret *model.BoletoReturn) *dto.BoletoReturnDTO {
+	if ret == nil {
+		return nil
+	}
+	returnDTO := &dto.BoletoReturnDTO{
+		ID:              util.SnowflakeID(ret.ID),
+		CompanyGlobalID: util.SnowflakeID(ret.CompanyGlobalID),
+		BankAccountID:   util.SnowflakeID(ret.BankAccountID),
+		Layout:          ret.Layout,
+		FileName:        ret.FileName,
+		Summary:         make(map[string]int),
+		Lines:           make([]dto.BoletoReturnLineDTO, len(ret.Lines)),
+		Unmatched:       make([]dto.BoletoReturnLineDTO, 0),
+		CreatedAt:       ret.CreatedAt,
+	}
+	for i := range ret.Lines {
+		line := MapToBoletoReturnLineDTO(&ret.Lines[i])
+		returnDTO.Lines[i] = line
+		returnDTO.Summary[line.Result]++
+		switch line.Result {
+		case model.BoletoReturnUnmatched, model.BoletoReturnAmountMismatch, model.BoletoReturnInstallmentNotOpen, model.BoletoReturnInvalid:
+			returnDTO.Unmatched = append(returnDTO.Unmatched, line)
+		}
+	}
+	return returnDTO
+}
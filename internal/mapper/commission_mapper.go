@@ -0,0 +1,328 @@
+package mapper
+
+import (
+	"encoding/json"
+	"go-sales/internal/dto"
+	"go-sales/internal/event"
+	"go-sales/internal/model"
+	"go-sales/internal/sales/commission"
+	"go-sales/pkg/decimal"
+	"go-sales/pkg/util"
+	"time"
+)
+
+// periodLayout é o formato dos meses dos extratos de comissão na API.
+const periodLayout = "2006-01"
+
+// ParsePeriod lê um mês no formato 2006-01 e devolve o primeiro dia dele.
+func ParsePeriod(value string) (time.Time, bool) {
+	period, err := time.Parse(periodLayout, value)
+	return period, err == nil
+}
+
+// PeriodOf devolve o primeiro dia do mês da data.
+func PeriodOf(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// CommissionDocumentDate devolve a data do documento da venda (já validada pelo binding).
+func CommissionDocumentDate(entryDTO *dto.CreateCommissionEntryDTO) time.Time {
+	return parseDate(entryDTO.DocumentDate)
+}
+
+// CommissionReversalDate devolve a data do estorno: a informada ou, sem ela, o dia de now.
+func CommissionReversalDate(reverseDTO *dto.ReverseCommissionEntryDTO, now time.Time) time.Time {
+	if reverseDTO.Date != nil {
+		return parseDate(*reverseDTO.Date)
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func MapToCommissionPlanDTO(plan *model.CommissionPlan) *dto.CommissionPlanDTO {
+	if plan == nil {
+		return nil
+	}
+	return &dto.CommissionPlanDTO{
+		ID:              util.SnowflakeID(plan.ID),
+		CompanyGlobalID: util.SnowflakeID(plan.CompanyGlobalID),
+		Name:            plan.Name,
+		Kind:            plan.Kind,
+		DefaultRate:     plan.DefaultRate,
+		Rules:           mapToCommissionRuleDTOs(plan.Rules),
+		Version:         plan.Version,
+		CreatedAt:       plan.CreatedAt,
+		UpdatedAt:       plan.UpdatedAt,
+	}
+}
+
+func mapToCommissionRuleDTOs(rules []model.CommissionRule) []dto.CommissionRuleDTO {
+	ruleDTOs := make([]dto.CommissionRuleDTO, len(rules))
+	for i, rule := range rules {
+		ruleDTOs[i] = dto.CommissionRuleDTO{
+			Category:    rule.Category,
+			MaxDiscount: rule.MaxDiscount,
+			MinVolume:   rule.MinVolume,
+			Rate:        rule.Rate,
+		}
+	}
+	return ruleDTOs
+}
+
+func mapToCommissionRules(ruleDTOs []dto.CommissionRuleDTO) []model.CommissionRule {
+	rules := make([]model.CommissionRule, len(ruleDTOs))
+	for i, ruleDTO := range ruleDTOs {
+		rules[i] = model.CommissionRule{
+			Category:    ruleDTO.Category,
+			MaxDiscount: ruleDTO.MaxDiscount,
+			MinVolume:   ruleDTO.MinVolume,
+			Rate:        ruleDTO.Rate,
+		}
+	}
+	return rules
+}
+
+func MapCreateToCommissionPlan(planDTO *dto.CreateCommissionPlanDTO) *model.CommissionPlan {
+	return &model.CommissionPlan{
+		CompanyGlobalID: planDTO.CompanyGlobalID.Int64(),
+		Name:            planDTO.Name,
+		Kind:            planDTO.Kind,
+		DefaultRate:     planDTO.DefaultRate,
+		Rules:           mapToCommissionRules(planDTO.Rules),
+	}
+}
+
+// MergeCommissionPlan aplica o PATCH sobre uma cópia do plano atual, para que o plano resultante seja
+// validado por inteiro (o tipo e as faixas precisam combinar) antes da gravação.
+func MergeCommissionPlan(planDTO *dto.UpdateCommissionPlanDTO, current *model.CommissionPlan) *model.CommissionPlan {
+	plan := *current
+	patch := planDTO.MergePatch
+	patchValue(patch, "name", planDTO.Name, &plan.Name)
+	patchValue(patch, "kind", planDTO.Kind, &plan.Kind)
+	patchValue(patch, "defaultRate", planDTO.DefaultRate, &plan.DefaultRate)
+	if planDTO.Rules != nil {
+		plan.Rules = mapToCommissionRules(planDTO.Rules)
+	}
+	return &plan
+}
+
+func MapUpdateCommissionPlanToColumns(planDTO *dto.UpdateCommissionPlanDTO) map[string]any {
+	columns := make(map[string]any)
+	patch := planDTO.MergePatch
+	patchColumn(columns, patch, "name", "name", planDTO.Name, nil)
+	patchColumn(columns, patch, "kind", "kind", planDTO.Kind, nil)
+	patchColumn(columns, patch, "defaultRate", "default_rate", planDTO.DefaultRate, nil)
+	// Num Updates por map o GORM não aplica o serializer do model, então o JSON é montado aqui.
+	if planDTO.Rules != nil {
+		rules, _ := json.Marshal(mapToCommissionRules(planDTO.Rules))
+		columns["rules"] = string(rules)
+	}
+	return columns
+}
+
+// MapToCommissionPlan converte o plano gravado para o pacote commission.
+func MapToCommissionPlan(plan *model.CommissionPlan) commission.Plan {
+	rules := make([]commission.Rule, len(plan.Rules))
+	for i, rule := range plan.Rules {
+		rules[i] = commission.Rule{
+			Category:    stringOrEmpty(rule.Category),
+			MaxDiscount: rule.MaxDiscount,
+			MinVolume:   rule.MinVolume,
+			Rate:        rule.Rate,
+		}
+	}
+	return commission.Plan{
+		ID:          plan.ID,
+		Kind:        plan.Kind,
+		DefaultRate: plan.DefaultRate,
+		Rules:       rules,
+	}
+}
+
+func MapCreateToCommissionAssignment(assignmentDTO *dto.CreateCommissionAssignmentDTO, plan *model.CommissionPlan) *model.CommissionPlanAssignment {
+	assignment := &model.CommissionPlanAssignment{
+		CompanyGlobalID: plan.CompanyGlobalID,
+		PlanID:          plan.ID,
+		ValidFrom:       parseDate(assignmentDTO.ValidFrom),
+	}
+	if assignmentDTO.UserID != nil {
+		userID := assignmentDTO.UserID.Int64()
+		assignment.UserID = &userID
+	}
+	if assignmentDTO.RoleID != nil {
+		roleID := assignmentDTO.RoleID.Int64()
+		assignment.RoleID = &roleID
+	}
+	if assignmentDTO.ValidTo != nil {
+		validTo := parseDate(*assignmentDTO.ValidTo)
+		assignment.ValidTo = &validTo
+	}
+	return assignment
+}
+
+func MapToCommissionAssignmentDTO(assignment *model.CommissionPlanAssignment) *dto.CommissionAssignmentDTO {
+	if assignment == nil {
+		return nil
+	}
+	assignmentDTO := &dto.CommissionAssignmentDTO{
+		ID:              util.SnowflakeID(assignment.ID),
+		CompanyGlobalID: util.SnowflakeID(assignment.CompanyGlobalID),
+		PlanID:          util.SnowflakeID(assignment.PlanID),
+		UserID:          snowflakePtr(assignment.UserID),
+		RoleID:          snowflakePtr(assignment.RoleID),
+		ValidFrom:       assignment.ValidFrom.Format(dateLayout),
+		CreatedAt:       assignment.CreatedAt,
+	}
+	if assignment.ValidTo != nil {
+		validTo := assignment.ValidTo.Format(dateLayout)
+		assignmentDTO.ValidTo = &validTo
+	}
+	return assignmentDTO
+}
+
+func MapToCommissionLines(items []dto.CommissionItemDTO) []commission.Line {
+	lines := make([]commission.Line, len(items))
+	for i, item := range items {
+		lines[i] = commission.Line{
+			Category:  stringOrEmpty(item.Category),
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+			Discount:  item.Discount,
+		}
+	}
+	return lines
+}
+
+// MapToCommissionSale monta o lançamento sale de uma venda calculada pelo plano.
+func MapToCommissionSale(entryDTO *dto.CreateCommissionEntryDTO, plan *model.CommissionPlan, result *commission.Result) *model.CommissionEntry {
+	documentDate := parseDate(entryDTO.DocumentDate)
+	lines := make([]model.CommissionLine, len(result.Lines))
+	for i, line := range result.Lines {
+		lines[i] = model.CommissionLine{
+			Category:     line.Category,
+			Value:        line.Value,
+			Discount:     line.Discount,
+			DiscountRate: line.DiscountRate,
+			Base:         line.Base,
+			Rate:         line.Rate,
+			Amount:       line.Amount,
+		}
+	}
+	return &model.CommissionEntry{
+		CompanyGlobalID: entryDTO.CompanyGlobalID.Int64(),
+		UserID:          entryDTO.UserID.Int64(),
+		PlanID:          &plan.ID,
+		Kind:            model.CommissionSale,
+		DocumentNumber:  entryDTO.DocumentNumber,
+		DocumentDate:    documentDate,
+		Period:          PeriodOf(documentDate),
+		BaseAmount:      result.Base,
+		Amount:          result.Amount,
+		Lines:           lines,
+	}
+}
+
+// MapToCommissionReversal monta o estorno (valores negativos) de base e amount da venda sale.
+func MapToCommissionReversal(sale *model.CommissionEntry, reason string, date time.Time, base, amount decimal.Decimal) *model.CommissionEntry {
+	return &model.CommissionEntry{
+		CompanyGlobalID: sale.CompanyGlobalID,
+		UserID:          sale.UserID,
+		PlanID:          sale.PlanID,
+		Kind:            model.CommissionReversal,
+		DocumentNumber:  sale.DocumentNumber,
+		DocumentDate:    date,
+		Period:          PeriodOf(date),
+		BaseAmount:      base.Neg(),
+		Amount:          amount.Neg(),
+		ReversedEntryID: &sale.ID,
+		Reason:          &reason,
+	}
+}
+
+// MapInvoiceToCommissionEntryDTO monta a venda do vendedor a partir da nota emitida.
+func MapInvoiceToCommissionEntryDTO(invoice event.InvoiceIssued) dto.CreateCommissionEntryDTO {
+	items := make([]dto.CommissionItemDTO, len(invoice.Items))
+	for i, item := range invoice.Items {
+		items[i] = dto.CommissionItemDTO{
+			Category:  item.Category,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+			Discount:  item.Discount,
+		}
+	}
+	return dto.CreateCommissionEntryDTO{
+		CompanyGlobalID: invoice.CompanyGlobalID,
+		UserID:          *invoice.SellerID,
+		DocumentNumber:  invoice.Number,
+		DocumentDate:    invoice.IssueDate,
+		Items:           items,
+	}
+}
+
+// MapInvoiceToReverseCommissionDTO monta o estorno do cancelamento ou da devolução da nota. Sem date, o
+// estorno é de hoje.
+func MapInvoiceToReverseCommissionDTO(reason string, date string, baseAmount *decimal.Decimal) dto.ReverseCommissionEntryDTO {
+	reverseDTO := dto.ReverseCommissionEntryDTO{Reason: reason, BaseAmount: baseAmount}
+	if date != "" {
+		reverseDTO.Date = &date
+	}
+	return reverseDTO
+}
+
+func MapToCommissionEntryDTO(entry *model.CommissionEntry) *dto.CommissionEntryDTO {
+	if entry == nil {
+		return nil
+	}
+	entryDTO := &dto.CommissionEntryDTO{
+		ID:              util.SnowflakeID(entry.ID),
+		CompanyGlobalID: util.SnowflakeID(entry.CompanyGlobalID),
+		UserID:          util.SnowflakeID(entry.UserID),
+		PlanID:          snowflakePtr(entry.PlanID),
+		Kind:            entry.Kind,
+		DocumentNumber:  entry.DocumentNumber,
+		DocumentDate:    entry.DocumentDate.Format(dateLayout),
+		Period:          entry.Period.Format(periodLayout),
+		BaseAmount:      entry.BaseAmount,
+		Amount:          entry.Amount,
+		ReversedEntryID: snowflakePtr(entry.ReversedEntryID),
+		Reason:          entry.Reason,
+		StatementID:     snowflakePtr(entry.StatementID),
+		CreatedAt:       entry.CreatedAt,
+	}
+	for i, line := range entry.Lines {
+		entryDTO.Lines = append(entryDTO.Lines, dto.CommissionLineDTO{
+			Item:         i + 1,
+			Category:     line.Category,
+			Value:        line.Value,
+			Discount:     line.Discount,
+			DiscountRate: line.DiscountRate,
+			Base:         line.Base,
+			Rate:         line.Rate,
+			Amount:       line.Amount,
+		})
+	}
+	return entryDTO
+}
+
+// MapToCommissionStatementDTO monta o extrato do mês; statement sem ID é um extrato ainda aberto.
+func MapToCommissionStatementDTO(statement *model.CommissionStatement, entries []model.CommissionEntry) *dto.CommissionStatementDTO {
+	statementDTO := &dto.CommissionStatementDTO{
+		CompanyGlobalID: util.SnowflakeID(statement.CompanyGlobalID),
+		UserID:          util.SnowflakeID(statement.UserID),
+		Period:          statement.Period.Format(periodLayout),
+		Status:          model.CommissionStatementOpen,
+		EntryCount:      statement.EntryCount,
+		BaseAmount:      statement.BaseAmount,
+		TotalAmount:     statement.TotalAmount,
+	}
+	if statement.ID != 0 {
+		statementDTO.ID = snowflakePtr(&statement.ID)
+		statementDTO.Status = model.CommissionStatementApproved
+		statementDTO.ApprovedAt = &statement.ApprovedAt
+		statementDTO.ApprovedByType = &statement.ApprovedByType
+		statementDTO.ApprovedByID = snowflakePtr(&statement.ApprovedByID)
+	}
+	for i := range entries {
+		statementDTO.Entries = append(statementDTO.Entries, *MapToCommissionEntryDTO(&entries[i]))
+	}
+	return statementDTO
+}
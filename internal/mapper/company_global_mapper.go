@@ -2,10 +2,13 @@ package mapper
 
 import (
 	"go-sales/internal/dto"
+	"go-sales/internal/fiscal/tax"
 	"go-sales/internal/model"
+	"go-sales/pkg/util"
 	"time"
 )
 
+// MapToCompanyGlobalAddress converte o endereço do payload da company, que é sempre o principal (MAIN).
 func MapToCompanyGlobalAddress(addressDTO *dto.CreateCompanyGlobalAddressDTO) *model.CompanyGlobalAddress {
 	if addressDTO == nil {
 		return nil
@@ -13,6 +16,7 @@ func MapToCompanyGlobalAddress(addressDTO *dto.CreateCompanyGlobalAddressDTO) *m
 	return &model.CompanyGlobalAddress{
 		ID:               0,
 		CompanyID:        0,
+		Type:             model.AddressTypeMain,
 		Street:           addressDTO.Street,
 		StreetNumber:     addressDTO.StreetNumber,
 		StreetComplement: addressDTO.StreetComplement,
@@ -23,12 +27,39 @@ func MapToCompanyGlobalAddress(addressDTO *dto.CreateCompanyGlobalAddressDTO) *m
 	}
 }
 
+// MapToSaveCompanyGlobalAddress converte o corpo de POST/PUT /company-globals/:id/addresses.
+func MapToSaveCompanyGlobalAddress(addressDTO *dto.SaveCompanyGlobalAddressDTO, companyID int64) *model.CompanyGlobalAddress {
+	if addressDTO == nil {
+		return nil
+	}
+	return &model.CompanyGlobalAddress{
+		ID:               0,
+		CompanyID:        companyID,
+		Type:             addressDTO.Type,
+		Street:           addressDTO.Street,
+		StreetNumber:     addressDTO.StreetNumber,
+		StreetComplement: addressDTO.StreetComplement,
+		City:             addressDTO.City,
+		State:            addressDTO.State,
+		PostalCode:       addressDTO.PostalCode,
+		Country:          addressDTO.Country,
+	}
+}
+
+// MapToCompanyGlobalAddresses monta a lista de endereços a partir do endereço principal do payload.
+func MapToCompanyGlobalAddresses(addressDTO *dto.CreateCompanyGlobalAddressDTO) []*model.CompanyGlobalAddress {
+	if address := MapToCompanyGlobalAddress(addressDTO); address != nil {
+		return []*model.CompanyGlobalAddress{address}
+	}
+	return nil
+}
+
 func MapToCompanyGlobalContact(contactDTO *dto.CreateCompanyGlobalContactDTO) *model.CompanyGlobalContact {
 	if contactDTO == nil {
 		return nil
 	}
 	return &model.CompanyGlobalContact{
-		ID:        0,
+		ID:        contactDTO.ID.Int64(),
 		CompanyID: 0,
 		Name:      contactDTO.Name,
 		Email:     contactDTO.Email,
@@ -55,7 +86,8 @@ func MapToCompanyGlobalAddressDTO(address *model.CompanyGlobalAddress) *dto.Comp
 		return nil
 	}
 	return &dto.CompanyGlobalAddressDTO{
-		ID:               address.ID,
+		ID:               util.SnowflakeID(address.ID),
+		Type:             address.Type,
 		Street:           address.Street,
 		StreetNumber:     address.StreetNumber,
 		StreetComplement: address.StreetComplement,
@@ -63,7 +95,22 @@ func MapToCompanyGlobalAddressDTO(address *model.CompanyGlobalAddress) *dto.Comp
 		State:            address.State,
 		PostalCode:       address.PostalCode,
 		Country:          address.Country,
+		CityIBGECode:     address.CityIBGECode,
+		Version:          address.Version,
+	}
+}
+
+func MapToCompanyGlobalAddressDTOs(addresses []*model.CompanyGlobalAddress) []*dto.CompanyGlobalAddressDTO {
+	if addresses == nil {
+		return nil
 	}
+	dtos := make([]*dto.CompanyGlobalAddressDTO, 0, len(addresses))
+	for _, address := range addresses {
+		if dto := MapToCompanyGlobalAddressDTO(address); dto != nil {
+			dtos = append(dtos, dto)
+		}
+	}
+	return dtos
 }
 
 func MapToCompanyGlobalContactDTO(contact *model.CompanyGlobalContact) *dto.CompanyGlobalContactDTO {
@@ -71,11 +118,12 @@ func MapToCompanyGlobalContactDTO(contact *model.CompanyGlobalContact) *dto.Comp
 		return nil
 	}
 	return &dto.CompanyGlobalContactDTO{
-		ID:    contact.ID,
-		Name:  contact.Name,
-		Email: contact.Email,
-		Phone: contact.Phone,
-		CGC:   contact.CGC,
+		ID:      util.SnowflakeID(contact.ID),
+		Name:    contact.Name,
+		Email:   contact.Email,
+		Phone:   contact.Phone,
+		CGC:     contact.CGC,
+		Version: contact.Version,
 	}
 }
 
@@ -106,15 +154,19 @@ func MapToCompanyGlobalDTO(company *model.CompanyGlobal) *dto.CompanyGlobalDTO {
 	}
 
 	return &dto.CompanyGlobalDTO{
-		ID:          company.ID,
+		ID:          util.SnowflakeID(company.ID),
 		Name:        company.Name,
 		SocialName:  company.SocialName,
 		Description: company.Description,
 		CGC:         company.CGC,
 		Enabled:     company.Enabled,
 		Email:       company.Email,
-		Address:     MapToCompanyGlobalAddressDTO(company.Address),
+		TaxRegime:   company.TaxRegime,
+		PixKey:      company.PixKey,
+		Address:     MapToCompanyGlobalAddressDTO(company.MainAddress()),
+		Addresses:   MapToCompanyGlobalAddressDTOs(company.Addresses),
 		Contacts:    MapToCompanyGlobalContactDTOs(company.Contacts),
+		Version:     company.Version,
 		CreatedAt:   company.CreatedAt,
 		UpdatedAt:   company.UpdatedAt,
 		DeletedAt:   deletedAt,
@@ -149,7 +201,9 @@ func MapToCreateCompanyGlobal(companyDTO *dto.CreateCompanyGlobalDTO) *model.Com
 		CGC:         companyDTO.CGC,
 		Enabled:     companyDTO.Enabled,
 		Email:       companyDTO.Email,
-		Address:     MapToCompanyGlobalAddress(companyDTO.Address),
+		TaxRegime:   taxRegimeOrDefault(companyDTO.TaxRegime),
+		PixKey:      companyDTO.PixKey,
+		Addresses:   MapToCompanyGlobalAddresses(companyDTO.Address),
 		Contacts:    MapToCompanyGlobalContacts(companyDTO.Contacts),
 	}
 }
@@ -167,7 +221,50 @@ func MapToUpdateCompanyGlobal(companyDTO *dto.CreateCompanyGlobalDTO, id int64)
 		CGC:         companyDTO.CGC,
 		Enabled:     companyDTO.Enabled,
 		Email:       companyDTO.Email,
-		Address:     MapToCompanyGlobalAddress(companyDTO.Address),
+		TaxRegime:   taxRegimeOrDefault(companyDTO.TaxRegime),
+		PixKey:      companyDTO.PixKey,
+		Addresses:   MapToCompanyGlobalAddresses(companyDTO.Address),
 		Contacts:    MapToCompanyGlobalContacts(companyDTO.Contacts),
 	}
 }
+
+// MapUpdateCompanyGlobalToColumns converte um PATCH nas colunas de company_globals a atualizar.
+func MapUpdateCompanyGlobalToColumns(companyDTO *dto.UpdateCompanyGlobalDTO) map[string]any {
+	columns := make(map[string]any)
+	patch := companyDTO.MergePatch
+	patchColumn(columns, patch, "name", "name", companyDTO.Name, nil)
+	patchColumn(columns, patch, "socialName", "social_name", companyDTO.SocialName, nil)
+	patchColumn(columns, patch, "description", "description", companyDTO.Description, nil)
+	patchColumn(columns, patch, "cgc", "cgc", companyDTO.CGC, nil)
+	patchColumn(columns, patch, "enabled", "enabled", companyDTO.Enabled, nil)
+	patchColumn(columns, patch, "email", "email", companyDTO.Email, nil)
+	patchColumn(columns, patch, "taxRegime", "tax_regime", companyDTO.TaxRegime, nil)
+	patchColumn(columns, patch, "pixKey", "pix_key", companyDTO.PixKey, nil)
+	return columns
+}
+
+// taxRegimeOrDefault devolve o regime informado ou, se vazio, o regime normal.
+func taxRegimeOrDefault(taxRegime string) string {
+	if taxRegime == "" {
+		return tax.RegimeNormal
+	}
+	return taxRegime
+}
+
+// MergeCompanyGlobalAddress aplica o PATCH do endereço sobre o endereço atual (ou um novo, se não houver).
+func MergeCompanyGlobalAddress(addressDTO *dto.UpdateCompanyGlobalAddressDTO, current *model.CompanyGlobalAddress) *model.CompanyGlobalAddress {
+	address := &model.CompanyGlobalAddress{}
+	if current != nil {
+		*address = *current
+	}
+	address.Type = model.AddressTypeMain
+	patch := addressDTO.MergePatch
+	patchValue(patch, "street", addressDTO.Street, &address.Street)
+	patchPointer(patch, "streetNumber", addressDTO.StreetNumber, &address.StreetNumber)
+	patchPointer(patch, "streetComplement", addressDTO.StreetComplement, &address.StreetComplement)
+	patchValue(patch, "city", addressDTO.City, &address.City)
+	patchValue(patch, "state", addressDTO.State, &address.State)
+	patchValue(patch, "postalCode", addressDTO.PostalCode, &address.PostalCode)
+	patchValue(patch, "country", addressDTO.Country, &address.Country)
+	return address
+}
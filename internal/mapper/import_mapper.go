@@ -0,0 +1,41 @@
+package mapper
+
+import (
+	"encoding/json"
+	"go-sales/pkg/util"
+
+	"go-sales/internal/dto"
+	"go-sales/internal/model"
+
+	"github.com/rs/zerolog/log"
+)
+
+func MapToImportJobDTO(job *model.ImportJob) *dto.ImportJobDTO {
+	if job == nil {
+		return nil
+	}
+	errors := make([]dto.ImportRowErrorDTO, 0)
+	if len(job.Errors) > 0 {
+		if err := json.Unmarshal(job.Errors, &errors); err != nil {
+			log.Warn().Err(err).Int64("import_job_id", job.ID).Msg("failed to decode import errors")
+		}
+	}
+	return &dto.ImportJobDTO{
+		ID:              util.SnowflakeID(job.ID),
+		CompanyGlobalID: util.SnowflakeID(job.CompanyGlobalID),
+		Entity:          job.Entity,
+		FileName:        job.FileName,
+		DryRun:          job.DryRun,
+		Status:          job.Status,
+		TotalRows:       job.TotalRows,
+		ProcessedRows:   job.ProcessedRows,
+		SucceededRows:   job.SucceededRows,
+		FailedRows:      job.FailedRows,
+		Errors:          errors,
+		ErrorMessage:    job.ErrorMessage,
+		StartedAt:       job.StartedAt,
+		FinishedAt:      job.FinishedAt,
+		CreatedAt:       job.CreatedAt,
+		UpdatedAt:       job.UpdatedAt,
+	}
+}
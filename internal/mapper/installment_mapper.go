@@ -0,0 +1,78 @@
+package mapper
+
+import (
+	"go-sales/internal/dto"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+	"time"
+)
+
+// dateLayout é o formato das datas sem hora (vencimentos) na API.
+const dateLayout = "2006-01-02"
+
+// parseDate lê uma data já validada pelo binding (datetime=2006-01-02).
+func parseDate(value string) time.Time {
+	date, _ := time.Parse(dateLayout, value)
+	return date
+}
+
+func MapToInstallmentDTO(installment *model.Installment) *dto.InstallmentDTO {
+	if installment == nil {
+		return nil
+	}
+	return &dto.InstallmentDTO{
+		ID:                util.SnowflakeID(installment.ID),
+		CompanyGlobalID:   util.SnowflakeID(installment.CompanyGlobalID),
+		DocumentNumber:    installment.DocumentNumber,
+		InstallmentNumber: installment.InstallmentNumber,
+		CustomerName:      installment.CustomerName,
+		CustomerCGC:       installment.CustomerCGC,
+		Description:       installment.Description,
+		Amount:            installment.Amount,
+		DueDate:           installment.DueDate.Format(dateLayout),
+		Status:            installment.Status,
+		PaidAmount:        installment.PaidAmount,
+		PaidAt:            installment.PaidAt,
+		PaymentMethod:     installment.PaymentMethod,
+		PaymentReference:  installment.PaymentReference,
+		Version:           installment.Version,
+		CreatedAt:         installment.CreatedAt,
+		UpdatedAt:         installment.UpdatedAt,
+	}
+}
+
+func MapCreateToInstallment(installmentDTO *dto.CreateInstallmentDTO) *model.Installment {
+	installmentNumber := installmentDTO.InstallmentNumber
+	if installmentNumber == 0 {
+		installmentNumber = 1
+	}
+	return &model.Installment{
+		CompanyGlobalID:   installmentDTO.CompanyGlobalID.Int64(),
+		DocumentNumber:    installmentDTO.DocumentNumber,
+		InstallmentNumber: installmentNumber,
+		CustomerName:      installmentDTO.CustomerName,
+		CustomerCGC:       installmentDTO.CustomerCGC,
+		Description:       installmentDTO.Description,
+		Amount:            installmentDTO.Amount.Round(2),
+		DueDate:           parseDate(installmentDTO.DueDate),
+		Status:            model.InstallmentOpen,
+	}
+}
+
+// MapUpdateInstallmentToColumns converte um PATCH nas colunas de installments a atualizar.
+func MapUpdateInstallmentToColumns(installmentDTO *dto.UpdateInstallmentDTO) map[string]any {
+	columns := make(map[string]any)
+	patch := installmentDTO.MergePatch
+	patchColumn(columns, patch, "customerName", "customer_name", installmentDTO.CustomerName, nil)
+	patchColumn(columns, patch, "customerCgc", "customer_cgc", installmentDTO.CustomerCGC, nil)
+	patchColumn(columns, patch, "description", "description", installmentDTO.Description, nil)
+	if installmentDTO.Amount != nil {
+		amount := installmentDTO.Amount.Round(2)
+		patchColumn(columns, patch, "amount", "amount", &amount, nil)
+	}
+	if installmentDTO.DueDate != nil {
+		dueDate := parseDate(*installmentDTO.DueDate)
+		patchColumn(columns, patch, "dueDate", "due_date", &dueDate, nil)
+	}
+	return columns
+}
@@ -0,0 +1,25 @@
+package mapper
+
+import (
+	"go-sales/internal/dto"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+)
+
+func MapToJobDTO(job *model.Job) *dto.JobDTO {
+	if job == nil {
+		return nil
+	}
+	return &dto.JobDTO{
+		ID:          util.SnowflakeID(job.ID),
+		Type:        job.Type,
+		Status:      job.Status,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt,
+		LastError:   job.LastError,
+		FinishedAt:  job.FinishedAt,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+}
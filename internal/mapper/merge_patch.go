@@ -0,0 +1,30 @@
+package mapper
+
+import "go-sales/internal/dto"
+
+// patchColumn grava em columns a coluna correspondente a um campo de JSON Merge Patch:
+// campos ausentes são ignorados e null grava cleared (nil para colunas anuláveis).
+func patchColumn[T any](columns map[string]any, patch dto.MergePatch, field, column string, value *T, cleared any) {
+	if !patch.Has(field) {
+		return
+	}
+	if value == nil {
+		columns[column] = cleared
+		return
+	}
+	columns[column] = *value
+}
+
+// patchValue aplica um campo de JSON Merge Patch sobre o valor atual de um model.
+func patchValue[T any](patch dto.MergePatch, field string, value *T, target *T) {
+	if patch.Has(field) && value != nil {
+		*target = *value
+	}
+}
+
+// patchPointer aplica um campo anulável de JSON Merge Patch sobre o valor atual de um model.
+func patchPointer[T any](patch dto.MergePatch, field string, value *T, target **T) {
+	if patch.Has(field) {
+		*target = value
+	}
+}
@@ -3,6 +3,7 @@ package mapper
 import (
 	"go-sales/internal/dto"
 	"go-sales/internal/model"
+	"go-sales/pkg/util"
 )
 
 // MapToPermissionDTO converte um model.Permission para dto.PermissionDTO.
@@ -12,11 +13,12 @@ func MapToPermissionDTO(permission *model.Permission) *dto.PermissionDTO {
 	}
 
 	return &dto.PermissionDTO{
-		ID:   permission.ID,
+		ID:   util.SnowflakeID(permission.ID),
 		Name: permission.Name,
 
-		CompanyGlobalID: permission.CompanyGlobalID,
+		CompanyGlobalID: util.SnowflakeID(permission.CompanyGlobalID),
 		Description:     permission.Description,
+		Version:         permission.Version,
 		CreatedAt:       permission.CreatedAt,
 		UpdatedAt:       permission.UpdatedAt,
 	}
@@ -45,7 +47,7 @@ func MapToPermission(dto *dto.CreatePermissionDTO) *model.Permission {
 
 	return &model.Permission{
 		ID:              0,
-		CompanyGlobalID: dto.CompanyGlobalID,
+		CompanyGlobalID: dto.CompanyGlobalID.Int64(),
 		Name:            dto.Name,
 		Description:     dto.Description,
 	}
@@ -71,8 +73,8 @@ func DTOMapToPermission(dto *dto.PermissionDTO) *model.Permission {
 	}
 
 	return &model.Permission{
-		ID:              dto.ID,
-		CompanyGlobalID: dto.CompanyGlobalID,
+		ID:              dto.ID.Int64(),
+		CompanyGlobalID: dto.CompanyGlobalID.Int64(),
 		Name:            dto.Name,
 		Description:     dto.Description,
 		CreatedAt:       dto.CreatedAt,
@@ -93,3 +95,12 @@ func DTOsMapToPermissions(dtos []dto.PermissionDTO) []*model.Permission {
 	}
 	return permissions
 }
+
+// MapUpdatePermissionToColumns converte um PATCH nas colunas de permissions a atualizar.
+func MapUpdatePermissionToColumns(permissionDTO *dto.UpdatePermissionDTO) map[string]any {
+	columns := make(map[string]any)
+	patch := permissionDTO.MergePatch
+	patchColumn(columns, patch, "name", "name", permissionDTO.Name, nil)
+	patchColumn(columns, patch, "description", "description", permissionDTO.Description, nil)
+	return columns
+}
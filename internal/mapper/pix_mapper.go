@@ -0,0 +1,38 @@
+package mapper
+
+import (
+	"go-sales/internal/dto"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+)
+
+func MapToPixChargeDTO(charge *model.PixCharge) *dto.PixChargeDTO {
+	if charge == nil {
+		return nil
+	}
+	return &dto.PixChargeDTO{
+		ID:              util.SnowflakeID(charge.ID),
+		CompanyGlobalID: util.SnowflakeID(charge.CompanyGlobalID),
+		InstallmentID:   util.SnowflakeID(charge.InstallmentID),
+		TxID:            charge.TxID,
+		Type:            charge.Type,
+		PixKey:          charge.PixKey,
+		Amount:          charge.Amount,
+		Payload:         charge.Payload,
+		Location:        charge.Location,
+		Status:          charge.Status,
+		EndToEndID:      charge.EndToEndID,
+		PaidAmount:      charge.PaidAmount,
+		PaidAt:          charge.PaidAt,
+		ExpiresAt:       charge.ExpiresAt,
+		CreatedAt:       charge.CreatedAt,
+	}
+}
+
+func MapToPixChargeDTOs(charges []model.PixCharge) []dto.PixChargeDTO {
+	dtos := make([]dto.PixChargeDTO, len(charges))
+	for i := range charges {
+		dtos[i] = *MapToPixChargeDTO(&charges[i])
+	}
+	return dtos
+}
@@ -0,0 +1,176 @@
+package mapper
+
+import (
+	"reflect"
+	"strings"
+
+	"go-sales/internal/dto"
+)
+
+// associations lista, por DTO, os campos JSON que são associações e só aparecem quando expandidos.
+var associations = map[reflect.Type]map[string]bool{
+	reflect.TypeOf(dto.UserDTO{}):          {"companyGlobal": true, "roles": true},
+	reflect.TypeOf(dto.RoleDTO{}):          {"permissions": true},
+	reflect.TypeOf(dto.CompanyGlobalDTO{}): {"address": true, "addresses": true, "contacts": true},
+}
+
+// exportHidden lista, por DTO, os campos que nunca são lidos nas listagens ou que não cabem numa coluna
+// (listas) e por isso ficam fora da exportação.
+var exportHidden = map[reflect.Type]map[string]bool{
+	reflect.TypeOf(dto.UserDTO{}):                {"password": true, "activationKey": true, "resetKey": true},
+	reflect.TypeOf(dto.CommissionEntryDTO{}):     {"lines": true},
+	reflect.TypeOf(dto.CommissionStatementDTO{}): {"entries": true},
+}
+
+// Project reduz o DTO aos campos pedidos em ?fields= e às associações pedidas em ?expand=.
+// O id é sempre mantido. Sem ?fields= e com todas as associações, devolve o próprio DTO.
+func Project(v any, opts dto.ReadOptions) any {
+	if len(opts.Fields) == 0 && opts.ExpandAll {
+		return v
+	}
+	fields := make(map[string]bool, len(opts.Fields))
+	for _, name := range opts.Fields {
+		fields[name] = true
+	}
+	return project(reflect.ValueOf(v), fields, expandTree(opts.Expand), opts.ExpandAll)
+}
+
+// ProjectPage aplica Project em cada item de uma página.
+func ProjectPage[T any](page *dto.PaginatedResponse[T], opts dto.ReadOptions) *dto.PaginatedResponse[any] {
+	items := make([]any, len(page.Items))
+	for i := range page.Items {
+		items[i] = Project(page.Items[i], opts)
+	}
+	return &dto.PaginatedResponse[any]{Items: items, PageInfo: page.PageInfo}
+}
+
+// expandTree agrupa os caminhos de ?expand= pelo primeiro nível:
+// "roles.permissions" vira {"roles": ["permissions"]}.
+func expandTree(paths []string) map[string][]string {
+	tree := make(map[string][]string, len(paths))
+	for _, path := range paths {
+		head, rest, _ := strings.Cut(path, ".")
+		if rest != "" {
+			tree[head] = append(tree[head], rest)
+		} else if _, ok := tree[head]; !ok {
+			tree[head] = nil
+		}
+	}
+	return tree
+}
+
+func project(v reflect.Value, fields map[string]bool, expand map[string][]string, expandAll bool) any {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice:
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = project(v.Index(i), fields, expand, expandAll)
+		}
+		return items
+	case reflect.Struct:
+	default:
+		return v.Interface()
+	}
+
+	assocs, ok := associations[v.Type()]
+	if !ok {
+		return v.Interface()
+	}
+
+	out := make(map[string]any, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		name, omitEmpty := jsonName(v.Type().Field(i))
+		if name == "" {
+			continue
+		}
+		fv := v.Field(i)
+
+		if assocs[name] {
+			nested, expanded := expand[name]
+			if !expanded && !expandAll {
+				continue
+			}
+			// As associações aninhadas só aparecem quando pedidas (ex: roles.permissions).
+			out[name] = project(fv, nil, expandTree(nested), false)
+			continue
+		}
+
+		if len(fields) > 0 && !fields[name] && name != "id" {
+			continue
+		}
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+		out[name] = fv.Interface()
+	}
+	return out
+}
+
+func jsonName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, strings.Contains(options, "omitempty")
+}
+
+// ExportColumns lista as colunas da exportação de T: os campos JSON do DTO na ordem da struct, sem
+// as associações e limitados a ?fields= (o id é sempre incluído). Todas as linhas usam as mesmas colunas.
+func ExportColumns[T any](opts dto.ReadOptions) []string {
+	t := reflect.TypeFor[T]()
+	fields := make(map[string]bool, len(opts.Fields))
+	for _, name := range opts.Fields {
+		fields[name] = true
+	}
+
+	columns := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _ := jsonName(t.Field(i))
+		if name == "" || associations[t][name] || exportHidden[t][name] {
+			continue
+		}
+		if len(fields) > 0 && !fields[name] && name != "id" {
+			continue
+		}
+		columns = append(columns, name)
+	}
+	return columns
+}
+
+// ExportValues devolve os valores de item nas colunas informadas. Ponteiros nulos viram nil.
+func ExportValues(item any, columns []string) []any {
+	v := reflect.Indirect(reflect.ValueOf(item))
+	index := make(map[string]int, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		if name, _ := jsonName(v.Type().Field(i)); name != "" {
+			index[name] = i
+		}
+	}
+
+	values := make([]any, len(columns))
+	for i, column := range columns {
+		fv := v.Field(index[column])
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+		values[i] = fv.Interface()
+	}
+	return values
+}
@@ -3,6 +3,7 @@ package mapper
 import (
 	"go-sales/internal/dto"
 	"go-sales/internal/model"
+	"go-sales/pkg/util"
 )
 
 // MapToRoleDTO converte um model.Role para dto.RoleDTO.
@@ -13,14 +14,15 @@ func MapToRoleDTO(role *model.Role) *dto.RoleDTO {
 	}
 
 	return &dto.RoleDTO{
-		ID:              role.ID,
+		ID:              util.SnowflakeID(role.ID),
 		Name:            role.Name,
 		Description:     role.Description,
 		Permissions:     *MapToPermissionDTOs(role.Permissions),
-		CompanyGlobalID: role.CompanyGlobalID,
+		CompanyGlobalID: util.SnowflakeID(role.CompanyGlobalID),
 		CanEdit:         role.CanEdit,
 		CanDelete:       role.CanDelete,
 		IsAdmin:         role.IsAdmin,
+		Version:         role.Version,
 		CreatedAt:       role.CreatedAt,
 		UpdatedAt:       role.UpdatedAt,
 	}
@@ -32,10 +34,10 @@ func MapToRole(roleDTO *dto.RoleDTO) *model.Role {
 	}
 
 	return &model.Role{
-		ID:              roleDTO.ID,
+		ID:              roleDTO.ID.Int64(),
 		Name:            roleDTO.Name,
 		Description:     roleDTO.Description,
-		CompanyGlobalID: roleDTO.CompanyGlobalID,
+		CompanyGlobalID: roleDTO.CompanyGlobalID.Int64(),
 		Permissions:     DTOsMapToPermissions(roleDTO.Permissions),
 		CanEdit:         roleDTO.CanEdit,
 		CanDelete:       roleDTO.CanDelete,
@@ -53,14 +55,14 @@ func MapCreateToRole(dto *dto.CreateRoleDTO) *model.Role {
 	permissions := make([]*model.Permission, len(dto.Permissions))
 	for i, perm := range dto.Permissions {
 		permissions[i] = &model.Permission{
-			ID: perm.ID,
+			ID: perm.ID.Int64(),
 		}
 	}
 
 	return &model.Role{
 		Name:            dto.Name,
 		Description:     dto.Description,
-		CompanyGlobalID: dto.CompanyGlobalID,
+		CompanyGlobalID: dto.CompanyGlobalID.Int64(),
 		Permissions:     permissions,
 		CanEdit:         dto.CanEdit,
 		CanDelete:       dto.CanDelete,
@@ -82,3 +84,16 @@ func MapToRoleDTOs(roles []*model.Role) *[]dto.RoleDTO {
 	}
 	return &result
 }
+
+// MapUpdateRoleToColumns converte um PATCH nas colunas de roles a atualizar.
+// As permissões são tratadas pelo serviço.
+func MapUpdateRoleToColumns(roleDTO *dto.UpdateRoleDTO) map[string]any {
+	columns := make(map[string]any)
+	patch := roleDTO.MergePatch
+	patchColumn(columns, patch, "name", "name", roleDTO.Name, nil)
+	patchColumn(columns, patch, "description", "description", roleDTO.Description, nil)
+	patchColumn(columns, patch, "canEdit", "can_edit", roleDTO.CanEdit, nil)
+	patchColumn(columns, patch, "canDelete", "can_delete", roleDTO.CanDelete, nil)
+	patchColumn(columns, patch, "isAdmin", "is_admin", roleDTO.IsAdmin, nil)
+	return columns
+}
@@ -0,0 +1,55 @@
+package mapper
+
+import (
+	"go-sales/internal/dto"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+)
+
+func MapToServiceAccountDTO(account *model.ServiceAccount) *dto.ServiceAccountDTO {
+	if account == nil {
+		return nil
+	}
+	return &dto.ServiceAccountDTO{
+		ID:              util.SnowflakeID(account.ID),
+		Name:            account.Name,
+		Description:     account.Description,
+		CompanyGlobalID: util.SnowflakeID(account.CompanyGlobalID),
+		Enabled:         account.Enabled,
+		Roles:           *MapToRoleDTOs(account.Roles),
+		Version:         account.Version,
+		CreatedAt:       account.CreatedAt,
+		UpdatedAt:       account.UpdatedAt,
+	}
+}
+
+func MapCreateToServiceAccount(accountDTO *dto.CreateServiceAccountDTO, roles []*model.Role) *model.ServiceAccount {
+	return &model.ServiceAccount{
+		Name:            accountDTO.Name,
+		Description:     accountDTO.Description,
+		CompanyGlobalID: accountDTO.CompanyGlobalID.Int64(),
+		Enabled:         true,
+		Roles:           roles,
+	}
+}
+
+func MapUpdateServiceAccountToColumns(accountDTO *dto.UpdateServiceAccountDTO) map[string]any {
+	columns := make(map[string]any)
+	patch := accountDTO.MergePatch
+	patchColumn(columns, patch, "name", "name", accountDTO.Name, nil)
+	patchColumn(columns, patch, "description", "description", accountDTO.Description, nil)
+	patchColumn(columns, patch, "enabled", "enabled", accountDTO.Enabled, nil)
+	return columns
+}
+
+func MapToAPIKeyDTO(key *model.APIKey) dto.APIKeyDTO {
+	return dto.APIKeyDTO{
+		ID:               util.SnowflakeID(key.ID),
+		ServiceAccountID: util.SnowflakeID(key.ServiceAccountID),
+		Prefix:           key.Prefix,
+		ExpiresAt:        key.ExpiresAt,
+		LastUsedAt:       key.LastUsedAt,
+		RevokedAt:        key.RevokedAt,
+		CreatedAt:        key.CreatedAt,
+	}
+}
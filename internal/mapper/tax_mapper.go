@@ -0,0 +1,239 @@
+package mapper
+
+import (
+	"go-sales/internal/dto"
+	"go-sales/internal/fiscal/tax"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+)
+
+func MapToTaxRuleDTO(rule *model.TaxRule) *dto.TaxRuleDTO {
+	if rule == nil {
+		return nil
+	}
+	return &dto.TaxRuleDTO{
+		ID:                       util.SnowflakeID(rule.ID),
+		CompanyGlobalID:          util.SnowflakeID(rule.CompanyGlobalID),
+		Description:              rule.Description,
+		OriginUF:                 rule.OriginUF,
+		DestinationUF:            rule.DestinationUF,
+		NCM:                      rule.NCM,
+		CFOP:                     rule.CFOP,
+		CustomerRegime:           rule.CustomerRegime,
+		IssuerRegime:             rule.IssuerRegime,
+		ICMSCode:                 rule.ICMSCode,
+		ICMSRate:                 rule.ICMSRate,
+		ICMSBaseReduction:        rule.ICMSBaseReduction,
+		SimplesCreditRate:        rule.SimplesCreditRate,
+		ICMSSTMVA:                rule.ICMSSTMVA,
+		ICMSSTRate:               rule.ICMSSTRate,
+		ICMSSTBaseReduction:      rule.ICMSSTBaseReduction,
+		IPICode:                  rule.IPICode,
+		IPIRate:                  rule.IPIRate,
+		PISCode:                  rule.PISCode,
+		PISRate:                  rule.PISRate,
+		COFINSCode:               rule.COFINSCode,
+		COFINSRate:               rule.COFINSRate,
+		ExcludeICMSFromPISCOFINS: rule.ExcludeICMSFromPISCOFINS,
+		Enabled:                  rule.Enabled,
+		Version:                  rule.Version,
+		CreatedAt:                rule.CreatedAt,
+		UpdatedAt:                rule.UpdatedAt,
+	}
+}
+
+func MapCreateToTaxRule(ruleDTO *dto.CreateTaxRuleDTO) *model.TaxRule {
+	enabled := true
+	if ruleDTO.Enabled != nil {
+		enabled = *ruleDTO.Enabled
+	}
+	return &model.TaxRule{
+		CompanyGlobalID:          ruleDTO.CompanyGlobalID.Int64(),
+		Description:              ruleDTO.Description,
+		OriginUF:                 ruleDTO.OriginUF,
+		DestinationUF:            ruleDTO.DestinationUF,
+		NCM:                      ruleDTO.NCM,
+		CFOP:                     ruleDTO.CFOP,
+		CustomerRegime:           ruleDTO.CustomerRegime,
+		IssuerRegime:             ruleDTO.IssuerRegime,
+		ICMSCode:                 ruleDTO.ICMSCode,
+		ICMSRate:                 ruleDTO.ICMSRate,
+		ICMSBaseReduction:        ruleDTO.ICMSBaseReduction,
+		SimplesCreditRate:        ruleDTO.SimplesCreditRate,
+		ICMSSTMVA:                ruleDTO.ICMSSTMVA,
+		ICMSSTRate:               ruleDTO.ICMSSTRate,
+		ICMSSTBaseReduction:      ruleDTO.ICMSSTBaseReduction,
+		IPICode:                  ruleDTO.IPICode,
+		IPIRate:                  ruleDTO.IPIRate,
+		PISCode:                  ruleDTO.PISCode,
+		PISRate:                  ruleDTO.PISRate,
+		COFINSCode:               ruleDTO.COFINSCode,
+		COFINSRate:               ruleDTO.COFINSRate,
+		ExcludeICMSFromPISCOFINS: ruleDTO.ExcludeICMSFromPISCOFINS,
+		Enabled:                  enabled,
+	}
+}
+
+// MergeTaxRule aplica o PATCH sobre uma cópia da regra atual, para que a regra resultante seja
+// validada por inteiro antes da gravação.
+func MergeTaxRule(ruleDTO *dto.UpdateTaxRuleDTO, current *model.TaxRule) *model.TaxRule {
+	rule := *current
+	patch := ruleDTO.MergePatch
+	patchPointer(patch, "description", ruleDTO.Description, &rule.Description)
+	patchPointer(patch, "originUf", ruleDTO.OriginUF, &rule.OriginUF)
+	patchPointer(patch, "destinationUf", ruleDTO.DestinationUF, &rule.DestinationUF)
+	patchPointer(patch, "ncm", ruleDTO.NCM, &rule.NCM)
+	patchPointer(patch, "cfop", ruleDTO.CFOP, &rule.CFOP)
+	patchPointer(patch, "customerRegime", ruleDTO.CustomerRegime, &rule.CustomerRegime)
+	patchPointer(patch, "issuerRegime", ruleDTO.IssuerRegime, &rule.IssuerRegime)
+	patchValue(patch, "icmsCode", ruleDTO.ICMSCode, &rule.ICMSCode)
+	patchValue(patch, "icmsRate", ruleDTO.ICMSRate, &rule.ICMSRate)
+	patchValue(patch, "icmsBaseReduction", ruleDTO.ICMSBaseReduction, &rule.ICMSBaseReduction)
+	patchValue(patch, "simplesCreditRate", ruleDTO.SimplesCreditRate, &rule.SimplesCreditRate)
+	patchValue(patch, "icmsStMva", ruleDTO.ICMSSTMVA, &rule.ICMSSTMVA)
+	patchValue(patch, "icmsStRate", ruleDTO.ICMSSTRate, &rule.ICMSSTRate)
+	patchValue(patch, "icmsStBaseReduction", ruleDTO.ICMSSTBaseReduction, &rule.ICMSSTBaseReduction)
+	patchPointer(patch, "ipiCode", ruleDTO.IPICode, &rule.IPICode)
+	patchValue(patch, "ipiRate", ruleDTO.IPIRate, &rule.IPIRate)
+	patchValue(patch, "pisCode", ruleDTO.PISCode, &rule.PISCode)
+	patchValue(patch, "pisRate", ruleDTO.PISRate, &rule.PISRate)
+	patchValue(patch, "cofinsCode", ruleDTO.COFINSCode, &rule.COFINSCode)
+	patchValue(patch, "cofinsRate", ruleDTO.COFINSRate, &rule.COFINSRate)
+	patchValue(patch, "excludeIcmsFromPisCofins", ruleDTO.ExcludeICMSFromPISCOFINS, &rule.ExcludeICMSFromPISCOFINS)
+	patchValue(patch, "enabled", ruleDTO.Enabled, &rule.Enabled)
+	return &rule
+}
+
+func MapUpdateTaxRuleToColumns(ruleDTO *dto.UpdateTaxRuleDTO) map[string]any {
+	columns := make(map[string]any)
+	patch := ruleDTO.MergePatch
+	patchColumn(columns, patch, "description", "description", ruleDTO.Description, nil)
+	patchColumn(columns, patch, "originUf", "origin_uf", ruleDTO.OriginUF, nil)
+	patchColumn(columns, patch, "destinationUf", "destination_uf", ruleDTO.DestinationUF, nil)
+	patchColumn(columns, patch, "ncm", "ncm", ruleDTO.NCM, nil)
+	patchColumn(columns, patch, "cfop", "cfop", ruleDTO.CFOP, nil)
+	patchColumn(columns, patch, "customerRegime", "customer_regime", ruleDTO.CustomerRegime, nil)
+	patchColumn(columns, patch, "issuerRegime", "issuer_regime", ruleDTO.IssuerRegime, nil)
+	patchColumn(columns, patch, "icmsCode", "icms_code", ruleDTO.ICMSCode, nil)
+	patchColumn(columns, patch, "icmsRate", "icms_rate", ruleDTO.ICMSRate, nil)
+	patchColumn(columns, patch, "icmsBaseReduction", "icms_base_reduction", ruleDTO.ICMSBaseReduction, nil)
+	patchColumn(columns, patch, "simplesCreditRate", "simples_credit_rate", ruleDTO.SimplesCreditRate, nil)
+	patchColumn(columns, patch, "icmsStMva", "icms_st_mva", ruleDTO.ICMSSTMVA, nil)
+	patchColumn(columns, patch, "icmsStRate", "icms_st_rate", ruleDTO.ICMSSTRate, nil)
+	patchColumn(columns, patch, "icmsStBaseReduction", "icms_st_base_reduction", ruleDTO.ICMSSTBaseReduction, nil)
+	patchColumn(columns, patch, "ipiCode", "ipi_code", ruleDTO.IPICode, nil)
+	patchColumn(columns, patch, "ipiRate", "ipi_rate", ruleDTO.IPIRate, nil)
+	patchColumn(columns, patch, "pisCode", "pis_code", ruleDTO.PISCode, nil)
+	patchColumn(columns, patch, "pisRate", "pis_rate", ruleDTO.PISRate, nil)
+	patchColumn(columns, patch, "cofinsCode", "cofins_code", ruleDTO.COFINSCode, nil)
+	patchColumn(columns, patch, "cofinsRate", "cofins_rate", ruleDTO.COFINSRate, nil)
+	patchColumn(columns, patch, "excludeIcmsFromPisCofins", "exclude_icms_from_pis_cofins", ruleDTO.ExcludeICMSFromPISCOFINS, nil)
+	patchColumn(columns, patch, "enabled", "enabled", ruleDTO.Enabled, nil)
+	return columns
+}
+
+// MapToTaxRule converte a regra gravada para o pacote tax; critérios nulos casam com qualquer valor.
+func MapToTaxRule(rule *model.TaxRule) tax.Rule {
+	return tax.Rule{
+		ID:                       rule.ID,
+		OriginUF:                 stringOrEmpty(rule.OriginUF),
+		DestinationUF:            stringOrEmpty(rule.DestinationUF),
+		NCM:                      stringOrEmpty(rule.NCM),
+		CFOP:                     stringOrEmpty(rule.CFOP),
+		CustomerRegime:           stringOrEmpty(rule.CustomerRegime),
+		IssuerRegime:             stringOrEmpty(rule.IssuerRegime),
+		ICMSCode:                 rule.ICMSCode,
+		ICMSRate:                 rule.ICMSRate,
+		ICMSBaseReduction:        rule.ICMSBaseReduction,
+		SimplesCreditRate:        rule.SimplesCreditRate,
+		ICMSSTMVA:                rule.ICMSSTMVA,
+		ICMSSTRate:               rule.ICMSSTRate,
+		ICMSSTBaseReduction:      rule.ICMSSTBaseReduction,
+		IPICode:                  stringOrEmpty(rule.IPICode),
+		IPIRate:                  rule.IPIRate,
+		PISCode:                  rule.PISCode,
+		PISRate:                  rule.PISRate,
+		COFINSCode:               rule.COFINSCode,
+		COFINSRate:               rule.COFINSRate,
+		ExcludeICMSFromPISCOFINS: rule.ExcludeICMSFromPISCOFINS,
+	}
+}
+
+func MapToTaxLines(items []dto.TaxPreviewItemDTO) []tax.Line {
+	lines := make([]tax.Line, len(items))
+	for i, item := range items {
+		lines[i] = tax.Line{
+			NCM:        item.NCM,
+			CFOP:       item.CFOP,
+			Quantity:   item.Quantity,
+			UnitPrice:  item.UnitPrice,
+			Discount:   item.Discount,
+			Freight:    item.Freight,
+			Insurance:  item.Insurance,
+			OtherCosts: item.OtherCosts,
+		}
+	}
+	return lines
+}
+
+func MapToTaxPreviewResultDTO(op tax.Operation, result *tax.Result) *dto.TaxPreviewResultDTO {
+	resultDTO := &dto.TaxPreviewResultDTO{
+		OriginUF:       op.OriginUF,
+		DestinationUF:  op.DestinationUF,
+		IssuerRegime:   op.IssuerRegime,
+		CustomerRegime: op.CustomerRegime,
+		Items:          make([]dto.TaxPreviewLineDTO, len(result.Lines)),
+		Totals: dto.TaxTotalsDTO{
+			Products:   result.Totals.Products,
+			Discount:   result.Totals.Discount,
+			Freight:    result.Totals.Freight,
+			Insurance:  result.Totals.Insurance,
+			OtherCosts: result.Totals.OtherCosts,
+			ICMSBase:   result.Totals.ICMSBase,
+			ICMS:       result.Totals.ICMS,
+			ICMSSTBase: result.Totals.ICMSSTBase,
+			ICMSST:     result.Totals.ICMSST,
+			IPI:        result.Totals.IPI,
+			PIS:        result.Totals.PIS,
+			COFINS:     result.Totals.COFINS,
+			Total:      result.Totals.Total,
+		},
+	}
+	for i, line := range result.Lines {
+		lineDTO := dto.TaxPreviewLineDTO{
+			Item:      i + 1,
+			TaxRuleID: util.SnowflakeID(line.RuleID),
+			Value:     line.Value,
+			Base:      line.Base,
+			ICMS:      mapToTaxAmountDTO(line.ICMS),
+			PIS:       mapToTaxAmountDTO(line.PIS),
+			COFINS:    mapToTaxAmountDTO(line.COFINS),
+			Total:     line.Total,
+		}
+		if !line.ICMSST.Base.IsZero() {
+			amount := mapToTaxAmountDTO(line.ICMSST)
+			lineDTO.ICMSST = &amount
+		}
+		if line.SimplesCredit.Code != "" {
+			amount := mapToTaxAmountDTO(line.SimplesCredit)
+			lineDTO.SimplesCredit = &amount
+		}
+		if line.IPI.Code != "" {
+			amount := mapToTaxAmountDTO(line.IPI)
+			lineDTO.IPI = &amount
+		}
+		resultDTO.Items[i] = lineDTO
+	}
+	return resultDTO
+}
+
+func mapToTaxAmountDTO(amount tax.Amount) dto.TaxAmountDTO {
+	return dto.TaxAmountDTO{Code: amount.Code, Base: amount.Base, Rate: amount.Rate, Amount: amount.Amount}
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
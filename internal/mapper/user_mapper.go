@@ -3,6 +3,7 @@ package mapper
 import (
 	"go-sales/internal/dto"
 	"go-sales/internal/model"
+	"go-sales/pkg/util"
 	"time"
 
 	"gorm.io/gorm"
@@ -14,29 +15,32 @@ func MapToUserDTO(user *model.User) *dto.UserDTO {
 	}
 
 	return &dto.UserDTO{
-		ID:              user.ID,
-		Name:            user.Name,
-		Email:           user.Email,
-		EmailRecovery:   user.EmailRecovery,
-		EmailVerified:   user.EmailVerified,
-		EmailVerifiedAt: user.EmailVerifiedAt,
-		Phone:           user.Phone,
-		PhoneVerified:   user.PhoneVerified,
-		PhoneVerifiedAt: user.PhoneVerifiedAt,
-		Password:        nil,
-		Enabled:         user.Enabled,
-		Actived:         user.Actived,
-		ActivationKey:   nil,
-		ActivatedAt:     parseTimePtr(user.ActivatedAt),
-		ResetKey:        nil,
-		ResetRequested:  parseTimePtr(user.ResetRequested),
-		ResetAt:         user.ResetAt,
-		CompanyGlobalID: user.CompanyGlobalID,
-		CompanyGlobal:   *MapToCompanyGlobalDTO(&user.CompanyGlobal),
-		Roles:           *MapToRoleDTOs(user.Roles),
-		CreatedAt:       user.CreatedAt,
-		UpdatedAt:       user.UpdatedAt,
-		DeletedAt:       parseDeletedAt(user.DeletedAt),
+		ID:                  util.SnowflakeID(user.ID),
+		Name:                user.Name,
+		Email:               user.Email,
+		EmailRecovery:       user.EmailRecovery,
+		EmailVerified:       user.EmailVerified,
+		EmailVerifiedAt:     user.EmailVerifiedAt,
+		Phone:               user.Phone,
+		PhoneVerified:       user.PhoneVerified,
+		PhoneVerifiedAt:     user.PhoneVerifiedAt,
+		Password:            nil,
+		Enabled:             user.Enabled,
+		Actived:             user.Actived,
+		ActivationKey:       nil,
+		ActivatedAt:         parseTimePtr(user.ActivatedAt),
+		ResetKey:            nil,
+		ResetRequested:      parseTimePtr(user.ResetRequested),
+		ResetAt:             user.ResetAt,
+		FailedLoginAttempts: user.FailedLoginAttempts,
+		LockedUntil:         user.LockedUntil,
+		CompanyGlobalID:     util.SnowflakeID(user.CompanyGlobalID),
+		CompanyGlobal:       *MapToCompanyGlobalDTO(&user.CompanyGlobal),
+		Roles:               *MapToRoleDTOs(user.Roles),
+		Version:             user.Version,
+		CreatedAt:           user.CreatedAt,
+		UpdatedAt:           user.UpdatedAt,
+		DeletedAt:           parseDeletedAt(user.DeletedAt),
 	}
 }
 
@@ -83,8 +87,21 @@ func MapCreateUserDTOToUser(userDTO *dto.CreateUserDTO, hashedPassword string, c
 		Email:           userDTO.Email,
 		Password:        hashedPassword,
 		Enabled:         false,
-		CompanyGlobalID: userDTO.CompanyGlobalID,
+		CompanyGlobalID: userDTO.CompanyGlobalID.Int64(),
 		CompanyGlobal:   *companyGlobal,
 		Roles:           roles,
 	}
 }
+
+// MapUpdateUserToColumns converte um PATCH nas colunas de users a atualizar.
+// A senha e as roles são tratadas pelo serviço.
+func MapUpdateUserToColumns(userDTO *dto.UpdateUserDTO) map[string]any {
+	columns := make(map[string]any)
+	patch := userDTO.MergePatch
+	patchColumn(columns, patch, "name", "full_name", userDTO.Name, nil)
+	patchColumn(columns, patch, "email", "email_address", userDTO.Email, nil)
+	patchColumn(columns, patch, "emailRecovery", "email_recovery", userDTO.EmailRecovery, "")
+	patchColumn(columns, patch, "phone", "phone_number", userDTO.Phone, "")
+	patchColumn(columns, patch, "enabled", "enabled", userDTO.Enabled, nil)
+	return columns
+}
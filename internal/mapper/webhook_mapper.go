@@ -0,0 +1,85 @@
+package mapper
+
+import (
+	"encoding/json"
+	"go-sales/internal/dto"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+)
+
+func MapToWebhookDTO(endpoint *model.WebhookEndpoint) *dto.WebhookDTO {
+	if endpoint == nil {
+		return nil
+	}
+	return &dto.WebhookDTO{
+		ID:              util.SnowflakeID(endpoint.ID),
+		URL:             endpoint.URL,
+		Description:     endpoint.Description,
+		EventTypes:      endpoint.EventTypes,
+		Enabled:         endpoint.Enabled,
+		CompanyGlobalID: util.SnowflakeID(endpoint.CompanyGlobalID),
+		Version:         endpoint.Version,
+		CreatedAt:       endpoint.CreatedAt,
+		UpdatedAt:       endpoint.UpdatedAt,
+	}
+}
+
+func MapCreateToWebhook(webhookDTO *dto.CreateWebhookDTO, secret string) *model.WebhookEndpoint {
+	return &model.WebhookEndpoint{
+		CompanyGlobalID: webhookDTO.CompanyGlobalID.Int64(),
+		URL:             webhookDTO.URL,
+		Description:     webhookDTO.Description,
+		EventTypes:      webhookDTO.EventTypes,
+		Secret:          secret,
+		Enabled:         true,
+	}
+}
+
+func MapUpdateWebhookToColumns(webhookDTO *dto.UpdateWebhookDTO) map[string]any {
+	columns := make(map[string]any)
+	patch := webhookDTO.MergePatch
+	patchColumn(columns, patch, "url", "url", webhookDTO.URL, nil)
+	patchColumn(columns, patch, "description", "description", webhookDTO.Description, nil)
+	patchColumn(columns, patch, "enabled", "enabled", webhookDTO.Enabled, nil)
+	// Num Updates por map o GORM não aplica o serializer do model, então o JSON é montado aqui.
+	if webhookDTO.EventTypes != nil {
+		eventTypes, _ := json.Marshal(webhookDTO.EventTypes)
+		columns["event_types"] = string(eventTypes)
+	}
+	return columns
+}
+
+func MapToWebhookDeliveryDTO(delivery *model.WebhookDelivery) *dto.WebhookDeliveryDTO {
+	if delivery == nil {
+		return nil
+	}
+	deliveryDTO := &dto.WebhookDeliveryDTO{
+		ID:             util.SnowflakeID(delivery.ID),
+		EndpointID:     util.SnowflakeID(delivery.EndpointID),
+		EventID:        util.SnowflakeID(delivery.EventID),
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		Payload:        delivery.Payload,
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+	}
+	if delivery.ReplayOf != nil {
+		replayOf := util.SnowflakeID(*delivery.ReplayOf)
+		deliveryDTO.ReplayOf = &replayOf
+	}
+	for _, attempt := range delivery.AttemptLog {
+		deliveryDTO.AttemptLog = append(deliveryDTO.AttemptLog, dto.WebhookDeliveryAttemptDTO{
+			Attempt:      attempt.Attempt,
+			StatusCode:   attempt.StatusCode,
+			Error:        attempt.Error,
+			ResponseBody: attempt.ResponseBody,
+			DurationMs:   attempt.DurationMs,
+			CreatedAt:    attempt.CreatedAt,
+		})
+	}
+	return deliveryDTO
+}
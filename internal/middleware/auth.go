@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-sales/internal/auth"
+	"go-sales/internal/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+const bearerPrefix = "Bearer "
+
+// Authenticate identifica quem fez a requisição pelo header Authorization: Bearer <token>. Cada token
+// vai para o primeiro autenticador que o aceita (ex: chaves de API "gsk_..."), e todos produzem o mesmo
+// auth.Principal. Requisições sem Authorization seguem anônimas; RequirePrincipal as recusa.
+//
+// O Principal é guardado no contexto (auth.FromContext), o ID dele alimenta RateLimitByUser, e o tenant
+// vira o X-Company-Global-Id da requisição. Um X-Company-Global-Id de outra empresa resulta em 403.
+func Authenticate(authenticators ...auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			c.Next()
+			return
+		}
+		token, ok := strings.CutPrefix(header, bearerPrefix)
+		if !ok || token == "" {
+			unauthorized(c)
+			return
+		}
+
+		var authenticator auth.Authenticator
+		for _, candidate := range authenticators {
+			if candidate.Accepts(token) {
+				authenticator = candidate
+				break
+			}
+		}
+		if authenticator == nil {
+			unauthorized(c)
+			return
+		}
+
+		principal, err := authenticator.Authenticate(token)
+		if err != nil {
+			if !errors.Is(err, auth.ErrInvalidToken) {
+				log.Error().Err(err).Msg("failed to authenticate bearer token")
+				problem.Write(c, problem.New(http.StatusInternalServerError, "internal_server_error", "internal server error"))
+				return
+			}
+			unauthorized(c)
+			return
+		}
+
+		tenant := strconv.FormatInt(principal.CompanyGlobalID, 10)
+		if requested := c.GetHeader(TenantHeader); requested != "" && requested != tenant {
+			problem.Write(c, problem.New(http.StatusForbidden, "tenant_mismatch", TenantHeader+" does not match the tenant of the credentials").WithArgs(TenantHeader))
+			return
+		}
+		c.Request.Header.Set(TenantHeader, tenant)
+
+		auth.SetPrincipal(c, principal)
+		c.Set(UserIDContextKey, string(principal.Type)+":"+strconv.FormatInt(principal.ID, 10))
+		c.Next()
+	}
+}
+
+// RequirePrincipal recusa com 401 as requisições que Authenticate deixou anônimas.
+func RequirePrincipal() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := auth.FromContext(c); !ok {
+			unauthorized(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequirePermission recusa com 401 as requisições anônimas e com 403 as de um Principal cujas roles
+// não concedem a permissão.
+func RequirePermission(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.FromContext(c)
+		if !ok {
+			unauthorized(c)
+			return
+		}
+		if !principal.HasPermission(name) {
+			problem.Write(c, problem.New(http.StatusForbidden, "permission_denied", "the "+name+" permission is required").WithArgs(name))
+			return
+		}
+		c.Next()
+	}
+}
+
+func unauthorized(c *gin.Context) {
+	c.Header("WWW-Authenticate", `Bearer realm="go-sales"`)
+	problem.Write(c, problem.New(http.StatusUnauthorized, "invalid_token", "missing, invalid or expired bearer token"))
+}
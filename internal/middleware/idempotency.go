@@ -0,0 +1,257 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-sales/internal/auth"
+	"go-sales/internal/database"
+	"go-sales/internal/model"
+	"go-sales/internal/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const (
+	// IdempotencyKeyHeader é o header com a chave escolhida pelo cliente para um POST.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marca as respostas reaproveitadas de uma requisição anterior.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// TenantHeader identifica a empresa (tenant) dona da chave.
+	TenantHeader = "X-Company-Global-Id"
+
+	maxIdempotencyKeyLength = 255
+
+	// idempotencyWaitTimeout é quanto uma requisição concorrente espera a primeira terminar.
+	idempotencyWaitTimeout  = 10 * time.Second
+	idempotencyPollInterval = 100 * time.Millisecond
+)
+
+// idempotencyLockTimeout é o prazo da reserva de uma chave em processamento, renovado a cada terço
+// dele enquanto o handler executa. Se o processo cair no meio da requisição, a chave volta a ficar
+// disponível depois dele.
+var idempotencyLockTimeout = 30 * time.Second
+
+// replayedHeaders são os headers da resposta original devolvidos junto com o corpo no replay.
+var replayedHeaders = []string{"Content-Type", "Content-Language", "Location", "ETag"}
+
+// Idempotency torna os POSTs com o header Idempotency-Key seguros para retentativas.
+// A primeira resposta é gravada por tenant, chave e hash do corpo durante ttl e devolvida nas
+// retentativas. O tenant é o das credenciais; as requisições anônimas ficam no escopo do IP do
+// cliente, nunca no de um header que ele escolhe. Reutilizar a chave com outro corpo resulta em 422, e requisições simultâneas com a
+// mesma chave são serializadas: a segunda espera a primeira terminar e recebe a mesma resposta.
+// Respostas 5xx não são gravadas, permitindo uma nova tentativa com a mesma chave. O corpo é lido
+// inteiro para o hash, então corpos maiores que maxBody são recusados com 413 antes disso.
+func Idempotency(repo database.IdempotencyRepositoryInterface, ttl time.Duration, maxBody int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			problem.Write(c, problem.New(http.StatusBadRequest, "invalid_idempotency_key", "Idempotency-Key must have at most 255 characters"))
+			return
+		}
+
+		var tenantID int64
+		scope := "ip=" + c.ClientIP()
+		if principal, ok := auth.FromContext(c); ok {
+			tenantID, scope = principal.CompanyGlobalID, ""
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBody))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				limit := strconv.FormatInt(maxBody, 10)
+				problem.Write(c, problem.New(http.StatusRequestEntityTooLarge, "payload_too_large", "the request body exceeds "+limit+" bytes").WithArgs(limit))
+				return
+			}
+			problem.Write(c, problem.New(http.StatusBadRequest, "validation_error", "the request payload is invalid"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		requestHash := hashRequest(c.Request.Method, c.Request.URL.Path, body)
+		deadline := time.Now().Add(idempotencyWaitTimeout)
+
+		for {
+			now := time.Now()
+			record := &model.IdempotencyKey{
+				TenantID:    tenantID,
+				Scope:       scope,
+				Key:         key,
+				RequestHash: requestHash,
+				LockToken:   uuid.NewString(),
+				LockedUntil: now.Add(idempotencyLockTimeout),
+				ExpiresAt:   now.Add(ttl),
+			}
+			reserved, err := repo.Reserve(record, now)
+			if err != nil {
+				log.Error().Err(err).Str("idempotency_key", key).Msg("failed to reserve idempotency key")
+				problem.Write(c, problem.New(http.StatusInternalServerError, "database_error", "database error"))
+				return
+			}
+			if reserved {
+				processAndStore(c, repo, record)
+				return
+			}
+
+			existing, err := repo.Find(tenantID, scope, key)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// A chave foi liberada entre o INSERT e a leitura; tenta reservar de novo.
+				continue
+			}
+			if err != nil {
+				log.Error().Err(err).Str("idempotency_key", key).Msg("failed to find idempotency key")
+				problem.Write(c, problem.New(http.StatusInternalServerError, "database_error", "database error"))
+				return
+			}
+
+			if existing.RequestHash != requestHash {
+				problem.Write(c, problem.New(http.StatusUnprocessableEntity, "idempotency_key_reused", "Idempotency-Key was already used with a different request"))
+				return
+			}
+			if existing.Status == model.IdempotencyStatusCompleted {
+				replay(c, existing)
+				return
+			}
+
+			// A primeira requisição ainda está em andamento: espera ela terminar.
+			if time.Now().After(deadline) {
+				problem.Write(c, problem.New(http.StatusConflict, "idempotency_key_in_progress", "a request with this Idempotency-Key is still being processed"))
+				return
+			}
+			select {
+			case <-c.Request.Context().Done():
+				c.Abort()
+				return
+			case <-time.After(idempotencyPollInterval):
+			}
+		}
+	}
+}
+
+// processAndStore executa a requisição que detém a chave, renovando a reserva enquanto ela executa,
+// e grava a resposta para os replays.
+func processAndStore(c *gin.Context, repo database.IdempotencyRepositoryInterface, record *model.IdempotencyKey) {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go extendLock(repo, record, stop, done)
+	stopExtending := func() {
+		close(stop)
+		<-done
+	}
+
+	defer func() {
+		// Em caso de pânico a chave é liberada antes de o GinRecovery responder 500.
+		if r := recover(); r != nil {
+			stopExtending()
+			if err := repo.Release(record); err != nil {
+				log.Error().Err(err).Str("idempotency_key", record.Key).Msg("failed to release idempotency key")
+			}
+			panic(r)
+		}
+	}()
+
+	recorder := &bodyRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+	c.Next()
+	stopExtending()
+
+	status := c.Writer.Status()
+	if status >= http.StatusInternalServerError {
+		if err := repo.Release(record); err != nil {
+			log.Error().Err(err).Str("idempotency_key", record.Key).Msg("failed to release idempotency key")
+		}
+		return
+	}
+
+	headers := make(map[string]string, len(replayedHeaders))
+	for _, name := range replayedHeaders {
+		if value := c.Writer.Header().Get(name); value != "" {
+			headers[name] = value
+		}
+	}
+	record.ResponseHeaders, _ = json.Marshal(headers)
+	record.ResponseStatus = status
+	record.ResponseBody = recorder.body.Bytes()
+	if err := repo.Complete(record); err != nil {
+		log.Error().Err(err).Str("idempotency_key", record.Key).Msg("failed to store idempotent response")
+	}
+}
+
+// extendLock renova a reserva da chave a cada terço do prazo até stop ser fechado. Se a reserva se
+// perder (o banco ficou fora além do prazo), Complete também falha e a resposta não é gravada.
+func extendLock(repo database.IdempotencyRepositoryInterface, record *model.IdempotencyKey, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(idempotencyLockTimeout / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			err := repo.Extend(record, time.Now().Add(idempotencyLockTimeout))
+			if errors.Is(err, database.ErrIdempotencyLockLost) {
+				log.Error().Str("idempotency_key", record.Key).Msg("idempotency key lock lost while the request was running")
+				return
+			}
+			if err != nil {
+				log.Error().Err(err).Str("idempotency_key", record.Key).Msg("failed to extend idempotency key lock")
+			}
+		}
+	}
+}
+
+// replay devolve a resposta gravada pela primeira requisição.
+func replay(c *gin.Context, record *model.IdempotencyKey) {
+	var headers map[string]string
+	if err := json.Unmarshal(record.ResponseHeaders, &headers); err != nil {
+		log.Warn().Err(err).Str("idempotency_key", record.Key).Msg("failed to decode stored response headers")
+	}
+	for name, value := range headers {
+		c.Header(name, value)
+	}
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Status(record.ResponseStatus)
+	if _, err := c.Writer.Write(record.ResponseBody); err != nil {
+		log.Error().Err(err).Str("idempotency_key", record.Key).Msg("failed to write idempotent response")
+	}
+	c.Abort()
+}
+
+// hashRequest identifica a requisição pelo método, caminho e corpo.
+func hashRequest(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// bodyRecorder copia o corpo da resposta enquanto ele é escrito para o cliente.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
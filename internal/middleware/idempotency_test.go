@@ -0,0 +1,228 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-sales/internal/database"
+	"go-sales/internal/model"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// fakeIdempotencyRepository guarda as chaves em memória e confere o LockToken como o repositório real.
+type fakeIdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]model.IdempotencyKey
+	extends int
+}
+
+func newFakeIdempotencyRepository() *fakeIdempotencyRepository {
+	return &fakeIdempotencyRepository{records: map[string]model.IdempotencyKey{}}
+}
+
+func idempotencyID(tenantID int64, scope, key string) string {
+	return strconv.FormatInt(tenantID, 10) + "|" + scope + "|" + key
+}
+
+func (r *fakeIdempotencyRepository) Reserve(record *model.IdempotencyKey, now time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := idempotencyID(record.TenantID, record.Scope, record.Key)
+	existing, ok := r.records[id]
+	if ok && !existing.ExpiresAt.Before(now) && !(existing.Status == model.IdempotencyStatusProcessing && existing.LockedUntil.Before(now)) {
+		return false, nil
+	}
+	record.Status = model.IdempotencyStatusProcessing
+	r.records[id] = *record
+	return true, nil
+}
+
+func (r *fakeIdempotencyRepository) Find(tenantID int64, scope string, key string) (*model.IdempotencyKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, ok := r.records[idempotencyID(tenantID, scope, key)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &record, nil
+}
+
+// held devolve a chave do record se ela ainda está reservada por ele.
+func (r *fakeIdempotencyRepository) held(record *model.IdempotencyKey) (string, bool) {
+	id := idempotencyID(record.TenantID, record.Scope, record.Key)
+	existing, ok := r.records[id]
+	return id, ok && existing.LockToken == record.LockToken && existing.Status == model.IdempotencyStatusProcessing
+}
+
+func (r *fakeIdempotencyRepository) Extend(record *model.IdempotencyKey, lockedUntil time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id, ok := r.held(record)
+	if !ok {
+		return database.ErrIdempotencyLockLost
+	}
+	existing := r.records[id]
+	existing.LockedUntil = lockedUntil
+	r.records[id] = existing
+	r.extends++
+	return nil
+}
+
+func (r *fakeIdempotencyRepository) Complete(record *model.IdempotencyKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id, ok := r.held(record)
+	if !ok {
+		return database.ErrIdempotencyLockLost
+	}
+	existing := r.records[id]
+	existing.Status = model.IdempotencyStatusCompleted
+	existing.ResponseStatus, existing.ResponseHeaders, existing.ResponseBody = record.ResponseStatus, record.ResponseHeaders, record.ResponseBody
+	r.records[id] = existing
+	return nil
+}
+
+func (r *fakeIdempotencyRepository) Release(record *model.IdempotencyKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id, ok := r.held(record); ok {
+		delete(r.records, id)
+	}
+	return nil
+}
+
+func (r *fakeIdempotencyRepository) DeleteExpired(now time.Time) (int64, error) {
+	return 0, nil
+}
+
+// idempotentServer sobe o middleware na frente de um handler que conta as execuções.
+func idempotentServer(repo database.IdempotencyRepositoryInterface, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	server := gin.New()
+	server.Use(Idempotency(repo, time.Hour, 1<<20))
+	server.POST("/orders", handler)
+	return server
+}
+
+func postOrder(server *gin.Engine, remoteAddr string, tenant string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"total":10}`))
+	req.RemoteAddr = remoteAddr
+	req.Header.Set(IdempotencyKeyHeader, "order-1")
+	if tenant != "" {
+		req.Header.Set(TenantHeader, tenant)
+	}
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	return w
+}
+
+// Requisições anônimas não escolhem o escopo da chave: o X-Company-Global-Id é ignorado e a mesma
+// chave vinda de outro IP é outra chave.
+func TestIdempotencyScopesAnonymousRequestsByClientIP(t *testing.T) {
+	repo := newFakeIdempotencyRepository()
+	var mu sync.Mutex
+	runs := 0
+	server := idempotentServer(repo, func(c *gin.Context) {
+		mu.Lock()
+		runs++
+		run := runs
+		mu.Unlock()
+		c.String(http.StatusCreated, "run %d", run)
+	})
+
+	first := postOrder(server, "192.0.2.10:4000", "1963596246084001793")
+	replayed := postOrder(server, "192.0.2.10:4001", "1963596246084001799")
+	other := postOrder(server, "198.51.100.20:4000", "1963596246084001793")
+
+	if first.Body.String() != "run 1" {
+		t.Fatalf("first response = %q", first.Body.String())
+	}
+	if replayed.Body.String() != "run 1" || replayed.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatalf("same client with another tenant header = %q, want the replay of run 1", replayed.Body.String())
+	}
+	if other.Body.String() != "run 2" || other.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Fatalf("another client with the same tenant header = %q, want its own run", other.Body.String())
+	}
+	if _, err := repo.Find(0, "ip=192.0.2.10", "order-1"); err != nil {
+		t.Fatalf("key not stored in the client IP scope: %v", err)
+	}
+}
+
+// Um handler mais lento que o prazo da reserva mantém a chave: a retentativa concorrente espera e
+// recebe a mesma resposta em vez de executar o handler de novo.
+func TestIdempotencyExtendsTheLockWhileTheHandlerRuns(t *testing.T) {
+	defer func(timeout time.Duration) { idempotencyLockTimeout = timeout }(idempotencyLockTimeout)
+	idempotencyLockTimeout = 30 * time.Millisecond
+
+	repo := newFakeIdempotencyRepository()
+	started := make(chan struct{})
+	var mu sync.Mutex
+	runs := 0
+	server := idempotentServer(repo, func(c *gin.Context) {
+		mu.Lock()
+		runs++
+		mu.Unlock()
+		close(started)
+		time.Sleep(10 * idempotencyLockTimeout)
+		c.String(http.StatusCreated, "created")
+	})
+
+	var first *httptest.ResponseRecorder
+	done := make(chan struct{})
+	go func() {
+		first = postOrder(server, "192.0.2.10:4000", "")
+		close(done)
+	}()
+	<-started
+	time.Sleep(3 * idempotencyLockTimeout)
+	retry := postOrder(server, "192.0.2.10:4001", "")
+	<-done
+
+	if runs != 1 {
+		t.Fatalf("handler ran %d times, want 1", runs)
+	}
+	if first.Code != http.StatusCreated || retry.Code != http.StatusCreated || retry.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatalf("first = %d, retry = %d (replayed %q), want the retry to replay the first response",
+			first.Code, retry.Code, retry.Header().Get(IdempotentReplayedHeader))
+	}
+	repo.mu.Lock()
+	extends := repo.extends
+	repo.mu.Unlock()
+	if extends == 0 {
+		t.Fatal("the lock was never extended")
+	}
+}
+
+// A requisição que perdeu a reserva não sobrescreve a resposta de quem tomou a chave.
+func TestIdempotencyCompleteRequiresTheLock(t *testing.T) {
+	repo := newFakeIdempotencyRepository()
+	var record *model.IdempotencyKey
+	server := idempotentServer(repo, func(c *gin.Context) {
+		// Outra requisição toma a chave enquanto esta executa.
+		stored, _ := repo.Find(0, "ip=192.0.2.10", "order-1")
+		record = stored
+		taken := *stored
+		taken.LockToken = "another-request"
+		taken.ResponseStatus, taken.ResponseBody = http.StatusCreated, []byte("theirs")
+		repo.mu.Lock()
+		repo.records[idempotencyID(0, "ip=192.0.2.10", "order-1")] = taken
+		repo.mu.Unlock()
+		_ = repo.Complete(&taken)
+		c.String(http.StatusCreated, "ours")
+	})
+
+	if w := postOrder(server, "192.0.2.10:4000", ""); w.Body.String() != "ours" {
+		t.Fatalf("response = %q", w.Body.String())
+	}
+	stored, _ := repo.Find(0, "ip=192.0.2.10", "order-1")
+	if string(stored.ResponseBody) != "theirs" || stored.LockToken == record.LockToken {
+		t.Fatalf("stored response = %q, want the one of the request holding the key", stored.ResponseBody)
+	}
+}
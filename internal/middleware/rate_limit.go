@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-sales/internal/problem"
+	"go-sales/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// RateLimitLimitHeader é a capacidade do bucket do cliente.
+	RateLimitLimitHeader = "RateLimit-Limit"
+	// RateLimitRemainingHeader é quantas requisições o cliente ainda pode fazer agora.
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	// RateLimitResetHeader é em quantos segundos o bucket do cliente enche de novo.
+	RateLimitResetHeader = "RateLimit-Reset"
+	// RateLimitPolicyHeader descreve a política aplicada (ex: "300;w=60").
+	RateLimitPolicyHeader = "RateLimit-Policy"
+
+	// UserIDContextKey é a chave do contexto com a identidade autenticada (ex: "service_account:123"),
+	// preenchida por Authenticate e usada por RateLimitByUser. Fica vazia nas requisições anônimas.
+	UserIDContextKey = "userID"
+)
+
+// RateLimitKeyFunc extrai do contexto a chave do bucket de uma dimensão (IP, tenant, usuário).
+// Uma chave vazia indica que a dimensão não se aplica à requisição.
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// RateLimitByIP separa os buckets pelo IP do cliente (c.ClientIP, que respeita os proxies confiáveis).
+func RateLimitByIP(c *gin.Context) string {
+	return "ip=" + c.ClientIP()
+}
+
+// RateLimitByTenant separa os buckets pela empresa do header X-Company-Global-Id. Só vale para
+// requisições autenticadas, em que Authenticate já trocou o header pelo tenant das credenciais; nas
+// anônimas o header é do cliente e um valor novo a cada requisição daria um bucket novo.
+func RateLimitByTenant(c *gin.Context) string {
+	if c.GetString(UserIDContextKey) == "" {
+		return ""
+	}
+	return "tenant=" + c.GetHeader(TenantHeader)
+}
+
+// RateLimitByUser separa os buckets pelo usuário ou conta de serviço autenticada.
+func RateLimitByUser(c *gin.Context) string {
+	userID := c.GetString(UserIDContextKey)
+	if userID == "" {
+		return ""
+	}
+	return "user=" + userID
+}
+
+// RateLimit limita as requisições do grupo de rotas com token buckets. name identifica o grupo, para
+// que grupos com políticas diferentes não dividam o mesmo bucket, e cada key é uma dimensão com o
+// seu próprio bucket (ex: IP, tenant e usuário). A requisição consome um token de cada bucket e é
+// recusada se qualquer um deles estiver vazio. Sem keys, ou se nenhuma se aplicar, o bucket é por IP.
+//
+// Toda resposta leva os headers RateLimit-* do bucket mais restrito; ao estourar o limite a resposta
+// é 429 com Retry-After. Se o store falhar a requisição passa: o rate limit não derruba a API junto
+// com o banco.
+func RateLimit(store ratelimit.Store, name string, limit ratelimit.Limit, keys ...RateLimitKeyFunc) gin.HandlerFunc {
+	if !limit.Valid() {
+		return func(c *gin.Context) { c.Next() }
+	}
+	if len(keys) == 0 {
+		keys = []RateLimitKeyFunc{RateLimitByIP}
+	}
+	policy := strconv.Itoa(limit.Requests) + ";w=" + strconv.Itoa(int(limit.Per.Seconds()))
+
+	return func(c *gin.Context) {
+		bucketKeys := make([]string, 0, len(keys))
+		for _, key := range keys {
+			if part := key(c); part != "" {
+				bucketKeys = append(bucketKeys, name+"|"+part)
+			}
+		}
+		if len(bucketKeys) == 0 {
+			bucketKeys = append(bucketKeys, name+"|"+RateLimitByIP(c))
+		}
+
+		var res ratelimit.Result
+		for i, bucketKey := range bucketKeys {
+			taken, err := store.Take(bucketKey, limit)
+			if err != nil {
+				log.Error().Err(err).Str("bucket", bucketKey).Msg("rate limit store failed, letting the request through")
+				c.Next()
+				return
+			}
+			if i == 0 || restricts(taken, res) {
+				res = taken
+			}
+		}
+
+		c.Header(RateLimitPolicyHeader, policy)
+		c.Header(RateLimitLimitHeader, strconv.Itoa(res.Limit))
+		c.Header(RateLimitRemainingHeader, strconv.Itoa(res.Remaining))
+		c.Header(RateLimitResetHeader, ceilSeconds(res.Reset))
+		if !res.Allowed {
+			retryAfter := ceilSeconds(res.RetryAfter)
+			c.Header("Retry-After", retryAfter)
+			problem.Write(c, problem.New(http.StatusTooManyRequests, "rate_limited", "too many requests, retry in "+retryAfter+" seconds").WithArgs(retryAfter))
+			return
+		}
+		c.Next()
+	}
+}
+
+// restricts indica se o resultado a é mais restrito que b: recusado antes de permitido, depois a
+// maior espera ou o menor saldo.
+func restricts(a, b ratelimit.Result) bool {
+	if a.Allowed != b.Allowed {
+		return !a.Allowed
+	}
+	if !a.Allowed {
+		return a.RetryAfter > b.RetryAfter
+	}
+	return a.Remaining < b.Remaining
+}
+
+// ceilSeconds arredonda a duração para cima em segundos inteiros, como pedem os headers.
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
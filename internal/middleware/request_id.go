@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"go-sales/internal/problem"
+	"go-sales/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRequestIDLength limita o tamanho de IDs recebidos do cliente para evitar abuso nos logs.
+const maxRequestIDLength = 128
+
+// RequestID garante que toda requisição tenha um ID de correlação.
+// Reaproveita o header X-Request-ID enviado pelo cliente ou gera um UUID novo,
+// e o devolve no header da resposta.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(problem.RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = util.New().String()
+		}
+		c.Header(problem.RequestIDHeader, requestID)
+		c.Next()
+	}
+}
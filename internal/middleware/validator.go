@@ -1,7 +1,10 @@
 package middleware
 
 import (
+	"encoding/json"
 	"errors"
+	"go-sales/internal/i18n"
+	"go-sales/internal/problem"
 	"go-sales/pkg/util"
 	"net/http"
 
@@ -18,34 +21,7 @@ func ValidateDTO(dtoType reflect.Type) gin.HandlerFunc {
 		dto := reflect.New(dtoType).Interface()
 
 		if err := c.ShouldBindJSON(dto); err != nil {
-			var ve validator.ValidationErrors
-			if errors.As(err, &ve) {
-				out := make([]map[string]string, len(ve))
-				typ := dtoType
-				if typ.Kind() == reflect.Ptr {
-					typ = typ.Elem()
-				}
-				for i, fe := range ve {
-					field, ok := typ.FieldByName(fe.StructField())
-					jsonTag := ""
-					if ok {
-						jsonTag = field.Tag.Get("json")
-					}
-					jsonName := strings.Split(jsonTag, ",")[0]
-					if jsonName == "" {
-						jsonName = fe.Field()
-					}
-					out[i] = map[string]string{
-						"field":   jsonName,
-						"message": fe.Error(),
-					}
-				}
-				c.JSON(http.StatusBadRequest, gin.H{"errors": out, "code": "validation_error"})
-				c.Abort()
-				return
-			}
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "validation_error"})
-			c.Abort()
+			problem.Write(c, validationProblem(c, err))
 			return
 		}
 		c.Set("validatedDTO", dto)
@@ -53,13 +29,61 @@ func ValidateDTO(dtoType reflect.Type) gin.HandlerFunc {
 	}
 }
 
+// validationProblem converte erros de bind/validação em um problema com a lista de campos violados.
+// As mensagens de cada campo são traduzidas para o locale negociado na requisição.
+func validationProblem(c *gin.Context, err error) *problem.Details {
+	p := problem.New(http.StatusBadRequest, "validation_error", "the request payload is invalid")
+	locale := i18n.FromContext(c)
+
+	var ve validator.ValidationErrors
+	if errors.As(err, &ve) {
+		trans := i18n.Translator(locale)
+		out := make([]problem.FieldError, len(ve))
+		for i, fe := range ve {
+			out[i] = problem.FieldError{
+				Field:   fieldPath(fe),
+				Message: fe.Translate(trans),
+				Rule:    fe.Tag(),
+			}
+		}
+		return p.WithErrors(out)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		expected := typeErr.Type.String()
+		if typeErr.Type == reflect.TypeOf(util.SnowflakeID(0)) {
+			expected = "snowflake ID"
+		}
+		fallback := "expected " + expected + " but got " + typeErr.Value
+		return p.WithErrors([]problem.FieldError{{
+			Field:   typeErr.Field,
+			Message: i18n.Message(locale, "invalid_type", fallback, expected, typeErr.Value),
+			Rule:    "type",
+		}})
+	}
+
+	p.Detail = err.Error()
+	return p
+}
+
+// fieldPath devolve o caminho JSON do campo, incluindo índices de slices validados com "dive".
+// O Namespace do validador começa com o nome da struct raiz (ex: "CreateCompanyGlobalDTO.contacts[1].email"),
+// e os demais segmentos já usam os nomes da tag json (ver validator.InitCustomValidator).
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if idx := strings.Index(ns, "."); idx >= 0 {
+		return ns[idx+1:]
+	}
+	return fe.Field()
+}
+
 // ValidateUUID verifica se um parâmetro da URL é um UUID válido.
 func ValidateUUID(paramName string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		idStr := c.Param(paramName)
 		if _, err := util.Parse(idStr); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + paramName + " format. Must be a valid UUID.", "code": "invalid_id_format"})
-			c.Abort()
+			problem.Write(c, problem.New(http.StatusBadRequest, "invalid_id_format", "Invalid "+paramName+" format. Must be a valid UUID.").WithArgs(paramName, "UUID"))
 			return
 		}
 		c.Next()
@@ -71,11 +95,7 @@ func ValidateSnowflake(paramName string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		idStr := c.Param(paramName)
 		if _, err := util.ParseSnowflake(idStr); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Invalid " + paramName + " format. Must be a valid Snowflake ID.",
-				"code":  "invalid_id_format",
-			})
-			c.Abort()
+			problem.Write(c, problem.New(http.StatusBadRequest, "invalid_id_format", "Invalid "+paramName+" format. Must be a valid Snowflake ID.").WithArgs(paramName, "Snowflake ID"))
 			return
 		}
 		c.Next()
@@ -86,11 +106,7 @@ func ValidateID(paramName string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		idStr := c.Param(paramName)
 		if _, err := util.ParseSnowflake(idStr); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Invalid " + paramName + " format. Must be a valid Snowflake ID.",
-				"code":  "invalid_id_format",
-			})
-			c.Abort()
+			problem.Write(c, problem.New(http.StatusBadRequest, "invalid_id_format", "Invalid "+paramName+" format. Must be a valid Snowflake ID.").WithArgs(paramName, "Snowflake ID"))
 			return
 		}
 		c.Next()
@@ -101,8 +117,7 @@ func ValidateCGC(paramName string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		cgcStr := c.Param(paramName)
 		if !util.IsValidCGC(cgcStr) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + paramName + " format. Must be a valid CGC.", "code": "invalid_cgc_format"})
-			c.Abort()
+			problem.Write(c, problem.New(http.StatusBadRequest, "invalid_cgc_format", "Invalid "+paramName+" format. Must be a valid CGC.").WithArgs(paramName))
 			return
 		}
 		c.Next()
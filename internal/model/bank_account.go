@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BankAccount é uma conta bancária de uma empresa. Os campos de cobrança (carteira, variação e
+// convênio) só são exigidos para emitir boletos.
+type BankAccount struct {
+	ID              int64   `gorm:"column:id;type:bigint;primaryKey"`
+	CompanyGlobalID int64   `gorm:"column:company_global_id;type:bigint;not null"`
+	BankCode        string  `gorm:"column:bank_code;type:varchar(3);not null"`
+	Agency          string  `gorm:"column:agency;type:varchar(5);not null"`
+	AgencyDigit     *string `gorm:"column:agency_digit;type:varchar(1)"`
+	AccountNumber   string  `gorm:"column:account_number;type:varchar(12);not null"`
+	AccountDigit    *string `gorm:"column:account_digit;type:varchar(1)"`
+	Description     *string `gorm:"column:description;type:varchar(255)"`
+	Wallet          *string `gorm:"column:wallet;type:varchar(3)"`
+	WalletVariation *string `gorm:"column:wallet_variation;type:varchar(3)"`
+	Agreement       *string `gorm:"column:agreement;type:varchar(20)"`
+
+	// NextOurNumber é o próximo nosso número dos boletos da conta.
+	NextOurNumber int64 `gorm:"column:next_our_number;type:bigint;not null;default:1"`
+	// RemittanceSequence é o número do último arquivo de remessa gerado (NSA).
+	RemittanceSequence int `gorm:"column:remittance_sequence;type:integer;not null;default:0"`
+
+	// Version é incrementada a cada escrita e exposta como ETag (controle de concorrência otimista).
+	Version int64 `gorm:"column:version;type:bigint;not null;default:1"`
+
+	CreatedAt time.Time      `gorm:"column:created_at;type:timestamptz"`
+	UpdatedAt time.Time      `gorm:"column:updated_at;type:timestamptz"`
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;type:timestamptz"`
+}
+
+func (BankAccount) TableName() string {
+	return "bank_accounts"
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"time"
+
+	"go-sales/pkg/decimal"
+)
+
+// Estados de um lançamento do extrato. Créditos nascem unmatched ou suggested (com a parcela sugerida
+// pela conciliação automática); o usuário os confirma (confirmed) ou rejeita a sugestão (rejected).
+// Débitos nascem ignored: não há parcela a receber para eles.
+const (
+	StatementLineUnmatched = "unmatched"
+	StatementLineSuggested = "suggested"
+	StatementLineConfirmed = "confirmed"
+	StatementLineRejected  = "rejected"
+	StatementLineIgnored   = "ignored"
+)
+
+// BankStatementImport é um extrato importado para uma conta bancária.
+type BankStatementImport struct {
+	ID              int64  `gorm:"column:id;type:bigint;primaryKey"`
+	CompanyGlobalID int64  `gorm:"column:company_global_id;type:bigint;not null"`
+	BankAccountID   int64  `gorm:"column:bank_account_id;type:bigint;not null"`
+	Format          string `gorm:"column:format;type:varchar(10);not null"`
+	FileName        string `gorm:"column:file_name;type:varchar(255);not null"`
+	LineCount       int    `gorm:"column:line_count;type:integer;not null"`
+	DuplicateCount  int    `gorm:"column:duplicate_count;type:integer;not null"`
+	SuggestedCount  int    `gorm:"column:suggested_count;type:integer;not null"`
+
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz"`
+}
+
+func (BankStatementImport) TableName() string {
+	return "bank_statement_imports"
+}
+
+// BankStatementLine é um lançamento de um extrato importado.
+type BankStatementLine struct {
+	ID              int64           `gorm:"column:id;type:bigint;primaryKey"`
+	CompanyGlobalID int64           `gorm:"column:company_global_id;type:bigint;not null"`
+	BankAccountID   int64           `gorm:"column:bank_account_id;type:bigint;not null"`
+	ImportID        int64           `gorm:"column:import_id;type:bigint;not null"`
+	ExternalID      string          `gorm:"column:external_id;type:varchar(255);not null"`
+	PostedAt        time.Time       `gorm:"column:posted_at;type:date;not null"`
+	Amount          decimal.Decimal `gorm:"column:amount;type:numeric(15,2);not null"`
+	Description     *string         `gorm:"column:description;type:varchar(255)"`
+	DocumentNumber  *string         `gorm:"column:document_number;type:varchar(60)"`
+	Status          string          `gorm:"column:status;type:varchar(20);not null"`
+
+	// InstallmentID é a parcela sugerida ou conciliada; Confidence (0 a 100) e MatchReasons explicam a
+	// sugestão automática e ficam vazios quando o usuário vincula a parcela manualmente.
+	InstallmentID *int64     `gorm:"column:installment_id;type:bigint"`
+	Confidence    *int       `gorm:"column:confidence;type:integer"`
+	MatchReasons  []string   `gorm:"column:match_reasons;type:jsonb;serializer:json"`
+	ReconciledAt  *time.Time `gorm:"column:reconciled_at;type:timestamptz"`
+
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamptz"`
+}
+
+func (BankStatementLine) TableName() string {
+	return "bank_statement_lines"
+}
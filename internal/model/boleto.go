@@ -0,0 +1,116 @@
+package model
+
+import (
+	"time"
+
+	"go-sales/pkg/decimal"
+)
+
+// Estados de um boleto. Um boleto nasce pending, vai para sent quando entra numa remessa e o retorno
+// do banco o leva a registered, rejected, paid ou written_off (baixado sem pagamento).
+const (
+	BoletoPending    = "pending"
+	BoletoSent       = "sent"
+	BoletoRegistered = "registered"
+	BoletoRejected   = "rejected"
+	BoletoPaid       = "paid"
+	BoletoWrittenOff = "written_off"
+)
+
+// Resultados do processamento de cada linha de um arquivo de retorno. unmatched, amount_mismatch,
+// installment_not_open e invalid formam o relatório de conciliação.
+const (
+	BoletoReturnSettled            = "settled"
+	BoletoReturnRegistered         = "registered"
+	BoletoReturnRejected           = "rejected"
+	BoletoReturnWrittenOff         = "written_off"
+	BoletoReturnAlreadyProcessed   = "already_processed"
+	BoletoReturnIgnored            = "ignored"
+	BoletoReturnUnmatched          = "unmatched"
+	BoletoReturnAmountMismatch     = "amount_mismatch"
+	BoletoReturnInstallmentNotOpen = "installment_not_open"
+	BoletoReturnInvalid            = "invalid"
+)
+
+// Boleto é um boleto de cobrança de uma parcela.
+type Boleto struct {
+	ID              int64           `gorm:"column:id;type:bigint;primaryKey"`
+	CompanyGlobalID int64           `gorm:"column:company_global_id;type:bigint;not null"`
+	BankAccountID   int64           `gorm:"column:bank_account_id;type:bigint;not null"`
+	InstallmentID   int64           `gorm:"column:installment_id;type:bigint;not null"`
+	OurNumber       int64           `gorm:"column:our_number;type:bigint;not null"`
+	Amount          decimal.Decimal `gorm:"column:amount;type:numeric(15,2);not null"`
+	DueDate         time.Time       `gorm:"column:due_date;type:date;not null"`
+	Barcode         string          `gorm:"column:barcode;type:varchar(44);not null"`
+	DigitableLine   string          `gorm:"column:digitable_line;type:varchar(60);not null"`
+	Status          string          `gorm:"column:status;type:varchar(20);not null;default:pending"`
+	RemittanceID    *int64          `gorm:"column:remittance_id;type:bigint"`
+
+	PaidAmount *decimal.Decimal `gorm:"column:paid_amount;type:numeric(15,2)"`
+	PaidAt     *time.Time       `gorm:"column:paid_at;type:timestamptz"`
+	CreditedAt *time.Time       `gorm:"column:credited_at;type:date"`
+
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamptz"`
+}
+
+func (Boleto) TableName() string {
+	return "boletos"
+}
+
+// BoletoRemittance é um arquivo de remessa gerado para uma conta.
+type BoletoRemittance struct {
+	ID              int64           `gorm:"column:id;type:bigint;primaryKey"`
+	CompanyGlobalID int64           `gorm:"column:company_global_id;type:bigint;not null"`
+	BankAccountID   int64           `gorm:"column:bank_account_id;type:bigint;not null"`
+	Layout          string          `gorm:"column:layout;type:varchar(10);not null"`
+	Sequence        int             `gorm:"column:sequence;type:integer;not null"`
+	FileName        string          `gorm:"column:file_name;type:varchar(100);not null"`
+	Content         string          `gorm:"column:content;type:text;not null"`
+	TitleCount      int             `gorm:"column:title_count;type:integer;not null"`
+	TotalAmount     decimal.Decimal `gorm:"column:total_amount;type:numeric(15,2);not null"`
+
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz"`
+}
+
+func (BoletoRemittance) TableName() string {
+	return "boleto_remittances"
+}
+
+// BoletoReturn é um arquivo de retorno processado, com o resultado de cada linha.
+type BoletoReturn struct {
+	ID              int64  `gorm:"column:id;type:bigint;primaryKey"`
+	CompanyGlobalID int64  `gorm:"column:company_global_id;type:bigint;not null"`
+	BankAccountID   int64  `gorm:"column:bank_account_id;type:bigint;not null"`
+	Layout          string `gorm:"column:layout;type:varchar(10);not null"`
+	FileName        string `gorm:"column:file_name;type:varchar(255);not null"`
+
+	Lines []BoletoReturnLine `gorm:"foreignKey:ReturnID"`
+
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz"`
+}
+
+func (BoletoReturn) TableName() string {
+	return "boleto_returns"
+}
+
+// BoletoReturnLine é o resultado de um movimento do arquivo de retorno.
+type BoletoReturnLine struct {
+	ID             int64            `gorm:"column:id;type:bigint;primaryKey"`
+	ReturnID       int64            `gorm:"column:return_id;type:bigint;not null"`
+	LineNumber     int              `gorm:"column:line_number;type:integer;not null"`
+	OurNumber      *int64           `gorm:"column:our_number;type:bigint"`
+	DocumentNumber *string          `gorm:"column:document_number;type:varchar(25)"`
+	BoletoID       *int64           `gorm:"column:boleto_id;type:bigint"`
+	InstallmentID  *int64           `gorm:"column:installment_id;type:bigint"`
+	Occurrence     string           `gorm:"column:occurrence;type:varchar(2);not null"`
+	Amount         *decimal.Decimal `gorm:"column:amount;type:numeric(15,2)"`
+	PaidAmount     *decimal.Decimal `gorm:"column:paid_amount;type:numeric(15,2)"`
+	CreditDate     *time.Time       `gorm:"column:credit_date;type:date"`
+	Result         string           `gorm:"column:result;type:varchar(30);not null"`
+	Message        *string          `gorm:"column:message;type:varchar(255)"`
+}
+
+func (BoletoReturnLine) TableName() string {
+	return "boleto_return_lines"
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"time"
+
+	"go-sales/pkg/decimal"
+
+	"gorm.io/gorm"
+)
+
+// Tipos de lançamento de comissão.
+const (
+	CommissionSale     = "sale"
+	CommissionReversal = "reversal"
+)
+
+// Motivos de estorno de uma comissão.
+const (
+	CommissionReasonCancelled = "cancelled"
+	CommissionReasonReturned  = "returned"
+)
+
+// Situações do extrato de comissão de um vendedor num mês.
+const (
+	CommissionStatementOpen     = "open"
+	CommissionStatementApproved = "approved"
+)
+
+// CommissionPlan é um plano de comissão de uma empresa (ver o pacote sales/commission).
+type CommissionPlan struct {
+	ID              int64            `gorm:"column:id;type:bigint;primaryKey"`
+	CompanyGlobalID int64            `gorm:"column:company_global_id;type:bigint;not null"`
+	Name            string           `gorm:"column:name;type:varchar(100);not null"`
+	Kind            string           `gorm:"column:kind;type:varchar(20);not null"`
+	DefaultRate     decimal.Decimal  `gorm:"column:default_rate;type:numeric(7,4);not null"`
+	Rules           []CommissionRule `gorm:"column:rules;type:jsonb;serializer:json;not null"`
+
+	// Version é incrementada a cada escrita e exposta como ETag (controle de concorrência otimista).
+	Version int64 `gorm:"column:version;type:bigint;not null;default:1"`
+
+	CreatedAt time.Time      `gorm:"column:created_at;type:timestamptz"`
+	UpdatedAt time.Time      `gorm:"column:updated_at;type:timestamptz"`
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;type:timestamptz"`
+}
+
+func (CommissionPlan) TableName() string {
+	return "commission_plans"
+}
+
+// CommissionRule é uma faixa de um plano; só o critério do tipo do plano é preenchido.
+type CommissionRule struct {
+	Category    *string          `json:"category,omitempty"`
+	MaxDiscount *decimal.Decimal `json:"maxDiscount,omitempty"`
+	MinVolume   *decimal.Decimal `json:"minVolume,omitempty"`
+	Rate        decimal.Decimal  `json:"rate"`
+}
+
+// CommissionPlanAssignment é a vigência de um plano para um usuário (UserID) ou para os usuários de
+// uma role (RoleID).
+type CommissionPlanAssignment struct {
+	ID              int64      `gorm:"column:id;type:bigint;primaryKey"`
+	CompanyGlobalID int64      `gorm:"column:company_global_id;type:bigint;not null"`
+	PlanID          int64      `gorm:"column:plan_id;type:bigint;not null"`
+	UserID          *int64     `gorm:"column:user_id;type:bigint"`
+	RoleID          *int64     `gorm:"column:role_id;type:bigint"`
+	ValidFrom       time.Time  `gorm:"column:valid_from;type:date;not null"`
+	ValidTo         *time.Time `gorm:"column:valid_to;type:date"`
+
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz"`
+}
+
+func (CommissionPlanAssignment) TableName() string {
+	return "commission_plan_assignments"
+}
+
+// CommissionEntry é a comissão de uma venda faturada (sale) ou o estorno dela (reversal, com valores
+// negativos). Period é o primeiro dia do mês em que o lançamento entra no extrato do vendedor.
+type CommissionEntry struct {
+	ID              int64           `gorm:"column:id;type:bigint;primaryKey"`
+	CompanyGlobalID int64           `gorm:"column:company_global_id;type:bigint;not null"`
+	UserID          int64           `gorm:"column:user_id;type:bigint;not null"`
+	PlanID          *int64          `gorm:"column:plan_id;type:bigint"`
+	Kind            string          `gorm:"column:kind;type:varchar(10);not null"`
+	DocumentNumber  string          `gorm:"column:document_number;type:varchar(60);not null"`
+	DocumentDate    time.Time       `gorm:"column:document_date;type:date;not null"`
+	Period          time.Time       `gorm:"column:period;type:date;not null"`
+	BaseAmount      decimal.Decimal `gorm:"column:base_amount;type:numeric(15,2);not null"`
+	Amount          decimal.Decimal `gorm:"column:amount;type:numeric(15,2);not null"`
+	// Lines é o cálculo de cada item da venda; vazio nos estornos.
+	Lines           []CommissionLine `gorm:"column:lines;type:jsonb;serializer:json"`
+	ReversedEntryID *int64           `gorm:"column:reversed_entry_id;type:bigint"`
+	Reason          *string          `gorm:"column:reason;type:varchar(20)"`
+	StatementID     *int64           `gorm:"column:statement_id;type:bigint"`
+	// EventID é o evento de faturamento que gerou o lançamento; vazio nos lançamentos feitos pela API.
+	EventID *int64 `gorm:"column:event_id;type:bigint"`
+
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz"`
+}
+
+func (CommissionEntry) TableName() string {
+	return "commission_entries"
+}
+
+// CommissionLine é a comissão de um item da venda (ver commission.LineResult).
+type CommissionLine struct {
+	Category     string          `json:"category,omitempty"`
+	Value        decimal.Decimal `json:"value"`
+	Discount     decimal.Decimal `json:"discount"`
+	DiscountRate decimal.Decimal `json:"discountRate"`
+	Base         decimal.Decimal `json:"base"`
+	Rate         decimal.Decimal `json:"rate"`
+	Amount       decimal.Decimal `json:"amount"`
+}
+
+// CommissionStatement é o extrato de comissões aprovado de um vendedor num mês.
+type CommissionStatement struct {
+	ID              int64           `gorm:"column:id;type:bigint;primaryKey"`
+	CompanyGlobalID int64           `gorm:"column:company_global_id;type:bigint;not null"`
+	UserID          int64           `gorm:"column:user_id;type:bigint;not null"`
+	Period          time.Time       `gorm:"column:period;type:date;not null"`
+	EntryCount      int             `gorm:"column:entry_count;type:integer;not null"`
+	BaseAmount      decimal.Decimal `gorm:"column:base_amount;type:numeric(15,2);not null"`
+	TotalAmount     decimal.Decimal `gorm:"column:total_amount;type:numeric(15,2);not null"`
+	ApprovedAt      time.Time       `gorm:"column:approved_at;type:timestamptz;not null"`
+	ApprovedByType  string          `gorm:"column:approved_by_type;type:varchar(20);not null"`
+	ApprovedByID    int64           `gorm:"column:approved_by_id;type:bigint;not null"`
+
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz"`
+}
+
+func (CommissionStatement) TableName() string {
+	return "commission_statements"
+}
@@ -13,15 +13,25 @@ type CompanyGlobalContact struct {
 	Email     *string `gorm:"column:email;type:varchar(150)"`
 	Phone     *string `gorm:"column:phone;type:varchar(20)"`
 	CGC       *string `gorm:"column:cgc;type:varchar(40)"`
+	Version   int64   `gorm:"column:version;type:bigint;not null;default:1"`
 }
 
 func (CompanyGlobalContact) TableName() string {
 	return "company_global_contacts"
 }
 
+// Tipos de endereço de uma empresa. Cada empresa tem no máximo um endereço MAIN.
+const (
+	AddressTypeMain     = "MAIN"
+	AddressTypeBilling  = "BILLING"
+	AddressTypeDelivery = "DELIVERY"
+	AddressTypeOther    = "OTHER"
+)
+
 type CompanyGlobalAddress struct {
 	ID               int64   `gorm:"column:id;type:bigint;primary_key"`
 	CompanyID        int64   `gorm:"column:company_id;type:bigint"`
+	Type             string  `gorm:"column:type;type:varchar(20);not null;default:MAIN"`
 	Street           string  `gorm:"column:street;type:varchar(255)"`
 	StreetNumber     *string `gorm:"column:street_number;type:varchar(50)"`
 	StreetComplement *string `gorm:"column:street_complement;type:varchar(255)"`
@@ -29,6 +39,9 @@ type CompanyGlobalAddress struct {
 	State            string  `gorm:"column:state;type:varchar(100)"`
 	PostalCode       string  `gorm:"column:postal_code;type:varchar(20)"`
 	Country          string  `gorm:"column:country;type:varchar(100)"`
+	// CityIBGECode é preenchido pelo AddressProvider para endereços no Brasil.
+	CityIBGECode *int32 `gorm:"column:city_ibge_code;type:integer"`
+	Version      int64  `gorm:"column:version;type:bigint;not null;default:1"`
 }
 
 func (CompanyGlobalAddress) TableName() string {
@@ -43,9 +56,16 @@ type CompanyGlobal struct {
 	CGC         string  `gorm:"column:cgc;type:varchar(40)"`
 	Enabled     bool    `gorm:"column:enabled;type:boolean"`
 	Email       *string `gorm:"column:email;type:varchar(150)"`
+	// TaxRegime é o regime tributário da empresa (tax.RegimeNormal ou tax.RegimeSimples).
+	TaxRegime string `gorm:"column:tax_regime;type:varchar(20);not null;default:normal"`
+	// PixKey é a chave PIX que recebe os pagamentos da empresa, no formato do DICT (ver pix.ParseKey).
+	PixKey *string `gorm:"column:pix_key;type:varchar(77)"`
+
+	Addresses []*CompanyGlobalAddress `gorm:"foreignKey:CompanyID"`
+	Contacts  []*CompanyGlobalContact `gorm:"foreignKey:CompanyID"`
 
-	Address  *CompanyGlobalAddress   `gorm:"foreignKey:CompanyID;references:ID"`
-	Contacts []*CompanyGlobalContact `gorm:"foreignKey:CompanyID"`
+	// Version é incrementada a cada escrita e exposta como ETag (controle de concorrência otimista).
+	Version int64 `gorm:"column:version;type:bigint;not null;default:1"`
 
 	CreatedAt time.Time      `gorm:"column:created_at;type:timestamptz;autoCreateTime;<-:create"`
 	UpdatedAt time.Time      `gorm:"column:updated_at;type:timestamptz;autoUpdateTime"`
@@ -55,3 +75,13 @@ type CompanyGlobal struct {
 func (CompanyGlobal) TableName() string {
 	return "company_globals"
 }
+
+// MainAddress devolve o endereço principal (MAIN) entre os endereços carregados, ou nil.
+func (c *CompanyGlobal) MainAddress() *CompanyGlobalAddress {
+	for _, address := range c.Addresses {
+		if address != nil && address.Type == AddressTypeMain {
+			return address
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+)
+
+// Estados de uma chave de idempotência.
+const (
+	IdempotencyStatusProcessing = "processing"
+	IdempotencyStatusCompleted  = "completed"
+)
+
+// IdempotencyKey guarda a primeira resposta de um POST enviado com o header Idempotency-Key.
+type IdempotencyKey struct {
+	// TenantID é a empresa das credenciais; 0 nas requisições anônimas, que usam Scope.
+	TenantID int64 `gorm:"column:tenant_id;type:bigint;primaryKey;autoIncrement:false"`
+	// Scope separa as chaves das requisições anônimas pelo IP do cliente (ex: "ip=203.0.113.7"); vazio
+	// nas autenticadas.
+	Scope       string `gorm:"column:scope;type:varchar(60);primaryKey"`
+	Key         string `gorm:"column:idempotency_key;type:varchar(255);primaryKey"`
+	RequestHash string `gorm:"column:request_hash;type:char(64);not null"`
+	Status      string `gorm:"column:status;type:varchar(20);not null"`
+
+	ResponseStatus  int    `gorm:"column:response_status;type:integer"`
+	ResponseHeaders []byte `gorm:"column:response_headers;type:jsonb"`
+	ResponseBody    []byte `gorm:"column:response_body;type:bytea"`
+
+	// LockToken identifica a reserva da requisição que detém a chave.
+	LockToken   string    `gorm:"column:lock_token;type:varchar(36);not null"`
+	LockedUntil time.Time `gorm:"column:locked_until;type:timestamptz;not null"`
+	ExpiresAt   time.Time `gorm:"column:expires_at;type:timestamptz;not null"`
+
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamptz"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
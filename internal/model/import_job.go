@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+)
+
+// Estados de uma importação em massa.
+const (
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+// ImportJob acompanha uma importação de planilha feita em segundo plano.
+type ImportJob struct {
+	ID              int64  `gorm:"column:id;type:bigint;primaryKey;autoIncrement:false"`
+	CompanyGlobalID int64  `gorm:"column:company_global_id;type:bigint;not null;default:0"`
+	Entity          string `gorm:"column:entity;type:varchar(30);not null"`
+	FileName        string `gorm:"column:file_name;type:varchar(255);not null"`
+	DryRun          bool   `gorm:"column:dry_run;type:boolean;not null"`
+	Status          string `gorm:"column:status;type:varchar(20);not null"`
+
+	TotalRows     int `gorm:"column:total_rows;type:integer;not null"`
+	ProcessedRows int `gorm:"column:processed_rows;type:integer;not null"`
+	SucceededRows int `gorm:"column:succeeded_rows;type:integer;not null"`
+	FailedRows    int `gorm:"column:failed_rows;type:integer;not null"`
+
+	// Errors é a lista JSON de erros por linha (dto.ImportRowErrorDTO).
+	Errors       []byte  `gorm:"column:errors;type:jsonb;not null"`
+	ErrorMessage *string `gorm:"column:error_message;type:text"`
+
+	StartedAt  *time.Time `gorm:"column:started_at;type:timestamptz"`
+	FinishedAt *time.Time `gorm:"column:finished_at;type:timestamptz"`
+	CreatedAt  time.Time  `gorm:"column:created_at;type:timestamptz"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at;type:timestamptz"`
+}
+
+func (ImportJob) TableName() string {
+	return "import_jobs"
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"time"
+
+	"go-sales/pkg/decimal"
+
+	"gorm.io/gorm"
+)
+
+// Estados de uma parcela. Só parcelas open podem ser alteradas, cobradas ou pagas.
+const (
+	InstallmentOpen      = "open"
+	InstallmentPaid      = "paid"
+	InstallmentCancelled = "cancelled"
+)
+
+// Meios de pagamento de uma parcela paga.
+const (
+	PaymentMethodPix    = "pix"
+	PaymentMethodBoleto = "boleto"
+	// PaymentMethodBankStatement é a baixa pela conciliação do extrato bancário.
+	PaymentMethodBankStatement = "bank_statement"
+)
+
+// Installment é uma parcela a receber de uma empresa.
+type Installment struct {
+	ID                int64           `gorm:"column:id;type:bigint;primaryKey"`
+	CompanyGlobalID   int64           `gorm:"column:company_global_id;type:bigint;not null"`
+	DocumentNumber    string          `gorm:"column:document_number;type:varchar(60);not null"`
+	InstallmentNumber int             `gorm:"column:installment_number;type:integer;not null;default:1"`
+	CustomerName      string          `gorm:"column:customer_name;type:varchar(255);not null"`
+	CustomerCGC       *string         `gorm:"column:customer_cgc;type:varchar(14)"`
+	Description       *string         `gorm:"column:description;type:text"`
+	Amount            decimal.Decimal `gorm:"column:amount;type:numeric(15,2);not null"`
+	DueDate           time.Time       `gorm:"column:due_date;type:date;not null"`
+	Status            string          `gorm:"column:status;type:varchar(20);not null;default:open"`
+
+	PaidAmount       *decimal.Decimal `gorm:"column:paid_amount;type:numeric(15,2)"`
+	PaidAt           *time.Time       `gorm:"column:paid_at;type:timestamptz"`
+	PaymentMethod    *string          `gorm:"column:payment_method;type:varchar(20)"`
+	PaymentReference *string          `gorm:"column:payment_reference;type:varchar(100)"`
+
+	// Version é incrementada a cada escrita e exposta como ETag (controle de concorrência otimista).
+	Version int64 `gorm:"column:version;type:bigint;not null;default:1"`
+
+	CreatedAt time.Time      `gorm:"column:created_at;type:timestamptz"`
+	UpdatedAt time.Time      `gorm:"column:updated_at;type:timestamptz"`
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;type:timestamptz"`
+}
+
+func (Installment) TableName() string {
+	return "installments"
+}
+
+// InstallmentPayment é a baixa de uma parcela.
+type InstallmentPayment struct {
+	Amount    decimal.Decimal
+	PaidAt    time.Time
+	Method    string
+	Reference string
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+)
+
+// Estados de um job da fila. Um job que falha volta para pending com um novo run_at até esgotar
+// as tentativas; então vai para dead e só sai de lá manualmente.
+const (
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusDead      = "dead"
+)
+
+// Job é uma unidade de trabalho assíncrono da fila em master.jobs. O Payload é apagado quando o job
+// termina (completed ou dead).
+type Job struct {
+	ID          int64  `gorm:"column:id;type:bigint;primaryKey;autoIncrement:false"`
+	Type        string `gorm:"column:type;type:varchar(100);not null"`
+	Payload     []byte `gorm:"column:payload;type:jsonb"`
+	Status      string `gorm:"column:status;type:varchar(20);not null"`
+	Attempts    int    `gorm:"column:attempts;type:integer;not null"`
+	MaxAttempts int    `gorm:"column:max_attempts;type:integer;not null"`
+
+	RunAt     time.Time `gorm:"column:run_at;type:timestamptz;not null"`
+	UniqueKey *string   `gorm:"column:unique_key;type:varchar(255)"`
+
+	LockedBy   *string    `gorm:"column:locked_by;type:varchar(100)"`
+	LockedAt   *time.Time `gorm:"column:locked_at;type:timestamptz"`
+	LastError  *string    `gorm:"column:last_error;type:text"`
+	FinishedAt *time.Time `gorm:"column:finished_at;type:timestamptz"`
+	CreatedAt  time.Time  `gorm:"column:created_at;type:timestamptz"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at;type:timestamptz"`
+}
+
+func (Job) TableName() string {
+	return "jobs"
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+)
+
+// Estados de um evento do outbox. Um evento cujo despacho falha continua pending, com um novo
+// next_attempt_at, até esgotar as tentativas; então vai para dead e deixa de bloquear os eventos
+// seguintes do mesmo agregado.
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusProcessed = "processed"
+	OutboxStatusDead      = "dead"
+)
+
+// OutboxEvent é um evento de domínio gravado em master.outbox_events à espera do despacho.
+type OutboxEvent struct {
+	ID              int64  `gorm:"column:id;type:bigint;primaryKey;autoIncrement:false"`
+	Position        int64  `gorm:"column:position;type:bigserial;->"`
+	EventType       string `gorm:"column:event_type;type:varchar(100);not null"`
+	AggregateType   string `gorm:"column:aggregate_type;type:varchar(100);not null"`
+	AggregateID     int64  `gorm:"column:aggregate_id;type:bigint;not null"`
+	CompanyGlobalID int64  `gorm:"column:company_global_id;type:bigint;not null"`
+	Payload         []byte `gorm:"column:payload;type:jsonb;not null"`
+	Status          string `gorm:"column:status;type:varchar(20);not null"`
+	Attempts        int    `gorm:"column:attempts;type:integer;not null"`
+
+	NextAttemptAt time.Time  `gorm:"column:next_attempt_at;type:timestamptz;not null"`
+	LockedBy      *string    `gorm:"column:locked_by;type:varchar(100)"`
+	LockedUntil   *time.Time `gorm:"column:locked_until;type:timestamptz"`
+	LastError     *string    `gorm:"column:last_error;type:text"`
+	OccurredAt    time.Time  `gorm:"column:occurred_at;type:timestamptz;not null"`
+	ProcessedAt   *time.Time `gorm:"column:processed_at;type:timestamptz"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
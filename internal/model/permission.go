@@ -10,6 +10,9 @@ type Permission struct {
 	Name            string  `gorm:"column:name;unique;not null"`
 	Description     *string `gorm:"column:description"`
 
+	// Version é incrementada a cada escrita e exposta como ETag (controle de concorrência otimista).
+	Version int64 `gorm:"column:version;type:bigint;not null;default:1"`
+
 	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz"`
 	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamptz"`
 }
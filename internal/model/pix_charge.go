@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"go-sales/pkg/decimal"
+)
+
+// Tipos de cobrança PIX: o QR Code estático traz a chave no próprio payload; o dinâmico aponta para a
+// cobrança registrada no PSP (location).
+const (
+	PixChargeStatic  = "static"
+	PixChargeDynamic = "dynamic"
+)
+
+// Estados de uma cobrança PIX.
+const (
+	PixChargeActive = "active"
+	PixChargePaid   = "paid"
+)
+
+// PixCharge é uma cobrança PIX de uma parcela.
+type PixCharge struct {
+	ID              int64           `gorm:"column:id;type:bigint;primaryKey"`
+	CompanyGlobalID int64           `gorm:"column:company_global_id;type:bigint;not null"`
+	InstallmentID   int64           `gorm:"column:installment_id;type:bigint;not null"`
+	TxID            string          `gorm:"column:txid;type:varchar(35);not null"`
+	Type            string          `gorm:"column:type;type:varchar(10);not null"`
+	PixKey          string          `gorm:"column:pix_key;type:varchar(77);not null"`
+	Amount          decimal.Decimal `gorm:"column:amount;type:numeric(15,2);not null"`
+	Payload         string          `gorm:"column:payload;type:text;not null"`
+	Location        *string         `gorm:"column:location;type:varchar(255)"`
+	Status          string          `gorm:"column:status;type:varchar(20);not null;default:active"`
+
+	EndToEndID *string          `gorm:"column:end_to_end_id;type:varchar(32)"`
+	PaidAmount *decimal.Decimal `gorm:"column:paid_amount;type:numeric(15,2)"`
+	PaidAt     *time.Time       `gorm:"column:paid_at;type:timestamptz"`
+	ExpiresAt  *time.Time       `gorm:"column:expires_at;type:timestamptz"`
+
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamptz"`
+}
+
+func (PixCharge) TableName() string {
+	return "pix_charges"
+}
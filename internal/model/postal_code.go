@@ -0,0 +1,27 @@
+package model
+
+// IBGEMunicipality é um município da tabela do IBGE, usado para validar cidade e UF e para obter o
+// código exigido nas notas fiscais.
+type IBGEMunicipality struct {
+	IBGECode int32  `gorm:"column:ibge_code;type:integer;primaryKey;autoIncrement:false"`
+	Name     string `gorm:"column:name;type:varchar(100);not null"`
+	UF       string `gorm:"column:uf;type:char(2);not null"`
+}
+
+func (IBGEMunicipality) TableName() string {
+	return "ibge_municipalities"
+}
+
+// PostalCode é um CEP da base local, com apenas os 8 dígitos.
+type PostalCode struct {
+	PostalCode   string  `gorm:"column:postal_code;type:char(8);primaryKey"`
+	Street       *string `gorm:"column:street;type:varchar(255)"`
+	District     *string `gorm:"column:district;type:varchar(100)"`
+	CityIBGECode int32   `gorm:"column:city_ibge_code;type:integer;not null"`
+
+	Municipality *IBGEMunicipality `gorm:"foreignKey:CityIBGECode;references:IBGECode"`
+}
+
+func (PostalCode) TableName() string {
+	return "postal_codes"
+}
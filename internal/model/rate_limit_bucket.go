@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+)
+
+// RateLimitBucket é o estado de um token bucket do rate limit guardado no Postgres.
+type RateLimitBucket struct {
+	Key        string    `gorm:"column:bucket_key;type:varchar(512);primaryKey"`
+	Tokens     float64   `gorm:"column:tokens;type:double precision;not null"`
+	Allowed    bool      `gorm:"column:allowed;type:boolean;not null"`
+	RefilledAt time.Time `gorm:"column:refilled_at;type:timestamptz;not null"`
+	ExpiresAt  time.Time `gorm:"column:expires_at;type:timestamptz;not null"`
+}
+
+func (RateLimitBucket) TableName() string {
+	return "rate_limit_buckets"
+}
@@ -16,6 +16,9 @@ type Role struct {
 	CanDelete bool `gorm:"column:can_delete;type:boolean;default:true"`
 	IsAdmin   bool `gorm:"column:is_admin;type:boolean;default:false"`
 
+	// Version é incrementada a cada escrita e exposta como ETag (controle de concorrência otimista).
+	Version int64 `gorm:"column:version;type:bigint;not null;default:1"`
+
 	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz"`
 	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamptz"`
 }
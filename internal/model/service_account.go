@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ServiceAccount é a identidade de uma integração (ERP, e-commerce) que chama a API sem um usuário.
+// Pertence a uma empresa e recebe as mesmas roles dos usuários.
+type ServiceAccount struct {
+	ID              int64   `gorm:"column:id;type:bigint;primaryKey"`
+	CompanyGlobalID int64   `gorm:"column:company_global_id;type:bigint;not null"`
+	Name            string  `gorm:"column:name;type:varchar(255);not null"`
+	Description     *string `gorm:"column:description;type:text"`
+	Enabled         bool    `gorm:"column:enabled;type:boolean;not null;default:true"`
+
+	Roles []*Role `gorm:"many2many:service_account_roles;"`
+
+	// Version é incrementada a cada escrita e exposta como ETag (controle de concorrência otimista).
+	Version int64 `gorm:"column:version;type:bigint;not null;default:1"`
+
+	CreatedAt time.Time      `gorm:"column:created_at;type:timestamptz"`
+	UpdatedAt time.Time      `gorm:"column:updated_at;type:timestamptz"`
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;type:timestamptz"`
+}
+
+func (ServiceAccount) TableName() string {
+	return "service_accounts"
+}
+
+// APIKey é uma chave de API de uma conta de serviço. A chave em si só existe na resposta que a cria;
+// o banco guarda o prefixo, para localizar a chave, e o hash SHA-256 da chave inteira.
+type APIKey struct {
+	ID               int64          `gorm:"column:id;type:bigint;primaryKey"`
+	ServiceAccountID int64          `gorm:"column:service_account_id;type:bigint;not null"`
+	ServiceAccount   ServiceAccount `gorm:"foreignKey:ServiceAccountID"`
+	Prefix           string         `gorm:"column:prefix;type:varchar(32);not null;unique"`
+	KeyHash          string         `gorm:"column:key_hash;type:char(64);not null"`
+	ExpiresAt        *time.Time     `gorm:"column:expires_at;type:timestamptz"`
+	LastUsedAt       *time.Time     `gorm:"column:last_used_at;type:timestamptz"`
+	RevokedAt        *time.Time     `gorm:"column:revoked_at;type:timestamptz"`
+	CreatedAt        time.Time      `gorm:"column:created_at;type:timestamptz"`
+	UpdatedAt        time.Time      `gorm:"column:updated_at;type:timestamptz"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+)
+
+// SnowflakeNode é o lease de um node ID do Snowflake por uma instância da API.
+type SnowflakeNode struct {
+	NodeID      int64     `gorm:"column:node_id;type:smallint;primaryKey;autoIncrement:false"`
+	Holder      string    `gorm:"column:holder;type:varchar(255);not null"`
+	LeasedUntil time.Time `gorm:"column:leased_until;type:timestamptz;not null"`
+	CreatedAt   time.Time `gorm:"column:created_at;type:timestamptz"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;type:timestamptz"`
+}
+
+func (SnowflakeNode) TableName() string {
+	return "snowflake_nodes"
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"go-sales/pkg/decimal"
+
+	"gorm.io/gorm"
+)
+
+// TaxRule é uma regra de impostos de uma empresa (ver o pacote fiscal/tax). Os critérios nulos casam
+// com qualquer valor; os percentuais são guardados como 18.0000 para 18%.
+type TaxRule struct {
+	ID              int64   `gorm:"column:id;type:bigint;primaryKey"`
+	CompanyGlobalID int64   `gorm:"column:company_global_id;type:bigint;not null"`
+	Description     *string `gorm:"column:description;type:text"`
+
+	OriginUF       *string `gorm:"column:origin_uf;type:char(2)"`
+	DestinationUF  *string `gorm:"column:destination_uf;type:char(2)"`
+	NCM            *string `gorm:"column:ncm;type:varchar(8)"`
+	CFOP           *string `gorm:"column:cfop;type:char(4)"`
+	CustomerRegime *string `gorm:"column:customer_regime;type:varchar(20)"`
+	IssuerRegime   *string `gorm:"column:issuer_regime;type:varchar(20)"`
+
+	ICMSCode                 string          `gorm:"column:icms_code;type:varchar(3);not null"`
+	ICMSRate                 decimal.Decimal `gorm:"column:icms_rate;type:numeric(7,4);not null"`
+	ICMSBaseReduction        decimal.Decimal `gorm:"column:icms_base_reduction;type:numeric(7,4);not null"`
+	SimplesCreditRate        decimal.Decimal `gorm:"column:simples_credit_rate;type:numeric(7,4);not null"`
+	ICMSSTMVA                decimal.Decimal `gorm:"column:icms_st_mva;type:numeric(9,4);not null"`
+	ICMSSTRate               decimal.Decimal `gorm:"column:icms_st_rate;type:numeric(7,4);not null"`
+	ICMSSTBaseReduction      decimal.Decimal `gorm:"column:icms_st_base_reduction;type:numeric(7,4);not null"`
+	IPICode                  *string         `gorm:"column:ipi_code;type:char(2)"`
+	IPIRate                  decimal.Decimal `gorm:"column:ipi_rate;type:numeric(7,4);not null"`
+	PISCode                  string          `gorm:"column:pis_code;type:char(2);not null"`
+	PISRate                  decimal.Decimal `gorm:"column:pis_rate;type:numeric(7,4);not null"`
+	COFINSCode               string          `gorm:"column:cofins_code;type:char(2);not null"`
+	COFINSRate               decimal.Decimal `gorm:"column:cofins_rate;type:numeric(7,4);not null"`
+	ExcludeICMSFromPISCOFINS bool            `gorm:"column:exclude_icms_from_pis_cofins;type:boolean;not null"`
+	Enabled                  bool            `gorm:"column:enabled;type:boolean;not null;default:true"`
+
+	// Version é incrementada a cada escrita e exposta como ETag (controle de concorrência otimista).
+	Version int64 `gorm:"column:version;type:bigint;not null;default:1"`
+
+	CreatedAt time.Time      `gorm:"column:created_at;type:timestamptz"`
+	UpdatedAt time.Time      `gorm:"column:updated_at;type:timestamptz"`
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;type:timestamptz"`
+}
+
+func (TaxRule) TableName() string {
+	return "tax_rules"
+}
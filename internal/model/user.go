@@ -31,6 +31,12 @@ type User struct {
 	ResetRequested *string    `gorm:"column:reset_requested;type:timestamptz;"`
 	ResetAt        *time.Time `gorm:"column:reset_at;type:timestamptz;"`
 
+	// FailedLoginAttempts conta as senhas erradas seguidas; volta a zero no próximo acerto.
+	FailedLoginAttempts int        `gorm:"column:failed_login_attempts;type:integer;not null;default:0"`
+	LastFailedLoginAt   *time.Time `gorm:"column:last_failed_login_at;type:timestamptz;"`
+	// LockedUntil é até quando a conta recusa a senha, mesmo correta.
+	LockedUntil *time.Time `gorm:"column:locked_until;type:timestamptz;"`
+
 	// 1. Adiciona o campo para a chave estrangeira.
 	// O GORM usará isso para criar a coluna `company_global_id` na tabela `users`.
 	CompanyGlobalID int64 `gorm:"column:company_global_id;type:bigint;not null"`
@@ -42,6 +48,9 @@ type User struct {
 
 	Roles []*Role `gorm:"many2many:user_roles;"`
 
+	// Version é incrementada a cada escrita e exposta como ETag (controle de concorrência otimista).
+	Version int64 `gorm:"column:version;type:bigint;not null;default:1"`
+
 	CreatedAt time.Time      `gorm:"column:created_at;type:timestamptz"`
 	UpdatedAt time.Time      `gorm:"column:updated_at;type:timestamptz"`
 	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;type:timestamptz"`
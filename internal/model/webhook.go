@@ -0,0 +1,79 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Estados de uma entrega de webhook. Uma entrega que falha continua pending enquanto houver
+// tentativas; esgotadas, vai para failed e só volta a ser enviada por replay.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookEndpoint é uma URL de uma empresa que recebe os eventos assinados.
+type WebhookEndpoint struct {
+	ID              int64    `gorm:"column:id;type:bigint;primaryKey"`
+	CompanyGlobalID int64    `gorm:"column:company_global_id;type:bigint;not null"`
+	URL             string   `gorm:"column:url;type:varchar(2048);not null"`
+	Description     *string  `gorm:"column:description;type:text"`
+	EventTypes      []string `gorm:"column:event_types;type:jsonb;serializer:json;not null"`
+	Secret          string   `gorm:"column:secret;type:varchar(128);not null"`
+	Enabled         bool     `gorm:"column:enabled;type:boolean;not null;default:true"`
+
+	// Version é incrementada a cada escrita e exposta como ETag (controle de concorrência otimista).
+	Version int64 `gorm:"column:version;type:bigint;not null;default:1"`
+
+	CreatedAt time.Time      `gorm:"column:created_at;type:timestamptz"`
+	UpdatedAt time.Time      `gorm:"column:updated_at;type:timestamptz"`
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;type:timestamptz"`
+}
+
+func (WebhookEndpoint) TableName() string {
+	return "webhook_endpoints"
+}
+
+// WebhookDelivery é a entrega de um evento para um endpoint.
+type WebhookDelivery struct {
+	ID              int64           `gorm:"column:id;type:bigint;primaryKey"`
+	EndpointID      int64           `gorm:"column:endpoint_id;type:bigint;not null"`
+	Endpoint        WebhookEndpoint `gorm:"foreignKey:EndpointID"`
+	CompanyGlobalID int64           `gorm:"column:company_global_id;type:bigint;not null"`
+	EventID         int64           `gorm:"column:event_id;type:bigint;not null"`
+	EventType       string          `gorm:"column:event_type;type:varchar(100);not null"`
+	Payload         []byte          `gorm:"column:payload;type:jsonb;not null"`
+	Status          string          `gorm:"column:status;type:varchar(20);not null"`
+	Attempts        int             `gorm:"column:attempts;type:integer;not null"`
+	LastStatusCode  *int            `gorm:"column:last_status_code;type:integer"`
+	LastError       *string         `gorm:"column:last_error;type:text"`
+	DeliveredAt     *time.Time      `gorm:"column:delivered_at;type:timestamptz"`
+	ReplayOf        *int64          `gorm:"column:replay_of;type:bigint"`
+
+	AttemptLog []WebhookDeliveryAttempt `gorm:"foreignKey:DeliveryID"`
+
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamptz"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookDeliveryAttempt é uma tentativa de entrega, com a resposta do receptor.
+type WebhookDeliveryAttempt struct {
+	ID           int64     `gorm:"column:id;type:bigint;primaryKey"`
+	DeliveryID   int64     `gorm:"column:delivery_id;type:bigint;not null"`
+	Attempt      int       `gorm:"column:attempt;type:integer;not null"`
+	StatusCode   *int      `gorm:"column:status_code;type:integer"`
+	Error        *string   `gorm:"column:error;type:text"`
+	ResponseBody *string   `gorm:"column:response_body;type:text"`
+	DurationMs   int64     `gorm:"column:duration_ms;type:bigint;not null"`
+	CreatedAt    time.Time `gorm:"column:created_at;type:timestamptz"`
+}
+
+func (WebhookDeliveryAttempt) TableName() string {
+	return "webhook_delivery_attempts"
+}
@@ -0,0 +1,207 @@
+// Package outbox despacha os eventos de domínio gravados em master.outbox_events para os assinantes do
+// event.Bus. Os eventos são gravados pelos repositórios na mesma transação da escrita que os gerou, então
+// só são despachados se ela foi confirmada, e nenhum se perde se o processo cair depois do commit.
+package outbox
+
+import (
+	"context"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-sales/internal/database"
+	"go-sales/internal/event"
+	"go-sales/internal/model"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Options configura o Dispatcher. Valores zerados usam os padrões de NewDispatcher.
+type Options struct {
+	// PollInterval é a espera entre consultas quando não há eventos prontos.
+	PollInterval time.Duration
+	// BatchSize é quantos eventos são reservados por consulta.
+	BatchSize int
+	// LockTimeout é por quanto tempo a reserva de um lote vale; se o processo cair, os eventos voltam
+	// a ser despachados depois disso.
+	LockTimeout time.Duration
+	// MaxAttempts é o número de tentativas de um evento antes de ele ir para dead.
+	MaxAttempts int
+	// BaseBackoff e MaxBackoff limitam a espera entre tentativas: BaseBackoff * 2^(tentativa-1).
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Dispatcher entrega os eventos do outbox ao barramento, pelo menos uma vez e na ordem em que foram
+// gravados dentro de cada agregado. Um evento que falha bloqueia os seguintes do mesmo agregado até
+// ser entregue ou ir para dead; os de outros agregados seguem normalmente.
+type Dispatcher struct {
+	repo     database.OutboxRepositoryInterface
+	bus      *event.Bus
+	opts     Options
+	workerID string
+
+	stop   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher cria o Dispatcher. Os assinantes devem ser registrados no barramento antes de Start.
+func NewDispatcher(repo database.OutboxRepositoryInterface, bus *event.Bus, opts Options) *Dispatcher {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.LockTimeout <= 0 {
+		opts.LockTimeout = 5 * time.Minute
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 10
+	}
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = 5 * time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = 30 * time.Minute
+	}
+
+	hostname, _ := os.Hostname()
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		repo:     repo,
+		bus:      bus,
+		opts:     opts,
+		workerID: hostname + ":" + strconv.Itoa(os.Getpid()),
+		stop:     make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start inicia o despacho em segundo plano.
+func (d *Dispatcher) Start() {
+	log.Info().Str("worker_id", d.workerID).Msg("Starting outbox dispatcher")
+	d.wg.Add(1)
+	go d.work()
+}
+
+// Shutdown para de reservar eventos e espera o lote em andamento terminar. Se ctx vencer antes, os
+// assinantes recebem o cancelamento do seu contexto e Shutdown espera apenas que eles retornem. Os
+// eventos do lote que não foram despachados voltam ao outbox quando a reserva expirar.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	log.Info().Msg("Draining outbox dispatcher")
+	close(d.stop)
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		d.cancel()
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+	for {
+		select {
+		case <-d.stop:
+			return
+		default:
+		}
+
+		events, err := d.repo.Claim(d.workerID, time.Now(), d.opts.BatchSize, d.opts.LockTimeout)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to claim outbox events")
+		}
+		for i := range events {
+			select {
+			case <-d.stop:
+				return
+			default:
+			}
+			d.process(&events[i])
+		}
+		// Com o lote cheio provavelmente há mais eventos prontos: consulta de novo sem esperar.
+		if err == nil && len(events) == d.opts.BatchSize {
+			continue
+		}
+
+		select {
+		case <-d.stop:
+			return
+		case <-time.After(d.opts.PollInterval):
+		}
+	}
+}
+
+// process entrega o evento aos assinantes e grava o resultado: processed, nova tentativa com backoff
+// ou dead. Um lote reservado tem no máximo um evento de cada agregado, então a ordem entre eles não importa.
+func (d *Dispatcher) process(outboxEvent *model.OutboxEvent) {
+	logger := log.With().
+		Int64("event_id", outboxEvent.ID).
+		Str("event_type", outboxEvent.EventType).
+		Int("attempt", outboxEvent.Attempts).
+		Logger()
+
+	now := time.Now()
+	decoded, err := event.Decode(outboxEvent.EventType, outboxEvent.Payload)
+	if err != nil {
+		// Um evento que não decodifica não vai decodificar na próxima tentativa.
+		logger.Error().Err(err).Msg("failed to decode outbox event, moving to dead")
+		if err := d.repo.Dead(outboxEvent.ID, now, err.Error()); err != nil {
+			logger.Error().Err(err).Msg("failed to move outbox event to dead")
+		}
+		return
+	}
+
+	err = d.bus.Dispatch(d.ctx, event.Envelope{
+		ID:              outboxEvent.ID,
+		Type:            outboxEvent.EventType,
+		AggregateType:   outboxEvent.AggregateType,
+		AggregateID:     outboxEvent.AggregateID,
+		CompanyGlobalID: outboxEvent.CompanyGlobalID,
+		OccurredAt:      outboxEvent.OccurredAt,
+		Payload:         outboxEvent.Payload,
+		Event:           decoded,
+	})
+
+	now = time.Now()
+	switch {
+	case err == nil:
+		logger.Debug().Msg("outbox event dispatched")
+		err = d.repo.MarkProcessed(outboxEvent.ID, now)
+	case outboxEvent.Attempts >= d.opts.MaxAttempts:
+		logger.Error().Err(err).Msg("outbox event failed permanently, moving to dead")
+		err = d.repo.Dead(outboxEvent.ID, now, err.Error())
+	default:
+		nextAttemptAt := now.Add(d.backoff(outboxEvent.Attempts))
+		logger.Warn().Err(err).Time("next_attempt_at", nextAttemptAt).Msg("outbox event failed, retrying later")
+		err = d.repo.Retry(outboxEvent.ID, nextAttemptAt, err.Error())
+	}
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to save outbox event result")
+	}
+}
+
+// backoff calcula a espera antes da próxima tentativa, com até 10% de variação aleatória.
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.opts.MaxBackoff
+	if attempt < 32 {
+		delay = min(d.opts.BaseBackoff<<(attempt-1), d.opts.MaxBackoff)
+	}
+	return delay + rand.N(delay/10+1)
+}
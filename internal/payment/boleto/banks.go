@@ -0,0 +1,277 @@
+package boleto
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Bank é o layout de cobrança de um banco: o campo livre do código de barras, o nosso número e os
+// campos próprios do banco nos arquivos CNAB.
+type Bank interface {
+	Code() string
+	Name() string
+	// Validate confere se a conta tem os dados de cobrança que o banco exige.
+	Validate(account Account) error
+	// OurNumber formata o nosso número como impresso no boleto.
+	OurNumber(account Account, ourNumber int64) string
+
+	maxOurNumber() int64
+	// freeField é o campo livre (posições 20 a 44) do código de barras.
+	freeField(account Account, ourNumber int64) string
+	// version240 é a versão do layout do header de arquivo e do header de lote do CNAB 240.
+	version240() (file, batch string)
+	// ourNumber240 é a identificação do título (20 posições) do segmento P; parseOurNumber240 a lê do
+	// segmento T.
+	ourNumber240(account Account, ourNumber int64) string
+	parseOurNumber240(field string) (int64, bool)
+	// header400 e detail400 preenchem os registros do CNAB 400 próprios do banco.
+	header400(r record, account Account, beneficiary Beneficiary, sequence int)
+	detail400(r record, account Account, beneficiary Beneficiary, title Title)
+	detailType400() byte
+	// parse400 lê os campos do detalhe de retorno do CNAB 400 que mudam de posição entre os bancos.
+	parse400(line string) (ourNumber int64, ok bool, controlNumber, creditDate string)
+	// occurrences é a tabela dos códigos de ocorrência do retorno no layout: cada banco tem os seus
+	// no CNAB 400.
+	occurrences(layout string) map[string]string
+}
+
+// Banks são os bancos com layout de cobrança implementado.
+var Banks = map[string]Bank{
+	"001": bancoDoBrasil{},
+	"237": bradesco{},
+}
+
+// BankByCode devolve o layout do banco de código FEBRABAN code (ex: "237").
+func BankByCode(code string) (Bank, error) {
+	if bank, ok := Banks[code]; ok {
+		return bank, nil
+	}
+	return nil, ErrUnsupportedBank
+}
+
+// bancoDoBrasil é a cobrança do Banco do Brasil com convênio de 7 dígitos: o nosso número tem 17
+// dígitos (convênio + sequencial de 10) e não tem dígito verificador. CNAB 400 no layout CBR643.
+type bancoDoBrasil struct{}
+
+// occurrencesBB400 são os comandos do retorno CBR643 do Banco do Brasil.
+var occurrencesBB400 = map[string]string{
+	"02": OccurrenceRegistered,
+	"03": OccurrenceRejected, // comando recusado
+	"05": OccurrenceSettled,  // liquidado sem registro
+	"06": OccurrenceSettled,  // liquidação normal
+	"07": OccurrenceSettled,  // liquidação por conta
+	"08": OccurrenceSettled,  // liquidação por saldo
+	"09": OccurrenceWrittenOff,
+	"10": OccurrenceWrittenOff, // baixa solicitada
+	"15": OccurrenceSettled,    // liquidação em cartório
+}
+
+func (bancoDoBrasil) Code() string { return "001" }
+func (bancoDoBrasil) Name() string { return "BANCO DO BRASIL S.A." }
+
+func (bancoDoBrasil) Validate(account Account) error {
+	switch {
+	case len(account.Agency) != 4 || !isDigits(account.Agency):
+		return errors.New("boleto: Banco do Brasil agency must have 4 digits")
+	case len(account.Number) > 8 || !isDigits(account.Number):
+		return errors.New("boleto: Banco do Brasil account number must have up to 8 digits")
+	case len(account.Agreement) != 7 || !isDigits(account.Agreement):
+		return errors.New("boleto: Banco do Brasil agreement (convênio) must have 7 digits")
+	case len(account.Wallet) != 2 || !isDigits(account.Wallet):
+		return errors.New("boleto: Banco do Brasil wallet must have 2 digits")
+	case account.WalletVariation != "" && (len(account.WalletVariation) > 3 || !isDigits(account.WalletVariation)):
+		return errors.New("boleto: Banco do Brasil wallet variation must have up to 3 digits")
+	}
+	return nil
+}
+
+func (bancoDoBrasil) maxOurNumber() int64 { return 9999999999 }
+
+func (bancoDoBrasil) OurNumber(account Account, ourNumber int64) string {
+	return account.Agreement + padInt(ourNumber, 10)
+}
+
+func (b bancoDoBrasil) freeField(account Account, ourNumber int64) string {
+	return "000000" + b.OurNumber(account, ourNumber) + account.Wallet
+}
+
+func (bancoDoBrasil) version240() (string, string) { return "083", "042" }
+
+func (b bancoDoBrasil) ourNumber240(account Account, ourNumber int64) string {
+	return b.OurNumber(account, ourNumber)
+}
+
+func (bancoDoBrasil) parseOurNumber240(field string) (int64, bool) {
+	if len(field) < 17 {
+		return 0, false
+	}
+	return parseDigits(field[7:17])
+}
+
+func (bancoDoBrasil) header400(r record, account Account, beneficiary Beneficiary, sequence int) {
+	r.alpha(12, 19, "COBRANCA")
+	r.num(27, 30, account.Agency)
+	r.alpha(31, 31, account.AgencyDigit)
+	r.num(32, 39, account.Number)
+	r.alpha(40, 40, account.NumberDigit)
+	r.num(41, 46, "0")
+	r.alpha(47, 76, beneficiary.Name)
+	r.alpha(77, 94, "001BANCODOBRASIL")
+	r.num(101, 107, strconv.Itoa(sequence))
+	r.num(130, 136, account.Agreement)
+}
+
+func (bancoDoBrasil) detailType400() byte { return '7' }
+
+func (b bancoDoBrasil) detail400(r record, account Account, beneficiary Beneficiary, title Title) {
+	r.num(2, 3, inscriptionType(beneficiary.CGC))
+	r.num(4, 17, beneficiary.CGC)
+	r.num(18, 21, account.Agency)
+	r.alpha(22, 22, account.AgencyDigit)
+	r.num(23, 30, account.Number)
+	r.alpha(31, 31, account.NumberDigit)
+	r.num(32, 38, account.Agreement)
+	r.alpha(39, 63, title.ControlNumber)
+	r.num(64, 80, b.OurNumber(account, title.OurNumber))
+	r.num(81, 84, "0")
+	r.num(92, 94, account.WalletVariation)
+	r.num(95, 101, "0")
+	r.num(107, 108, account.Wallet)
+	r.num(140, 142, "001")
+	r.num(143, 146, "0")
+	r.alpha(235, 271, title.Payer.Name)
+	r.alpha(275, 314, title.Payer.Street)
+	r.alpha(315, 326, title.Payer.District)
+	r.num(327, 334, title.Payer.PostalCode)
+	r.alpha(335, 349, title.Payer.City)
+	r.alpha(350, 351, title.Payer.UF)
+}
+
+func (bancoDoBrasil) occurrences(layout string) map[string]string {
+	if layout == Layout400 {
+		return occurrencesBB400
+	}
+	return occurrences240
+}
+
+func (bancoDoBrasil) parse400(line string) (int64, bool, string, string) {
+	ourNumber, ok := parseDigits(field(line, 71, 80))
+	return ourNumber, ok, field(line, 39, 63), field(line, 176, 181)
+}
+
+// bradesco é a cobrança do Bradesco: nosso número de 11 dígitos com dígito verificador módulo 11
+// (base 7) calculado com a carteira.
+type bradesco struct{}
+
+// occurrencesBradesco400 são as ocorrências do retorno CNAB 400 do Bradesco. O 16 (pago em cheque)
+// fica de fora: só vira liquidação depois da compensação, que chega como outra ocorrência.
+var occurrencesBradesco400 = map[string]string{
+	"02": OccurrenceRegistered,
+	"03": OccurrenceRejected,
+	"06": OccurrenceSettled, // liquidação normal
+	"09": OccurrenceWrittenOff,
+	"10": OccurrenceWrittenOff, // baixado conforme instruções da agência
+	"15": OccurrenceSettled,    // liquidação em cartório
+	"17": OccurrenceSettled,    // liquidação após baixa ou de título não registrado
+	"24": OccurrenceRejected,   // entrada rejeitada por CEP irregular
+}
+
+func (bradesco) Code() string { return "237" }
+func (bradesco) Name() string { return "BRADESCO" }
+
+func (bradesco) Validate(account Account) error {
+	switch {
+	case len(account.Agency) > 4 || !isDigits(account.Agency):
+		return errors.New("boleto: Bradesco agency must have up to 4 digits")
+	case len(account.Number) > 7 || !isDigits(account.Number):
+		return errors.New("boleto: Bradesco account number must have up to 7 digits")
+	case len(account.Wallet) != 2 || !isDigits(account.Wallet):
+		return errors.New("boleto: Bradesco wallet must have 2 digits")
+	case len(account.Agreement) > 20 || !isDigits(account.Agreement):
+		return errors.New("boleto: Bradesco agreement (código da empresa) must have up to 20 digits")
+	}
+	return nil
+}
+
+func (bradesco) maxOurNumber() int64 { return 99999999999 }
+
+// ourNumberDigit é o dígito do nosso número: módulo 11 (pesos 2 a 7) de carteira + nosso número;
+// resto 1 vira "P".
+func (bradesco) ourNumberDigit(account Account, ourNumber int64) string {
+	switch rest := mod11(account.Wallet+padInt(ourNumber, 11), 7); rest {
+	case 0:
+		return "0"
+	case 1:
+		return "P"
+	default:
+		return strconv.Itoa(11 - rest)
+	}
+}
+
+func (b bradesco) OurNumber(account Account, ourNumber int64) string {
+	return account.Wallet + "/" + padInt(ourNumber, 11) + "-" + b.ourNumberDigit(account, ourNumber)
+}
+
+func (bradesco) freeField(account Account, ourNumber int64) string {
+	return pad(account.Agency, 4) + account.Wallet + padInt(ourNumber, 11) + pad(account.Number, 7) + "0"
+}
+
+func (bradesco) version240() (string, string) { return "084", "042" }
+
+func (b bradesco) ourNumber240(account Account, ourNumber int64) string {
+	return "0" + account.Wallet + "00000" + padInt(ourNumber, 11) + b.ourNumberDigit(account, ourNumber)
+}
+
+func (bradesco) parseOurNumber240(field string) (int64, bool) {
+	if len(field) < 19 {
+		return 0, false
+	}
+	return parseDigits(field[8:19])
+}
+
+func (bradesco) header400(r record, account Account, beneficiary Beneficiary, sequence int) {
+	r.alpha(12, 26, "COBRANCA")
+	r.num(27, 46, account.Agreement)
+	r.alpha(47, 76, beneficiary.Name)
+	r.num(77, 79, "237")
+	r.alpha(80, 94, "BRADESCO")
+	r.alpha(109, 110, "MX")
+	r.num(111, 117, strconv.Itoa(sequence))
+}
+
+func (bradesco) detailType400() byte { return '1' }
+
+func (b bradesco) detail400(r record, account Account, beneficiary Beneficiary, title Title) {
+	r.num(2, 20, "0")
+	r.num(21, 21, "0")
+	r.num(22, 24, account.Wallet)
+	r.num(25, 29, account.Agency)
+	r.num(30, 36, account.Number)
+	r.alpha(37, 37, account.NumberDigit)
+	r.alpha(38, 62, title.ControlNumber)
+	r.num(63, 65, "0")
+	r.num(66, 70, "0")
+	r.num(71, 81, strconv.FormatInt(title.OurNumber, 10))
+	r.alpha(82, 82, b.ourNumberDigit(account, title.OurNumber))
+	r.num(83, 92, "0")
+	// O próprio beneficiário emite o boleto (2) e não há débito automático.
+	r.num(93, 93, "2")
+	r.alpha(94, 94, "N")
+	r.num(106, 106, "2")
+	r.num(140, 147, "0")
+	r.alpha(235, 274, title.Payer.Name)
+	r.alpha(275, 314, title.Payer.Street+" "+title.Payer.District+" "+title.Payer.City+" "+title.Payer.UF)
+	r.num(327, 334, title.Payer.PostalCode)
+}
+
+func (bradesco) occurrences(layout string) map[string]string {
+	if layout == Layout400 {
+		return occurrencesBradesco400
+	}
+	return occurrences240
+}
+
+func (bradesco) parse400(line string) (int64, bool, string, string) {
+	ourNumber, ok := parseDigits(field(line, 71, 81))
+	return ourNumber, ok, field(line, 38, 62), field(line, 296, 301)
+}
@@ -0,0 +1,123 @@
+// Package boleto gera o código de barras e a linha digitável dos boletos de cobrança (padrão FEBRABAN)
+// e os arquivos CNAB 240 e 400 de remessa, e lê os arquivos de retorno dos bancos suportados.
+package boleto
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go-sales/pkg/decimal"
+)
+
+// ErrUnsupportedBank é retornado por BankByCode para bancos sem layout de cobrança implementado.
+var ErrUnsupportedBank = errors.New("boleto: unsupported bank")
+
+// currencyReal é o código da moeda (real) no código de barras.
+const currencyReal = "9"
+
+// maxAmount é o maior valor que cabe nos 10 dígitos do código de barras.
+var maxAmount = decimal.MustParse("99999999.99")
+
+// Account é a conta de cobrança do beneficiário no banco.
+type Account struct {
+	BankCode    string
+	Agency      string
+	AgencyDigit string
+	Number      string
+	NumberDigit string
+	// Wallet é a carteira de cobrança (ex: "17" no Banco do Brasil, "09" no Bradesco).
+	Wallet string
+	// WalletVariation é a variação da carteira (Banco do Brasil).
+	WalletVariation string
+	// Agreement é o convênio de cobrança (Banco do Brasil, 7 dígitos) ou o código da empresa (Bradesco).
+	Agreement string
+}
+
+// Beneficiary é a empresa que emite o boleto.
+type Beneficiary struct {
+	Name string
+	CGC  string
+}
+
+// Payer é o pagador do boleto.
+type Payer struct {
+	Name       string
+	CGC        string
+	Street     string
+	District   string
+	City       string
+	UF         string
+	PostalCode string
+}
+
+// Title é um boleto a registrar no banco.
+type Title struct {
+	OurNumber      int64
+	DocumentNumber string
+	Amount         decimal.Decimal
+	DueDate        time.Time
+	IssueDate      time.Time
+	Payer          Payer
+	// ControlNumber é o "uso da empresa": o banco o devolve no retorno sem alteração.
+	ControlNumber string
+}
+
+// Code é a representação de um boleto para pagamento.
+type Code struct {
+	// Barcode são os 44 dígitos do código de barras.
+	Barcode string
+	// DigitableLine é a linha digitável formatada (AAABC.CCCCX DDDDD.DDDDDY EEEEE.EEEEEZ K UUUUVVVVVVVVVV).
+	DigitableLine string
+	// OurNumber é o nosso número como impresso no boleto.
+	OurNumber string
+}
+
+// Generate monta o código de barras e a linha digitável do boleto ourNumber da conta.
+func Generate(account Account, ourNumber int64, amount decimal.Decimal, dueDate time.Time) (*Code, error) {
+	bank, err := BankByCode(account.BankCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := bank.Validate(account); err != nil {
+		return nil, err
+	}
+	if ourNumber <= 0 || ourNumber > bank.maxOurNumber() {
+		return nil, fmt.Errorf("boleto: our number must be between 1 and %d", bank.maxOurNumber())
+	}
+	amount = amount.Round(2)
+	if amount.Sign() <= 0 || amount.Cmp(maxAmount) > 0 {
+		return nil, errors.New("boleto: amount must be between 0.01 and 99999999.99")
+	}
+	factor, err := DueFactor(dueDate)
+	if err != nil {
+		return nil, err
+	}
+
+	free := bank.freeField(account, ourNumber)
+	withoutDigit := bank.Code() + currencyReal + padInt(int64(factor), 4) + cents(amount, 10) + free
+	digit := barcodeDigit(withoutDigit)
+	barcode := withoutDigit[:4] + fmt.Sprint(digit) + withoutDigit[4:]
+	return &Code{
+		Barcode:       barcode,
+		DigitableLine: DigitableLine(barcode),
+		OurNumber:     bank.OurNumber(account, ourNumber),
+	}, nil
+}
+
+// DigitableLine converte os 44 dígitos do código de barras na linha digitável formatada.
+func DigitableLine(barcode string) string {
+	field1 := barcode[0:4] + barcode[19:24]
+	field2 := barcode[24:34]
+	field3 := barcode[34:44]
+	field1 += fmt.Sprint(mod10(field1))
+	field2 += fmt.Sprint(mod10(field2))
+	field3 += fmt.Sprint(mod10(field3))
+	return fmt.Sprintf("%s.%s %s.%s %s.%s %s %s",
+		field1[:5], field1[5:], field2[:5], field2[5:], field3[:5], field3[5:], barcode[4:5], barcode[5:19])
+}
+
+// cents devolve o valor em centavos com size dígitos.
+func cents(amount decimal.Decimal, size int) string {
+	return pad(amount.Mul(decimal.FromInt(100)).Round(0).StringFixed(0), size)
+}
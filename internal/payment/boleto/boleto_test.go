@@ -0,0 +1,121 @@
+package boleto
+
+import (
+	"testing"
+	"time"
+
+	"go-sales/pkg/decimal"
+)
+
+// O boleto de exemplo das especificações do Banco do Brasil: código de barras e linha digitável
+// publicados, com os três DVs módulo 10 dos campos e o DV geral módulo 11.
+func TestDigitableLinePublishedExample(t *testing.T) {
+	const barcode = "00193373700000001000500940144816060680935031"
+	const want = "00190.50095 40144.816069 06809.350314 3 37370000000100"
+
+	if got := DigitableLine(barcode); got != want {
+		t.Fatalf("DigitableLine = %s, want %s", got, want)
+	}
+	if got := barcodeDigit(barcode[:4] + barcode[5:]); got != 3 {
+		t.Fatalf("barcodeDigit = %d, want 3", got)
+	}
+}
+
+// Os códigos de barras seguem o campo livre de cada banco: no BB com convênio de 7 dígitos, seis
+// zeros, o nosso número de 17 dígitos (convênio + sequencial) e a carteira; no Bradesco, agência,
+// carteira, nosso número de 11 dígitos, conta e um zero.
+func TestGenerate(t *testing.T) {
+	due := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC) // fator 1016, depois do recomeço
+	tests := []struct {
+		name          string
+		account       Account
+		ourNumber     int64
+		barcode       string
+		digitableLine string
+		printed       string
+	}{
+		{
+			name:          "Banco do Brasil, convênio de 7 dígitos",
+			account:       Account{BankCode: "001", Agency: "1234", Number: "56789", Agreement: "1234567", Wallet: "17", WalletVariation: "19"},
+			ourNumber:     42,
+			barcode:       "00191101600000150750000001234567000000004217",
+			digitableLine: "00190.00009 01234.567004 00000.042176 1 10160000015075",
+			printed:       "12345670000000042",
+		},
+		{
+			name:          "Bradesco, dígito P no nosso número",
+			account:       Account{BankCode: "237", Agency: "1234", Number: "98765", Agreement: "4567", Wallet: "09"},
+			ourNumber:     2,
+			barcode:       "23798101600000150751234090000000000200987650",
+			digitableLine: "23791.23405 90000.000001 02009.876505 8 10160000015075",
+			printed:       "09/00000000002-P",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Generate(tt.account, tt.ourNumber, decimal.MustParse("150.75"), due)
+			if err != nil {
+				t.Fatalf("Generate: %v", err)
+			}
+			if code.Barcode != tt.barcode {
+				t.Errorf("barcode = %s, want %s", code.Barcode, tt.barcode)
+			}
+			if code.DigitableLine != tt.digitableLine {
+				t.Errorf("digitable line = %s, want %s", code.DigitableLine, tt.digitableLine)
+			}
+			if code.OurNumber != tt.printed {
+				t.Errorf("our number = %s, want %s", code.OurNumber, tt.printed)
+			}
+		})
+	}
+}
+
+// O exemplo do manual de cobrança do Bradesco (carteira 19, nosso número 2, DV 8) e os restos 1 e 0,
+// que viram "P" e "0".
+func TestBradescoOurNumberDigit(t *testing.T) {
+	account := Account{Wallet: "19"}
+	tests := []struct {
+		ourNumber int64
+		want      string
+	}{
+		{2, "8"},
+		{1, "P"},
+		{6, "0"},
+		{3, "6"},
+	}
+	for _, tt := range tests {
+		if got := (bradesco{}).ourNumberDigit(account, tt.ourNumber); got != tt.want {
+			t.Errorf("ourNumberDigit(19, %d) = %s, want %s", tt.ourNumber, got, tt.want)
+		}
+	}
+}
+
+// O fator chega a 9999 em 21/02/2025 e recomeça em 1000 no dia seguinte, a cada 9000 dias.
+func TestDueFactor(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		due  time.Time
+		want int
+	}{
+		{day(2000, time.July, 3), 1000},
+		{day(2007, time.December, 31), 3737},
+		{day(2025, time.February, 21), 9999},
+		{day(2025, time.February, 22), 1000},
+		{day(2025, time.February, 23), 1001},
+		{time.Date(2025, time.February, 22, 23, 59, 0, 0, time.FixedZone("BRT", -3*3600)), 1000},
+		{day(2049, time.October, 13), 9999},
+		{day(2049, time.October, 14), 1000},
+	}
+	for _, tt := range tests {
+		got, err := DueFactor(tt.due)
+		if err != nil {
+			t.Fatalf("DueFactor(%s): %v", tt.due.Format(time.DateOnly), err)
+		}
+		if got != tt.want {
+			t.Errorf("DueFactor(%s) = %d, want %d", tt.due.Format(time.DateOnly), got, tt.want)
+		}
+	}
+	if _, err := DueFactor(day(2000, time.July, 2)); err == nil {
+		t.Fatal("DueFactor before 03/07/2000 should fail")
+	}
+}
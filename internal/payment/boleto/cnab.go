@@ -0,0 +1,294 @@
+package boleto
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"go-sales/pkg/decimal"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Layouts de arquivo CNAB.
+const (
+	Layout240 = "cnab240"
+	Layout400 = "cnab400"
+)
+
+// ErrInvalidReturnFile é retornado por ParseReturn quando o arquivo não é um retorno CNAB 240 ou 400
+// de um banco suportado.
+var ErrInvalidReturnFile = errors.New("boleto: invalid CNAB return file")
+
+// Ocorrências do retorno, agrupadas pelo efeito sobre o boleto.
+const (
+	OccurrenceRegistered = "registered"
+	OccurrenceRejected   = "rejected"
+	OccurrenceSettled    = "settled"
+	OccurrenceWrittenOff = "written_off"
+	OccurrenceOther      = "other"
+)
+
+// occurrences240 classifica os códigos de movimento do retorno CNAB 240 (tabela G044 da FEBRABAN),
+// seguidos pelo Banco do Brasil e pelo Bradesco.
+var occurrences240 = map[string]string{
+	"02": OccurrenceRegistered,
+	"03": OccurrenceRejected,
+	"06": OccurrenceSettled, // liquidação
+	"09": OccurrenceWrittenOff,
+	"17": OccurrenceSettled,    // liquidação após baixa ou de título não registrado
+	"25": OccurrenceWrittenOff, // protestado e baixado
+}
+
+// Remittance é um arquivo de remessa: os boletos de uma conta a registrar no banco.
+type Remittance struct {
+	Layout      string
+	Account     Account
+	Beneficiary Beneficiary
+	// Sequence é o número sequencial do arquivo (NSA), que o banco exige crescente por conta.
+	Sequence  int
+	CreatedAt time.Time
+	Titles    []Title
+}
+
+// WriteRemittance monta o arquivo de remessa, com linhas terminadas em CRLF.
+func WriteRemittance(remittance Remittance) ([]byte, error) {
+	bank, err := BankByCode(remittance.Account.BankCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := bank.Validate(remittance.Account); err != nil {
+		return nil, err
+	}
+	if len(remittance.Titles) == 0 {
+		return nil, errors.New("boleto: remittance without titles")
+	}
+	for _, title := range remittance.Titles {
+		if title.OurNumber <= 0 || title.OurNumber > bank.maxOurNumber() {
+			return nil, fmt.Errorf("boleto: invalid our number %d", title.OurNumber)
+		}
+		if amount := title.Amount.Round(2); amount.Sign() <= 0 || amount.Cmp(maxAmount) > 0 {
+			return nil, fmt.Errorf("boleto: invalid amount for our number %d", title.OurNumber)
+		}
+	}
+
+	var records []record
+	switch remittance.Layout {
+	case Layout240:
+		records = remittance240(bank, remittance)
+	case Layout400:
+		records = remittance400(bank, remittance)
+	default:
+		return nil, fmt.Errorf("boleto: unknown layout %q", remittance.Layout)
+	}
+	var b bytes.Buffer
+	for _, r := range records {
+		b.Write(r)
+		b.WriteString("\r\n")
+	}
+	return b.Bytes(), nil
+}
+
+// Return é um arquivo de retorno lido por ParseReturn.
+type Return struct {
+	Layout   string
+	BankCode string
+	Lines    []ReturnLine
+}
+
+// ReturnLine é o movimento de um boleto no retorno.
+type ReturnLine struct {
+	// Line é a linha do arquivo (no CNAB 240, a do segmento T).
+	Line          int
+	OurNumber     int64
+	ControlNumber string
+	// DocumentNumber é o "seu número" informado na remessa.
+	DocumentNumber string
+	Occurrence     string
+	OccurrenceDate *time.Time
+	CreditDate     *time.Time
+	Amount         decimal.Decimal
+	PaidAmount     decimal.Decimal
+	Interest       decimal.Decimal
+	Discount       decimal.Decimal
+	// Problem explica por que a linha não pôde ser lida; os demais campos podem estar incompletos.
+	Problem string
+
+	// occurrences é a tabela de ocorrências do layout e do banco do arquivo.
+	occurrences map[string]string
+}
+
+// Kind classifica a ocorrência da linha (OccurrenceSettled, OccurrenceRejected...) pela tabela do
+// layout e do banco do arquivo.
+func (l ReturnLine) Kind() string {
+	if kind, ok := l.occurrences[l.Occurrence]; ok {
+		return kind
+	}
+	return OccurrenceOther
+}
+
+// check registra o primeiro campo que não pôde ser lido.
+func (l *ReturnLine) check(ok bool, name string) {
+	if !ok && l.Problem == "" {
+		l.Problem = "unreadable " + name
+	}
+}
+
+// ParseReturn lê um arquivo de retorno CNAB 240 ou 400, identificado pelo tamanho das linhas.
+func ParseReturn(data []byte) (*Return, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	lines := strings.Split(strings.TrimRight(text, "\n\x1a"), "\n")
+	if len(lines) < 2 {
+		return nil, ErrInvalidReturnFile
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, "\r")
+	}
+	var ret *Return
+	var err error
+	switch len(lines[0]) {
+	case 240:
+		ret, err = parseReturn240(lines)
+	case 400:
+		ret, err = parseReturn400(lines)
+	default:
+		return nil, ErrInvalidReturnFile
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Uma liquidação sem o valor pago não pode baixar a parcela: o valor do título não é o que entrou.
+	for i := range ret.Lines {
+		if line := &ret.Lines[i]; line.Kind() == OccurrenceSettled {
+			line.check(!line.PaidAmount.IsZero(), "paid amount")
+		}
+	}
+	return ret, nil
+}
+
+// record é uma linha de tamanho fixo do CNAB, preenchida com brancos. As posições dos métodos são as
+// dos manuais: começam em 1 e incluem o fim.
+type record []byte
+
+func newRecord(size int) record {
+	return record(bytes.Repeat([]byte{' '}, size))
+}
+
+// alpha grava um campo alfanumérico: maiúsculo, sem acentos, alinhado à esquerda e cortado no tamanho.
+func (r record) alpha(start, end int, value string) {
+	value = cnabText(value)
+	size := end - start + 1
+	if len(value) > size {
+		value = value[:size]
+	}
+	copy(r[start-1:end], value+strings.Repeat(" ", size-len(value)))
+}
+
+// num grava um campo numérico: só os dígitos de value, com zeros à esquerda. Se sobrarem dígitos,
+// ficam os da direita.
+func (r record) num(start, end int, value string) {
+	digits := strings.Map(func(c rune) rune {
+		if c >= '0' && c <= '9' {
+			return c
+		}
+		return -1
+	}, value)
+	size := end - start + 1
+	if len(digits) > size {
+		digits = digits[len(digits)-size:]
+	}
+	copy(r[start-1:end], pad(digits, size))
+}
+
+func (r record) amount(start, end int, value decimal.Decimal) {
+	r.num(start, end, cents(value.Round(2), end-start+1))
+}
+
+// date grava a data como DDMMAA (6 posições) ou DDMMAAAA (8 posições).
+func (r record) date(start, end int, value time.Time) {
+	if end-start+1 == 6 {
+		r.num(start, end, value.Format("020106"))
+		return
+	}
+	r.num(start, end, value.Format("02012006"))
+}
+
+// cnabText deixa o texto só com ASCII maiúsculo, sem acentos, como os bancos aceitam.
+func cnabText(value string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), value)
+	if err != nil {
+		folded = value
+	}
+	folded = strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7E {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(folded))
+	return strings.Join(strings.Fields(folded), " ")
+}
+
+// inscriptionType é o tipo de inscrição do CNAB: 1 para CPF e 2 para CNPJ.
+func inscriptionType(cgc string) string {
+	if len(onlyDigits(cgc)) == 14 {
+		return "2"
+	}
+	return "1"
+}
+
+func onlyDigits(value string) string {
+	return strings.Map(func(c rune) rune {
+		if c >= '0' && c <= '9' {
+			return c
+		}
+		return -1
+	}, value)
+}
+
+// field lê as posições start a end (a partir de 1) de line, sem os brancos das pontas.
+func field(line string, start, end int) string {
+	if len(line) < end {
+		return ""
+	}
+	return strings.TrimSpace(line[start-1 : end])
+}
+
+func parseDigits(value string) (int64, bool) {
+	value = strings.TrimSpace(value)
+	if !isDigits(value) {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	return n, err == nil
+}
+
+// parseAmount lê um valor em centavos.
+func parseAmount(value string) (decimal.Decimal, bool) {
+	n, ok := parseDigits(value)
+	if !ok {
+		return decimal.Zero, false
+	}
+	return decimal.FromInt(n).Mul(decimal.MustParse("0.01")), true
+}
+
+// parseDate lê DDMMAA ou DDMMAAAA; zeros ou brancos são uma data ausente.
+func parseDate(value string) (*time.Time, bool) {
+	if strings.Trim(value, "0 ") == "" {
+		return nil, true
+	}
+	layout := "020106"
+	if len(value) == 8 {
+		layout = "02012006"
+	}
+	date, err := time.Parse(layout, value)
+	if err != nil {
+		return nil, false
+	}
+	return &date, true
+}
@@ -0,0 +1,197 @@
+package boleto
+
+import (
+	"strconv"
+
+	"go-sales/pkg/decimal"
+)
+
+// remittance240 monta a remessa no CNAB 240 da FEBRABAN: header de arquivo, um lote de cobrança com
+// os segmentos P (título) e Q (pagador) de cada boleto, trailer de lote e trailer de arquivo.
+func remittance240(bank Bank, remittance Remittance) []record {
+	account, beneficiary := remittance.Account, remittance.Beneficiary
+	fileVersion, batchVersion := bank.version240()
+	records := make([]record, 0, 2*len(remittance.Titles)+4)
+
+	header := newRecord(240)
+	header.num(1, 3, bank.Code())
+	header.num(4, 7, "0000")
+	header.num(8, 8, "0")
+	header.num(18, 18, inscriptionType(beneficiary.CGC))
+	header.num(19, 32, beneficiary.CGC)
+	header.alpha(33, 52, account.Agreement)
+	header.num(53, 57, account.Agency)
+	header.alpha(58, 58, account.AgencyDigit)
+	header.num(59, 70, account.Number)
+	header.alpha(71, 71, account.NumberDigit)
+	header.alpha(73, 102, beneficiary.Name)
+	header.alpha(103, 132, bank.Name())
+	header.num(143, 143, "1")
+	header.date(144, 151, remittance.CreatedAt)
+	header.num(152, 157, remittance.CreatedAt.Format("150405"))
+	header.num(158, 163, strconv.Itoa(remittance.Sequence))
+	header.num(164, 166, fileVersion)
+	header.num(167, 171, "0")
+	records = append(records, header)
+
+	batch := newRecord(240)
+	batch.num(1, 3, bank.Code())
+	batch.num(4, 7, "1")
+	batch.num(8, 8, "1")
+	batch.alpha(9, 9, "R")
+	batch.num(10, 11, "01")
+	batch.num(14, 16, batchVersion)
+	batch.num(18, 18, inscriptionType(beneficiary.CGC))
+	batch.num(19, 33, beneficiary.CGC)
+	batch.alpha(34, 53, account.Agreement)
+	batch.num(54, 58, account.Agency)
+	batch.alpha(59, 59, account.AgencyDigit)
+	batch.num(60, 71, account.Number)
+	batch.alpha(72, 72, account.NumberDigit)
+	batch.alpha(74, 103, beneficiary.Name)
+	batch.num(184, 191, strconv.Itoa(remittance.Sequence))
+	batch.date(192, 199, remittance.CreatedAt)
+	batch.num(200, 207, "0")
+	records = append(records, batch)
+
+	total := decimal.Zero
+	for i, title := range remittance.Titles {
+		issueDate := title.IssueDate
+		if issueDate.IsZero() {
+			issueDate = remittance.CreatedAt
+		}
+		total = total.Add(title.Amount.Round(2))
+
+		p := newRecord(240)
+		p.num(1, 3, bank.Code())
+		p.num(4, 7, "1")
+		p.num(8, 8, "3")
+		p.num(9, 13, strconv.Itoa(2*i+1))
+		p.alpha(14, 14, "P")
+		p.num(16, 17, "01")
+		p.num(18, 22, account.Agency)
+		p.alpha(23, 23, account.AgencyDigit)
+		p.num(24, 35, account.Number)
+		p.alpha(36, 36, account.NumberDigit)
+		p.alpha(38, 57, bank.ourNumber240(account, title.OurNumber))
+		// Cobrança simples, título registrado, boleto emitido e distribuído pelo beneficiário.
+		p.num(58, 62, "11122")
+		p.alpha(63, 77, title.DocumentNumber)
+		p.date(78, 85, title.DueDate)
+		p.amount(86, 100, title.Amount)
+		p.num(101, 106, "0")
+		p.num(107, 108, "02") // duplicata mercantil
+		p.alpha(109, 109, "N")
+		p.date(110, 117, issueDate)
+		p.num(118, 118, "3") // sem juros
+		p.num(119, 141, "0")
+		p.num(142, 142, "0") // sem desconto
+		p.num(143, 195, "0")
+		p.alpha(196, 220, title.ControlNumber)
+		p.num(221, 223, "300") // não protestar
+		p.num(224, 227, "0")
+		p.num(228, 229, "09") // real
+		p.num(230, 239, "0")
+		records = append(records, p)
+
+		q := newRecord(240)
+		q.num(1, 3, bank.Code())
+		q.num(4, 7, "1")
+		q.num(8, 8, "3")
+		q.num(9, 13, strconv.Itoa(2*i+2))
+		q.alpha(14, 14, "Q")
+		q.num(16, 17, "01")
+		q.num(18, 18, inscriptionType(title.Payer.CGC))
+		q.num(19, 33, title.Payer.CGC)
+		q.alpha(34, 73, title.Payer.Name)
+		q.alpha(74, 113, title.Payer.Street)
+		q.alpha(114, 128, title.Payer.District)
+		q.num(129, 136, title.Payer.PostalCode)
+		q.alpha(137, 151, title.Payer.City)
+		q.alpha(152, 153, title.Payer.UF)
+		q.num(154, 169, "0")
+		q.num(210, 212, "0")
+		records = append(records, q)
+	}
+
+	trailer := newRecord(240)
+	trailer.num(1, 3, bank.Code())
+	trailer.num(4, 7, "1")
+	trailer.num(8, 8, "5")
+	trailer.num(18, 23, strconv.Itoa(2*len(remittance.Titles)+2))
+	trailer.num(24, 29, strconv.Itoa(len(remittance.Titles)))
+	trailer.amount(30, 46, total)
+	records = append(records, trailer)
+
+	fileTrailer := newRecord(240)
+	fileTrailer.num(1, 3, bank.Code())
+	fileTrailer.num(4, 7, "9999")
+	fileTrailer.num(8, 8, "9")
+	fileTrailer.num(18, 23, "1")
+	fileTrailer.num(24, 29, strconv.Itoa(len(records)+1))
+	fileTrailer.num(30, 35, "0")
+	return append(records, fileTrailer)
+}
+
+// parseReturn240 lê o retorno CNAB 240: cada boleto vem num segmento T seguido do segmento U.
+func parseReturn240(lines []string) (*Return, error) {
+	header := lines[0]
+	if field(header, 8, 8) != "0" || field(header, 143, 143) != "2" {
+		return nil, ErrInvalidReturnFile
+	}
+	bank, err := BankByCode(field(header, 1, 3))
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &Return{Layout: Layout240, BankCode: bank.Code()}
+	var current *ReturnLine
+	for i, line := range lines[1:] {
+		number := i + 2
+		if len(line) != 240 || field(line, 8, 8) != "3" {
+			continue
+		}
+		switch field(line, 14, 14) {
+		case "T":
+			ret.Lines = append(ret.Lines, readSegmentT(bank, line, number))
+			current = &ret.Lines[len(ret.Lines)-1]
+		case "U":
+			if current == nil {
+				continue
+			}
+			readSegmentU(current, line)
+			current = nil
+		}
+	}
+	return ret, nil
+}
+
+func readSegmentT(bank Bank, line string, number int) ReturnLine {
+	l := ReturnLine{
+		Line:           number,
+		Occurrence:     field(line, 16, 17),
+		ControlNumber:  field(line, 106, 130),
+		DocumentNumber: field(line, 59, 73),
+		occurrences:    bank.occurrences(Layout240),
+	}
+	var ok bool
+	l.OurNumber, ok = bank.parseOurNumber240(field(line, 38, 57))
+	l.check(ok, "our number")
+	l.Amount, ok = parseAmount(field(line, 82, 96))
+	l.check(ok, "amount")
+	return l
+}
+
+func readSegmentU(l *ReturnLine, line string) {
+	var ok bool
+	l.Interest, ok = parseAmount(field(line, 18, 32))
+	l.check(ok, "interest")
+	l.Discount, ok = parseAmount(field(line, 33, 47))
+	l.check(ok, "discount")
+	l.PaidAmount, ok = parseAmount(field(line, 78, 92))
+	l.check(ok, "paid amount")
+	l.OccurrenceDate, ok = parseDate(field(line, 138, 145))
+	l.check(ok, "occurrence date")
+	l.CreditDate, ok = parseDate(field(line, 146, 153))
+	l.check(ok, "credit date")
+}
@@ -0,0 +1,95 @@
+package boleto
+
+import (
+	"strconv"
+)
+
+// remittance400 monta a remessa no CNAB 400: header, um detalhe por boleto (com os campos próprios
+// do banco) e trailer, numerados em sequência nas posições 395 a 400.
+func remittance400(bank Bank, remittance Remittance) []record {
+	records := make([]record, 0, len(remittance.Titles)+2)
+
+	header := newRecord(400)
+	header.num(1, 1, "0")
+	header.num(2, 2, "1")
+	header.alpha(3, 9, "REMESSA")
+	header.num(10, 11, "01")
+	bank.header400(header, remittance.Account, remittance.Beneficiary, remittance.Sequence)
+	header.date(95, 100, remittance.CreatedAt)
+	header.num(395, 400, "1")
+	records = append(records, header)
+
+	for _, title := range remittance.Titles {
+		issueDate := title.IssueDate
+		if issueDate.IsZero() {
+			issueDate = remittance.CreatedAt
+		}
+		r := newRecord(400)
+		r[0] = bank.detailType400()
+		bank.detail400(r, remittance.Account, remittance.Beneficiary, title)
+		r.num(109, 110, "01") // entrada de título
+		r.alpha(111, 120, title.DocumentNumber)
+		r.date(121, 126, title.DueDate)
+		r.amount(127, 139, title.Amount)
+		r.num(148, 149, "01") // duplicata mercantil
+		r.alpha(150, 150, "N")
+		r.date(151, 156, issueDate)
+		r.num(157, 218, "0")
+		r.num(219, 220, inscriptionType(title.Payer.CGC))
+		r.num(221, 234, title.Payer.CGC)
+		r.num(395, 400, strconv.Itoa(len(records)+1))
+		records = append(records, r)
+	}
+
+	trailer := newRecord(400)
+	trailer.num(1, 1, "9")
+	trailer.num(395, 400, strconv.Itoa(len(records)+1))
+	return append(records, trailer)
+}
+
+// parseReturn400 lê o retorno CNAB 400: os detalhes do tipo do banco, entre o header e o trailer.
+func parseReturn400(lines []string) (*Return, error) {
+	header := lines[0]
+	if field(header, 1, 2) != "02" {
+		return nil, ErrInvalidReturnFile
+	}
+	bank, err := BankByCode(field(header, 77, 79))
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &Return{Layout: Layout400, BankCode: bank.Code()}
+	for i, line := range lines[1:] {
+		if len(line) != 400 || line[0] != bank.detailType400() {
+			continue
+		}
+		ret.Lines = append(ret.Lines, readDetail400(bank, line, i+2))
+	}
+	return ret, nil
+}
+
+func readDetail400(bank Bank, line string, number int) ReturnLine {
+	ourNumber, ok, control, creditDate := bank.parse400(line)
+	l := ReturnLine{
+		Line:           number,
+		OurNumber:      ourNumber,
+		ControlNumber:  control,
+		DocumentNumber: field(line, 117, 126),
+		Occurrence:     field(line, 109, 110),
+		occurrences:    bank.occurrences(Layout400),
+	}
+	l.check(ok, "our number")
+	l.OccurrenceDate, ok = parseDate(field(line, 111, 116))
+	l.check(ok, "occurrence date")
+	l.CreditDate, ok = parseDate(creditDate)
+	l.check(ok, "credit date")
+	l.Amount, ok = parseAmount(field(line, 153, 165))
+	l.check(ok, "amount")
+	l.Discount, ok = parseAmount(field(line, 241, 253))
+	l.check(ok, "discount")
+	l.PaidAmount, ok = parseAmount(field(line, 254, 266))
+	l.check(ok, "paid amount")
+	l.Interest, ok = parseAmount(field(line, 267, 279))
+	l.check(ok, "interest")
+	return l
+}
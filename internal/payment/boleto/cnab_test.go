@@ -0,0 +1,83 @@
+package boleto
+
+import "testing"
+
+func TestReturnLineKind(t *testing.T) {
+	bb, bradesco := Banks["001"], Banks["237"]
+	tests := []struct {
+		name       string
+		bank       Bank
+		layout     string
+		occurrence string
+		want       string
+	}{
+		{"CNAB 240 liquidação", bb, Layout240, "06", OccurrenceSettled},
+		{"CNAB 240 liquidação após baixa", bradesco, Layout240, "17", OccurrenceSettled},
+		{"CNAB 240 confirmação de desconto", bradesco, Layout240, "07", OccurrenceOther},
+		{"CNAB 240 transferência de carteira", bb, Layout240, "05", OccurrenceOther},
+		{"CNAB 240 baixa", bb, Layout240, "09", OccurrenceWrittenOff},
+		{"BB 400 liquidação por conta", bb, Layout400, "07", OccurrenceSettled},
+		{"BB 400 liquidação por saldo", bb, Layout400, "08", OccurrenceSettled},
+		{"Bradesco 400 abatimento cancelado", bradesco, Layout400, "13", OccurrenceOther},
+		{"Bradesco 400 pago em cheque", bradesco, Layout400, "16", OccurrenceOther},
+		{"Bradesco 400 liquidação após baixa", bradesco, Layout400, "17", OccurrenceSettled},
+		{"Bradesco 400 CEP irregular", bradesco, Layout400, "24", OccurrenceRejected},
+		{"Bradesco 400 códigos do BB", bradesco, Layout400, "07", OccurrenceOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := ReturnLine{Occurrence: tt.occurrence, occurrences: tt.bank.occurrences(tt.layout)}
+			if got := line.Kind(); got != tt.want {
+				t.Fatalf("Kind() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// bradescoReturn400 monta um retorno CNAB 400 do Bradesco com um detalhe de ocorrência occurrence.
+func bradescoReturn400(occurrence, paidAmount string) []byte {
+	header := newRecord(400)
+	header.num(1, 2, "02")
+	header.num(77, 79, "237")
+	detail := newRecord(400)
+	detail.num(1, 1, "1")
+	detail.num(71, 81, "123")
+	detail.num(109, 110, occurrence)
+	detail.num(111, 116, "100325")
+	detail.num(153, 165, "15000")
+	detail.num(241, 253, "0")
+	detail.num(254, 266, paidAmount)
+	detail.num(267, 279, "0")
+	detail.num(296, 301, "110325")
+	trailer := newRecord(400)
+	trailer.num(1, 1, "9")
+	return []byte(string(header) + "\r\n" + string(detail) + "\r\n" + string(trailer) + "\r\n")
+}
+
+func TestParseReturnSettlementWithoutPaidAmount(t *testing.T) {
+	ret, err := ParseReturn(bradescoReturn400("06", "0"))
+	if err != nil {
+		t.Fatalf("ParseReturn: %v", err)
+	}
+	if got := ret.Lines[0].Problem; got != "unreadable paid amount" {
+		t.Fatalf("Problem = %q, want unreadable paid amount", got)
+	}
+
+	ret, err = ParseReturn(bradescoReturn400("06", "14950"))
+	if err != nil {
+		t.Fatalf("ParseReturn: %v", err)
+	}
+	line := ret.Lines[0]
+	if line.Problem != "" || line.Kind() != OccurrenceSettled || line.PaidAmount.String() != "149.5" {
+		t.Fatalf("line = %+v, want a settlement of 149.50", line)
+	}
+
+	// Ocorrências que não são liquidação não trazem valor pago.
+	ret, err = ParseReturn(bradescoReturn400("02", "0"))
+	if err != nil {
+		t.Fatalf("ParseReturn: %v", err)
+	}
+	if ret.Lines[0].Problem != "" {
+		t.Fatalf("Problem of a registration = %q, want none", ret.Lines[0].Problem)
+	}
+}
@@ -0,0 +1,83 @@
+package boleto
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dueFactorBase é a data-base do fator de vencimento (fator 0).
+var dueFactorBase = time.Date(1997, time.October, 7, 0, 0, 0, 0, time.UTC)
+
+// DueFactor calcula o fator de vencimento (4 dígitos) do código de barras: os dias desde 07/10/1997.
+// Depois do fator 9999 (21/02/2025) a contagem recomeça em 1000, a cada 9000 dias.
+func DueFactor(due time.Time) (int, error) {
+	due = time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(due.Sub(dueFactorBase).Hours() / 24)
+	if days < 1000 {
+		return 0, errors.New("boleto: due date before 03/07/2000")
+	}
+	if days > 9999 {
+		days = (days-10000)%9000 + 1000
+	}
+	return days, nil
+}
+
+// mod10 é o dígito verificador módulo 10 dos campos da linha digitável: pesos 2 e 1 alternados a
+// partir da direita, somando os algarismos dos produtos.
+func mod10(digits string) int {
+	sum, weight := 0, 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		product := int(digits[i]-'0') * weight
+		sum += product/10 + product%10
+		weight = 3 - weight
+	}
+	return (10 - sum%10) % 10
+}
+
+// mod11 soma os dígitos com pesos de 2 a maxWeight a partir da direita e devolve o resto da divisão
+// por 11.
+func mod11(digits string, maxWeight int) int {
+	sum, weight := 0, 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		if weight++; weight > maxWeight {
+			weight = 2
+		}
+	}
+	return sum % 11
+}
+
+// barcodeDigit é o dígito geral do código de barras (módulo 11, pesos 2 a 9). 0, 10 e 11 viram 1.
+func barcodeDigit(digits string) int {
+	dv := 11 - mod11(digits, 9)
+	if dv == 0 || dv == 10 || dv == 11 {
+		return 1
+	}
+	return dv
+}
+
+// pad completa value com zeros à esquerda até size dígitos.
+func pad(value string, size int) string {
+	if len(value) >= size {
+		return value
+	}
+	return strings.Repeat("0", size-len(value)) + value
+}
+
+func padInt(value int64, size int) string {
+	return pad(strconv.FormatInt(value, 10), size)
+}
+
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,240 @@
+// Package pix monta e lê o payload "copia e cola" do PIX (BR Code, no padrão EMV QRCPS-MPM do Manual de
+// Padrões para Iniciação do Pix do Banco Central) e define a integração com o PSP que recebe os
+// pagamentos.
+package pix
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"go-sales/pkg/decimal"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrInvalidPayload é retornado por Decode quando o texto não é um BR Code de PIX válido.
+var ErrInvalidPayload = errors.New("pix: invalid payload")
+
+// IDs dos campos do BR Code usados pelo PIX.
+const (
+	idPayloadFormat     = "00"
+	idInitiationMethod  = "01"
+	idMerchantAccount   = "26"
+	idCategoryCode      = "52"
+	idCurrency          = "53"
+	idAmount            = "54"
+	idCountry           = "58"
+	idMerchantName      = "59"
+	idMerchantCity      = "60"
+	idAdditionalData    = "62"
+	idCRC               = "63"
+	idAccountGUI        = "00"
+	idAccountKey        = "01"
+	idAccountInfo       = "02"
+	idAccountURL        = "25"
+	idAdditionalTxID    = "05"
+	gui                 = "br.gov.bcb.pix"
+	currencyBRL         = "986"
+	initiationSingleUse = "12"
+	// noTxID é o txid dos QR Codes estáticos sem identificador e de todos os dinâmicos (o txid do
+	// dinâmico fica na cobrança, no PSP).
+	noTxID = "***"
+)
+
+// Limites de tamanho do manual.
+const (
+	MaxMerchantName = 25
+	MaxMerchantCity = 15
+	// MaxStaticTxID é o tamanho máximo do txid de um QR Code estático.
+	MaxStaticTxID = 25
+)
+
+// Payload é o conteúdo de um BR Code. Um payload é estático quando tem Key e dinâmico quando tem URL
+// (a location da cobrança no PSP, sem o "https://").
+type Payload struct {
+	Key         string
+	Description string
+	URL         string
+	// Amount zero gera um QR Code em que o pagador informa o valor.
+	Amount       decimal.Decimal
+	MerchantName string
+	MerchantCity string
+	// TxID identifica o pagamento no QR Code estático; vazio vira "***".
+	TxID string
+}
+
+// Dynamic diz se o payload aponta para uma cobrança no PSP.
+func (p *Payload) Dynamic() bool {
+	return p.URL != ""
+}
+
+// Encode monta o texto "copia e cola", terminado pelo CRC16 do campo 63.
+func (p *Payload) Encode() (string, error) {
+	if (p.Key == "") == (p.URL == "") {
+		return "", errors.New("pix: payload needs either a key or a URL")
+	}
+	if p.Amount.Sign() < 0 {
+		return "", errors.New("pix: amount cannot be negative")
+	}
+	name, city := truncate(MerchantText(p.MerchantName), MaxMerchantName), truncate(MerchantText(p.MerchantCity), MaxMerchantCity)
+	if name == "" || city == "" {
+		return "", errors.New("pix: merchant name and city are required")
+	}
+	txID := p.TxID
+	if txID == "" || p.Dynamic() {
+		txID = noTxID
+	} else if len(txID) > MaxStaticTxID || !isAlphanumeric(txID) {
+		return "", fmt.Errorf("pix: txid must have up to %d letters and digits", MaxStaticTxID)
+	}
+
+	account := field(idAccountGUI, gui)
+	if p.Dynamic() {
+		account += field(idAccountURL, p.URL)
+	} else {
+		account += field(idAccountKey, p.Key)
+		// A descrição ocupa o que sobra dos 99 caracteres do campo 26 e é cortada se não couber.
+		if description := truncate(MerchantText(p.Description), 99-len(account)-4); description != "" {
+			account += field(idAccountInfo, description)
+		}
+	}
+	if len(account) > 99 {
+		return "", errors.New("pix: key or URL too long")
+	}
+
+	var b strings.Builder
+	b.WriteString(field(idPayloadFormat, "01"))
+	if p.Dynamic() {
+		b.WriteString(field(idInitiationMethod, initiationSingleUse))
+	}
+	b.WriteString(field(idMerchantAccount, account))
+	b.WriteString(field(idCategoryCode, "0000"))
+	b.WriteString(field(idCurrency, currencyBRL))
+	if !p.Amount.IsZero() {
+		b.WriteString(field(idAmount, p.Amount.StringFixed(2)))
+	}
+	b.WriteString(field(idCountry, "BR"))
+	b.WriteString(field(idMerchantName, name))
+	b.WriteString(field(idMerchantCity, city))
+	b.WriteString(field(idAdditionalData, field(idAdditionalTxID, txID)))
+	b.WriteString(idCRC + "04")
+	payload := b.String()
+	if len(payload)+4 > 512 {
+		return "", errors.New("pix: payload longer than 512 characters")
+	}
+	return payload + fmt.Sprintf("%04X", CRC16(payload)), nil
+}
+
+// Decode lê um BR Code de PIX e confere o CRC.
+func Decode(text string) (*Payload, error) {
+	if len(text) < 8 || text[len(text)-8:len(text)-4] != idCRC+"04" {
+		return nil, ErrInvalidPayload
+	}
+	crc, err := strconv.ParseUint(text[len(text)-4:], 16, 16)
+	if err != nil || uint16(crc) != CRC16(text[:len(text)-4]) {
+		return nil, ErrInvalidPayload
+	}
+	fields, err := parseFields(text[:len(text)-8])
+	if err != nil || fields[idPayloadFormat] != "01" {
+		return nil, ErrInvalidPayload
+	}
+	account, err := parseFields(fields[idMerchantAccount])
+	if err != nil || !strings.EqualFold(account[idAccountGUI], gui) {
+		return nil, ErrInvalidPayload
+	}
+	p := &Payload{
+		Key:          account[idAccountKey],
+		Description:  account[idAccountInfo],
+		URL:          account[idAccountURL],
+		MerchantName: fields[idMerchantName],
+		MerchantCity: fields[idMerchantCity],
+	}
+	if raw, ok := fields[idAmount]; ok {
+		if p.Amount, err = decimal.Parse(raw); err != nil {
+			return nil, ErrInvalidPayload
+		}
+	}
+	if additional, err := parseFields(fields[idAdditionalData]); err == nil && additional[idAdditionalTxID] != noTxID {
+		p.TxID = additional[idAdditionalTxID]
+	}
+	if (p.Key == "") == (p.URL == "") {
+		return nil, ErrInvalidPayload
+	}
+	return p, nil
+}
+
+// field codifica um campo ID + tamanho (2 dígitos) + valor.
+func field(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+func parseFields(text string) (map[string]string, error) {
+	fields := make(map[string]string)
+	for len(text) > 0 {
+		if len(text) < 4 {
+			return nil, ErrInvalidPayload
+		}
+		size, err := strconv.Atoi(text[2:4])
+		if err != nil || len(text) < 4+size {
+			return nil, ErrInvalidPayload
+		}
+		fields[text[:2]] = text[4 : 4+size]
+		text = text[4+size:]
+	}
+	return fields, nil
+}
+
+// CRC16 é o CRC-16/CCITT-FALSE (polinômio 0x1021, valor inicial 0xFFFF) exigido no campo 63.
+func CRC16(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// MerchantText adapta nomes e cidades ao BR Code: sem acentos, só com caracteres ASCII imprimíveis e
+// com espaços simples ("São  Paulo" vira "Sao Paulo").
+func MerchantText(value string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), value)
+	if err != nil {
+		folded = value
+	}
+	folded = strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7E {
+			return -1
+		}
+		return r
+	}, folded)
+	return strings.Join(strings.Fields(folded), " ")
+}
+
+func truncate(value string, size int) string {
+	if size <= 0 {
+		return ""
+	}
+	if len(value) > size {
+		return strings.TrimSpace(value[:size])
+	}
+	return value
+}
+
+func isAlphanumeric(value string) bool {
+	for _, r := range value {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
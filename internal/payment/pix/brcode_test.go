@@ -0,0 +1,53 @@
+package pix
+
+import (
+	"errors"
+	"testing"
+)
+
+// sampleBRCode é o BR Code estático de exemplo do Manual de Padrões para Iniciação do Pix, com o CRC
+// publicado (1D3D).
+const sampleBRCode = "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D"
+
+func TestCRC16(t *testing.T) {
+	tests := []struct {
+		data string
+		want uint16
+	}{
+		// Valor de conferência do CRC-16/CCITT-FALSE no catálogo de CRCs.
+		{"123456789", 0x29B1},
+		{sampleBRCode[:len(sampleBRCode)-4], 0x1D3D},
+	}
+	for _, tt := range tests {
+		if got := CRC16(tt.data); got != tt.want {
+			t.Errorf("CRC16(%q) = %04X, want %04X", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeSample(t *testing.T) {
+	payload := Payload{Key: "123e4567-e12b-12d1-a456-426655440000", MerchantName: "Fulano de Tal", MerchantCity: "BRASILIA"}
+
+	got, err := payload.Encode()
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if got != sampleBRCode {
+		t.Fatalf("Encode = %s, want %s", got, sampleBRCode)
+	}
+}
+
+func TestDecodeSample(t *testing.T) {
+	payload, err := Decode(sampleBRCode)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if payload.Key != "123e4567-e12b-12d1-a456-426655440000" || payload.MerchantName != "Fulano de Tal" || payload.MerchantCity != "BRASILIA" || payload.TxID != "" {
+		t.Fatalf("Decode = %+v", payload)
+	}
+
+	tampered := sampleBRCode[:len(sampleBRCode)-4] + "1D3E"
+	if _, err := Decode(tampered); !errors.Is(err, ErrInvalidPayload) {
+		t.Fatalf("Decode with a wrong CRC = %v, want ErrInvalidPayload", err)
+	}
+}
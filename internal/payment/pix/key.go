@@ -0,0 +1,102 @@
+package pix
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"go-sales/pkg/util"
+)
+
+// ErrInvalidKey é retornado por ParseKey quando o texto não é uma chave PIX válida.
+var ErrInvalidKey = errors.New("pix: invalid key")
+
+// Tipos de chave PIX.
+const (
+	KeyCPF   = "cpf"
+	KeyCNPJ  = "cnpj"
+	KeyEmail = "email"
+	KeyPhone = "phone"
+	// KeyRandom é a chave aleatória (EVP), um UUID gerado pelo PSP.
+	KeyRandom = "random"
+)
+
+var (
+	phoneKey  = regexp.MustCompile(`^\+55[1-9][0-9]{9,10}$`)
+	randomKey = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+)
+
+// ParseKey identifica o tipo da chave e a devolve no formato do DICT: CPF e CNPJ só com dígitos,
+// e-mail e chave aleatória em minúsculas e telefone como +55DDDNÚMERO.
+func ParseKey(key string) (normalized, keyType string, err error) {
+	key = strings.TrimSpace(key)
+	lower := strings.ToLower(key)
+	switch {
+	case randomKey.MatchString(lower):
+		return lower, KeyRandom, nil
+	case phoneKey.MatchString(key):
+		return key, KeyPhone, nil
+	case strings.Contains(key, "@"):
+		address, err := mail.ParseAddress(key)
+		if err != nil || address.Address != key || len(key) > 77 {
+			return "", "", ErrInvalidKey
+		}
+		return lower, KeyEmail, nil
+	}
+	digits := util.OnlyDigits(key)
+	if strings.Trim(key, "0123456789.-/ ") != "" {
+		return "", "", ErrInvalidKey
+	}
+	switch {
+	case len(digits) == 11 && validDocument(digits, 11):
+		return digits, KeyCPF, nil
+	case len(digits) == 14 && validDocument(digits, 9):
+		return digits, KeyCNPJ, nil
+	}
+	return "", "", ErrInvalidKey
+}
+
+// validDocument confere os dois dígitos verificadores (módulo 11) de um CPF ou CNPJ. Os pesos vão
+// de 2 a maxWeight a partir do dígito mais à direita e recomeçam em 2 (no CNPJ, maxWeight é 9).
+func validDocument(digits string, maxWeight int) bool {
+	if strings.Count(digits, digits[:1]) == len(digits) {
+		return false
+	}
+	for _, position := range []int{len(digits) - 2, len(digits) - 1} {
+		sum, weight := 0, 2
+		for i := position - 1; i >= 0; i-- {
+			sum += int(digits[i]-'0') * weight
+			if weight++; weight > maxWeight {
+				weight = 2
+			}
+		}
+		check := 11 - sum%11
+		if check >= 10 {
+			check = 0
+		}
+		if int(digits[position]-'0') != check {
+			return false
+		}
+	}
+	return true
+}
+
+const txIDAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// NewTxID gera um txid aleatório com size letras e dígitos. Cobranças dinâmicas exigem de 26 a 35
+// caracteres; QR Codes estáticos aceitam até 25.
+func NewTxID(size int) (string, error) {
+	b := make([]byte, size)
+	max := big.NewInt(int64(len(txIDAlphabet)))
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = txIDAlphabet[n.Int64()]
+	}
+	return string(b), nil
+}
@@ -0,0 +1,124 @@
+package pix
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"go-sales/pkg/decimal"
+)
+
+// ErrInvalidNotification é retornado por ParseNotification quando a notificação não é autêntica ou
+// não pode ser lida.
+var ErrInvalidNotification = errors.New("pix: invalid notification")
+
+// Charge é a cobrança imediata (cob) registrada no PSP para um QR Code dinâmico.
+type Charge struct {
+	TxID   string
+	Key    string
+	Amount decimal.Decimal
+	// Expiration é a validade da cobrança a partir da criação.
+	Expiration  time.Duration
+	PayerName   string
+	PayerCGC    string
+	Description string
+}
+
+// Location é o endereço do payload da cobrança, que vai no campo 25 do BR Code.
+type Location struct {
+	URL       string
+	ExpiresAt time.Time
+}
+
+// Notification é um PIX recebido, como o PSP o informa no webhook.
+type Notification struct {
+	EndToEndID string
+	TxID       string
+	Amount     decimal.Decimal
+	PaidAt     time.Time
+	PayerInfo  string
+}
+
+// Provider é a integração com o PSP: o registro das cobranças dos QR Codes dinâmicos e a leitura das
+// notificações de pagamento recebidas no webhook.
+type Provider interface {
+	CreateCharge(ctx context.Context, charge Charge) (*Location, error)
+	// ParseNotification autentica a requisição do webhook e devolve os PIX recebidos.
+	ParseNotification(header http.Header, body []byte) ([]Notification, error)
+}
+
+// SignatureHeader é o header com o HMAC-SHA256 (hex) do corpo das notificações do FakeProvider.
+const SignatureHeader = "X-Pix-Signature"
+
+// FakeProvider é o PSP local, para desenvolvimento: cria as cobranças só em memória e aceita
+// notificações no formato do webhook da API Pix do Banco Central ({"pix": [...]}), assinadas com
+// Secret em SignatureHeader. Sem Secret nenhuma notificação é aceita.
+type FakeProvider struct {
+	Secret string
+	// BaseURL é o prefixo das locations geradas, sem o "https://".
+	BaseURL string
+}
+
+func NewFakeProvider(secret string) *FakeProvider {
+	return &FakeProvider{Secret: secret, BaseURL: "pix.example.com/qr/v2"}
+}
+
+func (p *FakeProvider) CreateCharge(_ context.Context, charge Charge) (*Location, error) {
+	if len(charge.TxID) < 26 || len(charge.TxID) > 35 || !isAlphanumeric(charge.TxID) {
+		return nil, errors.New("pix: txid of a charge must have 26 to 35 letters and digits")
+	}
+	if charge.Amount.Sign() <= 0 {
+		return nil, errors.New("pix: charge amount must be positive")
+	}
+	return &Location{
+		URL:       p.BaseURL + "/cob/" + charge.TxID,
+		ExpiresAt: time.Now().Add(charge.Expiration),
+	}, nil
+}
+
+// fakeNotification é o corpo do webhook da API Pix.
+type fakeNotification struct {
+	Pix []struct {
+		EndToEndID  string          `json:"endToEndId"`
+		TxID        string          `json:"txid"`
+		Valor       decimal.Decimal `json:"valor"`
+		Horario     time.Time       `json:"horario"`
+		InfoPagador string          `json:"infoPagador"`
+	} `json:"pix"`
+}
+
+func (p *FakeProvider) ParseNotification(header http.Header, body []byte) ([]Notification, error) {
+	if p.Secret == "" || !hmac.Equal([]byte(header.Get(SignatureHeader)), []byte(p.Sign(body))) {
+		return nil, ErrInvalidNotification
+	}
+	var parsed fakeNotification
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, ErrInvalidNotification
+	}
+	notifications := make([]Notification, 0, len(parsed.Pix))
+	for _, pix := range parsed.Pix {
+		if pix.EndToEndID == "" || pix.Valor.Sign() <= 0 {
+			return nil, ErrInvalidNotification
+		}
+		notifications = append(notifications, Notification{
+			EndToEndID: pix.EndToEndID,
+			TxID:       pix.TxID,
+			Amount:     pix.Valor,
+			PaidAt:     pix.Horario,
+			PayerInfo:  pix.InfoPagador,
+		})
+	}
+	return notifications, nil
+}
+
+// Sign devolve a assinatura que o FakeProvider espera para body.
+func (p *FakeProvider) Sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(p.Secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,44 @@
+package pix
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestFakeProviderParseNotification(t *testing.T) {
+	body := []byte(`{"pix":[{"endToEndId":"E12345678202503101430abcdefghijk","txid":"abc123","valor":"10.50","horario":"2025-03-10T14:30:00Z"}]}`)
+	signed := &FakeProvider{Secret: "change-me"}
+
+	header := http.Header{}
+	header.Set(SignatureHeader, signed.Sign(body))
+	notifications, err := signed.ParseNotification(header, body)
+	if err != nil {
+		t.Fatalf("ParseNotification of a signed notification: %v", err)
+	}
+	if len(notifications) != 1 || notifications[0].TxID != "abc123" || notifications[0].Amount.String() != "10.5" {
+		t.Fatalf("notifications = %+v, want the txid abc123 with 10.50", notifications)
+	}
+
+	tests := []struct {
+		name      string
+		provider  *FakeProvider
+		signature string
+	}{
+		{"no signature", signed, ""},
+		{"wrong signature", signed, (&FakeProvider{Secret: "other"}).Sign(body)},
+		// Sem segredo configurado nada é aceito, nem uma assinatura feita com o segredo vazio.
+		{"provider without secret", &FakeProvider{}, (&FakeProvider{}).Sign(body)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.signature != "" {
+				header.Set(SignatureHeader, tt.signature)
+			}
+			if _, err := tt.provider.ParseNotification(header, body); !errors.Is(err, ErrInvalidNotification) {
+				t.Fatalf("ParseNotification = %v, want ErrInvalidNotification", err)
+			}
+		})
+	}
+}
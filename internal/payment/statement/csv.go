@@ -0,0 +1,169 @@
+package statement
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode"
+
+	"go-sales/pkg/decimal"
+	"go-sales/pkg/spreadsheet"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Colunas reconhecidas no cabeçalho dos CSVs, pelo nome normalizado (minúsculo e sem acentos).
+var csvColumns = map[string][]string{
+	"date":        {"data", "data lancamento", "data do lancamento", "data movimento", "data mov", "dt lancamento", "date"},
+	"description": {"descricao", "historico", "lancamento", "memo", "description"},
+	"amount":      {"valor", "valor (r$)", "valor r$", "amount"},
+	"credit":      {"credito", "credito (r$)", "entrada", "entradas"},
+	"debit":       {"debito", "debito (r$)", "saida", "saidas"},
+	"document":    {"documento", "doc", "n documento", "no documento", "numero documento", "num documento", "documento n"},
+	"id":          {"id", "fitid", "identificador", "id transacao"},
+}
+
+// dateLayouts são os formatos de data aceitos nos CSVs.
+var dateLayouts = []string{"02/01/2006", "02/01/06", "2006-01-02", "02-01-2006", "02.01.2006"}
+
+// ParseCSV lê um extrato CSV. O cabeçalho é procurado nas primeiras linhas (os bancos costumam
+// exportar antes dele o nome da conta e o período) e precisa ter a data e o valor, em uma coluna com
+// sinal ou em colunas separadas de crédito e débito. Linhas de saldo são ignoradas.
+func ParseCSV(data []byte) ([]Transaction, error) {
+	rows, err := spreadsheet.ReadCSV(bytes.NewReader(toUTF8(data)))
+	if err != nil {
+		return nil, ErrInvalidStatement
+	}
+
+	headerRow, columns := -1, map[string]int(nil)
+	for i, row := range rows {
+		if i >= 20 {
+			break
+		}
+		if found := csvHeader(row); found != nil {
+			headerRow, columns = i, found
+			break
+		}
+	}
+	if headerRow < 0 {
+		return nil, ErrInvalidStatement
+	}
+
+	var transactions []Transaction
+	for _, row := range rows[headerRow+1:] {
+		description := cell(row, columns, "description")
+		if isBalanceLine(description) {
+			continue
+		}
+		rawDate := cell(row, columns, "date")
+		if rawDate == "" {
+			continue
+		}
+		postedAt, err := parseCSVDate(rawDate)
+		if err != nil {
+			return nil, ErrInvalidStatement
+		}
+		amount, err := csvAmount(row, columns)
+		if err != nil {
+			return nil, ErrInvalidStatement
+		}
+		if amount.IsZero() {
+			continue
+		}
+		transactions = append(transactions, Transaction{
+			ID:             cell(row, columns, "id"),
+			PostedAt:       postedAt,
+			Amount:         amount,
+			Description:    description,
+			DocumentNumber: cell(row, columns, "document"),
+		})
+	}
+	assignIDs(transactions)
+	return transactions, nil
+}
+
+// csvHeader devolve a posição de cada coluna reconhecida, ou nil se a linha não é um cabeçalho.
+func csvHeader(row []string) map[string]int {
+	columns := make(map[string]int)
+	for i, name := range row {
+		name = normalize(name)
+		for column, aliases := range csvColumns {
+			if _, ok := columns[column]; ok {
+				continue
+			}
+			for _, alias := range aliases {
+				if name == alias {
+					columns[column] = i
+					break
+				}
+			}
+		}
+	}
+	_, hasDate := columns["date"]
+	_, hasAmount := columns["amount"]
+	_, hasCredit := columns["credit"]
+	if !hasDate || (!hasAmount && !hasCredit) {
+		return nil
+	}
+	return columns
+}
+
+func csvAmount(row []string, columns map[string]int) (decimal.Decimal, error) {
+	if _, ok := columns["amount"]; ok {
+		return parseAmount(cell(row, columns, "amount"))
+	}
+	amount := decimal.Zero
+	if credit := cell(row, columns, "credit"); credit != "" {
+		value, err := parseAmount(credit)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		amount = amount.Add(value)
+	}
+	if debit := cell(row, columns, "debit"); debit != "" {
+		value, err := parseAmount(strings.TrimPrefix(strings.TrimSpace(debit), "-"))
+		if err != nil {
+			return decimal.Zero, err
+		}
+		amount = amount.Sub(value)
+	}
+	return amount, nil
+}
+
+func cell(row []string, columns map[string]int, column string) string {
+	i, ok := columns[column]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+func parseCSVDate(value string) (time.Time, error) {
+	// Alguns bancos exportam a data com horário ("15/01/2025 10:32").
+	value, _, _ = strings.Cut(value, " ")
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, ErrInvalidStatement
+}
+
+// isBalanceLine reconhece as linhas de saldo ("SALDO ANTERIOR", "Saldo do dia") que os bancos
+// intercalam com os lançamentos.
+func isBalanceLine(description string) bool {
+	return strings.HasPrefix(normalize(description), "saldo")
+}
+
+// normalize deixa o texto em minúsculas, sem acentos e com espaços simples, para comparar nomes de
+// colunas e documentos.
+func normalize(value string) string {
+	stripped, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), value)
+	if err == nil {
+		value = stripped
+	}
+	value = strings.NewReplacer("º", "", "°", "", ".", "", ":", "").Replace(value)
+	return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+}
@@ -0,0 +1,150 @@
+package statement
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"go-sales/pkg/decimal"
+)
+
+// Critérios que compõem a confiança de uma sugestão (0 a 100).
+const (
+	ReasonExactAmount = "exact_amount"
+	ReasonCloseAmount = "close_amount"
+	ReasonSameDate    = "same_date"
+	ReasonDateWindow  = "date_window"
+	ReasonDocument    = "document_number"
+	ReasonAmbiguous   = "ambiguous"
+)
+
+const (
+	scoreExactAmount = 50
+	scoreCloseAmount = 30
+	scoreDate        = 25
+	scoreDateMin     = 10
+	scoreDocument    = 25
+	penaltyAmbiguous = 15
+
+	// MinConfidence é a confiança mínima para uma parcela ser sugerida.
+	MinConfidence = 60
+)
+
+// closeAmountRate é a diferença relativa de valor aceita como "valor próximo" (tarifas, juros de
+// poucos centavos).
+var closeAmountRate = decimal.MustParse("0.01")
+
+// Candidate é uma parcela em aberto que pode ter sido paga por um crédito do extrato.
+type Candidate struct {
+	ID             int64
+	Amount         decimal.Decimal
+	DueDate        time.Time
+	DocumentNumber string
+}
+
+// Match é a parcela sugerida para o lançamento Index da lista passada a Suggest.
+type Match struct {
+	Index       int
+	CandidateID int64
+	Confidence  int
+	Reasons     []string
+}
+
+// Suggest procura a parcela de cada crédito. Um par é pontuado pelo valor (igual ou a menos de 1%),
+// pela distância entre a data do crédito e o vencimento (dentro de windowDays) e pelo número do
+// documento aparecer no histórico do banco; o valor é obrigatório. A atribuição é gulosa pela
+// confiança, e cada lançamento e cada parcela entram em no máximo uma sugestão. Quando há mais de uma
+// parcela com a maior pontuação para o mesmo crédito, a confiança é reduzida.
+func Suggest(transactions []Transaction, candidates []Candidate, windowDays int) []Match {
+	var pairs []Match
+	for i, t := range transactions {
+		if t.Amount.Sign() <= 0 {
+			continue
+		}
+		var best []Match
+		for _, candidate := range candidates {
+			score, reasons := scorePair(t, candidate, windowDays)
+			if score == 0 {
+				continue
+			}
+			pairs = append(pairs, Match{Index: i, CandidateID: candidate.ID, Confidence: score, Reasons: reasons})
+			if len(best) == 0 || score > best[0].Confidence {
+				best = []Match{pairs[len(pairs)-1]}
+			} else if score == best[0].Confidence {
+				best = append(best, pairs[len(pairs)-1])
+			}
+		}
+		if len(best) > 1 {
+			for j := range pairs {
+				if pairs[j].Index == i && pairs[j].Confidence == best[0].Confidence {
+					pairs[j].Confidence -= penaltyAmbiguous
+					pairs[j].Reasons = append(pairs[j].Reasons, ReasonAmbiguous)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(pairs, func(a, b int) bool { return pairs[a].Confidence > pairs[b].Confidence })
+	usedTransactions := make(map[int]bool)
+	usedCandidates := make(map[int64]bool)
+	var matches []Match
+	for _, pair := range pairs {
+		if pair.Confidence < MinConfidence || usedTransactions[pair.Index] || usedCandidates[pair.CandidateID] {
+			continue
+		}
+		usedTransactions[pair.Index] = true
+		usedCandidates[pair.CandidateID] = true
+		matches = append(matches, pair)
+	}
+	sort.Slice(matches, func(a, b int) bool { return matches[a].Index < matches[b].Index })
+	return matches
+}
+
+func scorePair(t Transaction, candidate Candidate, windowDays int) (int, []string) {
+	var score int
+	var reasons []string
+
+	diff := t.Amount.Sub(candidate.Amount)
+	if diff.Sign() < 0 {
+		diff = diff.Neg()
+	}
+	switch {
+	case diff.IsZero():
+		score += scoreExactAmount
+		reasons = append(reasons, ReasonExactAmount)
+	case diff.Cmp(candidate.Amount.Mul(closeAmountRate)) <= 0:
+		score += scoreCloseAmount
+		reasons = append(reasons, ReasonCloseAmount)
+	default:
+		return 0, nil
+	}
+
+	days := int(t.PostedAt.Sub(candidate.DueDate).Hours() / 24)
+	if days < 0 {
+		days = -days
+	}
+	switch {
+	case days == 0:
+		score += scoreDate
+		reasons = append(reasons, ReasonSameDate)
+	case days <= windowDays:
+		score += scoreDate - (scoreDate-scoreDateMin)*days/windowDays
+		reasons = append(reasons, ReasonDateWindow)
+	}
+
+	if documentMatches(t, candidate.DocumentNumber) {
+		score += scoreDocument
+		reasons = append(reasons, ReasonDocument)
+	}
+	return score, reasons
+}
+
+// documentMatches diz se o número do documento da parcela aparece no documento ou no histórico do
+// lançamento. Documentos muito curtos (ex: "1") não são comparados para não casar por acaso.
+func documentMatches(t Transaction, document string) bool {
+	document = normalize(document)
+	if len(document) < 3 {
+		return false
+	}
+	return strings.Contains(normalize(t.DocumentNumber), document) || strings.Contains(normalize(t.Description), document)
+}
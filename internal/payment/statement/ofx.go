@@ -0,0 +1,153 @@
+package statement
+
+import (
+	"strings"
+	"time"
+
+	"go-sales/pkg/decimal"
+)
+
+// ParseOFX lê os lançamentos (STMTTRN) de um extrato OFX. O OFX 1.x é SGML, com tags de valor sem
+// fechamento (<TRNAMT>-10.00), e o 2.x é XML; como só os valores das tags interessam, os dois são
+// lidos pelo mesmo leitor de tags, sem validar a estrutura do documento.
+func ParseOFX(data []byte) ([]Transaction, error) {
+	text := string(toUTF8(data))
+	if !strings.Contains(strings.ToUpper(text), "<OFX>") {
+		return nil, ErrInvalidStatement
+	}
+
+	var transactions []Transaction
+	for _, block := range ofxBlocks(text, "STMTTRN") {
+		values := ofxValues(block)
+		postedAt, err := parseOFXDate(values["DTPOSTED"])
+		if err != nil {
+			return nil, ErrInvalidStatement
+		}
+		amount, err := parseAmount(values["TRNAMT"])
+		if err != nil {
+			return nil, ErrInvalidStatement
+		}
+		description := values["MEMO"]
+		if description == "" {
+			description = values["NAME"]
+		}
+		document := values["CHECKNUM"]
+		if document == "" {
+			document = values["REFNUM"]
+		}
+		transactions = append(transactions, Transaction{
+			ID:             values["FITID"],
+			PostedAt:       postedAt,
+			Amount:         amount,
+			Description:    description,
+			DocumentNumber: document,
+		})
+	}
+	assignIDs(transactions)
+	return transactions, nil
+}
+
+// ofxBlocks devolve o conteúdo de cada agregado <tag>...</tag> do documento.
+func ofxBlocks(text, tag string) []string {
+	upper := strings.ToUpper(text)
+	open, end := "<"+tag+">", "</"+tag+">"
+	var blocks []string
+	for {
+		start := strings.Index(upper, open)
+		if start < 0 {
+			return blocks
+		}
+		upper, text = upper[start+len(open):], text[start+len(open):]
+		stop := strings.Index(upper, end)
+		if stop < 0 {
+			return blocks
+		}
+		blocks = append(blocks, text[:stop])
+		upper, text = upper[stop+len(end):], text[stop+len(end):]
+	}
+}
+
+// ofxValues lê as tags de valor de um agregado: o texto entre a tag de abertura e a próxima tag.
+func ofxValues(block string) map[string]string {
+	values := make(map[string]string)
+	for _, part := range strings.Split(block, "<")[1:] {
+		name, value, ok := strings.Cut(part, ">")
+		if !ok || strings.HasPrefix(name, "/") {
+			continue
+		}
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if _, exists := values[name]; !exists {
+			values[name] = ofxUnescape(strings.TrimSpace(value))
+		}
+	}
+	return values
+}
+
+var ofxEntities = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'", "&amp;", "&")
+
+func ofxUnescape(value string) string {
+	return ofxEntities.Replace(value)
+}
+
+// parseOFXDate lê a data de um DTPOSTED (AAAAMMDD[HHMMSS[.XXX]][[-3:BRT]]). Só o dia interessa: o
+// horário e o fuso são ignorados para que o lançamento fique no dia informado pelo banco.
+func parseOFXDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, ErrInvalidStatement
+	}
+	return time.Parse("20060102", value[:8])
+}
+
+// parseAmount lê um valor com ponto ou vírgula decimal, separador de milhar opcional, prefixo R$ e
+// sinal de menos ou sufixo C/D (crédito/débito).
+func parseAmount(value string) (decimal.Decimal, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	value = strings.TrimSpace(strings.TrimPrefix(value, "R$"))
+	negative := false
+	switch {
+	case strings.HasSuffix(value, "D"):
+		negative = true
+		value = strings.TrimSpace(strings.TrimSuffix(value, "D"))
+	case strings.HasSuffix(value, "C"):
+		value = strings.TrimSpace(strings.TrimSuffix(value, "C"))
+	}
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = value[1 : len(value)-1]
+	}
+	if strings.HasPrefix(value, "-") {
+		negative = !negative
+		value = value[1:]
+	}
+	value = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(value), "R$"))
+	value = strings.ReplaceAll(value, " ", "")
+
+	// O último separador é o decimal e os demais são de milhar, exceto quando ele é seguido de
+	// exatamente três dígitos e não há separador do outro tipo: "1.000" e "1,500" são milhares.
+	if i := strings.LastIndexAny(value, ".,"); i >= 0 {
+		integer := strings.NewReplacer(".", "", ",", "").Replace(value[:i])
+		if isThousands(value, i) {
+			value = integer + value[i+1:]
+		} else {
+			value = integer + "." + value[i+1:]
+		}
+	}
+	amount, err := decimal.Parse(value)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if negative {
+		amount = amount.Neg()
+	}
+	return amount, nil
+}
+
+// isThousands diz se o separador na posição i agrupa milhares: três dígitos depois dele, nenhum
+// separador do outro tipo e parte inteira sem zero à esquerda ("0.500" continua sendo meio real).
+func isThousands(value string, i int) bool {
+	other := ","
+	if value[i] == ',' {
+		other = "."
+	}
+	return len(value)-i-1 == 3 && !strings.Contains(value, other) && !strings.HasPrefix(value, "0")
+}
@@ -0,0 +1,37 @@
+package statement
+
+import (
+	"testing"
+
+	"go-sales/pkg/decimal"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"150.00", "150.00"},
+		{"-150.00", "-150.00"},
+		{"1.234,56", "1234.56"},
+		{"1,234.56", "1234.56"},
+		{"1.000", "1000"},
+		{"R$ 1.500", "1500"},
+		{"1.000.000", "1000000"},
+		{"2,500", "2500"},
+		{"1.234.567,89", "1234567.89"},
+		{"0.500", "0.5"},
+		{"12,5", "12.5"},
+		{"1.000,00 D", "-1000.00"},
+		{"(R$ 1.500)", "-1500"},
+	}
+	for _, tt := range tests {
+		got, err := parseAmount(tt.input)
+		if err != nil {
+			t.Fatalf("parseAmount(%q): %v", tt.input, err)
+		}
+		if !got.Equal(decimal.MustParse(tt.want)) {
+			t.Errorf("parseAmount(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
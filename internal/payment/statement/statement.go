@@ -0,0 +1,90 @@
+// Package statement lê extratos bancários em OFX (1.x SGML e 2.x XML) e nos layouts CSV mais comuns
+// dos bancos, e sugere a parcela a receber de cada crédito do extrato.
+package statement
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go-sales/pkg/decimal"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Formatos de extrato aceitos.
+const (
+	FormatOFX = "ofx"
+	FormatCSV = "csv"
+)
+
+// ErrInvalidStatement é retornado quando o arquivo não é um extrato legível ou não tem lançamentos.
+var ErrInvalidStatement = errors.New("statement: invalid or empty statement")
+
+// Transaction é um lançamento do extrato. Amount é positivo nos créditos e negativo nos débitos.
+type Transaction struct {
+	// ID identifica o lançamento na conta: o FITID do OFX, a coluna de id do CSV ou, sem elas, um hash
+	// dos campos do lançamento. Reimportar o mesmo extrato gera os mesmos ids.
+	ID             string
+	PostedAt       time.Time
+	Amount         decimal.Decimal
+	Description    string
+	DocumentNumber string
+}
+
+// Parse lê um extrato no formato informado.
+func Parse(format string, data []byte) ([]Transaction, error) {
+	var (
+		transactions []Transaction
+		err          error
+	)
+	switch format {
+	case FormatOFX:
+		transactions, err = ParseOFX(data)
+	case FormatCSV:
+		transactions, err = ParseCSV(data)
+	default:
+		return nil, ErrInvalidStatement
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(transactions) == 0 {
+		return nil, ErrInvalidStatement
+	}
+	return transactions, nil
+}
+
+// toUTF8 converte os extratos gravados em Windows-1252 (comum nos bancos brasileiros) para UTF-8.
+func toUTF8(data []byte) []byte {
+	data = []byte(strings.TrimPrefix(string(data), "\xef\xbb\xbf"))
+	if utf8.Valid(data) {
+		return data
+	}
+	decoded, err := charmap.Windows1252.NewDecoder().Bytes(data)
+	if err != nil {
+		return data
+	}
+	return decoded
+}
+
+// assignIDs preenche o ID dos lançamentos sem identificador com um hash da data, do valor, da
+// descrição e do documento. Lançamentos iguais no mesmo arquivo (dois PIX do mesmo valor no mesmo dia)
+// recebem também a ordem em que aparecem.
+func assignIDs(transactions []Transaction) {
+	seen := make(map[string]int)
+	for i := range transactions {
+		t := &transactions[i]
+		if t.ID != "" {
+			continue
+		}
+		key := strings.Join([]string{t.PostedAt.Format("2006-01-02"), t.Amount.StringFixed(2), t.Description, t.DocumentNumber}, "|")
+		seen[key]++
+		sum := sha256.Sum256([]byte(key + "|" + strconv.Itoa(seen[key])))
+		t.ID = "h:" + hex.EncodeToString(sum[:16])
+	}
+}
@@ -0,0 +1,116 @@
+package problem
+
+import (
+	"go-sales/internal/i18n"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType é o media type definido pela RFC 9457 para respostas de erro.
+const ContentType = "application/problem+json"
+
+// RequestIDHeader é o header usado para propagar o ID de correlação da requisição.
+const RequestIDHeader = "X-Request-ID"
+
+// TypePrefix é o prefixo do URI que identifica o tipo do problema.
+// O sufixo é sempre o código estável do erro (ex: "urn:go-sales:problem:email_in_use").
+const TypePrefix = "urn:go-sales:problem:"
+
+// FieldError descreve uma violação de validação em um campo do payload.
+// Field usa o caminho JSON completo, incluindo índices (ex: "contacts[1].email").
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Rule    string `json:"rule,omitempty"`
+}
+
+// Details é o corpo de uma resposta application/problem+json.
+type Details struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"requestId,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+
+	// args preenche os placeholders da mensagem traduzida (ver i18n.Message).
+	args []string
+}
+
+// Coder é o contrato mínimo de um erro que pode ser convertido em problema.
+// service.ErrorUtil satisfaz esta interface.
+type Coder interface {
+	HTTPStatusCode() int
+	Code() string
+	Error() string
+}
+
+// New cria um problema para o status e código informados.
+func New(status int, code, detail string) *Details {
+	return &Details{
+		Type:   TypePrefix + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// messageArgser é implementada pelos erros que carregam argumentos para a mensagem traduzida.
+type messageArgser interface {
+	MessageArgs() []string
+}
+
+// FromError converte um erro de domínio em problema.
+func FromError(err Coder) *Details {
+	p := New(err.HTTPStatusCode(), err.Code(), err.Error())
+	if withArgs, ok := err.(messageArgser); ok {
+		p.args = withArgs.MessageArgs()
+	}
+	return p
+}
+
+// WithErrors anexa as violações de campo ao problema.
+func (p *Details) WithErrors(errs []FieldError) *Details {
+	p.Errors = errs
+	return p
+}
+
+// WithArgs define os argumentos usados ao traduzir o detail.
+func (p *Details) WithArgs(args ...string) *Details {
+	p.args = args
+	return p
+}
+
+// Write traduz o detail pelo código do erro conforme o Accept-Language, completa instance
+// e requestId a partir do contexto, grava a resposta com o content type da RFC 9457
+// e aborta a cadeia de handlers.
+//
+// O detail original já está no idioma padrão e costuma ser mais específico que a mensagem do
+// catálogo, então só é trocado quando o locale negociado tem a sua própria tradução (ou quando
+// veio vazio). Sem tradução, a resposta sai no idioma padrão e o Content-Language diz isso.
+func Write(c *gin.Context, p *Details) {
+	locale := i18n.FromContext(c)
+	switch {
+	case p.Detail == "":
+		p.Detail = i18n.Message(locale, p.Code, p.Detail, p.args...)
+	case locale != i18n.DefaultLocale:
+		if msg, ok := i18n.Translate(locale, p.Code, p.args...); ok {
+			p.Detail = msg
+		} else {
+			locale = i18n.DefaultLocale
+		}
+	}
+	if p.Instance == "" && c.Request != nil {
+		p.Instance = c.Request.URL.Path
+	}
+	if p.RequestID == "" {
+		p.RequestID = c.Writer.Header().Get(RequestIDHeader)
+	}
+	c.Header("Content-Type", ContentType)
+	c.Header("Content-Language", locale)
+	c.AbortWithStatusJSON(p.Status, p)
+}
@@ -0,0 +1,334 @@
+// Package queue executa os jobs em segundo plano gravados em master.jobs: workers que disputam a
+// fila com FOR UPDATE SKIP LOCKED, novas tentativas com backoff exponencial, dead-letter, jobs
+// agendados por cron e drenagem no desligamento.
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-sales/internal/database"
+	"go-sales/internal/model"
+
+	"github.com/robfig/cron"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultMaxAttempts é o número de tentativas de um job criado por NewJob.
+const DefaultMaxAttempts = 5
+
+// Handler processa um job do tipo em que foi registrado. Um erro agenda uma nova tentativa com
+// backoff exponencial; esgotadas as tentativas (ou com um erro Permanent), o job vai para dead.
+// ctx é cancelado quando o desligamento não consegue esperar o fim do job.
+type Handler func(ctx context.Context, payload []byte) error
+
+// permanentError marca um erro que não adianta tentar de novo.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent envolve err para que o job vá direto para dead, sem novas tentativas.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// IsPermanent indica se err (ou algum erro que ele envolve) foi marcado com Permanent.
+func IsPermanent(err error) bool {
+	return errors.As(err, new(*permanentError))
+}
+
+// NewJob monta um job do tipo informado com o payload serializado em JSON, pronto para ser
+// gravado com JobRepositoryInterface.Enqueue. RunAt, MaxAttempts e UniqueKey podem ser ajustados antes.
+func NewJob(jobType string, payload any) (*model.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Job{
+		Type:        jobType,
+		Payload:     data,
+		MaxAttempts: DefaultMaxAttempts,
+	}, nil
+}
+
+// Options configura o Runner. Valores zerados usam os padrões de NewRunner.
+type Options struct {
+	// Workers é quantos jobs este processo executa ao mesmo tempo.
+	Workers int
+	// PollInterval é a espera entre consultas quando a fila está vazia.
+	PollInterval time.Duration
+	// LockTimeout é por quanto tempo a reserva de um job vale sem heartbeat antes de ele voltar à fila.
+	LockTimeout time.Duration
+	// BaseBackoff e MaxBackoff limitam a espera entre tentativas: BaseBackoff * 2^(tentativa-1).
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Runner executa os jobs da fila com os handlers registrados.
+type Runner struct {
+	repo     database.JobRepositoryInterface
+	opts     Options
+	workerID string
+	handlers map[string]Handler
+	cron     *cron.Cron
+
+	stop   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRunner cria o Runner. Os handlers e agendamentos devem ser registrados antes de Start.
+func NewRunner(repo database.JobRepositoryInterface, opts Options) *Runner {
+	if opts.Workers <= 0 {
+		opts.Workers = 2
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.LockTimeout <= 0 {
+		opts.LockTimeout = 5 * time.Minute
+	}
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = 10 * time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = time.Hour
+	}
+
+	hostname, _ := os.Hostname()
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Runner{
+		repo:     repo,
+		opts:     opts,
+		workerID: hostname + ":" + strconv.Itoa(os.Getpid()),
+		handlers: make(map[string]Handler),
+		cron:     cron.New(),
+		stop:     make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Register associa um handler a um tipo de job.
+func (r *Runner) Register(jobType string, handler Handler) {
+	r.handlers[jobType] = handler
+}
+
+// Register associa a um tipo de job um handler que recebe o payload já decodificado em P.
+// Um payload que não decodifica em P manda o job direto para dead.
+func Register[P any](r *Runner, jobType string, handler func(ctx context.Context, payload P) error) {
+	r.Register(jobType, func(ctx context.Context, data []byte) error {
+		var payload P
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return Permanent(fmt.Errorf("invalid payload for job %s: %w", jobType, err))
+		}
+		return handler(ctx, payload)
+	})
+}
+
+// Schedule enfileira um job do tipo informado a cada disparo do cron spec (formato de 5 campos, como
+// "0 * * * *", ou descritores como "@hourly"). Cada disparo usa a chave única tipo@horário, então
+// várias instâncias da API enfileiram o job uma única vez. Descritores "@every" não são alinhados
+// entre instâncias e por isso não devem ser usados com mais de uma instância.
+func (r *Runner) Schedule(spec string, jobType string, payload any) error {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	next := schedule.Next(time.Now())
+	r.cron.Schedule(schedule, cron.FuncJob(func() {
+		// O horário do disparo vem da própria agenda, e não do relógio, para que a chave seja igual em todas as instâncias.
+		mu.Lock()
+		firedAt := next
+		next = schedule.Next(firedAt)
+		mu.Unlock()
+
+		job, err := NewJob(jobType, payload)
+		if err != nil {
+			log.Error().Err(err).Str("job_type", jobType).Msg("failed to build scheduled job")
+			return
+		}
+		uniqueKey := jobType + "@" + firedAt.UTC().Format(time.RFC3339)
+		job.UniqueKey = &uniqueKey
+		job.RunAt = firedAt
+		if _, err := r.repo.Enqueue(job); err != nil {
+			log.Error().Err(err).Str("job_type", jobType).Msg("failed to enqueue scheduled job")
+		}
+	}))
+	return nil
+}
+
+// Start inicia os workers, a recuperação de jobs abandonados e o cron.
+func (r *Runner) Start() {
+	log.Info().Int("workers", r.opts.Workers).Str("worker_id", r.workerID).Msg("Starting job runner")
+	for i := 0; i < r.opts.Workers; i++ {
+		r.wg.Add(1)
+		go r.work()
+	}
+	r.wg.Add(1)
+	go r.recoverStale()
+	r.cron.Start()
+}
+
+// Shutdown para de pegar jobs novos e espera os jobs em execução terminarem. Se ctx vencer antes,
+// os jobs em andamento recebem o cancelamento do seu contexto e Shutdown espera apenas que eles retornem.
+func (r *Runner) Shutdown(ctx context.Context) error {
+	log.Info().Msg("Draining job runner")
+	r.cron.Stop()
+	close(r.stop)
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		r.cancel()
+		return nil
+	case <-ctx.Done():
+		r.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (r *Runner) work() {
+	defer r.wg.Done()
+	for {
+		select {
+		case <-r.stop:
+			return
+		default:
+		}
+
+		job, err := r.repo.Claim(r.workerID, time.Now())
+		if err != nil {
+			log.Error().Err(err).Msg("failed to claim job")
+		}
+		if err != nil || job == nil {
+			select {
+			case <-r.stop:
+				return
+			case <-time.After(r.opts.PollInterval):
+			}
+			continue
+		}
+		r.process(job)
+	}
+}
+
+// process executa o job e grava o resultado: completed, nova tentativa com backoff ou dead.
+func (r *Runner) process(job *model.Job) {
+	logger := log.With().Int64("job_id", job.ID).Str("job_type", job.Type).Int("attempt", job.Attempts).Logger()
+
+	handler, ok := r.handlers[job.Type]
+	if !ok {
+		logger.Error().Msg("no handler registered for job type")
+		if err := r.repo.Dead(job.ID, r.workerID, time.Now(), "no handler registered for job type "+job.Type); err != nil {
+			logger.Error().Err(err).Msg("failed to move job to dead")
+		}
+		return
+	}
+
+	stopHeartbeat := r.heartbeat(job.ID)
+	err := run(r.ctx, handler, job.Payload)
+	stopHeartbeat()
+
+	now := time.Now()
+	switch {
+	case err == nil:
+		logger.Debug().Msg("job completed")
+		err = r.repo.Complete(job.ID, r.workerID, now)
+	case IsPermanent(err) || job.Attempts >= job.MaxAttempts:
+		logger.Error().Err(err).Msg("job failed permanently, moving to dead")
+		err = r.repo.Dead(job.ID, r.workerID, now, err.Error())
+	default:
+		runAt := now.Add(r.backoff(job.Attempts))
+		logger.Warn().Err(err).Time("run_at", runAt).Msg("job failed, retrying later")
+		err = r.repo.Retry(job.ID, r.workerID, runAt, err.Error())
+	}
+	if errors.Is(err, database.ErrJobLockLost) {
+		logger.Warn().Msg("job lock lost to another worker; result discarded")
+		return
+	}
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to save job result")
+	}
+}
+
+// run executa o handler convertendo um panic em erro, para que o worker continue vivo.
+func run(ctx context.Context, handler Handler, payload []byte) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("job panicked: %v", recovered)
+		}
+	}()
+	return handler(ctx, payload)
+}
+
+// backoff calcula a espera antes da próxima tentativa, com até 10% de variação aleatória para que
+// jobs que falharam juntos não voltem todos ao mesmo tempo.
+func (r *Runner) backoff(attempt int) time.Duration {
+	delay := r.opts.MaxBackoff
+	if attempt < 32 {
+		delay = min(r.opts.BaseBackoff<<(attempt-1), r.opts.MaxBackoff)
+	}
+	return delay + rand.N(delay/10+1)
+}
+
+// heartbeat renova a reserva do job enquanto ele executa; a função devolvida encerra a renovação.
+func (r *Runner) heartbeat(id int64) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(r.opts.LockTimeout / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := r.repo.Heartbeat(id, r.workerID, time.Now()); err != nil {
+					log.Error().Err(err).Int64("job_id", id).Msg("failed to renew job lock")
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// recoverStale devolve periodicamente à fila os jobs de workers que caíram no meio da execução.
+func (r *Runner) recoverStale() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(r.opts.LockTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			recovered, err := r.repo.RecoverStale(time.Now().Add(-r.opts.LockTimeout))
+			if err != nil {
+				log.Error().Err(err).Msg("failed to recover stale jobs")
+				continue
+			}
+			if recovered > 0 {
+				log.Warn().Int64("recovered", recovered).Msg("stale jobs returned to the queue")
+			}
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Package ratelimit implementa o token bucket usado pelo middleware de rate limit, com um store em
+// memória (uma instância) e outro no Postgres (várias réplicas dividindo o mesmo limite).
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"go-sales/internal/database"
+)
+
+// Limit é a política de um bucket: até Requests requisições de rajada, recarregadas de forma
+// contínua ao longo de Per (ex: 300 por minuto = 5 tokens por segundo).
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// Valid indica se a política limita alguma coisa. Requests ou Per zerados desligam o limite.
+func (l Limit) Valid() bool {
+	return l.Requests > 0 && l.Per > 0
+}
+
+// rate é a recarga em tokens por segundo.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+// Result é o resultado de uma requisição contra o bucket.
+type Result struct {
+	Allowed bool
+	// Limit é a capacidade do bucket.
+	Limit int
+	// Remaining é quantas requisições ainda cabem agora.
+	Remaining int
+	// Reset é quanto falta para o bucket encher de novo.
+	Reset time.Duration
+	// RetryAfter é quanto falta para o próximo token quando a requisição foi recusada.
+	RetryAfter time.Duration
+}
+
+// Store guarda os buckets. Take recarrega o bucket da chave e consome um token, se houver.
+type Store interface {
+	Take(key string, limit Limit) (Result, error)
+}
+
+// result monta o Result a partir do saldo do bucket depois da tentativa.
+func result(limit Limit, tokens float64, allowed bool) Result {
+	rate := limit.rate()
+	res := Result{
+		Allowed:   allowed,
+		Limit:     limit.Requests,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     seconds((float64(limit.Requests) - tokens) / rate),
+	}
+	if !allowed {
+		res.RetryAfter = seconds((1 - tokens) / rate)
+	}
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Max(0, s) * float64(time.Second))
+}
+
+// memorySweepInterval é de quanto em quanto tempo o MemoryStore descarta os buckets já cheios.
+const memorySweepInterval = time.Minute
+
+type memoryBucket struct {
+	tokens     float64
+	refilledAt time.Time
+	expiresAt  time.Time
+}
+
+// MemoryStore guarda os buckets na memória do processo. Cada réplica aplica o limite sozinha.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+// NewMemoryStore cria um store em memória.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*memoryBucket)}
+}
+
+// Take implementa Store.
+func (s *MemoryStore) Take(key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	capacity := float64(limit.Requests)
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: capacity, refilledAt: now}
+		s.buckets[key] = bucket
+	}
+	elapsed := math.Max(0, now.Sub(bucket.refilledAt).Seconds())
+	bucket.tokens = math.Min(capacity, bucket.tokens+elapsed*limit.rate())
+	bucket.refilledAt = now
+	bucket.expiresAt = now.Add(limit.Per)
+
+	allowed := bucket.tokens >= 1
+	if allowed {
+		bucket.tokens--
+	}
+	return result(limit, bucket.tokens, allowed), nil
+}
+
+// sweep descarta os buckets que já voltaram a ficar cheios; eles equivalem a um bucket novo.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, bucket := range s.buckets {
+		if now.After(bucket.expiresAt) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// PostgresStore guarda os buckets em master.rate_limit_buckets, para que todas as réplicas da API
+// dividam o mesmo limite. Cada requisição custa um INSERT ... ON CONFLICT.
+type PostgresStore struct {
+	repo database.RateLimitRepositoryInterface
+}
+
+// NewPostgresStore cria um store no Postgres.
+func NewPostgresStore(repo database.RateLimitRepositoryInterface) *PostgresStore {
+	return &PostgresStore{repo: repo}
+}
+
+// Take implementa Store.
+func (s *PostgresStore) Take(key string, limit Limit) (Result, error) {
+	bucket, err := s.repo.Take(key, float64(limit.Requests), limit.rate(), limit.Per)
+	if err != nil {
+		return Result{}, err
+	}
+	return result(limit, bucket.Tokens, bucket.Allowed), nil
+}
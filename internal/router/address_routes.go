@@ -0,0 +1,20 @@
+package router
+
+import (
+	"go-sales/internal/config"
+	"go-sales/internal/database"
+	"go-sales/internal/handler"
+	"go-sales/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupAddressRoutes encapsula a configuração das rotas de consulta de endereços.
+func SetupAddressRoutes(router *gin.RouterGroup, db *gorm.DB, cfg *config.Config) {
+	postalCodeRepo := database.NewPostalCodeRepository(db)
+	addressProvider := service.NewLocalAddressProvider(postalCodeRepo)
+	addressHandler := handler.NewAddressHandler(addressProvider)
+
+	router.GET("/addresses/lookup", addressHandler.Lookup)
+}
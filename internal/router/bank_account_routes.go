@@ -0,0 +1,28 @@
+package router
+
+import (
+	"go-sales/internal/config"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/handler"
+	"go-sales/internal/middleware"
+	"go-sales/internal/service"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupBankAccountRoutes encapsula a configuração das rotas de contas bancárias.
+func SetupBankAccountRoutes(router *gin.RouterGroup, db *gorm.DB, cfg *config.Config) {
+	bankAccountRepo := database.NewBankAccountRepository(db)
+	companyRepo := database.NewCompanyGlobalRepository(db)
+	bankAccountService := service.NewBankAccountService(bankAccountRepo, companyRepo)
+	bankAccountHandler := handler.NewBankAccountHandler(bankAccountService, cfg)
+
+	router.POST("/bank-accounts", middleware.ValidateDTO(reflect.TypeOf(dto.CreateBankAccountDTO{})), bankAccountHandler.Create)
+	router.PATCH("/bank-accounts/:id", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.UpdateBankAccountDTO{})), bankAccountHandler.Patch)
+	router.DELETE("/bank-accounts/:id", middleware.ValidateID("id"), bankAccountHandler.Delete)
+	router.GET("/bank-accounts/:id", middleware.ValidateID("id"), bankAccountHandler.FindByID)
+	router.GET("/bank-accounts", bankAccountHandler.FindAll)
+}
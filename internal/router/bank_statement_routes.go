@@ -0,0 +1,32 @@
+package router
+
+import (
+	"go-sales/internal/config"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/handler"
+	"go-sales/internal/middleware"
+	"go-sales/internal/service"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupBankStatementRoutes encapsula a configuração das rotas de importação de extratos bancários e de
+// conciliação dos lançamentos com as parcelas.
+func SetupBankStatementRoutes(router *gin.RouterGroup, db *gorm.DB, cfg *config.Config) {
+	statementRepo := database.NewBankStatementRepository(db)
+	bankAccountRepo := database.NewBankAccountRepository(db)
+	installmentRepo := database.NewInstallmentRepository(db)
+	statementService := service.NewBankStatementService(statementRepo, bankAccountRepo, installmentRepo, cfg.AppStatementMatchDays)
+	statementHandler := handler.NewBankStatementHandler(statementService, cfg)
+
+	router.POST("/bank-accounts/:id/statements", middleware.ValidateID("id"), statementHandler.Import)
+	router.GET("/bank-accounts/:id/statement-lines", middleware.ValidateID("id"), statementHandler.FindLines)
+
+	router.GET("/bank-statement-lines/:id", middleware.ValidateID("id"), statementHandler.FindLine)
+	router.POST("/bank-statement-lines/:id/confirm", middleware.ValidateID("id"), statementHandler.Confirm)
+	router.POST("/bank-statement-lines/:id/reject", middleware.ValidateID("id"), statementHandler.Reject)
+	router.POST("/bank-statement-lines/:id/link", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.LinkStatementLineDTO{})), statementHandler.Link)
+}
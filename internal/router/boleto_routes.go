@@ -0,0 +1,36 @@
+package router
+
+import (
+	"go-sales/internal/config"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/handler"
+	"go-sales/internal/middleware"
+	"go-sales/internal/service"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupBoletoRoutes encapsula a configuração das rotas de boletos e dos arquivos CNAB de remessa e de
+// retorno.
+func SetupBoletoRoutes(router *gin.RouterGroup, db *gorm.DB, cfg *config.Config) {
+	boletoRepo := database.NewBoletoRepository(db)
+	bankAccountRepo := database.NewBankAccountRepository(db)
+	installmentRepo := database.NewInstallmentRepository(db)
+	companyRepo := database.NewCompanyGlobalRepository(db)
+	boletoService := service.NewBoletoService(boletoRepo, bankAccountRepo, installmentRepo, companyRepo)
+	boletoHandler := handler.NewBoletoHandler(boletoService)
+
+	router.POST("/installments/:id/boletos", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.CreateBoletoDTO{})), boletoHandler.Create)
+	router.GET("/installments/:id/boletos", middleware.ValidateID("id"), boletoHandler.FindByInstallment)
+	router.GET("/boletos/:id", middleware.ValidateID("id"), boletoHandler.FindByID)
+
+	router.POST("/bank-accounts/:id/remittances", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.CreateBoletoRemittanceDTO{})), boletoHandler.CreateRemittance)
+	router.GET("/boleto-remittances/:id", middleware.ValidateID("id"), boletoHandler.FindRemittance)
+	router.GET("/boleto-remittances/:id/file", middleware.ValidateID("id"), boletoHandler.RemittanceFile)
+
+	router.POST("/bank-accounts/:id/returns", middleware.ValidateID("id"), boletoHandler.ProcessReturn)
+	router.GET("/boleto-returns/:id", middleware.ValidateID("id"), boletoHandler.FindReturn)
+}
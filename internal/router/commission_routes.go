@@ -0,0 +1,45 @@
+package router
+
+import (
+	"go-sales/internal/auth"
+	"go-sales/internal/config"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/handler"
+	"go-sales/internal/middleware"
+	"go-sales/internal/service"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupCommissionRoutes encapsula a configuração das rotas de planos de comissão, dos lançamentos das
+// vendas faturadas e dos extratos mensais dos vendedores.
+func SetupCommissionRoutes(router *gin.RouterGroup, db *gorm.DB, cfg *config.Config) {
+	commissionRepo := database.NewCommissionRepository(db)
+	companyRepo := database.NewCompanyGlobalRepository(db)
+	userRepo := database.NewUserRepository(db)
+	roleRepo := database.NewRoleRepository(db)
+	commissionService := service.NewCommissionService(commissionRepo, companyRepo, userRepo, roleRepo)
+	commissionHandler := handler.NewCommissionHandler(commissionService, cfg)
+
+	router.POST("/commission-plans", middleware.ValidateDTO(reflect.TypeOf(dto.CreateCommissionPlanDTO{})), commissionHandler.CreatePlan)
+	router.PATCH("/commission-plans/:id", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.UpdateCommissionPlanDTO{})), commissionHandler.PatchPlan)
+	router.DELETE("/commission-plans/:id", middleware.ValidateID("id"), commissionHandler.DeletePlan)
+	router.GET("/commission-plans/:id", middleware.ValidateID("id"), commissionHandler.FindPlanByID)
+	router.GET("/commission-plans", commissionHandler.FindPlans)
+
+	router.POST("/commission-plans/:id/assignments", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.CreateCommissionAssignmentDTO{})), commissionHandler.CreateAssignment)
+	router.GET("/commission-plans/:id/assignments", middleware.ValidateID("id"), commissionHandler.FindAssignments)
+	router.DELETE("/commission-plan-assignments/:id", middleware.ValidateID("id"), commissionHandler.DeleteAssignment)
+
+	router.POST("/commission-entries", middleware.ValidateDTO(reflect.TypeOf(dto.CreateCommissionEntryDTO{})), commissionHandler.Record)
+	router.GET("/commission-entries", commissionHandler.FindEntries)
+	router.GET("/commission-entries/:id", middleware.ValidateID("id"), commissionHandler.FindEntry)
+	router.POST("/commission-entries/:id/reverse", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.ReverseCommissionEntryDTO{})), commissionHandler.Reverse)
+
+	router.GET("/commission-statements", commissionHandler.FindStatements)
+	router.GET("/users/:id/commission-statements/:period", middleware.ValidateID("id"), commissionHandler.FindStatement)
+	router.POST("/users/:id/commission-statements/:period/approve", middleware.RequirePermission(auth.PermissionApproveCommissions), middleware.ValidateID("id"), commissionHandler.Approve)
+}
@@ -14,16 +14,37 @@ import (
 )
 
 func SetupCompanyGlobalRoutes(router *gin.RouterGroup, db *gorm.DB, cfg *config.Config) {
-	repo := database.NewCompanyGlobalRepository(db)
-	service := service.NewCompanyGlobalService(repo)
-	handler := handler.NewCompanyGlobalHandler(service, cfg)
+	companyRepo := database.NewCompanyGlobalRepository(db)
+	addressProvider := service.NewLocalAddressProvider(database.NewPostalCodeRepository(db))
+	companyService := service.NewCompanyGlobalService(companyRepo, addressProvider)
+	companyHandler := handler.NewCompanyGlobalHandler(companyService, cfg)
 
-	router.POST("/company-globals", middleware.ValidateDTO(reflect.TypeOf(dto.CreateCompanyGlobalDTO{})), handler.Create)
-	router.PUT("/company-globals/:id", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.CreateCompanyGlobalDTO{})), handler.Update)
-	router.DELETE("/company-globals/:id", middleware.ValidateID("id"), handler.Delete)
-	router.GET("/company-globals/:id", middleware.ValidateID("id"), handler.FindByID)
-	router.GET("/company-globals", handler.FindAll)
+	router.POST("/company-globals", middleware.ValidateDTO(reflect.TypeOf(dto.CreateCompanyGlobalDTO{})), companyHandler.Create)
+	router.PUT("/company-globals/:id", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.CreateCompanyGlobalDTO{})), companyHandler.Update)
+	router.PATCH("/company-globals/:id", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.UpdateCompanyGlobalDTO{})), companyHandler.Patch)
+	router.DELETE("/company-globals/:id", middleware.ValidateID("id"), companyHandler.Delete)
+	router.GET("/company-globals/:id", middleware.ValidateID("id"), companyHandler.FindByID)
+	router.GET("/company-globals", companyHandler.FindAll)
 
 	// adding restore point for soft delete
-	router.POST("/company-globals/:id/restore", middleware.ValidateID("id"), handler.Restore)
+	router.POST("/company-globals/:id/restore", middleware.ValidateID("id"), companyHandler.Restore)
+
+	// Contatos e endereços como sub-recursos, com ids estáveis e ETag próprio.
+	contactRepo := database.NewCompanyGlobalContactRepository(db)
+	contactService := service.NewCompanyGlobalContactService(contactRepo, companyRepo)
+	contactHandler := handler.NewCompanyGlobalContactHandler(contactService)
+	router.GET("/company-globals/:id/contacts", middleware.ValidateID("id"), contactHandler.FindAll)
+	router.POST("/company-globals/:id/contacts", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.CreateCompanyGlobalContactDTO{})), contactHandler.Create)
+	router.GET("/company-globals/:id/contacts/:contactId", middleware.ValidateID("id"), middleware.ValidateID("contactId"), contactHandler.FindByID)
+	router.PUT("/company-globals/:id/contacts/:contactId", middleware.ValidateID("id"), middleware.ValidateID("contactId"), middleware.ValidateDTO(reflect.TypeOf(dto.CreateCompanyGlobalContactDTO{})), contactHandler.Update)
+	router.DELETE("/company-globals/:id/contacts/:contactId", middleware.ValidateID("id"), middleware.ValidateID("contactId"), contactHandler.Delete)
+
+	addressRepo := database.NewCompanyGlobalAddressRepository(db)
+	addressService := service.NewCompanyGlobalAddressService(addressRepo, companyRepo, addressProvider)
+	addressHandler := handler.NewCompanyGlobalAddressHandler(addressService)
+	router.GET("/company-globals/:id/addresses", middleware.ValidateID("id"), addressHandler.FindAll)
+	router.POST("/company-globals/:id/addresses", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.SaveCompanyGlobalAddressDTO{})), addressHandler.Create)
+	router.GET("/company-globals/:id/addresses/:addressId", middleware.ValidateID("id"), middleware.ValidateID("addressId"), addressHandler.FindByID)
+	router.PUT("/company-globals/:id/addresses/:addressId", middleware.ValidateID("id"), middleware.ValidateID("addressId"), middleware.ValidateDTO(reflect.TypeOf(dto.SaveCompanyGlobalAddressDTO{})), addressHandler.Update)
+	router.DELETE("/company-globals/:id/addresses/:addressId", middleware.ValidateID("id"), middleware.ValidateID("addressId"), addressHandler.Delete)
 }
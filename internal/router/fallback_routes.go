@@ -0,0 +1,22 @@
+package router
+
+import (
+	"go-sales/internal/problem"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupFallbackRoutes faz as rotas inexistentes (404) e os métodos não suportados por uma rota
+// existente (405) responderem com problem+json, como os demais erros da API.
+func SetupFallbackRoutes(server *gin.Engine) {
+	server.HandleMethodNotAllowed = true
+	server.NoRoute(func(c *gin.Context) {
+		problem.Write(c, problem.New(http.StatusNotFound, "route_not_found", "no route matches "+c.Request.Method+" "+c.Request.URL.Path).
+			WithArgs(c.Request.Method, c.Request.URL.Path))
+	})
+	server.NoMethod(func(c *gin.Context) {
+		problem.Write(c, problem.New(http.StatusMethodNotAllowed, "method_not_allowed", "method "+c.Request.Method+" is not allowed on "+c.Request.URL.Path).
+			WithArgs(c.Request.Method, c.Request.URL.Path))
+	})
+}
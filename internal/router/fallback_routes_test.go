@@ -0,0 +1,45 @@
+package router
+
+import (
+	"encoding/json"
+	"go-sales/internal/problem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestFallbackRoutesWriteProblems(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := gin.New()
+	server.GET("/api/v1/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+	SetupFallbackRoutes(server)
+
+	tests := []struct {
+		method, path string
+		status       int
+		code         string
+	}{
+		{http.MethodGet, "/api/v1/nothing-here", http.StatusNotFound, "route_not_found"},
+		{http.MethodDelete, "/api/v1/users", http.StatusMethodNotAllowed, "method_not_allowed"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+		if w.Code != tt.status {
+			t.Fatalf("%s %s: status %d, want %d", tt.method, tt.path, w.Code, tt.status)
+		}
+		if got := w.Header().Get("Content-Type"); got != problem.ContentType {
+			t.Fatalf("%s %s: Content-Type %q, want %q", tt.method, tt.path, got, problem.ContentType)
+		}
+		var details problem.Details
+		if err := json.Unmarshal(w.Body.Bytes(), &details); err != nil {
+			t.Fatalf("%s %s: decoding body: %v", tt.method, tt.path, err)
+		}
+		if details.Code != tt.code || details.Status != tt.status || details.Instance != tt.path {
+			t.Fatalf("%s %s: problem %+v", tt.method, tt.path, details)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package router
+
+import (
+	"go-sales/internal/config"
+	"go-sales/internal/database"
+	"go-sales/internal/handler"
+	"go-sales/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupImportRoutes encapsula a configuração das rotas de importação em massa.
+func SetupImportRoutes(router *gin.RouterGroup, db *gorm.DB, cfg *config.Config) {
+	importJobRepo := database.NewImportJobRepository(db)
+	importService := service.NewImportService(db, importJobRepo)
+	importHandler := handler.NewImportHandler(importService)
+
+	router.POST("/imports/:entity", importHandler.Create)
+	router.GET("/imports/jobs/:id", importHandler.FindByID)
+}
@@ -0,0 +1,28 @@
+package router
+
+import (
+	"go-sales/internal/config"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/handler"
+	"go-sales/internal/middleware"
+	"go-sales/internal/service"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupInstallmentRoutes encapsula a configuração das rotas de parcelas a receber.
+func SetupInstallmentRoutes(router *gin.RouterGroup, db *gorm.DB, cfg *config.Config) {
+	installmentRepo := database.NewInstallmentRepository(db)
+	companyRepo := database.NewCompanyGlobalRepository(db)
+	installmentService := service.NewInstallmentService(installmentRepo, companyRepo)
+	installmentHandler := handler.NewInstallmentHandler(installmentService, cfg)
+
+	router.POST("/installments", middleware.ValidateDTO(reflect.TypeOf(dto.CreateInstallmentDTO{})), installmentHandler.Create)
+	router.PATCH("/installments/:id", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.UpdateInstallmentDTO{})), installmentHandler.Patch)
+	router.DELETE("/installments/:id", middleware.ValidateID("id"), installmentHandler.Delete)
+	router.GET("/installments/:id", middleware.ValidateID("id"), installmentHandler.FindByID)
+	router.GET("/installments", installmentHandler.FindAll)
+}
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"go-sales/internal/config"
+	"go-sales/internal/database"
+	"go-sales/internal/handler"
+	"go-sales/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupJobRoutes encapsula a configuração das rotas de consulta da fila de jobs.
+func SetupJobRoutes(router *gin.RouterGroup, db *gorm.DB, cfg *config.Config) {
+	jobRepo := database.NewJobRepository(db)
+	jobService := service.NewJobService(jobRepo)
+	jobHandler := handler.NewJobHandler(jobService)
+
+	router.GET("/jobs/:id", jobHandler.FindByID)
+}
@@ -28,6 +28,7 @@ func SetupPermissionRoutes(router *gin.RouterGroup, db *gorm.DB, cfg *config.Con
 	// 4. Definir as rotas para /permissions
 	router.POST("/permissions", middleware.ValidateDTO(reflect.TypeOf(dto.CreatePermissionDTO{})), permissionHandler.Create)
 	router.PUT("/permissions/:id", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.CreatePermissionDTO{})), permissionHandler.Update)
+	router.PATCH("/permissions/:id", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.UpdatePermissionDTO{})), permissionHandler.Patch)
 	router.DELETE("/permissions/:id", middleware.ValidateID("id"), permissionHandler.Delete)
 	router.GET("/permissions/:id", middleware.ValidateID("id"), permissionHandler.FindByID)
 	router.GET("/permissions", permissionHandler.FindAll)
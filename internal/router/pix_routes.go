@@ -0,0 +1,37 @@
+package router
+
+import (
+	"go-sales/internal/config"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/handler"
+	"go-sales/internal/middleware"
+	"go-sales/internal/payment/pix"
+	"go-sales/internal/service"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupPixRoutes encapsula a configuração das rotas de cobranças PIX e do webhook do PSP. O PSP é o
+// pix.FakeProvider local até a integração com um PSP real, e só com APP_PIX_PROVIDER=fake; sem PSP as
+// rotas não são registradas.
+func SetupPixRoutes(router *gin.RouterGroup, db *gorm.DB, cfg *config.Config) {
+	if cfg.AppPixProvider != "fake" {
+		return
+	}
+	pixChargeRepo := database.NewPixChargeRepository(db)
+	installmentRepo := database.NewInstallmentRepository(db)
+	companyRepo := database.NewCompanyGlobalRepository(db)
+	provider := pix.NewFakeProvider(cfg.AppPixWebhookSecret)
+	pixService := service.NewPixService(pixChargeRepo, installmentRepo, companyRepo, provider)
+	pixHandler := handler.NewPixHandler(pixService)
+
+	router.POST("/installments/:id/pix-charges", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.CreatePixChargeDTO{})), pixHandler.CreateCharge)
+	router.GET("/installments/:id/pix-charges", middleware.ValidateID("id"), pixHandler.FindByInstallment)
+	router.GET("/pix-charges/:id", middleware.ValidateID("id"), pixHandler.FindByID)
+	router.GET("/pix-charges/:id/qrcode.png", middleware.ValidateID("id"), pixHandler.QRCode)
+
+	router.POST("/pix/webhook", pixHandler.Webhook)
+}
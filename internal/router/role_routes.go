@@ -23,6 +23,7 @@ func SetupRoleRoutes(router *gin.RouterGroup, db *gorm.DB, cfg *config.Config) {
 
 	router.POST("/roles", middleware.ValidateDTO(reflect.TypeOf(dto.CreateRoleDTO{})), roleHandler.Create)
 	router.PUT("/roles/:id", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.RoleDTO{})), roleHandler.Update)
+	router.PATCH("/roles/:id", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.UpdateRoleDTO{})), roleHandler.Patch)
 	router.DELETE("/roles/:id", middleware.ValidateID("id"), roleHandler.Delete)
 	router.GET("/roles/:id", middleware.ValidateID("id"), roleHandler.FindByID)
 	router.GET("/roles", roleHandler.FindAll)
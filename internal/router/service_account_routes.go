@@ -0,0 +1,38 @@
+package router
+
+import (
+	"go-sales/internal/auth"
+	"go-sales/internal/config"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/handler"
+	"go-sales/internal/middleware"
+	"go-sales/internal/service"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupServiceAccountRoutes encapsula a configuração das rotas de contas de serviço e chaves de API.
+func SetupServiceAccountRoutes(router *gin.RouterGroup, db *gorm.DB, cfg *config.Config) {
+	accountRepo := database.NewServiceAccountRepository(db)
+	roleRepo := database.NewRoleRepository(db)
+	companyRepo := database.NewCompanyGlobalRepository(db)
+	accountService := service.NewServiceAccountService(accountRepo, roleRepo, companyRepo)
+	accountHandler := handler.NewServiceAccountHandler(accountService, cfg)
+
+	// Todas as rotas exigem credenciais com a permissão service_accounts.manage e só enxergam as contas
+	// da empresa do Principal.
+	accounts := router.Group("/service-accounts", middleware.RequirePermission(auth.PermissionManageServiceAccounts))
+	accounts.POST("", middleware.ValidateDTO(reflect.TypeOf(dto.CreateServiceAccountDTO{})), accountHandler.Create)
+	accounts.PATCH("/:id", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.UpdateServiceAccountDTO{})), accountHandler.Patch)
+	accounts.DELETE("/:id", middleware.ValidateID("id"), accountHandler.Delete)
+	accounts.GET("/:id", middleware.ValidateID("id"), accountHandler.FindByID)
+	accounts.GET("", accountHandler.FindAll)
+
+	accounts.POST("/:id/keys", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.CreateAPIKeyDTO{})), accountHandler.CreateKey)
+	accounts.GET("/:id/keys", middleware.ValidateID("id"), accountHandler.FindKeys)
+	accounts.POST("/:id/keys/:keyId/rotate", middleware.ValidateID("id"), middleware.ValidateID("keyId"), middleware.ValidateDTO(reflect.TypeOf(dto.RotateAPIKeyDTO{})), accountHandler.RotateKey)
+	accounts.DELETE("/:id/keys/:keyId", middleware.ValidateID("id"), middleware.ValidateID("keyId"), accountHandler.RevokeKey)
+}
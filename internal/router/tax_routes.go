@@ -0,0 +1,30 @@
+package router
+
+import (
+	"go-sales/internal/config"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/handler"
+	"go-sales/internal/middleware"
+	"go-sales/internal/service"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupTaxRoutes encapsula a configuração das rotas de regras de impostos e da prévia de impostos.
+func SetupTaxRoutes(router *gin.RouterGroup, db *gorm.DB, cfg *config.Config) {
+	taxRuleRepo := database.NewTaxRuleRepository(db)
+	companyRepo := database.NewCompanyGlobalRepository(db)
+	taxService := service.NewTaxService(taxRuleRepo, companyRepo)
+	taxHandler := handler.NewTaxHandler(taxService, cfg)
+
+	router.POST("/tax-rules", middleware.ValidateDTO(reflect.TypeOf(dto.CreateTaxRuleDTO{})), taxHandler.Create)
+	router.PATCH("/tax-rules/:id", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.UpdateTaxRuleDTO{})), taxHandler.Patch)
+	router.DELETE("/tax-rules/:id", middleware.ValidateID("id"), taxHandler.Delete)
+	router.GET("/tax-rules/:id", middleware.ValidateID("id"), taxHandler.FindByID)
+	router.GET("/tax-rules", taxHandler.FindAll)
+
+	router.POST("/taxes/preview", middleware.ValidateDTO(reflect.TypeOf(dto.TaxPreviewDTO{})), taxHandler.Preview)
+}
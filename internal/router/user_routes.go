@@ -1,6 +1,7 @@
 package router
 
 import (
+	"go-sales/internal/auth"
 	"go-sales/internal/config"
 	"go-sales/internal/database"
 	"go-sales/internal/dto"
@@ -22,14 +23,23 @@ func SetupUserRoutes(router *gin.RouterGroup, db *gorm.DB, cfg *config.Config) {
 
 	// 2. Inicializar o Serviço
 	userService := service.NewUserService(userRepo, userCompanyRepo, userRoleRepo)
+	credentialService := service.NewCredentialService(userRepo, service.LockoutPolicy{
+		MaxAttempts: cfg.AppLoginMaxAttempts,
+		BaseLockout: cfg.AppLoginLockout,
+		MaxLockout:  cfg.AppLoginMaxLockout,
+	})
 
 	// 3. Inicializar o Handler
 	userHandler := handler.NewUserHandler(userService, cfg)
+	credentialHandler := handler.NewCredentialHandler(credentialService)
 
 	// 4. Definir as rotas para /users
 	router.POST("/users", middleware.ValidateDTO(reflect.TypeOf(dto.CreateUserDTO{})), userHandler.Create)
 	router.PUT("/users/:id", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.CreateUserDTO{})), userHandler.Update)
+	router.PATCH("/users/:id", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.UpdateUserDTO{})), userHandler.Patch)
 	router.DELETE("/users/:id", middleware.ValidateID("id"), userHandler.Delete)
 	router.GET("/users/:id", middleware.ValidateID("id"), userHandler.FindByID)
 	router.GET("/users", userHandler.FindAll)
+	// O desbloqueio exige a permissão users.unlock e só vale para os usuários da empresa do Principal.
+	router.POST("/users/:id/unlock", middleware.RequirePermission(auth.PermissionUnlockUsers), middleware.ValidateID("id"), credentialHandler.Unlock)
 }
@@ -0,0 +1,33 @@
+package router
+
+import (
+	"go-sales/internal/config"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/handler"
+	"go-sales/internal/middleware"
+	"go-sales/internal/service"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupWebhookRoutes encapsula a configuração das rotas de webhooks e do log de entregas.
+func SetupWebhookRoutes(router *gin.RouterGroup, db *gorm.DB, cfg *config.Config) {
+	webhookRepo := database.NewWebhookRepository(db)
+	companyRepo := database.NewCompanyGlobalRepository(db)
+	webhookService := service.NewWebhookService(db, webhookRepo, companyRepo, cfg.AppWebhookAllowPrivateNetworks)
+	webhookHandler := handler.NewWebhookHandler(webhookService, cfg)
+
+	router.POST("/webhooks", middleware.ValidateDTO(reflect.TypeOf(dto.CreateWebhookDTO{})), webhookHandler.Create)
+	router.PATCH("/webhooks/:id", middleware.ValidateID("id"), middleware.ValidateDTO(reflect.TypeOf(dto.UpdateWebhookDTO{})), webhookHandler.Patch)
+	router.DELETE("/webhooks/:id", middleware.ValidateID("id"), webhookHandler.Delete)
+	router.GET("/webhooks/:id", middleware.ValidateID("id"), webhookHandler.FindByID)
+	router.GET("/webhooks", webhookHandler.FindAll)
+
+	router.POST("/webhooks/:id/ping", middleware.ValidateID("id"), webhookHandler.Ping)
+	router.GET("/webhooks/:id/deliveries", middleware.ValidateID("id"), webhookHandler.FindDeliveries)
+	router.GET("/webhooks/:id/deliveries/:deliveryId", middleware.ValidateID("id"), middleware.ValidateID("deliveryId"), webhookHandler.FindDelivery)
+	router.POST("/webhooks/:id/deliveries/:deliveryId/replay", middleware.ValidateID("id"), middleware.ValidateID("deliveryId"), webhookHandler.Replay)
+}
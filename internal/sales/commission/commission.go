@@ -0,0 +1,104 @@
+package commission
+
+import (
+	"fmt"
+
+	"go-sales/pkg/decimal"
+)
+
+// Line é um item da venda faturada. Discount é o desconto do item (não unitário).
+type Line struct {
+	Category  string
+	Quantity  decimal.Decimal
+	UnitPrice decimal.Decimal
+	Discount  decimal.Decimal
+}
+
+// LineResult é a comissão de um item.
+type LineResult struct {
+	Category string
+	// Value é quantidade x preço unitário; Base é o valor menos o desconto.
+	Value    decimal.Decimal
+	Discount decimal.Decimal
+	// DiscountRate é o desconto em % do valor, com 2 casas; é o critério dos planos KindDiscount.
+	DiscountRate decimal.Decimal
+	Base         decimal.Decimal
+	Rate         decimal.Decimal
+	Amount       decimal.Decimal
+}
+
+// Result é a comissão da venda: os itens e a soma das bases e das comissões.
+type Result struct {
+	Lines  []LineResult
+	Base   decimal.Decimal
+	Amount decimal.Decimal
+	// Volume é o volume do mês usado na escolha da faixa dos planos KindVolume, já com esta venda.
+	Volume decimal.Decimal
+}
+
+// LineError é um problema em um item (numerado a partir de 1).
+type LineError struct {
+	Line    int
+	Problem string
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("commission: line %d: %s", e.Line, e.Problem)
+}
+
+// Calculate calcula a comissão de cada item pelo plano. monthVolume é a base já faturada pelo
+// vendedor no mês antes desta venda (líquida dos estornos); nos planos KindVolume a faixa é escolhida
+// pelo volume do mês somado à base desta venda, e vale para todos os itens dela. Cada valor é
+// arredondado para 2 casas (metade para longe do zero) e os totais somam os valores arredondados.
+func Calculate(plan Plan, lines []Line, monthVolume decimal.Decimal) (*Result, error) {
+	result := &Result{Lines: make([]LineResult, len(lines))}
+	for i, line := range lines {
+		if line.Quantity.Sign() <= 0 || line.UnitPrice.Sign() < 0 {
+			return nil, &LineError{Line: i + 1, Problem: "quantity must be positive and unit price cannot be negative"}
+		}
+		value := line.Quantity.Mul(line.UnitPrice).Round(2)
+		discount := line.Discount.Round(2)
+		if discount.Sign() < 0 || discount.Cmp(value) > 0 {
+			return nil, &LineError{Line: i + 1, Problem: "discount must be between 0 and the line value"}
+		}
+		r := LineResult{
+			Category: line.Category,
+			Value:    value,
+			Discount: discount,
+			Base:     value.Sub(discount),
+		}
+		if !value.IsZero() {
+			r.DiscountRate = discount.Mul(decimal.FromInt(100)).Div(value).Round(2)
+		}
+		result.Lines[i] = r
+		result.Base = result.Base.Add(r.Base)
+	}
+	result.Volume = monthVolume.Add(result.Base)
+
+	for i := range result.Lines {
+		r := &result.Lines[i]
+		switch plan.Kind {
+		case KindCategory:
+			r.Rate = plan.categoryRate(r.Category)
+		case KindDiscount:
+			r.Rate = plan.discountRate(r.DiscountRate)
+		case KindVolume:
+			r.Rate = plan.volumeRate(result.Volume)
+		default:
+			return nil, fmt.Errorf("commission: plan %d has unknown kind %q", plan.ID, plan.Kind)
+		}
+		r.Amount = r.Base.Percent(r.Rate).Round(2)
+		result.Amount = result.Amount.Add(r.Amount)
+	}
+	return result, nil
+}
+
+// Reversal devolve a comissão a estornar (positiva) quando returnedBase da base de uma venda é
+// cancelada ou devolvida: a parte proporcional de amount, arredondada para 2 casas. Quem estorna o
+// saldo inteiro deve usar o saldo da comissão, para não acumular diferenças de arredondamento.
+func Reversal(base, amount, returnedBase decimal.Decimal) decimal.Decimal {
+	if base.IsZero() {
+		return decimal.Zero
+	}
+	return amount.Mul(returnedBase).Div(base).Round(2)
+}
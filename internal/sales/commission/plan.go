@@ -0,0 +1,142 @@
+// Package commission calcula a comissão dos vendedores (usuários) sobre as vendas faturadas, a partir
+// do plano de comissão vigente para cada um. O pacote não acessa o banco: o plano e o volume do mês
+// chegam prontos em Calculate.
+package commission
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-sales/pkg/decimal"
+)
+
+// Tipos de plano: o critério que escolhe o percentual de cada item.
+const (
+	// KindCategory paga o percentual da categoria do produto.
+	KindCategory = "category"
+	// KindDiscount paga o percentual da faixa de desconto do item (a menor faixa que cobre o desconto).
+	KindDiscount = "discount"
+	// KindVolume paga o percentual da faixa alcançada pelo volume faturado do vendedor no mês.
+	KindVolume = "volume"
+)
+
+// Rule é uma faixa do plano. Só o critério do tipo do plano é preenchido: Category (KindCategory),
+// MaxDiscount em % (KindDiscount) ou MinVolume (KindVolume). Rate é o percentual da comissão.
+type Rule struct {
+	Category    string
+	MaxDiscount *decimal.Decimal
+	MinVolume   *decimal.Decimal
+	Rate        decimal.Decimal
+}
+
+// Plan é um plano de comissão. DefaultRate vale para os itens que nenhuma faixa cobre.
+type Plan struct {
+	ID          int64
+	Kind        string
+	DefaultRate decimal.Decimal
+	Rules       []Rule
+}
+
+// Validate confere o tipo, os percentuais e as faixas do plano e devolve os problemas encontrados.
+func (p *Plan) Validate() []string {
+	var problems []string
+	check := func(ok bool, problem string) {
+		if !ok {
+			problems = append(problems, problem)
+		}
+	}
+	hundred := decimal.FromInt(100)
+	validRate := func(rate decimal.Decimal) bool {
+		return rate.Sign() >= 0 && rate.Cmp(hundred) <= 0
+	}
+
+	check(p.Kind == KindCategory || p.Kind == KindDiscount || p.Kind == KindVolume, "kind must be category, discount or volume")
+	check(validRate(p.DefaultRate), "defaultRate must be between 0 and 100")
+
+	seen := make(map[string]bool, len(p.Rules))
+	for i, rule := range p.Rules {
+		prefix := "rules[" + strconv.Itoa(i) + "]"
+		check(validRate(rule.Rate), prefix+".rate must be between 0 and 100")
+
+		var key string
+		switch p.Kind {
+		case KindCategory:
+			check(strings.TrimSpace(rule.Category) != "" && rule.MaxDiscount == nil && rule.MinVolume == nil, prefix+" must have only a category")
+			key = normalizeCategory(rule.Category)
+		case KindDiscount:
+			check(rule.Category == "" && rule.MaxDiscount != nil && rule.MinVolume == nil, prefix+" must have only a maxDiscount")
+			if rule.MaxDiscount != nil {
+				check(validRate(*rule.MaxDiscount), prefix+".maxDiscount must be between 0 and 100")
+				key = rule.MaxDiscount.String()
+			}
+		case KindVolume:
+			check(rule.Category == "" && rule.MaxDiscount == nil && rule.MinVolume != nil, prefix+" must have only a minVolume")
+			if rule.MinVolume != nil {
+				check(rule.MinVolume.Sign() >= 0, prefix+".minVolume cannot be negative")
+				key = rule.MinVolume.String()
+			}
+		}
+		if key != "" {
+			check(!seen[key], prefix+" repeats the criterion of another rule")
+			seen[key] = true
+		}
+	}
+	return problems
+}
+
+// categoryRate devolve o percentual da categoria (sem diferenciar maiúsculas nem espaços nas pontas)
+// ou o padrão do plano.
+func (p *Plan) categoryRate(category string) decimal.Decimal {
+	category = normalizeCategory(category)
+	for _, rule := range p.Rules {
+		if category != "" && normalizeCategory(rule.Category) == category {
+			return rule.Rate
+		}
+	}
+	return p.DefaultRate
+}
+
+// discountRate devolve o percentual da menor faixa cujo MaxDiscount cobre o desconto, ou o padrão do
+// plano quando o desconto passa de todas as faixas.
+func (p *Plan) discountRate(discount decimal.Decimal) decimal.Decimal {
+	var best *Rule
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if rule.MaxDiscount == nil || discount.Cmp(*rule.MaxDiscount) > 0 {
+			continue
+		}
+		if best == nil || rule.MaxDiscount.Cmp(*best.MaxDiscount) < 0 {
+			best = rule
+		}
+	}
+	if best == nil {
+		return p.DefaultRate
+	}
+	return best.Rate
+}
+
+// volumeRate devolve o percentual da maior faixa cujo MinVolume o volume alcança, ou o padrão do
+// plano abaixo da primeira faixa.
+func (p *Plan) volumeRate(volume decimal.Decimal) decimal.Decimal {
+	tiers := make([]Rule, 0, len(p.Rules))
+	for _, rule := range p.Rules {
+		if rule.MinVolume != nil {
+			tiers = append(tiers, rule)
+		}
+	}
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].MinVolume.Cmp(*tiers[j].MinVolume) < 0 })
+
+	rate := p.DefaultRate
+	for _, tier := range tiers {
+		if volume.Cmp(*tier.MinVolume) < 0 {
+			break
+		}
+		rate = tier.Rate
+	}
+	return rate
+}
+
+func normalizeCategory(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// AddressProvider consulta CEPs e valida os endereços gravados. LocalAddressProvider usa a base
+// CEP/IBGE importada no banco; outra fonte (ex: uma API externa) pode ser usada implementando esta
+// interface e trocando o provider nas rotas.
+type AddressProvider interface {
+	LookupPostalCode(postalCode string) (*dto.AddressLookupDTO, ErrorUtil)
+	NormalizeAddress(address *model.CompanyGlobalAddress) ErrorUtil
+}
+
+type LocalAddressProvider struct {
+	repo database.PostalCodeRepositoryInterface
+}
+
+func NewLocalAddressProvider(repo database.PostalCodeRepositoryInterface) AddressProvider {
+	return &LocalAddressProvider{
+		repo: repo,
+	}
+}
+
+func (p *LocalAddressProvider) LookupPostalCode(postalCode string) (*dto.AddressLookupDTO, ErrorUtil) {
+	digits := util.OnlyDigits(postalCode)
+	if len(digits) != 8 {
+		return nil, ErrInvalidPostalCode
+	}
+
+	record, err := p.repo.FindByPostalCode(digits)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPostalCodeNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Str("postal_code", digits).
+			Msg("failed to lookup postal code")
+		return nil, GormDefaultError(err)
+	}
+	return mapper.MapToAddressLookupDTO(record), nil
+}
+
+// NormalizeAddress valida e padroniza endereços no Brasil: a UF deve existir, a cidade deve estar na
+// lista de municípios do IBGE daquela UF (sem diferenciar acentos e maiúsculas) e o CEP deve ter 8
+// dígitos. A cidade recebe o nome oficial e o código IBGE. Endereços de outros países são mantidos
+// como vieram, sem código IBGE.
+func (p *LocalAddressProvider) NormalizeAddress(address *model.CompanyGlobalAddress) ErrorUtil {
+	if address == nil {
+		return nil
+	}
+	if !util.IsBrazil(address.Country) {
+		address.CityIBGECode = nil
+		return nil
+	}
+
+	uf := strings.ToUpper(strings.TrimSpace(address.State))
+	if !util.IsValidUF(uf) {
+		return ErrInvalidState
+	}
+	postalCode := util.OnlyDigits(address.PostalCode)
+	if len(postalCode) != 8 {
+		return ErrInvalidPostalCode
+	}
+
+	municipalities, err := p.repo.FindMunicipalitiesByUF(uf)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Str("uf", uf).
+			Msg("failed to find municipalities")
+		return GormDefaultError(err)
+	}
+	city := util.FoldText(address.City)
+	for _, municipality := range municipalities {
+		if util.FoldText(municipality.Name) == city {
+			code := municipality.IBGECode
+			address.City = municipality.Name
+			address.State = uf
+			address.PostalCode = postalCode
+			address.CityIBGECode = &code
+			return nil
+		}
+	}
+	return ErrCityNotFound
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"errors"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
+	"go-sales/internal/model"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// BankAccountServiceInterface define a gestão das contas bancárias das empresas.
+type BankAccountServiceInterface interface {
+	Create(accountDTO dto.CreateBankAccountDTO) (*dto.BankAccountDTO, ErrorUtil)
+	Patch(accountDTO dto.UpdateBankAccountDTO, id int64, version int64) (*dto.BankAccountDTO, ErrorUtil)
+	Delete(id int64, version int64) ErrorUtil
+	FindByID(id int64, opts dto.ReadOptions) (*dto.BankAccountDTO, ErrorUtil)
+	FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.BankAccountDTO], ErrorUtil)
+}
+
+// bankAccountService é a implementação concreta.
+type bankAccountService struct {
+	repo              database.BankAccountRepositoryInterface
+	repoCompanyGlobal database.CompanyGlobalRepositoryInterface
+}
+
+// NewBankAccountService cria uma nova instância do serviço de contas bancárias.
+func NewBankAccountService(repo database.BankAccountRepositoryInterface, repoCompanyGlobal database.CompanyGlobalRepositoryInterface) BankAccountServiceInterface {
+	return &bankAccountService{
+		repo:              repo,
+		repoCompanyGlobal: repoCompanyGlobal,
+	}
+}
+
+func (s *bankAccountService) Create(accountDTO dto.CreateBankAccountDTO) (*dto.BankAccountDTO, ErrorUtil) {
+	companyExists, errCompanyExists := CheckCompanyGlobalExists(s.repoCompanyGlobal, accountDTO.CompanyGlobalID.Int64(), false)
+	if errCompanyExists != nil {
+		return nil, errCompanyExists
+	}
+	if !companyExists {
+		return nil, ErrCompanyGlobalNotFound
+	}
+
+	account := mapper.MapCreateToBankAccount(&accountDTO)
+	if err := s.repo.Create(account); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Str("company_global_id", accountDTO.CompanyGlobalID.String()).
+			Msg("failed to create bank account")
+		return nil, GormDefaultError(err)
+	}
+	return mapper.MapToBankAccountDTO(account), nil
+}
+
+func (s *bankAccountService) Patch(accountDTO dto.UpdateBankAccountDTO, id int64, version int64) (*dto.BankAccountDTO, ErrorUtil) {
+	existing, errFind := s.find(id, dto.FullRead)
+	if errFind != nil {
+		return nil, errFind
+	}
+
+	version, errVersion := CheckVersion(version, existing.Version)
+	if errVersion != nil {
+		return nil, errVersion
+	}
+
+	columns := mapper.MapUpdateBankAccountToColumns(&accountDTO)
+	if err := s.repo.Patch(id, version, columns); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("bank_account_id", id).
+			Msg("failed to patch bank account")
+		return nil, GormDefaultError(err)
+	}
+	return s.FindByID(id, dto.FullRead)
+}
+
+func (s *bankAccountService) Delete(id int64, version int64) ErrorUtil {
+	if err := s.repo.Delete(id, version); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrBankAccountNotFound
+		}
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("bank_account_id", id).
+			Msg("failed to delete bank account")
+		return GormDefaultError(err)
+	}
+	return nil
+}
+
+func (s *bankAccountService) FindByID(id int64, opts dto.ReadOptions) (*dto.BankAccountDTO, ErrorUtil) {
+	account, errFind := s.find(id, opts)
+	if errFind != nil {
+		return nil, errFind
+	}
+	return mapper.MapToBankAccountDTO(account), nil
+}
+
+func (s *bankAccountService) find(id int64, opts dto.ReadOptions) (*model.BankAccount, ErrorUtil) {
+	return findBankAccount(s.repo, id, opts)
+}
+
+func (s *bankAccountService) FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.BankAccountDTO], ErrorUtil) {
+	companyExists, errCompanyExists := CheckCompanyGlobalExists(s.repoCompanyGlobal, companyGlobalID, false)
+	if errCompanyExists != nil {
+		log.Error().
+			Err(errCompanyExists).
+			Caller().
+			Str("company_global_id", strconv.FormatInt(companyGlobalID, 10)).
+			Msg("failed to check if company global exists")
+		return nil, errCompanyExists
+	}
+	if !companyExists {
+		return nil, ErrCompanyGlobalNotFound
+	}
+
+	accounts, pageInfo, err := s.repo.FindAll(filters, page, opts, companyGlobalID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to findAll bank accounts")
+		return nil, GormDefaultError(err)
+	}
+
+	items := make([]dto.BankAccountDTO, len(accounts))
+	for i := range accounts {
+		items[i] = *mapper.MapToBankAccountDTO(&accounts[i])
+	}
+	return &dto.PaginatedResponse[dto.BankAccountDTO]{
+		Items:    items,
+		PageInfo: pageInfo,
+	}, nil
+}
+
+// findBankAccount busca a conta bancária id; os serviços de boleto e de extrato a usam para validar a
+// conta informada.
+func findBankAccount(repo database.BankAccountRepositoryInterface, id int64, opts dto.ReadOptions) (*model.BankAccount, ErrorUtil) {
+	account, err := repo.FindByID(id, opts)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrBankAccountNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("bank_account_id", id).
+			Msg("failed to find bank account")
+		return nil, GormDefaultError(err)
+	}
+	return account, nil
+}
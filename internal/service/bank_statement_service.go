@@ -0,0 +1,378 @@
+package service
+
+import (
+	"errors"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
+	"go-sales/internal/model"
+	"go-sales/internal/payment/statement"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// defaultStatementMatchDays é a janela padrão, em dias, entre o crédito e o vencimento da parcela.
+const defaultStatementMatchDays = 5
+
+// candidateMarginDays amplia a busca de parcelas além da janela: um crédito fora dela ainda pode ser
+// sugerido pelo valor e pelo número do documento.
+const candidateMarginDays = 60
+
+// BankStatementServiceInterface define a importação dos extratos das contas bancárias, a conciliação
+// automática dos créditos com as parcelas em aberto e a confirmação, rejeição ou vínculo manual das
+// sugestões.
+type BankStatementServiceInterface interface {
+	// Import lê o extrato (format "ofx" ou "csv"; vazio detecta pelo nome e pelo conteúdo), grava os
+	// lançamentos novos da conta e sugere a parcela de cada crédito.
+	Import(bankAccountID int64, format string, fileName string, content []byte) (*dto.BankStatementImportDTO, ErrorUtil)
+	FindLines(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, bankAccountID int64) (*dto.PaginatedResponse[dto.BankStatementLineDTO], ErrorUtil)
+	FindLine(id int64) (*dto.BankStatementLineDTO, ErrorUtil)
+	// Confirm aceita a parcela sugerida e a baixa com o crédito do extrato.
+	Confirm(id int64) (*dto.BankStatementLineDTO, ErrorUtil)
+	// Reject descarta a parcela sugerida; o lançamento pode ser vinculado manualmente depois.
+	Reject(id int64) (*dto.BankStatementLineDTO, ErrorUtil)
+	// Link concilia o lançamento com uma parcela escolhida pelo usuário e a baixa.
+	Link(id int64, linkDTO dto.LinkStatementLineDTO) (*dto.BankStatementLineDTO, ErrorUtil)
+}
+
+// bankStatementService é a implementação concreta.
+type bankStatementService struct {
+	repo            database.BankStatementRepositoryInterface
+	repoBankAccount database.BankAccountRepositoryInterface
+	repoInstallment database.InstallmentRepositoryInterface
+	matchDays       int
+}
+
+// NewBankStatementService cria uma nova instância do serviço de extratos. matchDays é a janela da
+// conciliação automática; zero usa o padrão.
+func NewBankStatementService(repo database.BankStatementRepositoryInterface, repoBankAccount database.BankAccountRepositoryInterface, repoInstallment database.InstallmentRepositoryInterface, matchDays int) BankStatementServiceInterface {
+	if matchDays <= 0 {
+		matchDays = defaultStatementMatchDays
+	}
+	return &bankStatementService{
+		repo:            repo,
+		repoBankAccount: repoBankAccount,
+		repoInstallment: repoInstallment,
+		matchDays:       matchDays,
+	}
+}
+
+// statementFormat escolhe o formato pela extensão do arquivo ou, sem ela, pelo conteúdo.
+func statementFormat(format string, fileName string, content []byte) string {
+	if format != "" {
+		return strings.ToLower(format)
+	}
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".ofx", ".qfx":
+		return statement.FormatOFX
+	case ".csv", ".txt":
+		return statement.FormatCSV
+	}
+	head := strings.ToUpper(string(content[:min(len(content), 1024)]))
+	if strings.Contains(head, "OFXHEADER") || strings.Contains(head, "<OFX>") {
+		return statement.FormatOFX
+	}
+	return statement.FormatCSV
+}
+
+// Import grava os lançamentos e concilia os créditos novos. Reimportar um extrato não duplica
+// lançamentos: os que a conta já tem (pelo FITID ou pelo hash do lançamento) entram só na contagem de
+// duplicados. Débitos são gravados como ignored.
+func (s *bankStatementService) Import(bankAccountID int64, format string, fileName string, content []byte) (*dto.BankStatementImportDTO, ErrorUtil) {
+	account, errAccount := findBankAccount(s.repoBankAccount, bankAccountID, dto.FullRead)
+	if errAccount != nil {
+		return nil, errAccount
+	}
+
+	format = statementFormat(format, fileName, content)
+	transactions, err := statement.Parse(format, content)
+	if err != nil {
+		return nil, ErrInvalidStatementFile
+	}
+
+	lines := make([]model.BankStatementLine, len(transactions))
+	for i, transaction := range transactions {
+		lines[i] = model.BankStatementLine{
+			ExternalID:     transaction.ID,
+			PostedAt:       transaction.PostedAt,
+			Amount:         transaction.Amount.Round(2),
+			Description:    truncatedOrNil(transaction.Description, 255),
+			DocumentNumber: truncatedOrNil(transaction.DocumentNumber, 60),
+			Status:         model.StatementLineUnmatched,
+		}
+		if transaction.Amount.Sign() < 0 {
+			lines[i].Status = model.StatementLineIgnored
+		}
+	}
+
+	statementImport := &model.BankStatementImport{
+		CompanyGlobalID: account.CompanyGlobalID,
+		BankAccountID:   account.ID,
+		Format:          format,
+		FileName:        fileName,
+	}
+	inserted, err := s.repo.CreateImport(statementImport, lines)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("bank_account_id", bankAccountID).
+			Msg("failed to import bank statement")
+		return nil, GormDefaultError(err)
+	}
+
+	if errMatch := s.match(statementImport, inserted); errMatch != nil {
+		return nil, errMatch
+	}
+	log.Info().
+		Int64("statement_import_id", statementImport.ID).
+		Int64("bank_account_id", bankAccountID).
+		Int("lines", statementImport.LineCount).
+		Int("duplicates", statementImport.DuplicateCount).
+		Int("suggested", statementImport.SuggestedCount).
+		Msg("bank statement imported")
+	return mapper.MapToBankStatementImportDTO(statementImport, inserted), nil
+}
+
+// match sugere uma parcela para cada crédito importado e atualiza lines com as sugestões gravadas.
+func (s *bankStatementService) match(statementImport *model.BankStatementImport, lines []model.BankStatementLine) ErrorUtil {
+	var transactions []statement.Transaction
+	var credits []int
+	for i, line := range lines {
+		if line.Status != model.StatementLineUnmatched {
+			continue
+		}
+		transactions = append(transactions, statement.Transaction{
+			PostedAt:       line.PostedAt,
+			Amount:         line.Amount,
+			Description:    stringOrEmpty(line.Description),
+			DocumentNumber: stringOrEmpty(line.DocumentNumber),
+		})
+		credits = append(credits, i)
+	}
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	from, to := transactions[0].PostedAt, transactions[0].PostedAt
+	for _, transaction := range transactions {
+		if transaction.PostedAt.Before(from) {
+			from = transaction.PostedAt
+		}
+		if transaction.PostedAt.After(to) {
+			to = transaction.PostedAt
+		}
+	}
+	installments, err := s.repo.FindCandidates(statementImport.CompanyGlobalID,
+		from.AddDate(0, 0, -candidateMarginDays), to.AddDate(0, 0, candidateMarginDays))
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("statement_import_id", statementImport.ID).
+			Msg("failed to find installments to match")
+		return GormDefaultError(err)
+	}
+	candidates := make([]statement.Candidate, len(installments))
+	for i, installment := range installments {
+		candidates[i] = statement.Candidate{
+			ID:             installment.ID,
+			Amount:         installment.Amount,
+			DueDate:        installment.DueDate,
+			DocumentNumber: installment.DocumentNumber,
+		}
+	}
+
+	matches := statement.Suggest(transactions, candidates, s.matchDays)
+	suggestions := make([]database.StatementSuggestion, len(matches))
+	for i, match := range matches {
+		suggestions[i] = database.StatementSuggestion{
+			LineID:        lines[credits[match.Index]].ID,
+			InstallmentID: match.CandidateID,
+			Confidence:    match.Confidence,
+			Reasons:       match.Reasons,
+		}
+	}
+	saved, err := s.repo.SaveSuggestions(statementImport.ID, suggestions)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("statement_import_id", statementImport.ID).
+			Msg("failed to save statement suggestions")
+		return GormDefaultError(err)
+	}
+
+	statementImport.SuggestedCount = len(saved)
+	byLine := make(map[int64]database.StatementSuggestion, len(saved))
+	for _, suggestion := range saved {
+		byLine[suggestion.LineID] = suggestion
+	}
+	for i := range lines {
+		suggestion, ok := byLine[lines[i].ID]
+		if !ok {
+			continue
+		}
+		lines[i].Status = model.StatementLineSuggested
+		lines[i].InstallmentID = &suggestion.InstallmentID
+		lines[i].Confidence = &suggestion.Confidence
+		lines[i].MatchReasons = suggestion.Reasons
+	}
+	return nil
+}
+
+func (s *bankStatementService) FindLines(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, bankAccountID int64) (*dto.PaginatedResponse[dto.BankStatementLineDTO], ErrorUtil) {
+	if _, errAccount := findBankAccount(s.repoBankAccount, bankAccountID, dto.ReadOptions{Fields: []string{"id"}}); errAccount != nil {
+		return nil, errAccount
+	}
+
+	lines, pageInfo, err := s.repo.FindLines(filters, page, opts, bankAccountID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to findAll statement lines")
+		return nil, GormDefaultError(err)
+	}
+
+	items := make([]dto.BankStatementLineDTO, len(lines))
+	for i := range lines {
+		items[i] = *mapper.MapToBankStatementLineDTO(&lines[i])
+	}
+	return &dto.PaginatedResponse[dto.BankStatementLineDTO]{
+		Items:    items,
+		PageInfo: pageInfo,
+	}, nil
+}
+
+func (s *bankStatementService) findLine(id int64) (*model.BankStatementLine, ErrorUtil) {
+	line, err := s.repo.FindLine(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrStatementLineNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("statement_line_id", id).
+			Msg("failed to find statement line")
+		return nil, GormDefaultError(err)
+	}
+	return line, nil
+}
+
+func (s *bankStatementService) FindLine(id int64) (*dto.BankStatementLineDTO, ErrorUtil) {
+	line, errFind := s.findLine(id)
+	if errFind != nil {
+		return nil, errFind
+	}
+	return mapper.MapToBankStatementLineDTO(line), nil
+}
+
+func (s *bankStatementService) Confirm(id int64) (*dto.BankStatementLineDTO, ErrorUtil) {
+	line, errFind := s.findLine(id)
+	if errFind != nil {
+		return nil, errFind
+	}
+	if line.Status != model.StatementLineSuggested || line.InstallmentID == nil {
+		return nil, ErrStatementLineNotSuggested
+	}
+	return s.reconcile(line, *line.InstallmentID, ErrStatementLineNotSuggested, model.StatementLineSuggested)
+}
+
+func (s *bankStatementService) Link(id int64, linkDTO dto.LinkStatementLineDTO) (*dto.BankStatementLineDTO, ErrorUtil) {
+	line, errFind := s.findLine(id)
+	if errFind != nil {
+		return nil, errFind
+	}
+	switch line.Status {
+	case model.StatementLineUnmatched, model.StatementLineSuggested, model.StatementLineRejected:
+	default:
+		return nil, ErrStatementLineNotLinkable
+	}
+	return s.reconcile(line, linkDTO.InstallmentID.Int64(), ErrStatementLineNotLinkable, model.StatementLineUnmatched, model.StatementLineSuggested, model.StatementLineRejected)
+}
+
+// reconcile concilia o lançamento com a parcela e, se ela estiver em aberto e o crédito for do valor
+// dela, a baixa com o valor e a data do crédito. Uma parcela já paga por outro meio (ex: boleto) ou
+// com valor diferente do crédito é só vinculada ao lançamento e continua como está, como no PIX e no
+// boleto. errChanged é devolvido quando outra requisição tirou o lançamento dos estados from.
+func (s *bankStatementService) reconcile(line *model.BankStatementLine, installmentID int64, errChanged ErrorUtil, from ...string) (*dto.BankStatementLineDTO, ErrorUtil) {
+	installment, err := s.repoInstallment.FindByID(installmentID, dto.FullRead)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInstallmentNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("installment_id", installmentID).
+			Msg("failed to find installment")
+		return nil, GormDefaultError(err)
+	}
+	if installment.CompanyGlobalID != line.CompanyGlobalID {
+		return nil, ErrBankAccountCompanyMismatch
+	}
+	if installment.Status == model.InstallmentCancelled {
+		return nil, ErrInstallmentCancelled
+	}
+
+	payment := model.InstallmentPayment{
+		Amount:    line.Amount,
+		PaidAt:    line.PostedAt,
+		Method:    model.PaymentMethodBankStatement,
+		Reference: truncate(line.ExternalID, 100),
+	}
+	amountMatches := line.Amount.Cmp(installment.Amount) == 0
+	settled, err := s.repo.Confirm(line.ID, installmentID, payment, installment.Status == model.InstallmentOpen && amountMatches, from, installmentPaid)
+	if errors.Is(err, database.ErrStatementLineStatusChanged) {
+		return nil, errChanged
+	}
+	if err != nil {
+		if GormDefaultError(err) == ErrDuplicateKey {
+			return nil, ErrInstallmentAlreadyMatched
+		}
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("statement_line_id", line.ID).
+			Int64("installment_id", installmentID).
+			Msg("failed to reconcile statement line")
+		return nil, GormDefaultError(err)
+	}
+	if !amountMatches {
+		log.Warn().
+			Int64("statement_line_id", line.ID).
+			Int64("installment_id", installmentID).
+			Str("amount", line.Amount.String()).
+			Msg("statement line reconciled with a different amount; installment left open")
+	}
+	log.Info().
+		Int64("statement_line_id", line.ID).
+		Int64("installment_id", installmentID).
+		Bool("settled", settled).
+		Msg("statement line reconciled")
+	return s.FindLine(line.ID)
+}
+
+func (s *bankStatementService) Reject(id int64) (*dto.BankStatementLineDTO, ErrorUtil) {
+	if _, errFind := s.findLine(id); errFind != nil {
+		return nil, errFind
+	}
+	err := s.repo.Reject(id)
+	if errors.Is(err, database.ErrStatementLineStatusChanged) {
+		return nil, ErrStatementLineNotSuggested
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("statement_line_id", id).
+			Msg("failed to reject statement line suggestion")
+		return nil, GormDefaultError(err)
+	}
+	return s.FindLine(id)
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/model"
+	"go-sales/pkg/decimal"
+	"go-sales/pkg/util"
+)
+
+// fakeStatementRepository guarda um lançamento e registra o que Confirm recebeu.
+type fakeStatementRepository struct {
+	database.BankStatementRepositoryInterface
+
+	line    model.BankStatementLine
+	settle  bool
+	payment model.InstallmentPayment
+}
+
+func (r *fakeStatementRepository) FindLine(id int64) (*model.BankStatementLine, error) {
+	line := r.line
+	return &line, nil
+}
+
+func (r *fakeStatementRepository) Confirm(id int64, installmentID int64, payment model.InstallmentPayment, settle bool, from []string, events ...database.Recorder[model.Installment]) (bool, error) {
+	r.line.Status = model.StatementLineConfirmed
+	r.line.InstallmentID = &installmentID
+	r.settle, r.payment = settle, payment
+	return settle, nil
+}
+
+type fakeInstallmentRepository struct {
+	database.InstallmentRepositoryInterface
+
+	installment model.Installment
+}
+
+func (r *fakeInstallmentRepository) FindByID(id int64, opts dto.ReadOptions) (*model.Installment, error) {
+	installment := r.installment
+	return &installment, nil
+}
+
+func TestLinkSettlesOnlyTheSameAmount(t *testing.T) {
+	tests := []struct {
+		name       string
+		amount     string
+		status     string
+		wantSettle bool
+	}{
+		{"same amount", "150.00", model.InstallmentOpen, true},
+		{"smaller amount", "100.00", model.InstallmentOpen, false},
+		{"larger amount", "1500.00", model.InstallmentOpen, false},
+		{"installment already paid", "150.00", model.InstallmentPaid, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeStatementRepository{line: model.BankStatementLine{
+				ID: 1, CompanyGlobalID: 10, ExternalID: "FIT-1", PostedAt: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+				Amount: decimal.MustParse(tt.amount), Status: model.StatementLineUnmatched,
+			}}
+			installments := &fakeInstallmentRepository{installment: model.Installment{
+				ID: 20, CompanyGlobalID: 10, Amount: decimal.MustParse("150.00"), Status: tt.status,
+			}}
+			svc := &bankStatementService{repo: repo, repoInstallment: installments}
+
+			line, err := svc.Link(1, dto.LinkStatementLineDTO{InstallmentID: util.SnowflakeID(20)})
+			if err != nil {
+				t.Fatalf("Link: %v", err)
+			}
+			if line.Status != model.StatementLineConfirmed {
+				t.Fatalf("line status = %s, want confirmed", line.Status)
+			}
+			if repo.settle != tt.wantSettle {
+				t.Fatalf("settle = %v, want %v", repo.settle, tt.wantSettle)
+			}
+			if !repo.payment.Amount.Equal(decimal.MustParse(tt.amount)) {
+				t.Fatalf("payment amount = %s, want the line amount %s", repo.payment.Amount, tt.amount)
+			}
+		})
+	}
+}
@@ -0,0 +1,498 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
+	"go-sales/internal/model"
+	"go-sales/internal/payment/boleto"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// BoletoServiceInterface define a emissão dos boletos das parcelas, a geração dos arquivos de remessa
+// CNAB e o processamento dos arquivos de retorno, que baixa as parcelas pagas.
+type BoletoServiceInterface interface {
+	Create(installmentID int64, boletoDTO dto.CreateBoletoDTO) (*dto.BoletoDTO, ErrorUtil)
+	FindByID(id int64) (*dto.BoletoDTO, ErrorUtil)
+	FindByInstallment(installmentID int64) ([]dto.BoletoDTO, ErrorUtil)
+	// CreateRemittance gera o arquivo de remessa com os boletos pendentes da conta.
+	CreateRemittance(bankAccountID int64, remittanceDTO dto.CreateBoletoRemittanceDTO) (*dto.BoletoRemittanceDTO, ErrorUtil)
+	FindRemittance(id int64) (*dto.BoletoRemittanceDTO, ErrorUtil)
+	// RemittanceFile devolve o nome e o conteúdo do arquivo de remessa.
+	RemittanceFile(id int64) (string, []byte, ErrorUtil)
+	// ProcessReturn processa um arquivo de retorno da conta e devolve o relatório de conciliação.
+	ProcessReturn(bankAccountID int64, fileName string, content []byte) (*dto.BoletoReturnDTO, ErrorUtil)
+	FindReturn(id int64) (*dto.BoletoReturnDTO, ErrorUtil)
+}
+
+// boletoService é a implementação concreta.
+type boletoService struct {
+	repo              database.BoletoRepositoryInterface
+	repoBankAccount   database.BankAccountRepositoryInterface
+	repoInstallment   database.InstallmentRepositoryInterface
+	repoCompanyGlobal database.CompanyGlobalRepositoryInterface
+}
+
+// NewBoletoService cria uma nova instância do serviço de boletos.
+func NewBoletoService(repo database.BoletoRepositoryInterface, repoBankAccount database.BankAccountRepositoryInterface, repoInstallment database.InstallmentRepositoryInterface, repoCompanyGlobal database.CompanyGlobalRepositoryInterface) BoletoServiceInterface {
+	return &boletoService{
+		repo:              repo,
+		repoBankAccount:   repoBankAccount,
+		repoInstallment:   repoInstallment,
+		repoCompanyGlobal: repoCompanyGlobal,
+	}
+}
+
+// billingAccount converte a conta bancária na conta de cobrança do pacote boleto e confere se o banco
+// tem layout de cobrança e se a conta tem os dados que ele exige.
+func billingAccount(account *model.BankAccount) (boleto.Bank, boleto.Account, ErrorUtil) {
+	billing := boleto.Account{
+		BankCode:        account.BankCode,
+		Agency:          account.Agency,
+		AgencyDigit:     stringOrEmpty(account.AgencyDigit),
+		Number:          account.AccountNumber,
+		NumberDigit:     stringOrEmpty(account.AccountDigit),
+		Wallet:          stringOrEmpty(account.Wallet),
+		WalletVariation: stringOrEmpty(account.WalletVariation),
+		Agreement:       stringOrEmpty(account.Agreement),
+	}
+	bank, err := boleto.BankByCode(account.BankCode)
+	if err != nil {
+		return nil, billing, ErrUnsupportedBoletoBank
+	}
+	if err := bank.Validate(billing); err != nil {
+		return nil, billing, NewError(err.Error(), http.StatusUnprocessableEntity, "invalid_boleto_account", err.Error())
+	}
+	return bank, billing, nil
+}
+
+// Create emite um boleto do valor e do vencimento da parcela, com o próximo nosso número da conta. O
+// boleto fica pending até entrar num arquivo de remessa.
+func (s *boletoService) Create(installmentID int64, boletoDTO dto.CreateBoletoDTO) (*dto.BoletoDTO, ErrorUtil) {
+	installment, err := s.repoInstallment.FindByID(installmentID, dto.FullRead)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInstallmentNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("installment_id", installmentID).
+			Msg("failed to find installment")
+		return nil, GormDefaultError(err)
+	}
+	if installment.Status != model.InstallmentOpen {
+		return nil, ErrInstallmentNotOpen
+	}
+
+	account, errAccount := findBankAccount(s.repoBankAccount, boletoDTO.BankAccountID.Int64(), dto.FullRead)
+	if errAccount != nil {
+		return nil, errAccount
+	}
+	if account.CompanyGlobalID != installment.CompanyGlobalID {
+		return nil, ErrBankAccountCompanyMismatch
+	}
+	_, billing, errBilling := billingAccount(account)
+	if errBilling != nil {
+		return nil, errBilling
+	}
+
+	active, err := s.repo.CountActive(installmentID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("installment_id", installmentID).
+			Msg("failed to count active boletos")
+		return nil, GormDefaultError(err)
+	}
+	if active > 0 {
+		return nil, ErrBoletoAlreadyIssued
+	}
+
+	slip := &model.Boleto{
+		CompanyGlobalID: installment.CompanyGlobalID,
+		BankAccountID:   account.ID,
+		InstallmentID:   installment.ID,
+		Amount:          installment.Amount,
+		DueDate:         installment.DueDate,
+		Status:          model.BoletoPending,
+	}
+	var errGenerate error
+	err = s.repo.Create(slip, func(ourNumber int64) error {
+		code, err := boleto.Generate(billing, ourNumber, slip.Amount, slip.DueDate)
+		if err != nil {
+			errGenerate = err
+			return err
+		}
+		slip.Barcode = code.Barcode
+		slip.DigitableLine = code.DigitableLine
+		return nil
+	})
+	if errGenerate != nil {
+		return nil, NewError(errGenerate.Error(), http.StatusUnprocessableEntity, "boleto_generation_failed", errGenerate.Error())
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("installment_id", installmentID).
+			Msg("failed to create boleto")
+		return nil, GormDefaultError(err)
+	}
+	log.Info().Int64("boleto_id", slip.ID).Int64("installment_id", installmentID).Int64("our_number", slip.OurNumber).Msg("boleto created")
+	return mapper.MapToBoletoDTO(slip), nil
+}
+
+func (s *boletoService) FindByID(id int64) (*dto.BoletoDTO, ErrorUtil) {
+	slip, err := s.repo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrBoletoNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("boleto_id", id).
+			Msg("failed to find boleto")
+		return nil, GormDefaultError(err)
+	}
+	return mapper.MapToBoletoDTO(slip), nil
+}
+
+func (s *boletoService) FindByInstallment(installmentID int64) ([]dto.BoletoDTO, ErrorUtil) {
+	if _, err := s.repoInstallment.FindByID(installmentID, dto.ReadOptions{Fields: []string{"id"}}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInstallmentNotFound
+		}
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("installment_id", installmentID).
+			Msg("failed to find installment")
+		return nil, GormDefaultError(err)
+	}
+
+	slips, err := s.repo.FindByInstallment(installmentID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("installment_id", installmentID).
+			Msg("failed to find boletos")
+		return nil, GormDefaultError(err)
+	}
+	return mapper.MapToBoletoDTOs(slips), nil
+}
+
+// CreateRemittance monta a remessa com o beneficiário (razão social e CNPJ da empresa) e o pagador de
+// cada parcela. O "uso da empresa" de cada título leva o id do boleto, devolvido pelo banco no retorno.
+func (s *boletoService) CreateRemittance(bankAccountID int64, remittanceDTO dto.CreateBoletoRemittanceDTO) (*dto.BoletoRemittanceDTO, ErrorUtil) {
+	account, errAccount := findBankAccount(s.repoBankAccount, bankAccountID, dto.FullRead)
+	if errAccount != nil {
+		return nil, errAccount
+	}
+	_, billing, errBilling := billingAccount(account)
+	if errBilling != nil {
+		return nil, errBilling
+	}
+	company, err := s.repoCompanyGlobal.FindByID(account.CompanyGlobalID, false, dto.FullRead)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCompanyGlobalNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("company_global_id", account.CompanyGlobalID).
+			Msg("failed to find company global")
+		return nil, GormDefaultError(err)
+	}
+	beneficiary := boleto.Beneficiary{Name: company.SocialName, CGC: company.CGC}
+	if beneficiary.Name == "" {
+		beneficiary.Name = company.Name
+	}
+
+	var errGenerate error
+	remittance, err := s.repo.CreateRemittance(bankAccountID, func(sequence int, slips []model.Boleto) (*model.BoletoRemittance, error) {
+		now := time.Now()
+		titles := make([]boleto.Title, len(slips))
+		for i, slip := range slips {
+			installment, err := s.repoInstallment.FindByID(slip.InstallmentID, dto.FullRead)
+			if err != nil {
+				return nil, err
+			}
+			titles[i] = boleto.Title{
+				OurNumber:      slip.OurNumber,
+				DocumentNumber: installment.DocumentNumber + "/" + strconv.Itoa(installment.InstallmentNumber),
+				Amount:         slip.Amount,
+				DueDate:        slip.DueDate,
+				IssueDate:      slip.CreatedAt,
+				Payer:          boleto.Payer{Name: installment.CustomerName, CGC: stringOrEmpty(installment.CustomerCGC)},
+				ControlNumber:  strconv.FormatInt(slip.ID, 10),
+			}
+		}
+		content, err := boleto.WriteRemittance(boleto.Remittance{
+			Layout:      remittanceDTO.Layout,
+			Account:     billing,
+			Beneficiary: beneficiary,
+			Sequence:    sequence,
+			CreatedAt:   now,
+			Titles:      titles,
+		})
+		if err != nil {
+			errGenerate = err
+			return nil, err
+		}
+		return &model.BoletoRemittance{
+			CompanyGlobalID: account.CompanyGlobalID,
+			Layout:          remittanceDTO.Layout,
+			FileName:        fmt.Sprintf("%s-%s-%06d.rem", account.BankCode, remittanceDTO.Layout, sequence),
+			Content:         string(content),
+		}, nil
+	})
+	switch {
+	case errors.Is(err, database.ErrNoPendingBoletos):
+		return nil, ErrNoPendingBoletos
+	case errGenerate != nil:
+		return nil, NewError(errGenerate.Error(), http.StatusUnprocessableEntity, "boleto_generation_failed", errGenerate.Error())
+	case err != nil:
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("bank_account_id", bankAccountID).
+			Msg("failed to create remittance")
+		return nil, GormDefaultError(err)
+	}
+	log.Info().Int64("remittance_id", remittance.ID).Int64("bank_account_id", bankAccountID).Int("titles", remittance.TitleCount).Msg("boleto remittance created")
+	return mapper.MapToBoletoRemittanceDTO(remittance), nil
+}
+
+func (s *boletoService) findRemittance(id int64) (*model.BoletoRemittance, ErrorUtil) {
+	remittance, err := s.repo.FindRemittance(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrBoletoRemittanceNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("remittance_id", id).
+			Msg("failed to find remittance")
+		return nil, GormDefaultError(err)
+	}
+	return remittance, nil
+}
+
+func (s *boletoService) FindRemittance(id int64) (*dto.BoletoRemittanceDTO, ErrorUtil) {
+	remittance, errFind := s.findRemittance(id)
+	if errFind != nil {
+		return nil, errFind
+	}
+	return mapper.MapToBoletoRemittanceDTO(remittance), nil
+}
+
+func (s *boletoService) RemittanceFile(id int64) (string, []byte, ErrorUtil) {
+	remittance, errFind := s.findRemittance(id)
+	if errFind != nil {
+		return "", nil, errFind
+	}
+	return remittance.FileName, []byte(remittance.Content), nil
+}
+
+// ProcessReturn aplica cada movimento do retorno ao boleto do nosso número e grava o resultado de
+// cada linha. O processamento é idempotente: reenviar o mesmo arquivo não baixa nada duas vezes. Uma
+// liquidação com valor diferente do boleto fica registrada no boleto, mas não baixa a parcela.
+func (s *boletoService) ProcessReturn(bankAccountID int64, fileName string, content []byte) (*dto.BoletoReturnDTO, ErrorUtil) {
+	account, errAccount := findBankAccount(s.repoBankAccount, bankAccountID, dto.FullRead)
+	if errAccount != nil {
+		return nil, errAccount
+	}
+	bank, billing, errBilling := billingAccount(account)
+	if errBilling != nil {
+		return nil, errBilling
+	}
+
+	parsed, err := boleto.ParseReturn(content)
+	if errors.Is(err, boleto.ErrUnsupportedBank) {
+		return nil, ErrUnsupportedBoletoBank
+	}
+	if err != nil {
+		return nil, ErrInvalidReturnFile
+	}
+	if parsed.BankCode != account.BankCode {
+		return nil, ErrReturnBankMismatch
+	}
+
+	ret := &model.BoletoReturn{
+		CompanyGlobalID: account.CompanyGlobalID,
+		BankAccountID:   account.ID,
+		Layout:          parsed.Layout,
+		FileName:        fileName,
+		Lines:           make([]model.BoletoReturnLine, 0, len(parsed.Lines)),
+	}
+	for _, line := range parsed.Lines {
+		result, errLine := s.applyReturnLine(account, bank, billing, line)
+		if errLine != nil {
+			return nil, errLine
+		}
+		ret.Lines = append(ret.Lines, result)
+	}
+	if err := s.repo.CreateReturn(ret); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("bank_account_id", bankAccountID).
+			Msg("failed to save return file")
+		return nil, GormDefaultError(err)
+	}
+	report := mapper.MapToBoletoReturnDTO(ret)
+	log.Info().
+		Int64("boleto_return_id", ret.ID).
+		Int64("bank_account_id", bankAccountID).
+		Int("lines", len(ret.Lines)).
+		Int("settled", report.Summary[model.BoletoReturnSettled]).
+		Int("unmatched", len(report.Unmatched)).
+		Msg("boleto return processed")
+	return report, nil
+}
+
+func (s *boletoService) applyReturnLine(account *model.BankAccount, bank boleto.Bank, billing boleto.Account, line boleto.ReturnLine) (model.BoletoReturnLine, ErrorUtil) {
+	result := model.BoletoReturnLine{
+		LineNumber: line.Line,
+		Occurrence: line.Occurrence,
+		Amount:     &line.Amount,
+		CreditDate: line.CreditDate,
+	}
+	if line.DocumentNumber != "" {
+		result.DocumentNumber = &line.DocumentNumber
+	}
+	if !line.PaidAmount.IsZero() {
+		result.PaidAmount = &line.PaidAmount
+	}
+	if line.Problem != "" {
+		result.Result = model.BoletoReturnInvalid
+		result.Message = &line.Problem
+		return result, nil
+	}
+	result.OurNumber = &line.OurNumber
+
+	slip, err := s.repo.FindByOurNumber(account.ID, line.OurNumber)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Warn().Int64("bank_account_id", account.ID).Int64("our_number", line.OurNumber).Msg("boleto return line without a boleto")
+		result.Result = model.BoletoReturnUnmatched
+		return result, nil
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("our_number", line.OurNumber).
+			Msg("failed to find boleto")
+		return result, GormDefaultError(err)
+	}
+	result.BoletoID = &slip.ID
+	result.InstallmentID = &slip.InstallmentID
+
+	switch line.Kind() {
+	case boleto.OccurrenceRegistered:
+		return s.moveBoleto(result, slip, model.BoletoRegistered, model.BoletoReturnRegistered, model.BoletoPending, model.BoletoSent)
+	case boleto.OccurrenceRejected:
+		return s.moveBoleto(result, slip, model.BoletoRejected, model.BoletoReturnRejected, model.BoletoPending, model.BoletoSent)
+	case boleto.OccurrenceWrittenOff:
+		return s.moveBoleto(result, slip, model.BoletoWrittenOff, model.BoletoReturnWrittenOff, model.BoletoPending, model.BoletoSent, model.BoletoRegistered)
+	case boleto.OccurrenceSettled:
+		return s.settleBoleto(result, slip, bank, billing, line)
+	}
+	result.Result = model.BoletoReturnIgnored
+	return result, nil
+}
+
+// moveBoleto muda o estado do boleto pela ocorrência do retorno; um boleto que já saiu dos estados from
+// (ex: a mesma confirmação de entrada recebida de novo) não muda.
+func (s *boletoService) moveBoleto(result model.BoletoReturnLine, slip *model.Boleto, status, lineResult string, from ...string) (model.BoletoReturnLine, ErrorUtil) {
+	err := s.repo.SetStatus(slip.ID, status, from...)
+	switch {
+	case errors.Is(err, database.ErrBoletoStatusChanged):
+		result.Result = model.BoletoReturnAlreadyProcessed
+	case err != nil:
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("boleto_id", slip.ID).
+			Msg("failed to update boleto status")
+		return result, GormDefaultError(err)
+	default:
+		result.Result = lineResult
+	}
+	return result, nil
+}
+
+// settleBoleto registra a liquidação. O valor pago confere quando, descontados juros e somado o
+// desconto, é igual ao valor do boleto.
+func (s *boletoService) settleBoleto(result model.BoletoReturnLine, slip *model.Boleto, bank boleto.Bank, billing boleto.Account, line boleto.ReturnLine) (model.BoletoReturnLine, ErrorUtil) {
+	if slip.Status == model.BoletoPaid {
+		result.Result = model.BoletoReturnAlreadyProcessed
+		return result, nil
+	}
+	// ParseReturn já recusa a liquidação sem o valor pago; o valor do título nunca o substitui.
+	paid := line.PaidAmount
+	paidAt := time.Now()
+	if line.OccurrenceDate != nil {
+		paidAt = *line.OccurrenceDate
+	}
+	payment := model.InstallmentPayment{
+		Amount:    paid,
+		PaidAt:    paidAt,
+		Method:    model.PaymentMethodBoleto,
+		Reference: bank.OurNumber(billing, slip.OurNumber),
+	}
+	amountMatches := paid.Sub(line.Interest).Add(line.Discount).Cmp(slip.Amount) == 0
+	settled, err := s.repo.MarkPaid(slip.ID, payment, line.CreditDate, amountMatches, installmentPaid)
+	switch {
+	case errors.Is(err, database.ErrBoletoStatusChanged):
+		result.Result = model.BoletoReturnAlreadyProcessed
+	case err != nil:
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("boleto_id", slip.ID).
+			Msg("failed to register boleto payment")
+		return result, GormDefaultError(err)
+	case !amountMatches:
+		log.Warn().Int64("boleto_id", slip.ID).Str("paid_amount", paid.String()).Msg("boleto paid with a different amount")
+		message := fmt.Sprintf("paid %s, expected %s", paid.StringFixed(2), slip.Amount.StringFixed(2))
+		result.Result = model.BoletoReturnAmountMismatch
+		result.Message = &message
+	case !settled:
+		log.Warn().Int64("boleto_id", slip.ID).Msg("boleto paid for an installment that is not open")
+		result.Result = model.BoletoReturnInstallmentNotOpen
+	default:
+		log.Info().Int64("boleto_id", slip.ID).Int64("installment_id", slip.InstallmentID).Msg("installment paid by boleto")
+		result.Result = model.BoletoReturnSettled
+	}
+	return result, nil
+}
+
+func (s *boletoService) FindReturn(id int64) (*dto.BoletoReturnDTO, ErrorUtil) {
+	ret, err := s.repo.FindReturn(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrBoletoReturnNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("boleto_return_id", id).
+			Msg("failed to find return file")
+		return nil, GormDefaultError(err)
+	}
+	return mapper.MapToBoletoReturnDTO(ret), nil
+}
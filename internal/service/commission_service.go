@@ -0,0 +1,792 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-sales/internal/auth"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/event"
+	"go-sales/internal/mapper"
+	"go-sales/internal/model"
+	"go-sales/internal/sales/commission"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// CommissionServiceInterface define os planos de comissão dos vendedores, as suas atribuições a
+// usuários e roles, os lançamentos das vendas faturadas (e os estornos) e os extratos mensais.
+type CommissionServiceInterface interface {
+	CreatePlan(planDTO dto.CreateCommissionPlanDTO) (*dto.CommissionPlanDTO, ErrorUtil)
+	PatchPlan(planDTO dto.UpdateCommissionPlanDTO, id int64, version int64) (*dto.CommissionPlanDTO, ErrorUtil)
+	DeletePlan(id int64, version int64) ErrorUtil
+	FindPlanByID(id int64, opts dto.ReadOptions) (*dto.CommissionPlanDTO, ErrorUtil)
+	FindPlans(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.CommissionPlanDTO], ErrorUtil)
+
+	CreateAssignment(assignmentDTO dto.CreateCommissionAssignmentDTO, planID int64) (*dto.CommissionAssignmentDTO, ErrorUtil)
+	FindAssignments(planID int64) ([]dto.CommissionAssignmentDTO, ErrorUtil)
+	DeleteAssignment(id int64) ErrorUtil
+
+	// Record calcula e grava a comissão de uma venda faturada pelo plano vigente do vendedor. Ainda não há
+	// evento de faturamento no barramento: quem fatura chama Record (e Reverse, no cancelamento) pela API.
+	Record(entryDTO dto.CreateCommissionEntryDTO) (*dto.CommissionEntryDTO, ErrorUtil)
+	// Reverse estorna a comissão de uma venda cancelada ou devolvida (no todo ou em parte).
+	Reverse(reverseDTO dto.ReverseCommissionEntryDTO, id int64) (*dto.CommissionEntryDTO, ErrorUtil)
+	// RecordInvoice e ReverseInvoice lançam a comissão a partir dos eventos de faturamento (ver
+	// SubscribeCommissionEvents). eventID é o ID do evento no outbox: o mesmo evento entregue de novo
+	// não gera outro lançamento.
+	RecordInvoice(entryDTO dto.CreateCommissionEntryDTO, eventID int64) ErrorUtil
+	ReverseInvoice(companyGlobalID int64, documentNumber string, reverseDTO dto.ReverseCommissionEntryDTO, eventID int64) ErrorUtil
+	FindEntry(id int64) (*dto.CommissionEntryDTO, ErrorUtil)
+	FindEntries(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.CommissionEntryDTO], ErrorUtil)
+	Export(filters map[string][]string, opts dto.ReadOptions, companyGlobalID int64, fn func(*dto.CommissionEntryDTO) error) ErrorUtil
+
+	// FindStatement devolve o extrato do vendedor no mês (2006-01), aberto ou aprovado, com os lançamentos.
+	FindStatement(userID int64, period string) (*dto.CommissionStatementDTO, ErrorUtil)
+	// FindStatements resume o mês de todos os vendedores da empresa.
+	FindStatements(companyGlobalID int64, period string) ([]dto.CommissionStatementDTO, ErrorUtil)
+	// ExportStatements entrega para fn o extrato de cada vendedor da empresa no mês, como FindStatements.
+	ExportStatements(companyGlobalID int64, period string, fn func(*dto.CommissionStatementDTO) error) ErrorUtil
+	// Approve fecha o extrato do vendedor num mês já encerrado; depois dele o mês não aceita lançamentos.
+	// approver é obrigatório e precisa ser da empresa do vendedor.
+	Approve(userID int64, period string, approver *auth.Principal) (*dto.CommissionStatementDTO, ErrorUtil)
+}
+
+// commissionService é a implementação concreta.
+type commissionService struct {
+	repo              database.CommissionRepositoryInterface
+	repoCompanyGlobal database.CompanyGlobalRepositoryInterface
+	repoUser          database.UserRepositoryInterface
+	repoRole          database.RoleRepositoryInterface
+}
+
+// NewCommissionService cria uma nova instância do serviço de comissões.
+func NewCommissionService(repo database.CommissionRepositoryInterface, repoCompanyGlobal database.CompanyGlobalRepositoryInterface, repoUser database.UserRepositoryInterface, repoRole database.RoleRepositoryInterface) CommissionServiceInterface {
+	return &commissionService{
+		repo:              repo,
+		repoCompanyGlobal: repoCompanyGlobal,
+		repoUser:          repoUser,
+		repoRole:          repoRole,
+	}
+}
+
+// validateCommissionPlan confere o plano completo (depois do merge, no PATCH) com commission.Plan.Validate.
+func validateCommissionPlan(plan *model.CommissionPlan) ErrorUtil {
+	commissionPlan := mapper.MapToCommissionPlan(plan)
+	if problems := commissionPlan.Validate(); len(problems) > 0 {
+		return NewError("invalid commission plan: "+strings.Join(problems, "; "), http.StatusBadRequest, "invalid_commission_plan", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// commissionPlanError traduz a violação do nome único do plano na empresa.
+func commissionPlanError(err error) ErrorUtil {
+	if errDefault := GormDefaultError(err); errDefault != ErrDuplicateKey {
+		return errDefault
+	}
+	return ErrCommissionPlanNameInUse
+}
+
+func (s *commissionService) CreatePlan(planDTO dto.CreateCommissionPlanDTO) (*dto.CommissionPlanDTO, ErrorUtil) {
+	companyExists, errCompanyExists := CheckCompanyGlobalExists(s.repoCompanyGlobal, planDTO.CompanyGlobalID.Int64(), false)
+	if errCompanyExists != nil {
+		return nil, errCompanyExists
+	}
+	if !companyExists {
+		return nil, ErrCompanyGlobalNotFound
+	}
+
+	plan := mapper.MapCreateToCommissionPlan(&planDTO)
+	if errValidate := validateCommissionPlan(plan); errValidate != nil {
+		return nil, errValidate
+	}
+	if err := s.repo.CreatePlan(plan); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Str("company_global_id", planDTO.CompanyGlobalID.String()).
+			Msg("failed to create commission plan")
+		return nil, commissionPlanError(err)
+	}
+	log.Info().Int64("commission_plan_id", plan.ID).Str("company_global_id", planDTO.CompanyGlobalID.String()).Msg("commission plan created")
+	return mapper.MapToCommissionPlanDTO(plan), nil
+}
+
+func (s *commissionService) PatchPlan(planDTO dto.UpdateCommissionPlanDTO, id int64, version int64) (*dto.CommissionPlanDTO, ErrorUtil) {
+	existing, errFind := s.findPlan(id, dto.FullRead)
+	if errFind != nil {
+		return nil, errFind
+	}
+
+	version, errVersion := CheckVersion(version, existing.Version)
+	if errVersion != nil {
+		return nil, errVersion
+	}
+
+	if errValidate := validateCommissionPlan(mapper.MergeCommissionPlan(&planDTO, existing)); errValidate != nil {
+		return nil, errValidate
+	}
+
+	columns := mapper.MapUpdateCommissionPlanToColumns(&planDTO)
+	if err := s.repo.PatchPlan(id, version, columns); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("commission_plan_id", id).
+			Msg("failed to patch commission plan")
+		return nil, commissionPlanError(err)
+	}
+	return s.FindPlanByID(id, dto.FullRead)
+}
+
+func (s *commissionService) DeletePlan(id int64, version int64) ErrorUtil {
+	if err := s.repo.DeletePlan(id, version); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCommissionPlanNotFound
+		}
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("commission_plan_id", id).
+			Msg("failed to delete commission plan")
+		return GormDefaultError(err)
+	}
+	return nil
+}
+
+func (s *commissionService) FindPlanByID(id int64, opts dto.ReadOptions) (*dto.CommissionPlanDTO, ErrorUtil) {
+	plan, errFind := s.findPlan(id, opts)
+	if errFind != nil {
+		return nil, errFind
+	}
+	return mapper.MapToCommissionPlanDTO(plan), nil
+}
+
+func (s *commissionService) findPlan(id int64, opts dto.ReadOptions) (*model.CommissionPlan, ErrorUtil) {
+	plan, err := s.repo.FindPlanByID(id, opts)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCommissionPlanNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("commission_plan_id", id).
+			Msg("failed to find commission plan")
+		return nil, GormDefaultError(err)
+	}
+	return plan, nil
+}
+
+func (s *commissionService) FindPlans(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.CommissionPlanDTO], ErrorUtil) {
+	if errCompany := s.checkCompany(companyGlobalID); errCompany != nil {
+		return nil, errCompany
+	}
+
+	plans, pageInfo, err := s.repo.FindPlans(filters, page, opts, companyGlobalID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to findAll commission plans")
+		return nil, GormDefaultError(err)
+	}
+
+	items := make([]dto.CommissionPlanDTO, len(plans))
+	for i := range plans {
+		items[i] = *mapper.MapToCommissionPlanDTO(&plans[i])
+	}
+	return &dto.PaginatedResponse[dto.CommissionPlanDTO]{
+		Items:    items,
+		PageInfo: pageInfo,
+	}, nil
+}
+
+func (s *commissionService) checkCompany(companyGlobalID int64) ErrorUtil {
+	companyExists, errCompanyExists := CheckCompanyGlobalExists(s.repoCompanyGlobal, companyGlobalID, false)
+	if errCompanyExists != nil {
+		log.Error().
+			Err(errCompanyExists).
+			Caller().
+			Str("company_global_id", strconv.FormatInt(companyGlobalID, 10)).
+			Msg("failed to check if company global exists")
+		return errCompanyExists
+	}
+	if !companyExists {
+		return ErrCompanyGlobalNotFound
+	}
+	return nil
+}
+
+// CreateAssignment atribui o plano a um usuário ou a uma role da mesma empresa do plano. O usuário (ou a
+// role) só pode ter um plano vigente em cada dia.
+func (s *commissionService) CreateAssignment(assignmentDTO dto.CreateCommissionAssignmentDTO, planID int64) (*dto.CommissionAssignmentDTO, ErrorUtil) {
+	if (assignmentDTO.UserID == nil) == (assignmentDTO.RoleID == nil) {
+		return nil, ErrCommissionAssignmentTarget
+	}
+	if assignmentDTO.ValidTo != nil && *assignmentDTO.ValidTo < assignmentDTO.ValidFrom {
+		return nil, ErrCommissionAssignmentValidity
+	}
+
+	plan, errFind := s.findPlan(planID, dto.FullRead)
+	if errFind != nil {
+		return nil, errFind
+	}
+
+	var targetCompanyID int64
+	if assignmentDTO.UserID != nil {
+		user, errUser := s.findUser(assignmentDTO.UserID.Int64())
+		if errUser != nil {
+			return nil, errUser
+		}
+		targetCompanyID = user.CompanyGlobalID
+	} else {
+		role, err := s.repoRole.FindByID(assignmentDTO.RoleID.Int64(), dto.FullRead)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		if err != nil {
+			log.Error().
+				Err(err).
+				Caller().
+				Str("role_id", assignmentDTO.RoleID.String()).
+				Msg("failed to find role")
+			return nil, GormDefaultError(err)
+		}
+		targetCompanyID = role.CompanyGlobalID
+	}
+	if targetCompanyID != plan.CompanyGlobalID {
+		return nil, ErrCommissionCompanyMismatch
+	}
+
+	assignment := mapper.MapCreateToCommissionAssignment(&assignmentDTO, plan)
+	if err := s.repo.CreateAssignment(assignment); err != nil {
+		if errors.Is(err, database.ErrCommissionAssignmentOverlap) {
+			return nil, ErrCommissionAssignmentOverlap
+		}
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("commission_plan_id", planID).
+			Msg("failed to create commission plan assignment")
+		return nil, GormDefaultError(err)
+	}
+	log.Info().Int64("commission_assignment_id", assignment.ID).Int64("commission_plan_id", planID).Msg("commission plan assigned")
+	return mapper.MapToCommissionAssignmentDTO(assignment), nil
+}
+
+func (s *commissionService) FindAssignments(planID int64) ([]dto.CommissionAssignmentDTO, ErrorUtil) {
+	if _, errFind := s.findPlan(planID, dto.FullRead); errFind != nil {
+		return nil, errFind
+	}
+
+	assignments, err := s.repo.FindAssignments(planID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("commission_plan_id", planID).
+			Msg("failed to find commission plan assignments")
+		return nil, GormDefaultError(err)
+	}
+
+	items := make([]dto.CommissionAssignmentDTO, len(assignments))
+	for i := range assignments {
+		items[i] = *mapper.MapToCommissionAssignmentDTO(&assignments[i])
+	}
+	return items, nil
+}
+
+func (s *commissionService) DeleteAssignment(id int64) ErrorUtil {
+	if err := s.repo.DeleteAssignment(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCommissionAssignmentNotFound
+		}
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("commission_assignment_id", id).
+			Msg("failed to delete commission plan assignment")
+		return GormDefaultError(err)
+	}
+	return nil
+}
+
+func (s *commissionService) findUser(id int64) (*model.User, ErrorUtil) {
+	user, err := s.repoUser.FindByID(strconv.FormatInt(id, 10), dto.FullRead)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("user_id", id).
+			Msg("failed to find user")
+		return nil, GormDefaultError(err)
+	}
+	return user, nil
+}
+
+// Record calcula a comissão da venda com o plano vigente para o vendedor na data do documento. Nos
+// planos por volume, a faixa considera o que o vendedor já faturou no mês mais esta venda.
+func (s *commissionService) Record(entryDTO dto.CreateCommissionEntryDTO) (*dto.CommissionEntryDTO, ErrorUtil) {
+	return s.record(entryDTO, nil)
+}
+
+// record grava a comissão da venda; eventID é o evento de faturamento que a gerou, se houver.
+func (s *commissionService) record(entryDTO dto.CreateCommissionEntryDTO, eventID *int64) (*dto.CommissionEntryDTO, ErrorUtil) {
+	user, errUser := s.findUser(entryDTO.UserID.Int64())
+	if errUser != nil {
+		return nil, errUser
+	}
+	if user.CompanyGlobalID != entryDTO.CompanyGlobalID.Int64() {
+		return nil, ErrCommissionCompanyMismatch
+	}
+
+	documentDate := mapper.CommissionDocumentDate(&entryDTO)
+	plan, err := s.repo.FindPlanFor(user.ID, documentDate)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCommissionPlanNotAssigned
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("user_id", user.ID).
+			Msg("failed to find commission plan for user")
+		return nil, GormDefaultError(err)
+	}
+
+	volume, err := s.repo.MonthVolume(user.ID, mapper.PeriodOf(documentDate))
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("user_id", user.ID).
+			Msg("failed to sum commission month volume")
+		return nil, GormDefaultError(err)
+	}
+
+	result, err := commission.Calculate(mapper.MapToCommissionPlan(plan), mapper.MapToCommissionLines(entryDTO.Items), volume)
+	if err != nil {
+		var lineErr *commission.LineError
+		if errors.As(err, &lineErr) {
+			return nil, NewError(err.Error(), http.StatusUnprocessableEntity, "commission_calculation_failed", strconv.Itoa(lineErr.Line), lineErr.Problem)
+		}
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("commission_plan_id", plan.ID).
+			Msg("failed to calculate commission")
+		return nil, NewError(err.Error(), http.StatusUnprocessableEntity, "invalid_commission_plan", err.Error())
+	}
+
+	entry := mapper.MapToCommissionSale(&entryDTO, plan, result)
+	entry.EventID = eventID
+	if err := s.repo.CreateEntry(entry); err != nil {
+		return nil, s.entryError(err, entry)
+	}
+	log.Info().
+		Int64("commission_entry_id", entry.ID).
+		Int64("user_id", entry.UserID).
+		Str("document_number", entry.DocumentNumber).
+		Str("amount", entry.Amount.String()).
+		Msg("commission recorded")
+	return mapper.MapToCommissionEntryDTO(entry), nil
+}
+
+// Reverse grava o estorno da venda id. O cancelamento estorna todo o saldo ainda não estornado; a
+// devolução com baseAmount estorna a comissão proporcional a essa base. O estorno entra no mês da
+// sua data, que pode ser posterior ao da venda.
+func (s *commissionService) Reverse(reverseDTO dto.ReverseCommissionEntryDTO, id int64) (*dto.CommissionEntryDTO, ErrorUtil) {
+	sale, errFind := s.findEntry(id)
+	if errFind != nil {
+		return nil, errFind
+	}
+	return s.reverse(reverseDTO, sale, nil)
+}
+
+// reverse grava o estorno da venda; eventID é o evento de faturamento que o gerou, se houver.
+func (s *commissionService) reverse(reverseDTO dto.ReverseCommissionEntryDTO, sale *model.CommissionEntry, eventID *int64) (*dto.CommissionEntryDTO, ErrorUtil) {
+	id := sale.ID
+	if sale.Kind != model.CommissionSale {
+		return nil, ErrCommissionEntryNotReversible
+	}
+
+	date := mapper.CommissionReversalDate(&reverseDTO, time.Now())
+	if date.Before(sale.DocumentDate) {
+		return nil, ErrCommissionReversalBeforeSale
+	}
+
+	reversedBase, reversedAmount, err := s.repo.ReversedTotals(sale.ID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("commission_entry_id", id).
+			Msg("failed to sum commission reversals")
+		return nil, GormDefaultError(err)
+	}
+	// Os estornos são negativos: o saldo é a venda mais os estornos.
+	remainingBase := sale.BaseAmount.Add(reversedBase)
+	remainingAmount := sale.Amount.Add(reversedAmount)
+	if remainingBase.Sign() <= 0 {
+		return nil, ErrCommissionAlreadyReversed
+	}
+
+	base, amount := remainingBase, remainingAmount
+	if reverseDTO.Reason == model.CommissionReasonReturned && reverseDTO.BaseAmount != nil {
+		base = reverseDTO.BaseAmount.Round(2)
+		if base.Sign() <= 0 || base.Cmp(remainingBase) > 0 {
+			return nil, ErrCommissionReversalExceedsSale
+		}
+		if base.Cmp(remainingBase) < 0 {
+			amount = commission.Reversal(sale.BaseAmount, sale.Amount, base)
+			if amount.Cmp(remainingAmount) > 0 {
+				amount = remainingAmount
+			}
+		}
+	}
+
+	entry := mapper.MapToCommissionReversal(sale, reverseDTO.Reason, date, base, amount)
+	entry.EventID = eventID
+	if err := s.repo.CreateEntry(entry); err != nil {
+		return nil, s.entryError(err, entry)
+	}
+	log.Info().
+		Int64("commission_entry_id", entry.ID).
+		Int64("reversed_entry_id", sale.ID).
+		Str("reason", reverseDTO.Reason).
+		Str("amount", entry.Amount.String()).
+		Msg("commission reversed")
+	return mapper.MapToCommissionEntryDTO(entry), nil
+}
+
+// RecordInvoice grava a comissão da nota emitida. Um evento repetido, uma nota já lançada pela API e um
+// vendedor sem plano de comissão vigente não geram lançamento nem erro.
+func (s *commissionService) RecordInvoice(entryDTO dto.CreateCommissionEntryDTO, eventID int64) ErrorUtil {
+	if recorded, errRecorded := s.eventRecorded(eventID); recorded || errRecorded != nil {
+		return errRecorded
+	}
+	_, err := s.record(entryDTO, &eventID)
+	switch err {
+	case nil, ErrCommissionDocumentExists:
+		return nil
+	case ErrCommissionPlanNotAssigned:
+		log.Info().
+			Int64("user_id", entryDTO.UserID.Int64()).
+			Str("document_number", entryDTO.DocumentNumber).
+			Msg("invoice seller has no commission plan, no commission recorded")
+		return nil
+	}
+	return err
+}
+
+// ReverseInvoice estorna a comissão da nota cancelada ou devolvida. Notas sem lançamento de venda (sem
+// vendedor ou sem plano), eventos repetidos e vendas já estornadas por inteiro são ignorados.
+func (s *commissionService) ReverseInvoice(companyGlobalID int64, documentNumber string, reverseDTO dto.ReverseCommissionEntryDTO, eventID int64) ErrorUtil {
+	if recorded, errRecorded := s.eventRecorded(eventID); recorded || errRecorded != nil {
+		return errRecorded
+	}
+	sale, err := s.repo.FindSale(companyGlobalID, documentNumber)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Str("company_global_id", strconv.FormatInt(companyGlobalID, 10)).
+			Str("document_number", documentNumber).
+			Msg("failed to find commission sale of the invoice")
+		return GormDefaultError(err)
+	}
+	_, errReverse := s.reverse(reverseDTO, sale, &eventID)
+	switch errReverse {
+	case nil, ErrCommissionDocumentExists, ErrCommissionAlreadyReversed:
+		return nil
+	}
+	return errReverse
+}
+
+// eventRecorded indica se o evento de faturamento já gerou um lançamento.
+func (s *commissionService) eventRecorded(eventID int64) (bool, ErrorUtil) {
+	recorded, err := s.repo.EventRecorded(eventID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("event_id", eventID).
+			Msg("failed to check commission event")
+		return false, GormDefaultError(err)
+	}
+	return recorded, nil
+}
+
+// SubscribeCommissionEvents registra no barramento o assinante "commissions", que lança a comissão da
+// nota emitida e a estorna no cancelamento e na devolução. Se ele falha, o outbox entrega o evento de
+// novo mais tarde.
+func SubscribeCommissionEvents(bus *event.Bus, s CommissionServiceInterface) {
+	event.Subscribe(bus, "commissions", func(_ context.Context, env event.Envelope, e event.InvoiceIssued) error {
+		if e.SellerID == nil {
+			return nil
+		}
+		if err := s.RecordInvoice(mapper.MapInvoiceToCommissionEntryDTO(e), env.ID); err != nil {
+			return err
+		}
+		return nil
+	})
+	event.Subscribe(bus, "commissions", func(_ context.Context, env event.Envelope, e event.InvoiceCancelled) error {
+		reverseDTO := mapper.MapInvoiceToReverseCommissionDTO(model.CommissionReasonCancelled, e.Date, nil)
+		if err := s.ReverseInvoice(e.CompanyGlobalID.Int64(), e.Number, reverseDTO, env.ID); err != nil {
+			return err
+		}
+		return nil
+	})
+	event.Subscribe(bus, "commissions", func(_ context.Context, env event.Envelope, e event.InvoiceReturned) error {
+		reverseDTO := mapper.MapInvoiceToReverseCommissionDTO(model.CommissionReasonReturned, e.Date, &e.BaseAmount)
+		if err := s.ReverseInvoice(e.CompanyGlobalID.Int64(), e.Number, reverseDTO, env.ID); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// entryError traduz as recusas de CreateEntry.
+func (s *commissionService) entryError(err error, entry *model.CommissionEntry) ErrorUtil {
+	switch {
+	case errors.Is(err, database.ErrCommissionPeriodClosed):
+		return ErrCommissionPeriodClosed
+	case errors.Is(err, database.ErrCommissionOverReversed):
+		return ErrCommissionReversalExceedsSale
+	}
+	if errDefault := GormDefaultError(err); errDefault == ErrDuplicateKey {
+		return ErrCommissionDocumentExists
+	}
+	log.Error().
+		Err(err).
+		Caller().
+		Int64("user_id", entry.UserID).
+		Str("document_number", entry.DocumentNumber).
+		Msg("failed to create commission entry")
+	return GormDefaultError(err)
+}
+
+func (s *commissionService) FindEntry(id int64) (*dto.CommissionEntryDTO, ErrorUtil) {
+	entry, errFind := s.findEntry(id)
+	if errFind != nil {
+		return nil, errFind
+	}
+	return mapper.MapToCommissionEntryDTO(entry), nil
+}
+
+func (s *commissionService) findEntry(id int64) (*model.CommissionEntry, ErrorUtil) {
+	entry, err := s.repo.FindEntry(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCommissionEntryNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("commission_entry_id", id).
+			Msg("failed to find commission entry")
+		return nil, GormDefaultError(err)
+	}
+	return entry, nil
+}
+
+func (s *commissionService) FindEntries(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.CommissionEntryDTO], ErrorUtil) {
+	if errCompany := s.checkCompany(companyGlobalID); errCompany != nil {
+		return nil, errCompany
+	}
+
+	entries, pageInfo, err := s.repo.FindEntries(filters, page, opts, companyGlobalID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to findAll commission entries")
+		return nil, GormDefaultError(err)
+	}
+
+	items := make([]dto.CommissionEntryDTO, len(entries))
+	for i := range entries {
+		items[i] = *mapper.MapToCommissionEntryDTO(&entries[i])
+	}
+	return &dto.PaginatedResponse[dto.CommissionEntryDTO]{
+		Items:    items,
+		PageInfo: pageInfo,
+	}, nil
+}
+
+// Export percorre todos os lançamentos que atendem aos filtros, sem paginação, e entrega cada um como DTO para fn.
+func (s *commissionService) Export(filters map[string][]string, opts dto.ReadOptions, companyGlobalID int64, fn func(*dto.CommissionEntryDTO) error) ErrorUtil {
+	if errCompany := s.checkCompany(companyGlobalID); errCompany != nil {
+		return errCompany
+	}
+
+	err := s.repo.Stream(filters, opts, companyGlobalID, func(entry *model.CommissionEntry) error {
+		return fn(mapper.MapToCommissionEntryDTO(entry))
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to export commission entries")
+		return GormDefaultError(err)
+	}
+	return nil
+}
+
+func (s *commissionService) FindStatement(userID int64, period string) (*dto.CommissionStatementDTO, ErrorUtil) {
+	month, ok := mapper.ParsePeriod(period)
+	if !ok {
+		return nil, ErrInvalidCommissionPeriod
+	}
+	user, errUser := s.findUser(userID)
+	if errUser != nil {
+		return nil, errUser
+	}
+
+	statement, err := s.repo.FindStatement(userID, month)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("user_id", userID).
+			Str("period", period).
+			Msg("failed to find commission statement")
+		return nil, GormDefaultError(err)
+	}
+	entries, err := s.repo.FindPeriodEntries(userID, month)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("user_id", userID).
+			Str("period", period).
+			Msg("failed to find commission entries of the period")
+		return nil, GormDefaultError(err)
+	}
+
+	// Extrato ainda aberto: os totais são os dos lançamentos até agora.
+	if statement == nil {
+		statement = &model.CommissionStatement{
+			CompanyGlobalID: user.CompanyGlobalID,
+			UserID:          userID,
+			Period:          month,
+			EntryCount:      len(entries),
+		}
+		for _, entry := range entries {
+			statement.BaseAmount = statement.BaseAmount.Add(entry.BaseAmount)
+			statement.TotalAmount = statement.TotalAmount.Add(entry.Amount)
+		}
+	}
+	return mapper.MapToCommissionStatementDTO(statement, entries), nil
+}
+
+func (s *commissionService) FindStatements(companyGlobalID int64, period string) ([]dto.CommissionStatementDTO, ErrorUtil) {
+	month, ok := mapper.ParsePeriod(period)
+	if !ok {
+		return nil, ErrInvalidCommissionPeriod
+	}
+	if errCompany := s.checkCompany(companyGlobalID); errCompany != nil {
+		return nil, errCompany
+	}
+
+	statements, err := s.repo.FindStatements(companyGlobalID, month)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Str("company_global_id", strconv.FormatInt(companyGlobalID, 10)).
+			Str("period", period).
+			Msg("failed to find commission statements")
+		return nil, GormDefaultError(err)
+	}
+
+	items := make([]dto.CommissionStatementDTO, len(statements))
+	for i := range statements {
+		items[i] = *mapper.MapToCommissionStatementDTO(&statements[i], nil)
+	}
+	return items, nil
+}
+
+// ExportStatements percorre os extratos do mês, os mesmos de FindStatements, e entrega cada um para fn.
+func (s *commissionService) ExportStatements(companyGlobalID int64, period string, fn func(*dto.CommissionStatementDTO) error) ErrorUtil {
+	statements, errFind := s.FindStatements(companyGlobalID, period)
+	if errFind != nil {
+		return errFind
+	}
+	for i := range statements {
+		if err := fn(&statements[i]); err != nil {
+			log.Error().
+				Err(err).
+				Caller().
+				Str("company_global_id", strconv.FormatInt(companyGlobalID, 10)).
+				Str("period", period).
+				Msg("failed to export commission statements")
+			return GormDefaultError(err)
+		}
+	}
+	return nil
+}
+
+// Approve grava o extrato aprovado do vendedor no mês, com os totais dos lançamentos. Só meses já
+// encerrados podem ser aprovados, para que nenhuma venda do mês chegue depois do fechamento. Vendedores
+// de outra empresa são tratados como inexistentes.
+func (s *commissionService) Approve(userID int64, period string, approver *auth.Principal) (*dto.CommissionStatementDTO, ErrorUtil) {
+	if approver == nil {
+		return nil, ErrCommissionApproverRequired
+	}
+	month, ok := mapper.ParsePeriod(period)
+	if !ok {
+		return nil, ErrInvalidCommissionPeriod
+	}
+	now := time.Now()
+	if now.Before(month.AddDate(0, 1, 0)) {
+		return nil, ErrCommissionPeriodNotEnded
+	}
+	user, errUser := s.findUser(userID)
+	if errUser != nil {
+		return nil, errUser
+	}
+	if user.CompanyGlobalID != approver.CompanyGlobalID {
+		return nil, ErrUserNotFound
+	}
+
+	statement := &model.CommissionStatement{
+		CompanyGlobalID: user.CompanyGlobalID,
+		UserID:          userID,
+		Period:          month,
+		ApprovedAt:      now,
+		ApprovedByType:  string(approver.Type),
+		ApprovedByID:    approver.ID,
+	}
+	if err := s.repo.ApproveStatement(statement); err != nil {
+		if errors.Is(err, database.ErrCommissionPeriodClosed) || GormDefaultError(err) == ErrDuplicateKey {
+			return nil, ErrCommissionPeriodClosed
+		}
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("user_id", userID).
+			Str("period", period).
+			Msg("failed to approve commission statement")
+		return nil, GormDefaultError(err)
+	}
+	log.Info().
+		Int64("commission_statement_id", statement.ID).
+		Int64("user_id", userID).
+		Str("period", period).
+		Str("total_amount", statement.TotalAmount.String()).
+		Msg("commission statement approved")
+	return s.FindStatement(userID, period)
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-sales/internal/auth"
+	"go-sales/internal/database"
+	"go-sales/internal/event"
+	"go-sales/internal/model"
+	"go-sales/internal/sales/commission"
+	"go-sales/pkg/decimal"
+	"go-sales/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+// fakeCommissionRepository guarda os lançamentos e os extratos aprovados em memória, com o plano plan
+// vigente para todos os vendedores.
+type fakeCommissionRepository struct {
+	database.CommissionRepositoryInterface
+
+	plan     model.CommissionPlan
+	entries  []model.CommissionEntry
+	approved []model.CommissionStatement
+}
+
+func (r *fakeCommissionRepository) FindPlanFor(userID int64, date time.Time) (*model.CommissionPlan, error) {
+	if r.plan.ID == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &r.plan, nil
+}
+
+func (r *fakeCommissionRepository) MonthVolume(userID int64, period time.Time) (decimal.Decimal, error) {
+	return decimal.Zero, nil
+}
+
+func (r *fakeCommissionRepository) CreateEntry(entry *model.CommissionEntry) error {
+	entry.ID = int64(len(r.entries) + 1)
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *fakeCommissionRepository) FindSale(companyGlobalID int64, documentNumber string) (*model.CommissionEntry, error) {
+	for i := range r.entries {
+		entry := &r.entries[i]
+		if entry.CompanyGlobalID == companyGlobalID && entry.DocumentNumber == documentNumber && entry.Kind == model.CommissionSale {
+			return entry, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeCommissionRepository) EventRecorded(eventID int64) (bool, error) {
+	for _, entry := range r.entries {
+		if entry.EventID != nil && *entry.EventID == eventID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeCommissionRepository) ReversedTotals(saleID int64) (decimal.Decimal, decimal.Decimal, error) {
+	base, amount := decimal.Zero, decimal.Zero
+	for _, entry := range r.entries {
+		if entry.ReversedEntryID != nil && *entry.ReversedEntryID == saleID {
+			base, amount = base.Add(entry.BaseAmount), amount.Add(entry.Amount)
+		}
+	}
+	return base, amount, nil
+}
+
+func (r *fakeCommissionRepository) ApproveStatement(statement *model.CommissionStatement) error {
+	statement.ID = int64(len(r.approved) + 1)
+	r.approved = append(r.approved, *statement)
+	return nil
+}
+
+func (r *fakeCommissionRepository) FindStatement(userID int64, period time.Time) (*model.CommissionStatement, error) {
+	for i := range r.approved {
+		if r.approved[i].UserID == userID && r.approved[i].Period.Equal(period) {
+			return &r.approved[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeCommissionRepository) FindPeriodEntries(userID int64, period time.Time) ([]model.CommissionEntry, error) {
+	return nil, nil
+}
+
+// O extrato só é aprovado por um principal da empresa do vendedor, e grava o tipo e o ID de quem aprovou.
+func TestApproveOnlyWithinTheCompany(t *testing.T) {
+	repo := &fakeCommissionRepository{}
+	users := &fakeUserRepository{users: map[int64]model.User{7: {ID: 7, CompanyGlobalID: 10}}}
+	svc := NewCommissionService(repo, nil, users, nil)
+
+	if _, err := svc.Approve(7, "2025-01", nil); err != ErrCommissionApproverRequired {
+		t.Fatalf("Approve without a principal = %v, want ErrCommissionApproverRequired", err)
+	}
+	outsider := &auth.Principal{Type: auth.PrincipalUser, ID: 3, CompanyGlobalID: 20}
+	if _, err := svc.Approve(7, "2025-01", outsider); err != ErrUserNotFound {
+		t.Fatalf("Approve from another company = %v, want ErrUserNotFound", err)
+	}
+	if len(repo.approved) != 0 {
+		t.Fatalf("statements approved across companies: %v", repo.approved)
+	}
+
+	approver := &auth.Principal{Type: auth.PrincipalServiceAccount, ID: 5, CompanyGlobalID: 10, Name: "erp"}
+	statement, err := svc.Approve(7, "2025-01", approver)
+	if err != nil {
+		t.Fatalf("Approve within the company: %v", err)
+	}
+	if len(repo.approved) != 1 || repo.approved[0].ApprovedByType != "service_account" || repo.approved[0].ApprovedByID != 5 {
+		t.Fatalf("approved = %+v, want the service account 5", repo.approved)
+	}
+	if statement.ApprovedByType == nil || *statement.ApprovedByType != "service_account" || statement.ApprovedByID == nil || statement.ApprovedByID.Int64() != 5 {
+		t.Fatalf("statement approver = %v/%v", statement.ApprovedByType, statement.ApprovedByID)
+	}
+}
+
+// A nota emitida gera a venda do vendedor; a devolução e o cancelamento a estornam. Cada evento gera um
+// único lançamento, mesmo entregue de novo pelo outbox.
+func TestCommissionEventsFromInvoices(t *testing.T) {
+	repo := &fakeCommissionRepository{plan: model.CommissionPlan{ID: 3, Kind: commission.KindCategory, DefaultRate: decimal.MustParse("5")}}
+	users := &fakeUserRepository{users: map[int64]model.User{7: {ID: 7, CompanyGlobalID: 10}}}
+	bus := event.NewBus()
+	SubscribeCommissionEvents(bus, NewCommissionService(repo, nil, users, nil))
+
+	seller := util.SnowflakeID(7)
+	events := []event.Envelope{
+		{ID: 100, Type: event.TypeInvoiceIssued, Event: event.InvoiceIssued{
+			ID: 50, CompanyGlobalID: 10, Number: "NF-1", IssueDate: "2025-03-10", SellerID: &seller,
+			Items: []event.InvoiceItem{{Quantity: decimal.MustParse("2"), UnitPrice: decimal.MustParse("50")}},
+		}},
+		{ID: 101, Type: event.TypeInvoiceIssued, Event: event.InvoiceIssued{
+			ID: 51, CompanyGlobalID: 10, Number: "NF-2", IssueDate: "2025-03-10",
+			Items: []event.InvoiceItem{{Quantity: decimal.MustParse("1"), UnitPrice: decimal.MustParse("10")}},
+		}},
+		{ID: 102, Type: event.TypeInvoiceReturned, Event: event.InvoiceReturned{
+			ID: 50, CompanyGlobalID: 10, Number: "NF-1", Date: "2025-03-12", BaseAmount: decimal.MustParse("60"),
+		}},
+		{ID: 103, Type: event.TypeInvoiceCancelled, Event: event.InvoiceCancelled{
+			ID: 50, CompanyGlobalID: 10, Number: "NF-1", Date: "2025-03-15",
+		}},
+	}
+	for _, env := range events {
+		for range 2 {
+			if err := bus.Dispatch(context.Background(), env); err != nil {
+				t.Fatalf("dispatching %s %d: %v", env.Type, env.ID, err)
+			}
+		}
+	}
+
+	want := []struct {
+		kind    string
+		eventID int64
+		base    string
+		amount  string
+	}{
+		{model.CommissionSale, 100, "100", "5"},
+		{model.CommissionReversal, 102, "-60", "-3"},
+		{model.CommissionReversal, 103, "-40", "-2"},
+	}
+	if len(repo.entries) != len(want) {
+		t.Fatalf("entries = %+v, want %d", repo.entries, len(want))
+	}
+	for i, w := range want {
+		entry := repo.entries[i]
+		if entry.Kind != w.kind || entry.EventID == nil || *entry.EventID != w.eventID ||
+			entry.BaseAmount.Cmp(decimal.MustParse(w.base)) != 0 || entry.Amount.Cmp(decimal.MustParse(w.amount)) != 0 {
+			t.Errorf("entry %d = %s event %v base %s amount %s, want %s event %d base %s amount %s",
+				i, entry.Kind, entry.EventID, entry.BaseAmount, entry.Amount, w.kind, w.eventID, w.base, w.amount)
+		}
+	}
+}
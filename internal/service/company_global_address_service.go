@@ -0,0 +1,184 @@
+package service
+
+import (
+	"errors"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
+	"go-sales/internal/model"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type CompanyGlobalAddressService struct {
+	repo            database.CompanyGlobalAddressRepositoryInterface
+	companyRepo     database.CompanyGlobalRepositoryInterface
+	addressProvider AddressProvider
+}
+
+// CompanyGlobalAddressServiceInterface define as operações de /company-globals/:id/addresses.
+// Uma empresa pode ter vários endereços, mas sempre exatamente um principal (MAIN).
+type CompanyGlobalAddressServiceInterface interface {
+	FindAll(companyID int64) ([]*dto.CompanyGlobalAddressDTO, ErrorUtil)
+	FindByID(companyID int64, id int64) (*dto.CompanyGlobalAddressDTO, ErrorUtil)
+	Create(companyID int64, addressDTO dto.SaveCompanyGlobalAddressDTO) (*dto.CompanyGlobalAddressDTO, ErrorUtil)
+	Update(companyID int64, id int64, version int64, addressDTO dto.SaveCompanyGlobalAddressDTO) (*dto.CompanyGlobalAddressDTO, ErrorUtil)
+	Delete(companyID int64, id int64, version int64) ErrorUtil
+}
+
+func NewCompanyGlobalAddressService(repo database.CompanyGlobalAddressRepositoryInterface, companyRepo database.CompanyGlobalRepositoryInterface, addressProvider AddressProvider) CompanyGlobalAddressServiceInterface {
+	return &CompanyGlobalAddressService{
+		repo:            repo,
+		companyRepo:     companyRepo,
+		addressProvider: addressProvider,
+	}
+}
+
+func (s *CompanyGlobalAddressService) FindAll(companyID int64) ([]*dto.CompanyGlobalAddressDTO, ErrorUtil) {
+	addresses, errFind := s.findAll(companyID)
+	if errFind != nil {
+		return nil, errFind
+	}
+	return mapper.MapToCompanyGlobalAddressDTOs(addresses), nil
+}
+
+func (s *CompanyGlobalAddressService) FindByID(companyID int64, id int64) (*dto.CompanyGlobalAddressDTO, ErrorUtil) {
+	address, errFind := s.find(companyID, id)
+	if errFind != nil {
+		return nil, errFind
+	}
+	return mapper.MapToCompanyGlobalAddressDTO(address), nil
+}
+
+func (s *CompanyGlobalAddressService) Create(companyID int64, addressDTO dto.SaveCompanyGlobalAddressDTO) (*dto.CompanyGlobalAddressDTO, ErrorUtil) {
+	addresses, errFind := s.findAll(companyID)
+	if errFind != nil {
+		return nil, errFind
+	}
+	if addressDTO.Type == model.AddressTypeMain && (&model.CompanyGlobal{Addresses: addresses}).MainAddress() != nil {
+		return nil, ErrCompanyGlobalMainAddressExists
+	}
+
+	address := mapper.MapToSaveCompanyGlobalAddress(&addressDTO, companyID)
+	if errAddress := s.addressProvider.NormalizeAddress(address); errAddress != nil {
+		return nil, errAddress
+	}
+	if err := s.repo.Create(address); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to create company address")
+		return nil, GormDefaultError(err)
+	}
+	return mapper.MapToCompanyGlobalAddressDTO(address), nil
+}
+
+func (s *CompanyGlobalAddressService) Update(companyID int64, id int64, version int64, addressDTO dto.SaveCompanyGlobalAddressDTO) (*dto.CompanyGlobalAddressDTO, ErrorUtil) {
+	addresses, errFind := s.findAll(companyID)
+	if errFind != nil {
+		return nil, errFind
+	}
+	original := findAddress(addresses, id)
+	if original == nil {
+		return nil, ErrCompanyGlobalAddressNotFound
+	}
+
+	version, errVersion := CheckVersion(version, original.Version)
+	if errVersion != nil {
+		return nil, errVersion
+	}
+
+	// O endereço principal só pode ser alterado, nunca rebaixado; e não pode haver dois.
+	if original.Type == model.AddressTypeMain && addressDTO.Type != model.AddressTypeMain {
+		return nil, ErrCompanyGlobalMainAddressRequired
+	}
+	if original.Type != model.AddressTypeMain && addressDTO.Type == model.AddressTypeMain {
+		return nil, ErrCompanyGlobalMainAddressExists
+	}
+
+	address := mapper.MapToSaveCompanyGlobalAddress(&addressDTO, companyID)
+	address.ID = id
+	if errAddress := s.addressProvider.NormalizeAddress(address); errAddress != nil {
+		return nil, errAddress
+	}
+	if err := s.repo.Update(address, version); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to update company address")
+		return nil, addressError(err)
+	}
+	return mapper.MapToCompanyGlobalAddressDTO(address), nil
+}
+
+// Delete remove um endereço secundário; o endereço principal não pode ser removido.
+func (s *CompanyGlobalAddressService) Delete(companyID int64, id int64, version int64) ErrorUtil {
+	address, errFind := s.find(companyID, id)
+	if errFind != nil {
+		return errFind
+	}
+	if address.Type == model.AddressTypeMain {
+		return ErrCompanyGlobalMainAddressRequired
+	}
+
+	if err := s.repo.Delete(companyID, id, version); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to delete company address")
+		return addressError(err)
+	}
+	return nil
+}
+
+// findAll carrega os endereços garantindo que a empresa existe.
+func (s *CompanyGlobalAddressService) findAll(companyID int64) ([]*model.CompanyGlobalAddress, ErrorUtil) {
+	if _, errExists := CheckCompanyGlobalExists(s.companyRepo, companyID, false); errExists != nil {
+		return nil, errExists
+	}
+
+	addresses, err := s.repo.FindAll(companyID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to find company addresses")
+		return nil, GormDefaultError(err)
+	}
+	return addresses, nil
+}
+
+// find carrega o endereço garantindo que a empresa existe e que o endereço pertence a ela.
+func (s *CompanyGlobalAddressService) find(companyID int64, id int64) (*model.CompanyGlobalAddress, ErrorUtil) {
+	if _, errExists := CheckCompanyGlobalExists(s.companyRepo, companyID, false); errExists != nil {
+		return nil, errExists
+	}
+
+	address, err := s.repo.FindByID(companyID, id)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to find company address")
+		return nil, addressError(err)
+	}
+	return address, nil
+}
+
+func findAddress(addresses []*model.CompanyGlobalAddress, id int64) *model.CompanyGlobalAddress {
+	for _, address := range addresses {
+		if address.ID == id {
+			return address
+		}
+	}
+	return nil
+}
+
+// addressError traduz o registro não encontrado para o erro específico de endereço.
+func addressError(err error) ErrorUtil {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrCompanyGlobalAddressNotFound
+	}
+	return GormDefaultError(err)
+}
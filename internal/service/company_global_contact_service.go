@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
+	"go-sales/internal/model"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type CompanyGlobalContactService struct {
+	repo        database.CompanyGlobalContactRepositoryInterface
+	companyRepo database.CompanyGlobalRepositoryInterface
+}
+
+// CompanyGlobalContactServiceInterface define as operações de /company-globals/:id/contacts.
+type CompanyGlobalContactServiceInterface interface {
+	FindAll(companyID int64) ([]*dto.CompanyGlobalContactDTO, ErrorUtil)
+	FindByID(companyID int64, id int64) (*dto.CompanyGlobalContactDTO, ErrorUtil)
+	Create(companyID int64, contactDTO dto.CreateCompanyGlobalContactDTO) (*dto.CompanyGlobalContactDTO, ErrorUtil)
+	Update(companyID int64, id int64, version int64, contactDTO dto.CreateCompanyGlobalContactDTO) (*dto.CompanyGlobalContactDTO, ErrorUtil)
+	Delete(companyID int64, id int64, version int64) ErrorUtil
+}
+
+func NewCompanyGlobalContactService(repo database.CompanyGlobalContactRepositoryInterface, companyRepo database.CompanyGlobalRepositoryInterface) CompanyGlobalContactServiceInterface {
+	return &CompanyGlobalContactService{
+		repo:        repo,
+		companyRepo: companyRepo,
+	}
+}
+
+func (s *CompanyGlobalContactService) FindAll(companyID int64) ([]*dto.CompanyGlobalContactDTO, ErrorUtil) {
+	if _, errExists := CheckCompanyGlobalExists(s.companyRepo, companyID, false); errExists != nil {
+		return nil, errExists
+	}
+
+	contacts, err := s.repo.FindAll(companyID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to find company contacts")
+		return nil, GormDefaultError(err)
+	}
+	return mapper.MapToCompanyGlobalContactDTOs(contacts), nil
+}
+
+func (s *CompanyGlobalContactService) FindByID(companyID int64, id int64) (*dto.CompanyGlobalContactDTO, ErrorUtil) {
+	contact, errFind := s.find(companyID, id)
+	if errFind != nil {
+		return nil, errFind
+	}
+	return mapper.MapToCompanyGlobalContactDTO(contact), nil
+}
+
+func (s *CompanyGlobalContactService) Create(companyID int64, contactDTO dto.CreateCompanyGlobalContactDTO) (*dto.CompanyGlobalContactDTO, ErrorUtil) {
+	if _, errExists := CheckCompanyGlobalExists(s.companyRepo, companyID, false); errExists != nil {
+		return nil, errExists
+	}
+
+	// O id do payload só é usado no PUT da company; aqui o contato é sempre novo.
+	contact := mapper.MapToCompanyGlobalContact(&contactDTO)
+	contact.CompanyID = companyID
+	if err := s.repo.Create(contact); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to create company contact")
+		return nil, GormDefaultError(err)
+	}
+	return mapper.MapToCompanyGlobalContactDTO(contact), nil
+}
+
+func (s *CompanyGlobalContactService) Update(companyID int64, id int64, version int64, contactDTO dto.CreateCompanyGlobalContactDTO) (*dto.CompanyGlobalContactDTO, ErrorUtil) {
+	original, errFind := s.find(companyID, id)
+	if errFind != nil {
+		return nil, errFind
+	}
+
+	version, errVersion := CheckVersion(version, original.Version)
+	if errVersion != nil {
+		return nil, errVersion
+	}
+
+	contact := mapper.MapToCompanyGlobalContact(&contactDTO)
+	contact.ID = id
+	contact.CompanyID = companyID
+	if err := s.repo.Update(contact, version); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to update company contact")
+		return nil, contactError(err)
+	}
+	return mapper.MapToCompanyGlobalContactDTO(contact), nil
+}
+
+// Delete remove o contato, desde que a empresa continue com pelo menos um.
+func (s *CompanyGlobalContactService) Delete(companyID int64, id int64, version int64) ErrorUtil {
+	if _, errFind := s.find(companyID, id); errFind != nil {
+		return errFind
+	}
+
+	count, err := s.repo.Count(companyID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to count company contacts")
+		return GormDefaultError(err)
+	}
+	if count <= 1 {
+		return ErrCompanyGlobalContactRequired
+	}
+
+	if err := s.repo.Delete(companyID, id, version); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to delete company contact")
+		return contactError(err)
+	}
+	return nil
+}
+
+// find carrega o contato garantindo que a empresa existe e que o contato pertence a ela.
+func (s *CompanyGlobalContactService) find(companyID int64, id int64) (*model.CompanyGlobalContact, ErrorUtil) {
+	if _, errExists := CheckCompanyGlobalExists(s.companyRepo, companyID, false); errExists != nil {
+		return nil, errExists
+	}
+
+	contact, err := s.repo.FindByID(companyID, id)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to find company contact")
+		return nil, contactError(err)
+	}
+	return contact, nil
+}
+
+// contactError traduz o registro não encontrado para o erro específico de contato.
+func contactError(err error) ErrorUtil {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrCompanyGlobalContactNotFound
+	}
+	return GormDefaultError(err)
+}
@@ -6,33 +6,37 @@ import (
 	"go-sales/internal/dto"
 	"go-sales/internal/mapper"
 	"go-sales/internal/model"
-	"math"
+	"go-sales/internal/payment/pix"
 
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 )
 
 type CompanyGlobalService struct {
-	repo database.CompanyGlobalRepositoryInterface
+	repo            database.CompanyGlobalRepositoryInterface
+	addressProvider AddressProvider
 }
 type CompanyGlobalServiceInterface interface {
 	Create(companyDTO dto.CreateCompanyGlobalDTO) (*dto.CompanyGlobalDTO, ErrorUtil)
-	Update(companyDTO dto.CreateCompanyGlobalDTO, id int64) (*dto.CompanyGlobalDTO, ErrorUtil)
-	Delete(id int64) ErrorUtil
-	FindByID(id int64) (*dto.CompanyGlobalDTO, ErrorUtil)
+	Update(companyDTO dto.CreateCompanyGlobalDTO, id int64, version int64) (*dto.CompanyGlobalDTO, ErrorUtil)
+	Delete(id int64, version int64) ErrorUtil
+	Patch(companyDTO dto.UpdateCompanyGlobalDTO, id int64, version int64) (*dto.CompanyGlobalDTO, ErrorUtil)
+	FindByID(id int64, opts dto.ReadOptions) (*dto.CompanyGlobalDTO, ErrorUtil)
 	FindByCGC(cgc string) (*dto.CompanyGlobalDTO, ErrorUtil)
-	FindAll(filters map[string][]string, page, pageSize int) (*dto.PaginatedResponse[dto.CompanyGlobalDTO], ErrorUtil)
+	FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions) (*dto.PaginatedResponse[dto.CompanyGlobalDTO], ErrorUtil)
+	Export(filters map[string][]string, opts dto.ReadOptions, fn func(*dto.CompanyGlobalDTO) error) ErrorUtil
 	Restore(id int64) ErrorUtil
 }
 
-func NewCompanyGlobalService(repo database.CompanyGlobalRepositoryInterface) CompanyGlobalServiceInterface {
+func NewCompanyGlobalService(repo database.CompanyGlobalRepositoryInterface, addressProvider AddressProvider) CompanyGlobalServiceInterface {
 	return &CompanyGlobalService{
-		repo: repo,
+		repo:            repo,
+		addressProvider: addressProvider,
 	}
 }
 
 func (s *CompanyGlobalService) Restore(id int64) ErrorUtil {
-	err := s.repo.Restore(id)
+	err := s.repo.Restore(id, companyGlobalRestored)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -70,11 +74,20 @@ func (s *CompanyGlobalService) Create(companyDTO dto.CreateCompanyGlobalDTO) (*d
 		return nil, ErrCGCInUse
 	}
 
+	pixKey, errPixKey := normalizePixKey(companyDTO.PixKey)
+	if errPixKey != nil {
+		return nil, errPixKey
+	}
+	companyDTO.PixKey = pixKey
+
 	// 2. Mapear o DTO para o modelo do banco de dados.
 	newCompany := mapper.MapToCreateCompanyGlobal(&companyDTO)
+	if errAddress := s.normalizeAddresses(newCompany.Addresses); errAddress != nil {
+		return nil, errAddress
+	}
 
 	// 3. Chamar o repositório para persistir a empresa.
-	if err := s.repo.Create(newCompany); err != nil {
+	if err := s.repo.Create(newCompany, companyGlobalCreated); err != nil {
 		log.Error().
 			Err(err).
 			Caller().
@@ -85,9 +98,9 @@ func (s *CompanyGlobalService) Create(companyDTO dto.CreateCompanyGlobalDTO) (*d
 	return mapper.MapToCompanyGlobalDTO(newCompany), nil
 }
 
-func (s *CompanyGlobalService) Update(companyDTO dto.CreateCompanyGlobalDTO, id int64) (*dto.CompanyGlobalDTO, ErrorUtil) {
+func (s *CompanyGlobalService) Update(companyDTO dto.CreateCompanyGlobalDTO, id int64, version int64) (*dto.CompanyGlobalDTO, ErrorUtil) {
 	// 1. Verificar se a empresa existe.
-	original, err := s.repo.FindByID(id, false)
+	original, err := s.repo.FindByID(id, false, dto.FullRead)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -96,6 +109,12 @@ func (s *CompanyGlobalService) Update(companyDTO dto.CreateCompanyGlobalDTO, id
 		return nil, GormDefaultError(err)
 	}
 
+	// 2. Verificar se a versão enviada no If-Match ainda é a atual.
+	version, errVersion := CheckVersion(version, original.Version)
+	if errVersion != nil {
+		return nil, errVersion
+	}
+
 	otherCompany, err := s.repo.FindByCGC(companyDTO.CGC, true)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		log.Error().
@@ -108,13 +127,33 @@ func (s *CompanyGlobalService) Update(companyDTO dto.CreateCompanyGlobalDTO, id
 		return nil, ErrCGCInUse
 	}
 
+	if !companyDTO.ValidateContacts() {
+		log.Error().
+			Err(ErrCompanyGlobalContactsFieldValidationUnique).
+			Caller().
+			Msg("failed contacts validation")
+		return nil, ErrCompanyGlobalContactsFieldValidationUnique
+	}
+	if errContacts := checkContactIDs(companyDTO.Contacts, original.Contacts); errContacts != nil {
+		return nil, errContacts
+	}
+
+	pixKey, errPixKey := normalizePixKey(companyDTO.PixKey)
+	if errPixKey != nil {
+		return nil, errPixKey
+	}
+	companyDTO.PixKey = pixKey
+
 	// 3. Mapear o DTO para o modelo do banco de dados.
 	updatedCompany := mapper.MapToUpdateCompanyGlobal(&companyDTO, id)
+	if errAddress := s.normalizeAddresses(updatedCompany.Addresses); errAddress != nil {
+		return nil, errAddress
+	}
 
 	updatedCompany.CreatedAt = original.CreatedAt
 
 	// 4. Chamar o repositório para persistir a empresa.
-	if err := s.repo.Update(updatedCompany); err != nil {
+	if err := s.repo.Update(updatedCompany, version, companyGlobalUpdated); err != nil {
 		log.Error().
 			Err(err).
 			Caller().
@@ -123,11 +162,84 @@ func (s *CompanyGlobalService) Update(companyDTO dto.CreateCompanyGlobalDTO, id
 		return nil, GormDefaultError(err)
 	}
 
-	return mapper.MapToCompanyGlobalDTO(updatedCompany), nil
+	// Relê a empresa para devolver também os endereços que não fazem parte do payload.
+	return s.FindByID(id, dto.FullRead)
 }
 
-func (s *CompanyGlobalService) Delete(id int64) ErrorUtil {
-	err := s.repo.Delete(id)
+// Patch aplica um JSON Merge Patch na empresa: apenas os campos enviados são alterados.
+func (s *CompanyGlobalService) Patch(companyDTO dto.UpdateCompanyGlobalDTO, id int64, version int64) (*dto.CompanyGlobalDTO, ErrorUtil) {
+	original, err := s.repo.FindByID(id, false, dto.FullRead)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to patch company")
+		return nil, GormDefaultError(err)
+	}
+
+	version, errVersion := CheckVersion(version, original.Version)
+	if errVersion != nil {
+		return nil, errVersion
+	}
+
+	if companyDTO.CGC != nil && *companyDTO.CGC != original.CGC {
+		otherCompany, err := s.repo.FindByCGC(*companyDTO.CGC, true)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Error().
+				Err(err).
+				Caller().
+				Msg("failed to patch company")
+			return nil, GormDefaultError(err)
+		}
+		if otherCompany != nil && otherCompany.ID != id {
+			return nil, ErrCGCInUse
+		}
+	}
+
+	if companyDTO.Contacts != nil && !(&dto.CreateCompanyGlobalDTO{Contacts: companyDTO.Contacts}).ValidateContacts() {
+		log.Error().
+			Err(ErrCompanyGlobalContactsFieldValidationUnique).
+			Caller().
+			Msg("failed contacts validation")
+		return nil, ErrCompanyGlobalContactsFieldValidationUnique
+	}
+	if errContacts := checkContactIDs(companyDTO.Contacts, original.Contacts); errContacts != nil {
+		return nil, errContacts
+	}
+
+	pixKey, errPixKey := normalizePixKey(companyDTO.PixKey)
+	if errPixKey != nil {
+		return nil, errPixKey
+	}
+	companyDTO.PixKey = pixKey
+
+	// O endereço é um objeto: o patch é mesclado no endereço principal atual.
+	var address *model.CompanyGlobalAddress
+	if companyDTO.Address != nil {
+		address = mapper.MergeCompanyGlobalAddress(companyDTO.Address, original.MainAddress())
+		if address.Street == "" || address.City == "" || address.State == "" || address.PostalCode == "" || address.Country == "" {
+			return nil, ErrAddressIncomplete
+		}
+		if errAddress := s.addressProvider.NormalizeAddress(address); errAddress != nil {
+			return nil, errAddress
+		}
+	}
+
+	columns := mapper.MapUpdateCompanyGlobalToColumns(&companyDTO)
+	contacts := mapper.MapToCompanyGlobalContacts(companyDTO.Contacts)
+	if err := s.repo.Patch(id, version, columns, address, contacts, companyGlobalUpdated); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to patch company")
+		return nil, GormDefaultError(err)
+	}
+
+	return s.FindByID(id, dto.FullRead)
+}
+
+func (s *CompanyGlobalService) Delete(id int64, version int64) ErrorUtil {
+	err := s.repo.Delete(id, version, companyGlobalDeleted)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -138,8 +250,8 @@ func (s *CompanyGlobalService) Delete(id int64) ErrorUtil {
 	return nil
 }
 
-func (s *CompanyGlobalService) FindByID(id int64) (*dto.CompanyGlobalDTO, ErrorUtil) {
-	company, err := s.repo.FindByID(id, false)
+func (s *CompanyGlobalService) FindByID(id int64, opts dto.ReadOptions) (*dto.CompanyGlobalDTO, ErrorUtil) {
+	company, err := s.repo.FindByID(id, false, opts)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -163,8 +275,8 @@ func (s *CompanyGlobalService) FindByCGC(cgc string) (*dto.CompanyGlobalDTO, Err
 }
 
 // ...
-func (s *CompanyGlobalService) FindAll(filters map[string][]string, page, pageSize int) (*dto.PaginatedResponse[dto.CompanyGlobalDTO], ErrorUtil) {
-	companies, totalItems, err := s.repo.FindAll(filters, page, pageSize, false)
+func (s *CompanyGlobalService) FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions) (*dto.PaginatedResponse[dto.CompanyGlobalDTO], ErrorUtil) {
+	companies, pageInfo, err := s.repo.FindAll(filters, page, opts, false)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -180,21 +292,73 @@ func (s *CompanyGlobalService) FindAll(filters map[string][]string, page, pageSi
 	}
 	companyDTOs := mapper.MapToCompanyGlobalDTOs(companyPtrs)
 
-	totalPages := 0
-	if pageSize > 0 {
-		totalPages = int(math.Ceil(float64(totalItems) / float64(pageSize)))
-	}
-
 	// Instancia a struct genérica, especificando que T é dto.CompanyGlobalDTO.
 	paginatedResponse := &dto.PaginatedResponse[dto.CompanyGlobalDTO]{
-		Items: *companyDTOs,
-		PageInfo: dto.PageInfo{
-			Page:       page,
-			PageSize:   pageSize,
-			TotalItems: totalItems,
-			TotalPages: totalPages,
-		},
+		Items:    *companyDTOs,
+		PageInfo: pageInfo,
 	}
 
 	return paginatedResponse, nil
 }
+
+// checkContactIDs garante que os contatos enviados com id já pertencem à empresa e que nenhum id
+// aparece duas vezes, já que eles serão atualizados no lugar.
+// normalizePixKey confere a chave PIX informada e a devolve no formato do DICT (ver pix.ParseKey).
+func normalizePixKey(key *string) (*string, ErrorUtil) {
+	if key == nil {
+		return nil, nil
+	}
+	normalized, _, err := pix.ParseKey(*key)
+	if err != nil {
+		return nil, ErrInvalidPixKey
+	}
+	return &normalized, nil
+}
+
+func checkContactIDs(contactDTOs []*dto.CreateCompanyGlobalContactDTO, current []*model.CompanyGlobalContact) ErrorUtil {
+	known := make(map[int64]bool, len(current))
+	for _, contact := range current {
+		known[contact.ID] = true
+	}
+	seen := make(map[int64]bool, len(contactDTOs))
+	for _, contactDTO := range contactDTOs {
+		if contactDTO == nil || contactDTO.ID == 0 {
+			continue
+		}
+		if !known[contactDTO.ID.Int64()] || seen[contactDTO.ID.Int64()] {
+			log.Error().
+				Err(ErrCompanyGlobalContactNotFound).
+				Caller().
+				Int64("contact_id", contactDTO.ID.Int64()).
+				Msg("failed contacts validation")
+			return ErrCompanyGlobalContactNotFound
+		}
+		seen[contactDTO.ID.Int64()] = true
+	}
+	return nil
+}
+
+// normalizeAddresses valida e padroniza cada endereço com o AddressProvider.
+func (s *CompanyGlobalService) normalizeAddresses(addresses []*model.CompanyGlobalAddress) ErrorUtil {
+	for _, address := range addresses {
+		if errAddress := s.addressProvider.NormalizeAddress(address); errAddress != nil {
+			return errAddress
+		}
+	}
+	return nil
+}
+
+// Export percorre todas as companies que atendem aos filtros, sem paginação, e entrega cada uma como DTO para fn.
+func (s *CompanyGlobalService) Export(filters map[string][]string, opts dto.ReadOptions, fn func(*dto.CompanyGlobalDTO) error) ErrorUtil {
+	err := s.repo.Stream(filters, opts, func(company *model.CompanyGlobal) error {
+		return fn(mapper.MapToCompanyGlobalDTO(company))
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to export companies")
+		return GormDefaultError(err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"errors"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// LockoutPolicy define quando uma conta é bloqueada por senhas erradas.
+// Depois de MaxAttempts falhas seguidas a conta fica bloqueada por BaseLockout; cada nova falha após
+// o desbloqueio dobra o tempo, até MaxLockout. Uma senha correta zera a contagem.
+type LockoutPolicy struct {
+	MaxAttempts int
+	BaseLockout time.Duration
+	MaxLockout  time.Duration
+}
+
+// DefaultLockoutPolicy é usada nos campos não configurados: 5 tentativas, 1 minuto, até 24 horas.
+var DefaultLockoutPolicy = LockoutPolicy{MaxAttempts: 5, BaseLockout: time.Minute, MaxLockout: 24 * time.Hour}
+
+// CredentialServiceInterface confere senhas de usuários aplicando o bloqueio por tentativas.
+// É a base do login de usuários, que ainda não existe: hoje só contas de serviço autenticam (chaves de
+// API). O futuro endpoint de login deve chamar CheckPassword antes de emitir a sessão; até lá nenhuma
+// rota alimenta a contagem de falhas.
+type CredentialServiceInterface interface {
+	CheckPassword(email string, companyGlobalID int64, password string) (*dto.UserDTO, ErrorUtil)
+	// Unlock desbloqueia um usuário de companyGlobalID; usuários de outra empresa não são encontrados.
+	Unlock(userID int64, companyGlobalID int64) ErrorUtil
+}
+
+// credentialService é a implementação concreta.
+type credentialService struct {
+	repo   database.UserRepositoryInterface
+	policy LockoutPolicy
+}
+
+// NewCredentialService cria uma nova instância do serviço de credenciais.
+// Campos zerados da política assumem os valores de DefaultLockoutPolicy.
+func NewCredentialService(repo database.UserRepositoryInterface, policy LockoutPolicy) CredentialServiceInterface {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = DefaultLockoutPolicy.MaxAttempts
+	}
+	if policy.BaseLockout <= 0 {
+		policy.BaseLockout = DefaultLockoutPolicy.BaseLockout
+	}
+	if policy.MaxLockout <= 0 {
+		policy.MaxLockout = DefaultLockoutPolicy.MaxLockout
+	}
+	return &credentialService{repo: repo, policy: policy}
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+)
+
+// compareDummyHash gasta o mesmo tempo de um bcrypt real quando o email não existe, para que o tempo
+// de resposta não revele quais emails estão cadastrados.
+func compareDummyHash(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("go-sales-dummy-password"), bcrypt.DefaultCost)
+	})
+	_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+}
+
+// CheckPassword confere a senha do usuário da empresa. Enquanto a conta estiver bloqueada a senha nem
+// é conferida e o resultado é ErrAccountLocked. Cada senha errada conta para o bloqueio; a falha que
+// bloqueia a conta já responde ErrAccountLocked.
+func (s *credentialService) CheckPassword(email string, companyGlobalID int64, password string) (*dto.UserDTO, ErrorUtil) {
+	user, err := s.repo.FindByEmailAndCompany(email, companyGlobalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			compareDummyHash(password)
+			return nil, ErrInvalidCredentials
+		}
+		log.Error().Err(err).Caller().Str("company_global_id", strconv.FormatInt(companyGlobalID, 10)).Msg("failed to find user by email")
+		return nil, GormDefaultError(err)
+	}
+
+	now := time.Now()
+	if user.LockedUntil != nil && user.LockedUntil.After(now) {
+		return nil, ErrAccountLocked
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		failed, err := s.repo.RecordLoginFailure(user.ID, now, s.policy.MaxAttempts, s.policy.BaseLockout, s.policy.MaxLockout)
+		if err != nil {
+			log.Error().Err(err).Caller().Int64("user_id", user.ID).Msg("failed to record login failure")
+			return nil, GormDefaultError(err)
+		}
+		if failed.LockedUntil != nil && failed.LockedUntil.After(now) {
+			log.Warn().Int64("user_id", user.ID).Int("failed_attempts", failed.FailedLoginAttempts).
+				Time("locked_until", *failed.LockedUntil).Msg("account locked after too many failed password attempts")
+			return nil, ErrAccountLocked
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		if err := s.repo.ResetLoginFailures(user.ID); err != nil {
+			log.Error().Err(err).Caller().Int64("user_id", user.ID).Msg("failed to reset login failures")
+			return nil, GormDefaultError(err)
+		}
+		user.FailedLoginAttempts = 0
+		user.LockedUntil = nil
+	}
+	return mapper.MapToUserDTO(user), nil
+}
+
+// Unlock desbloqueia a conta e zera as falhas (POST /users/:id/unlock).
+func (s *credentialService) Unlock(userID int64, companyGlobalID int64) ErrorUtil {
+	user, err := s.repo.FindByID(strconv.FormatInt(userID, 10), dto.FullRead)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && user.CompanyGlobalID != companyGlobalID) {
+		return ErrNotFound
+	}
+	if err != nil {
+		log.Error().Err(err).Caller().Int64("user_id", userID).Msg("failed to find user")
+		return GormDefaultError(err)
+	}
+
+	if err := s.repo.ResetLoginFailures(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		log.Error().Err(err).Caller().Int64("user_id", userID).Msg("failed to unlock user")
+		return GormDefaultError(err)
+	}
+	log.Info().Int64("user_id", userID).Msg("user account unlocked")
+	return nil
+}
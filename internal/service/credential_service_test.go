@@ -0,0 +1,56 @@
+package service
+
+import (
+	"strconv"
+	"testing"
+
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// fakeUserRepository guarda usuários em memória e registra os desbloqueios.
+type fakeUserRepository struct {
+	database.UserRepositoryInterface
+
+	users    map[int64]model.User
+	unlocked []int64
+}
+
+func (r *fakeUserRepository) FindByID(id string, opts dto.ReadOptions) (*model.User, error) {
+	userID, _ := strconv.ParseInt(id, 10, 64)
+	user, ok := r.users[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &user, nil
+}
+
+func (r *fakeUserRepository) ResetLoginFailures(id int64) error {
+	r.unlocked = append(r.unlocked, id)
+	return nil
+}
+
+func TestUnlockOnlyWithinTheCompany(t *testing.T) {
+	repo := &fakeUserRepository{users: map[int64]model.User{7: {ID: 7, CompanyGlobalID: 10}}}
+	svc := NewCredentialService(repo, DefaultLockoutPolicy)
+
+	if err := svc.Unlock(7, 20); err != ErrNotFound {
+		t.Fatalf("Unlock from another company = %v, want ErrNotFound", err)
+	}
+	if err := svc.Unlock(8, 10); err != ErrNotFound {
+		t.Fatalf("Unlock of a missing user = %v, want ErrNotFound", err)
+	}
+	if len(repo.unlocked) != 0 {
+		t.Fatalf("users unlocked across companies: %v", repo.unlocked)
+	}
+
+	if err := svc.Unlock(7, 10); err != nil {
+		t.Fatalf("Unlock within the company: %v", err)
+	}
+	if len(repo.unlocked) != 1 || repo.unlocked[0] != 7 {
+		t.Fatalf("unlocked = %v, want [7]", repo.unlocked)
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"go-sales/internal/event"
+	"go-sales/internal/mapper"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+)
+
+// Recorders dos eventos de domínio. Os serviços os passam aos métodos de escrita dos repositórios, que
+// os chamam com o registro gravado e gravam o evento no outbox na mesma transação (ver database.Recorder).
+
+func userCreated(user *model.User) event.Event {
+	return event.UserCreated{UserDTO: *mapper.MapToUserDTO(user)}
+}
+
+func userUpdated(user *model.User) event.Event {
+	return event.UserUpdated{UserDTO: *mapper.MapToUserDTO(user)}
+}
+
+func userDeleted(user *model.User) event.Event {
+	return event.UserDeleted{Deleted: event.Deleted{
+		ID:              util.SnowflakeID(user.ID),
+		CompanyGlobalID: util.SnowflakeID(user.CompanyGlobalID),
+	}}
+}
+
+func companyGlobalCreated(company *model.CompanyGlobal) event.Event {
+	return event.CompanyGlobalCreated{CompanyGlobalDTO: *mapper.MapToCompanyGlobalDTO(company)}
+}
+
+func companyGlobalUpdated(company *model.CompanyGlobal) event.Event {
+	return event.CompanyGlobalUpdated{CompanyGlobalDTO: *mapper.MapToCompanyGlobalDTO(company)}
+}
+
+func companyGlobalDeleted(company *model.CompanyGlobal) event.Event {
+	return event.CompanyGlobalDeleted{Deleted: event.Deleted{
+		ID:              util.SnowflakeID(company.ID),
+		CompanyGlobalID: util.SnowflakeID(company.ID),
+	}}
+}
+
+func companyGlobalRestored(company *model.CompanyGlobal) event.Event {
+	return event.CompanyGlobalRestored{CompanyGlobalDTO: *mapper.MapToCompanyGlobalDTO(company)}
+}
+
+func roleCreated(role *model.Role) event.Event {
+	return event.RoleCreated{RoleDTO: *mapper.MapToRoleDTO(role)}
+}
+
+func roleUpdated(role *model.Role) event.Event {
+	return event.RoleUpdated{RoleDTO: *mapper.MapToRoleDTO(role)}
+}
+
+func roleDeleted(role *model.Role) event.Event {
+	return event.RoleDeleted{Deleted: event.Deleted{
+		ID:              util.SnowflakeID(role.ID),
+		CompanyGlobalID: util.SnowflakeID(role.CompanyGlobalID),
+	}}
+}
+
+func installmentPaid(installment *model.Installment) event.Event {
+	return event.InstallmentPaid{InstallmentDTO: *mapper.MapToInstallmentDTO(installment)}
+}
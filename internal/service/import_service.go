@@ -0,0 +1,418 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/i18n"
+	"go-sales/internal/mapper"
+	"go-sales/internal/model"
+	"go-sales/internal/queue"
+	"go-sales/pkg/util"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxImportRows é o limite de linhas (sem contar o cabeçalho) de uma planilha importada.
+	MaxImportRows = 10000
+	// importBatchRows é quantas linhas são gravadas em cada transação.
+	importBatchRows = 100
+	// importSavepoint isola cada linha dentro da transação do lote: uma linha inválida é desfeita sem
+	// abortar as demais.
+	importSavepoint = "import_row"
+)
+
+// ImportJobType é o tipo do job da fila que executa uma importação.
+const ImportJobType = "imports.run"
+
+// ImportJobPayload é o payload do job ImportJobType: a importação a executar. As linhas da planilha
+// ficam na própria importação (ImportJobRepositoryInterface.SaveRows), e não em master.jobs, porque
+// podem ter senhas em texto puro.
+type ImportJobPayload struct {
+	ImportJobID     int64 `json:"importJobId"`
+	CompanyGlobalID int64 `json:"companyGlobalId"`
+	// Rows só vem preenchido em jobs enfileirados antes de as linhas saírem do payload.
+	Rows [][]string `json:"rows,omitempty"`
+}
+
+// errDryRun desfaz a transação do lote quando a importação é apenas uma simulação.
+var errDryRun = errors.New("dry run")
+
+// rowImporter valida uma linha e a cria usando os serviços ligados à transação tx, devolvendo os
+// erros da linha. É a mesma validação do POST da entidade: binding do DTO e regras do serviço.
+type rowImporter func(tx *gorm.DB, row importRow) []dto.ImportRowErrorDTO
+
+// importers são as entidades aceitas em POST /imports/{entity}.
+var importers = map[string]rowImporter{
+	"users":       importUser,
+	"permissions": importPermission,
+}
+
+// ImportServiceInterface define as operações de importação em massa.
+type ImportServiceInterface interface {
+	Start(entity string, fileName string, rows [][]string, dryRun bool, companyGlobalID int64) (*dto.ImportJobDTO, ErrorUtil)
+	FindByID(id int64) (*dto.ImportJobDTO, ErrorUtil)
+	Process(ctx context.Context, payload ImportJobPayload) error
+}
+
+type ImportService struct {
+	db      *gorm.DB
+	jobRepo database.ImportJobRepositoryInterface
+}
+
+// NewImportService cria o serviço de importação. db é usado para abrir uma transação por lote.
+func NewImportService(db *gorm.DB, jobRepo database.ImportJobRepositoryInterface) ImportServiceInterface {
+	return &ImportService{
+		db:      db,
+		jobRepo: jobRepo,
+	}
+}
+
+// Start registra a importação e enfileira o job que a executa em segundo plano. rows inclui o cabeçalho, cujas colunas
+// são os nomes dos campos JSON do POST da entidade (sem diferenciar maiúsculas). companyGlobalID,
+// quando informado, é usado nas linhas sem a coluna companyGlobalId.
+func (s *ImportService) Start(entity string, fileName string, rows [][]string, dryRun bool, companyGlobalID int64) (*dto.ImportJobDTO, ErrorUtil) {
+	if _, ok := importers[entity]; !ok {
+		return nil, ErrUnsupportedImportEntity
+	}
+	if len(rows) < 2 || len(rows)-1 > MaxImportRows {
+		return nil, ErrInvalidImportFile
+	}
+
+	job := &model.ImportJob{
+		CompanyGlobalID: companyGlobalID,
+		Entity:          entity,
+		FileName:        fileName,
+		DryRun:          dryRun,
+		Status:          model.ImportStatusPending,
+		TotalRows:       len(rows) - 1,
+		Errors:          []byte("[]"),
+	}
+	// A importação e o job da fila são gravados juntos: ou a importação fica registrada e enfileirada, ou nada é gravado.
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		importJobRepo := database.NewImportJobRepository(tx)
+		if err := importJobRepo.Create(job); err != nil {
+			return err
+		}
+		data, err := json.Marshal(rows)
+		if err != nil {
+			return err
+		}
+		if err := importJobRepo.SaveRows(job.ID, data); err != nil {
+			return err
+		}
+		queueJob, err := queue.NewJob(ImportJobType, ImportJobPayload{ImportJobID: job.ID, CompanyGlobalID: companyGlobalID})
+		if err != nil {
+			return err
+		}
+		// Uma importação interrompida já gravou parte das linhas; repetir geraria erros de duplicidade.
+		queueJob.MaxAttempts = 1
+		_, err = database.NewJobRepository(tx).Enqueue(queueJob)
+		return err
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to create import job")
+		return nil, GormDefaultError(err)
+	}
+	return mapper.MapToImportJobDTO(job), nil
+}
+
+// Process executa a importação enfileirada por Start. É o handler do job ImportJobType.
+func (s *ImportService) Process(ctx context.Context, payload ImportJobPayload) error {
+	job, err := s.jobRepo.FindByID(payload.ImportJobID)
+	if err != nil {
+		return err
+	}
+	importer, ok := importers[job.Entity]
+	if !ok {
+		s.finish(job, ErrUnsupportedImportEntity)
+		return nil
+	}
+	rows := payload.Rows
+	if rows == nil {
+		data, err := s.jobRepo.FindRows(job.ID)
+		if err != nil {
+			return err
+		}
+		if data == nil {
+			s.finish(job, ErrInvalidImportFile)
+			return nil
+		}
+		if err := json.Unmarshal(data, &rows); err != nil {
+			s.finish(job, err)
+			return nil
+		}
+	}
+	return s.run(ctx, job, parseImportRows(rows, payload.CompanyGlobalID), importer)
+}
+
+func (s *ImportService) FindByID(id int64) (*dto.ImportJobDTO, ErrorUtil) {
+	job, err := s.jobRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrImportJobNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to find import job")
+		return nil, GormDefaultError(err)
+	}
+	return mapper.MapToImportJobDTO(job), nil
+}
+
+// run processa as linhas em lotes de importBatchRows, cada lote em uma transação. As linhas válidas
+// do lote são gravadas juntas; as inválidas são desfeitas pelo savepoint e entram no relatório.
+// Em dry run todo lote é desfeito no final, mas a validação é a mesma.
+// Um desligamento da aplicação interrompe a importação entre dois lotes.
+func (s *ImportService) run(ctx context.Context, job *model.ImportJob, rows []importRow, importer rowImporter) error {
+	logger := log.With().Int64("import_job_id", job.ID).Str("entity", job.Entity).Logger()
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error().Interface("panic", r).Msg("import job panicked")
+			s.finish(job, fmt.Errorf("unexpected error: %v", r))
+		}
+	}()
+
+	now := time.Now()
+	job.Status = model.ImportStatusRunning
+	job.StartedAt = &now
+	s.save(job)
+
+	rowErrors := make([]dto.ImportRowErrorDTO, 0)
+	for start := 0; start < len(rows); start += importBatchRows {
+		if err := ctx.Err(); err != nil {
+			logger.Warn().Int("processed", job.ProcessedRows).Msg("import job interrupted")
+			s.finish(job, errors.New("import interrupted before finishing; rows already processed were kept"))
+			return err
+		}
+		batch := rows[start:min(start+importBatchRows, len(rows))]
+		succeeded, failed := 0, 0
+		batchErrors := make([]dto.ImportRowErrorDTO, 0)
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			for _, row := range batch {
+				if err := tx.SavePoint(importSavepoint).Error; err != nil {
+					return err
+				}
+				if errs := importer(tx, row); len(errs) > 0 {
+					if err := tx.RollbackTo(importSavepoint).Error; err != nil {
+						return err
+					}
+					batchErrors = append(batchErrors, errs...)
+					failed++
+					continue
+				}
+				succeeded++
+			}
+			if job.DryRun {
+				return errDryRun
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errDryRun) {
+			logger.Error().Err(err).Int("row", batch[0].number).Msg("import batch failed")
+			s.finish(job, err)
+			return nil
+		}
+
+		rowErrors = append(rowErrors, batchErrors...)
+		job.ProcessedRows += len(batch)
+		job.SucceededRows += succeeded
+		job.FailedRows += failed
+		job.Errors, _ = json.Marshal(rowErrors)
+		s.save(job)
+	}
+
+	s.finish(job, nil)
+	logger.Info().Int("succeeded", job.SucceededRows).Int("failed", job.FailedRows).Bool("dry_run", job.DryRun).Msg("import job finished")
+	return nil
+}
+
+// finish grava o estado final da importação e apaga as linhas da planilha; err, quando não for nil,
+// marca a importação como falha.
+func (s *ImportService) finish(job *model.ImportJob, err error) {
+	now := time.Now()
+	job.FinishedAt = &now
+	job.Status = model.ImportStatusCompleted
+	if err != nil {
+		message := err.Error()
+		job.Status = model.ImportStatusFailed
+		job.ErrorMessage = &message
+	}
+	s.save(job)
+	if err := s.jobRepo.ClearRows(job.ID); err != nil {
+		log.Error().Err(err).Int64("import_job_id", job.ID).Msg("failed to clear import job rows")
+	}
+}
+
+func (s *ImportService) save(job *model.ImportJob) {
+	if err := s.jobRepo.Update(job); err != nil {
+		log.Error().Err(err).Int64("import_job_id", job.ID).Msg("failed to save import job progress")
+	}
+}
+
+// importRow é uma linha da planilha indexada pelo nome da coluna (em minúsculas).
+type importRow struct {
+	number   int
+	values   map[string]string
+	defaults map[string]string
+	errors   []dto.ImportRowErrorDTO
+}
+
+// parseImportRows converte as linhas da planilha usando o cabeçalho (rows[0]) como nomes das colunas.
+func parseImportRows(rows [][]string, companyGlobalID int64) []importRow {
+	header := make([]string, len(rows[0]))
+	for i, name := range rows[0] {
+		header[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+	defaults := map[string]string{}
+	if companyGlobalID != 0 {
+		defaults["companyglobalid"] = strconv.FormatInt(companyGlobalID, 10)
+	}
+
+	out := make([]importRow, 0, len(rows)-1)
+	for i, values := range rows[1:] {
+		row := importRow{number: i + 2, values: make(map[string]string, len(header)), defaults: defaults}
+		for j, name := range header {
+			if j < len(values) && name != "" {
+				row.values[name] = strings.TrimSpace(values[j])
+			}
+		}
+		out = append(out, row)
+	}
+	return out
+}
+
+func (r *importRow) get(field string) string {
+	if value := r.values[strings.ToLower(field)]; value != "" {
+		return value
+	}
+	return r.defaults[strings.ToLower(field)]
+}
+
+func (r *importRow) optional(field string) *string {
+	if value := r.get(field); value != "" {
+		return &value
+	}
+	return nil
+}
+
+// id lê um Snowflake ID; células vazias resultam em 0 e ficam para a validação do DTO.
+func (r *importRow) id(field string) util.SnowflakeID {
+	value := r.get(field)
+	if value == "" {
+		return 0
+	}
+	id, err := util.ParseSnowflake(value)
+	if err != nil {
+		r.fail(field, "snowflake", field+" must be a valid Snowflake ID")
+	}
+	return util.SnowflakeID(id)
+}
+
+// ids lê uma lista de Snowflake IDs separados por "|" (ex: "1963354596233486336|1963354596233486337").
+func (r *importRow) ids(field string) []util.SnowflakeID {
+	value := r.get(field)
+	if value == "" {
+		return nil
+	}
+	parts := strings.Split(value, "|")
+	ids := make([]util.SnowflakeID, 0, len(parts))
+	for _, part := range parts {
+		id, err := util.ParseSnowflake(strings.TrimSpace(part))
+		if err != nil {
+			r.fail(field, "snowflake", field+" must be a list of Snowflake IDs separated by |")
+			return nil
+		}
+		ids = append(ids, util.SnowflakeID(id))
+	}
+	return ids
+}
+
+func (r *importRow) fail(field, code, message string) {
+	r.errors = append(r.errors, dto.ImportRowErrorDTO{Row: r.number, Field: field, Code: code, Message: message})
+}
+
+// validate aplica no DTO as mesmas regras de binding do POST da entidade.
+func (r *importRow) validate(obj any) {
+	err := binding.Validator.ValidateStruct(obj)
+	var ve validator.ValidationErrors
+	if !errors.As(err, &ve) {
+		if err != nil {
+			r.fail("", "validation_error", err.Error())
+		}
+		return
+	}
+	trans := i18n.Translator(i18n.LocaleEnUS)
+	for _, fe := range ve {
+		field := fe.Namespace()
+		if idx := strings.Index(field, "."); idx >= 0 {
+			field = field[idx+1:]
+		}
+		r.fail(field, fe.Tag(), fe.Translate(trans))
+	}
+}
+
+// failWith registra um erro de negócio devolvido pelo serviço.
+func (r *importRow) failWith(err ErrorUtil) {
+	var args []string
+	if withArgs, ok := err.(interface{ MessageArgs() []string }); ok {
+		args = withArgs.MessageArgs()
+	}
+	r.fail("", err.Code(), i18n.Message(i18n.LocaleEnUS, err.Code(), err.Error(), args...))
+}
+
+func importUser(tx *gorm.DB, row importRow) []dto.ImportRowErrorDTO {
+	userDTO := dto.CreateUserDTO{
+		Name:            row.get("name"),
+		Email:           row.get("email"),
+		Password:        row.get("password"),
+		CompanyGlobalID: row.id("companyGlobalId"),
+		RoleIDs:         row.ids("roleIds"),
+	}
+	if userDTO.RoleIDs == nil {
+		userDTO.RoleIDs = []util.SnowflakeID{}
+	}
+	row.validate(&userDTO)
+	if len(row.errors) > 0 {
+		return row.errors
+	}
+
+	userService := NewUserService(database.NewUserRepository(tx), database.NewCompanyGlobalRepository(tx), database.NewRoleRepository(tx))
+	if _, err := userService.Create(userDTO); err != nil {
+		row.failWith(err)
+	}
+	return row.errors
+}
+
+func importPermission(tx *gorm.DB, row importRow) []dto.ImportRowErrorDTO {
+	permissionDTO := dto.CreatePermissionDTO{
+		Name:            row.get("name"),
+		CompanyGlobalID: row.id("companyGlobalId"),
+		Description:     row.optional("description"),
+	}
+	row.validate(&permissionDTO)
+	if len(row.errors) > 0 {
+		return row.errors
+	}
+
+	permissionService := NewPermissionService(database.NewPermissionRepository(tx), database.NewCompanyGlobalRepository(tx))
+	if _, err := permissionService.Create(permissionDTO); err != nil {
+		row.failWith(err)
+	}
+	return row.errors
+}
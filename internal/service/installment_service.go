@@ -0,0 +1,172 @@
+package service
+
+import (
+	"errors"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
+	"go-sales/internal/model"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// InstallmentServiceInterface define a gestão das parcelas a receber. A baixa das parcelas é feita
+// pelos meios de pagamento (ver PixServiceInterface e BoletoServiceInterface) e pela conciliação do
+// extrato bancário (BankStatementServiceInterface).
+type InstallmentServiceInterface interface {
+	Create(installmentDTO dto.CreateInstallmentDTO) (*dto.InstallmentDTO, ErrorUtil)
+	Patch(installmentDTO dto.UpdateInstallmentDTO, id int64, version int64) (*dto.InstallmentDTO, ErrorUtil)
+	Delete(id int64, version int64) ErrorUtil
+	FindByID(id int64, opts dto.ReadOptions) (*dto.InstallmentDTO, ErrorUtil)
+	FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.InstallmentDTO], ErrorUtil)
+}
+
+// installmentService é a implementação concreta.
+type installmentService struct {
+	repo              database.InstallmentRepositoryInterface
+	repoCompanyGlobal database.CompanyGlobalRepositoryInterface
+}
+
+// NewInstallmentService cria uma nova instância do serviço de parcelas.
+func NewInstallmentService(repo database.InstallmentRepositoryInterface, repoCompanyGlobal database.CompanyGlobalRepositoryInterface) InstallmentServiceInterface {
+	return &installmentService{
+		repo:              repo,
+		repoCompanyGlobal: repoCompanyGlobal,
+	}
+}
+
+func (s *installmentService) Create(installmentDTO dto.CreateInstallmentDTO) (*dto.InstallmentDTO, ErrorUtil) {
+	companyExists, errCompanyExists := CheckCompanyGlobalExists(s.repoCompanyGlobal, installmentDTO.CompanyGlobalID.Int64(), false)
+	if errCompanyExists != nil {
+		return nil, errCompanyExists
+	}
+	if !companyExists {
+		return nil, ErrCompanyGlobalNotFound
+	}
+
+	installment := mapper.MapCreateToInstallment(&installmentDTO)
+	if installment.Amount.Sign() <= 0 {
+		return nil, ErrInvalidInstallmentAmount
+	}
+	if err := s.repo.Create(installment); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Str("company_global_id", installmentDTO.CompanyGlobalID.String()).
+			Msg("failed to create installment")
+		return nil, GormDefaultError(err)
+	}
+	return mapper.MapToInstallmentDTO(installment), nil
+}
+
+func (s *installmentService) Patch(installmentDTO dto.UpdateInstallmentDTO, id int64, version int64) (*dto.InstallmentDTO, ErrorUtil) {
+	existing, errFind := s.find(id, dto.FullRead)
+	if errFind != nil {
+		return nil, errFind
+	}
+
+	version, errVersion := CheckVersion(version, existing.Version)
+	if errVersion != nil {
+		return nil, errVersion
+	}
+	if existing.Status != model.InstallmentOpen {
+		return nil, ErrInstallmentNotOpen
+	}
+
+	columns := mapper.MapUpdateInstallmentToColumns(&installmentDTO)
+	if installmentDTO.Amount != nil && installmentDTO.Amount.Round(2).Sign() <= 0 {
+		return nil, ErrInvalidInstallmentAmount
+	}
+	if err := s.repo.Patch(id, version, columns); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("installment_id", id).
+			Msg("failed to patch installment")
+		return nil, GormDefaultError(err)
+	}
+	return s.FindByID(id, dto.FullRead)
+}
+
+// Delete apaga a parcela. Parcelas pagas ou canceladas são mantidas.
+func (s *installmentService) Delete(id int64, version int64) ErrorUtil {
+	existing, errFind := s.find(id, dto.FullRead)
+	if errFind != nil {
+		return errFind
+	}
+	if existing.Status != model.InstallmentOpen {
+		return ErrInstallmentNotOpen
+	}
+
+	if err := s.repo.Delete(id, version); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInstallmentNotFound
+		}
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("installment_id", id).
+			Msg("failed to delete installment")
+		return GormDefaultError(err)
+	}
+	return nil
+}
+
+func (s *installmentService) FindByID(id int64, opts dto.ReadOptions) (*dto.InstallmentDTO, ErrorUtil) {
+	installment, errFind := s.find(id, opts)
+	if errFind != nil {
+		return nil, errFind
+	}
+	return mapper.MapToInstallmentDTO(installment), nil
+}
+
+func (s *installmentService) find(id int64, opts dto.ReadOptions) (*model.Installment, ErrorUtil) {
+	installment, err := s.repo.FindByID(id, opts)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInstallmentNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("installment_id", id).
+			Msg("failed to find installment")
+		return nil, GormDefaultError(err)
+	}
+	return installment, nil
+}
+
+func (s *installmentService) FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.InstallmentDTO], ErrorUtil) {
+	companyExists, errCompanyExists := CheckCompanyGlobalExists(s.repoCompanyGlobal, companyGlobalID, false)
+	if errCompanyExists != nil {
+		log.Error().
+			Err(errCompanyExists).
+			Caller().
+			Str("company_global_id", strconv.FormatInt(companyGlobalID, 10)).
+			Msg("failed to check if company global exists")
+		return nil, errCompanyExists
+	}
+	if !companyExists {
+		return nil, ErrCompanyGlobalNotFound
+	}
+
+	installments, pageInfo, err := s.repo.FindAll(filters, page, opts, companyGlobalID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to findAll installments")
+		return nil, GormDefaultError(err)
+	}
+
+	items := make([]dto.InstallmentDTO, len(installments))
+	for i := range installments {
+		items[i] = *mapper.MapToInstallmentDTO(&installments[i])
+	}
+	return &dto.PaginatedResponse[dto.InstallmentDTO]{
+		Items:    items,
+		PageInfo: pageInfo,
+	}, nil
+}
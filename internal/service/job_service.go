@@ -0,0 +1,41 @@
+package service
+
+import (
+	"errors"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// JobServiceInterface define a consulta dos jobs da fila em segundo plano.
+type JobServiceInterface interface {
+	FindByID(id int64) (*dto.JobDTO, ErrorUtil)
+}
+
+type JobService struct {
+	repo database.JobRepositoryInterface
+}
+
+func NewJobService(repo database.JobRepositoryInterface) JobServiceInterface {
+	return &JobService{
+		repo: repo,
+	}
+}
+
+func (s *JobService) FindByID(id int64) (*dto.JobDTO, ErrorUtil) {
+	job, err := s.repo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to find job")
+		return nil, GormDefaultError(err)
+	}
+	return mapper.MapToJobDTO(job), nil
+}
@@ -6,7 +6,6 @@ import (
 	"go-sales/internal/dto"
 	"go-sales/internal/mapper"
 	"go-sales/internal/model"
-	"math"
 	"strconv"
 	"strings"
 
@@ -17,10 +16,12 @@ import (
 // PermissionServiceInterface define a interface para a lógica de negócios de permissões.
 type PermissionServiceInterface interface {
 	Create(permissionDTO dto.CreatePermissionDTO) (*dto.PermissionDTO, ErrorUtil)
-	Update(permissionDTO dto.CreatePermissionDTO, permissionID int64) (*dto.PermissionDTO, ErrorUtil)
-	Delete(permissionID int64) ErrorUtil
-	FindByID(permissionID int64) (*dto.PermissionDTO, ErrorUtil)
-	FindAll(filters map[string][]string, page, pageSize int, companyGlobalID int64) (*dto.PaginatedResponse[dto.PermissionDTO], ErrorUtil)
+	Update(permissionDTO dto.CreatePermissionDTO, permissionID int64, version int64) (*dto.PermissionDTO, ErrorUtil)
+	Delete(permissionID int64, version int64) ErrorUtil
+	Patch(permissionDTO dto.UpdatePermissionDTO, permissionID int64, version int64) (*dto.PermissionDTO, ErrorUtil)
+	FindByID(permissionID int64, opts dto.ReadOptions) (*dto.PermissionDTO, ErrorUtil)
+	FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.PermissionDTO], ErrorUtil)
+	Export(filters map[string][]string, opts dto.ReadOptions, companyGlobalID int64, fn func(*dto.PermissionDTO) error) ErrorUtil
 }
 
 // permissionService é a implementação concreta.
@@ -38,10 +39,10 @@ func (s *permissionService) Create(permissionDTO dto.CreatePermissionDTO) (*dto.
 	// Verificar se já existe uma permissão com o mesmo nome
 	permissionDTO.Name = strings.ToUpper(strings.TrimSpace(permissionDTO.Name))
 
-	companyGlobalExists, errCompanyGlobalExists := CheckCompanyGlobalExists(s.repoCompanyGlobal, permissionDTO.CompanyGlobalID, false)
+	companyGlobalExists, errCompanyGlobalExists := CheckCompanyGlobalExists(s.repoCompanyGlobal, permissionDTO.CompanyGlobalID.Int64(), false)
 	if errCompanyGlobalExists != nil {
 		log.Error().
-			Err(errCompanyGlobalExists).Str("company_global_id", strconv.FormatInt(permissionDTO.CompanyGlobalID, 10)).
+			Err(errCompanyGlobalExists).Str("company_global_id", permissionDTO.CompanyGlobalID.String()).
 			Msg("failed to check if company global exists")
 		return nil, errCompanyGlobalExists
 	}
@@ -49,18 +50,18 @@ func (s *permissionService) Create(permissionDTO dto.CreatePermissionDTO) (*dto.
 		log.Error().
 			Err(errCompanyGlobalExists).
 			Caller().
-			Str("company_global_id", strconv.FormatInt(permissionDTO.CompanyGlobalID, 10)).
+			Str("company_global_id", permissionDTO.CompanyGlobalID.String()).
 			Msg("failed to find existing company global")
 		return nil, ErrCompanyGlobalNotFound
 	}
 
-	existingPermission, err := s.repo.ExistsByName(permissionDTO.Name, permissionDTO.CompanyGlobalID)
+	existingPermission, err := s.repo.ExistsByName(permissionDTO.Name, permissionDTO.CompanyGlobalID.Int64())
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		log.Error().
 			Err(err).
 			Caller().
 			Str("permission_name", permissionDTO.Name).
-			Str("company_global_id", strconv.FormatInt(permissionDTO.CompanyGlobalID, 10)).
+			Str("company_global_id", permissionDTO.CompanyGlobalID.String()).
 			Msg("failed to find existing permission")
 		return nil, GormDefaultError(err)
 	}
@@ -69,7 +70,7 @@ func (s *permissionService) Create(permissionDTO dto.CreatePermissionDTO) (*dto.
 			Err(err).
 			Caller().
 			Str("permission_name", permissionDTO.Name).
-			Str("company_global_id", strconv.FormatInt(permissionDTO.CompanyGlobalID, 10)).
+			Str("company_global_id", permissionDTO.CompanyGlobalID.String()).
 			Msg("permission name is already in use")
 		return nil, ErrPermissionNameInUse
 	}
@@ -87,9 +88,9 @@ func (s *permissionService) Create(permissionDTO dto.CreatePermissionDTO) (*dto.
 	return mapper.MapToPermissionDTO(newPermission), nil
 }
 
-func (s *permissionService) Update(permissionDTO dto.CreatePermissionDTO, permissionID int64) (*dto.PermissionDTO, ErrorUtil) {
+func (s *permissionService) Update(permissionDTO dto.CreatePermissionDTO, permissionID int64, version int64) (*dto.PermissionDTO, ErrorUtil) {
 	permissionDTO.Name = strings.ToUpper(strings.TrimSpace(permissionDTO.Name))
-	existingPermission, err := s.repo.FindByID(permissionID)
+	existingPermission, err := s.repo.FindByID(permissionID, dto.FullRead)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		log.Error().
 			Err(err).
@@ -107,13 +108,18 @@ func (s *permissionService) Update(permissionDTO dto.CreatePermissionDTO, permis
 		return nil, ErrNotFound
 	}
 
-	existingPermissionName, err := s.repo.FindByName(permissionDTO.Name, permissionDTO.CompanyGlobalID)
+	version, errVersion := CheckVersion(version, existingPermission.Version)
+	if errVersion != nil {
+		return nil, errVersion
+	}
+
+	existingPermissionName, err := s.repo.FindByName(permissionDTO.Name, permissionDTO.CompanyGlobalID.Int64())
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		log.Error().
 			Err(err).
 			Caller().
 			Str("permission_name", permissionDTO.Name).
-			Str("company_global_id", strconv.FormatInt(permissionDTO.CompanyGlobalID, 10)).
+			Str("company_global_id", permissionDTO.CompanyGlobalID.String()).
 			Msg("failed to find existing permission")
 		return nil, GormDefaultError(err)
 	}
@@ -122,7 +128,7 @@ func (s *permissionService) Update(permissionDTO dto.CreatePermissionDTO, permis
 			Err(err).
 			Caller().
 			Str("permission_name", permissionDTO.Name).
-			Str("company_global_id", strconv.FormatInt(permissionDTO.CompanyGlobalID, 10)).
+			Str("company_global_id", permissionDTO.CompanyGlobalID.String()).
 			Msg("permission name is already in use")
 		return nil, ErrPermissionNameInUse
 	}
@@ -130,7 +136,7 @@ func (s *permissionService) Update(permissionDTO dto.CreatePermissionDTO, permis
 	existingPermission.Name = permissionDTO.Name
 	existingPermission.Description = permissionDTO.Description
 
-	if err := s.repo.Update(existingPermission); err != nil {
+	if err := s.repo.Update(existingPermission, version); err != nil {
 		log.Error().
 			Err(err).
 			Caller().
@@ -141,8 +147,58 @@ func (s *permissionService) Update(permissionDTO dto.CreatePermissionDTO, permis
 	return mapper.MapToPermissionDTO(existingPermission), nil
 }
 
-func (s *permissionService) Delete(permissionID int64) ErrorUtil {
-	err := s.repo.Delete(permissionID)
+// Patch aplica um JSON Merge Patch na permissão: apenas os campos enviados são alterados.
+func (s *permissionService) Patch(permissionDTO dto.UpdatePermissionDTO, permissionID int64, version int64) (*dto.PermissionDTO, ErrorUtil) {
+	existingPermission, err := s.repo.FindByID(permissionID, dto.FullRead)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Str("permission_id", strconv.FormatInt(permissionID, 10)).
+			Msg("failed to find existing permission")
+		return nil, GormDefaultError(err)
+	}
+
+	version, errVersion := CheckVersion(version, existingPermission.Version)
+	if errVersion != nil {
+		return nil, errVersion
+	}
+
+	if permissionDTO.Name != nil {
+		name := strings.ToUpper(strings.TrimSpace(*permissionDTO.Name))
+		permissionDTO.Name = &name
+
+		existingPermissionName, err := s.repo.FindByName(name, existingPermission.CompanyGlobalID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Error().
+				Err(err).
+				Caller().
+				Str("permission_name", name).
+				Msg("failed to find existing permission")
+			return nil, GormDefaultError(err)
+		}
+		if existingPermissionName != nil && existingPermissionName.ID != permissionID {
+			log.Error().
+				Caller().
+				Str("permission_name", name).
+				Msg("permission name is already in use")
+			return nil, ErrPermissionNameInUse
+		}
+	}
+
+	if err := s.repo.Patch(permissionID, version, mapper.MapUpdatePermissionToColumns(&permissionDTO)); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to patch permission")
+		return nil, GormDefaultError(err)
+	}
+
+	return s.FindByID(permissionID, dto.FullRead)
+}
+
+func (s *permissionService) Delete(permissionID int64, version int64) ErrorUtil {
+	err := s.repo.Delete(permissionID, version)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -153,8 +209,8 @@ func (s *permissionService) Delete(permissionID int64) ErrorUtil {
 	return nil
 }
 
-func (s *permissionService) FindByID(permissionID int64) (*dto.PermissionDTO, ErrorUtil) {
-	permission, err := s.repo.FindByID(permissionID)
+func (s *permissionService) FindByID(permissionID int64, opts dto.ReadOptions) (*dto.PermissionDTO, ErrorUtil) {
+	permission, err := s.repo.FindByID(permissionID, opts)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -165,7 +221,7 @@ func (s *permissionService) FindByID(permissionID int64) (*dto.PermissionDTO, Er
 	return mapper.MapToPermissionDTO(permission), nil
 }
 
-func (s *permissionService) FindAll(filters map[string][]string, page, pageSize int, companyGlobalID int64) (*dto.PaginatedResponse[dto.PermissionDTO], ErrorUtil) {
+func (s *permissionService) FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.PermissionDTO], ErrorUtil) {
 
 	companyExists, errCompanyExists := CheckCompanyGlobalExists(s.repoCompanyGlobal, companyGlobalID, false)
 	if errCompanyExists != nil {
@@ -180,7 +236,7 @@ func (s *permissionService) FindAll(filters map[string][]string, page, pageSize
 		return nil, ErrCompanyGlobalNotFound
 	}
 
-	permissions, totalItems, err := s.repo.FindAll(filters, page, pageSize, companyGlobalID)
+	permissions, pageInfo, err := s.repo.FindAll(filters, page, opts, companyGlobalID)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -192,18 +248,37 @@ func (s *permissionService) FindAll(filters map[string][]string, page, pageSize
 	permissionPtrs := make([]*model.Permission, len(permissions))
 	copy(permissionPtrs, permissions)
 	permissionDTOs := mapper.MapToPermissionDTOs(permissionPtrs)
-	totalPages := 0
-	if pageSize > 0 {
-		totalPages = int(math.Ceil(float64(totalItems) / float64(pageSize)))
-	}
 
 	return &dto.PaginatedResponse[dto.PermissionDTO]{
-		Items: *permissionDTOs,
-		PageInfo: dto.PageInfo{
-			Page:       page,
-			PageSize:   pageSize,
-			TotalItems: totalItems,
-			TotalPages: totalPages,
-		},
+		Items:    *permissionDTOs,
+		PageInfo: pageInfo,
 	}, nil
 }
+
+// Export percorre todas as permissões que atendem aos filtros, sem paginação, e entrega cada um como DTO para fn.
+func (s *permissionService) Export(filters map[string][]string, opts dto.ReadOptions, companyGlobalID int64, fn func(*dto.PermissionDTO) error) ErrorUtil {
+	companyExists, errCompanyExists := CheckCompanyGlobalExists(s.repoCompanyGlobal, companyGlobalID, false)
+	if errCompanyExists != nil {
+		log.Error().
+			Err(errCompanyExists).
+			Caller().
+			Str("company_global_id", strconv.FormatInt(companyGlobalID, 10)).
+			Msg("failed to check if company global exists")
+		return errCompanyExists
+	}
+	if !companyExists {
+		return ErrCompanyGlobalNotFound
+	}
+
+	err := s.repo.Stream(filters, opts, companyGlobalID, func(permission *model.Permission) error {
+		return fn(mapper.MapToPermissionDTO(permission))
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to export permissions")
+		return GormDefaultError(err)
+	}
+	return nil
+}
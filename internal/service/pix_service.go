@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
+	"go-sales/internal/model"
+	"go-sales/internal/payment/pix"
+	"go-sales/pkg/qrcode"
+	"go-sales/pkg/util"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// Resultados de um PIX recebido no webhook (ver dto.PixNotificationResultDTO).
+const (
+	PixResultSettled            = "settled"
+	PixResultAlreadyProcessed   = "already_processed"
+	PixResultUnknownTxID        = "unknown_txid"
+	PixResultAmountMismatch     = "amount_mismatch"
+	PixResultInstallmentNotOpen = "installment_not_open"
+)
+
+// defaultPixChargeExpiration é a validade das cobranças dinâmicas sem expiresIn.
+const defaultPixChargeExpiration = 24 * time.Hour
+
+// qrCodeScale é o tamanho, em pixels, de cada módulo do PNG do QR Code.
+const qrCodeScale = 6
+
+// PixServiceInterface define a geração das cobranças PIX das parcelas e a baixa das parcelas pelos
+// PIX recebidos no webhook do PSP.
+type PixServiceInterface interface {
+	CreateCharge(ctx context.Context, installmentID int64, chargeDTO dto.CreatePixChargeDTO) (*dto.PixChargeDTO, ErrorUtil)
+	FindByID(id int64) (*dto.PixChargeDTO, ErrorUtil)
+	FindByInstallment(installmentID int64) ([]dto.PixChargeDTO, ErrorUtil)
+	// QRCode devolve o PNG do QR Code da cobrança.
+	QRCode(id int64) ([]byte, ErrorUtil)
+	// HandleWebhook autentica a notificação do PSP e baixa as parcelas dos PIX recebidos.
+	HandleWebhook(header http.Header, body []byte) (*dto.PixWebhookResultDTO, ErrorUtil)
+}
+
+// pixService é a implementação concreta.
+type pixService struct {
+	repo              database.PixChargeRepositoryInterface
+	repoInstallment   database.InstallmentRepositoryInterface
+	repoCompanyGlobal database.CompanyGlobalRepositoryInterface
+	provider          pix.Provider
+}
+
+// NewPixService cria uma nova instância do serviço de PIX.
+func NewPixService(repo database.PixChargeRepositoryInterface, repoInstallment database.InstallmentRepositoryInterface, repoCompanyGlobal database.CompanyGlobalRepositoryInterface, provider pix.Provider) PixServiceInterface {
+	return &pixService{
+		repo:              repo,
+		repoInstallment:   repoInstallment,
+		repoCompanyGlobal: repoCompanyGlobal,
+		provider:          provider,
+	}
+}
+
+// CreateCharge gera uma cobrança PIX do valor da parcela, com a chave PIX e o endereço principal da
+// empresa. A cobrança dinâmica é registrada antes no PSP, que devolve a location do payload.
+func (s *pixService) CreateCharge(ctx context.Context, installmentID int64, chargeDTO dto.CreatePixChargeDTO) (*dto.PixChargeDTO, ErrorUtil) {
+	installment, err := s.repoInstallment.FindByID(installmentID, dto.FullRead)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInstallmentNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("installment_id", installmentID).
+			Msg("failed to find installment")
+		return nil, GormDefaultError(err)
+	}
+	if installment.Status != model.InstallmentOpen {
+		return nil, ErrInstallmentNotOpen
+	}
+
+	company, err := s.repoCompanyGlobal.FindByID(installment.CompanyGlobalID, false, dto.FullRead)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCompanyGlobalNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("company_global_id", installment.CompanyGlobalID).
+			Msg("failed to find company global")
+		return nil, GormDefaultError(err)
+	}
+	if company.PixKey == nil {
+		return nil, ErrPixKeyNotConfigured
+	}
+	main := company.MainAddress()
+	if main == nil || pix.MerchantText(main.City) == "" {
+		return nil, ErrPixMerchantCityRequired
+	}
+
+	charge := &model.PixCharge{
+		CompanyGlobalID: installment.CompanyGlobalID,
+		InstallmentID:   installment.ID,
+		Type:            chargeDTO.Type,
+		PixKey:          *company.PixKey,
+		Amount:          installment.Amount,
+		Status:          model.PixChargeActive,
+	}
+	payload := pix.Payload{
+		Amount:       installment.Amount,
+		MerchantName: company.SocialName,
+		MerchantCity: main.City,
+	}
+	if pix.MerchantText(payload.MerchantName) == "" {
+		payload.MerchantName = company.Name
+	}
+
+	txIDSize := pix.MaxStaticTxID
+	if chargeDTO.Type == model.PixChargeDynamic {
+		txIDSize = 32
+	}
+	if charge.TxID, err = pix.NewTxID(txIDSize); err != nil {
+		log.Error().Err(err).Caller().Msg("failed to generate PIX txid")
+		return nil, ErrInternalServer
+	}
+
+	if chargeDTO.Type == model.PixChargeDynamic {
+		expiration := defaultPixChargeExpiration
+		if chargeDTO.ExpiresIn != nil {
+			expiration = time.Duration(*chargeDTO.ExpiresIn) * time.Second
+		}
+		location, err := s.provider.CreateCharge(ctx, pix.Charge{
+			TxID:        charge.TxID,
+			Key:         charge.PixKey,
+			Amount:      charge.Amount,
+			Expiration:  expiration,
+			PayerName:   installment.CustomerName,
+			PayerCGC:    util.OnlyDigits(stringOrEmpty(installment.CustomerCGC)),
+			Description: stringOrEmpty(chargeDTO.Description),
+		})
+		if err != nil {
+			log.Error().
+				Err(err).
+				Caller().
+				Int64("installment_id", installmentID).
+				Msg("failed to create PIX charge at the provider")
+			return nil, ErrPixProviderFailed
+		}
+		charge.Location = &location.URL
+		charge.ExpiresAt = &location.ExpiresAt
+		payload.URL = location.URL
+	} else {
+		payload.Key = charge.PixKey
+		payload.TxID = charge.TxID
+		payload.Description = stringOrEmpty(chargeDTO.Description)
+	}
+
+	if charge.Payload, err = payload.Encode(); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("installment_id", installmentID).
+			Msg("failed to encode PIX payload")
+		return nil, NewError(err.Error(), http.StatusUnprocessableEntity, "pix_payload_failed", err.Error())
+	}
+	if err := s.repo.Create(charge); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("installment_id", installmentID).
+			Msg("failed to create PIX charge")
+		return nil, GormDefaultError(err)
+	}
+	log.Info().Int64("pix_charge_id", charge.ID).Int64("installment_id", installmentID).Str("txid", charge.TxID).Msg("PIX charge created")
+	return s.chargeWithQRCode(charge)
+}
+
+func (s *pixService) FindByID(id int64) (*dto.PixChargeDTO, ErrorUtil) {
+	charge, errFind := s.find(id)
+	if errFind != nil {
+		return nil, errFind
+	}
+	return s.chargeWithQRCode(charge)
+}
+
+func (s *pixService) find(id int64) (*model.PixCharge, ErrorUtil) {
+	charge, err := s.repo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPixChargeNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("pix_charge_id", id).
+			Msg("failed to find PIX charge")
+		return nil, GormDefaultError(err)
+	}
+	return charge, nil
+}
+
+func (s *pixService) FindByInstallment(installmentID int64) ([]dto.PixChargeDTO, ErrorUtil) {
+	if _, err := s.repoInstallment.FindByID(installmentID, dto.ReadOptions{Fields: []string{"id"}}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInstallmentNotFound
+		}
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("installment_id", installmentID).
+			Msg("failed to find installment")
+		return nil, GormDefaultError(err)
+	}
+
+	charges, err := s.repo.FindByInstallment(installmentID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("installment_id", installmentID).
+			Msg("failed to find PIX charges")
+		return nil, GormDefaultError(err)
+	}
+	return mapper.MapToPixChargeDTOs(charges), nil
+}
+
+func (s *pixService) QRCode(id int64) ([]byte, ErrorUtil) {
+	charge, errFind := s.find(id)
+	if errFind != nil {
+		return nil, errFind
+	}
+	png, err := qrCodePNG(charge.Payload)
+	if err != nil {
+		log.Error().Err(err).Caller().Int64("pix_charge_id", id).Msg("failed to render PIX QR code")
+		return nil, ErrInternalServer
+	}
+	return png, nil
+}
+
+// chargeWithQRCode devolve a cobrança com o QR Code numa data URI.
+func (s *pixService) chargeWithQRCode(charge *model.PixCharge) (*dto.PixChargeDTO, ErrorUtil) {
+	png, err := qrCodePNG(charge.Payload)
+	if err != nil {
+		log.Error().Err(err).Caller().Int64("pix_charge_id", charge.ID).Msg("failed to render PIX QR code")
+		return nil, ErrInternalServer
+	}
+	chargeDTO := mapper.MapToPixChargeDTO(charge)
+	chargeDTO.QRCode = "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+	return chargeDTO, nil
+}
+
+func qrCodePNG(payload string) ([]byte, error) {
+	code, err := qrcode.Encode([]byte(payload))
+	if err != nil {
+		return nil, err
+	}
+	return code.PNG(qrCodeScale)
+}
+
+// HandleWebhook processa cada PIX recebido de forma independente e idempotente: o PIX já registrado
+// na cobrança é ignorado. Um valor diferente do cobrado fica registrado na cobrança, mas não baixa a
+// parcela. Só erros de banco fazem o webhook falhar, para que o PSP reenvie a notificação.
+func (s *pixService) HandleWebhook(header http.Header, body []byte) (*dto.PixWebhookResultDTO, ErrorUtil) {
+	notifications, err := s.provider.ParseNotification(header, body)
+	if err != nil {
+		log.Warn().Err(err).Msg("rejected PIX notification")
+		return nil, ErrInvalidPixNotification
+	}
+
+	results := make([]dto.PixNotificationResultDTO, 0, len(notifications))
+	for _, notification := range notifications {
+		result, errNotification := s.settle(notification)
+		if errNotification != nil {
+			return nil, errNotification
+		}
+		results = append(results, result)
+	}
+	return &dto.PixWebhookResultDTO{Results: results}, nil
+}
+
+func (s *pixService) settle(notification pix.Notification) (dto.PixNotificationResultDTO, ErrorUtil) {
+	result := dto.PixNotificationResultDTO{EndToEndID: notification.EndToEndID, TxID: notification.TxID}
+	if notification.TxID == "" {
+		result.Result = PixResultUnknownTxID
+		return result, nil
+	}
+	charge, err := s.repo.FindByTxID(notification.TxID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Warn().Str("txid", notification.TxID).Str("end_to_end_id", notification.EndToEndID).Msg("PIX received for an unknown txid")
+		result.Result = PixResultUnknownTxID
+		return result, nil
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Str("txid", notification.TxID).
+			Msg("failed to find PIX charge")
+		return result, GormDefaultError(err)
+	}
+	installmentID := util.SnowflakeID(charge.InstallmentID)
+	result.InstallmentID = &installmentID
+	if charge.Status != model.PixChargeActive {
+		result.Result = PixResultAlreadyProcessed
+		return result, nil
+	}
+
+	paidAt := notification.PaidAt
+	if paidAt.IsZero() {
+		paidAt = time.Now()
+	}
+	payment := model.InstallmentPayment{
+		Amount:    notification.Amount,
+		PaidAt:    paidAt,
+		Method:    model.PaymentMethodPix,
+		Reference: notification.EndToEndID,
+	}
+	amountMatches := notification.Amount.Cmp(charge.Amount) == 0
+	settled, err := s.repo.MarkPaid(charge.ID, payment, amountMatches, installmentPaid)
+	switch {
+	case errors.Is(err, database.ErrPixChargeNotActive):
+		result.Result = PixResultAlreadyProcessed
+	case err != nil:
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("pix_charge_id", charge.ID).
+			Msg("failed to register PIX payment")
+		return result, GormDefaultError(err)
+	case !amountMatches:
+		log.Warn().Int64("pix_charge_id", charge.ID).Str("amount", notification.Amount.String()).Msg("PIX received with a different amount")
+		result.Result = PixResultAmountMismatch
+	case !settled:
+		log.Warn().Int64("pix_charge_id", charge.ID).Msg("PIX received for an installment that is not open")
+		result.Result = PixResultInstallmentNotOpen
+	default:
+		log.Info().Int64("pix_charge_id", charge.ID).Int64("installment_id", charge.InstallmentID).Msg("installment paid by PIX")
+		result.Result = PixResultSettled
+	}
+	return result, nil
+}
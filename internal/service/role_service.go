@@ -6,7 +6,6 @@ import (
 	"go-sales/internal/dto"
 	"go-sales/internal/mapper"
 	"go-sales/internal/model"
-	"math"
 	"strconv"
 	"strings"
 
@@ -17,10 +16,12 @@ import (
 // RoleServiceInterface define a interface para a lógica de negócios de roles.
 type RoleServiceInterface interface {
 	Create(roleDTO dto.CreateRoleDTO) (*dto.RoleDTO, ErrorUtil)
-	Update(roleDTO dto.RoleDTO, roleID int64) (*dto.RoleDTO, ErrorUtil)
-	Delete(roleID int64) ErrorUtil
-	FindByID(roleID int64) (*dto.RoleDTO, ErrorUtil)
-	FindAll(filters map[string][]string, page, pageSize int, companyGlobalID int64) (*dto.PaginatedResponse[dto.RoleDTO], ErrorUtil)
+	Update(roleDTO dto.RoleDTO, roleID int64, version int64) (*dto.RoleDTO, ErrorUtil)
+	Delete(roleID int64, version int64) ErrorUtil
+	Patch(roleDTO dto.UpdateRoleDTO, roleID int64, version int64) (*dto.RoleDTO, ErrorUtil)
+	FindByID(roleID int64, opts dto.ReadOptions) (*dto.RoleDTO, ErrorUtil)
+	FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.RoleDTO], ErrorUtil)
+	Export(filters map[string][]string, opts dto.ReadOptions, companyGlobalID int64, fn func(*dto.RoleDTO) error) ErrorUtil
 }
 
 // roleService é a implementação concreta.
@@ -37,12 +38,12 @@ func NewRoleService(repo database.RoleRepositoryInterface, repoPerm database.Per
 
 func (s *roleService) Create(roleDTO dto.CreateRoleDTO) (*dto.RoleDTO, ErrorUtil) {
 	roleDTO.Name = strings.ToUpper(strings.TrimSpace(roleDTO.Name))
-	companyGlobalExists, err := s.repoCompanyGlobal.Exists(roleDTO.CompanyGlobalID, false)
+	companyGlobalExists, err := s.repoCompanyGlobal.Exists(roleDTO.CompanyGlobalID.Int64(), false)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		log.Error().
 			Err(err).
-			Str("company_global_id", strconv.FormatInt(roleDTO.CompanyGlobalID, 10)).
-			Str("company_global_id", strconv.FormatInt(roleDTO.CompanyGlobalID, 10)).
+			Str("company_global_id", roleDTO.CompanyGlobalID.String()).
+			Str("company_global_id", roleDTO.CompanyGlobalID.String()).
 			Msg("failed to check if company global exists")
 		return nil, GormDefaultError(err)
 	}
@@ -50,12 +51,12 @@ func (s *roleService) Create(roleDTO dto.CreateRoleDTO) (*dto.RoleDTO, ErrorUtil
 		log.Error().
 			Err(err).
 			Caller().
-			Str("company_global_id", strconv.FormatInt(roleDTO.CompanyGlobalID, 10)).
+			Str("company_global_id", roleDTO.CompanyGlobalID.String()).
 			Msg("failed to find existing company global")
 		return nil, ErrCompanyGlobalNotFound
 	}
 
-	existingRole, err := s.repo.ExistsByName(roleDTO.Name, roleDTO.CompanyGlobalID)
+	existingRole, err := s.repo.ExistsByName(roleDTO.Name, roleDTO.CompanyGlobalID.Int64())
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		log.Error().
 			Err(err).
@@ -74,9 +75,10 @@ func (s *roleService) Create(roleDTO dto.CreateRoleDTO) (*dto.RoleDTO, ErrorUtil
 
 	permIds := make([]int64, len(roleDTO.Permissions))
 	for i, perm := range roleDTO.Permissions {
-		permIds[i] = perm.ID
+		permIds[i] = perm.ID.Int64()
 	}
-	permissions, err := s.repoPerm.FindByIDs(permIds, &roleDTO.CompanyGlobalID) // Ensure permissions belong to the same company global
+	companyGlobalID := roleDTO.CompanyGlobalID.Int64()
+	permissions, err := s.repoPerm.FindByIDs(permIds, &companyGlobalID) // Ensure permissions belong to the same company global
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -98,7 +100,7 @@ func (s *roleService) Create(roleDTO dto.CreateRoleDTO) (*dto.RoleDTO, ErrorUtil
 	newRole := mapper.MapCreateToRole(&roleDTO)
 	newRole.Permissions = permissions
 
-	if err := s.repo.Create(newRole); err != nil {
+	if err := s.repo.Create(newRole, roleCreated); err != nil {
 		log.Error().
 			Err(err).
 			Caller().
@@ -109,14 +111,14 @@ func (s *roleService) Create(roleDTO dto.CreateRoleDTO) (*dto.RoleDTO, ErrorUtil
 	return mapper.MapToRoleDTO(newRole), nil
 }
 
-func (s *roleService) Update(roleDTO dto.RoleDTO, roleID int64) (*dto.RoleDTO, ErrorUtil) {
+func (s *roleService) Update(roleDTO dto.RoleDTO, roleID int64, version int64) (*dto.RoleDTO, ErrorUtil) {
 	roleDTO.Name = strings.ToUpper(strings.TrimSpace(roleDTO.Name))
-	companyGlobalExists, err := s.repoCompanyGlobal.Exists(roleDTO.CompanyGlobalID, false)
+	companyGlobalExists, err := s.repoCompanyGlobal.Exists(roleDTO.CompanyGlobalID.Int64(), false)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		log.Error().
 			Err(err).
-			Str("company_global_id", strconv.FormatInt(roleDTO.CompanyGlobalID, 10)).
-			Str("company_global_id", strconv.FormatInt(roleDTO.CompanyGlobalID, 10)).
+			Str("company_global_id", roleDTO.CompanyGlobalID.String()).
+			Str("company_global_id", roleDTO.CompanyGlobalID.String()).
 			Msg("failed to check if company global exists")
 		return nil, GormDefaultError(err)
 	}
@@ -124,12 +126,12 @@ func (s *roleService) Update(roleDTO dto.RoleDTO, roleID int64) (*dto.RoleDTO, E
 		log.Error().
 			Err(err).
 			Caller().
-			Str("company_global_id", strconv.FormatInt(roleDTO.CompanyGlobalID, 10)).
+			Str("company_global_id", roleDTO.CompanyGlobalID.String()).
 			Msg("failed to find existing company global")
 		return nil, ErrCompanyGlobalNotFound
 	}
 
-	existingRole, err := s.repo.FindByID(roleID)
+	existingRole, err := s.repo.FindByID(roleID, dto.FullRead)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		log.Error().
 			Err(err).
@@ -146,8 +148,13 @@ func (s *roleService) Update(roleDTO dto.RoleDTO, roleID int64) (*dto.RoleDTO, E
 		return nil, ErrNotFound
 	}
 
+	version, errVersion := CheckVersion(version, existingRole.Version)
+	if errVersion != nil {
+		return nil, errVersion
+	}
+
 	if roleDTO.Name != existingRole.Name {
-		roleWithNewName, err := s.repo.ExistsByName(roleDTO.Name, roleDTO.CompanyGlobalID)
+		roleWithNewName, err := s.repo.ExistsByName(roleDTO.Name, roleDTO.CompanyGlobalID.Int64())
 		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 			log.Error().
 				Err(err).
@@ -171,9 +178,10 @@ func (s *roleService) Update(roleDTO dto.RoleDTO, roleID int64) (*dto.RoleDTO, E
 
 	permIds := make([]int64, len(roleDTO.Permissions))
 	for i, perm := range roleDTO.Permissions {
-		permIds[i] = perm.ID
+		permIds[i] = perm.ID.Int64()
 	}
-	permissions, err := s.repoPerm.FindByIDs(permIds, &roleDTO.CompanyGlobalID) // Ensure permissions belong to the same company global
+	companyGlobalID := roleDTO.CompanyGlobalID.Int64()
+	permissions, err := s.repoPerm.FindByIDs(permIds, &companyGlobalID) // Ensure permissions belong to the same company global
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -193,8 +201,9 @@ func (s *roleService) Update(roleDTO dto.RoleDTO, roleID int64) (*dto.RoleDTO, E
 	}
 
 	updateRole := mapper.MapToRole(&roleDTO)
+	updateRole.ID = roleID
 
-	if err := s.repo.Update(updateRole); err != nil {
+	if err := s.repo.Update(updateRole, version, roleUpdated); err != nil {
 		log.Error().
 			Err(err).
 			Caller().
@@ -205,8 +214,85 @@ func (s *roleService) Update(roleDTO dto.RoleDTO, roleID int64) (*dto.RoleDTO, E
 	return mapper.MapToRoleDTO(updateRole), nil
 }
 
-func (s *roleService) Delete(roleID int64) ErrorUtil {
-	err := s.repo.Delete(roleID)
+// Patch aplica um JSON Merge Patch na role: apenas os campos enviados são alterados.
+// permissions, quando enviado, substitui a lista inteira.
+func (s *roleService) Patch(roleDTO dto.UpdateRoleDTO, roleID int64, version int64) (*dto.RoleDTO, ErrorUtil) {
+	existingRole, err := s.repo.FindByID(roleID, dto.FullRead)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Str("role_id", strconv.FormatInt(roleID, 10)).
+			Msg("failed to find existing role")
+		return nil, GormDefaultError(err)
+	}
+
+	version, errVersion := CheckVersion(version, existingRole.Version)
+	if errVersion != nil {
+		return nil, errVersion
+	}
+
+	if roleDTO.Name != nil {
+		name := strings.ToUpper(strings.TrimSpace(*roleDTO.Name))
+		roleDTO.Name = &name
+
+		if name != existingRole.Name {
+			roleWithNewName, err := s.repo.ExistsByName(name, existingRole.CompanyGlobalID)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Error().
+					Err(err).
+					Caller().
+					Str("role_name", name).
+					Msg("failed to find existing role")
+				return nil, GormDefaultError(err)
+			}
+			if roleWithNewName {
+				log.Error().
+					Caller().
+					Str("role_name", name).
+					Msg("role name is already in use")
+				return nil, ErrRoleNameInUse
+			}
+		}
+	}
+
+	var permissions []*model.Permission
+	if roleDTO.Permissions != nil {
+		permIds := make([]int64, len(roleDTO.Permissions))
+		for i, perm := range roleDTO.Permissions {
+			permIds[i] = perm.ID.Int64()
+		}
+		permissions, err = s.repoPerm.FindByIDs(permIds, &existingRole.CompanyGlobalID) // Ensure permissions belong to the same company global
+		if err != nil {
+			log.Error().
+				Err(err).
+				Caller().
+				Str("role_id", strconv.FormatInt(roleID, 10)).
+				Msg("failed to find role permissions")
+			return nil, GormDefaultError(err)
+		}
+		if len(permissions) != len(permIds) {
+			log.Error().
+				Caller().
+				Str("role_id", strconv.FormatInt(roleID, 10)).
+				Msg("failed to find role permissions")
+			return nil, ErrPermissionsNotFound
+		}
+	}
+
+	if err := s.repo.Patch(roleID, version, mapper.MapUpdateRoleToColumns(&roleDTO), permissions, roleUpdated); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to patch role")
+		return nil, GormDefaultError(err)
+	}
+
+	return s.FindByID(roleID, dto.FullRead)
+}
+
+func (s *roleService) Delete(roleID int64, version int64) ErrorUtil {
+	err := s.repo.Delete(roleID, version, roleDeleted)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -217,8 +303,8 @@ func (s *roleService) Delete(roleID int64) ErrorUtil {
 	return nil
 }
 
-func (s *roleService) FindByID(roleID int64) (*dto.RoleDTO, ErrorUtil) {
-	role, err := s.repo.FindByID(roleID)
+func (s *roleService) FindByID(roleID int64, opts dto.ReadOptions) (*dto.RoleDTO, ErrorUtil) {
+	role, err := s.repo.FindByID(roleID, opts)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -229,7 +315,7 @@ func (s *roleService) FindByID(roleID int64) (*dto.RoleDTO, ErrorUtil) {
 	return mapper.MapToRoleDTO(role), nil
 }
 
-func (s *roleService) FindAll(filters map[string][]string, page, pageSize int, companyGlobalID int64) (*dto.PaginatedResponse[dto.RoleDTO], ErrorUtil) {
+func (s *roleService) FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.RoleDTO], ErrorUtil) {
 
 	companyExists, errCompanyExists := CheckCompanyGlobalExists(s.repoCompanyGlobal, companyGlobalID, false)
 	if errCompanyExists != nil {
@@ -244,7 +330,7 @@ func (s *roleService) FindAll(filters map[string][]string, page, pageSize int, c
 		return nil, ErrCompanyGlobalNotFound
 	}
 
-	roles, totalItems, err := s.repo.FindAll(filters, page, pageSize, companyGlobalID)
+	roles, pageInfo, err := s.repo.FindAll(filters, page, opts, companyGlobalID)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -258,18 +344,37 @@ func (s *roleService) FindAll(filters map[string][]string, page, pageSize int, c
 		rolePtrs[i] = &roles[i]
 	}
 	roleDTOs := mapper.MapToRoleDTOs(rolePtrs)
-	totalPages := 0
-	if pageSize > 0 {
-		totalPages = int(math.Ceil(float64(totalItems) / float64(pageSize)))
-	}
 
 	return &dto.PaginatedResponse[dto.RoleDTO]{
-		Items: *roleDTOs,
-		PageInfo: dto.PageInfo{
-			Page:       page,
-			PageSize:   pageSize,
-			TotalItems: totalItems,
-			TotalPages: totalPages,
-		},
+		Items:    *roleDTOs,
+		PageInfo: pageInfo,
 	}, nil
 }
+
+// Export percorre todas as roles que atendem aos filtros, sem paginação, e entrega cada um como DTO para fn.
+func (s *roleService) Export(filters map[string][]string, opts dto.ReadOptions, companyGlobalID int64, fn func(*dto.RoleDTO) error) ErrorUtil {
+	companyExists, errCompanyExists := CheckCompanyGlobalExists(s.repoCompanyGlobal, companyGlobalID, false)
+	if errCompanyExists != nil {
+		log.Error().
+			Err(errCompanyExists).
+			Caller().
+			Str("company_global_id", strconv.FormatInt(companyGlobalID, 10)).
+			Msg("failed to check if company global exists")
+		return errCompanyExists
+	}
+	if !companyExists {
+		return ErrCompanyGlobalNotFound
+	}
+
+	err := s.repo.Stream(filters, opts, companyGlobalID, func(role *model.Role) error {
+		return fn(mapper.MapToRoleDTO(role))
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to export roles")
+		return GormDefaultError(err)
+	}
+	return nil
+}
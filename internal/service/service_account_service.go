@@ -0,0 +1,428 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-sales/internal/auth"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/mapper"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const (
+	// APIKeyPrefix marca as chaves de API, para o middleware de autenticação reconhecê-las e para
+	// ferramentas de varredura de segredos encontrá-las em repositórios e logs.
+	APIKeyPrefix = "gsk_"
+
+	// apiKeyTouchInterval é o intervalo mínimo entre duas gravações de last_used_at da mesma chave.
+	apiKeyTouchInterval = time.Minute
+)
+
+// ServiceAccountServiceInterface define a gestão das contas de serviço e das suas chaves de API.
+// companyGlobalID é a empresa de quem fez a requisição: contas de outras empresas não são encontradas.
+type ServiceAccountServiceInterface interface {
+	Create(accountDTO dto.CreateServiceAccountDTO, companyGlobalID int64) (*dto.ServiceAccountDTO, ErrorUtil)
+	Patch(accountDTO dto.UpdateServiceAccountDTO, id int64, version int64, companyGlobalID int64) (*dto.ServiceAccountDTO, ErrorUtil)
+	Delete(id int64, version int64, companyGlobalID int64) ErrorUtil
+	FindByID(id int64, opts dto.ReadOptions, companyGlobalID int64) (*dto.ServiceAccountDTO, ErrorUtil)
+	FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.ServiceAccountDTO], ErrorUtil)
+
+	CreateKey(id int64, keyDTO dto.CreateAPIKeyDTO, companyGlobalID int64) (*dto.CreatedAPIKeyDTO, ErrorUtil)
+	RotateKey(id int64, keyID int64, rotateDTO dto.RotateAPIKeyDTO, companyGlobalID int64) (*dto.CreatedAPIKeyDTO, ErrorUtil)
+	FindKeys(id int64, companyGlobalID int64) ([]dto.APIKeyDTO, ErrorUtil)
+	RevokeKey(id int64, keyID int64, companyGlobalID int64) ErrorUtil
+}
+
+// serviceAccountService é a implementação concreta.
+type serviceAccountService struct {
+	repo              database.ServiceAccountRepositoryInterface
+	repoRole          database.RoleRepositoryInterface
+	repoCompanyGlobal database.CompanyGlobalRepositoryInterface
+}
+
+// NewServiceAccountService cria uma nova instância do serviço de contas de serviço.
+func NewServiceAccountService(repo database.ServiceAccountRepositoryInterface, repoRole database.RoleRepositoryInterface, repoCompanyGlobal database.CompanyGlobalRepositoryInterface) ServiceAccountServiceInterface {
+	return &serviceAccountService{repo: repo, repoRole: repoRole, repoCompanyGlobal: repoCompanyGlobal}
+}
+
+// findRoles carrega as roles pedidas, que precisam existir e ser da mesma empresa da conta.
+func (s *serviceAccountService) findRoles(roleIDs []util.SnowflakeID, companyGlobalID int64) ([]*model.Role, ErrorUtil) {
+	roles, err := s.repoRole.FindAllByIDs(util.FromSnowflakeIDs(roleIDs))
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Str("role_ids", fmt.Sprintf("%v", roleIDs)).
+			Msg("failed to find roles by IDs")
+		return nil, GormDefaultError(err)
+	}
+	if len(roles) != len(roleIDs) {
+		return nil, ErrRoleNotFound
+	}
+	for _, role := range roles {
+		if role.CompanyGlobalID != companyGlobalID {
+			return nil, ErrRoleNotFound
+		}
+	}
+	return roles, nil
+}
+
+// Create cria a conta na empresa de quem fez a requisição; companyGlobalId de outra empresa é recusado.
+func (s *serviceAccountService) Create(accountDTO dto.CreateServiceAccountDTO, companyGlobalID int64) (*dto.ServiceAccountDTO, ErrorUtil) {
+	if accountDTO.CompanyGlobalID.Int64() != companyGlobalID {
+		return nil, ErrServiceAccountTenantMismatch
+	}
+	companyExists, errCompanyExists := CheckCompanyGlobalExists(s.repoCompanyGlobal, companyGlobalID, false)
+	if errCompanyExists != nil {
+		return nil, errCompanyExists
+	}
+	if !companyExists {
+		return nil, ErrCompanyGlobalNotFound
+	}
+
+	nameInUse, err := s.repo.ExistsByName(accountDTO.Name, companyGlobalID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Str("name", accountDTO.Name).
+			Msg("failed to check if service account name exists")
+		return nil, GormDefaultError(err)
+	}
+	if nameInUse {
+		return nil, ErrServiceAccountNameInUse
+	}
+
+	roles, errRoles := s.findRoles(accountDTO.RoleIDs, companyGlobalID)
+	if errRoles != nil {
+		return nil, errRoles
+	}
+
+	account := mapper.MapCreateToServiceAccount(&accountDTO, roles)
+	if err := s.repo.Create(account); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Str("name", accountDTO.Name).
+			Msg("failed to create service account")
+		return nil, GormDefaultError(err)
+	}
+	log.Info().Int64("service_account_id", account.ID).Str("company_global_id", accountDTO.CompanyGlobalID.String()).Msg("service account created")
+	return mapper.MapToServiceAccountDTO(account), nil
+}
+
+func (s *serviceAccountService) Patch(accountDTO dto.UpdateServiceAccountDTO, id int64, version int64, companyGlobalID int64) (*dto.ServiceAccountDTO, ErrorUtil) {
+	existing, errFind := s.find(id, dto.FullRead, companyGlobalID)
+	if errFind != nil {
+		return nil, errFind
+	}
+
+	version, errVersion := CheckVersion(version, existing.Version)
+	if errVersion != nil {
+		return nil, errVersion
+	}
+
+	if accountDTO.Name != nil && *accountDTO.Name != existing.Name {
+		nameInUse, err := s.repo.ExistsByName(*accountDTO.Name, existing.CompanyGlobalID)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Caller().
+				Str("name", *accountDTO.Name).
+				Msg("failed to check if service account name exists")
+			return nil, GormDefaultError(err)
+		}
+		if nameInUse {
+			return nil, ErrServiceAccountNameInUse
+		}
+	}
+
+	var roles []*model.Role
+	if accountDTO.RoleIDs != nil {
+		var errRoles ErrorUtil
+		roles, errRoles = s.findRoles(accountDTO.RoleIDs, existing.CompanyGlobalID)
+		if errRoles != nil {
+			return nil, errRoles
+		}
+	}
+
+	columns := mapper.MapUpdateServiceAccountToColumns(&accountDTO)
+	if err := s.repo.Patch(id, version, companyGlobalID, columns, roles); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("service_account_id", id).
+			Msg("failed to patch service account")
+		return nil, GormDefaultError(err)
+	}
+	return s.FindByID(id, dto.FullRead, companyGlobalID)
+}
+
+// Delete apaga a conta de serviço e revoga todas as suas chaves.
+func (s *serviceAccountService) Delete(id int64, version int64, companyGlobalID int64) ErrorUtil {
+	if err := s.repo.Delete(id, version, companyGlobalID, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrServiceAccountNotFound
+		}
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("service_account_id", id).
+			Msg("failed to delete service account")
+		return GormDefaultError(err)
+	}
+	return nil
+}
+
+func (s *serviceAccountService) FindByID(id int64, opts dto.ReadOptions, companyGlobalID int64) (*dto.ServiceAccountDTO, ErrorUtil) {
+	account, errFind := s.find(id, opts, companyGlobalID)
+	if errFind != nil {
+		return nil, errFind
+	}
+	return mapper.MapToServiceAccountDTO(account), nil
+}
+
+func (s *serviceAccountService) find(id int64, opts dto.ReadOptions, companyGlobalID int64) (*model.ServiceAccount, ErrorUtil) {
+	account, err := s.repo.FindByID(id, opts, companyGlobalID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrServiceAccountNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("service_account_id", id).
+			Msg("failed to find service account")
+		return nil, GormDefaultError(err)
+	}
+	return account, nil
+}
+
+func (s *serviceAccountService) FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.ServiceAccountDTO], ErrorUtil) {
+	companyExists, errCompanyExists := CheckCompanyGlobalExists(s.repoCompanyGlobal, companyGlobalID, false)
+	if errCompanyExists != nil {
+		log.Error().
+			Err(errCompanyExists).
+			Caller().
+			Str("company_global_id", strconv.FormatInt(companyGlobalID, 10)).
+			Msg("failed to check if company global exists")
+		return nil, errCompanyExists
+	}
+	if !companyExists {
+		return nil, ErrCompanyGlobalNotFound
+	}
+
+	accounts, pageInfo, err := s.repo.FindAll(filters, page, opts, companyGlobalID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to findAll service accounts")
+		return nil, GormDefaultError(err)
+	}
+
+	items := make([]dto.ServiceAccountDTO, len(accounts))
+	for i := range accounts {
+		items[i] = *mapper.MapToServiceAccountDTO(&accounts[i])
+	}
+	return &dto.PaginatedResponse[dto.ServiceAccountDTO]{
+		Items:    items,
+		PageInfo: pageInfo,
+	}, nil
+}
+
+// CreateKey emite uma nova chave para a conta. A resposta é a única vez em que a chave aparece.
+func (s *serviceAccountService) CreateKey(id int64, keyDTO dto.CreateAPIKeyDTO, companyGlobalID int64) (*dto.CreatedAPIKeyDTO, ErrorUtil) {
+	if keyDTO.ExpiresAt != nil && !keyDTO.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidAPIKeyExpiry
+	}
+	if _, errFind := s.find(id, dto.FullRead, companyGlobalID); errFind != nil {
+		return nil, errFind
+	}
+
+	key, secret, errKey := newAPIKey(id, keyDTO.ExpiresAt)
+	if errKey != nil {
+		return nil, errKey
+	}
+	if err := s.repo.CreateKey(key); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("service_account_id", id).
+			Msg("failed to create API key")
+		return nil, GormDefaultError(err)
+	}
+	log.Info().Int64("service_account_id", id).Str("prefix", key.Prefix).Msg("API key created")
+	return &dto.CreatedAPIKeyDTO{APIKeyDTO: mapper.MapToAPIKeyDTO(key), Key: secret}, nil
+}
+
+// RotateKey emite uma chave nova no lugar de keyID. A antiga continua valendo pelo período de
+// carência pedido e depois expira; sem carência, expira na hora.
+func (s *serviceAccountService) RotateKey(id int64, keyID int64, rotateDTO dto.RotateAPIKeyDTO, companyGlobalID int64) (*dto.CreatedAPIKeyDTO, ErrorUtil) {
+	now := time.Now()
+	if rotateDTO.ExpiresAt != nil && !rotateDTO.ExpiresAt.After(now) {
+		return nil, ErrInvalidAPIKeyExpiry
+	}
+	if _, errFind := s.find(id, dto.FullRead, companyGlobalID); errFind != nil {
+		return nil, errFind
+	}
+	old, err := s.repo.FindKey(id, keyID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("api_key_id", keyID).
+			Msg("failed to find API key")
+		return nil, GormDefaultError(err)
+	}
+	if old.RevokedAt != nil {
+		return nil, ErrAPIKeyRevoked
+	}
+
+	key, secret, errKey := newAPIKey(id, rotateDTO.ExpiresAt)
+	if errKey != nil {
+		return nil, errKey
+	}
+	oldExpiresAt := now.Add(time.Duration(rotateDTO.GracePeriodSeconds) * time.Second)
+	if err := s.repo.RotateKey(old, key, oldExpiresAt); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("api_key_id", keyID).
+			Msg("failed to rotate API key")
+		return nil, GormDefaultError(err)
+	}
+	log.Info().Int64("service_account_id", id).Str("old_prefix", old.Prefix).Str("prefix", key.Prefix).Msg("API key rotated")
+	return &dto.CreatedAPIKeyDTO{APIKeyDTO: mapper.MapToAPIKeyDTO(key), Key: secret}, nil
+}
+
+func (s *serviceAccountService) FindKeys(id int64, companyGlobalID int64) ([]dto.APIKeyDTO, ErrorUtil) {
+	if _, errFind := s.find(id, dto.FullRead, companyGlobalID); errFind != nil {
+		return nil, errFind
+	}
+	keys, err := s.repo.FindKeys(id)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("service_account_id", id).
+			Msg("failed to find API keys")
+		return nil, GormDefaultError(err)
+	}
+	items := make([]dto.APIKeyDTO, len(keys))
+	for i := range keys {
+		items[i] = mapper.MapToAPIKeyDTO(&keys[i])
+	}
+	return items, nil
+}
+
+func (s *serviceAccountService) RevokeKey(id int64, keyID int64, companyGlobalID int64) ErrorUtil {
+	if _, errFind := s.find(id, dto.FullRead, companyGlobalID); errFind != nil {
+		return errFind
+	}
+	if err := s.repo.RevokeKey(id, keyID, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("api_key_id", keyID).
+			Msg("failed to revoke API key")
+		return GormDefaultError(err)
+	}
+	log.Info().Int64("service_account_id", id).Int64("api_key_id", keyID).Msg("API key revoked")
+	return nil
+}
+
+// newAPIKey gera uma chave no formato gsk_<prefixo>_<segredo>. O prefixo (16 caracteres hex) é guardado
+// para localizar a chave; do segredo (32 bytes aleatórios) só fica o hash SHA-256 da chave inteira.
+// Com essa entropia o SHA-256 basta: não há senha fraca para um hash lento proteger.
+func newAPIKey(serviceAccountID int64, expiresAt *time.Time) (*model.APIKey, string, ErrorUtil) {
+	random := make([]byte, 8+32)
+	if _, err := rand.Read(random); err != nil {
+		log.Error().Err(err).Caller().Msg("failed to generate API key")
+		return nil, "", ErrInternalServer
+	}
+	prefix := APIKeyPrefix + hex.EncodeToString(random[:8])
+	secret := prefix + "_" + base64.RawURLEncoding.EncodeToString(random[8:])
+	return &model.APIKey{
+		ServiceAccountID: serviceAccountID,
+		Prefix:           prefix,
+		KeyHash:          hashAPIKey(secret),
+		ExpiresAt:        expiresAt,
+	}, secret, nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// apiKeyAuthenticator autentica as chaves de API das contas de serviço (Authorization: Bearer gsk_...).
+type apiKeyAuthenticator struct {
+	repo database.ServiceAccountRepositoryInterface
+}
+
+// NewAPIKeyAuthenticator cria o autenticador de chaves de API usado pelo middleware.Authenticate.
+func NewAPIKeyAuthenticator(repo database.ServiceAccountRepositoryInterface) auth.Authenticator {
+	return &apiKeyAuthenticator{repo: repo}
+}
+
+// Accepts implementa auth.Authenticator.
+func (a *apiKeyAuthenticator) Accepts(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// Authenticate implementa auth.Authenticator. A chave é recusada se não existir, se o hash não conferir,
+// se estiver revogada ou expirada, ou se a conta de serviço estiver desabilitada ou apagada.
+func (a *apiKeyAuthenticator) Authenticate(token string) (*auth.Principal, error) {
+	rest := strings.TrimPrefix(token, APIKeyPrefix)
+	separator := strings.IndexByte(rest, '_')
+	if separator <= 0 {
+		return nil, auth.ErrInvalidToken
+	}
+	prefix := APIKeyPrefix + rest[:separator]
+
+	key, err := a.repo.FindKeyByPrefix(prefix)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, auth.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(token)), []byte(key.KeyHash)) != 1 {
+		return nil, auth.ErrInvalidToken
+	}
+
+	now := time.Now()
+	account := key.ServiceAccount
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && !key.ExpiresAt.After(now)) || account.ID == 0 || !account.Enabled {
+		return nil, auth.ErrInvalidToken
+	}
+
+	if err := a.repo.TouchKey(key.ID, now, apiKeyTouchInterval); err != nil {
+		// Falhar ao registrar o uso não deve recusar uma chave válida.
+		log.Warn().Err(err).Str("prefix", key.Prefix).Msg("failed to record API key usage")
+	}
+
+	principal := auth.NewPrincipal(auth.PrincipalServiceAccount, account.ID, account.CompanyGlobalID, account.Name, account.Roles)
+	principal.APIKeyID = key.ID
+	return principal, nil
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"errors"
+	"go-sales/internal/database"
 	"net/http"
 	"reflect"
 
@@ -18,6 +19,8 @@ type AbstractError struct {
 	error          string
 	httpStatusCode int
 	code           string
+	// args preenchem os placeholders da mensagem traduzida (ver i18n.Message).
+	args []string
 }
 
 // NewError cria um novo erro customizado de forma segura.
@@ -25,12 +28,14 @@ type AbstractError struct {
 // - msg: mensagem de erro.
 // - statusCode: código HTTP (ex: http.StatusBadRequest).
 // - code: código único do erro (ex: "validated_dto_not_found").
+// - args: valores opcionais para os placeholders da mensagem traduzida.
 // Retorna: ErrorUtil (interface implementada por AbstractError).
-func NewError(msg string, statusCode int, code string) ErrorUtil {
+func NewError(msg string, statusCode int, code string, args ...string) ErrorUtil {
 	return &AbstractError{
 		error:          msg,
 		httpStatusCode: statusCode,
 		code:           code,
+		args:           args,
 	}
 }
 
@@ -43,6 +48,11 @@ func (e *AbstractError) Code() string {
 	return e.code
 }
 
+// MessageArgs devolve os argumentos usados para traduzir a mensagem do erro.
+func (e *AbstractError) MessageArgs() []string {
+	return e.args
+}
+
 var (
 	// ErrEmailInUse é retornado quando uma tentativa de criar um usuário com um email que já existe.
 	ErrEmailInUse = &AbstractError{
@@ -141,9 +151,493 @@ var (
 		httpStatusCode: http.StatusBadRequest,
 		code:           "permissions_not_found",
 	}
+
+	// ErrAddressIncomplete é retornado quando um PATCH cria o endereço sem todos os campos obrigatórios.
+	ErrAddressIncomplete = &AbstractError{
+		error:          "address is incomplete: street, city, state, postalCode and country are required",
+		httpStatusCode: http.StatusBadRequest,
+		code:           "address_incomplete",
+	}
+
+	// ErrVersionMismatch é retornado quando o If-Match não corresponde à versão atual do registro,
+	// ou seja, o registro foi alterado por outra requisição depois de ter sido lido.
+	ErrVersionMismatch = &AbstractError{
+		error:          "the resource was modified by another request; reload it and retry with the current ETag",
+		httpStatusCode: http.StatusPreconditionFailed,
+		code:           "version_mismatch",
+	}
+
+	// ErrPreconditionRequired é retornado quando um PUT, PATCH ou DELETE chega sem o cabeçalho If-Match.
+	ErrPreconditionRequired = &AbstractError{
+		error:          "the If-Match header is required for this request",
+		httpStatusCode: http.StatusPreconditionRequired,
+		code:           "precondition_required",
+	}
+
+	// ErrCompanyGlobalContactNotFound é retornado quando o contato não existe ou não pertence à empresa.
+	ErrCompanyGlobalContactNotFound = &AbstractError{
+		error:          "contact not found in this company global",
+		httpStatusCode: http.StatusNotFound,
+		code:           "company_global_contact_not_found",
+	}
+	// ErrCompanyGlobalAddressNotFound é retornado quando o endereço não existe ou não pertence à empresa.
+	ErrCompanyGlobalAddressNotFound = &AbstractError{
+		error:          "address not found in this company global",
+		httpStatusCode: http.StatusNotFound,
+		code:           "company_global_address_not_found",
+	}
+	// ErrCompanyGlobalContactRequired é retornado ao tentar remover o último contato da empresa.
+	ErrCompanyGlobalContactRequired = &AbstractError{
+		error:          "a company global must have at least one contact",
+		httpStatusCode: http.StatusConflict,
+		code:           "company_global_contact_required",
+	}
+	// ErrCompanyGlobalMainAddressRequired é retornado ao tentar remover o endereço principal (MAIN)
+	// ou trocar o seu tipo.
+	ErrCompanyGlobalMainAddressRequired = &AbstractError{
+		error:          "a company global must keep its MAIN address; update it instead",
+		httpStatusCode: http.StatusConflict,
+		code:           "company_global_main_address_required",
+	}
+	// ErrCompanyGlobalMainAddressExists é retornado ao tentar criar um segundo endereço principal (MAIN).
+	ErrCompanyGlobalMainAddressExists = &AbstractError{
+		error:          "the company global already has a MAIN address",
+		httpStatusCode: http.StatusConflict,
+		code:           "company_global_main_address_exists",
+	}
+
+	// ErrInvalidPostalCode é retornado quando um CEP não tem 8 dígitos.
+	ErrInvalidPostalCode = &AbstractError{
+		error:          "postal code must have 8 digits",
+		httpStatusCode: http.StatusBadRequest,
+		code:           "invalid_postal_code",
+	}
+	// ErrPostalCodeNotFound é retornado quando o CEP não existe na base local.
+	ErrPostalCodeNotFound = &AbstractError{
+		error:          "postal code not found",
+		httpStatusCode: http.StatusNotFound,
+		code:           "postal_code_not_found",
+	}
+	// ErrInvalidState é retornado quando o estado de um endereço no Brasil não é uma UF válida.
+	ErrInvalidState = &AbstractError{
+		error:          "state must be a valid UF (e.g. SP)",
+		httpStatusCode: http.StatusBadRequest,
+		code:           "invalid_state",
+	}
+	// ErrCityNotFound é retornado quando a cidade não está na lista de municípios do IBGE para a UF.
+	ErrCityNotFound = &AbstractError{
+		error:          "city not found in the IBGE municipality list for the given state",
+		httpStatusCode: http.StatusBadRequest,
+		code:           "city_not_found",
+	}
+
+	// ErrUnsupportedImportEntity é retornado quando POST /imports/{entity} recebe uma entidade sem importação.
+	ErrUnsupportedImportEntity = &AbstractError{
+		error:          "this entity cannot be imported; supported entities are users and permissions",
+		httpStatusCode: http.StatusBadRequest,
+		code:           "unsupported_import_entity",
+	}
+	// ErrInvalidImportFile é retornado quando a planilha enviada não pode ser lida ou não tem linhas.
+	ErrInvalidImportFile = &AbstractError{
+		error:          "the file must be a CSV or XLSX spreadsheet with a header row and up to 10000 data rows",
+		httpStatusCode: http.StatusBadRequest,
+		code:           "invalid_import_file",
+	}
+	// ErrImportJobNotFound é retornado quando a importação consultada não existe.
+	ErrImportJobNotFound = &AbstractError{
+		error:          "import job not found",
+		httpStatusCode: http.StatusNotFound,
+		code:           "import_job_not_found",
+	}
+	// ErrJobNotFound é retornado quando o job consultado em GET /jobs/:id não existe.
+	ErrJobNotFound = &AbstractError{
+		error:          "job not found",
+		httpStatusCode: http.StatusNotFound,
+		code:           "job_not_found",
+	}
+	// ErrInvalidCredentials é retornado quando o email ou a senha não conferem.
+	// Não diz qual dos dois errou, para não revelar quais emails estão cadastrados.
+	ErrInvalidCredentials = &AbstractError{
+		error:          "invalid email or password",
+		httpStatusCode: http.StatusUnauthorized,
+		code:           "invalid_credentials",
+	}
+	// ErrAccountLocked é retornado quando a conta está bloqueada por excesso de senhas erradas.
+	ErrAccountLocked = &AbstractError{
+		error:          "account temporarily locked after too many failed password attempts",
+		httpStatusCode: http.StatusLocked,
+		code:           "account_locked",
+	}
+	// ErrServiceAccountNotFound é retornado quando a conta de serviço não existe.
+	ErrServiceAccountNotFound = &AbstractError{
+		error:          "service account not found",
+		httpStatusCode: http.StatusNotFound,
+		code:           "service_account_not_found",
+	}
+	// ErrServiceAccountNameInUse é retornado quando a empresa já tem uma conta de serviço com o mesmo nome.
+	ErrServiceAccountNameInUse = &AbstractError{
+		error:          "service account name already in use",
+		httpStatusCode: http.StatusConflict,
+		code:           "service_account_name_in_use",
+	}
+	// ErrServiceAccountTenantMismatch é retornado ao criar uma conta de serviço em outra empresa que não
+	// a das credenciais.
+	ErrServiceAccountTenantMismatch = &AbstractError{
+		error:          "companyGlobalId does not match the tenant of the credentials",
+		httpStatusCode: http.StatusForbidden,
+		code:           "tenant_mismatch",
+		args:           []string{"companyGlobalId"},
+	}
+	// ErrAPIKeyNotFound é retornado quando a chave de API não existe ou é de outra conta de serviço.
+	ErrAPIKeyNotFound = &AbstractError{
+		error:          "API key not found",
+		httpStatusCode: http.StatusNotFound,
+		code:           "api_key_not_found",
+	}
+	// ErrAPIKeyRevoked é retornado ao rotacionar uma chave já revogada.
+	ErrAPIKeyRevoked = &AbstractError{
+		error:          "API key is revoked",
+		httpStatusCode: http.StatusConflict,
+		code:           "api_key_revoked",
+	}
+	// ErrInvalidAPIKeyExpiry é retornado quando a expiração pedida para a chave já passou.
+	ErrInvalidAPIKeyExpiry = &AbstractError{
+		error:          "expiresAt must be in the future",
+		httpStatusCode: http.StatusBadRequest,
+		code:           "invalid_api_key_expiry",
+	}
+	// ErrWebhookNotFound é retornado quando o endpoint de webhook não existe.
+	ErrWebhookNotFound = &AbstractError{
+		error:          "webhook not found",
+		httpStatusCode: http.StatusNotFound,
+		code:           "webhook_not_found",
+	}
+	// ErrWebhookDeliveryNotFound é retornado quando a entrega não existe ou não é do endpoint informado.
+	ErrWebhookDeliveryNotFound = &AbstractError{
+		error:          "webhook delivery not found",
+		httpStatusCode: http.StatusNotFound,
+		code:           "webhook_delivery_not_found",
+	}
+	// ErrWebhookDisabled é retornado ao testar (ping) ou reenviar para um endpoint desabilitado.
+	ErrWebhookDisabled = &AbstractError{
+		error:          "webhook is disabled",
+		httpStatusCode: http.StatusConflict,
+		code:           "webhook_disabled",
+	}
+	// ErrInvalidWebhookURL é retornado quando a URL do endpoint não é http(s) absoluta.
+	ErrInvalidWebhookURL = &AbstractError{
+		error:          "webhook url must be an absolute http or https URL",
+		httpStatusCode: http.StatusBadRequest,
+		code:           "invalid_webhook_url",
+	}
+	// ErrWebhookURLNotAllowed é retornado quando a URL do webhook aponta para localhost, para o serviço
+	// de metadados da nuvem ou para um IP de rede privada ou reservada.
+	ErrWebhookURLNotAllowed = &AbstractError{
+		error:          "webhook URL must not point to a private or reserved address",
+		httpStatusCode: http.StatusBadRequest,
+		code:           "webhook_url_not_allowed",
+	}
+	// ErrTaxRuleNotFound é retornado quando a regra de impostos não existe.
+	ErrTaxRuleNotFound = &AbstractError{
+		error:          "tax rule not found",
+		httpStatusCode: http.StatusNotFound,
+		code:           "tax_rule_not_found",
+	}
+	// ErrTaxOriginUFRequired é retornado na prévia de impostos sem originUf quando a empresa não tem
+	// endereço principal de onde tirar a UF de origem.
+	ErrTaxOriginUFRequired = &AbstractError{
+		error:          "originUf is required because the company global has no MAIN address",
+		httpStatusCode: http.StatusBadRequest,
+		code:           "tax_origin_uf_required",
+	}
+	// ErrInvalidPixKey é retornado quando a chave PIX da empresa não é CPF, CNPJ, e-mail, telefone
+	// (+55...) ou chave aleatória válidos.
+	ErrInvalidPixKey = &AbstractError{
+		error:          "invalid PIX key",
+		httpStatusCode: http.StatusBadRequest,
+		code:           "invalid_pix_key",
+	}
+	// ErrInstallmentNotFound é retornado quando a parcela não existe.
+	ErrInstallmentNotFound = &AbstractError{
+		error:          "installment not found",
+		httpStatusCode: http.StatusNotFound,
+		code:           "installment_not_found",
+	}
+	// ErrInvalidInstallmentAmount é retornado quando o valor da parcela não é maior que zero.
+	ErrInvalidInstallmentAmount = &AbstractError{
+		error:          "amount must be greater than zero",
+		httpStatusCode: http.StatusBadRequest,
+		code:           "invalid_installment_amount",
+	}
+	// ErrInstallmentNotOpen é retornado ao alterar, apagar ou cobrar uma parcela já paga ou cancelada.
+	ErrInstallmentNotOpen = &AbstractError{
+		error:          "installment is not open",
+		httpStatusCode: http.StatusConflict,
+		code:           "installment_not_open",
+	}
+	// ErrPixChargeNotFound é retornado quando a cobrança PIX não existe.
+	ErrPixChargeNotFound = &AbstractError{
+		error:          "PIX charge not found",
+		httpStatusCode: http.StatusNotFound,
+		code:           "pix_charge_not_found",
+	}
+	// ErrPixKeyNotConfigured é retornado ao gerar uma cobrança PIX para uma empresa sem pixKey.
+	ErrPixKeyNotConfigured = &AbstractError{
+		error:          "the company global has no PIX key",
+		httpStatusCode: http.StatusUnprocessableEntity,
+		code:           "pix_key_not_configured",
+	}
+	// ErrPixMerchantCityRequired é retornado quando a empresa não tem endereço principal, de onde vem a
+	// cidade do recebedor no BR Code.
+	ErrPixMerchantCityRequired = &AbstractError{
+		error:          "the company global needs a MAIN address to receive PIX payments",
+		httpStatusCode: http.StatusUnprocessableEntity,
+		code:           "pix_merchant_city_required",
+	}
+	// ErrPixProviderFailed é retornado quando o PSP não registra a cobrança de um QR Code dinâmico.
+	ErrPixProviderFailed = &AbstractError{
+		error:          "the PIX provider failed to create the charge",
+		httpStatusCode: http.StatusBadGateway,
+		code:           "pix_provider_failed",
+	}
+	// ErrInvalidPixNotification é retornado pelo webhook PIX quando a notificação não é autêntica ou
+	// não pode ser lida.
+	ErrInvalidPixNotification = &AbstractError{
+		error:          "invalid PIX notification",
+		httpStatusCode: http.StatusUnauthorized,
+		code:           "invalid_pix_notification",
+	}
+	// ErrBankAccountNotFound é retornado quando a conta bancária não existe.
+	ErrBankAccountNotFound = &AbstractError{
+		error:          "bank account not found",
+		httpStatusCode: http.StatusNotFound,
+		code:           "bank_account_not_found",
+	}
+	// ErrBankAccountCompanyMismatch é retornado ao emitir o boleto de uma parcela numa conta de outra
+	// empresa ou ao vincular um lançamento do extrato a uma parcela de outra empresa.
+	ErrBankAccountCompanyMismatch = &AbstractError{
+		error:          "the bank account belongs to another company global",
+		httpStatusCode: http.StatusUnprocessableEntity,
+		code:           "bank_account_company_mismatch",
+	}
+	// ErrUnsupportedBoletoBank é retornado ao emitir boletos numa conta de banco sem layout de cobrança.
+	ErrUnsupportedBoletoBank = &AbstractError{
+		error:          "boletos are not supported for this bank",
+		httpStatusCode: http.StatusUnprocessableEntity,
+		code:           "unsupported_boleto_bank",
+	}
+	// ErrBoletoNotFound é retornado quando o boleto não existe.
+	ErrBoletoNotFound = &AbstractError{
+		error:          "boleto not found",
+		httpStatusCode: http.StatusNotFound,
+		code:           "boleto_not_found",
+	}
+	// ErrBoletoAlreadyIssued é retornado ao emitir um boleto para uma parcela que já tem um boleto
+	// ativo (pendente, enviado ou registrado).
+	ErrBoletoAlreadyIssued = &AbstractError{
+		error:          "the installment already has an active boleto",
+		httpStatusCode: http.StatusConflict,
+		code:           "boleto_already_issued",
+	}
+	// ErrNoPendingBoletos é retornado ao gerar uma remessa para uma conta sem boletos pendentes.
+	ErrNoPendingBoletos = &AbstractError{
+		error:          "the bank account has no pending boletos",
+		httpStatusCode: http.StatusUnprocessableEntity,
+		code:           "no_pending_boletos",
+	}
+	// ErrBoletoRemittanceNotFound é retornado quando o arquivo de remessa não existe.
+	ErrBoletoRemittanceNotFound = &AbstractError{
+		error:          "remittance file not found",
+		httpStatusCode: http.StatusNotFound,
+		code:           "boleto_remittance_not_found",
+	}
+	// ErrInvalidReturnFile é retornado quando o arquivo enviado não é um retorno CNAB 240 ou 400.
+	ErrInvalidReturnFile = &AbstractError{
+		error:          "the file is not a CNAB 240 or 400 return file",
+		httpStatusCode: http.StatusBadRequest,
+		code:           "invalid_return_file",
+	}
+	// ErrReturnBankMismatch é retornado quando o retorno é de um banco diferente do da conta.
+	ErrReturnBankMismatch = &AbstractError{
+		error:          "the return file is from another bank",
+		httpStatusCode: http.StatusUnprocessableEntity,
+		code:           "return_bank_mismatch",
+	}
+	// ErrBoletoReturnNotFound é retornado quando o arquivo de retorno processado não existe.
+	ErrBoletoReturnNotFound = &AbstractError{
+		error:          "return file not found",
+		httpStatusCode: http.StatusNotFound,
+		code:           "boleto_return_not_found",
+	}
+	// ErrInvalidStatementFile é retornado quando o arquivo enviado não é um extrato OFX ou CSV legível.
+	ErrInvalidStatementFile = &AbstractError{
+		error:          "the file is not an OFX or CSV bank statement with transactions",
+		httpStatusCode: http.StatusBadRequest,
+		code:           "invalid_statement_file",
+	}
+	// ErrStatementLineNotFound é retornado quando o lançamento do extrato não existe.
+	ErrStatementLineNotFound = &AbstractError{
+		error:          "statement line not found",
+		httpStatusCode: http.StatusNotFound,
+		code:           "statement_line_not_found",
+	}
+	// ErrStatementLineNotSuggested é retornado ao confirmar ou rejeitar um lançamento sem parcela
+	// sugerida.
+	ErrStatementLineNotSuggested = &AbstractError{
+		error:          "the statement line has no suggested installment",
+		httpStatusCode: http.StatusConflict,
+		code:           "statement_line_not_suggested",
+	}
+	// ErrStatementLineNotLinkable é retornado ao vincular uma parcela a um lançamento já conciliado ou
+	// a um débito.
+	ErrStatementLineNotLinkable = &AbstractError{
+		error:          "only unreconciled credit lines can be linked to an installment",
+		httpStatusCode: http.StatusConflict,
+		code:           "statement_line_not_linkable",
+	}
+	// ErrInstallmentAlreadyMatched é retornado ao vincular uma parcela já sugerida ou conciliada em
+	// outro lançamento.
+	ErrInstallmentAlreadyMatched = &AbstractError{
+		error:          "the installment is already matched to another statement line",
+		httpStatusCode: http.StatusConflict,
+		code:           "installment_already_matched",
+	}
+	// ErrInstallmentCancelled é retornado ao conciliar um lançamento com uma parcela cancelada.
+	ErrInstallmentCancelled = &AbstractError{
+		error:          "the installment is cancelled",
+		httpStatusCode: http.StatusConflict,
+		code:           "installment_cancelled",
+	}
+	// ErrUserNotFound é retornado quando o usuário informado (ex: o vendedor de uma comissão) não existe.
+	ErrUserNotFound = &AbstractError{
+		error:          "user not found",
+		httpStatusCode: http.StatusNotFound,
+		code:           "user_not_found",
+	}
+	// ErrCommissionPlanNotFound é retornado quando o plano de comissão não existe.
+	ErrCommissionPlanNotFound = &AbstractError{
+		error:          "commission plan not found",
+		httpStatusCode: http.StatusNotFound,
+		code:           "commission_plan_not_found",
+	}
+	// ErrCommissionPlanNameInUse é retornado quando a empresa já tem um plano com o mesmo nome.
+	ErrCommissionPlanNameInUse = &AbstractError{
+		error:          "commission plan name already in use",
+		httpStatusCode: http.StatusConflict,
+		code:           "commission_plan_name_in_use",
+	}
+	// ErrCommissionAssignmentNotFound é retornado quando a atribuição do plano não existe.
+	ErrCommissionAssignmentNotFound = &AbstractError{
+		error:          "commission plan assignment not found",
+		httpStatusCode: http.StatusNotFound,
+		code:           "commission_assignment_not_found",
+	}
+	// ErrCommissionAssignmentTarget é retornado quando a atribuição não tem exatamente um entre userId
+	// e roleId.
+	ErrCommissionAssignmentTarget = &AbstractError{
+		error:          "exactly one of userId or roleId is required",
+		httpStatusCode: http.StatusBadRequest,
+		code:           "commission_assignment_target",
+	}
+	// ErrCommissionAssignmentValidity é retornado quando validTo é anterior a validFrom.
+	ErrCommissionAssignmentValidity = &AbstractError{
+		error:          "validTo cannot be before validFrom",
+		httpStatusCode: http.StatusBadRequest,
+		code:           "commission_assignment_validity",
+	}
+	// ErrCommissionAssignmentOverlap é retornado quando o usuário ou a role já tem um plano vigente em
+	// parte do período.
+	ErrCommissionAssignmentOverlap = &AbstractError{
+		error:          "the user or role already has a commission plan in this period",
+		httpStatusCode: http.StatusConflict,
+		code:           "commission_assignment_overlap",
+	}
+	// ErrCommissionCompanyMismatch é retornado quando o vendedor ou a role é de outra empresa.
+	ErrCommissionCompanyMismatch = &AbstractError{
+		error:          "the user or role belongs to another company global",
+		httpStatusCode: http.StatusUnprocessableEntity,
+		code:           "commission_company_mismatch",
+	}
+	// ErrCommissionPlanNotAssigned é retornado ao lançar uma venda de um vendedor sem plano vigente na
+	// data do documento.
+	ErrCommissionPlanNotAssigned = &AbstractError{
+		error:          "no commission plan is assigned to the user on the document date",
+		httpStatusCode: http.StatusUnprocessableEntity,
+		code:           "commission_plan_not_assigned",
+	}
+	// ErrCommissionEntryNotFound é retornado quando o lançamento de comissão não existe.
+	ErrCommissionEntryNotFound = &AbstractError{
+		error:          "commission entry not found",
+		httpStatusCode: http.StatusNotFound,
+		code:           "commission_entry_not_found",
+	}
+	// ErrCommissionDocumentExists é retornado ao lançar de novo a comissão de uma venda.
+	ErrCommissionDocumentExists = &AbstractError{
+		error:          "the document already has a commission entry",
+		httpStatusCode: http.StatusConflict,
+		code:           "commission_document_exists",
+	}
+	// ErrCommissionPeriodClosed é retornado ao lançar comissões ou aprovar o extrato de um mês já
+	// aprovado para o vendedor.
+	ErrCommissionPeriodClosed = &AbstractError{
+		error:          "the commission statement of this period is already approved",
+		httpStatusCode: http.StatusConflict,
+		code:           "commission_period_closed",
+	}
+	// ErrCommissionPeriodNotEnded é retornado ao aprovar o extrato de um mês que ainda não terminou.
+	ErrCommissionPeriodNotEnded = &AbstractError{
+		error:          "the commission period has not ended yet",
+		httpStatusCode: http.StatusConflict,
+		code:           "commission_period_not_ended",
+	}
+	// ErrCommissionApproverRequired é retornado ao aprovar um extrato sem saber quem aprova.
+	ErrCommissionApproverRequired = &AbstractError{
+		error:          "the commission statement approver is required",
+		httpStatusCode: http.StatusUnauthorized,
+		code:           "commission_approver_required",
+	}
+	// ErrInvalidCommissionPeriod é retornado quando o mês do extrato não está no formato YYYY-MM.
+	ErrInvalidCommissionPeriod = &AbstractError{
+		error:          "period must be a month in the format YYYY-MM",
+		httpStatusCode: http.StatusBadRequest,
+		code:           "invalid_commission_period",
+	}
+	// ErrCommissionEntryNotReversible é retornado ao estornar um lançamento que não é de venda.
+	ErrCommissionEntryNotReversible = &AbstractError{
+		error:          "only sale commission entries can be reversed",
+		httpStatusCode: http.StatusConflict,
+		code:           "commission_entry_not_reversible",
+	}
+	// ErrCommissionAlreadyReversed é retornado ao estornar uma venda já estornada por inteiro.
+	ErrCommissionAlreadyReversed = &AbstractError{
+		error:          "the sale commission is already fully reversed",
+		httpStatusCode: http.StatusConflict,
+		code:           "commission_already_reversed",
+	}
+	// ErrCommissionReversalExceedsSale é retornado quando a base devolvida passa do saldo da venda ou
+	// não é positiva.
+	ErrCommissionReversalExceedsSale = &AbstractError{
+		error:          "baseAmount must be positive and cannot exceed the sale base not yet reversed",
+		httpStatusCode: http.StatusUnprocessableEntity,
+		code:           "commission_reversal_exceeds_sale",
+	}
+	// ErrCommissionReversalBeforeSale é retornado quando a data do estorno é anterior à da venda.
+	ErrCommissionReversalBeforeSale = &AbstractError{
+		error:          "the reversal date cannot be before the sale date",
+		httpStatusCode: http.StatusUnprocessableEntity,
+		code:           "commission_reversal_before_sale",
+	}
 )
 
 func GormDefaultError(err error) ErrorUtil {
+	// Filtros e ordenações inválidos vindos da query string são erro do cliente.
+	var filterErr *database.FilterError
+	if errors.As(err, &filterErr) {
+		return NewError(filterErr.Error(), http.StatusBadRequest, "invalid_filter", filterErr.Param, filterErr.Reason)
+	}
+	if errors.Is(err, database.ErrVersionConflict) {
+		return ErrVersionMismatch
+	}
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return ErrNotFound
 	}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"errors"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/fiscal/tax"
+	"go-sales/internal/mapper"
+	"go-sales/internal/model"
+	"go-sales/pkg/util"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// TaxServiceInterface define a gestão das regras de impostos e a prévia dos impostos de um carrinho.
+type TaxServiceInterface interface {
+	Create(ruleDTO dto.CreateTaxRuleDTO) (*dto.TaxRuleDTO, ErrorUtil)
+	Patch(ruleDTO dto.UpdateTaxRuleDTO, id int64, version int64) (*dto.TaxRuleDTO, ErrorUtil)
+	Delete(id int64, version int64) ErrorUtil
+	FindByID(id int64, opts dto.ReadOptions) (*dto.TaxRuleDTO, ErrorUtil)
+	FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.TaxRuleDTO], ErrorUtil)
+
+	// Preview calcula os impostos do carrinho com as regras habilitadas e o regime da empresa.
+	Preview(previewDTO dto.TaxPreviewDTO) (*dto.TaxPreviewResultDTO, ErrorUtil)
+}
+
+// taxService é a implementação concreta.
+type taxService struct {
+	repo              database.TaxRuleRepositoryInterface
+	repoCompanyGlobal database.CompanyGlobalRepositoryInterface
+}
+
+// NewTaxService cria uma nova instância do serviço de impostos.
+func NewTaxService(repo database.TaxRuleRepositoryInterface, repoCompanyGlobal database.CompanyGlobalRepositoryInterface) TaxServiceInterface {
+	return &taxService{
+		repo:              repo,
+		repoCompanyGlobal: repoCompanyGlobal,
+	}
+}
+
+// validateTaxRule confere a regra completa (depois do merge, no PATCH) com tax.Rule.Validate.
+func validateTaxRule(rule *model.TaxRule) ErrorUtil {
+	taxRule := mapper.MapToTaxRule(rule)
+	if problems := taxRule.Validate(); len(problems) > 0 {
+		return NewError("invalid tax rule: "+strings.Join(problems, "; "), http.StatusBadRequest, "invalid_tax_rule", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func (s *taxService) Create(ruleDTO dto.CreateTaxRuleDTO) (*dto.TaxRuleDTO, ErrorUtil) {
+	companyExists, errCompanyExists := CheckCompanyGlobalExists(s.repoCompanyGlobal, ruleDTO.CompanyGlobalID.Int64(), false)
+	if errCompanyExists != nil {
+		return nil, errCompanyExists
+	}
+	if !companyExists {
+		return nil, ErrCompanyGlobalNotFound
+	}
+
+	rule := mapper.MapCreateToTaxRule(&ruleDTO)
+	if errValidate := validateTaxRule(rule); errValidate != nil {
+		return nil, errValidate
+	}
+	if err := s.repo.Create(rule); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Str("company_global_id", ruleDTO.CompanyGlobalID.String()).
+			Msg("failed to create tax rule")
+		return nil, GormDefaultError(err)
+	}
+	log.Info().Int64("tax_rule_id", rule.ID).Str("company_global_id", ruleDTO.CompanyGlobalID.String()).Msg("tax rule created")
+	return mapper.MapToTaxRuleDTO(rule), nil
+}
+
+func (s *taxService) Patch(ruleDTO dto.UpdateTaxRuleDTO, id int64, version int64) (*dto.TaxRuleDTO, ErrorUtil) {
+	existing, errFind := s.find(id, dto.FullRead)
+	if errFind != nil {
+		return nil, errFind
+	}
+
+	version, errVersion := CheckVersion(version, existing.Version)
+	if errVersion != nil {
+		return nil, errVersion
+	}
+
+	if errValidate := validateTaxRule(mapper.MergeTaxRule(&ruleDTO, existing)); errValidate != nil {
+		return nil, errValidate
+	}
+
+	columns := mapper.MapUpdateTaxRuleToColumns(&ruleDTO)
+	if err := s.repo.Patch(id, version, columns); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("tax_rule_id", id).
+			Msg("failed to patch tax rule")
+		return nil, GormDefaultError(err)
+	}
+	return s.FindByID(id, dto.FullRead)
+}
+
+func (s *taxService) Delete(id int64, version int64) ErrorUtil {
+	if err := s.repo.Delete(id, version); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTaxRuleNotFound
+		}
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("tax_rule_id", id).
+			Msg("failed to delete tax rule")
+		return GormDefaultError(err)
+	}
+	return nil
+}
+
+func (s *taxService) FindByID(id int64, opts dto.ReadOptions) (*dto.TaxRuleDTO, ErrorUtil) {
+	rule, errFind := s.find(id, opts)
+	if errFind != nil {
+		return nil, errFind
+	}
+	return mapper.MapToTaxRuleDTO(rule), nil
+}
+
+func (s *taxService) find(id int64, opts dto.ReadOptions) (*model.TaxRule, ErrorUtil) {
+	rule, err := s.repo.FindByID(id, opts)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTaxRuleNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("tax_rule_id", id).
+			Msg("failed to find tax rule")
+		return nil, GormDefaultError(err)
+	}
+	return rule, nil
+}
+
+func (s *taxService) FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.TaxRuleDTO], ErrorUtil) {
+	companyExists, errCompanyExists := CheckCompanyGlobalExists(s.repoCompanyGlobal, companyGlobalID, false)
+	if errCompanyExists != nil {
+		log.Error().
+			Err(errCompanyExists).
+			Caller().
+			Str("company_global_id", strconv.FormatInt(companyGlobalID, 10)).
+			Msg("failed to check if company global exists")
+		return nil, errCompanyExists
+	}
+	if !companyExists {
+		return nil, ErrCompanyGlobalNotFound
+	}
+
+	rules, pageInfo, err := s.repo.FindAll(filters, page, opts, companyGlobalID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to findAll tax rules")
+		return nil, GormDefaultError(err)
+	}
+
+	items := make([]dto.TaxRuleDTO, len(rules))
+	for i := range rules {
+		items[i] = *mapper.MapToTaxRuleDTO(&rules[i])
+	}
+	return &dto.PaginatedResponse[dto.TaxRuleDTO]{
+		Items:    items,
+		PageInfo: pageInfo,
+	}, nil
+}
+
+// Preview calcula os impostos do carrinho sem gravar nada. A UF de origem, se omitida, é a do
+// endereço principal da empresa, e o regime do emitente é o cadastrado na empresa.
+func (s *taxService) Preview(previewDTO dto.TaxPreviewDTO) (*dto.TaxPreviewResultDTO, ErrorUtil) {
+	companyGlobalID := previewDTO.CompanyGlobalID.Int64()
+	company, err := s.repoCompanyGlobal.FindByID(companyGlobalID, false, dto.FullRead)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCompanyGlobalNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Str("company_global_id", previewDTO.CompanyGlobalID.String()).
+			Msg("failed to find company global")
+		return nil, GormDefaultError(err)
+	}
+
+	op := tax.Operation{
+		DestinationUF:  strings.ToUpper(previewDTO.DestinationUF),
+		IssuerRegime:   company.TaxRegime,
+		CustomerRegime: previewDTO.CustomerRegime,
+	}
+	if previewDTO.OriginUF != nil {
+		op.OriginUF = strings.ToUpper(*previewDTO.OriginUF)
+	} else if main := company.MainAddress(); main != nil {
+		op.OriginUF = main.State
+	} else {
+		return nil, ErrTaxOriginUFRequired
+	}
+	if !util.IsValidUF(op.OriginUF) || !util.IsValidUF(op.DestinationUF) {
+		return nil, ErrInvalidState
+	}
+
+	models, err := s.repo.FindEnabledByCompany(companyGlobalID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Str("company_global_id", previewDTO.CompanyGlobalID.String()).
+			Msg("failed to find tax rules")
+		return nil, GormDefaultError(err)
+	}
+	rules := make([]tax.Rule, len(models))
+	for i := range models {
+		rules[i] = mapper.MapToTaxRule(&models[i])
+	}
+
+	result, err := tax.Calculate(op, mapper.MapToTaxLines(previewDTO.Items), rules)
+	if err != nil {
+		var lineErr *tax.LineError
+		if errors.As(err, &lineErr) {
+			return nil, NewError(lineErr.Error(), http.StatusUnprocessableEntity, "tax_calculation_failed", strconv.Itoa(lineErr.Line), lineErr.Problem)
+		}
+		log.Error().Err(err).Caller().Msg("failed to calculate taxes")
+		return nil, ErrInternalServer
+	}
+	return mapper.MapToTaxPreviewResultDTO(op, result), nil
+}
@@ -7,7 +7,7 @@ import (
 	"go-sales/internal/dto"
 	"go-sales/internal/mapper"
 	"go-sales/internal/model"
-	"math"
+	"go-sales/pkg/util"
 	"strconv"
 
 	"github.com/rs/zerolog/log"
@@ -17,10 +17,12 @@ import (
 
 type UserServiceInterface interface {
 	Create(userDTO dto.CreateUserDTO) (*dto.UserDTO, ErrorUtil)
-	Update(userDTO dto.CreateUserDTO, userID string) (*dto.UserDTO, ErrorUtil)
-	Delete(userID string) ErrorUtil
-	FindByID(userID string) (*dto.UserDTO, ErrorUtil)
-	FindAll(filters map[string][]string, page, pageSize int, companyGlobalID int64) (*dto.PaginatedResponse[dto.UserDTO], ErrorUtil)
+	Update(userDTO dto.CreateUserDTO, userID string, version int64) (*dto.UserDTO, ErrorUtil)
+	Patch(userDTO dto.UpdateUserDTO, userID string, version int64) (*dto.UserDTO, ErrorUtil)
+	Delete(userID string, version int64) ErrorUtil
+	FindByID(userID string, opts dto.ReadOptions) (*dto.UserDTO, ErrorUtil)
+	FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.UserDTO], ErrorUtil)
+	Export(filters map[string][]string, opts dto.ReadOptions, companyGlobalID int64, fn func(*dto.UserDTO) error) ErrorUtil
 }
 
 // userService é a implementação concreta.
@@ -40,10 +42,10 @@ func NewUserService(repo database.UserRepositoryInterface, repoCompany database.
 func (s *userService) Create(userDTO dto.CreateUserDTO) (*dto.UserDTO, ErrorUtil) {
 	log.Debug().Msgf("Creating user: %+v", userDTO)
 
-	companyGlobalExists, errCompanyGlobalExists := CheckCompanyGlobalExists(s.repoCompany, userDTO.CompanyGlobalID, false)
+	companyGlobalExists, errCompanyGlobalExists := CheckCompanyGlobalExists(s.repoCompany, userDTO.CompanyGlobalID.Int64(), false)
 	if errCompanyGlobalExists != nil {
 		log.Error().
-			Err(errCompanyGlobalExists).Str("company_global_id", strconv.FormatInt(userDTO.CompanyGlobalID, 10)).
+			Err(errCompanyGlobalExists).Str("company_global_id", userDTO.CompanyGlobalID.String()).
 			Msg("failed to check if company global exists")
 		return nil, errCompanyGlobalExists
 	}
@@ -51,16 +53,16 @@ func (s *userService) Create(userDTO dto.CreateUserDTO) (*dto.UserDTO, ErrorUtil
 		log.Error().
 			Err(errCompanyGlobalExists).
 			Caller().
-			Str("company_global_id", strconv.FormatInt(userDTO.CompanyGlobalID, 10)).
+			Str("company_global_id", userDTO.CompanyGlobalID.String()).
 			Msg("failed to find existing company global")
 		return nil, ErrCompanyGlobalNotFound
 	}
 
-	userEmailExists, errUserEmailExists := CheckUserEmailExists(s.repo, userDTO.Email, userDTO.CompanyGlobalID, false)
+	userEmailExists, errUserEmailExists := CheckUserEmailExists(s.repo, userDTO.Email, userDTO.CompanyGlobalID.Int64(), false)
 	if errUserEmailExists != nil {
 		log.Error().
 			Err(errUserEmailExists).Str("email", userDTO.Email).
-			Str("company_global_id", strconv.FormatInt(userDTO.CompanyGlobalID, 10)).
+			Str("company_global_id", userDTO.CompanyGlobalID.String()).
 			Msg("failed to check if user email exists")
 		return nil, errUserEmailExists
 	}
@@ -69,7 +71,7 @@ func (s *userService) Create(userDTO dto.CreateUserDTO) (*dto.UserDTO, ErrorUtil
 			Err(ErrEmailInUse).
 			Caller().
 			Str("email", userDTO.Email).
-			Str("company_global_id", strconv.FormatInt(userDTO.CompanyGlobalID, 10)).
+			Str("company_global_id", userDTO.CompanyGlobalID.String()).
 			Msg("email already in use")
 		return nil, ErrEmailInUse
 	}
@@ -82,16 +84,16 @@ func (s *userService) Create(userDTO dto.CreateUserDTO) (*dto.UserDTO, ErrorUtil
 		return nil, ErrInternalServer
 	}
 
-	existingCompanyGlobal, err := s.repoCompany.FindByID(userDTO.CompanyGlobalID, false)
+	existingCompanyGlobal, err := s.repoCompany.FindByID(userDTO.CompanyGlobalID.Int64(), false, dto.FullRead)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		log.Error().
 			Err(err).
-			Str("company_global_id", strconv.FormatInt(userDTO.CompanyGlobalID, 10)).
+			Str("company_global_id", userDTO.CompanyGlobalID.String()).
 			Msg("failed to find existing company global")
 		return nil, ErrDatabase
 	}
 
-	roles, err := s.repoRole.FindAllByIDs(userDTO.RoleIDs)
+	roles, err := s.repoRole.FindAllByIDs(util.FromSnowflakeIDs(userDTO.RoleIDs))
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -110,7 +112,7 @@ func (s *userService) Create(userDTO dto.CreateUserDTO) (*dto.UserDTO, ErrorUtil
 
 	newUser := mapper.MapCreateUserDTOToUser(&userDTO, string(hashedPassword), existingCompanyGlobal, roles)
 
-	if err := s.repo.Create(newUser); err != nil {
+	if err := s.repo.Create(newUser, userCreated); err != nil {
 		log.Error().
 			Err(err).
 			Caller().
@@ -123,13 +125,13 @@ func (s *userService) Create(userDTO dto.CreateUserDTO) (*dto.UserDTO, ErrorUtil
 	return mapper.MapToUserDTO(newUser), nil
 }
 
-func (s *userService) Update(userDTO dto.CreateUserDTO, userID string) (*dto.UserDTO, ErrorUtil) {
+func (s *userService) Update(userDTO dto.CreateUserDTO, userID string, version int64) (*dto.UserDTO, ErrorUtil) {
 	log.Debug().Msgf("Updating user: %+v", userDTO)
 
-	companyGlobalExists, errCompanyGlobalExists := CheckCompanyGlobalExists(s.repoCompany, userDTO.CompanyGlobalID, false)
+	companyGlobalExists, errCompanyGlobalExists := CheckCompanyGlobalExists(s.repoCompany, userDTO.CompanyGlobalID.Int64(), false)
 	if errCompanyGlobalExists != nil {
 		log.Error().
-			Err(errCompanyGlobalExists).Str("company_global_id", strconv.FormatInt(userDTO.CompanyGlobalID, 10)).
+			Err(errCompanyGlobalExists).Str("company_global_id", userDTO.CompanyGlobalID.String()).
 			Msg("failed to check if company global exists")
 		return nil, errCompanyGlobalExists
 	}
@@ -137,12 +139,12 @@ func (s *userService) Update(userDTO dto.CreateUserDTO, userID string) (*dto.Use
 		log.Error().
 			Err(errCompanyGlobalExists).
 			Caller().
-			Str("company_global_id", strconv.FormatInt(userDTO.CompanyGlobalID, 10)).
+			Str("company_global_id", userDTO.CompanyGlobalID.String()).
 			Msg("failed to find existing company global")
 		return nil, ErrCompanyGlobalNotFound
 	}
 
-	existingUser, err := s.repo.FindByID(userID)
+	existingUser, err := s.repo.FindByID(userID, dto.FullRead)
 	if err != nil {
 		// AQUI ESTÁ A TRADUÇÃO DO ERRO!
 		// Se o repositório retornou "record not found", o serviço retorna "ErrNotFound".
@@ -173,6 +175,12 @@ func (s *userService) Update(userDTO dto.CreateUserDTO, userID string) (*dto.Use
 		return nil, ErrNotFound
 	}
 
+	// Verificar se a versão enviada no If-Match ainda é a atual.
+	version, errVersion := CheckVersion(version, existingUser.Version)
+	if errVersion != nil {
+		return nil, errVersion
+	}
+
 	// 2. Verificar se o novo email já está em uso por OUTRO usuário.
 	if userDTO.Email != existingUser.Email {
 		log.Debug().Msgf("Checking if email is already in use: %s", userDTO.Email)
@@ -199,7 +207,10 @@ func (s *userService) Update(userDTO dto.CreateUserDTO, userID string) (*dto.Use
 	existingUser.Email = userDTO.Email // Atualiza o email
 
 	// 4. Chamar o repositório para persistir as alterações.
-	if err := s.repo.Update(existingUser); err != nil {
+	if err := s.repo.Update(existingUser, version, userUpdated); err != nil {
+		if errors.Is(err, database.ErrVersionConflict) {
+			return nil, ErrVersionMismatch
+		}
 		return nil, ErrDatabase
 	}
 
@@ -208,27 +219,107 @@ func (s *userService) Update(userDTO dto.CreateUserDTO, userID string) (*dto.Use
 	return mapper.MapToUserDTO(existingUser), nil
 }
 
-func (s *userService) Delete(id string) ErrorUtil {
+// Patch aplica um JSON Merge Patch no usuário: apenas os campos enviados são alterados.
+// roleIds, quando enviado, substitui as roles do usuário.
+func (s *userService) Patch(userDTO dto.UpdateUserDTO, userID string, version int64) (*dto.UserDTO, ErrorUtil) {
+	existingUser, err := s.repo.FindByID(userID, dto.FullRead)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Str("user_id", userID).
+			Msg("failed to find existing user")
+		return nil, GormDefaultError(err)
+	}
+
+	version, errVersion := CheckVersion(version, existingUser.Version)
+	if errVersion != nil {
+		return nil, errVersion
+	}
+
+	if userDTO.Email != nil && *userDTO.Email != existingUser.Email {
+		userEmailExists, errUserEmailExists := CheckUserEmailExists(s.repo, *userDTO.Email, existingUser.CompanyGlobalID, false)
+		if errUserEmailExists != nil {
+			return nil, errUserEmailExists
+		}
+		if userEmailExists {
+			log.Error().
+				Caller().
+				Str("email", *userDTO.Email).
+				Msg("email already in use")
+			return nil, ErrEmailInUse
+		}
+	}
+
+	columns := mapper.MapUpdateUserToColumns(&userDTO)
+	if userDTO.Password != nil {
+		hashedPassword, errBcryptPass := bcrypt.GenerateFromPassword([]byte(*userDTO.Password), bcrypt.DefaultCost)
+		if errBcryptPass != nil {
+			log.Error().
+				Err(errBcryptPass).
+				Str("user_id", userID).
+				Msg("failed to hash password")
+			return nil, ErrInternalServer
+		}
+		columns["password_hash"] = string(hashedPassword)
+	}
+
+	var roles []*model.Role
+	if userDTO.RoleIDs != nil {
+		roles, err = s.repoRole.FindAllByIDs(util.FromSnowflakeIDs(userDTO.RoleIDs))
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("role_ids", fmt.Sprintf("%v", userDTO.RoleIDs)).
+				Msg("failed to find roles by IDs")
+			return nil, GormDefaultError(err)
+		}
+		if len(roles) != len(userDTO.RoleIDs) {
+			return nil, ErrRoleNotFound
+		}
+	}
+
+	if err := s.repo.Patch(existingUser.ID, version, columns, roles, userUpdated); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Str("user_id", userID).
+			Msg("failed to patch user")
+		return nil, GormDefaultError(err)
+	}
+
+	return s.FindByID(userID, dto.FullRead)
+}
+
+func (s *userService) Delete(id string, version int64) ErrorUtil {
 
-	err := s.repo.Delete(id)
+	err := s.repo.Delete(id, version, userDeleted)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return ErrNotFound // Retorne um erro específico se o usuário não for encontrado.
 		}
+		if errors.Is(err, database.ErrVersionConflict) {
+			return ErrVersionMismatch // O usuário foi alterado depois do GET que gerou o ETag.
+		}
 		return ErrDatabase // Retorne outros erros do banco de dados.
 	}
 	return nil // Retorno nil indica sucesso na exclusão.
 }
 
-func (s *userService) FindByID(userID string) (*dto.UserDTO, ErrorUtil) {
-	user, err := s.repo.FindByID(userID)
+func (s *userService) FindByID(userID string, opts dto.ReadOptions) (*dto.UserDTO, ErrorUtil) {
+	user, err := s.repo.FindByID(userID, opts)
 	if err != nil {
-		return nil, ErrDatabase
+		log.Error().
+			Err(err).
+			Caller().
+			Str("user_id", userID).
+			Msg("failed to find user by ID")
+		return nil, GormDefaultError(err)
 	}
 	return mapper.MapToUserDTO(user), nil
 }
 
-func (s *userService) FindAll(filters map[string][]string, page, pageSize int, companyGlobalID int64) (*dto.PaginatedResponse[dto.UserDTO], ErrorUtil) {
+func (s *userService) FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.UserDTO], ErrorUtil) {
 
 	companyExists, errCompanyExists := CheckCompanyGlobalExists(s.repoCompany, companyGlobalID, false)
 	if errCompanyExists != nil {
@@ -244,9 +335,13 @@ func (s *userService) FindAll(filters map[string][]string, page, pageSize int, c
 	}
 
 	// 1. Chamar o repositório para buscar os usuários.
-	users, totalItems, err := s.repo.FindAll(filters, page, pageSize)
+	users, pageInfo, err := s.repo.FindAll(filters, page, opts, companyGlobalID)
 	if err != nil {
-		return nil, ErrDatabase
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to findAll user")
+		return nil, GormDefaultError(err)
 	}
 
 	userPtrs := make([]*model.User, len(users))
@@ -254,18 +349,37 @@ func (s *userService) FindAll(filters map[string][]string, page, pageSize int, c
 		userPtrs[i] = &users[i]
 	}
 	userDTOs := mapper.MapToUserDTOs(userPtrs)
-	totalPages := 0
-	if pageSize > 0 {
-		totalPages = int(math.Ceil(float64(totalItems) / float64(pageSize)))
-	}
 
 	return &dto.PaginatedResponse[dto.UserDTO]{
-		Items: *userDTOs,
-		PageInfo: dto.PageInfo{
-			Page:       page,
-			PageSize:   pageSize,
-			TotalItems: totalItems,
-			TotalPages: totalPages,
-		},
+		Items:    *userDTOs,
+		PageInfo: pageInfo,
 	}, nil
 }
+
+// Export percorre todos os usuários que atendem aos filtros, sem paginação, e entrega cada um como DTO para fn.
+func (s *userService) Export(filters map[string][]string, opts dto.ReadOptions, companyGlobalID int64, fn func(*dto.UserDTO) error) ErrorUtil {
+	companyExists, errCompanyExists := CheckCompanyGlobalExists(s.repoCompany, companyGlobalID, false)
+	if errCompanyExists != nil {
+		log.Error().
+			Err(errCompanyExists).
+			Caller().
+			Str("company_global_id", strconv.FormatInt(companyGlobalID, 10)).
+			Msg("failed to check if company global exists")
+		return errCompanyExists
+	}
+	if !companyExists {
+		return ErrCompanyGlobalNotFound
+	}
+
+	err := s.repo.Stream(filters, opts, companyGlobalID, func(user *model.User) error {
+		return fn(mapper.MapToUserDTO(user))
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to export users")
+		return GormDefaultError(err)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"go-sales/internal/database"
 	"strconv"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
@@ -42,3 +43,40 @@ func CheckUserEmailExists(repo database.UserRepositoryInterface, email string, c
 	}
 	return existingUser, nil
 }
+
+// CheckVersion compara a versão pedida no If-Match com a versão lida do banco e devolve a versão
+// que deve condicionar a escrita. Com database.AnyVersion (If-Match: *) vale a versão lida, o que
+// ainda protege contra alterações feitas entre a leitura e a escrita.
+func CheckVersion(expected, current int64) (int64, ErrorUtil) {
+	if expected != database.AnyVersion && expected != current {
+		return 0, ErrVersionMismatch
+	}
+	return current, nil
+}
+
+// stringOrEmpty devolve o valor de um campo opcional, ou "" se ele for nil.
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// truncate corta s em no máximo size caracteres (runas), para caber numa coluna varchar(size).
+func truncate(s string, size int) string {
+	runes := []rune(s)
+	if len(runes) <= size {
+		return s
+	}
+	return string(runes[:size])
+}
+
+// truncatedOrNil devolve s cortado em size caracteres, ou nil se s estiver vazio.
+func truncatedOrNil(s string, size int) *string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	s = truncate(s, size)
+	return &s
+}
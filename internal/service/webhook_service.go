@@ -0,0 +1,543 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-sales/internal/database"
+	"go-sales/internal/dto"
+	"go-sales/internal/event"
+	"go-sales/internal/mapper"
+	"go-sales/internal/model"
+	"go-sales/internal/queue"
+	"go-sales/internal/webhook"
+	"go-sales/pkg/util"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const (
+	// WebhookDeliverJobType é o tipo do job da fila que envia uma entrega de webhook.
+	WebhookDeliverJobType = "webhooks.deliver"
+
+	// webhookMaxAttempts é o número de tentativas de uma entrega. Com o backoff da fila (10s dobrando
+	// até 1h), as tentativas se espalham por cerca de 20 minutos antes de a entrega ir para failed.
+	webhookMaxAttempts = 8
+	// webhookTimeout é o tempo máximo de espera pela resposta do receptor.
+	webhookTimeout = 10 * time.Second
+	// webhookResponseBodyLimit é quanto da resposta 2xx do receptor é guardado em cada tentativa. As
+	// demais respostas não têm o corpo guardado: com uma URL mal-intencionada elas poderiam expor o
+	// conteúdo de outro serviço.
+	webhookResponseBodyLimit = 256
+	// webhookUserAgent identifica as requisições de webhook nos logs do receptor.
+	webhookUserAgent = "go-sales-webhooks/1.0"
+)
+
+// WebhookDeliverPayload é o payload do job WebhookDeliverJobType.
+type WebhookDeliverPayload struct {
+	DeliveryID int64 `json:"deliveryId"`
+}
+
+// NewWebhookEventHandler cria o assinante do barramento de eventos que transforma os eventos de
+// domínio em entregas de webhook: uma entrega para cada endpoint habilitado da empresa que assina o
+// tipo do evento. O ID do evento é o do outbox, então um evento despachado de novo não gera entregas
+// duplicadas.
+func NewWebhookEventHandler(db *gorm.DB) event.Handler {
+	return func(ctx context.Context, env event.Envelope) error {
+		if !webhook.EventTypes[env.Type] {
+			return nil
+		}
+		endpoints, err := database.NewWebhookRepository(db).FindSubscribed(env.CompanyGlobalID, env.Type)
+		if err != nil || len(endpoints) == 0 {
+			return err
+		}
+		payload, err := json.Marshal(webhook.Event{
+			ID:              util.SnowflakeID(env.ID),
+			Type:            env.Type,
+			CompanyGlobalID: util.SnowflakeID(env.CompanyGlobalID),
+			CreatedAt:       env.OccurredAt.UTC(),
+			Data:            env.Payload,
+		})
+		if err != nil {
+			return err
+		}
+		for _, endpoint := range endpoints {
+			delivery := &model.WebhookDelivery{
+				EndpointID:      endpoint.ID,
+				CompanyGlobalID: env.CompanyGlobalID,
+				EventID:         env.ID,
+				EventType:       env.Type,
+				Payload:         payload,
+			}
+			if err := enqueueWebhookDelivery(db.WithContext(ctx), delivery); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// newWebhookEvent monta o envelope do evento e o serializa no corpo que será assinado e enviado.
+func newWebhookEvent(companyGlobalID int64, eventType string, data any) (*webhook.Event, []byte, error) {
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	webhookEvent := &webhook.Event{
+		ID:              util.SnowflakeID(util.NewSnowflake()),
+		Type:            eventType,
+		CompanyGlobalID: util.SnowflakeID(companyGlobalID),
+		CreatedAt:       time.Now().UTC(),
+		Data:            rawData,
+	}
+	payload, err := json.Marshal(webhookEvent)
+	if err != nil {
+		return nil, nil, err
+	}
+	return webhookEvent, payload, nil
+}
+
+// enqueueWebhookDelivery grava a entrega como pending e enfileira o job que vai enviá-la, na mesma
+// transação. Se o endpoint já tiver uma entrega (que não seja replay) do mesmo evento, nada é feito.
+func enqueueWebhookDelivery(db *gorm.DB, delivery *model.WebhookDelivery) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		delivery.Status = model.WebhookDeliveryPending
+		created, err := database.NewWebhookRepository(tx).CreateDelivery(delivery)
+		if err != nil || !created {
+			return err
+		}
+		job, err := queue.NewJob(WebhookDeliverJobType, WebhookDeliverPayload{DeliveryID: delivery.ID})
+		if err != nil {
+			return err
+		}
+		job.MaxAttempts = webhookMaxAttempts
+		_, err = database.NewJobRepository(tx).Enqueue(job)
+		return err
+	})
+}
+
+// WebhookServiceInterface define a gestão dos endpoints de webhook e das suas entregas.
+type WebhookServiceInterface interface {
+	Create(webhookDTO dto.CreateWebhookDTO) (*dto.CreatedWebhookDTO, ErrorUtil)
+	Patch(webhookDTO dto.UpdateWebhookDTO, id int64, version int64) (*dto.WebhookDTO, ErrorUtil)
+	Delete(id int64, version int64) ErrorUtil
+	FindByID(id int64, opts dto.ReadOptions) (*dto.WebhookDTO, ErrorUtil)
+	FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.WebhookDTO], ErrorUtil)
+
+	FindDeliveries(id int64, filters map[string][]string, page dto.PageRequest) (*dto.PaginatedResponse[dto.WebhookDeliveryDTO], ErrorUtil)
+	FindDelivery(id int64, deliveryID int64) (*dto.WebhookDeliveryDTO, ErrorUtil)
+	Replay(id int64, deliveryID int64) (*dto.WebhookDeliveryDTO, ErrorUtil)
+	Ping(id int64) (*dto.WebhookDeliveryDTO, ErrorUtil)
+
+	// Deliver é o handler do job WebhookDeliverJobType: faz uma tentativa de envio e a registra.
+	Deliver(ctx context.Context, payload WebhookDeliverPayload) error
+}
+
+// webhookService é a implementação concreta.
+type webhookService struct {
+	db                   *gorm.DB
+	repo                 database.WebhookRepositoryInterface
+	repoCompanyGlobal    database.CompanyGlobalRepositoryInterface
+	client               *http.Client
+	allowPrivateNetworks bool
+}
+
+// NewWebhookService cria uma nova instância do serviço de webhooks. allowPrivateNetworks libera URLs
+// de loopback e de redes privadas, para testar com um receptor local; em produção fica false.
+func NewWebhookService(db *gorm.DB, repo database.WebhookRepositoryInterface, repoCompanyGlobal database.CompanyGlobalRepositoryInterface, allowPrivateNetworks bool) WebhookServiceInterface {
+	return &webhookService{
+		db:                   db,
+		repo:                 repo,
+		repoCompanyGlobal:    repoCompanyGlobal,
+		client:               webhook.NewHTTPClient(webhookTimeout, allowPrivateNetworks),
+		allowPrivateNetworks: allowPrivateNetworks,
+	}
+}
+
+// validateWebhookURL aceita apenas URLs http(s) absolutas e, sem allowPrivateNetworks, recusa hosts
+// internos conhecidos. Nomes que resolvem para IPs internos são barrados na conexão.
+func (s *webhookService) validateWebhookURL(rawURL string) ErrorUtil {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+	if !s.allowPrivateNetworks && webhook.CheckHost(parsed.Hostname()) != nil {
+		return ErrWebhookURLNotAllowed
+	}
+	return nil
+}
+
+// validateWebhookEventTypes confere se todos os eventos pedidos existem no catálogo.
+func validateWebhookEventTypes(eventTypes []string) ErrorUtil {
+	for _, eventType := range eventTypes {
+		if !webhook.EventTypes[eventType] {
+			return NewError("unsupported webhook event type", http.StatusBadRequest, "unsupported_webhook_event", eventType)
+		}
+	}
+	return nil
+}
+
+// Create cadastra o endpoint. O segredo de assinatura é gerado aqui e só aparece nesta resposta.
+func (s *webhookService) Create(webhookDTO dto.CreateWebhookDTO) (*dto.CreatedWebhookDTO, ErrorUtil) {
+	companyExists, errCompanyExists := CheckCompanyGlobalExists(s.repoCompanyGlobal, webhookDTO.CompanyGlobalID.Int64(), false)
+	if errCompanyExists != nil {
+		return nil, errCompanyExists
+	}
+	if !companyExists {
+		return nil, ErrCompanyGlobalNotFound
+	}
+	if errURL := s.validateWebhookURL(webhookDTO.URL); errURL != nil {
+		return nil, errURL
+	}
+	if errEvents := validateWebhookEventTypes(webhookDTO.EventTypes); errEvents != nil {
+		return nil, errEvents
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		log.Error().Err(err).Caller().Msg("failed to generate webhook secret")
+		return nil, ErrInternalServer
+	}
+	endpoint := mapper.MapCreateToWebhook(&webhookDTO, secret)
+	if err := s.repo.Create(endpoint); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Str("url", webhookDTO.URL).
+			Msg("failed to create webhook")
+		return nil, GormDefaultError(err)
+	}
+	log.Info().Int64("webhook_id", endpoint.ID).Str("company_global_id", webhookDTO.CompanyGlobalID.String()).Msg("webhook created")
+	return &dto.CreatedWebhookDTO{WebhookDTO: *mapper.MapToWebhookDTO(endpoint), Secret: secret}, nil
+}
+
+func (s *webhookService) Patch(webhookDTO dto.UpdateWebhookDTO, id int64, version int64) (*dto.WebhookDTO, ErrorUtil) {
+	existing, errFind := s.find(id, dto.FullRead)
+	if errFind != nil {
+		return nil, errFind
+	}
+
+	version, errVersion := CheckVersion(version, existing.Version)
+	if errVersion != nil {
+		return nil, errVersion
+	}
+
+	if webhookDTO.URL != nil {
+		if errURL := s.validateWebhookURL(*webhookDTO.URL); errURL != nil {
+			return nil, errURL
+		}
+	}
+	if errEvents := validateWebhookEventTypes(webhookDTO.EventTypes); errEvents != nil {
+		return nil, errEvents
+	}
+
+	columns := mapper.MapUpdateWebhookToColumns(&webhookDTO)
+	if err := s.repo.Patch(id, version, columns); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("webhook_id", id).
+			Msg("failed to patch webhook")
+		return nil, GormDefaultError(err)
+	}
+	return s.FindByID(id, dto.FullRead)
+}
+
+// Delete apaga o endpoint. As entregas pendentes dele são marcadas como failed ao serem processadas.
+func (s *webhookService) Delete(id int64, version int64) ErrorUtil {
+	if err := s.repo.Delete(id, version); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrWebhookNotFound
+		}
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("webhook_id", id).
+			Msg("failed to delete webhook")
+		return GormDefaultError(err)
+	}
+	return nil
+}
+
+func (s *webhookService) FindByID(id int64, opts dto.ReadOptions) (*dto.WebhookDTO, ErrorUtil) {
+	endpoint, errFind := s.find(id, opts)
+	if errFind != nil {
+		return nil, errFind
+	}
+	return mapper.MapToWebhookDTO(endpoint), nil
+}
+
+func (s *webhookService) find(id int64, opts dto.ReadOptions) (*model.WebhookEndpoint, ErrorUtil) {
+	endpoint, err := s.repo.FindByID(id, opts)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("webhook_id", id).
+			Msg("failed to find webhook")
+		return nil, GormDefaultError(err)
+	}
+	return endpoint, nil
+}
+
+func (s *webhookService) FindAll(filters map[string][]string, page dto.PageRequest, opts dto.ReadOptions, companyGlobalID int64) (*dto.PaginatedResponse[dto.WebhookDTO], ErrorUtil) {
+	companyExists, errCompanyExists := CheckCompanyGlobalExists(s.repoCompanyGlobal, companyGlobalID, false)
+	if errCompanyExists != nil {
+		log.Error().
+			Err(errCompanyExists).
+			Caller().
+			Str("company_global_id", strconv.FormatInt(companyGlobalID, 10)).
+			Msg("failed to check if company global exists")
+		return nil, errCompanyExists
+	}
+	if !companyExists {
+		return nil, ErrCompanyGlobalNotFound
+	}
+
+	endpoints, pageInfo, err := s.repo.FindAll(filters, page, opts, companyGlobalID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Msg("failed to findAll webhooks")
+		return nil, GormDefaultError(err)
+	}
+
+	items := make([]dto.WebhookDTO, len(endpoints))
+	for i := range endpoints {
+		items[i] = *mapper.MapToWebhookDTO(&endpoints[i])
+	}
+	return &dto.PaginatedResponse[dto.WebhookDTO]{
+		Items:    items,
+		PageInfo: pageInfo,
+	}, nil
+}
+
+// FindDeliveries lista as entregas do endpoint, das mais novas para as mais antigas.
+func (s *webhookService) FindDeliveries(id int64, filters map[string][]string, page dto.PageRequest) (*dto.PaginatedResponse[dto.WebhookDeliveryDTO], ErrorUtil) {
+	if _, errFind := s.find(id, dto.FullRead); errFind != nil {
+		return nil, errFind
+	}
+	deliveries, pageInfo, err := s.repo.FindDeliveries(filters, page, id)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("webhook_id", id).
+			Msg("failed to find webhook deliveries")
+		return nil, GormDefaultError(err)
+	}
+
+	items := make([]dto.WebhookDeliveryDTO, len(deliveries))
+	for i := range deliveries {
+		items[i] = *mapper.MapToWebhookDeliveryDTO(&deliveries[i])
+	}
+	return &dto.PaginatedResponse[dto.WebhookDeliveryDTO]{
+		Items:    items,
+		PageInfo: pageInfo,
+	}, nil
+}
+
+// FindDelivery devolve a entrega com o payload e todas as tentativas.
+func (s *webhookService) FindDelivery(id int64, deliveryID int64) (*dto.WebhookDeliveryDTO, ErrorUtil) {
+	delivery, errFind := s.findDelivery(id, deliveryID)
+	if errFind != nil {
+		return nil, errFind
+	}
+	return mapper.MapToWebhookDeliveryDTO(delivery), nil
+}
+
+func (s *webhookService) findDelivery(id int64, deliveryID int64) (*model.WebhookDelivery, ErrorUtil) {
+	delivery, err := s.repo.FindDelivery(id, deliveryID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("webhook_delivery_id", deliveryID).
+			Msg("failed to find webhook delivery")
+		return nil, GormDefaultError(err)
+	}
+	return delivery, nil
+}
+
+// Replay reenvia uma entrega, com qualquer status, como uma nova entrega do mesmo evento (mesmo
+// payload e mesmo ID de evento), que começa com as tentativas zeradas.
+func (s *webhookService) Replay(id int64, deliveryID int64) (*dto.WebhookDeliveryDTO, ErrorUtil) {
+	endpoint, errFind := s.find(id, dto.FullRead)
+	if errFind != nil {
+		return nil, errFind
+	}
+	if !endpoint.Enabled {
+		return nil, ErrWebhookDisabled
+	}
+	original, errDelivery := s.findDelivery(id, deliveryID)
+	if errDelivery != nil {
+		return nil, errDelivery
+	}
+
+	replay := &model.WebhookDelivery{
+		EndpointID:      original.EndpointID,
+		CompanyGlobalID: original.CompanyGlobalID,
+		EventID:         original.EventID,
+		EventType:       original.EventType,
+		Payload:         original.Payload,
+		ReplayOf:        &original.ID,
+	}
+	if err := enqueueWebhookDelivery(s.db, replay); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("webhook_delivery_id", deliveryID).
+			Msg("failed to replay webhook delivery")
+		return nil, GormDefaultError(err)
+	}
+	log.Info().Int64("webhook_id", id).Int64("replay_of", deliveryID).Int64("webhook_delivery_id", replay.ID).Msg("webhook delivery replayed")
+	return mapper.MapToWebhookDeliveryDTO(replay), nil
+}
+
+// Ping enfileira um evento webhook.ping para o endpoint, para testar a URL e a verificação da assinatura.
+func (s *webhookService) Ping(id int64) (*dto.WebhookDeliveryDTO, ErrorUtil) {
+	endpoint, errFind := s.find(id, dto.FullRead)
+	if errFind != nil {
+		return nil, errFind
+	}
+	if !endpoint.Enabled {
+		return nil, ErrWebhookDisabled
+	}
+
+	pingEvent, payload, err := newWebhookEvent(endpoint.CompanyGlobalID, webhook.EventPing, struct {
+		WebhookID util.SnowflakeID `json:"webhookId"`
+	}{WebhookID: util.SnowflakeID(endpoint.ID)})
+	if err != nil {
+		log.Error().Err(err).Caller().Msg("failed to build webhook ping event")
+		return nil, ErrInternalServer
+	}
+	delivery := &model.WebhookDelivery{
+		EndpointID:      endpoint.ID,
+		CompanyGlobalID: endpoint.CompanyGlobalID,
+		EventID:         pingEvent.ID.Int64(),
+		EventType:       webhook.EventPing,
+		Payload:         payload,
+	}
+	if err := enqueueWebhookDelivery(s.db, delivery); err != nil {
+		log.Error().
+			Err(err).
+			Caller().
+			Int64("webhook_id", id).
+			Msg("failed to enqueue webhook ping")
+		return nil, GormDefaultError(err)
+	}
+	return mapper.MapToWebhookDeliveryDTO(delivery), nil
+}
+
+// Deliver envia a entrega assinada e grava a tentativa. Uma resposta 2xx conclui a entrega; qualquer
+// outra resposta (inclusive redirecionamentos) ou erro de rede devolve erro para a fila tentar de novo
+// com backoff. Na última tentativa, ou se a URL resolver para um endereço interno, a entrega vai para
+// failed.
+func (s *webhookService) Deliver(ctx context.Context, payload WebhookDeliverPayload) error {
+	delivery, err := s.repo.FindDeliveryToSend(payload.DeliveryID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return queue.Permanent(fmt.Errorf("webhook delivery %d not found", payload.DeliveryID))
+	}
+	if err != nil {
+		return err
+	}
+	if delivery.Status != model.WebhookDeliveryPending {
+		return nil
+	}
+
+	attempt := &model.WebhookDeliveryAttempt{
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.Attempts + 1,
+	}
+
+	// Endpoint apagado ou desabilitado depois de o evento ser publicado: a entrega é encerrada sem envio.
+	if delivery.Endpoint.ID == 0 || !delivery.Endpoint.Enabled {
+		reason := "webhook endpoint was deleted or disabled"
+		attempt.Error = &reason
+		return s.repo.RecordAttempt(attempt, model.WebhookDeliveryFailed, nil)
+	}
+
+	statusCode, errSend := s.send(ctx, delivery, attempt)
+	now := time.Now()
+	if errSend == nil && statusCode >= 200 && statusCode < 300 {
+		if err := s.repo.RecordAttempt(attempt, model.WebhookDeliverySucceeded, &now); err != nil {
+			return err
+		}
+		log.Debug().Int64("webhook_delivery_id", delivery.ID).Int("status_code", statusCode).Msg("webhook delivered")
+		return nil
+	}
+
+	if errSend == nil {
+		errSend = fmt.Errorf("webhook endpoint responded with status %d", statusCode)
+	}
+	message := errSend.Error()
+	blocked := errors.Is(errSend, webhook.ErrPrivateAddress)
+	if blocked {
+		// Não grava o IP interno para quem consulta a entrega.
+		message = webhook.ErrPrivateAddress.Error()
+	}
+	attempt.Error = &message
+
+	status := model.WebhookDeliveryPending
+	if attempt.Attempt >= webhookMaxAttempts || blocked {
+		status = model.WebhookDeliveryFailed
+	}
+	if err := s.repo.RecordAttempt(attempt, status, nil); err != nil {
+		return err
+	}
+	if status == model.WebhookDeliveryFailed {
+		return queue.Permanent(errSend)
+	}
+	return errSend
+}
+
+// send faz a requisição assinada e devolve o status da resposta. A duração e o status são gravados em
+// attempt; o começo do corpo só nas respostas 2xx.
+func (s *webhookService) send(ctx context.Context, delivery *model.WebhookDelivery, attempt *model.WebhookDeliveryAttempt) (int, error) {
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set(webhook.IDHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(webhook.EventHeader, delivery.EventType)
+	req.Header.Set(webhook.TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(delivery.Endpoint.Secret, timestamp, delivery.Payload))
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	attempt.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	attempt.StatusCode = &resp.StatusCode
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseBodyLimit))
+		// O corpo vai para uma coluna TEXT, que não aceita NUL nem UTF-8 inválido.
+		bodyText := strings.ReplaceAll(strings.ToValidUTF8(string(body), "\uFFFD"), "\x00", "")
+		attempt.ResponseBody = &bodyText
+	}
+	// Lê o resto (com limite) para a conexão poder ser reaproveitada.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	return resp.StatusCode, nil
+}
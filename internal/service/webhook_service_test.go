@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"go-sales/internal/database"
+	"go-sales/internal/model"
+	"go-sales/internal/queue"
+	"go-sales/internal/webhook"
+)
+
+// fakeWebhookRepository guarda uma entrega em memória e aplica RecordAttempt como o repositório real.
+type fakeWebhookRepository struct {
+	database.WebhookRepositoryInterface
+
+	mu       sync.Mutex
+	delivery model.WebhookDelivery
+	attempts []model.WebhookDeliveryAttempt
+}
+
+func (r *fakeWebhookRepository) FindDeliveryToSend(id int64) (*model.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery := r.delivery
+	return &delivery, nil
+}
+
+func (r *fakeWebhookRepository) RecordAttempt(attempt *model.WebhookDeliveryAttempt, status string, deliveredAt *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts = append(r.attempts, *attempt)
+	r.delivery.Attempts = attempt.Attempt
+	r.delivery.Status = status
+	r.delivery.DeliveredAt = deliveredAt
+	return nil
+}
+
+// receivedRequest é o que o receptor de teste viu em uma requisição.
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newWebhookReceiver sobe um receptor que responde statuses em sequência (o último se repete).
+func newWebhookReceiver(t *testing.T, statuses ...int) (*httptest.Server, *[]receivedRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var received []receivedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, receivedRequest{header: r.Header.Clone(), body: body})
+		status := statuses[min(len(received), len(statuses))-1]
+		mu.Unlock()
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("internal details"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func newTestWebhookService(repo *fakeWebhookRepository) *webhookService {
+	return &webhookService{
+		repo:   repo,
+		client: webhook.NewHTTPClient(5*time.Second, true),
+	}
+}
+
+func newTestDelivery(url string) model.WebhookDelivery {
+	return model.WebhookDelivery{
+		ID:         42,
+		EndpointID: 7,
+		Endpoint:   model.WebhookEndpoint{ID: 7, URL: url, Secret: "whsec_test", Enabled: true},
+		EventType:  "user.created",
+		Payload:    []byte(`{"id":"1","type":"user.created"}`),
+		Status:     model.WebhookDeliveryPending,
+	}
+}
+
+func TestDeliverSignsTheRequest(t *testing.T) {
+	server, received := newWebhookReceiver(t, http.StatusNoContent)
+	repo := &fakeWebhookRepository{delivery: newTestDelivery(server.URL)}
+
+	if err := newTestWebhookService(repo).Deliver(context.Background(), WebhookDeliverPayload{DeliveryID: 42}); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	if len(*received) != 1 {
+		t.Fatalf("receiver got %d requests, want 1", len(*received))
+	}
+
+	req := (*received)[0]
+	if err := webhook.Verify("whsec_test", req.header.Get(webhook.TimestampHeader), req.header.Get(webhook.SignatureHeader), req.body, webhook.DefaultTolerance, time.Now()); err != nil {
+		t.Fatalf("Verify of the delivered request: %v", err)
+	}
+	if got := req.header.Get(webhook.IDHeader); got != "42" {
+		t.Errorf("%s = %q, want 42", webhook.IDHeader, got)
+	}
+	if got := req.header.Get(webhook.EventHeader); got != "user.created" {
+		t.Errorf("%s = %q, want user.created", webhook.EventHeader, got)
+	}
+	if repo.delivery.Status != model.WebhookDeliverySucceeded || repo.delivery.DeliveredAt == nil {
+		t.Errorf("delivery status = %s, deliveredAt = %v, want succeeded with a time", repo.delivery.Status, repo.delivery.DeliveredAt)
+	}
+}
+
+func TestDeliverSignatureOutsideTolerance(t *testing.T) {
+	server, received := newWebhookReceiver(t, http.StatusNoContent)
+	repo := &fakeWebhookRepository{delivery: newTestDelivery(server.URL)}
+
+	if err := newTestWebhookService(repo).Deliver(context.Background(), WebhookDeliverPayload{DeliveryID: 42}); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	req := (*received)[0]
+	// O mesmo request reenviado depois da tolerância deve ser recusado pelo receptor.
+	later := time.Now().Add(webhook.DefaultTolerance + time.Minute)
+	err := webhook.Verify("whsec_test", req.header.Get(webhook.TimestampHeader), req.header.Get(webhook.SignatureHeader), req.body, webhook.DefaultTolerance, later)
+	if !errors.Is(err, webhook.ErrTimestampOutOfTolerance) {
+		t.Fatalf("Verify after the tolerance = %v, want ErrTimestampOutOfTolerance", err)
+	}
+}
+
+func TestDeliverRetriesNon2xx(t *testing.T) {
+	server, received := newWebhookReceiver(t, http.StatusInternalServerError, http.StatusFound, http.StatusOK)
+	repo := &fakeWebhookRepository{delivery: newTestDelivery(server.URL)}
+	svc := newTestWebhookService(repo)
+
+	for attempt, status := range []int{http.StatusInternalServerError, http.StatusFound} {
+		err := svc.Deliver(context.Background(), WebhookDeliverPayload{DeliveryID: 42})
+		if err == nil || queue.IsPermanent(err) {
+			t.Fatalf("attempt %d (status %d): Deliver = %v, want a retryable error", attempt+1, status, err)
+		}
+		if repo.delivery.Status != model.WebhookDeliveryPending {
+			t.Fatalf("attempt %d: delivery status = %s, want pending", attempt+1, repo.delivery.Status)
+		}
+		recorded := repo.attempts[attempt]
+		if recorded.StatusCode == nil || *recorded.StatusCode != status {
+			t.Fatalf("attempt %d: recorded status = %v, want %d", attempt+1, recorded.StatusCode, status)
+		}
+		if recorded.ResponseBody != nil {
+			t.Errorf("attempt %d: response body of a %d was stored: %q", attempt+1, status, *recorded.ResponseBody)
+		}
+	}
+
+	if err := svc.Deliver(context.Background(), WebhookDeliverPayload{DeliveryID: 42}); err != nil {
+		t.Fatalf("third attempt: Deliver = %v, want nil", err)
+	}
+	if repo.delivery.Status != model.WebhookDeliverySucceeded || repo.delivery.Attempts != 3 {
+		t.Fatalf("delivery = %s after %d attempts, want succeeded after 3", repo.delivery.Status, repo.delivery.Attempts)
+	}
+	if len(*received) != 3 {
+		t.Fatalf("receiver got %d requests, want 3", len(*received))
+	}
+	// O Webhook-Id se repete nas novas tentativas, para o receptor descartar duplicatas.
+	for _, req := range *received {
+		if got := req.header.Get(webhook.IDHeader); got != strconv.Itoa(42) {
+			t.Fatalf("%s = %q, want 42", webhook.IDHeader, got)
+		}
+	}
+}
+
+func TestDeliverFailsOnLastAttempt(t *testing.T) {
+	server, _ := newWebhookReceiver(t, http.StatusServiceUnavailable)
+	delivery := newTestDelivery(server.URL)
+	delivery.Attempts = webhookMaxAttempts - 1
+	repo := &fakeWebhookRepository{delivery: delivery}
+
+	err := newTestWebhookService(repo).Deliver(context.Background(), WebhookDeliverPayload{DeliveryID: 42})
+	if !queue.IsPermanent(err) {
+		t.Fatalf("Deliver on the last attempt = %v, want a permanent error", err)
+	}
+	if repo.delivery.Status != model.WebhookDeliveryFailed {
+		t.Fatalf("delivery status = %s, want failed", repo.delivery.Status)
+	}
+	if repo.delivery.Attempts != webhookMaxAttempts {
+		t.Fatalf("attempts = %d, want %d", repo.delivery.Attempts, webhookMaxAttempts)
+	}
+}
+
+func TestDeliverRefusesPrivateAddress(t *testing.T) {
+	server, received := newWebhookReceiver(t, http.StatusNoContent)
+	repo := &fakeWebhookRepository{delivery: newTestDelivery(server.URL)}
+	svc := newTestWebhookService(repo)
+	svc.client = webhook.NewHTTPClient(5*time.Second, false)
+
+	err := svc.Deliver(context.Background(), WebhookDeliverPayload{DeliveryID: 42})
+	if !queue.IsPermanent(err) {
+		t.Fatalf("Deliver to %s = %v, want a permanent error", server.URL, err)
+	}
+	if len(*received) != 0 {
+		t.Fatalf("receiver got %d requests, want 0", len(*received))
+	}
+	if repo.delivery.Status != model.WebhookDeliveryFailed {
+		t.Fatalf("delivery status = %s, want failed", repo.delivery.Status)
+	}
+	if got := *repo.attempts[0].Error; got != webhook.ErrPrivateAddress.Error() {
+		t.Fatalf("recorded error = %q, want %q", got, webhook.ErrPrivateAddress.Error())
+	}
+}
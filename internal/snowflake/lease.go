@@ -0,0 +1,160 @@
+// Package snowflake mantém o lease do node ID do Snowflake desta instância em master.snowflake_nodes,
+// garantindo que duas instâncias da API nunca gerem IDs com o mesmo node ID.
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-sales/internal/database"
+	"go-sales/pkg/util"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// acquireAttempts é quantas vezes a disputa por um node ID livre é repetida antes de desistir.
+const acquireAttempts = 5
+
+// ErrNoFreeNode é retornado quando todos os node IDs (ou o node ID pedido) estão com lease ativo.
+var ErrNoFreeNode = errors.New("no free snowflake node ID")
+
+// LeaseOptions configura o lease.
+type LeaseOptions struct {
+	// NodeID, quando informado, é o único node ID aceito (ex: SNOWFLAKE_NODE_ID fixo por instância).
+	NodeID *int64
+	// TTL é a validade do lease sem renovação. A renovação acontece a cada TTL/3. Padrão: 1 minuto.
+	TTL time.Duration
+	// OnLost é chamada quando o lease se perde (outra instância tomou o node ID ou ele venceu sem
+	// renovação). A instância não pode mais gerar IDs; o padrão encerra o processo.
+	OnLost func(err error)
+}
+
+// Lease é o node ID detido por esta instância.
+type Lease struct {
+	repo   database.SnowflakeNodeRepositoryInterface
+	holder string
+	nodeID int64
+	ttl    time.Duration
+	onLost func(err error)
+
+	started bool
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// AcquireLease toma o lease de um node ID livre. Falha com ErrNoFreeNode quando não há node ID livre,
+// o que deve impedir a inicialização da instância.
+func AcquireLease(repo database.SnowflakeNodeRepositoryInterface, opts LeaseOptions) (*Lease, error) {
+	if opts.TTL <= 0 {
+		opts.TTL = time.Minute
+	}
+	if opts.OnLost == nil {
+		opts.OnLost = func(err error) {
+			log.Fatal().Err(err).Msg("Snowflake node lease lost; stopping to avoid duplicate IDs")
+		}
+	}
+
+	hostname, _ := os.Hostname()
+	holder := fmt.Sprintf("%s:%d:%s", hostname, os.Getpid(), uuid.NewString())
+
+	for attempt := 1; attempt <= acquireAttempts; attempt++ {
+		node, err := repo.Acquire(holder, opts.TTL, opts.NodeID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Outra instância pode ter vencido a disputa pelo mesmo node ID; tenta de novo.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		log.Info().Int64("node_id", node.NodeID).Str("holder", holder).Time("leased_until", node.LeasedUntil).Msg("Snowflake node ID leased")
+		return &Lease{
+			repo:   repo,
+			holder: holder,
+			nodeID: node.NodeID,
+			ttl:    opts.TTL,
+			onLost: opts.OnLost,
+			stop:   make(chan struct{}),
+			done:   make(chan struct{}),
+		}, nil
+	}
+	if opts.NodeID != nil {
+		return nil, fmt.Errorf("%w: node ID %d is leased by another instance", ErrNoFreeNode, *opts.NodeID)
+	}
+	return nil, ErrNoFreeNode
+}
+
+// NodeID é o node ID detido por esta instância.
+func (l *Lease) NodeID() int64 {
+	return l.nodeID
+}
+
+// Start inicia a renovação periódica do lease.
+func (l *Lease) Start() {
+	l.started = true
+	go l.heartbeat()
+}
+
+// Release para a renovação e libera o node ID. Deve ser chamada no desligamento, depois que a
+// instância parou de gerar IDs.
+func (l *Lease) Release() error {
+	l.once.Do(func() { close(l.stop) })
+	if l.started {
+		<-l.done
+	}
+	return l.repo.Release(l.nodeID, l.holder)
+}
+
+// heartbeat renova o lease a cada TTL/3. Falhas de conexão são toleradas enquanto o último lease
+// confirmado ainda vale; depois disso, ou se outra instância tomou o node ID, chama OnLost.
+func (l *Lease) heartbeat() {
+	defer close(l.done)
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	// O prazo é contado a partir do momento em que a renovação foi pedida, que é anterior ao NOW() do banco.
+	validUntil := time.Now().Add(l.ttl)
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			requestedAt := time.Now()
+			_, err := l.repo.Renew(l.nodeID, l.holder, l.ttl)
+			switch {
+			case err == nil:
+				validUntil = requestedAt.Add(l.ttl)
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				l.onLost(fmt.Errorf("snowflake node %d was taken by another instance", l.nodeID))
+				return
+			default:
+				log.Error().Err(err).Int64("node_id", l.nodeID).Msg("failed to renew snowflake node lease")
+				if time.Now().After(validUntil) {
+					l.onLost(fmt.Errorf("snowflake node %d lease expired: %w", l.nodeID, err))
+					return
+				}
+			}
+		}
+	}
+}
+
+// ParseNodeID lê um node ID fixo (ex: SNOWFLAKE_NODE_ID); vazio significa qualquer node ID livre.
+func ParseNodeID(value string) (*int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	nodeID, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snowflake node ID %q: %w", value, err)
+	}
+	if nodeID < 0 || nodeID > util.MaxSnowflakeNodeID {
+		return nil, fmt.Errorf("snowflake node ID %d is out of range [0, %d]", nodeID, util.MaxSnowflakeNodeID)
+	}
+	return &nodeID, nil
+}
@@ -0,0 +1,19 @@
+package validator
+
+import (
+	"go-sales/pkg/util"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Valida se o campo é um CGC (CPF/CNPJ) válido.
+func CGCValidator(fl validator.FieldLevel) bool {
+	return util.IsValidCGC(fl.Field().String())
+}
+
+func InitCGCValidator() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterValidation("cgc", CGCValidator)
+	}
+}
@@ -0,0 +1,28 @@
+package validator
+
+import (
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// nullTracker é implementada pelos DTOs de PATCH (ver dto.MergePatch).
+type nullTracker interface {
+	IsNull(field string) bool
+}
+
+// NotNullValidator rejeita null explícito em DTOs de PATCH, para campos que podem ser
+// omitidos mas não limpos. Fora de um DTO de PATCH a tag não tem efeito.
+func NotNullValidator(fl validator.FieldLevel) bool {
+	patch, ok := fl.Parent().Interface().(nullTracker)
+	if !ok {
+		return true
+	}
+	return !patch.IsNull(fl.FieldName())
+}
+
+func InitNotNullValidator() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		// callValidationEvenIfNull: o campo chega como ponteiro nil tanto ausente quanto null.
+		v.RegisterValidation("notnull", NotNullValidator, true)
+	}
+}
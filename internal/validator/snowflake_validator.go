@@ -8,7 +8,8 @@ import (
 	"github.com/go-playground/validator/v10"
 )
 
-// Valida se o campo é um Snowflake válido (int64 > 0)
+// Valida se o campo é um Snowflake válido (int64 > 0). util.SnowflakeID tem int64 como tipo base e
+// cai no mesmo caso.
 func SnowflakeValidator(fl validator.FieldLevel) bool {
 	switch fl.Field().Kind() {
 	case reflect.Int, reflect.Int64:
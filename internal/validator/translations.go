@@ -0,0 +1,19 @@
+package validator
+
+import (
+	"go-sales/internal/i18n"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+)
+
+// InitTranslations registra as mensagens de validação traduzidas (en-US, pt-BR, es),
+// inclusive para as tags customizadas "snowflake", "cgc" e "notnull".
+func InitTranslations() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		if err := i18n.RegisterValidatorTranslations(v); err != nil {
+			log.Error().Err(err).Msg("failed to register validator translations")
+		}
+	}
+}
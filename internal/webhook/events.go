@@ -0,0 +1,37 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"go-sales/internal/event"
+	"go-sales/pkg/util"
+)
+
+const (
+	// EventPing é enviado por POST /webhooks/:id/ping para testar o endpoint. Não pode ser assinado.
+	EventPing = "webhook.ping"
+
+	// AllEvents assina todos os tipos de evento, inclusive os que forem criados depois.
+	AllEvents = "*"
+)
+
+// EventTypes é o catálogo de eventos aceitos na assinatura de um endpoint: todos os eventos de domínio
+// (ver event.Types) e AllEvents.
+var EventTypes = func() map[string]bool {
+	types := map[string]bool{AllEvents: true}
+	for _, eventType := range event.Types {
+		types[eventType] = true
+	}
+	return types
+}()
+
+// Event é o corpo JSON de toda entrega. ID identifica o evento: as entregas dele para endpoints
+// diferentes, e os replays, repetem o mesmo ID. Data é o evento de domínio como foi gravado no outbox.
+type Event struct {
+	ID              util.SnowflakeID `json:"id"`
+	Type            string           `json:"type"`
+	CompanyGlobalID util.SnowflakeID `json:"companyGlobalId"`
+	CreatedAt       time.Time        `json:"createdAt"`
+	Data            json.RawMessage  `json:"data"`
+}
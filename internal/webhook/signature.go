@@ -0,0 +1,78 @@
+// Package webhook define o formato dos webhooks enviados pela API: o catálogo de eventos, o envelope
+// JSON e a assinatura HMAC-SHA256 com timestamp que os receptores usam para validar a origem.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// IDHeader é o ID da entrega. Se repete nas novas tentativas; o receptor pode usá-lo para descartar duplicatas.
+	IDHeader = "Webhook-Id"
+	// EventHeader é o tipo do evento (ex: "user.created").
+	EventHeader = "Webhook-Event"
+	// TimestampHeader é o instante do envio em segundos Unix, também coberto pela assinatura.
+	TimestampHeader = "Webhook-Timestamp"
+	// SignatureHeader traz "v1=" seguido do HMAC-SHA256 em hex de "<timestamp>.<corpo>".
+	SignatureHeader = "Webhook-Signature"
+
+	// SecretPrefix marca os segredos de assinatura dos endpoints.
+	SecretPrefix = "whsec_"
+	// DefaultTolerance é a diferença máxima aceita entre o timestamp e o relógio do receptor.
+	DefaultTolerance = 5 * time.Minute
+
+	signatureVersion = "v1="
+)
+
+var (
+	// ErrInvalidSignature é retornado por Verify quando nenhuma assinatura confere.
+	ErrInvalidSignature = errors.New("webhook signature does not match")
+	// ErrTimestampOutOfTolerance é retornado por Verify quando o timestamp está fora da tolerância,
+	// o que protege contra o reenvio de uma requisição capturada.
+	ErrTimestampOutOfTolerance = errors.New("webhook timestamp is outside the tolerance")
+)
+
+// NewSecret gera o segredo de assinatura de um endpoint.
+func NewSecret() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return SecretPrefix + base64.RawURLEncoding.EncodeToString(random), nil
+}
+
+// Sign devolve o valor do header Webhook-Signature para o corpo enviado em timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signatureVersion + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify confere os headers Webhook-Timestamp e Webhook-Signature de uma entrega recebida.
+// O header de assinatura pode trazer várias assinaturas separadas por vírgula; basta uma conferir.
+func Verify(secret, timestampHeader, signatureHeader string, body []byte, tolerance time.Duration, now time.Time) error {
+	timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if diff := now.Sub(time.Unix(timestamp, 0)); diff > tolerance || diff < -tolerance {
+		return ErrTimestampOutOfTolerance
+	}
+	expected := Sign(secret, timestamp, body)
+	for _, signature := range strings.Split(signatureHeader, ",") {
+		if hmac.Equal([]byte(strings.TrimSpace(signature)), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	secret, err := NewSecret()
+	if err != nil {
+		t.Fatalf("NewSecret: %v", err)
+	}
+	if !strings.HasPrefix(secret, SecretPrefix) {
+		t.Fatalf("secret %q has no %q prefix", secret, SecretPrefix)
+	}
+
+	body := []byte(`{"id":"1","type":"user.created"}`)
+	now := time.Unix(1760000000, 0)
+	signature := Sign(secret, now.Unix(), body)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	if err := Verify(secret, timestamp, signature, body, DefaultTolerance, now); err != nil {
+		t.Fatalf("Verify of a fresh signature: %v", err)
+	}
+	// Durante a rotação do segredo o header pode trazer mais de uma assinatura.
+	if err := Verify(secret, timestamp, "v1=deadbeef, "+signature, body, DefaultTolerance, now); err != nil {
+		t.Fatalf("Verify with several signatures: %v", err)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	const secret = "whsec_test"
+	body := []byte(`{"id":"1"}`)
+	sentAt := time.Unix(1760000000, 0)
+	timestamp := strconv.FormatInt(sentAt.Unix(), 10)
+	signature := Sign(secret, sentAt.Unix(), body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		body      []byte
+		now       time.Time
+		want      error
+	}{
+		{"other secret", "whsec_other", timestamp, signature, body, sentAt, ErrInvalidSignature},
+		{"changed body", secret, timestamp, signature, []byte(`{"id":"2"}`), sentAt, ErrInvalidSignature},
+		{"changed timestamp", secret, strconv.FormatInt(sentAt.Unix()+1, 10), signature, body, sentAt, ErrInvalidSignature},
+		{"invalid timestamp", secret, "yesterday", signature, body, sentAt, ErrInvalidSignature},
+		{"too old", secret, timestamp, signature, body, sentAt.Add(DefaultTolerance + time.Second), ErrTimestampOutOfTolerance},
+		{"from the future", secret, timestamp, signature, body, sentAt.Add(-DefaultTolerance - time.Second), ErrTimestampOutOfTolerance},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.timestamp, tt.signature, tt.body, DefaultTolerance, tt.now)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Verify = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyAcceptsEdgeOfTolerance(t *testing.T) {
+	const secret = "whsec_test"
+	body := []byte(`{}`)
+	sentAt := time.Unix(1760000000, 0)
+	signature := Sign(secret, sentAt.Unix(), body)
+
+	if err := Verify(secret, strconv.FormatInt(sentAt.Unix(), 10), signature, body, DefaultTolerance, sentAt.Add(DefaultTolerance)); err != nil {
+		t.Fatalf("Verify at the tolerance limit: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress é retornado quando a URL do endpoint é, ou resolve para, um endereço de loopback,
+// de rede privada, link-local (como o 169.254.169.254 dos metadados de nuvem) ou reservado.
+var ErrPrivateAddress = errors.New("webhook URL points to a private or reserved address")
+
+// reservedPrefixes são as faixas não roteáveis na internet que netip.Addr não classifica sozinho.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "esta rede"
+	netip.MustParsePrefix("100.64.0.0/10"),   // CGNAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // atribuições do IETF
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentação
+	netip.MustParsePrefix("198.18.0.0/15"),   // testes de desempenho
+	netip.MustParsePrefix("198.51.100.0/24"), // documentação
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentação
+	netip.MustParsePrefix("240.0.0.0/4"),     // reservado, inclui o broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64, que pode apontar para um IPv4 interno
+	netip.MustParsePrefix("2001:db8::/32"),   // documentação
+}
+
+// blockedHosts são nomes que sempre apontam para a própria máquina ou para o serviço de metadados.
+var blockedHosts = map[string]bool{
+	"localhost":                true,
+	"metadata.google.internal": true,
+}
+
+// IsPublicAddr indica se o endereço pode receber webhooks: não é loopback, privado (RFC 1918 e
+// fc00::/7), link-local, multicast, não especificado nem reservado.
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckHost recusa, antes de qualquer requisição, os hosts que já se sabe serem internos: localhost,
+// os nomes de metadados e IPs literais fora da internet. Nomes comuns só são conferidos na conexão,
+// depois da resolução de DNS (ver NewHTTPClient).
+func CheckHost(host string) error {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if blockedHosts[host] || strings.HasSuffix(host, ".localhost") {
+		return ErrPrivateAddress
+	}
+	if addr, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil && !IsPublicAddr(addr) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+// dialControl roda depois da resolução de DNS, com o IP que vai de fato receber a conexão. Assim um
+// nome que resolve para um IP interno (ou que muda de IP entre a validação e o envio) é recusado.
+func dialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !IsPublicAddr(addr) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	return nil
+}
+
+// NewHTTPClient cria o cliente que envia os webhooks. Ele não segue redirecionamentos (a URL cadastrada
+// é a que recebe os eventos), não usa o proxy do ambiente e, a menos que allowPrivateNetworks seja
+// true (desenvolvimento local), só conecta em endereços públicos.
+func NewHTTPClient(timeout time.Duration, allowPrivateNetworks bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}
+	if !allowPrivateNetworks {
+		dialer.Control = dialControl
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   timeout,
+			ExpectContinueTimeout: time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
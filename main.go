@@ -1,12 +1,27 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"go-sales/internal/config"
 	"go-sales/internal/database"
+	"go-sales/internal/event"
 	"go-sales/internal/logger"
+	"go-sales/internal/middleware"
+	"go-sales/internal/outbox"
+	"go-sales/internal/queue"
+	"go-sales/internal/ratelimit"
 	"go-sales/internal/router"
+	"go-sales/internal/service"
+	"go-sales/internal/snowflake"
 	"go-sales/internal/validator" // Importe o novo pacote validator
+	"go-sales/pkg/util"
 	dlog "log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
@@ -34,12 +49,73 @@ func main() {
 	}
 	log.Info().Msg("Database connection established successfully")
 
+	// Node ID do Snowflake por lease no banco: a instância não sobe sem um node ID exclusivo.
+	nodeID, err := snowflake.ParseNodeID(cfg.SnowflakeNodeID)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid SNOWFLAKE_NODE_ID")
+	}
+	nodeLease, err := snowflake.AcquireLease(database.NewSnowflakeNodeRepository(database.DB), snowflake.LeaseOptions{
+		NodeID: nodeID,
+		TTL:    cfg.AppSnowflakeLeaseTTL,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to lease a snowflake node ID")
+	}
+	if err := util.InitSnowflake(nodeLease.NodeID()); err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize snowflake node")
+	}
+	nodeLease.Start()
+
+	// Sem o segredo, qualquer cliente conseguiria forjar um cursor válido.
+	if cfg.AppCursorSecret == "" {
+		log.Fatal().Msg("APP_CURSOR_SECRET is not set; pagination cursors cannot be signed")
+	}
+	database.SetCursorSecret(cfg.AppCursorSecret)
+
+	// O único PSP é o fake local: ele só sobe quando pedido explicitamente, fora de produção e com o
+	// segredo que autentica as notificações do webhook.
+	switch cfg.AppPixProvider {
+	case "":
+		log.Warn().Msg("APP_PIX_PROVIDER is not set; PIX routes are disabled")
+	case "fake":
+		if cfg.AppEnv == "production" {
+			log.Fatal().Msg("APP_PIX_PROVIDER=fake cannot be used in production")
+		}
+		if cfg.AppPixWebhookSecret == "" {
+			log.Fatal().Msg("APP_PIX_WEBHOOK_SECRET is not set; PIX notifications cannot be authenticated")
+		}
+	default:
+		log.Fatal().Str("provider", cfg.AppPixProvider).Msg("Unknown APP_PIX_PROVIDER, use fake or leave it empty")
+	}
+
+	if cfg.AppSnowflakeIDAsNumber {
+		log.Warn().Msg("APP_SNOWFLAKE_ID_AS_NUMBER is enabled; IDs are serialized as JSON numbers and lose precision in JavaScript clients")
+	}
+	util.SetSnowflakeIDAsNumber(cfg.AppSnowflakeIDAsNumber)
+
+	if cfg.AppIdempotencyTTL <= 0 {
+		cfg.AppIdempotencyTTL = 24 * time.Hour
+	}
+	if cfg.AppIdempotencyMaxBody <= 0 {
+		cfg.AppIdempotencyMaxBody = 10 << 20
+	}
+	if cfg.AppShutdownTimeout <= 0 {
+		cfg.AppShutdownTimeout = 30 * time.Second
+	}
+
 	log.Info().Msg("Setting up Gin server...")
 	// Inicializa o validador customizado ANTES de criar a instância do Gin.
 	validator.InitCustomValidator()
 
 	validator.InitSnowflakeValidator()
 
+	validator.InitCGCValidator()
+
+	validator.InitNotNullValidator()
+
+	// Mensagens de validação traduzidas conforme o Accept-Language (en-US, pt-BR, es).
+	validator.InitTranslations()
+
 	// Define o modo do Gin com base na configuração (ex: "release" para produção)
 	gin.SetMode(gin.ReleaseMode)
 	if cfg.AppEnv != "production" {
@@ -52,26 +128,221 @@ func main() {
 	server := gin.New()
 
 	// Adicione seus middlewares customizados na ordem que devem executar.
-	// 1. Middleware de log estruturado (usando zerolog).
+	// 1. ID de correlação, usado nos logs e nas respostas de erro (problem+json).
+	server.Use(middleware.RequestID())
+
+	// 2. Middleware de log estruturado (usando zerolog).
 	server.Use(logger.GinLogger()) // Você precisará criar esta função.
 
-	// 2. Middleware de recuperação de pânico customizado.
+	// 3. Middleware de recuperação de pânico customizado.
 	server.Use(logger.GinRecovery(true)) // Você precisará criar esta função.
 
 	// Agrupar rotas sob um prefixo
 	log.Info().Msgf("Setting up API routes with prefix: %s", cfg.AppAPIPrefix)
 	api := server.Group(cfg.AppAPIPrefix)
 
+	// 4. Autenticação por Authorization: Bearer. Chaves de API (gsk_...) viram o Principal da conta de
+	// serviço; requisições sem o header seguem anônimas.
+	api.Use(middleware.Authenticate(service.NewAPIKeyAuthenticator(database.NewServiceAccountRepository(database.DB))))
+
+	// 5. Rate limit (token bucket) com um bucket por IP, outro por tenant e outro por usuário; as
+	// requisições anônimas usam só o do IP. Roda antes da idempotência para que um 429
+	// não seja gravado como a resposta da Idempotency-Key. Grupos com limites próprios usam
+	// middleware.RateLimit com outro nome e outra política.
+	rateLimitStore := newRateLimitStore(cfg.AppRateLimitStore)
+	api.Use(middleware.RateLimit(rateLimitStore, "api", ratelimit.Limit{
+		Requests: cfg.AppRateLimitRequests,
+		Per:      cfg.AppRateLimitPeriod,
+	}, middleware.RateLimitByIP, middleware.RateLimitByTenant, middleware.RateLimitByUser))
+
+	// 6. Idempotency-Key nos POSTs: retentativas recebem a resposta da primeira requisição.
+	idempotencyRepo := database.NewIdempotencyRepository(database.DB)
+	api.Use(middleware.Idempotency(idempotencyRepo, cfg.AppIdempotencyTTL, cfg.AppIdempotencyMaxBody))
+
+	// Rotas inexistentes (404) e métodos não suportados (405) também respondem com problem+json.
+	router.SetupFallbackRoutes(server)
+
 	// --- REGISTRO DAS ROTAS MODULARES ---
 	// Passe o grupo de rotas e a conexão com o DB para a função de setup.
 	router.SetupUserRoutes(api, database.DB, cfg)
 	router.SetupCompanyGlobalRoutes(api, database.DB, cfg)
 	router.SetupPermissionRoutes(api, database.DB, cfg)
 	router.SetupRoleRoutes(api, database.DB, cfg)
+	router.SetupAddressRoutes(api, database.DB, cfg)
+	router.SetupImportRoutes(api, database.DB, cfg)
+	router.SetupJobRoutes(api, database.DB, cfg)
+	router.SetupServiceAccountRoutes(api, database.DB, cfg)
+	router.SetupWebhookRoutes(api, database.DB, cfg)
+	router.SetupTaxRoutes(api, database.DB, cfg)
+	router.SetupInstallmentRoutes(api, database.DB, cfg)
+	router.SetupPixRoutes(api, database.DB, cfg)
+	router.SetupBankAccountRoutes(api, database.DB, cfg)
+	router.SetupBoletoRoutes(api, database.DB, cfg)
+	router.SetupBankStatementRoutes(api, database.DB, cfg)
+	router.SetupCommissionRoutes(api, database.DB, cfg)
+
+	// Fila de jobs em segundo plano (master.jobs): handlers e agendamentos são registrados antes de iniciar.
+	jobRepo := database.NewJobRepository(database.DB)
+	runner := queue.NewRunner(jobRepo, queue.Options{
+		Workers:      cfg.AppJobWorkers,
+		PollInterval: cfg.AppJobPollInterval,
+	})
+	outboxRepo := database.NewOutboxRepository(database.DB)
+	registerJobs(cfg, runner, jobRepo, idempotencyRepo, database.NewRateLimitRepository(database.DB), outboxRepo)
+
+	// Despacho dos eventos de domínio gravados no outbox para os assinantes do barramento.
+	bus := event.NewBus()
+	bus.Subscribe(event.AllTypes, "webhooks", service.NewWebhookEventHandler(database.DB))
+	commissionService := service.NewCommissionService(database.NewCommissionRepository(database.DB), database.NewCompanyGlobalRepository(database.DB),
+		database.NewUserRepository(database.DB), database.NewRoleRepository(database.DB))
+	service.SubscribeCommissionEvents(bus, commissionService)
+	dispatcher := outbox.NewDispatcher(outboxRepo, bus, outbox.Options{
+		PollInterval: cfg.AppOutboxPollInterval,
+	})
 
 	log.Info().Msgf("Server is starting on port %s...", cfg.AppAPIPort)
 	// Inicia o servidor
-	if err := server.Run(":" + cfg.AppAPIPort); err != nil {
-		log.Fatal().Err(err).Msg("Failed to start server")
+	srv := &http.Server{Addr: ":" + cfg.AppAPIPort, Handler: server}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal().Err(err).Msg("Failed to start server")
+		}
+	}()
+	runner.Start()
+	dispatcher.Start()
+
+	// Desligamento gracioso: para de aceitar requisições, espera as que estão em andamento e drena a fila.
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Info().Msg("Shutting down server...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.AppShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("Server did not shut down cleanly")
+	}
+	if err := dispatcher.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("Outbox dispatcher did not drain before the shutdown timeout")
+	}
+	if err := runner.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("Job runner did not drain before the shutdown timeout")
+	}
+	if err := nodeLease.Release(); err != nil {
+		log.Error().Err(err).Msg("Failed to release snowflake node lease")
+	}
+	log.Info().Msg("Server stopped")
+}
+
+// newRateLimitStore escolhe o store do rate limit conforme APP_RATE_LIMIT_STORE.
+func newRateLimitStore(kind string) ratelimit.Store {
+	switch kind {
+	case "", "memory":
+		return ratelimit.NewMemoryStore()
+	case "postgres":
+		return ratelimit.NewPostgresStore(database.NewRateLimitRepository(database.DB))
+	default:
+		log.Fatal().Str("store", kind).Msg("Unknown APP_RATE_LIMIT_STORE, use memory or postgres")
+		return nil
+	}
+}
+
+const (
+	// purgeIdempotencyKeysJob é o job agendado que apaga as chaves de idempotência com o TTL vencido.
+	purgeIdempotencyKeysJob = "idempotency.purge"
+	// purgeRateLimitBucketsJob é o job agendado que apaga os buckets do rate limit já cheios.
+	purgeRateLimitBucketsJob = "ratelimit.purge"
+	// purgeOutboxEventsJob é o job agendado que apaga os eventos do outbox já despachados.
+	purgeOutboxEventsJob = "outbox.purge"
+	// purgeFinishedJobsJob é o job agendado que apaga os jobs da fila já terminados.
+	purgeFinishedJobsJob = "jobs.purge"
+
+	// outboxRetention é por quanto tempo os eventos despachados ficam no outbox, para consulta.
+	outboxRetention = 7 * 24 * time.Hour
+	// jobRetention é por quanto tempo os jobs completed e dead ficam em master.jobs, para consulta.
+	jobRetention = 7 * 24 * time.Hour
+)
+
+// registerJobs registra os handlers da fila de jobs e os jobs agendados (cron).
+func registerJobs(cfg *config.Config, runner *queue.Runner, jobRepo database.JobRepositoryInterface, idempotencyRepo database.IdempotencyRepositoryInterface, rateLimitRepo database.RateLimitRepositoryInterface, outboxRepo database.OutboxRepositoryInterface) {
+	importJobRepo := database.NewImportJobRepository(database.DB)
+	importService := service.NewImportService(database.DB, importJobRepo)
+	queue.Register(runner, service.ImportJobType, importService.Process)
+
+	webhookService := service.NewWebhookService(database.DB, database.NewWebhookRepository(database.DB), database.NewCompanyGlobalRepository(database.DB), cfg.AppWebhookAllowPrivateNetworks)
+	queue.Register(runner, service.WebhookDeliverJobType, webhookService.Deliver)
+
+	runner.Register(purgeIdempotencyKeysJob, purgeExpiredIdempotencyKeys(idempotencyRepo))
+	if err := runner.Schedule("@hourly", purgeIdempotencyKeysJob, struct{}{}); err != nil {
+		log.Fatal().Err(err).Msg("Failed to schedule idempotency key purge")
+	}
+
+	runner.Register(purgeRateLimitBucketsJob, purgeExpiredRateLimitBuckets(rateLimitRepo))
+	if err := runner.Schedule("@hourly", purgeRateLimitBucketsJob, struct{}{}); err != nil {
+		log.Fatal().Err(err).Msg("Failed to schedule rate limit bucket purge")
+	}
+
+	runner.Register(purgeOutboxEventsJob, purgeProcessedOutboxEvents(outboxRepo))
+	if err := runner.Schedule("@hourly", purgeOutboxEventsJob, struct{}{}); err != nil {
+		log.Fatal().Err(err).Msg("Failed to schedule outbox event purge")
+	}
+
+	runner.Register(purgeFinishedJobsJob, purgeFinishedJobs(jobRepo, importJobRepo))
+	if err := runner.Schedule("@hourly", purgeFinishedJobsJob, struct{}{}); err != nil {
+		log.Fatal().Err(err).Msg("Failed to schedule finished job purge")
+	}
+}
+
+// purgeExpiredIdempotencyKeys apaga as chaves de idempotência com o TTL vencido.
+func purgeExpiredIdempotencyKeys(repo database.IdempotencyRepositoryInterface) queue.Handler {
+	return func(ctx context.Context, _ []byte) error {
+		deleted, err := repo.DeleteExpired(time.Now())
+		if err != nil {
+			return err
+		}
+		log.Debug().Int64("deleted", deleted).Msg("expired idempotency keys purged")
+		return nil
+	}
+}
+
+// purgeExpiredRateLimitBuckets apaga os buckets do store Postgres que já voltaram a ficar cheios.
+func purgeExpiredRateLimitBuckets(repo database.RateLimitRepositoryInterface) queue.Handler {
+	return func(ctx context.Context, _ []byte) error {
+		deleted, err := repo.DeleteExpired(time.Now())
+		if err != nil {
+			return err
+		}
+		log.Debug().Int64("deleted", deleted).Msg("expired rate limit buckets purged")
+		return nil
+	}
+}
+
+// purgeProcessedOutboxEvents apaga os eventos do outbox despachados há mais de outboxRetention.
+func purgeProcessedOutboxEvents(repo database.OutboxRepositoryInterface) queue.Handler {
+	return func(ctx context.Context, _ []byte) error {
+		deleted, err := repo.DeleteProcessed(time.Now().Add(-outboxRetention))
+		if err != nil {
+			return err
+		}
+		log.Debug().Int64("deleted", deleted).Msg("processed outbox events purged")
+		return nil
+	}
+}
+
+// purgeFinishedJobs apaga os jobs terminados há mais de jobRetention e as linhas das importações que
+// não chegaram a terminar nesse prazo.
+func purgeFinishedJobs(jobRepo database.JobRepositoryInterface, importJobRepo database.ImportJobRepositoryInterface) queue.Handler {
+	return func(ctx context.Context, _ []byte) error {
+		before := time.Now().Add(-jobRetention)
+		deleted, err := jobRepo.DeleteFinished(before)
+		if err != nil {
+			return err
+		}
+		cleared, err := importJobRepo.ClearStaleRows(before)
+		if err != nil {
+			return err
+		}
+		log.Debug().Int64("deleted", deleted).Int64("import_rows_cleared", cleared).Msg("finished jobs purged")
+		return nil
 	}
 }
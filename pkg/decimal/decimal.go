@@ -0,0 +1,246 @@
+// Package decimal representa valores monetários, quantidades e alíquotas sem os erros de arredondamento
+// do ponto flutuante. Os valores são imutáveis: as operações devolvem um novo Decimal.
+package decimal
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Decimal é um número decimal exato. O valor zero é 0.
+type Decimal struct {
+	rat *big.Rat
+}
+
+// Zero é o Decimal 0.
+var Zero = Decimal{}
+
+// maxScale é o limite de casas decimais de String para valores que não são decimais finitos.
+const maxScale = 20
+
+var decimalType = reflect.TypeOf(Decimal{})
+
+// New devolve unscaled * 10^-scale (ex: New(1999, 2) é 19.99).
+func New(unscaled int64, scale int) Decimal {
+	r := new(big.Rat).SetInt64(unscaled)
+	if scale > 0 {
+		r.Quo(r, new(big.Rat).SetInt(pow10(scale)))
+	} else if scale < 0 {
+		r.Mul(r, new(big.Rat).SetInt(pow10(-scale)))
+	}
+	return Decimal{rat: r}
+}
+
+// FromInt devolve o inteiro n como Decimal.
+func FromInt(n int64) Decimal {
+	return New(n, 0)
+}
+
+// Parse lê um número em notação decimal ("19.99", "-0.5", "3"). Frações (1/3) e expoentes não são aceitos.
+func Parse(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.ContainsAny(s, "/eE") {
+		return Zero, fmt.Errorf("decimal: invalid number %q", s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Zero, fmt.Errorf("decimal: invalid number %q", s)
+	}
+	return Decimal{rat: r}, nil
+}
+
+// MustParse é Parse para constantes; entra em pânico se s não for um número.
+func MustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func (d Decimal) value() *big.Rat {
+	if d.rat == nil {
+		return new(big.Rat)
+	}
+	return d.rat
+}
+
+func (d Decimal) Add(other Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Add(d.value(), other.value())}
+}
+
+func (d Decimal) Sub(other Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Sub(d.value(), other.value())}
+}
+
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Mul(d.value(), other.value())}
+}
+
+// Div divide d por other. Entra em pânico se other for zero, como a divisão de inteiros.
+func (d Decimal) Div(other Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Quo(d.value(), other.value())}
+}
+
+func (d Decimal) Neg() Decimal {
+	return Decimal{rat: new(big.Rat).Neg(d.value())}
+}
+
+// Percent devolve d * rate / 100 (ex: 1000.Percent(18) é 180).
+func (d Decimal) Percent(rate Decimal) Decimal {
+	return d.Mul(rate).Div(FromInt(100))
+}
+
+// Round arredonda para places casas decimais, com as metades para longe do zero (19.985 vira 19.99).
+func (d Decimal) Round(places int) Decimal {
+	r, _ := new(big.Rat).SetString(d.value().FloatString(places))
+	return Decimal{rat: r}
+}
+
+// Truncate descarta as casas decimais depois de places, sem arredondar.
+func (d Decimal) Truncate(places int) Decimal {
+	scale := new(big.Rat).SetInt(pow10(places))
+	scaled := new(big.Rat).Mul(d.value(), scale)
+	truncated := new(big.Int).Quo(scaled.Num(), scaled.Denom())
+	return Decimal{rat: new(big.Rat).Quo(new(big.Rat).SetInt(truncated), scale)}
+}
+
+// Cmp devolve -1, 0 ou +1 conforme d seja menor, igual ou maior que other.
+func (d Decimal) Cmp(other Decimal) int {
+	return d.value().Cmp(other.value())
+}
+
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Sign devolve -1, 0 ou +1 conforme o sinal de d.
+func (d Decimal) Sign() int {
+	return d.value().Sign()
+}
+
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Scale devolve o número de casas decimais de d (19.90 tem 1), ou -1 se d não for um decimal finito
+// (como 1/3, resultado de Div).
+func (d Decimal) Scale() int {
+	denom := d.value().Denom()
+	for places := 0; places <= maxScale; places++ {
+		if new(big.Int).Mod(pow10(places), denom).Sign() == 0 {
+			return places
+		}
+	}
+	return -1
+}
+
+// Unscaled devolve d * 10^places arredondado, como int64 (ex: 19.99 com 2 casas é 1999). Útil para
+// campos em centavos.
+func (d Decimal) Unscaled(places int) int64 {
+	scaled := d.Round(places).Mul(New(1, -places))
+	return scaled.value().Num().Int64()
+}
+
+// StringFixed formata d com exatamente places casas decimais, arredondando como Round.
+func (d Decimal) StringFixed(places int) string {
+	return d.value().FloatString(places)
+}
+
+// String formata d com as casas decimais necessárias, sem zeros à direita ("19.9", "3").
+func (d Decimal) String() string {
+	places := d.Scale()
+	if places < 0 {
+		places = maxScale
+	}
+	return d.value().FloatString(places)
+}
+
+// MarshalJSON grava d como number JSON com todas as casas decimais, sem passar por float64.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON aceita number e string ("19.99"). null mantém o valor atual.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	raw := data
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		raw = data[1 : len(data)-1]
+	}
+	parsed, err := Parse(string(raw))
+	if err != nil {
+		return &json.UnmarshalTypeError{Value: jsonKind(data), Type: decimalType}
+	}
+	*d = parsed
+	return nil
+}
+
+// jsonKind descreve o valor JSON recebido nas mensagens de erro de tipo (como o encoding/json faz).
+func jsonKind(data []byte) string {
+	switch data[0] {
+	case '"':
+		return "string"
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case 't', 'f':
+		return "bool"
+	default:
+		return "number"
+	}
+}
+
+// Value grava d em colunas NUMERIC.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan lê colunas NUMERIC.
+func (d *Decimal) Scan(src any) error {
+	var raw string
+	switch v := src.(type) {
+	case nil:
+		*d = Zero
+		return nil
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	case int64:
+		*d = FromInt(v)
+		return nil
+	case float64:
+		raw = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("decimal: cannot scan %T", src)
+	}
+	parsed, err := Parse(raw)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Sum soma os valores.
+func Sum(values ...Decimal) Decimal {
+	total := new(big.Rat)
+	for _, v := range values {
+		total.Add(total, v.value())
+	}
+	return Decimal{rat: total}
+}
@@ -0,0 +1,36 @@
+// Portado da QR Code generator library de Project Nayuki.
+// Copyright (c) Project Nayuki. (MIT License, ver o arquivo LICENSE deste diretório)
+// https://www.nayuki.io/page/qr-code-generator-library
+
+package qrcode
+
+// bitBuffer acumula os bits dos dados, do mais significativo para o menos significativo.
+type bitBuffer struct {
+	bits []bool
+}
+
+func newBitBuffer() *bitBuffer {
+	return &bitBuffer{}
+}
+
+func (b *bitBuffer) len() int {
+	return len(b.bits)
+}
+
+// append acrescenta os n bits menos significativos de value.
+func (b *bitBuffer) append(value, n int) {
+	for i := n - 1; i >= 0; i-- {
+		b.bits = append(b.bits, bit(value, i))
+	}
+}
+
+// bytes agrupa os bits em bytes; o tamanho precisa ser múltiplo de 8.
+func (b *bitBuffer) bytes() []byte {
+	result := make([]byte, len(b.bits)/8)
+	for i, set := range b.bits {
+		if set {
+			result[i>>3] |= 1 << (7 - (i & 7))
+		}
+	}
+	return result
+}
@@ -0,0 +1,301 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// brCode é o BR Code estático de exemplo do Manual de Padrões para Iniciação do PIX.
+const brCode = "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D"
+
+// O exemplo "HELLO WORLD" 1-M do tutorial de QR Code do Thonky: 16 codewords de dados e os 10 de correção.
+func TestReedSolomonRemainder(t *testing.T) {
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	if got := reedSolomonRemainder(data, reedSolomonDivisor(len(want))); !bytes.Equal(got, want) {
+		t.Fatalf("ECC = %v, want %v", got, want)
+	}
+}
+
+// formatBitsM é a informação de formato do nível M para cada máscara (tabela C.1 da ISO/IEC 18004).
+var formatBitsM = [8]string{
+	"101010000010010", "101000100100101", "101111001111100", "101101101001011",
+	"100010111111001", "100000011001110", "100111110010111", "100101010100000",
+}
+
+func TestDrawFormat(t *testing.T) {
+	for mask, want := range formatBitsM {
+		c := newCode(1)
+		c.drawFormat(mask)
+		if got := readFormat(c); got != want {
+			t.Errorf("format of mask %d = %s, want %s", mask, got, want)
+		}
+	}
+}
+
+// A informação de versão das versões 7 e 8 (tabela D.1 da norma).
+func TestDrawVersion(t *testing.T) {
+	for version, want := range map[int]string{7: "000111110010010100", 8: "001000010110111100"} {
+		c := newCode(version)
+		c.drawVersion()
+		var got strings.Builder
+		for i := 17; i >= 0; i-- {
+			a, b := c.Size-11+i%3, i/3
+			if c.modules[b][a] != c.modules[a][b] {
+				t.Fatalf("version %d: the two copies of the version information differ", version)
+			}
+			got.WriteString(moduleBit(c.modules[b][a]))
+		}
+		if got.String() != want {
+			t.Errorf("version %d information = %s, want %s", version, got.String(), want)
+		}
+	}
+}
+
+// Capacidade em bytes do nível M (tabela 7 da norma): a maior entrada de cada versão e a primeira que
+// já não cabe nela.
+func TestEncodeChoosesTheSmallestVersion(t *testing.T) {
+	tests := []struct {
+		length  int
+		version int
+	}{
+		{1, 1}, {14, 1}, {15, 2}, {26, 2}, {27, 3}, {122, 7}, {123, 8},
+		{180, 9}, {181, 10}, {213, 10}, {214, 11}, {2331, 40},
+	}
+	for _, tt := range tests {
+		c, err := Encode(bytes.Repeat([]byte("a"), tt.length))
+		if err != nil {
+			t.Fatalf("Encode of %d bytes: %v", tt.length, err)
+		}
+		if c.Version != tt.version {
+			t.Errorf("Encode of %d bytes: version %d, want %d", tt.length, c.Version, tt.version)
+		}
+	}
+	if _, err := Encode(bytes.Repeat([]byte("a"), 2332)); !errors.Is(err, ErrTooLong) {
+		t.Fatalf("Encode of 2332 bytes = %v, want ErrTooLong", err)
+	}
+}
+
+// Cada código gerado é lido de volta: a máscara vem da informação de formato, a correção de cada bloco
+// tem síndromes nulas e os dados decodificados são os de entrada.
+func TestEncodeDecodesBack(t *testing.T) {
+	inputs := []string{"", "PIX", "https://pix.example.com/qr/v2/cob/7d9f0335a8b04f4b9e3e4a1d2c6b8f10", brCode, strings.Repeat("São Paulo ", 60)}
+	for _, input := range inputs {
+		c, err := Encode([]byte(input))
+		if err != nil {
+			t.Fatalf("Encode(%q): %v", input, err)
+		}
+		got, err := decode(c)
+		if err != nil {
+			t.Fatalf("decoding version %d, mask %d: %v", c.Version, c.Mask, err)
+		}
+		if got != input {
+			t.Fatalf("decoded %q, want %q", got, input)
+		}
+	}
+}
+
+// testdata/brcode.txt fixa os módulos do QR Code de brCode (# escuro, . claro), além da versão e da
+// máscara escolhidas, para que mudanças no encoder apareçam aqui.
+func TestEncodeBRCode(t *testing.T) {
+	c, err := Encode([]byte(brCode))
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if c.Version != 8 || c.Mask != 2 {
+		t.Fatalf("version %d, mask %d, want version 8, mask 2", c.Version, c.Mask)
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "brcode.txt"))
+	if err != nil {
+		t.Fatalf("reading testdata: %v", err)
+	}
+	if got := modulesText(c); got != string(want) {
+		t.Fatalf("modules differ from testdata/brcode.txt:\n%s", got)
+	}
+}
+
+func modulesText(c *Code) string {
+	var text strings.Builder
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.Dark(x, y) {
+				text.WriteByte('#')
+			} else {
+				text.WriteByte('.')
+			}
+		}
+		text.WriteByte('\n')
+	}
+	return text.String()
+}
+
+func moduleBit(dark bool) string {
+	if dark {
+		return "1"
+	}
+	return "0"
+}
+
+// readFormat lê a primeira cópia da informação de formato, do bit mais significativo para o menos.
+func readFormat(c *Code) string {
+	positions := [15][2]int{}
+	for i := 0; i <= 5; i++ {
+		positions[i] = [2]int{8, i}
+	}
+	positions[6], positions[7], positions[8] = [2]int{8, 7}, [2]int{8, 8}, [2]int{7, 8}
+	for i := 9; i < 15; i++ {
+		positions[i] = [2]int{14 - i, 8}
+	}
+	var bits strings.Builder
+	for i := 14; i >= 0; i-- {
+		bits.WriteString(moduleBit(c.modules[positions[i][1]][positions[i][0]]))
+	}
+	return bits.String()
+}
+
+// unmask são as condições das máscaras como a norma as escreve, com i a linha e j a coluna.
+var unmask = [8]func(i, j int) bool{
+	func(i, j int) bool { return (i+j)%2 == 0 },
+	func(i, j int) bool { return i%2 == 0 },
+	func(i, j int) bool { return j%3 == 0 },
+	func(i, j int) bool { return (i+j)%3 == 0 },
+	func(i, j int) bool { return (i/2+j/3)%2 == 0 },
+	func(i, j int) bool { return i*j%2+i*j%3 == 0 },
+	func(i, j int) bool { return (i*j%2+i*j%3)%2 == 0 },
+	func(i, j int) bool { return ((i+j)%2+i*j%3)%2 == 0 },
+}
+
+// decode lê o QR Code como um leitor faria, a partir dos módulos e das tabelas de blocos da norma.
+func decode(c *Code) (string, error) {
+	format := readFormat(c)
+	mask := -1
+	for m, bits := range formatBitsM {
+		if bits == format {
+			mask = m
+		}
+	}
+	if mask < 0 || mask != c.Mask {
+		return "", errors.New("format information " + format + " does not match the chosen mask " + strconv.Itoa(c.Mask))
+	}
+
+	// Leitura em zigue-zague dos módulos de dados, já sem a máscara.
+	var codewords []byte
+	var current, count int
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if (right+1)&2 == 0 {
+				y = c.Size - 1 - vert
+			}
+			for x := right; x > right-2; x-- {
+				if c.isFunction[y][x] {
+					continue
+				}
+				current <<= 1
+				if c.modules[y][x] != unmask[mask](y, x) {
+					current |= 1
+				}
+				if count++; count%8 == 0 {
+					codewords = append(codewords, byte(current))
+					current = 0
+				}
+			}
+		}
+	}
+
+	// Separa os blocos intercalados e confere a correção de cada um.
+	numBlocks, eccLen := eccBlocks[c.Version], eccCodewordsPerBlock[c.Version]
+	total := rawDataModules(c.Version) / 8
+	codewords = codewords[:total]
+	numShort := numBlocks - total%numBlocks
+	shortData := total/numBlocks - eccLen
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i <= shortData; i++ {
+		for b := range blocks {
+			if i < shortData || b >= numShort {
+				blocks[b] = append(blocks[b], codewords[k])
+				k++
+			}
+		}
+	}
+	var data []byte
+	for b := range blocks {
+		data = append(data, blocks[b]...)
+	}
+	for i := 0; i < eccLen; i++ {
+		for b := range blocks {
+			blocks[b] = append(blocks[b], codewords[k])
+			k++
+		}
+	}
+	for b, block := range blocks {
+		if !zeroSyndromes(block, eccLen) {
+			return "", errors.New("block " + strconv.Itoa(b) + " has nonzero syndromes")
+		}
+	}
+
+	// Modo byte: 0100, o contador de caracteres e os bytes.
+	reader := bitReader{data: data}
+	if reader.read(4) != 0b0100 {
+		return "", errors.New("data is not in byte mode")
+	}
+	length := reader.read(countBits(c.Version))
+	text := make([]byte, length)
+	for i := range text {
+		text[i] = byte(reader.read(8))
+	}
+	return string(text), nil
+}
+
+// zeroSyndromes avalia o bloco (dados e correção) nas raízes 2^0 ... 2^(eccLen-1) do gerador, com
+// logaritmos em GF(256) calculados aqui.
+func zeroSyndromes(block []byte, eccLen int) bool {
+	var exp [255]int
+	log := map[int]int{}
+	for i, x := 0, 1; i < 255; i++ {
+		exp[i], log[x] = x, i
+		if x <<= 1; x&0x100 != 0 {
+			x ^= 0x11D
+		}
+	}
+	multiply := func(a, b int) int {
+		if a == 0 || b == 0 {
+			return 0
+		}
+		return exp[(log[a]+log[b])%255]
+	}
+	for i := 0; i < eccLen; i++ {
+		syndrome := 0
+		for _, coefficient := range block {
+			syndrome = multiply(syndrome, exp[i]) ^ int(coefficient)
+		}
+		if syndrome != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *bitReader) read(n int) int {
+	value := 0
+	for i := 0; i < n; i++ {
+		value = value<<1 | int(r.data[r.pos>>3]>>(7-r.pos&7)&1)
+		r.pos++
+	}
+	return value
+}
@@ -0,0 +1,47 @@
+// Portado da QR Code generator library de Project Nayuki.
+// Copyright (c) Project Nayuki. (MIT License, ver o arquivo LICENSE deste diretório)
+// https://www.nayuki.io/page/qr-code-generator-library
+
+package qrcode
+
+// reedSolomonDivisor devolve o polinômio gerador de grau degree sobre GF(256) (polinômio 0x11D), sem
+// o coeficiente do termo de maior grau, que é sempre 1.
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// reedSolomonRemainder devolve os codewords de correção de data: o resto da divisão pelo gerador.
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coefficient := range divisor {
+			result[i] ^= gfMultiply(coefficient, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplica em GF(256) com o polinômio redutor x^8 + x^4 + x^3 + x^2 + 1.
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
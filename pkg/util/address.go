@@ -0,0 +1,52 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ufs são as siglas das unidades federativas do Brasil.
+var ufs = map[string]bool{
+	"AC": true, "AL": true, "AP": true, "AM": true, "BA": true, "CE": true, "DF": true,
+	"ES": true, "GO": true, "MA": true, "MT": true, "MS": true, "MG": true, "PA": true,
+	"PB": true, "PR": true, "PE": true, "PI": true, "RJ": true, "RN": true, "RS": true,
+	"RO": true, "RR": true, "SC": true, "SP": true, "SE": true, "TO": true,
+}
+
+// IsValidUF verifica se uf é a sigla de uma unidade federativa (ex: "SP").
+func IsValidUF(uf string) bool {
+	return ufs[uf]
+}
+
+// IsBrazil verifica se o país informado é o Brasil ("BR", "BRA", "Brasil" ou "Brazil").
+func IsBrazil(country string) bool {
+	switch strings.ToUpper(strings.TrimSpace(country)) {
+	case "BR", "BRA", "BRASIL", "BRAZIL":
+		return true
+	}
+	return false
+}
+
+// OnlyDigits remove tudo o que não for dígito (ex: "01310-100" vira "01310100").
+func OnlyDigits(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+}
+
+// FoldText prepara um texto para comparação: sem acentos, em minúsculas e com espaços simples.
+// "São  Paulo" e "SAO PAULO" resultam no mesmo valor.
+func FoldText(value string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), value)
+	if err != nil {
+		folded = value
+	}
+	return strings.ToLower(strings.Join(strings.Fields(folded), " "))
+}
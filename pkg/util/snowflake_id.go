@@ -0,0 +1,96 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+)
+
+// SnowflakeID é o tipo dos IDs Snowflake nos DTOs. Os IDs passam de 2^53 e perdem precisão quando
+// lidos como number em JavaScript, por isso são serializados como string JSON ("1963354596233486336").
+// Na entrada, aceita tanto string quanto number.
+type SnowflakeID int64
+
+// snowflakeIDAsNumber é a chave de compatibilidade da migração: quando ligada, os IDs voltam a ser
+// serializados como number, para clientes que ainda não leem string.
+var snowflakeIDAsNumber atomic.Bool
+
+// SetSnowflakeIDAsNumber liga ou desliga a serialização dos IDs como number. Deve ser chamada na
+// inicialização, antes de atender requisições.
+func SetSnowflakeIDAsNumber(asNumber bool) {
+	snowflakeIDAsNumber.Store(asNumber)
+}
+
+func (id SnowflakeID) Int64() int64 {
+	return int64(id)
+}
+
+func (id SnowflakeID) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+func (id SnowflakeID) MarshalJSON() ([]byte, error) {
+	if snowflakeIDAsNumber.Load() {
+		return []byte(id.String()), nil
+	}
+	return []byte(`"` + id.String() + `"`), nil
+}
+
+// UnmarshalJSON aceita "123", 123 e null (que mantém o valor atual).
+func (id *SnowflakeID) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	raw := data
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		raw = data[1 : len(data)-1]
+	}
+	value, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return &json.UnmarshalTypeError{Value: jsonKind(data), Type: reflect.TypeOf(*id)}
+	}
+	*id = SnowflakeID(value)
+	return nil
+}
+
+// jsonKind descreve o valor JSON recebido nas mensagens de erro de tipo (como o encoding/json faz).
+func jsonKind(data []byte) string {
+	switch data[0] {
+	case '"':
+		return "string"
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case 't', 'f':
+		return "bool"
+	default:
+		return "number"
+	}
+}
+
+// ToSnowflakeIDs converte uma lista de IDs do model para o tipo dos DTOs.
+func ToSnowflakeIDs(ids []int64) []SnowflakeID {
+	if ids == nil {
+		return nil
+	}
+	out := make([]SnowflakeID, len(ids))
+	for i, id := range ids {
+		out[i] = SnowflakeID(id)
+	}
+	return out
+}
+
+// FromSnowflakeIDs converte uma lista de IDs dos DTOs para int64.
+func FromSnowflakeIDs(ids []SnowflakeID) []int64 {
+	if ids == nil {
+		return nil
+	}
+	out := make([]int64, len(ids))
+	for i, id := range ids {
+		out[i] = int64(id)
+	}
+	return out
+}